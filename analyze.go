@@ -4,27 +4,181 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/zoobzio/pipz"
 )
 
 // AnalyzeInput contains rich input structure for analysis.
 type AnalyzeInput[T any] struct {
-	Data        T       // The structured data to analyze
-	Context     string  // Optional context for analysis
-	Focus       string  // Optional specific aspect to focus on
-	Temperature float32 // Temperature for analysis
+	Data        T        // The structured data to analyze
+	Context     string   // Optional context for analysis
+	Focus       string   // Optional specific aspect to focus on
+	MinSeverity string   // Optional least severe finding to return; findings without a severity are kept
+	Constraints []string // Optional rules the analysis must follow, added after the built-in ones
+	Scored      bool     // Ask for a 0 to 100 score and its basis alongside the analysis; not used by slice and delta analysis
+	Temperature float32  // Temperature for analysis
+}
+
+// DefaultAnalyzeMaxRecords is the most records a slice analysis accepts in
+// one call when AnalyzeSliceInput.MaxRecords is unset.
+const DefaultAnalyzeMaxRecords = 100
+
+// AnalyzeSliceInput contains a slice of records analyzed together, so trends
+// across records are visible to the model.
+type AnalyzeSliceInput[T any] struct {
+	Data        []T      // The records to analyze, identified by their index
+	Context     string   // Optional context for analysis
+	Focus       string   // Optional specific aspect to focus on
+	MinSeverity string   // Optional least severe finding to return; findings without a severity are kept
+	Constraints []string // Optional rules the analysis must follow, added after the built-in ones
+	MaxRecords  int      // Most records accepted in one call; 0 uses DefaultAnalyzeMaxRecords
+	Temperature float32  // Temperature for analysis
+}
+
+// DeltaInput contains two snapshots of the same type for delta analysis.
+type DeltaInput[T any] struct {
+	Before      T        // The earlier snapshot
+	After       T        // The later snapshot
+	Context     string   // Optional context for analysis
+	Focus       string   // Optional specific aspect to focus on
+	MinSeverity string   // Optional least severe finding to return; findings without a severity are kept
+	Constraints []string // Optional rules the analysis must follow, added after the built-in ones
+	Temperature float32  // Temperature for analysis
+}
+
+// Field change kinds reported in a delta diff.
+const (
+	fieldAdded   = "added"
+	fieldRemoved = "removed"
+	fieldChanged = "changed"
+)
+
+// fieldChange is a single difference between two JSON values.
+type fieldChange struct {
+	Path   string `json:"path"`             // Dotted path to the field, with [i] for array elements
+	Change string `json:"change"`           // added, removed, or changed
+	Before any    `json:"before,omitempty"` // Value before, absent when added
+	After  any    `json:"after,omitempty"`  // Value after, absent when removed
+}
+
+// Finding severities, from least to most severe. Providers that report on
+// a three-level scale are mapped onto these: "warning" becomes medium.
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Finding is a single issue or observation from an analysis.
+type Finding struct {
+	Title          string `json:"title"`          // Short name of the finding
+	Severity       string `json:"severity"`       // info, low, medium, high, or critical
+	Area           string `json:"area"`           // Part of the input the finding concerns
+	Field          string `json:"field"`          // Path of the input field the finding concerns, if any
+	Description    string `json:"description"`    // What was found, in more detail than the title
+	Recommendation string `json:"recommendation"` // Suggested action, if any
+}
+
+// UnmarshalJSON accepts a finding object, or a plain string as returned by
+// providers unaware of the structured form, which becomes the title.
+func (f *Finding) UnmarshalJSON(data []byte) error {
+	var title string
+	if err := json.Unmarshal(data, &title); err == nil {
+		*f = Finding{Title: title}
+		return nil
+	}
+	type plain Finding
+	return json.Unmarshal(data, (*plain)(f))
+}
+
+// Validate checks if the finding is valid.
+// An empty severity is allowed for findings given as plain strings.
+func (f Finding) Validate() error {
+	if strings.TrimSpace(f.Title) == "" {
+		return fmt.Errorf("title required but empty")
+	}
+	if f.Severity != "" && normalizeSeverity(f.Severity) == "" {
+		return fmt.Errorf("unknown severity %q", f.Severity)
+	}
+	return nil
+}
+
+// String renders the finding as a single line, located by its field or
+// else its area, e.g. "[high] auth.password_hash: plaintext passwords -
+// passwords are stored unhashed (recommendation: hash with bcrypt)".
+func (f Finding) String() string {
+	var b strings.Builder
+	if f.Severity != "" {
+		fmt.Fprintf(&b, "[%s] ", f.Severity)
+	}
+	if f.Field != "" {
+		fmt.Fprintf(&b, "%s: ", f.Field)
+	} else if f.Area != "" {
+		fmt.Fprintf(&b, "%s: ", f.Area)
+	}
+	b.WriteString(f.Title)
+	if f.Description != "" {
+		fmt.Fprintf(&b, " - %s", f.Description)
+	}
+	if f.Recommendation != "" {
+		fmt.Fprintf(&b, " (recommendation: %s)", f.Recommendation)
+	}
+	return b.String()
+}
+
+// FlattenFindings renders findings as strings for display code written for
+// plain-text findings.
+func FlattenFindings(findings []Finding) []string {
+	flattened := make([]string, len(findings))
+	for i, finding := range findings {
+		flattened[i] = finding.String()
+	}
+	return flattened
+}
+
+// normalizeSeverity maps a severity and its common variants to a standard
+// value. Returns an empty string for unknown severities.
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case SeverityInfo, "informational", "information", "note":
+		return SeverityInfo
+	case SeverityLow, "minor":
+		return SeverityLow
+	case SeverityMedium, "med", "moderate", "warning", "warn":
+		return SeverityMedium
+	case SeverityHigh, "major":
+		return SeverityHigh
+	case SeverityCritical, "crit", "severe", "blocker":
+		return SeverityCritical
+	default:
+		return ""
+	}
+}
+
+// severityRank orders standard severities from 0 for info to 4 for
+// critical. Returns -1 for an empty or unknown severity.
+func severityRank(severity string) int {
+	return slices.Index([]string{SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}, normalizeSeverity(severity))
 }
 
 // AnalyzeResponse contains the analysis with metadata.
 type AnalyzeResponse struct {
-	Analysis   string   `json:"analysis"`   // The main analysis text
-	Confidence float64  `json:"confidence"` // Confidence in analysis
-	Findings   []string `json:"findings"`   // Key findings or issues
-	Reasoning  []string `json:"reasoning"`  // Explanation of analysis approach
+	Analysis   string    `json:"analysis"`              // The main analysis text
+	Confidence float64   `json:"confidence"`            // Confidence in analysis
+	Findings   []Finding `json:"findings"`              // Key findings or issues
+	Score      float64   `json:"score,omitempty"`       // 0 to 100 rating, higher is better, requested with AnalyzeInput.Scored
+	ScoreBasis string    `json:"score_basis,omitempty"` // What the score measures and what the ends of the scale mean
+	Reasoning  []string  `json:"reasoning"`             // Explanation of analysis approach
 }
 
-// Validate checks if the response is valid.
+// Validate checks if the response is valid. A score without its basis is
+// rejected; a requested score with neither is rejected by the synapse.
 func (r AnalyzeResponse) Validate() error {
 	if r.Analysis == "" {
 		return fmt.Errorf("analysis required but empty")
@@ -32,22 +186,118 @@ func (r AnalyzeResponse) Validate() error {
 	if r.Confidence < 0 || r.Confidence > 1 {
 		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
 	}
+	if r.Score < 0 || r.Score > analyzeMaxScore {
+		return fmt.Errorf("score must be 0-%d, got %f", analyzeMaxScore, r.Score)
+	}
+	if r.Score != 0 && strings.TrimSpace(r.ScoreBasis) == "" {
+		return fmt.Errorf("score_basis required with a score but empty")
+	}
+	for i, finding := range r.Findings {
+		if err := finding.Validate(); err != nil {
+			return fmt.Errorf("finding %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// analyzeMaxScore is the top of the analysis score scale.
+const analyzeMaxScore = 100
+
+// Schema properties of the analysis score.
+const (
+	analyzeScoreProperty      = "score"
+	analyzeScoreBasisProperty = "score_basis"
+)
+
+// analyzeScoreConstraint is added to analysis prompts that request a score.
+const analyzeScoreConstraint = "score: overall rating of the input from 0 to 100, higher is better, consistent with the findings"
+
+// analyzeScoreBasisConstraint follows analyzeScoreConstraint.
+const analyzeScoreBasisConstraint = "score_basis: what the score measures and what 0 and 100 mean"
+
+// validateAnalyze requires the basis of a score the input asked for, so a
+// response that left out the score is not read as a score of 0.
+func validateAnalyze(scored bool, response AnalyzeResponse) error {
+	if !scored {
+		return nil
+	}
+	if strings.TrimSpace(response.ScoreBasis) == "" {
+		return fmt.Errorf("score_basis required but empty; score was requested")
+	}
+	return nil
+}
+
+// RecordFinding is a note about a single record of a slice analysis.
+type RecordFinding struct {
+	Index int    `json:"index"` // Index of the record in the input slice
+	Note  string `json:"note"`  // What stands out about the record
+}
+
+// AnalyzeSliceResponse contains the analysis of a slice of records, with
+// notes on individual records alongside the overall analysis.
+type AnalyzeSliceResponse struct {
+	Analysis   string          `json:"analysis"`   // The overall analysis text
+	Confidence float64         `json:"confidence"` // Confidence in analysis
+	Findings   []Finding       `json:"findings"`   // Key findings or issues across records
+	PerRecord  []RecordFinding `json:"per_record"` // Notes on records that stand out
+	Reasoning  []string        `json:"reasoning"`  // Explanation of analysis approach
+}
+
+// Validate checks if the response is valid. Record indices are checked
+// against the input by the synapse.
+func (r AnalyzeSliceResponse) Validate() error {
+	overall := AnalyzeResponse{Analysis: r.Analysis, Confidence: r.Confidence, Findings: r.Findings}
+	if err := overall.Validate(); err != nil {
+		return err
+	}
+	for i, record := range r.PerRecord {
+		if strings.TrimSpace(record.Note) == "" {
+			return fmt.Errorf("per-record finding %d: note required but empty", i)
+		}
+	}
+	return nil
+}
+
+// checkRecordIndices checks that every per-record finding names one of
+// count records.
+func checkRecordIndices(response AnalyzeSliceResponse, count int) error {
+	for _, record := range response.PerRecord {
+		if record.Index < 0 || record.Index >= count {
+			return fmt.Errorf("record index must be from 0 to %d, got %d", count-1, record.Index)
+		}
+	}
 	return nil
 }
 
 // AnalyzeSynapse analyzes structured data and produces text analysis.
 type AnalyzeSynapse[T any] struct {
-	what     string // What kind of analysis to perform
-	schema   string // Pre-computed JSON schema
-	defaults AnalyzeInput[T]
-	service  *Service[AnalyzeResponse]
+	what         string // What kind of analysis to perform
+	schema       string // Pre-computed JSON schema
+	scoredSchema string // Pre-computed JSON schema including the score
+	sliceSchema  string // Pre-computed JSON schema for AnalyzeSliceResponse
+	defaults     AnalyzeInput[T]
+	diffOnly     bool // Send only a field-level diff in delta analysis
+	service      *Service[AnalyzeResponse]
+	slice        *Service[AnalyzeSliceResponse] // Service for FireWithSlice, sharing the pipeline
 }
 
 // Analyze creates a new analysis synapse for structured input.
 // Returns an error if the JSON schema cannot be generated.
 func Analyze[T any](what string, provider Provider, opts ...Option) (*AnalyzeSynapse[T], error) {
-	// Generate schema once at construction
-	schema, err := generateJSONSchema[AnalyzeResponse]()
+	// Generate schema once at construction; the score is only part of the
+	// schema when requested with AnalyzeInput.Scored
+	scoredSchema, err := generateJSONSchema[AnalyzeResponse]()
+	if err != nil {
+		return nil, fmt.Errorf("analyze synapse: %w", err)
+	}
+	schema, err := omitProperty(scoredSchema, analyzeScoreProperty)
+	if err == nil {
+		schema, err = omitProperty(schema, analyzeScoreBasisProperty)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("analyze synapse: %w", err)
+	}
+	sliceSchema, err := generateJSONSchema[AnalyzeSliceResponse]()
 	if err != nil {
 		return nil, fmt.Errorf("analyze synapse: %w", err)
 	}
@@ -62,23 +312,55 @@ func Analyze[T any](what string, provider Provider, opts ...Option) (*AnalyzeSyn
 	svc := NewService[AnalyzeResponse](pipeline, "analyze", provider, DefaultTemperatureAnalytical)
 
 	return &AnalyzeSynapse[T]{
-		what:    what,
-		schema:  schema,
-		service: svc,
+		what:         what,
+		schema:       schema,
+		scoredSchema: scoredSchema,
+		sliceSchema:  sliceSchema,
+		service:      svc,
+		slice:        NewService[AnalyzeSliceResponse](pipeline, "analyze", provider, DefaultTemperatureAnalytical),
 	}, nil
 }
 
+// DiffOnly controls what delta analysis sends to the LLM. By default both
+// snapshots are sent in full; with diffOnly set, only a field-level diff of
+// their JSON forms is sent, which keeps prompts small for large structs.
+func (a *AnalyzeSynapse[T]) DiffOnly(diffOnly bool) *AnalyzeSynapse[T] {
+	a.diffOnly = diffOnly
+	return a
+}
+
 // GetPipeline returns the underlying pipeline.
 func (a *AnalyzeSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
 	return a.service.GetPipeline()
 }
 
+// WithDefaults creates a new Analyze with default input values.
+// These are merged with user input at execution time, including by the
+// slice and delta methods. Data is always taken from the input.
+func (a *AnalyzeSynapse[T]) WithDefaults(defaults AnalyzeInput[T]) *AnalyzeSynapse[T] {
+	a.defaults = defaults
+	return a
+}
+
 // Fire performs the analysis with structured input.
 func (a *AnalyzeSynapse[T]) Fire(ctx context.Context, session *Session, data T) (string, error) {
 	input := AnalyzeInput[T]{Data: data}
 	return a.FireWithInput(ctx, session, input)
 }
 
+// FireResult performs the analysis and returns it in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (a *AnalyzeSynapse[T]) FireResult(ctx context.Context, session *Session, data T) (Result[string], error) {
+	merged := a.mergeInputs(AnalyzeInput[T]{Data: data})
+	result, err := a.service.executeChecked(ctx, session, a.buildPrompt(merged), merged.Temperature, func(response AnalyzeResponse) error {
+		return validateAnalyze(merged.Scored, response)
+	})
+	if err != nil {
+		return withValue(result, ""), fmt.Errorf("analysis failed: %w", err)
+	}
+	return withValue(result, result.Value.Analysis), nil
+}
+
 // FireWithDetails performs the analysis and returns detailed response.
 func (a *AnalyzeSynapse[T]) FireWithDetails(ctx context.Context, session *Session, data T) (*AnalyzeResponse, error) {
 	input := AnalyzeInput[T]{Data: data}
@@ -98,20 +380,150 @@ func (a *AnalyzeSynapse[T]) FireWithInput(ctx context.Context, session *Session,
 func (a *AnalyzeSynapse[T]) FireWithInputDetails(ctx context.Context, session *Session, input AnalyzeInput[T]) (*AnalyzeResponse, error) {
 	// Merge defaults with user input
 	merged := a.mergeInputs(input)
+	if merged.MinSeverity != "" && severityRank(merged.MinSeverity) < 0 {
+		return nil, fmt.Errorf("analysis failed: %w: unknown minimum severity %q", ErrInvalidPrompt, merged.MinSeverity)
+	}
 
 	// Build prompt
 	prompt := a.buildPrompt(merged)
 
 	// Execute through service with session (service handles temperature fallback)
-	response, err := a.service.Execute(ctx, session, prompt, merged.Temperature)
+	result, err := a.service.executeChecked(ctx, session, prompt, merged.Temperature, func(response AnalyzeResponse) error {
+		return validateAnalyze(merged.Scored, response)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("analysis failed: %w", err)
 	}
+	response := result.Value
+
+	response.Findings = filterFindings(normalizeFindings(response.Findings), merged.MinSeverity)
+	return &response, nil
+}
+
+// FireScore performs the analysis asking for a score, whether or not
+// input.Scored is set, and returns the score from 0 to 100. Use
+// FireWithInputDetails with Scored set to read the score's basis and the
+// analysis as well.
+//
+// Example:
+//
+//	score, err := analyzer.FireScore(ctx, session, zyn.AnalyzeInput[Config]{
+//	    Data:  config,
+//	    Focus: "production readiness",
+//	})
+func (a *AnalyzeSynapse[T]) FireScore(ctx context.Context, session *Session, input AnalyzeInput[T]) (float64, error) {
+	input.Scored = true
+	response, err := a.FireWithInputDetails(ctx, session, input)
+	if err != nil {
+		return 0, err
+	}
+	return response.Score, nil
+}
+
+// FireWithSlice analyzes records together in a single call, so the analysis
+// can cover trends across them, and notes individual records by index.
+// Context, focus, minimum severity, and temperature fall back to the
+// defaults as for FireWithInput. Slices of more than MaxRecords records are
+// rejected before the call with a *TooManyItemsError; analyze them in chunks.
+func (a *AnalyzeSynapse[T]) FireWithSlice(ctx context.Context, session *Session, input AnalyzeSliceInput[T]) (*AnalyzeSliceResponse, error) {
+	merged := a.mergeInputs(AnalyzeInput[T]{
+		Context:     input.Context,
+		Focus:       input.Focus,
+		MinSeverity: input.MinSeverity,
+		Constraints: input.Constraints,
+		Temperature: input.Temperature,
+	})
+	input.Context = merged.Context
+	input.Focus = merged.Focus
+	input.Constraints = merged.Constraints
+	if len(input.Data) == 0 {
+		return nil, fmt.Errorf("slice analysis failed: %w: no records to analyze", ErrInvalidPrompt)
+	}
+	limit := input.MaxRecords
+	if limit <= 0 {
+		limit = DefaultAnalyzeMaxRecords
+	}
+	if len(input.Data) > limit {
+		return nil, fmt.Errorf("slice analysis failed: %w",
+			&TooManyItemsError{Synapse: "analyze", Items: len(input.Data), Limit: limit})
+	}
+	if merged.MinSeverity != "" && severityRank(merged.MinSeverity) < 0 {
+		return nil, fmt.Errorf("slice analysis failed: %w: unknown minimum severity %q", ErrInvalidPrompt, merged.MinSeverity)
+	}
+
+	result, err := a.slice.executeChecked(ctx, session, a.buildSlicePrompt(input), merged.Temperature,
+		func(response AnalyzeSliceResponse) error {
+			return checkRecordIndices(response, len(input.Data))
+		})
+	if err != nil {
+		return nil, fmt.Errorf("slice analysis failed: %w", err)
+	}
 
+	response := result.Value
+	response.Findings = filterFindings(normalizeFindings(response.Findings), merged.MinSeverity)
 	return &response, nil
 }
 
-// mergeInputs combines defaults with user input.
+// FireDelta analyzes what changed between two snapshots and why it matters.
+// Findings name the changed fields in their area.
+func (a *AnalyzeSynapse[T]) FireDelta(ctx context.Context, session *Session, before, after T, focus string) (*AnalyzeResponse, error) {
+	input := DeltaInput[T]{Before: before, After: after, Focus: focus}
+	return a.FireDeltaWithInput(ctx, session, input)
+}
+
+// FireDeltaWithInput analyzes the delta between two snapshots with rich input.
+func (a *AnalyzeSynapse[T]) FireDeltaWithInput(ctx context.Context, session *Session, input DeltaInput[T]) (*AnalyzeResponse, error) {
+	// Context, focus, and temperature fall back to the defaults as for FireWithInput
+	merged := a.mergeInputs(AnalyzeInput[T]{
+		Context:     input.Context,
+		Focus:       input.Focus,
+		MinSeverity: input.MinSeverity,
+		Constraints: input.Constraints,
+		Temperature: input.Temperature,
+	})
+	input.Context = merged.Context
+	input.Focus = merged.Focus
+	input.Constraints = merged.Constraints
+	if merged.MinSeverity != "" && severityRank(merged.MinSeverity) < 0 {
+		return nil, fmt.Errorf("delta analysis failed: %w: unknown minimum severity %q", ErrInvalidPrompt, merged.MinSeverity)
+	}
+
+	prompt := a.buildDeltaPrompt(input)
+
+	response, err := a.service.Execute(ctx, session, prompt, merged.Temperature)
+	if err != nil {
+		return nil, fmt.Errorf("delta analysis failed: %w", err)
+	}
+
+	response.Findings = filterFindings(normalizeFindings(response.Findings), merged.MinSeverity)
+	return &response, nil
+}
+
+// normalizeFindings normalizes finding severities to standard values in
+// place and returns the findings.
+func normalizeFindings(findings []Finding) []Finding {
+	for i := range findings {
+		if findings[i].Severity != "" {
+			findings[i].Severity = normalizeSeverity(findings[i].Severity)
+		}
+	}
+	return findings
+}
+
+// filterFindings drops findings less severe than minSeverity, keeping
+// findings without a severity. An empty minSeverity keeps every finding.
+func filterFindings(findings []Finding, minSeverity string) []Finding {
+	if minSeverity == "" {
+		return findings
+	}
+	minimum := severityRank(minSeverity)
+	return slices.DeleteFunc(findings, func(finding Finding) bool {
+		return finding.Severity != "" && severityRank(finding.Severity) < minimum
+	})
+}
+
+// mergeInputs combines defaults with user input. Constraints from both are
+// kept, defaults first.
 func (a *AnalyzeSynapse[T]) mergeInputs(input AnalyzeInput[T]) AnalyzeInput[T] {
 	merged := a.defaults
 
@@ -124,6 +536,15 @@ func (a *AnalyzeSynapse[T]) mergeInputs(input AnalyzeInput[T]) AnalyzeInput[T] {
 	if input.Focus != "" {
 		merged.Focus = input.Focus
 	}
+	if input.MinSeverity != "" {
+		merged.MinSeverity = input.MinSeverity
+	}
+	if len(input.Constraints) > 0 {
+		merged.Constraints = append(append([]string(nil), a.defaults.Constraints...), input.Constraints...)
+	}
+	if input.Scored {
+		merged.Scored = true
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -151,15 +572,203 @@ func (a *AnalyzeSynapse[T]) buildPrompt(input AnalyzeInput[T]) *Prompt {
 	constraints := []string{
 		"analysis: comprehensive text analysis of the input data",
 		"confidence: 0.0 to 1.0",
-		"findings: list of key findings or issues discovered",
+		"findings: key findings or issues, each with a short title, severity (info, low, medium, high, or critical), area, " +
+			"field path when the finding concerns one field, description, and recommendation",
+		"reasoning: explanation of analysis methodology",
+	}
+
+	if input.Scored {
+		prompt.Schema = a.scoredSchema
+		constraints = append(constraints, analyzeScoreConstraint, analyzeScoreBasisConstraint)
+	}
+
+	if input.Focus != "" {
+		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
+	}
+	constraints = append(constraints, input.Constraints...)
+
+	prompt.Constraints = constraints
+
+	return prompt
+}
+
+// buildSlicePrompt constructs the prompt analyzing the records of the merged
+// input together, each numbered by its index.
+func (a *AnalyzeSynapse[T]) buildSlicePrompt(input AnalyzeSliceInput[T]) *Prompt {
+	records := make([]indexedItem[T], len(input.Data))
+	for i, record := range input.Data {
+		records[i] = indexedItem[T]{Index: i, Data: record}
+	}
+
+	constraints := []string{
+		"analysis: comprehensive text analysis of the records as a whole, including trends and comparisons across records",
+		"confidence: 0.0 to 1.0",
+		"findings: key findings or issues across records, each with a short title, severity (info, low, medium, high, or critical), area, " +
+			"field path when the finding concerns one field, description, and recommendation",
+		fmt.Sprintf("per_record: notes on individual records that stand out, each with the record's index as given (0 to %d) and a note", len(input.Data)-1),
+		"reasoning: explanation of analysis methodology",
+	}
+	if input.Focus != "" {
+		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
+	}
+	constraints = append(constraints, input.Constraints...)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Analyze records: %s", a.what),
+		Input:       renderJSON(records),
+		Context:     input.Context,
+		Schema:      a.sliceSchema,
+		Constraints: constraints,
+	}
+}
+
+// buildDeltaPrompt constructs the prompt for delta analysis, with both
+// snapshots labeled BEFORE and AFTER, or only their diff when diffOnly is set.
+func (a *AnalyzeSynapse[T]) buildDeltaPrompt(input DeltaInput[T]) *Prompt {
+	prompt := &Prompt{
+		Task:    fmt.Sprintf("Analyze changes: %s", a.what),
+		Input:   renderSnapshots(input.Before, input.After),
+		Context: input.Context,
+		Schema:  a.schema,
+	}
+
+	constraints := []string{
+		"analysis: what changed between BEFORE and AFTER and the implications of each change",
+		"confidence: 0.0 to 1.0",
+		"findings: one per significant change, each with a short title, severity (info, low, medium, high, or critical), area, " +
+			"field naming the changed field path, description, and recommendation",
 		"reasoning: explanation of analysis methodology",
+		"differences: focus on the differences; do not describe unchanged fields",
+	}
+
+	if a.diffOnly {
+		if changes, err := diffJSON(input.Before, input.After); err == nil {
+			prompt.Input = renderChanges(changes)
+			constraints = append(constraints,
+				"input: a field-level diff; each entry gives a path, the change (added, removed, or changed), and the BEFORE and AFTER values")
+		}
 	}
 
 	if input.Focus != "" {
 		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
 	}
+	constraints = append(constraints, input.Constraints...)
 
 	prompt.Constraints = constraints
 
 	return prompt
 }
+
+// renderSnapshots renders both snapshots as labeled JSON.
+func renderSnapshots(before, after any) string {
+	return renderLabeled("BEFORE", before, "AFTER", after)
+}
+
+// renderChanges renders a field-level diff as labeled JSON.
+func renderChanges(changes []fieldChange) string {
+	if len(changes) == 0 {
+		return "CHANGES (BEFORE -> AFTER):\nnone; the snapshots are identical"
+	}
+	return "CHANGES (BEFORE -> AFTER):\n" + renderJSON(changes)
+}
+
+// renderJSON renders a value as indented JSON, or with %+v if it cannot be marshaled.
+func renderJSON(value any) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+	return string(data)
+}
+
+// diffJSON returns the field-level differences between the JSON forms of
+// before and after, in path order.
+func diffJSON(before, after any) ([]fieldChange, error) {
+	beforeValue, err := toJSONValue(before)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+	afterValue, err := toJSONValue(after)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+
+	var changes []fieldChange
+	diffValues("", beforeValue, afterValue, &changes)
+	return changes, nil
+}
+
+// toJSONValue converts a value to its decoded JSON form.
+func toJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// diffValues appends the differences between two decoded JSON values at path.
+// Objects are compared by key and arrays by index; anything else is compared
+// as a whole.
+func diffValues(path string, before, after any, changes *[]fieldChange) {
+	beforeObject, beforeIsObject := before.(map[string]any)
+	afterObject, afterIsObject := after.(map[string]any)
+	if beforeIsObject && afterIsObject {
+		keys := make([]string, 0, len(beforeObject)+len(afterObject))
+		for key := range beforeObject {
+			keys = append(keys, key)
+		}
+		for key := range afterObject {
+			if _, shared := beforeObject[key]; !shared {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			beforeChild, inBefore := beforeObject[key]
+			afterChild, inAfter := afterObject[key]
+			switch {
+			case !inBefore:
+				*changes = append(*changes, fieldChange{Path: childPath, Change: fieldAdded, After: afterChild})
+			case !inAfter:
+				*changes = append(*changes, fieldChange{Path: childPath, Change: fieldRemoved, Before: beforeChild})
+			default:
+				diffValues(childPath, beforeChild, afterChild, changes)
+			}
+		}
+		return
+	}
+
+	beforeArray, beforeIsArray := before.([]any)
+	afterArray, afterIsArray := after.([]any)
+	if beforeIsArray && afterIsArray {
+		for i := range max(len(beforeArray), len(afterArray)) {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(beforeArray):
+				*changes = append(*changes, fieldChange{Path: childPath, Change: fieldAdded, After: afterArray[i]})
+			case i >= len(afterArray):
+				*changes = append(*changes, fieldChange{Path: childPath, Change: fieldRemoved, Before: beforeArray[i]})
+			default:
+				diffValues(childPath, beforeArray[i], afterArray[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		if path == "" {
+			path = "$"
+		}
+		*changes = append(*changes, fieldChange{Path: path, Change: fieldChanged, Before: before, After: after})
+	}
+}
@@ -2,6 +2,9 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -194,6 +197,63 @@ func TestAnalyzeSynapse_FireWithDetails(t *testing.T) {
 		}
 	})
 
+	t.Run("structured_findings", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"analysis": "two issues", "confidence": 0.9, "findings": [
+			{"severity": " High ", "area": "auth", "title": "passwords stored in plaintext", "recommendation": "hash with bcrypt"},
+			{"severity": "moderate", "area": "logging", "title": "tokens written to logs", "recommendation": ""}
+		], "reasoning": ["reviewed"]}`)
+		synapse, err := Analyze[TestData]("security review", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), TestData{Value: 1, Name: "test"})
+		if err != nil {
+			t.Fatalf("FireWithDetails failed: %v", err)
+		}
+		if len(response.Findings) != 2 {
+			t.Fatalf("Expected 2 findings, got %d", len(response.Findings))
+		}
+		if response.Findings[0].Severity != SeverityHigh {
+			t.Errorf("Expected severity normalized to high, got %q", response.Findings[0].Severity)
+		}
+		if response.Findings[1].Severity != SeverityMedium {
+			t.Errorf("Expected severity normalized to medium, got %q", response.Findings[1].Severity)
+		}
+		if response.Findings[0].Area != "auth" || response.Findings[0].Recommendation != "hash with bcrypt" {
+			t.Errorf("Unexpected finding: %+v", response.Findings[0])
+		}
+	})
+
+	t.Run("legacy_string_findings", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"analysis": "test", "confidence": 0.9, "findings": ["finding1"], "reasoning": ["test"]}`)
+		synapse, err := Analyze[TestData]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), TestData{Value: 1, Name: "test"})
+		if err != nil {
+			t.Fatalf("FireWithDetails failed: %v", err)
+		}
+		if len(response.Findings) != 1 || response.Findings[0] != (Finding{Title: "finding1"}) {
+			t.Errorf("Expected string finding as title, got %+v", response.Findings)
+		}
+	})
+
+	t.Run("unknown_severity", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"analysis": "test", "confidence": 0.9, "findings": [{"severity": "catastrophic", "area": "", "title": "bad", "recommendation": ""}], "reasoning": ["test"]}`)
+		synapse, err := Analyze[TestData]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		_, err = synapse.FireWithDetails(context.Background(), NewSession(), TestData{Value: 1, Name: "test"})
+		if !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("Expected ErrInvalidResponse for unknown severity, got %v", err)
+		}
+	})
+
 	t.Run("reliability", func(t *testing.T) {
 		provider := NewMockProviderWithResponse(`{"analysis": "test", "confidence": 0.8, "findings": [], "reasoning": ["test"]}`)
 		synapse, err := Analyze[TestData]("test", provider,
@@ -362,6 +422,52 @@ func TestAnalyzeSynapse_FireWithInputDetails(t *testing.T) {
 	})
 }
 
+func TestAnalyzeSynapse_WithDefaults(t *testing.T) {
+	t.Run("sets_defaults", func(t *testing.T) {
+		provider := NewMockProvider()
+		synapse, err := Analyze[TestData]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		synapseWithDefaults := synapse.WithDefaults(AnalyzeInput[TestData]{
+			Context:     "nightly batch",
+			Temperature: 0.2,
+		})
+
+		if synapseWithDefaults != synapse {
+			t.Fatal("WithDefaults should return the synapse")
+		}
+		if synapse.defaults.Context != "nightly batch" || synapse.defaults.Temperature != 0.2 {
+			t.Error("Defaults not set correctly")
+		}
+	})
+
+	t.Run("applied_to_prompt", func(t *testing.T) {
+		var prompt string
+		var temperature float32
+		provider := NewMockProviderWithCallback(func(p string, temp float32) (string, error) {
+			prompt, temperature = p, temp
+			return `{"analysis": "done", "confidence": 0.9, "findings": [], "reasoning": ["y"]}`, nil
+		})
+		synapse, err := Analyze[TestData]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse = synapse.WithDefaults(AnalyzeInput[TestData]{Context: "nightly batch", Temperature: 0.2})
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), TestData{Value: 1, Name: "x"}); err != nil {
+			t.Fatalf("Fire failed with defaults: %v", err)
+		}
+		if !strings.Contains(prompt, "nightly batch") {
+			t.Errorf("expected default context in prompt, got:\n%s", prompt)
+		}
+		if temperature != 0.2 {
+			t.Errorf("expected default temperature 0.2, got %v", temperature)
+		}
+	})
+}
+
 func TestAnalyzeSynapse_mergeInputs(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		synapse := &AnalyzeSynapse[TestData]{
@@ -427,6 +533,33 @@ func TestAnalyzeSynapse_mergeInputs(t *testing.T) {
 			t.Error("Should keep default context when not overridden")
 		}
 	})
+
+	t.Run("fallback", func(t *testing.T) {
+		synapse := &AnalyzeSynapse[TestData]{
+			defaults: AnalyzeInput[TestData]{
+				Data:        TestData{Value: 7},
+				Focus:       "default focus",
+				MinSeverity: SeverityHigh,
+				Constraints: []string{"default constraint"},
+				Temperature: 0.5,
+			},
+		}
+
+		merged := synapse.mergeInputs(AnalyzeInput[TestData]{Temperature: TemperatureUnset})
+
+		if merged.Data.Value != 0 {
+			t.Error("Data should never come from defaults")
+		}
+		if merged.Focus != "default focus" || merged.MinSeverity != SeverityHigh {
+			t.Error("Empty input fields should fall back to defaults")
+		}
+		if len(merged.Constraints) != 1 || merged.Constraints[0] != "default constraint" {
+			t.Errorf("Expected default constraints, got %q", merged.Constraints)
+		}
+		if merged.Temperature != 0.5 {
+			t.Errorf("Unset temperature should fall back to default, got %v", merged.Temperature)
+		}
+	})
 }
 
 func TestAnalyzeSynapse_buildPrompt(t *testing.T) {
@@ -545,4 +678,570 @@ func TestAnalyzeResponse_Validate(t *testing.T) {
 			t.Error("expected error for confidence > 1")
 		}
 	})
+
+	t.Run("score_out_of_range", func(t *testing.T) {
+		for _, score := range []float64{-1, 100.5} {
+			r := AnalyzeResponse{Analysis: "text", Confidence: 0.9, Score: score, ScoreBasis: "readiness"}
+			if err := r.Validate(); err == nil {
+				t.Errorf("expected error for score %v", score)
+			}
+		}
+	})
+
+	t.Run("score_without_basis", func(t *testing.T) {
+		r := AnalyzeResponse{Analysis: "text", Confidence: 0.9, Score: 80}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a score without its basis")
+		}
+	})
+}
+
+func TestAnalyzeSynapse_Score(t *testing.T) {
+	var prompts []string
+	respond := func(response string) Provider {
+		return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			prompts = append(prompts, prompt)
+			return response, nil
+		})
+	}
+	scored := `{"analysis": "mostly ready", "confidence": 0.8, "findings": [], "score": 72, "score_basis": "production readiness; 0 unusable, 100 ready to ship", "reasoning": ["r"]}`
+
+	t.Run("schema omits score by default", func(t *testing.T) {
+		prompts = nil
+		synapse, err := Analyze[TestData]("config", respond(scored))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), NewSession(), TestData{Value: 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompts[0], `"score`) || strings.Contains(prompts[0], analyzeScoreConstraint) {
+			t.Errorf("expected no score requested, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("scored input requests score", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Analyze[TestData]("config", respond(scored))
+		response, err := synapse.FireWithInputDetails(context.Background(), NewSession(), AnalyzeInput[TestData]{Data: TestData{Value: 1}, Scored: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Score != 72 || !strings.HasPrefix(response.ScoreBasis, "production readiness") {
+			t.Errorf("expected score 72 with its basis, got %v %q", response.Score, response.ScoreBasis)
+		}
+		for _, want := range []string{`"score"`, `"score_basis"`, analyzeScoreConstraint, analyzeScoreBasisConstraint} {
+			if !strings.Contains(prompts[0], want) {
+				t.Errorf("expected prompt to contain %q, got\n%s", want, prompts[0])
+			}
+		}
+	})
+
+	t.Run("rejects missing score", func(t *testing.T) {
+		synapse, _ := Analyze[TestData]("config", respond(`{"analysis": "ok", "confidence": 0.8, "findings": [], "reasoning": ["r"]}`))
+		session := NewSession()
+		_, err := synapse.FireScore(context.Background(), session, AnalyzeInput[TestData]{Data: TestData{Value: 1}})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "score_basis") {
+			t.Errorf("expected a missing score rejected, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("rejects out of range score", func(t *testing.T) {
+		synapse, _ := Analyze[TestData]("config", respond(strings.Replace(scored, `"score": 72`, `"score": 140`, 1)))
+		_, err := synapse.FireScore(context.Background(), NewSession(), AnalyzeInput[TestData]{Data: TestData{Value: 1}})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "score must be 0-100") {
+			t.Errorf("expected an out of range score rejected, got %v", err)
+		}
+	})
+
+	t.Run("fire score", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Analyze[TestData]("config", respond(scored))
+		score, err := synapse.FireScore(context.Background(), NewSession(), AnalyzeInput[TestData]{Data: TestData{Value: 1}, Focus: "production readiness"})
+		if err != nil || score != 72 {
+			t.Errorf("expected score 72, got %v, %v", score, err)
+		}
+		if !strings.Contains(prompts[0], analyzeScoreConstraint) {
+			t.Errorf("expected score requested, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("scored defaults", func(t *testing.T) {
+		synapse := &AnalyzeSynapse[TestData]{defaults: AnalyzeInput[TestData]{Scored: true}}
+		if merged := synapse.mergeInputs(AnalyzeInput[TestData]{}); !merged.Scored {
+			t.Error("expected Scored kept from defaults")
+		}
+	})
+}
+
+func TestFinding_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		wantErr bool
+	}{
+		{"complete", Finding{Title: "no backups", Severity: "critical", Area: "db", Field: "backup.enabled", Description: "nothing is backed up", Recommendation: "enable backups"}, false},
+		{"title_only", Finding{Title: "minor nit"}, false},
+		{"severity_variant", Finding{Title: "note", Severity: "Informational"}, false},
+		{"warning", Finding{Title: "slow query", Severity: "warning"}, false},
+		{"missing_title", Finding{Severity: "high", Area: "db", Description: "no backups"}, true},
+		{"blank_title", Finding{Title: "  ", Severity: "high"}, true},
+		{"unknown_severity", Finding{Title: "x", Severity: "urgent-ish"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.finding.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeSeverity(t *testing.T) {
+	tests := map[string]string{
+		"info":          SeverityInfo,
+		"Informational": SeverityInfo,
+		"low":           SeverityLow,
+		"minor":         SeverityLow,
+		" MEDIUM ":      SeverityMedium,
+		"moderate":      SeverityMedium,
+		"Warning":       SeverityMedium,
+		"High":          SeverityHigh,
+		"major":         SeverityHigh,
+		"critical":      SeverityCritical,
+		"severe":        SeverityCritical,
+		"catastrophic":  "",
+		"":              "",
+	}
+	for input, want := range tests {
+		if got := normalizeSeverity(input); got != want {
+			t.Errorf("normalizeSeverity(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFlattenFindings(t *testing.T) {
+	findings := []Finding{
+		{Title: "plaintext passwords", Severity: "high", Area: "auth", Recommendation: "hash them"},
+		{Title: "weak hashing", Severity: "medium", Area: "auth", Field: "auth.hash", Description: "MD5 is used", Recommendation: "use bcrypt"},
+		{Title: "legacy finding"},
+	}
+	got := FlattenFindings(findings)
+	want := []string{
+		"[high] auth: plaintext passwords (recommendation: hash them)",
+		"[medium] auth.hash: weak hashing - MD5 is used (recommendation: use bcrypt)",
+		"legacy finding",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d strings, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("FlattenFindings()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+type deltaConfig struct {
+	Name     string            `json:"name"`
+	Replicas int               `json:"replicas"`
+	Ports    []int             `json:"ports"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestAnalyzeSynapse_MinSeverity(t *testing.T) {
+	response := `{"analysis": "two issues", "confidence": 0.9, "findings": [
+		{"severity": "info", "area": "logging", "title": "verbose logs", "recommendation": ""},
+		"plain finding without a severity",
+		{"severity": "Major", "area": "auth", "title": "tokens never expire", "recommendation": "set a TTL"},
+		{"severity": "medium", "area": "db", "title": "no index on email", "recommendation": "add one"},
+		{"severity": "critical", "area": "secrets", "title": "password in config", "recommendation": "use a vault"}
+	], "reasoning": ["r"]}`
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return response, nil
+	})
+	synapse, err := Analyze[TestData]("security review", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	areas := func(findings []Finding) []string {
+		names := make([]string, len(findings))
+		for i, finding := range findings {
+			names[i] = finding.Area
+		}
+		return names
+	}
+
+	result, err := synapse.FireWithInputDetails(context.Background(), NewSession(), AnalyzeInput[TestData]{MinSeverity: "High"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := areas(result.Findings); !slices.Equal(got, []string{"", "auth", "secrets"}) {
+		t.Errorf("expected high and above plus unrated findings, got %q", got)
+	}
+	if strings.Contains(prompt, "High") {
+		t.Error("expected the filter applied client-side only")
+	}
+
+	synapse.defaults = AnalyzeInput[TestData]{MinSeverity: SeverityMedium}
+	result, err = synapse.FireDeltaWithInput(context.Background(), NewSession(), DeltaInput[TestData]{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := areas(result.Findings); !slices.Equal(got, []string{"", "auth", "db", "secrets"}) {
+		t.Errorf("expected the default minimum applied to delta analysis, got %q", got)
+	}
+
+	result, err = synapse.FireWithInputDetails(context.Background(), NewSession(), AnalyzeInput[TestData]{MinSeverity: "warning"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := areas(result.Findings); !slices.Equal(got, []string{"", "auth", "db", "secrets"}) {
+		t.Errorf("expected warning to filter as medium, got %q", got)
+	}
+
+	_, err = synapse.FireWithInputDetails(context.Background(), NewSession(), AnalyzeInput[TestData]{MinSeverity: "urgent"})
+	if !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected an unknown minimum rejected, got %v", err)
+	}
+}
+
+func TestAnalyzeSynapse_FireDelta(t *testing.T) {
+	before := deltaConfig{Name: "api", Replicas: 3, Ports: []int{80, 443}, Labels: map[string]string{"tier": "web", "team": "core"}}
+	after := deltaConfig{Name: "api", Replicas: 1, Ports: []int{80}, Labels: map[string]string{"tier": "web", "owner": "ops"}}
+	response := `{"analysis": "replicas reduced", "confidence": 0.9, "findings": [{"title": "replicas dropped from 3 to 1", "severity": "High", "area": "scaling", "field": "replicas", "description": "", "recommendation": "restore redundancy"}], "reasoning": ["compared"]}`
+
+	t.Run("full", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return response, nil
+		})
+		synapse, err := Analyze[deltaConfig]("deployment config review", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		result, err := synapse.FireDelta(context.Background(), session, before, after, "availability")
+		if err != nil {
+			t.Fatalf("FireDelta failed: %v", err)
+		}
+		if len(result.Findings) != 1 || result.Findings[0].Severity != SeverityHigh || result.Findings[0].Field != "replicas" {
+			t.Errorf("unexpected findings: %+v", result.Findings)
+		}
+
+		for _, want := range []string{
+			"Task: Analyze changes: deployment config review",
+			"BEFORE:\n{\n  \"name\": \"api\",\n  \"replicas\": 3,",
+			"AFTER:\n{\n  \"name\": \"api\",\n  \"replicas\": 1,",
+			"differences: focus on the differences",
+			"focus: availability",
+		} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected prompt to contain %q, got:\n%s", want, prompt)
+			}
+		}
+		if strings.Contains(prompt, "CHANGES") {
+			t.Error("expected full snapshots, not a diff")
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected session to record the exchange, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("diff only", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return response, nil
+		})
+		synapse, err := Analyze[deltaConfig]("deployment config review", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse.DiffOnly(true)
+
+		_, err = synapse.FireDeltaWithInput(context.Background(), NewSession(), DeltaInput[deltaConfig]{
+			Before:  before,
+			After:   after,
+			Context: "production cluster",
+		})
+		if err != nil {
+			t.Fatalf("FireDeltaWithInput failed: %v", err)
+		}
+
+		if strings.Contains(prompt, "BEFORE:\n{") || strings.Contains(prompt, `"name": "api"`) {
+			t.Errorf("expected only the diff to be sent, got:\n%s", prompt)
+		}
+		for _, want := range []string{
+			"CHANGES (BEFORE -> AFTER):",
+			`"path": "replicas"`,
+			`"path": "ports[1]"`,
+			`"path": "labels.owner"`,
+			`"path": "labels.team"`,
+			"Context: production cluster",
+			"input: a field-level diff",
+		} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected prompt to contain %q, got:\n%s", want, prompt)
+			}
+		}
+	})
+
+	t.Run("diff only identical", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return response, nil
+		})
+		synapse, err := Analyze[deltaConfig]("review", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		if _, err := synapse.DiffOnly(true).FireDelta(context.Background(), NewSession(), before, before, ""); err != nil {
+			t.Fatalf("FireDelta failed: %v", err)
+		}
+		if !strings.Contains(prompt, "none; the snapshots are identical") {
+			t.Errorf("expected identical snapshots to be reported, got:\n%s", prompt)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		synapse, err := Analyze[deltaConfig]("review", NewMockProviderWithError("boom"))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.FireDelta(context.Background(), NewSession(), before, after, ""); err == nil {
+			t.Error("expected error from provider")
+		}
+	})
+}
+
+func TestDiffJSON(t *testing.T) {
+	before := map[string]any{
+		"name":  "api",
+		"limit": 10,
+		"tags":  []string{"a", "b"},
+		"db":    map[string]any{"host": "old", "port": 5432},
+		"gone":  true,
+	}
+	after := map[string]any{
+		"name":  "api",
+		"limit": 20,
+		"tags":  []string{"a", "c", "d"},
+		"db":    map[string]any{"host": "new", "port": 5432},
+		"new":   nil,
+	}
+
+	changes, err := diffJSON(before, after)
+	if err != nil {
+		t.Fatalf("diffJSON failed: %v", err)
+	}
+
+	want := []fieldChange{
+		{Path: "db.host", Change: fieldChanged, Before: "old", After: "new"},
+		{Path: "gone", Change: fieldRemoved, Before: true},
+		{Path: "limit", Change: fieldChanged, Before: float64(10), After: float64(20)},
+		{Path: "new", Change: fieldAdded},
+		{Path: "tags[1]", Change: fieldChanged, Before: "b", After: "c"},
+		{Path: "tags[2]", Change: fieldAdded, After: "d"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for i := range want {
+		if changes[i].Path != want[i].Path || changes[i].Change != want[i].Change ||
+			changes[i].Before != want[i].Before || changes[i].After != want[i].After {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		changes, err := diffJSON(before, before)
+		if err != nil || len(changes) != 0 {
+			t.Errorf("expected no changes, got %+v, %v", changes, err)
+		}
+	})
+
+	t.Run("root", func(t *testing.T) {
+		changes, err := diffJSON(1, 2)
+		if err != nil || len(changes) != 1 || changes[0].Path != "$" {
+			t.Errorf("expected root change, got %+v, %v", changes, err)
+		}
+	})
+
+	t.Run("unmarshalable", func(t *testing.T) {
+		if _, err := diffJSON(make(chan int), 1); err == nil {
+			t.Error("expected error for value that cannot be marshaled")
+		}
+	})
+}
+
+func TestAnalyzeSliceResponse_Validate(t *testing.T) {
+	valid := AnalyzeSliceResponse{Analysis: "sales grew", Confidence: 0.8, PerRecord: []RecordFinding{{Index: 1, Note: "best quarter"}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	empty := valid
+	empty.Analysis = ""
+	if err := empty.Validate(); err == nil {
+		t.Error("expected empty analysis rejected")
+	}
+	blank := valid
+	blank.PerRecord = []RecordFinding{{Index: 0, Note: " "}}
+	if err := blank.Validate(); err == nil {
+		t.Error("expected empty note rejected")
+	}
+	if err := checkRecordIndices(valid, 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, index := range []int{-1, 2} {
+		outside := valid
+		outside.PerRecord = []RecordFinding{{Index: index, Note: "n"}}
+		if err := checkRecordIndices(outside, 2); err == nil {
+			t.Errorf("expected index %d rejected", index)
+		}
+	}
+}
+
+func TestAnalyzeSynapse_FireWithSlice(t *testing.T) {
+	quarters := []TestData{{Value: 100, Name: "Q1"}, {Value: 120, Name: "Q2"}, {Value: 90, Name: "Q3"}}
+	sliceJSON := func(index string) string {
+		return `{"analysis": "growth stalled in Q3", "confidence": 0.8, "findings": [
+			{"severity": "minor", "area": "Q1", "title": "slow start", "recommendation": ""},
+			{"severity": "high", "area": "Q3", "title": "revenue fell 25%", "recommendation": "review pricing"}
+		], "per_record": [{"index": ` + index + `, "note": "revenue fell after two quarters of growth"}], "reasoning": ["compared quarters"]}`
+	}
+
+	t.Run("records analyzed together", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return sliceJSON("2"), nil
+		})
+		synapse, err := Analyze[TestData]("sales trends", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		response, err := synapse.FireWithSlice(context.Background(), session, AnalyzeSliceInput[TestData]{
+			Data:        quarters,
+			Focus:       "quarter over quarter",
+			MinSeverity: SeverityMedium,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.PerRecord) != 1 || response.PerRecord[0].Index != 2 {
+			t.Errorf("unexpected per-record findings: %+v", response.PerRecord)
+		}
+		if len(response.Findings) != 1 || response.Findings[0].Severity != SeverityHigh {
+			t.Errorf("expected findings normalized and filtered, got %+v", response.Findings)
+		}
+		for _, want := range []string{"Analyze records: sales trends", `"index": 2`, `"name": "Q3"`, "(0 to 2)", "focus: quarter over quarter"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+			}
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected the call recorded in the session, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls++
+			if calls == 1 {
+				return sliceJSON("3"), nil
+			}
+			return sliceJSON("0"), nil
+		})
+		synapse, _ := Analyze[TestData]("sales trends", provider)
+		_, err := synapse.FireWithSlice(context.Background(), NewSession(), AnalyzeSliceInput[TestData]{Data: quarters})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "from 0 to 2, got 3") {
+			t.Errorf("expected an out-of-range index rejected, got %v", err)
+		}
+
+		retrying, _ := Analyze[TestData]("sales trends", provider, WithValidationRetry(2))
+		response, err := retrying.FireWithSlice(context.Background(), NewSession(), AnalyzeSliceInput[TestData]{Data: quarters})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.PerRecord[0].Index != 0 {
+			t.Errorf("expected a valid index after a retry, got %+v", response.PerRecord)
+		}
+	})
+
+	t.Run("record cap", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls++
+			return sliceJSON("0"), nil
+		})
+		synapse, _ := Analyze[TestData]("sales trends", provider)
+
+		_, err := synapse.FireWithSlice(context.Background(), NewSession(), AnalyzeSliceInput[TestData]{Data: quarters, MaxRecords: 2})
+		var tooMany *TooManyItemsError
+		if !errors.As(err, &tooMany) || tooMany.Items != 3 || tooMany.Limit != 2 || !strings.Contains(err.Error(), "chunks") {
+			t.Errorf("expected a *TooManyItemsError, got %v", err)
+		}
+		_, err = synapse.FireWithSlice(context.Background(), NewSession(), AnalyzeSliceInput[TestData]{Data: make([]TestData, DefaultAnalyzeMaxRecords+1)})
+		if !errors.Is(err, ErrTooManyItems) {
+			t.Errorf("expected the default cap applied, got %v", err)
+		}
+		_, err = synapse.FireWithSlice(context.Background(), NewSession(), AnalyzeSliceInput[TestData]{})
+		if !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("expected no records rejected, got %v", err)
+		}
+		if calls != 0 {
+			t.Errorf("expected no provider call, got %d", calls)
+		}
+	})
+}
+
+func TestAnalyzeSynapse_Constraints(t *testing.T) {
+	const limit = "never recommend increasing max_connections above 500"
+	var prompts []string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompts = append(prompts, p)
+		if strings.Contains(p, "per_record") {
+			return `{"analysis": "x", "confidence": 0.8, "findings": [], "per_record": [], "reasoning": ["x"]}`, nil
+		}
+		return `{"analysis": "x", "confidence": 0.8, "findings": [], "reasoning": ["x"]}`, nil
+	})
+	synapse, _ := Analyze[TestData]("database config", provider)
+	synapse.WithDefaults(AnalyzeInput[TestData]{Constraints: []string{"cite the setting by name"}})
+
+	merged := synapse.mergeInputs(AnalyzeInput[TestData]{Constraints: []string{limit}})
+	if !slices.Equal(merged.Constraints, []string{"cite the setting by name", limit}) {
+		t.Errorf("expected default constraints first, got %q", merged.Constraints)
+	}
+	prompt := synapse.buildPrompt(AnalyzeInput[TestData]{Focus: "pooling", Constraints: merged.Constraints})
+	if n := len(prompt.Constraints); n < 3 || prompt.Constraints[n-1] != limit || prompt.Constraints[n-3] != "focus: pooling" {
+		t.Errorf("expected the caller's constraints after the built-in ones, got %q", prompt.Constraints)
+	}
+
+	ctx := context.Background()
+	if _, err := synapse.FireWithSlice(ctx, NewSession(), AnalyzeSliceInput[TestData]{Data: []TestData{{Value: 1}}, Constraints: []string{limit}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := synapse.FireDeltaWithInput(ctx, NewSession(), DeltaInput[TestData]{Constraints: []string{limit}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, p := range prompts {
+		if !strings.Contains(p, "cite the setting by name") || !strings.Contains(p, limit) {
+			t.Errorf("call %d: expected default and call constraints, got %s", i, p)
+		}
+	}
 }
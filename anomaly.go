@@ -0,0 +1,222 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// AnomalyInput contains rich input structure for anomaly detection.
+type AnomalyInput[T any] struct {
+	Records     []T     // The records to inspect
+	Context     string  // Optional context, such as what normal looks like
+	Focus       string  // Optional specific aspect to focus on, such as latency fields
+	Temperature float32 // Temperature for detection
+}
+
+// Anomaly is a record that stands out from the others.
+type Anomaly struct {
+	Index    int    `json:"index"`    // Position of the record in the input, from 0
+	Reason   string `json:"reason"`   // Why the record is anomalous
+	Severity string `json:"severity"` // low, medium, or high
+}
+
+// AnomalyResponse contains the response from an anomaly synapse.
+type AnomalyResponse struct {
+	Anomalies  []Anomaly `json:"anomalies"`  // Anomalous records; empty if none stand out
+	Confidence float64   `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string  `json:"reasoning"`  // Explanation of what was considered normal
+}
+
+// Validate checks if the response is valid. Indices are checked against the
+// records by the synapse.
+func (r AnomalyResponse) Validate() error {
+	for i, anomaly := range r.Anomalies {
+		if strings.TrimSpace(anomaly.Reason) == "" {
+			return fmt.Errorf("anomaly %d: reason required but empty", i)
+		}
+		switch normalizeSeverity(anomaly.Severity) {
+		case SeverityLow, SeverityMedium, SeverityHigh:
+		default:
+			return fmt.Errorf("anomaly %d: severity must be low, medium, or high, got %q", i, anomaly.Severity)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkIndices rejects anomalies whose index is not a record position.
+func checkIndices(records int, response AnomalyResponse) error {
+	for i, anomaly := range response.Anomalies {
+		if anomaly.Index < 0 || anomaly.Index >= records {
+			return fmt.Errorf("anomaly %d: index %d out of range for %d records", i, anomaly.Index, records)
+		}
+	}
+	return nil
+}
+
+// AnomalySynapse finds the records of type T that stand out from the rest.
+type AnomalySynapse[T any] struct {
+	what     string // What kind of records are inspected
+	schema   string // Pre-computed JSON schema
+	defaults AnomalyInput[T]
+	service  *Service[AnomalyResponse]
+}
+
+// DetectAnomalies creates a new anomaly synapse for records of type T.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	detector, err := DetectAnomalies[Request]("API requests", provider)
+//	response, err := detector.FireWithInput(ctx, session, AnomalyInput[Request]{
+//	    Records: requests,
+//	    Focus:   "latency columns",
+//	})
+//	for _, anomaly := range response.Anomalies {
+//	    log.Printf("%s: %+v", anomaly.Reason, requests[anomaly.Index])
+//	}
+func DetectAnomalies[T any](what string, provider Provider, opts ...Option) (*AnomalySynapse[T], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[AnomalyResponse]()
+	if err != nil {
+		return nil, fmt.Errorf("anomaly synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[AnomalyResponse](pipeline, "anomaly", provider, DefaultTemperatureAnalytical)
+
+	return &AnomalySynapse[T]{
+		what:    what,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the underlying pipeline.
+func (a *AnomalySynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return a.service.GetPipeline()
+}
+
+// WithDefaults creates a new DetectAnomalies with default input values.
+// These are merged with user input at execution time.
+func (a *AnomalySynapse[T]) WithDefaults(defaults AnomalyInput[T]) *AnomalySynapse[T] {
+	a.defaults = defaults
+	return a
+}
+
+// Fire finds the anomalous records.
+func (a *AnomalySynapse[T]) Fire(ctx context.Context, session *Session, records []T) (AnomalyResponse, error) {
+	return a.FireWithInput(ctx, session, AnomalyInput[T]{Records: records})
+}
+
+// FireResult finds the anomalous records and returns the response in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (a *AnomalySynapse[T]) FireResult(ctx context.Context, session *Session, records []T) (Result[AnomalyResponse], error) {
+	return a.execute(ctx, session, AnomalyInput[T]{Records: records})
+}
+
+// FireWithInput finds the anomalous records with rich input.
+func (a *AnomalySynapse[T]) FireWithInput(ctx context.Context, session *Session, input AnomalyInput[T]) (AnomalyResponse, error) {
+	result, err := a.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// execute merges input with the defaults and inspects the records,
+// rejecting anomalies that point outside them.
+func (a *AnomalySynapse[T]) execute(ctx context.Context, session *Session, input AnomalyInput[T]) (Result[AnomalyResponse], error) {
+	merged := a.mergeInputs(input)
+	if len(merged.Records) == 0 {
+		return Result[AnomalyResponse]{Provider: a.service.providerName},
+			fmt.Errorf("%w: anomaly synapse needs records", ErrInvalidPrompt)
+	}
+
+	result, err := a.service.executeChecked(ctx, session, a.buildPrompt(merged), merged.Temperature, func(response AnomalyResponse) error {
+		return checkIndices(len(merged.Records), response)
+	})
+	if err != nil {
+		return result, fmt.Errorf("anomaly detection failed: %w", err)
+	}
+
+	for i := range result.Value.Anomalies {
+		result.Value.Anomalies[i].Severity = normalizeSeverity(result.Value.Anomalies[i].Severity)
+	}
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input.
+func (a *AnomalySynapse[T]) mergeInputs(input AnomalyInput[T]) AnomalyInput[T] {
+	merged := a.defaults
+
+	if len(input.Records) > 0 {
+		merged.Records = input.Records
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Focus != "" {
+		merged.Focus = input.Focus
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. Each record is
+// rendered on its own line after its index.
+func (a *AnomalySynapse[T]) buildPrompt(input AnomalyInput[T]) *Prompt {
+	prompt := &Prompt{
+		Task:    fmt.Sprintf("Find the anomalous records among these %s", a.what),
+		Input:   renderRecords(input.Records),
+		Context: input.Context,
+		Schema:  a.schema,
+	}
+
+	constraints := []string{
+		fmt.Sprintf("index: the record's number in brackets, from 0 to %d", len(input.Records)-1),
+		"anomalies: only records that stand out from the others; empty if none do",
+		"reason: what makes the record stand out, naming the fields involved",
+		"severity: low, medium, or high",
+		"confidence: 0.0 to 1.0",
+		"reasoning: what the normal records look like",
+	}
+
+	if input.Focus != "" {
+		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
+	}
+
+	prompt.Constraints = constraints
+
+	return prompt
+}
+
+// renderRecords renders records one per line as compact JSON after their
+// index, e.g. "[0] {"id":1}". Records that cannot be marshaled use %+v.
+func renderRecords[T any](records []T) string {
+	lines := make([]string, len(records))
+	for i, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%+v", record))
+		}
+		lines[i] = fmt.Sprintf("[%d] %s", i, data)
+	}
+	return strings.Join(lines, "\n")
+}
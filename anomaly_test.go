@@ -0,0 +1,192 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// requestRecord is the record type of the anomaly tests.
+type requestRecord struct {
+	Path      string `json:"path"`
+	LatencyMS int    `json:"latency_ms"`
+}
+
+// requestRecords are the records of the anomaly tests.
+var requestRecords = []requestRecord{
+	{Path: "/users", LatencyMS: 40},
+	{Path: "/users", LatencyMS: 45},
+	{Path: "/users", LatencyMS: 4000},
+}
+
+func TestAnomalyResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Index: 2, Reason: "slow", Severity: "high"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"baseline"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("empty_reason", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Severity: "low"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"baseline"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an anomaly without a reason")
+		}
+	})
+
+	t.Run("unknown_severity", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Reason: "slow", Severity: "urgent"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"baseline"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an unknown severity")
+		}
+	})
+
+	t.Run("severity_outside_the_scale", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Reason: "slow", Severity: "critical"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"baseline"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a severity outside the scale")
+		}
+	})
+
+	t.Run("confidence_too_low", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Index: 2, Reason: "slow", Severity: "high"}},
+			Confidence: -0.1,
+			Reasoning:  []string{"baseline"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for negative confidence")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := AnomalyResponse{
+			Anomalies:  []Anomaly{{Index: 2, Reason: "slow", Severity: "high"}},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestDetectAnomalies_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"anomalies": [{"index": 2, "reason": "latency_ms is 100 times the others", "severity": "Major"}], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`, nil
+	})
+	detector, err := DetectAnomalies[requestRecord]("API requests", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := detector.Fire(context.Background(), NewSession(), requestRecords)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Anomalies) != 1 || response.Anomalies[0].Index != 2 {
+		t.Errorf("unexpected anomalies: %+v", response.Anomalies)
+	}
+	if response.Anomalies[0].Severity != SeverityHigh {
+		t.Errorf("expected severity normalized to high, got %q", response.Anomalies[0].Severity)
+	}
+	for _, want := range []string{"API requests", `[0] {"path":"/users","latency_ms":40}`, `[2] {"path":"/users","latency_ms":4000}`, "from 0 to 2"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestDetectAnomalies_NoAnomalies(t *testing.T) {
+	detector, _ := DetectAnomalies[requestRecord]("API requests", NewMockProviderWithResponse(`{"anomalies": [], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`))
+
+	response, err := detector.Fire(context.Background(), NewSession(), requestRecords[:2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Anomalies) != 0 {
+		t.Errorf("expected no anomalies, got %+v", response.Anomalies)
+	}
+}
+
+func TestDetectAnomalies_IndexOutOfRange(t *testing.T) {
+	for _, index := range []string{"3", "-1"} {
+		t.Run(index, func(t *testing.T) {
+			provider := NewMockProviderWithResponse(`{"anomalies": [{"index": ` + index + `, "reason": "slow", "severity": "high"}], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`)
+			detector, _ := DetectAnomalies[requestRecord]("API requests", provider)
+
+			_, err := detector.Fire(context.Background(), NewSession(), requestRecords)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "index "+index+" out of range for 3 records") {
+				t.Errorf("expected ErrInvalidResponse for index %s, got %v", index, err)
+			}
+		})
+	}
+}
+
+func TestDetectAnomalies_EmptyRecords(t *testing.T) {
+	detector, _ := DetectAnomalies[requestRecord]("API requests", NewMockProviderWithName("mock"))
+
+	result, err := detector.FireResult(context.Background(), NewSession(), nil)
+	if !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+	if result.Provider != "mock" {
+		t.Errorf("expected provider name on the result, got %q", result.Provider)
+	}
+}
+
+func TestDetectAnomalies_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"anomalies": [], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`, nil
+	})
+	detector, _ := DetectAnomalies[requestRecord]("API requests", provider)
+	detector.WithDefaults(AnomalyInput[requestRecord]{Context: "a read-only endpoint", Focus: "latency columns"})
+
+	if _, err := detector.FireWithInput(context.Background(), NewSession(), AnomalyInput[requestRecord]{Records: requestRecords}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "a read-only endpoint") || !strings.Contains(prompt, "focus: latency columns") {
+		t.Errorf("expected defaults in prompt, got %s", prompt)
+	}
+}
+
+func TestDetectAnomalies_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"anomalies": [{"index": 3, "reason": "slow", "severity": "high"}], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`, nil
+		}
+		return `{"anomalies": [{"index": 2, "reason": "slow", "severity": "high"}], "confidence": 0.8, "reasoning": ["requests usually take under 50ms"]}`, nil
+	})
+	detector, _ := DetectAnomalies[requestRecord]("API requests", provider, WithValidationRetry(2))
+
+	response, err := detector.Fire(context.Background(), NewSession(), requestRecords)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || response.Anomalies[0].Index != 2 {
+		t.Errorf("expected a retry after the out-of-range index, got %d calls and %+v", calls, response)
+	}
+}
@@ -0,0 +1,287 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// AnswerUnknown is the answer given, without citations, when the documents
+// do not answer the question and WithAllowUnknown is set.
+const AnswerUnknown = "I don't know"
+
+// answerUnknownConstraint is added to answer prompts that accept AnswerUnknown.
+const answerUnknownConstraint = `if the documents do not answer the question, answer exactly "` + AnswerUnknown + `" with no citations`
+
+// allowUnknownID identifies the unknown answers option.
+var allowUnknownID = pipz.NewIdentity("zyn:allow-unknown", "Accepts answers the documents do not support")
+
+// Document is a source an answer synapse answers from. Citations name it by ID.
+type Document struct {
+	ID   string // Stable identifier cited by answers; defaults to "doc-N", its 1-based position
+	Text string // The document's content
+}
+
+// Citation is a quote from a document supporting an answer.
+type Citation struct {
+	DocID string `json:"doc_id"` // ID of the quoted document
+	Quote string `json:"quote"`  // Text quoted from the document
+}
+
+// AnswerInput contains rich input structure for question answering.
+type AnswerInput struct {
+	Question    string     // The question to answer
+	Documents   []Document // The sources the answer must come from
+	Context     string     // Optional background information
+	Temperature float32    // LLM temperature setting for this specific request
+}
+
+// AnswerResponse contains the response from an answer synapse.
+type AnswerResponse struct {
+	Answer     string     `json:"answer"`     // The answer to the question
+	Citations  []Citation `json:"citations"`  // Quotes supporting the answer
+	Confidence float64    `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string   `json:"reasoning"`  // Explanation of the answer
+}
+
+// Validate checks if the response is valid. Citations are checked against
+// the documents by the synapse.
+func (r AnswerResponse) Validate() error {
+	if strings.TrimSpace(r.Answer) == "" {
+		return fmt.Errorf("answer required but empty")
+	}
+	for i, citation := range r.Citations {
+		if strings.TrimSpace(citation.DocID) == "" {
+			return fmt.Errorf("citation %d: doc_id required but empty", i)
+		}
+		if strings.TrimSpace(citation.Quote) == "" {
+			return fmt.Errorf("citation %d: quote required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// Unknown reports whether the response is AnswerUnknown, ignoring case,
+// surrounding space and a final period.
+func (r AnswerResponse) Unknown() bool {
+	answer := strings.TrimSuffix(strings.TrimSpace(r.Answer), ".")
+	return strings.EqualFold(answer, AnswerUnknown) && len(r.Citations) == 0
+}
+
+// answerSettings holds the settings of one answer call.
+type answerSettings struct {
+	allowUnknown bool // Accept AnswerUnknown, with WithAllowUnknown
+}
+
+// WithAllowUnknown lets answer synapses answer AnswerUnknown, with no
+// citations, when the documents do not answer the question. Without it every
+// answer must cite the documents, so a model with nothing to cite is pushed
+// to invent support rather than admit it.
+// The option has no effect on other synapse types.
+func WithAllowUnknown() Option {
+	return withSettings(allowUnknownID, func(req *SynapseRequest, settings *answerSettings) {
+		if settings.allowUnknown {
+			return
+		}
+		settings.allowUnknown = true
+		req.Prompt.Constraints = append(slices.Clone(req.Prompt.Constraints), answerUnknownConstraint)
+	})
+}
+
+// validateCitations checks that an answer cites the prompt's documents, or
+// is AnswerUnknown and the call accepts it.
+func validateCitations(prompt *Prompt, settings *answerSettings, response AnswerResponse) error {
+	if len(response.Citations) == 0 {
+		if response.Unknown() && settings.allowUnknown {
+			return nil
+		}
+		return fmt.Errorf("citations required but empty")
+	}
+	for i, citation := range response.Citations {
+		if !slices.ContainsFunc(prompt.Documents, func(doc Document) bool { return doc.ID == citation.DocID }) {
+			return fmt.Errorf("citation %d: unknown document %q", i, citation.DocID)
+		}
+	}
+	return nil
+}
+
+// AnswerSynapse represents a question-answering synapse that cites its sources.
+type AnswerSynapse struct {
+	topic    string
+	defaults AnswerInput
+	base     *Base[AnswerInput, AnswerResponse]
+}
+
+// NewAnswer creates a new answer synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewAnswer(topic string, provider Provider, opts ...Option) (*AnswerSynapse, error) {
+	synapse := &AnswerSynapse{topic: topic}
+
+	base, err := NewSynapse(SynapseConfig[AnswerInput, AnswerResponse]{
+		Type:        "answer",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (a *AnswerSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return a.base.GetPipeline()
+}
+
+// WithDefaults creates a new Answer with default input values.
+// These are merged with user input at execution time.
+func (a *AnswerSynapse) WithDefaults(defaults AnswerInput) *AnswerSynapse {
+	a.defaults = defaults
+	return a
+}
+
+// Fire answers question from documents and returns the answer text.
+func (a *AnswerSynapse) Fire(ctx context.Context, session *Session, question string, documents []Document) (string, error) {
+	response, err := a.FireWithInput(ctx, session, AnswerInput{Question: question, Documents: documents})
+	if err != nil {
+		return "", err
+	}
+	return response.Answer, nil
+}
+
+// FireResult answers question from documents and returns the answer text in
+// a Result envelope carrying the call's usage, timing, and request metadata.
+func (a *AnswerSynapse) FireResult(ctx context.Context, session *Session, question string, documents []Document) (Result[string], error) {
+	result, err := a.execute(ctx, session, AnswerInput{Question: question, Documents: documents})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Answer), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (a *AnswerSynapse) FireWithInput(ctx context.Context, session *Session, input AnswerInput) (AnswerResponse, error) {
+	result, err := a.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, answering the
+// input from the items as documents. The returned Validator is an
+// AnswerResponse.
+func (a *AnswerSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	answer := AnswerInput{Question: input.Input, Context: input.Context, Temperature: input.Temperature}
+	for _, item := range input.Items {
+		answer.Documents = append(answer.Documents, Document{Text: item})
+	}
+	response, err := a.FireWithInput(ctx, session, answer)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and answers the question.
+func (a *AnswerSynapse) execute(ctx context.Context, session *Session, input AnswerInput) (Result[AnswerResponse], error) {
+	merged := a.mergeInputs(input)
+	if err := checkDocuments(merged); err != nil {
+		return Result[AnswerResponse]{Provider: a.base.service.providerName}, err
+	}
+	prompt := a.buildPrompt(merged)
+	prompt.Schema = a.base.Schema()
+	settings := &answerSettings{}
+	return a.base.service.executeConfigured(ctx, session, prompt, settings, merged.Temperature, func(response AnswerResponse) error {
+		return validateCitations(prompt, settings, response)
+	})
+}
+
+// checkDocuments rejects input without a question or documents, or whose
+// documents share an ID.
+func checkDocuments(input AnswerInput) error {
+	if input.Question == "" {
+		return fmt.Errorf("%w: answer synapse needs a question", ErrInvalidPrompt)
+	}
+	if len(input.Documents) == 0 {
+		return fmt.Errorf("%w: answer synapse needs documents", ErrInvalidPrompt)
+	}
+	seen := make(map[string]bool, len(input.Documents))
+	for _, doc := range input.Documents {
+		if seen[doc.ID] {
+			return fmt.Errorf("%w: duplicate document ID %q", ErrInvalidPrompt, doc.ID)
+		}
+		seen[doc.ID] = true
+	}
+	return nil
+}
+
+// mergeInputs combines defaults with user input. Documents without an ID
+// are given their position, so citations of them can be checked.
+func (a *AnswerSynapse) mergeInputs(input AnswerInput) AnswerInput {
+	merged := a.defaults
+
+	if input.Question != "" {
+		merged.Question = input.Question
+	}
+	if len(input.Documents) > 0 {
+		merged.Documents = input.Documents
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	documents := make([]Document, len(merged.Documents))
+	for i, doc := range merged.Documents {
+		if doc.ID == "" {
+			doc.ID = fmt.Sprintf("doc-%d", i+1)
+		}
+		documents[i] = doc
+	}
+	merged.Documents = documents
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (a *AnswerSynapse) buildPrompt(input AnswerInput) *Prompt {
+	return &Prompt{
+		Task:      fmt.Sprintf("Answer the question about %s using only the documents", a.topic),
+		Input:     input.Question,
+		Context:   input.Context,
+		Documents: input.Documents,
+		Constraints: []string{
+			"answer: from the documents only, not from prior knowledge",
+			"citations: every document the answer relies on, by its ID in brackets, with the supporting text quoted exactly",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the answer",
+		},
+	}
+}
+
+// Answer creates a new answer synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	support, err := Answer("our refund policy", provider, WithAllowUnknown())
+//	response, err := support.FireWithInput(ctx, session, AnswerInput{
+//	    Question:  "Can I return opened items?",
+//	    Documents: []Document{{ID: "policy", Text: policyText}},
+//	})
+func Answer(topic string, provider Provider, opts ...Option) (*AnswerSynapse, error) {
+	return NewAnswer(topic, provider, opts...)
+}
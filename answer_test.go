@@ -0,0 +1,237 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// refundDocuments are the sources of the answer tests.
+var refundDocuments = []Document{
+	{ID: "policy", Text: "Unopened items can be returned within 30 days."},
+	{ID: "faq", Text: "Opened items are exchanged, not refunded."},
+}
+
+func TestAnswerResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     "Within 30 days.",
+			Citations:  []Citation{{DocID: "policy", Quote: "within 30 days"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"the policy says so"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("blank_answer", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     " ",
+			Citations:  []Citation{{DocID: "policy", Quote: "within 30 days"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"the policy says so"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank answer")
+		}
+	})
+
+	t.Run("citation_without_doc_id", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     "Within 30 days.",
+			Citations:  []Citation{{Quote: "within 30 days"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"the policy says so"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a citation without a document ID")
+		}
+	})
+
+	t.Run("citation_without_quote", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     "Within 30 days.",
+			Citations:  []Citation{{DocID: "policy"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"the policy says so"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a citation without a quote")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     "Within 30 days.",
+			Citations:  []Citation{{DocID: "policy", Quote: "within 30 days"}},
+			Confidence: 1.2,
+			Reasoning:  []string{"the policy says so"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := AnswerResponse{
+			Answer:     "Within 30 days.",
+			Citations:  []Citation{{DocID: "policy", Quote: "within 30 days"}},
+			Confidence: 0.9,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestAnswerSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"answer": "Opened items are exchanged.", "citations": [{"doc_id": "faq", "quote": "Opened items are exchanged"}], "confidence": 0.9, "reasoning": ["the FAQ covers opened items"]}`, nil
+	})
+	synapse, err := Answer("the refund policy", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	answer, err := synapse.Fire(context.Background(), NewSession(), "Can I return opened items?", refundDocuments)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer != "Opened items are exchanged." {
+		t.Errorf("unexpected answer: %q", answer)
+	}
+	for _, want := range []string{"the refund policy", "Input: Can I return opened items?", "[policy]", "[faq]\n    Opened items are exchanged, not refunded."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, AnswerUnknown) {
+		t.Errorf("expected no unknown answers offered, got %s", prompt)
+	}
+}
+
+func TestAnswerSynapse_DefaultIDs(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"answer": "30 days.", "citations": [{"doc_id": "doc-2", "quote": "30 days"}], "confidence": 0.8, "reasoning": ["x"]}`, nil
+	})
+	synapse, _ := Answer("the refund policy", provider)
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "How long do I have?",
+		[]Document{{Text: "Shipping is free."}, {Text: "Returns are accepted for 30 days."}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value != "30 days." || !strings.Contains(prompt, "[doc-1]") || !strings.Contains(prompt, "[doc-2]") {
+		t.Errorf("expected positional IDs, got %q and %s", result.Value, prompt)
+	}
+}
+
+func TestAnswerSynapse_Citations(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"unknown document", `{"answer": "Yes.", "citations": [{"doc_id": "handbook", "quote": "yes"}], "confidence": 0.9, "reasoning": ["x"]}`},
+		{"no citations", `{"answer": "Yes.", "citations": [], "confidence": 0.9, "reasoning": ["x"]}`},
+		{"empty quote", `{"answer": "Yes.", "citations": [{"doc_id": "faq", "quote": ""}], "confidence": 0.9, "reasoning": ["x"]}`},
+		{"unknown not allowed", `{"answer": "I don't know", "citations": [], "confidence": 0.2, "reasoning": ["x"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, _ := Answer("the refund policy", NewMockProviderWithResponse(tt.response))
+			session := NewSession()
+			if _, err := synapse.Fire(context.Background(), session, "Can I return it?", refundDocuments); !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("expected ErrInvalidResponse, got %v", err)
+			}
+			if session.Len() != 0 {
+				t.Errorf("expected the session untouched, got %d messages", session.Len())
+			}
+		})
+	}
+}
+
+func TestWithAllowUnknown(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"answer": "I don't know.", "citations": [], "confidence": 0.3, "reasoning": ["no document covers shipping"]}`, nil
+	})
+	synapse, _ := Answer("the refund policy", provider, WithAllowUnknown())
+
+	response, err := synapse.FireWithInput(context.Background(), NewSession(), AnswerInput{
+		Question:  "How much is shipping?",
+		Documents: refundDocuments,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.Unknown() {
+		t.Errorf("expected an unknown answer, got %+v", response)
+	}
+	if !strings.Contains(prompt, answerUnknownConstraint) {
+		t.Errorf("expected unknown answers offered, got %s", prompt)
+	}
+
+	// Other uncited answers are still rejected
+	uncited, _ := Answer("the refund policy", NewMockProviderWithResponse(`{"answer": "Shipping is free.", "citations": [], "confidence": 0.9, "reasoning": ["x"]}`), WithAllowUnknown())
+	if _, err := uncited.Fire(context.Background(), NewSession(), "How much is shipping?", refundDocuments); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+
+	// Other synapse types are unaffected
+	var binaryPrompt string
+	binary, _ := Binary("Is this valid?", NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		binaryPrompt = p
+		return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+	}), WithAllowUnknown())
+	if _, err := binary.Fire(context.Background(), NewSession(), "input"); err != nil || strings.Contains(binaryPrompt, AnswerUnknown) {
+		t.Errorf("expected the binary prompt unchanged, got %v and %s", err, binaryPrompt)
+	}
+}
+
+func TestAnswerSynapse_InvalidInput(t *testing.T) {
+	var calls int
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		return `{}`, nil
+	})
+	synapse, _ := Answer("the refund policy", provider)
+
+	inputs := map[string]AnswerInput{
+		"no question":   {Documents: refundDocuments},
+		"no documents":  {Question: "Can I return it?"},
+		"duplicate IDs": {Question: "Can I return it?", Documents: []Document{{ID: "a", Text: "x"}, {ID: "a", Text: "y"}}},
+		"clashing IDs":  {Question: "Can I return it?", Documents: []Document{{ID: "doc-2", Text: "x"}, {Text: "y"}}},
+	}
+	for name, input := range inputs {
+		if _, err := synapse.FireWithInput(context.Background(), NewSession(), input); !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("%s: expected ErrInvalidPrompt, got %v", name, err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("expected no provider calls, got %d", calls)
+	}
+}
+
+func TestAnswerSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"answer": "30 days.", "citations": [{"doc_id": "doc-1", "quote": "30 days"}], "confidence": 0.8, "reasoning": ["x"]}`)
+	synapse, _ := Answer("the refund policy", provider)
+
+	result, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{
+		Input: "How long do I have?",
+		Items: []string{"Returns are accepted for 30 days."},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := result.(AnswerResponse); !ok || response.Answer != "30 days." {
+		t.Errorf("expected an AnswerResponse, got %+v", result)
+	}
+}
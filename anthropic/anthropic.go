@@ -3,11 +3,13 @@ package anthropic
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/capitan"
@@ -22,6 +24,10 @@ type Provider struct {
 	maxTokens  int
 	httpClient *http.Client
 	name       string
+	vision     bool
+	httpConfig zyn.HTTPConfig
+	httpErr    error       // Invalid HTTPConfig, reported by every call
+	closed     atomic.Bool // Set by Close
 }
 
 // Config holds configuration for the Anthropic provider.
@@ -30,10 +36,15 @@ type Config struct {
 	Model     string        // e.g. "claude-sonnet-4-20250514", "claude-3-5-haiku-20241022"
 	BaseURL   string        // Optional, defaults to "https://api.anthropic.com"
 	MaxTokens int           // Optional, defaults to 4096
-	Timeout   time.Duration // Optional, defaults to 30s
+	Timeout   time.Duration // Optional, defaults to 30s; unused with HTTPConfig.HTTPClient
+	Vision    bool          // Optional, set when the model accepts image inputs
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
 }
 
 // New creates a new Anthropic provider.
+// If config.Headers would override a credential header, every call fails
+// with the error from config.HTTPConfig.Validate.
 func New(config Config) *Provider {
 	if config.Model == "" {
 		config.Model = "claude-sonnet-4-20250514"
@@ -49,14 +60,15 @@ func New(config Config) *Provider {
 	}
 
 	return &Provider{
-		apiKey:    config.APIKey,
-		model:     config.Model,
-		baseURL:   config.BaseURL,
-		maxTokens: config.MaxTokens,
-		name:      "anthropic",
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		apiKey:     config.APIKey,
+		model:      config.Model,
+		baseURL:    config.BaseURL,
+		maxTokens:  config.MaxTokens,
+		name:       "anthropic",
+		vision:     config.Vision,
+		httpConfig: config.HTTPConfig,
+		httpErr:    config.HTTPConfig.Validate(),
+		httpClient: config.HTTPConfig.Client(config.Timeout),
 	}
 }
 
@@ -65,15 +77,37 @@ func (p *Provider) Name() string {
 	return p.name
 }
 
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
 // Call sends messages to Anthropic and returns the response with usage stats.
 func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
 	startTime := time.Now()
 
 	// Emit provider.call.started hook
-	capitan.Info(ctx, zyn.ProviderCallStarted,
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
 		zyn.ProviderKey.Field(p.name),
 		zyn.ModelKey.Field(p.model),
-	)
+	)...)
 
 	// Extract system messages and conversation messages
 	var systemParts []string
@@ -82,10 +116,7 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		if msg.Role == zyn.RoleSystem {
 			systemParts = append(systemParts, msg.Content)
 		} else {
-			apiMessages = append(apiMessages, message{
-				Role:    msg.Role,
-				Content: msg.Content,
-			})
+			apiMessages = append(apiMessages, newMessage(msg))
 		}
 	}
 
@@ -113,6 +144,7 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	p.httpConfig.Apply(req)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", p.apiKey)
 	req.Header.Set("anthropic-version", "2023-06-01")
@@ -149,17 +181,20 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 				zyn.APIErrorTypeKey.Field(errorResp.Error.Type),
 			)
 
-			capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+			capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
 
 			// Check for rate limit
 			if resp.StatusCode == http.StatusTooManyRequests {
-				return nil, fmt.Errorf("rate limit exceeded: %s", errorResp.Error.Message)
+				return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, errorResp.Error.Message)
 			}
 			return nil, fmt.Errorf("anthropic error (%d): %s", resp.StatusCode, errorResp.Error.Message)
 		}
 
 		fields = append(fields, zyn.ErrorKey.Field(fmt.Sprintf("status %d", resp.StatusCode)))
-		capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: status %d", zyn.ErrRateLimited, resp.StatusCode)
+		}
 		return nil, fmt.Errorf("anthropic error: status %d", resp.StatusCode)
 	}
 
@@ -201,10 +236,11 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		fields = append(fields, zyn.ResponseFinishReasonKey.Field(messagesResp.StopReason))
 	}
 
-	capitan.Info(ctx, zyn.ProviderCallCompleted, fields...)
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
 
 	return &zyn.ProviderResponse{
-		Content: content,
+		Content:   content,
+		Truncated: messagesResp.StopReason == "max_tokens",
 		Usage: zyn.TokenUsage{
 			Prompt:     messagesResp.Usage.InputTokens,
 			Completion: messagesResp.Usage.OutputTokens,
@@ -223,9 +259,34 @@ type messagesRequest struct {
 	System      string    `json:"system,omitempty"`
 }
 
+// message is a conversation message. Content is a string, or a list of
+// content blocks when images are attached.
 type message struct {
 	Role    string `json:"role"`
-	Content string `json:"content"`
+	Content any    `json:"content"`
+}
+
+// newMessage converts a zyn.Message, sending attached images as image
+// blocks ahead of the text as the API recommends.
+func newMessage(msg zyn.Message) message {
+	if len(msg.Images) == 0 {
+		return message{Role: msg.Role, Content: msg.Content}
+	}
+
+	blocks := make([]contentBlock, 0, len(msg.Images)+1)
+	for _, image := range msg.Images {
+		source := &imageSource{Type: "url", URL: image.URL}
+		if image.URL == "" {
+			source = &imageSource{
+				Type:      "base64",
+				MediaType: image.MIME,
+				Data:      base64.StdEncoding.EncodeToString(image.Data),
+			}
+		}
+		blocks = append(blocks, contentBlock{Type: "image", Source: source})
+	}
+	blocks = append(blocks, contentBlock{Type: "text", Text: msg.Content})
+	return message{Role: msg.Role, Content: blocks}
 }
 
 type messagesResponse struct {
@@ -239,8 +300,16 @@ type messagesResponse struct {
 }
 
 type contentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text,omitempty"`
+	Type   string       `json:"type"`
+	Text   string       `json:"text,omitempty"`
+	Source *imageSource `json:"source,omitempty"`
+}
+
+type imageSource struct {
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 type usage struct {
@@ -3,10 +3,12 @@ package anthropic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/zoobzio/zyn"
@@ -310,3 +312,195 @@ func TestNoSystemMessage(t *testing.T) {
 		t.Fatalf("Call failed: %v", err)
 	}
 }
+
+func TestProviderCapabilities(t *testing.T) {
+	if New(Config{APIKey: "test-key"}).Capabilities().Vision {
+		t.Error("Expected vision to be off by default")
+	}
+	if !New(Config{APIKey: "test-key", Vision: true}).Capabilities().Vision {
+		t.Error("Expected vision when configured")
+	}
+	if model := New(Config{APIKey: "test-key", Model: "claude-3-5-haiku-20241022"}).Capabilities().Model; model != "claude-3-5-haiku-20241022" {
+		t.Errorf("Expected configured model, got %q", model)
+	}
+}
+
+func TestImageAttachments(t *testing.T) {
+	var body struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "{}"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL, Vision: true})
+	_, err := provider.Call(context.Background(), []zyn.Message{
+		{Role: zyn.RoleUser, Content: "describe", Images: []zyn.ImageInput{
+			{URL: "https://example.com/a.png"},
+			{Data: []byte("hi"), MIME: "image/png"},
+		}},
+	}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	var blocks []contentBlock
+	if err := json.Unmarshal(body.Messages[0].Content, &blocks); err != nil {
+		t.Fatalf("Expected content blocks, got %s", body.Messages[0].Content)
+	}
+	if len(blocks) != 3 || blocks[2].Type != "text" || blocks[2].Text != "describe" {
+		t.Fatalf("Unexpected content blocks: %+v", blocks)
+	}
+	if blocks[0].Type != "image" || blocks[0].Source.Type != "url" || blocks[0].Source.URL != "https://example.com/a.png" {
+		t.Errorf("Unexpected URL image block: %+v", blocks[0].Source)
+	}
+	if source := blocks[1].Source; source == nil || source.Type != "base64" || source.MediaType != "image/png" || source.Data != "aGk=" {
+		t.Errorf("Unexpected data image block: %+v", source)
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	bodies := map[string]string{
+		"with error body": `{"error": {"message": "Too many requests", "type": "rate_limit_error"}}`,
+		"without body":    ``,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+
+			_, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if !errors.Is(err, zyn.ErrRateLimited) {
+				t.Errorf("Expected ErrRateLimited, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}], "stop_reason": "end_turn"}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	t.Run("default user agent", func(t *testing.T) {
+		provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != zyn.DefaultUserAgent() {
+			t.Errorf("Expected default User-Agent, got %q", got.Get("User-Agent"))
+		}
+	})
+
+	t.Run("custom headers", func(t *testing.T) {
+		provider := New(Config{
+			APIKey:  "test-key",
+			BaseURL: server.URL,
+			HTTPConfig: zyn.HTTPConfig{
+				UserAgent: "billing/2.3",
+				Headers:   map[string]string{"X-Team": "billing"},
+			},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != "billing/2.3" || got.Get("X-Team") != "billing" {
+			t.Errorf("Expected custom headers, got %v", got)
+		}
+	})
+
+	t.Run("rejects credential override", func(t *testing.T) {
+		got = nil
+		provider := New(Config{
+			APIKey:     "test-key",
+			BaseURL:    server.URL,
+			HTTPConfig: zyn.HTTPConfig{Headers: map[string]string{"X-Api-Key": "stolen"}},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err == nil || !strings.Contains(err.Error(), "credentials") {
+			t.Errorf("Expected credential header rejected, got %v", err)
+		}
+		if got != nil {
+			t.Error("Expected no request sent")
+		}
+	})
+}
+
+func TestTruncatedResponse(t *testing.T) {
+	bodies := map[string]string{
+		"truncated": `{"content": [{"type": "text", "text": "{\"decision\": tr"}], "stop_reason": "max_tokens"}`,
+		"complete":  `{"content": [{"type": "text", "text": "{}"}], "stop_reason": "end_turn"}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+			response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if response.Truncated != (name == "truncated") {
+				t.Errorf("Expected truncated %t, got %t", name == "truncated", response.Truncated)
+			}
+		})
+	}
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}], "stop_reason": "end_turn"}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
@@ -25,7 +25,11 @@
 //	fmt.Println(result.Decision, result.Confidence)
 package zyn
 
-import "context"
+import (
+	"context"
+	"math"
+	"time"
+)
 
 // Provider defines the interface for LLM providers.
 // Providers accept conversation messages and return responses with usage stats.
@@ -40,6 +44,21 @@ type Provider interface {
 	Name() string
 }
 
+// Capabilities describes the features and limits a provider advertises.
+// Zero values mean unknown or unsupported.
+type Capabilities struct {
+	MaxContextTokens int    // Maximum tokens the model accepts in a single request
+	Vision           bool   // Whether the model accepts images attached to messages
+	Model            string // Model the provider calls, used to price requests
+}
+
+// CapabilitiesProvider is an optional interface for providers that advertise
+// their capabilities. Synapses use it for preflight checks, such as rejecting
+// prompts larger than the context window before calling the provider.
+type CapabilitiesProvider interface {
+	Capabilities() Capabilities
+}
+
 // Validator defines the interface for response validation.
 // All response types must implement this to ensure LLM outputs are valid.
 type Validator interface {
@@ -51,19 +70,70 @@ type TokenUsage struct {
 	Prompt     int // Tokens used by the prompt/messages
 	Completion int // Tokens used by the completion/response
 	Total      int // Total tokens used
+	// CachedPrompt counts the prompt tokens the provider served from its
+	// prompt cache, already included in Prompt. Zero when the provider does
+	// not report it.
+	CachedPrompt int
+	// Reasoning counts the completion tokens a reasoning model spent
+	// thinking before it answered, already included in Completion. Zero when
+	// the provider does not report it.
+	Reasoning int
+}
+
+// CallParams carries request parameters beyond the Provider interface's
+// arguments. Synapses set them on the context of every provider call, and
+// providers read them with CallParamsFromContext; providers that ignore them
+// still produce valid responses.
+type CallParams struct {
+	// ResponseSchema is the JSON schema the response must follow, set when
+	// the prompt asks for a JSON response. Providers with structured-output
+	// support can enforce it, e.g. after converting it with StrictSchema.
+	ResponseSchema string
+
+	// Seed asks for deterministic sampling with the given seed, set by
+	// WithSeed; nil leaves sampling to the provider.
+	Seed *int
+
+	MaxTokens int      // Completion token limit, set by WithMaxTokens; 0 when unset
+	TopP      float32  // Nucleus sampling probability mass, set by WithTopP; 0 when unset
+	Stop      []string // Sequences that end the completion, set by WithStopSequences
 }
 
 // ProviderResponse contains the response from an LLM provider.
 type ProviderResponse struct {
-	Content string     // The text response content
-	Usage   TokenUsage // Token usage statistics
+	Content   string     // The text response content
+	Usage     TokenUsage // Token usage statistics
+	ToolCalls []ToolCall // Tools the model called, for providers implementing ToolProvider
+	Truncated bool       // Whether the response stopped at the completion token limit
+	Cached    bool       // Whether the response was served by a CachingProvider without calling the model
+
+	// Logprobs holds the log probability of each generated token, for
+	// providers configured to return them; nil otherwise.
+	Logprobs []TokenLogprob
+}
+
+// TokenLogprob is the log probability of a generated token. Comparing the
+// probabilities of the tokens that carry an answer with the confidence the
+// model reports is a way to calibrate it.
+type TokenLogprob struct {
+	Token       string         // The generated token
+	Logprob     float64        // Natural log of the token's probability
+	TopLogprobs []TokenLogprob // Most likely tokens at this position, when requested
+}
+
+// Probability returns the token's probability, between 0 and 1.
+func (t TokenLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
 }
 
 // Message represents a single message in a conversation.
 // Messages are exchanged between the user and the assistant (LLM).
 type Message struct {
-	Role    string // RoleUser, RoleAssistant, or RoleSystem
-	Content string // The message content
+	Role       string       // RoleUser, RoleAssistant, RoleSystem, or RoleTool
+	Content    string       // The message content
+	Images     []ImageInput // Images attached to the message, for providers with vision support
+	ToolCalls  []ToolCall   // Tools called by an assistant message
+	ToolCallID string       // ID of the call a RoleTool message answers
 }
 
 // Role constants for message types.
@@ -71,6 +141,7 @@ const (
 	RoleUser      = "user"
 	RoleAssistant = "assistant"
 	RoleSystem    = "system"
+	RoleTool      = "tool"
 )
 
 // Default temperature constants for different synapse types.
@@ -104,20 +175,40 @@ const (
 // It contains the prompt, parameters, session, and response data.
 type SynapseRequest struct {
 	// Input fields
-	Prompt      *Prompt // The structured prompt to send to LLM
-	Temperature float32 // Temperature parameter for response generation
+	Prompt      *Prompt  // The structured prompt to send to LLM
+	Temperature float32  // Temperature parameter for response generation
+	Tools       []Tool   // Tools offered to the model, set by WithTools
+	Seed        *int     // Sampling seed, set by WithSeed; nil when unset
+	MaxTokens   int      // Completion token limit, set by WithMaxTokens; 0 when unset
+	TopP        float32  // Nucleus sampling probability mass, set by WithTopP; 0 when unset
+	Stop        []string // Sequences that end the completion, set by WithStopSequences
 
 	// Session fields
 	SessionID string    // ID of the conversation session
 	Messages  []Message // Message history from session
 
 	// Metadata fields
-	RequestID    string // Unique identifier for this request
-	SynapseType  string // Type of synapse (binary, extraction, etc.)
-	ProviderName string // Name of the provider being used
+	RequestID       string // Unique identifier for this request
+	ParentRequestID string // Caller's request ID from ContextWithRequestID; empty when none was set
+	SynapseType     string // Type of synapse (binary, extraction, etc.)
+	ProviderName    string // Name of the provider being used
+
+	// Limit fields (set by options)
+	MaxPromptTokens    int // Hard limit on estimated prompt tokens; 0 uses the provider's advertised limit
+	PromptTokenWarning int // Estimated prompt tokens above which PromptSizeWarning is emitted; 0 uses 80% of the limit
+
+	MinRemainingDeadline time.Duration // Time the context's deadline must leave for a provider call; 0 disables the check
 
 	// Output fields (populated by pipeline)
-	Response string      // Raw text response from provider
-	Usage    *TokenUsage // Token usage from provider response
-	Error    error       // Any error that occurred during processing
+	Response  string         // Raw text response from provider
+	Usage     *TokenUsage    // Token usage from provider response
+	ToolCalls []ToolCall     // Tools the model called instead of answering
+	Logprobs  []TokenLogprob // Log probabilities of the response tokens, when the provider returns them
+	Error     error          // Any error that occurred during processing
+
+	EstimatedPromptTokens int // Estimated prompt tokens, set when a limit or warning threshold applies
+	Attempts              int // Provider calls made for this request, including retries and fallbacks
+
+	validate func(response string) error // Parses and validates a response as the service will, for WithValidationRetry
+	settings any                         // The synapse's settings for this call, configured by its options
 }
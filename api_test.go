@@ -92,6 +92,20 @@ func TestTokenUsage(t *testing.T) {
 	})
 }
 
+func TestTokenLogprob_Probability(t *testing.T) {
+	t.Run("certain", func(t *testing.T) {
+		if p := (TokenLogprob{Logprob: 0}).Probability(); p != 1 {
+			t.Errorf("expected probability 1, got %f", p)
+		}
+	})
+
+	t.Run("likely", func(t *testing.T) {
+		if p := (TokenLogprob{Logprob: -0.1053605}).Probability(); p < 0.899 || p > 0.901 {
+			t.Errorf("expected probability 0.9, got %f", p)
+		}
+	})
+}
+
 func TestMessage(t *testing.T) {
 	t.Run("user_message", func(t *testing.T) {
 		msg := Message{Role: RoleUser, Content: "hello"}
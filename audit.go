@@ -0,0 +1,286 @@
+package zyn
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/pipz"
+)
+
+// auditID identifies the audit log stage.
+var auditID = pipz.NewIdentity("zyn:audit-log", "Writes an audit record of every request")
+
+// Audit outcomes recorded in AuditRecord.Outcome.
+const (
+	AuditSuccess         = "success"          // Response parsed and validated
+	AuditParseFailed     = "parse_failed"     // Response could not be parsed
+	AuditInvalidResponse = "invalid_response" // Response parsed but failed validation
+	AuditToolCalls       = "tool_calls"       // Model called tools instead of answering
+	AuditError           = "error"            // Request failed before a response was parsed
+)
+
+// redactedText replaces matches of AuditConfig.RedactPatterns.
+const redactedText = "[REDACTED]"
+
+// AuditConfig configures an audit log.
+type AuditConfig struct {
+	// RedactPatterns are replaced by "[REDACTED]" in the prompt, response,
+	// session, and errors of each record. Hashes are not affected.
+	RedactPatterns []*regexp.Regexp
+
+	// IncludeSession adds the session messages sent before the prompt.
+	IncludeSession bool
+
+	// HashOnly records SHA-256 hashes of the prompt, response, and session
+	// instead of their text. The hashes are of the unredacted text, so a
+	// record can be matched against a payload kept elsewhere.
+	HashOnly bool
+}
+
+// AuditRecord is one line of an audit log, written as JSON.
+type AuditRecord struct {
+	RequestID       string         `json:"request_id"`
+	ParentRequestID string         `json:"parent_request_id,omitempty"`
+	Timestamp       time.Time      `json:"timestamp"`
+	SynapseType     string         `json:"synapse_type"`
+	Task            string         `json:"task"`
+	Provider        string         `json:"provider"`
+	Attempts        int            `json:"attempts"`
+	Prompt          string         `json:"prompt,omitempty"`
+	PromptHash      string         `json:"prompt_sha256,omitempty"`
+	Session         []AuditMessage `json:"session,omitempty"`
+	SessionHash     string         `json:"session_sha256,omitempty"`
+	Response        string         `json:"response,omitempty"`
+	ResponseHash    string         `json:"response_sha256,omitempty"`
+	Usage           *AuditUsage    `json:"usage,omitempty"`
+	Outcome         string         `json:"outcome"`
+	Error           string         `json:"error,omitempty"`
+	AttemptErrors   []string       `json:"attempt_errors,omitempty"` // Errors from calls through the audit stage, in order
+	Rescued         bool           `json:"rescued,omitempty"`        // The request succeeded after a failed call
+}
+
+// AuditMessage is a session message in an AuditRecord.
+type AuditMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// AuditUsage is the token usage in an AuditRecord.
+type AuditUsage struct {
+	Prompt     int `json:"prompt_tokens"`
+	Completion int `json:"completion_tokens"`
+	Total      int `json:"total_tokens"`
+}
+
+// WithAuditLog writes an append-only record of every request to w, one JSON
+// line per Fire: the rendered prompt, the raw response, token usage, attempts,
+// and the outcome, including responses that failed to parse or validate.
+//
+// A record is written before Fire returns. Writes are serialized, so one
+// writer can be shared by synapses on many goroutines, and each record is
+// passed to w in a single Write. A failed write does not fail the request;
+// it emits an AuditWriteFailed hook event.
+//
+// List WithAuditLog before WithRetry and WithFallback to record the errors
+// they rescue in AttemptErrors; listed after them, only the final outcome is
+// seen. Calls made by EstimateCost are not recorded.
+//
+// Example:
+//
+//	file, _ := os.OpenFile("audit.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+//	synapse, _ := zyn.Binary("Is this spam?", provider,
+//	    zyn.WithAuditLog(file, zyn.AuditConfig{
+//	        RedactPatterns: []*regexp.Regexp{regexp.MustCompile(`\b\d{16}\b`)},
+//	    }),
+//	    zyn.WithRetry(3))
+func WithAuditLog(w io.Writer, cfg AuditConfig) Option {
+	log := &auditLog{w: w, cfg: cfg}
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		return pipz.Apply(auditID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			trail := auditTrailFor(ctx, log)
+			processed, err := pipeline.Process(ctx, req)
+			if err != nil && trail != nil {
+				trail.fail(err)
+			}
+			return processed, err
+		})
+	}
+}
+
+// auditLog is the destination of one WithAuditLog option.
+type auditLog struct {
+	mu  sync.Mutex // Serializes writes to w
+	w   io.Writer
+	cfg AuditConfig
+}
+
+// write appends record to the log as one line.
+func (l *auditLog) write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(line)
+	return err
+}
+
+// redact replaces the configured patterns in text.
+func (l *auditLog) redact(text string) string {
+	for _, pattern := range l.cfg.RedactPatterns {
+		text = pattern.ReplaceAllString(text, redactedText)
+	}
+	return text
+}
+
+// record builds the audit record of a finished request.
+func (l *auditLog) record(req *SynapseRequest, err error, errs []string) AuditRecord {
+	record := AuditRecord{
+		RequestID:       req.RequestID,
+		ParentRequestID: req.ParentRequestID,
+		Timestamp:       time.Now().UTC(),
+		SynapseType:     req.SynapseType,
+		Task:            l.redact(req.Prompt.Task),
+		Provider:        req.ProviderName,
+		Attempts:        req.Attempts,
+		Outcome:         auditOutcome(err),
+	}
+	for _, e := range errs {
+		record.AttemptErrors = append(record.AttemptErrors, l.redact(e))
+	}
+	record.Rescued = err == nil && len(errs) > 0
+	if err != nil {
+		record.Error = l.redact(err.Error())
+	}
+	if req.Usage != nil {
+		record.Usage = &AuditUsage{Prompt: req.Usage.Prompt, Completion: req.Usage.Completion, Total: req.Usage.Total}
+	}
+
+	prompt := req.Prompt.Render()
+	if l.cfg.HashOnly {
+		record.PromptHash = sha256Hex(prompt)
+		if req.Response != "" {
+			record.ResponseHash = sha256Hex(req.Response)
+		}
+	} else {
+		record.Prompt = l.redact(prompt)
+		record.Response = l.redact(req.Response)
+	}
+
+	if l.cfg.IncludeSession && len(req.Messages) > 0 {
+		session := make([]AuditMessage, len(req.Messages))
+		for i, msg := range req.Messages {
+			session[i] = AuditMessage{Role: msg.Role, Content: msg.Content}
+		}
+		if l.cfg.HashOnly {
+			data, _ := json.Marshal(session) //nolint:errcheck // strings always marshal
+			record.SessionHash = sha256Hex(string(data))
+		} else {
+			for i := range session {
+				session[i].Content = l.redact(session[i].Content)
+			}
+			record.Session = session
+		}
+	}
+	return record
+}
+
+// auditOutcome classifies a request's final error.
+func auditOutcome(err error) string {
+	switch {
+	case err == nil:
+		return AuditSuccess
+	case errors.Is(err, ErrParseFailed):
+		return AuditParseFailed
+	case errors.Is(err, ErrInvalidResponse):
+		return AuditInvalidResponse
+	case errors.Is(err, ErrToolCalls):
+		return AuditToolCalls
+	default:
+		return AuditError
+	}
+}
+
+// sha256Hex returns the hex-encoded SHA-256 hash of text.
+func sha256Hex(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// auditTrail collects the errors of one request's calls through one audit log.
+type auditTrail struct {
+	log  *auditLog
+	errs []string
+}
+
+// fail records an error returned through the audit stage.
+func (t *auditTrail) fail(err error) {
+	t.log.mu.Lock()
+	defer t.log.mu.Unlock()
+	t.errs = append(t.errs, err.Error())
+}
+
+// requestAudit holds the audit trails of one request, one per audit log its
+// calls pass through. The service writes them once the request has finished.
+type requestAudit struct {
+	mu     sync.Mutex
+	trails []*auditTrail
+}
+
+// withRequestAudit returns a context whose audit stages record to a new
+// requestAudit.
+func withRequestAudit(ctx context.Context) (context.Context, *requestAudit) {
+	audit := &requestAudit{}
+	return context.WithValue(ctx, auditContextKey, audit), audit
+}
+
+// auditTrailFor returns the request's trail for log, creating it on the
+// request's first call through the log, or nil outside a request or during
+// a preview.
+func auditTrailFor(ctx context.Context, log *auditLog) *auditTrail {
+	audit, ok := ctx.Value(auditContextKey).(*requestAudit)
+	if !ok || previewing(ctx) {
+		return nil
+	}
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	if i := slices.IndexFunc(audit.trails, func(t *auditTrail) bool { return t.log == log }); i >= 0 {
+		return audit.trails[i]
+	}
+	trail := &auditTrail{log: log}
+	audit.trails = append(audit.trails, trail)
+	return trail
+}
+
+// write appends the finished request to every audit log it passed through.
+func (a *requestAudit) write(ctx context.Context, req *SynapseRequest, err error) {
+	a.mu.Lock()
+	trails := slices.Clone(a.trails)
+	a.mu.Unlock()
+
+	for _, trail := range trails {
+		trail.log.mu.Lock()
+		errs := slices.Clone(trail.errs)
+		trail.log.mu.Unlock()
+
+		if writeErr := trail.log.write(trail.log.record(req, err, errs)); writeErr != nil {
+			capitan.Error(ctx, AuditWriteFailed, HookFields(ctx,
+				RequestIDKey.Field(req.RequestID),
+				SynapseTypeKey.Field(req.SynapseType),
+				ErrorKey.Field(writeErr.Error()),
+			)...)
+		}
+	}
+}
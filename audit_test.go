@@ -0,0 +1,305 @@
+package zyn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// auditBuffer is a writer safe for concurrent use that counts its writes.
+type auditBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+}
+
+func (b *auditBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes++
+	return b.buf.Write(p)
+}
+
+// records parses the buffer's JSON lines.
+func (b *auditBuffer) records(t *testing.T) []AuditRecord {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var records []AuditRecord
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWithAuditLog(t *testing.T) {
+	t.Run("records a successful request", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Binary("Is this valid?", NewMockProvider(), WithAuditLog(log, AuditConfig{}))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), "test@example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records := log.records(t)
+		if len(records) != 1 {
+			t.Fatalf("expected one record, got %d", len(records))
+		}
+		record := records[0]
+		if record.RequestID != result.RequestID || record.SynapseType != "binary" || record.Provider != "mock" || record.Attempts != 1 {
+			t.Errorf("unexpected metadata: %+v", record)
+		}
+		if record.Outcome != AuditSuccess || record.Error != "" || record.Rescued {
+			t.Errorf("unexpected outcome: %+v", record)
+		}
+		if !strings.Contains(record.Prompt, "test@example.com") || !strings.Contains(record.Response, `"decision"`) {
+			t.Errorf("expected prompt and response text, got %+v", record)
+		}
+		if record.Usage == nil || record.Usage.Total != 150 || record.Timestamp.IsZero() {
+			t.Errorf("unexpected usage or timestamp: %+v", record)
+		}
+	})
+
+	t.Run("records the caller's request ID as parent", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Binary("Is this valid?", NewMockProvider(), WithAuditLog(log, AuditConfig{}))
+
+		ctx := ContextWithRequestID(context.Background(), "req-caller")
+		result, err := synapse.FireResult(ctx, NewSession(), "test@example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		record := log.records(t)[0]
+		if record.RequestID != result.RequestID || record.ParentRequestID != "req-caller" {
+			t.Errorf("expected request ID %q with parent req-caller, got %+v", result.RequestID, record)
+		}
+	})
+
+	t.Run("records a parse failure", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Binary("Is this valid?", NewMockProviderWithResponse("not json"), WithAuditLog(log, AuditConfig{}))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); !errors.Is(err, ErrParseFailed) {
+			t.Fatalf("expected parse failure, got %v", err)
+		}
+
+		records := log.records(t)
+		if len(records) != 1 {
+			t.Fatalf("expected one record, got %d", len(records))
+		}
+		if records[0].Outcome != AuditParseFailed || records[0].Response != "not json" || records[0].Error == "" {
+			t.Errorf("unexpected record: %+v", records[0])
+		}
+	})
+
+	t.Run("records errors rescued by fallback", func(t *testing.T) {
+		log := &auditBuffer{}
+		fallback, _ := Binary("Is this valid?", NewMockProviderWithName("backup"))
+		synapse, _ := Binary("Is this valid?", NewMockProviderWithError("service unavailable"),
+			WithAuditLog(log, AuditConfig{}), WithFallback(fallback))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records := log.records(t)
+		if len(records) != 1 {
+			t.Fatalf("expected one record, got %d", len(records))
+		}
+		record := records[0]
+		if record.Outcome != AuditSuccess || !record.Rescued || record.Attempts != 2 {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		if len(record.AttemptErrors) != 1 || !strings.Contains(record.AttemptErrors[0], "service unavailable") {
+			t.Errorf("expected the primary's error, got %v", record.AttemptErrors)
+		}
+	})
+
+	t.Run("records retries once", func(t *testing.T) {
+		log := &auditBuffer{}
+		calls := 0
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", fmt.Errorf("timeout %d", calls)
+			}
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithAuditLog(log, AuditConfig{}), WithRetry(3))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records := log.records(t)
+		if len(records) != 1 || len(records[0].AttemptErrors) != 2 || !records[0].Rescued || records[0].Attempts != 3 {
+			t.Fatalf("unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("records timeouts", func(t *testing.T) {
+		log := &auditBuffer{}
+		release := make(chan struct{})
+		defer close(release)
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			<-release
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithAuditLog(log, AuditConfig{}), WithTimeout(10*time.Millisecond))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), "input")
+		if err == nil {
+			t.Fatal("expected timeout")
+		}
+		if result.Attempts != 1 {
+			t.Errorf("expected one attempt, got %d", result.Attempts)
+		}
+
+		records := log.records(t)
+		if len(records) != 1 || records[0].Outcome != AuditError || records[0].Attempts != 1 || records[0].Response != "" {
+			t.Fatalf("unexpected records: %+v", records)
+		}
+	})
+
+	t.Run("redacts text", func(t *testing.T) {
+		log := &auditBuffer{}
+		card := regexp.MustCompile(`\b\d{16}\b`)
+		synapse, _ := Binary("Is this valid?", NewMockProvider(),
+			WithAuditLog(log, AuditConfig{RedactPatterns: []*regexp.Regexp{card}}))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "card 4111111111111111"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		record := log.records(t)[0]
+		if strings.Contains(record.Prompt, "4111111111111111") || !strings.Contains(record.Prompt, "card [REDACTED]") {
+			t.Errorf("expected redacted prompt, got %q", record.Prompt)
+		}
+	})
+
+	t.Run("hashes unredacted text", func(t *testing.T) {
+		log := &auditBuffer{}
+		card := regexp.MustCompile(`\b\d{16}\b`)
+		synapse, _ := Binary("Is this valid?", NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`),
+			WithAuditLog(log, AuditConfig{RedactPatterns: []*regexp.Regexp{card}, HashOnly: true}))
+
+		prompt := synapse.buildPrompt(BinaryInput{Subject: "card 4111111111111111"})
+		if _, err := synapse.Fire(context.Background(), NewSession(), "card 4111111111111111"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		record := log.records(t)[0]
+		if record.Prompt != "" || record.Response != "" {
+			t.Errorf("expected no text, got %+v", record)
+		}
+		if record.PromptHash != sha256Hex(prompt.Render()) {
+			t.Errorf("expected hash of the unredacted prompt, got %q", record.PromptHash)
+		}
+		if record.ResponseHash != sha256Hex(`{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`) {
+			t.Errorf("unexpected response hash %q", record.ResponseHash)
+		}
+	})
+
+	t.Run("includes session", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Binary("Is this valid?", NewMockProvider(), WithAuditLog(log, AuditConfig{IncludeSession: true}))
+		session := NewSession()
+
+		for _, input := range []string{"first", "second"} {
+			if _, err := synapse.Fire(context.Background(), session, input); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		records := log.records(t)
+		if len(records) != 2 || len(records[0].Session) != 0 || len(records[1].Session) != 2 {
+			t.Fatalf("unexpected sessions: %+v", records)
+		}
+		if records[1].Session[0].Role != RoleUser || !strings.Contains(records[1].Session[0].Content, "first") {
+			t.Errorf("unexpected session message: %+v", records[1].Session[0])
+		}
+	})
+
+	t.Run("serializes concurrent writes", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Sentiment("feedback", NewMockProvider(), WithAuditLog(log, AuditConfig{}))
+
+		inputs := make([]string, 20)
+		for i := range inputs {
+			inputs[i] = fmt.Sprintf("message %d", i)
+		}
+		if _, err := synapse.FireBatch(context.Background(), inputs, BatchOptions{Concurrency: 8}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records := log.records(t)
+		if len(records) != 20 || log.writes != 20 {
+			t.Fatalf("expected 20 records in 20 writes, got %d in %d", len(records), log.writes)
+		}
+		seen := make(map[string]bool)
+		for _, record := range records {
+			seen[record.RequestID] = true
+		}
+		if len(seen) != 20 {
+			t.Errorf("expected distinct request IDs, got %d", len(seen))
+		}
+	})
+
+	t.Run("write failure does not fail the request", func(t *testing.T) {
+		synapse, _ := Binary("Is this valid?", NewMockProvider(), WithAuditLog(failingWriter{}, AuditConfig{}))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("previews are not recorded", func(t *testing.T) {
+		log := &auditBuffer{}
+		synapse, _ := Binary("Is this valid?", NewMockProvider(), WithAuditLog(log, AuditConfig{}))
+
+		if _, err := previewSynapse(synapse, SynapseInput{Input: "input"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if records := log.records(t); len(records) != 0 {
+			t.Errorf("expected no records, got %+v", records)
+		}
+	})
+}
+
+func TestAuditOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, AuditSuccess},
+		{fmt.Errorf("%w: bad json", ErrParseFailed), AuditParseFailed},
+		{fmt.Errorf("%w: missing field", ErrInvalidResponse), AuditInvalidResponse},
+		{&ToolCallsError{Calls: []ToolCall{{Name: "lookup"}}}, AuditToolCalls},
+		{errors.New("timeout"), AuditError},
+	}
+	for _, tt := range tests {
+		if got := auditOutcome(tt.err); got != tt.want {
+			t.Errorf("auditOutcome(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoobzio/pipz"
+)
+
+// SynapseConfig describes a synapse type built with NewSynapse.
+// In is the synapse's input type and Resp its response type.
+type SynapseConfig[In any, Resp Validator] struct {
+	// Type identifies the synapse in hook events and error messages (e.g. "severity").
+	Type string
+
+	// Temperature is the default used when a call does not set one.
+	// Zero selects DefaultTemperatureDeterministic.
+	Temperature float32
+
+	// BuildPrompt renders the prompt for an input. Required.
+	// The response schema is filled in when the returned prompt leaves it empty.
+	BuildPrompt func(input In) *Prompt
+
+	// Validate runs after Resp.Validate for checks that depend on the synapse's
+	// configuration rather than the response alone. Optional. A failure is
+	// reported like any other invalid response and leaves the session untouched.
+	Validate func(response Resp) error
+}
+
+// Base carries the machinery shared by all synapses: the option pipeline,
+// schema generation, prompt validation, response parsing and validation,
+// transactional session updates, and hook emission. Custom synapse types
+// embed or wrap a Base and supply only their prompt building.
+type Base[In any, Resp Validator] struct {
+	config  SynapseConfig[In, Resp]
+	schema  string
+	service *Service[Resp]
+}
+
+// NewSynapse creates the base for a custom synapse type bound to a provider.
+// Options are applied exactly as for the built-in synapses.
+// Returns an error if the config is incomplete or the JSON schema cannot be generated.
+//
+// Example:
+//
+//	base, err := zyn.NewSynapse(zyn.SynapseConfig[string, SeverityResponse]{
+//	    Type: "severity",
+//	    BuildPrompt: func(incident string) *zyn.Prompt {
+//	        return &zyn.Prompt{Task: "Rate the severity of this incident", Input: incident}
+//	    },
+//	}, provider, zyn.WithRetry(3))
+//	response, err := base.Execute(ctx, session, "Checkout is down", zyn.TemperatureUnset)
+func NewSynapse[In any, Resp Validator](config SynapseConfig[In, Resp], provider Provider, opts ...Option) (*Base[In, Resp], error) {
+	if config.Type == "" {
+		return nil, fmt.Errorf("synapse type is required")
+	}
+	if config.BuildPrompt == nil {
+		return nil, fmt.Errorf("%s synapse: BuildPrompt is required", config.Type)
+	}
+
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[Resp]()
+	if err != nil {
+		return nil, fmt.Errorf("%s synapse: %w", config.Type, err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	temperature := config.Temperature
+	if temperature == 0 {
+		temperature = DefaultTemperatureDeterministic
+	}
+
+	svc := NewService[Resp](pipeline, config.Type, provider, temperature)
+	if config.Validate != nil {
+		svc.validate = func(_ *Prompt, response Resp) error {
+			return config.Validate(response)
+		}
+	}
+
+	return &Base[In, Resp]{
+		config:  config,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// Schema returns the JSON schema generated for Resp.
+func (b *Base[In, Resp]) Schema() string {
+	return b.schema
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (b *Base[In, Resp]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return b.service.GetPipeline()
+}
+
+// Execute builds the prompt for input and runs it through the pipeline.
+// A temperature of 0 or TemperatureUnset uses the configured default.
+func (b *Base[In, Resp]) Execute(ctx context.Context, session *Session, input In, temperature float32) (Resp, error) {
+	result, err := b.ExecuteResult(ctx, session, input, temperature)
+	return result.Value, err
+}
+
+// ExecuteResult is like Execute but returns the response in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (b *Base[In, Resp]) ExecuteResult(ctx context.Context, session *Session, input In, temperature float32) (Result[Resp], error) {
+	prompt := b.config.BuildPrompt(input)
+	if prompt == nil {
+		return Result[Resp]{Provider: b.service.providerName}, fmt.Errorf("%w: %s synapse built no prompt", ErrInvalidPrompt, b.config.Type)
+	}
+	if prompt.Schema == "" {
+		prompt.Schema = b.schema
+	}
+	return b.service.ExecuteResult(ctx, session, prompt, temperature)
+}
@@ -0,0 +1,227 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+type baseLevelResponse struct {
+	Level     string   `json:"level"`
+	Reasoning []string `json:"reasoning"`
+}
+
+func (r baseLevelResponse) Validate() error {
+	if r.Level == "" {
+		return fmt.Errorf("level required but empty")
+	}
+	return nil
+}
+
+func baseLevelConfig() SynapseConfig[string, baseLevelResponse] {
+	return SynapseConfig[string, baseLevelResponse]{
+		Type: "level",
+		BuildPrompt: func(input string) *Prompt {
+			return &Prompt{
+				Task:        "Rate the level",
+				Input:       input,
+				Constraints: []string{"level: low or high"},
+			}
+		},
+	}
+}
+
+func TestNewSynapse(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		var gotPrompt string
+		var gotTemp float32
+		provider := NewMockProviderWithCallback(func(prompt string, temp float32) (string, error) {
+			gotPrompt, gotTemp = prompt, temp
+			return `{"level": "high", "reasoning": ["outage"]}`, nil
+		})
+
+		base, err := NewSynapse(baseLevelConfig(), provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if base.GetPipeline() == nil {
+			t.Error("expected pipeline")
+		}
+		if !strings.Contains(base.Schema(), `"level"`) {
+			t.Errorf("expected schema for response type, got %s", base.Schema())
+		}
+
+		session := NewSession()
+		response, err := base.Execute(context.Background(), session, "checkout down", TemperatureUnset)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Level != "high" {
+			t.Errorf("expected high, got %q", response.Level)
+		}
+		if !strings.Contains(gotPrompt, "Task: Rate the level") || !strings.Contains(gotPrompt, `"level"`) {
+			t.Errorf("expected prompt with task and generated schema, got %s", gotPrompt)
+		}
+		if gotTemp != DefaultTemperatureDeterministic {
+			t.Errorf("expected default temperature %v, got %v", DefaultTemperatureDeterministic, gotTemp)
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected 2 session messages, got %d", session.Len())
+		}
+	})
+
+	t.Run("configured temperature", func(t *testing.T) {
+		var gotTemp float32
+		provider := NewMockProviderWithCallback(func(_ string, temp float32) (string, error) {
+			gotTemp = temp
+			return `{"level": "low", "reasoning": ["minor"]}`, nil
+		})
+		config := baseLevelConfig()
+		config.Temperature = DefaultTemperatureCreative
+
+		base, err := NewSynapse(config, provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := base.Execute(context.Background(), NewSession(), "typo", 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotTemp != DefaultTemperatureCreative {
+			t.Errorf("expected configured temperature, got %v", gotTemp)
+		}
+		if _, err := base.Execute(context.Background(), NewSession(), "typo", 0.9); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotTemp != 0.9 {
+			t.Errorf("expected call temperature, got %v", gotTemp)
+		}
+	})
+
+	t.Run("options applied", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", fmt.Errorf("transient")
+			}
+			return `{"level": "low", "reasoning": ["minor"]}`, nil
+		})
+
+		base, err := NewSynapse(baseLevelConfig(), provider, WithRetry(3))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := base.Execute(context.Background(), NewSession(), "typo", 0); err != nil {
+			t.Fatalf("expected success after retries, got %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		provider := NewMockProvider()
+
+		config := baseLevelConfig()
+		config.Type = ""
+		if _, err := NewSynapse(config, provider); err == nil {
+			t.Error("expected error for missing type")
+		}
+
+		config = baseLevelConfig()
+		config.BuildPrompt = nil
+		if _, err := NewSynapse(config, provider); err == nil || !strings.Contains(err.Error(), "level synapse: BuildPrompt is required") {
+			t.Errorf("expected BuildPrompt error, got %v", err)
+		}
+	})
+
+	t.Run("nil prompt", func(t *testing.T) {
+		config := baseLevelConfig()
+		config.BuildPrompt = func(string) *Prompt { return nil }
+
+		base, err := NewSynapse(config, NewMockProvider())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := base.Execute(context.Background(), NewSession(), "x", 0); !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("expected ErrInvalidPrompt, got %v", err)
+		}
+	})
+}
+
+func TestNewSynapse_PostValidation(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"level": "extreme", "reasoning": ["very bad"]}`)
+	config := baseLevelConfig()
+	config.Validate = func(r baseLevelResponse) error {
+		if r.Level != "low" && r.Level != "high" {
+			return fmt.Errorf("level %q not in scale", r.Level)
+		}
+		return nil
+	}
+
+	base, err := NewSynapse(config, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var errorType string
+	wg.Add(1)
+	listener := capitan.Hook(ResponseParseFailed, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		errorType, _ = ErrorTypeKey.From(e)
+	})
+	defer listener.Close()
+
+	session := NewSession()
+	_, err = base.Execute(context.Background(), session, "checkout down", 0)
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `level "extreme" not in scale`) {
+		t.Errorf("expected post-validation message, got %q", err.Error())
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected session untouched, got %d messages", session.Len())
+	}
+
+	wg.Wait()
+	if errorType != "validation_error" {
+		t.Errorf("expected validation_error hook, got %q", errorType)
+	}
+}
+
+func TestBinary_BuiltOnBase(t *testing.T) {
+	var gotPrompt string
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		gotPrompt = prompt
+		return `{"decision": true, "confidence": 0.9, "reasoning": ["valid"]}`, nil
+	})
+
+	synapse, err := Binary("the email is valid", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := synapse.buildPrompt(BinaryInput{Subject: "a@example.com"}).Render()
+	if _, err := synapse.Fire(context.Background(), NewSession(), "a@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPrompt != expected {
+		t.Errorf("expected base to send the synapse's prompt unchanged\nwant: %s\ngot:  %s", expected, gotPrompt)
+	}
+	// The base schema includes the score and the unknown flag, which are only
+	// sent when requested
+	schema, err := omitProperty(synapse.base.Schema(), binaryScoreProperty)
+	if err == nil {
+		schema, err = omitProperty(schema, binaryUnknownProperty)
+	}
+	if err != nil || synapse.schema != schema {
+		t.Error("expected binary schema to come from the base")
+	}
+}
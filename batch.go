@@ -0,0 +1,203 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SliceOptions configures a batch call.
+type SliceOptions struct {
+	Concurrency     int  // Maximum calls in flight; values below 1 run items sequentially
+	ContinueOnError bool // Keep processing after a failed item instead of stopping the batch
+}
+
+// BatchOptions configures a batch call that keeps going past failed items
+// unless told otherwise.
+type BatchOptions struct {
+	Concurrency int  // Maximum calls in flight; values below 1 run items sequentially
+	StopOnError bool // Stop the batch at the first failed item
+}
+
+// ItemError reports a failed item in a batch call.
+type ItemError struct {
+	Index int    // Position of the item in the input slice
+	Err   error  // Provider, parse, or validation error
+	Raw   string // Raw provider response, empty when the provider call itself failed
+}
+
+// Error implements the error interface.
+func (e ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// batchOutcome collects the results of runBatch.
+type batchOutcome[Out any] struct {
+	results []Result[Out] // Per-item envelopes in input order; zero for items not run
+	errors  []ItemError   // Failed items in index order
+	usage   TokenUsage    // Usage summed across items, including failed ones
+	err     error         // Set when the batch stopped before completing
+}
+
+// succeeded returns the number of items that completed without error.
+func (o batchOutcome[Out]) succeeded() int {
+	count := 0
+	for i := range o.results {
+		if o.ok(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// ok reports whether the item at index ran and completed without error.
+func (o batchOutcome[Out]) ok(index int) bool {
+	return o.results[index].RequestID != "" && !o.failed(index)
+}
+
+// failed reports whether the item at index failed.
+func (o batchOutcome[Out]) failed(index int) bool {
+	_, failed := o.failedWith(index)
+	return failed
+}
+
+// failedWith returns the error of the item at index, if it failed.
+func (o batchOutcome[Out]) failedWith(index int) (ItemError, bool) {
+	for _, itemErr := range o.errors {
+		if itemErr.Index == index {
+			return itemErr, true
+		}
+	}
+	return ItemError{}, false
+}
+
+// runBatch fires every item with at most concurrency calls in flight,
+// preserving input order in the results. When stopOnError is set, the first
+// failure cancels the remaining items and is returned as the batch error.
+// When unit is set, each finished item is reported to WithProgress callbacks
+// as "<unit> i/n"; items interrupted by an early stop are not reported.
+func runBatch[In, Out any](ctx context.Context, items []In, concurrency int, stopOnError bool, unit string,
+	fire func(context.Context, In) (Result[Out], error)) batchOutcome[Out] {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	outcome := batchOutcome[Out]{results: make([]Result[Out], len(items))}
+	// Hook events are delivered asynchronously and dropped once their context
+	// is cancelled, so the batch context is only cancellable when it may stop.
+	batchCtx, cancel := ctx, context.CancelFunc(func() {})
+	if stopOnError {
+		batchCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	var progress *progressScope
+	if unit != "" {
+		batchCtx, progress = startProgress(batchCtx, len(items), unit)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		stopped  bool
+		firstErr *ItemError
+	)
+	slots := make(chan struct{}, concurrency)
+
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-batchCtx.Done():
+		}
+		if batchCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(index int, item In) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result, err := fire(batchCtx, item)
+
+			finished := func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				addUsage(&outcome.usage, result.Usage)
+				// Items interrupted by an early stop are not failures of their own
+				if err != nil && stopped && errors.Is(err, context.Canceled) {
+					return false
+				}
+				outcome.results[index] = result
+				if err == nil {
+					return true
+				}
+				itemErr := ItemError{Index: index, Err: err, Raw: result.response}
+				outcome.errors = append(outcome.errors, itemErr)
+				if stopOnError && !stopped {
+					stopped = true
+					firstErr = &itemErr
+					cancel()
+				}
+				return true
+			}()
+			if finished && progress != nil {
+				progress.advance("", result.Usage)
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	slices.SortFunc(outcome.errors, func(a, b ItemError) int { return a.Index - b.Index })
+
+	switch {
+	case firstErr != nil:
+		outcome.err = fmt.Errorf("batch stopped: %w", *firstErr)
+	case ctx.Err() != nil:
+		outcome.err = ctx.Err()
+	}
+	return outcome
+}
+
+// addUsage adds usage to total when present.
+func addUsage(total *TokenUsage, usage *TokenUsage) {
+	if usage == nil {
+		return
+	}
+	total.Prompt += usage.Prompt
+	total.Completion += usage.Completion
+	total.Total += usage.Total
+	total.CachedPrompt += usage.CachedPrompt
+	total.Reasoning += usage.Reasoning
+}
+
+// recordBatch adds a single summary exchange for a batch to the session in
+// place of one exchange per item, and records the batch's summed usage.
+func recordBatch[Out any](session *Session, task string, outcome batchOutcome[Out]) {
+	if session == nil {
+		return
+	}
+
+	total := len(outcome.results)
+	summary := fmt.Sprintf("Processed %d of %d items.", outcome.succeeded(), total)
+	if len(outcome.errors) > 0 {
+		indices := make([]string, len(outcome.errors))
+		for i, itemErr := range outcome.errors {
+			indices[i] = strconv.Itoa(itemErr.Index)
+		}
+		summary += " Failed items: " + strings.Join(indices, ", ") + "."
+	}
+
+	session.Append(RoleUser, fmt.Sprintf("Task: %s\nInput: batch of %d items", task, total))
+	session.Append(RoleAssistant, summary)
+	session.SetUsage(&outcome.usage)
+}
@@ -0,0 +1,144 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatch_OrderAndConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	items := []int{5, 1, 4, 2, 3}
+
+	outcome := runBatch(context.Background(), items, 2, true, "", func(_ context.Context, item int) (Result[int], error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		// Later items finish first so order must come from the index
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		return Result[int]{Value: item * 10, RequestID: "req", Usage: &TokenUsage{Prompt: 1, Completion: 2, Total: 3}}, nil
+	})
+
+	if outcome.err != nil || len(outcome.errors) != 0 {
+		t.Fatalf("unexpected failure: %v %v", outcome.err, outcome.errors)
+	}
+	for i, item := range items {
+		if outcome.results[i].Value != item*10 {
+			t.Errorf("result %d: expected %d, got %d", i, item*10, outcome.results[i].Value)
+		}
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 calls in flight, got %d", peak.Load())
+	}
+	if outcome.usage.Total != 15 || outcome.usage.Prompt != 5 {
+		t.Errorf("expected summed usage, got %+v", outcome.usage)
+	}
+	if outcome.succeeded() != len(items) {
+		t.Errorf("expected %d succeeded, got %d", len(items), outcome.succeeded())
+	}
+}
+
+func TestRunBatch_StopOnError(t *testing.T) {
+	var calls atomic.Int32
+	items := []string{"ok", "bad", "ok", "ok"}
+
+	outcome := runBatch(context.Background(), items, 1, true, "", func(_ context.Context, item string) (Result[string], error) {
+		calls.Add(1)
+		if item == "bad" {
+			return Result[string]{RequestID: "req", response: "raw"}, errors.New("boom")
+		}
+		return Result[string]{Value: item, RequestID: "req"}, nil
+	})
+
+	if calls.Load() != 2 {
+		t.Errorf("expected batch to stop after the failure, got %d calls", calls.Load())
+	}
+	var itemErr ItemError
+	if !errors.As(outcome.err, &itemErr) || itemErr.Index != 1 {
+		t.Fatalf("expected batch error for item 1, got %v", outcome.err)
+	}
+	if len(outcome.errors) != 1 || outcome.errors[0].Raw != "raw" {
+		t.Errorf("expected item error with raw response, got %+v", outcome.errors)
+	}
+	if outcome.succeeded() != 1 {
+		t.Errorf("expected 1 succeeded, got %d", outcome.succeeded())
+	}
+}
+
+func TestRunBatch_ContinueOnError(t *testing.T) {
+	items := []string{"bad", "ok", "bad", "ok"}
+
+	outcome := runBatch(context.Background(), items, 3, false, "", func(_ context.Context, item string) (Result[string], error) {
+		if item == "bad" {
+			return Result[string]{RequestID: "req"}, errors.New("boom")
+		}
+		return Result[string]{Value: item, RequestID: "req"}, nil
+	})
+
+	if outcome.err != nil {
+		t.Fatalf("unexpected batch error: %v", outcome.err)
+	}
+	if len(outcome.errors) != 2 || outcome.errors[0].Index != 0 || outcome.errors[1].Index != 2 {
+		t.Errorf("expected item errors for 0 and 2 in order, got %+v", outcome.errors)
+	}
+	if outcome.results[1].Value != "ok" || outcome.results[3].Value != "ok" {
+		t.Error("expected successful items to keep their results")
+	}
+}
+
+func TestRunBatch_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outcome := runBatch(ctx, []int{1, 2}, 1, false, "", func(ctx context.Context, item int) (Result[int], error) {
+		return Result[int]{Value: item}, ctx.Err()
+	})
+	if !errors.Is(outcome.err, context.Canceled) {
+		t.Errorf("expected context error, got %v", outcome.err)
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	outcome := batchOutcome[int]{
+		results: []Result[int]{{RequestID: "a"}, {RequestID: "b"}, {}},
+		errors:  []ItemError{{Index: 1, Err: errors.New("boom")}},
+		usage:   TokenUsage{Prompt: 2, Completion: 4, Total: 6},
+	}
+	session := NewSession()
+	recordBatch(session, "Convert: records", outcome)
+
+	messages := session.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("expected one summary exchange, got %d messages", len(messages))
+	}
+	if !strings.Contains(messages[0].Content, "batch of 3 items") {
+		t.Errorf("unexpected user message: %q", messages[0].Content)
+	}
+	if messages[1].Content != "Processed 1 of 3 items. Failed items: 1." {
+		t.Errorf("unexpected summary: %q", messages[1].Content)
+	}
+	if session.TotalUsage().Total != 6 {
+		t.Errorf("expected batch usage recorded, got %+v", session.TotalUsage())
+	}
+
+	// A nil session is ignored
+	recordBatch(nil, "Convert: records", outcome)
+}
+
+func TestItemError(t *testing.T) {
+	err := ItemError{Index: 3, Err: ErrInvalidResponse}
+	if err.Error() != "item 3: "+ErrInvalidResponse.Error() {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Error("expected ItemError to unwrap")
+	}
+}
@@ -2,77 +2,242 @@ package zyn
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/zoobzio/pipz"
 )
 
 // BinaryInput contains rich input structure for binary decisions.
 type BinaryInput struct {
-	Subject     string   // The main item being evaluated
-	Context     string   // Background information or situation
-	Criteria    []string // Specific criteria for evaluation
-	Examples    []string // Examples of positive/negative cases
-	Constraints []string // Limitations or requirements
-	Temperature float32  // LLM temperature setting for this specific request
+	Subject     string       // The main item being evaluated
+	Context     string       // Background information or situation
+	Criteria    []string     // Specific criteria for evaluation
+	Examples    []string     // Examples of positive/negative cases
+	Constraints []string     // Limitations or requirements
+	Images      []ImageInput // Images to evaluate, for providers with vision support
+	Temperature float32      // LLM temperature setting for this specific request
 }
 
 // BinaryResponse contains the response from a binary synapse.
 type BinaryResponse struct {
-	Decision   bool     `json:"decision"`   // Binary yes/no result
-	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
-	Reasoning  []string `json:"reasoning"`  // Explanation of decision
+	Decision   bool     `json:"decision"`          // Binary yes/no result; false when Unknown
+	Unknown    bool     `json:"unknown,omitempty"` // The input lacks the information to decide, allowed with WithAbstain
+	Confidence float64  `json:"confidence"`        // 0.0 to 1.0 confidence score
+	Score      float64  `json:"score,omitempty"`   // Probability the answer is yes, requested with WithBinaryScore
+	Reasoning  []string `json:"reasoning"`         // Explanation of decision
 }
 
 // Validate checks if the response is valid.
 func (r BinaryResponse) Validate() error {
+	if r.Unknown && r.Decision {
+		return fmt.Errorf("decision must be null when unknown, got true")
+	}
 	if r.Confidence < 0 || r.Confidence > 1 {
 		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
 	}
+	if r.Score < 0 || r.Score > 1 {
+		return fmt.Errorf("score must be 0-1, got %f", r.Score)
+	}
 	if len(r.Reasoning) == 0 {
 		return fmt.Errorf("reasoning required but empty")
 	}
 	return nil
 }
 
+// binaryScoreProperty is the schema property holding the probability score.
+const binaryScoreProperty = "score"
+
+// binaryUnknownProperty is the schema property marking an abstention.
+const binaryUnknownProperty = "unknown"
+
+// binaryDecisionConstraint is the decision constraint of binary prompts that
+// do not allow abstaining.
+const binaryDecisionConstraint = "decision: true or false only"
+
+// binaryAbstainConstraint replaces binaryDecisionConstraint in binary prompts
+// built with WithAbstain.
+const binaryAbstainConstraint = "decision: true or false, or null with unknown true only when the input lacks the information " +
+	"needed to decide either way; when the input merely makes the call hard, set unknown false and decide, with lower confidence"
+
+// binaryScoreConstraint is added to binary prompts that request a score.
+const binaryScoreConstraint = "score: probability from 0.0 to 1.0 that the answer is true, consistent with decision"
+
+// binaryScoreTolerance is how far past 0.5 a score may lean against the
+// decision, absorbing borderline calls.
+const binaryScoreTolerance = 0.05
+
+// binarySettings holds the settings of one binary call.
+type binarySettings struct {
+	score   bool // Ask for a probability score, with WithBinaryScore or FireScore
+	abstain bool // Allow an unknown answer, with WithAbstain
+}
+
+// Identity for the binary score option.
+var binaryScoreID = pipz.NewIdentity("zyn:binary-score", "Requests a probability score")
+
+// Identity for the abstain option.
+var abstainID = pipz.NewIdentity("zyn:abstain", "Allows an unknown binary outcome")
+
+// fullBinarySchema is the binary schema including the score and the unknown
+// flag, generated on first use.
+var fullBinarySchema = sync.OnceValues(generateJSONSchema[BinaryResponse])
+
+// binarySchema returns the binary schema with the score and the unknown flag
+// only if requested. Allowing unknown also makes the decision nullable.
+func binarySchema(score, abstain bool) (string, error) {
+	schema, err := fullBinarySchema()
+	if err != nil {
+		return "", err
+	}
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	omit := func(property string) {
+		delete(parsed.Properties, property)
+		parsed.Required = slices.DeleteFunc(parsed.Required, func(name string) bool { return name == property })
+	}
+	if !score {
+		omit(binaryScoreProperty)
+	}
+	if abstain {
+		parsed.Properties["decision"].Nullable = true
+	} else {
+		omit(binaryUnknownProperty)
+	}
+
+	jsonBytes, err := json.MarshalIndent(&parsed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// WithAbstain lets a binary synapse answer unknown when the input does not
+// contain the information needed to decide, instead of forcing a guess.
+// The decision becomes nullable in the response schema, and Fire, FireResult
+// and FireScore return ErrUndecided for an unknown answer; FireWithDetails
+// and FireWithInput return the response with Unknown set. Without it, the
+// two-valued behavior is unchanged and an unknown answer is rejected.
+// The option has no effect on other synapse types.
+func WithAbstain() Option {
+	return withSettings(abstainID, func(req *SynapseRequest, settings *binarySettings) {
+		if settings.abstain {
+			return
+		}
+		settings.abstain = true
+		constraints := slices.Clone(req.Prompt.Constraints)
+		if i := slices.Index(constraints, binaryDecisionConstraint); i >= 0 {
+			constraints[i] = binaryAbstainConstraint
+		} else {
+			constraints = append(constraints, binaryAbstainConstraint)
+		}
+		req.Prompt.Constraints = constraints
+		setBinarySchema(req.Prompt, settings)
+	})
+}
+
+// WithBinaryScore asks a binary synapse for a probability that the answer is
+// yes alongside its decision, for callers that apply their own thresholds.
+// Without it the response schema does not mention the score. A requested
+// score must agree with the decision: at least 0.5 for true and at most 0.5
+// for false, within a small tolerance.
+// The option has no effect on other synapse types.
+func WithBinaryScore() Option {
+	return withSettings(binaryScoreID, func(req *SynapseRequest, settings *binarySettings) {
+		requestBinaryScore(req.Prompt, settings)
+	})
+}
+
+// requestBinaryScore adds the score to a binary prompt's schema and
+// constraints unless the call already asks for one.
+func requestBinaryScore(prompt *Prompt, settings *binarySettings) {
+	if settings.score {
+		return
+	}
+	settings.score = true
+	prompt.Constraints = append(slices.Clone(prompt.Constraints), binaryScoreConstraint)
+	setBinarySchema(prompt, settings)
+}
+
+// setBinarySchema sets a binary prompt's schema to match the score and
+// unknown outcome the call asks for, in strict form for strict prompts.
+func setBinarySchema(prompt *Prompt, settings *binarySettings) {
+	schema, err := binarySchema(settings.score, settings.abstain)
+	if err != nil {
+		return
+	}
+	if prompt.Strict {
+		if schema, err = strictSchemaJSON(schema); err != nil {
+			return
+		}
+	}
+	prompt.Schema = schema
+}
+
+// validateBinary rejects unknown answers the call does not allow, and
+// checks that a requested score agrees with the decision.
+func validateBinary(settings *binarySettings, response BinaryResponse) error {
+	if response.Unknown {
+		if !settings.abstain {
+			return fmt.Errorf("unknown not allowed; decision must be true or false")
+		}
+		return nil
+	}
+	if !settings.score {
+		return nil
+	}
+	if response.Decision && response.Score < 0.5-binaryScoreTolerance {
+		return fmt.Errorf("score %.2f contradicts decision true", response.Score)
+	}
+	if !response.Decision && response.Score > 0.5+binaryScoreTolerance {
+		return fmt.Errorf("score %.2f contradicts decision false", response.Score)
+	}
+	return nil
+}
+
 // BinarySynapse represents a binary (yes/no) decision synapse.
 type BinarySynapse struct {
 	question string
 	schema   string // Pre-computed JSON schema
 	defaults BinaryInput
-	service  *Service[BinaryResponse]
+	base     *Base[BinaryInput, BinaryResponse]
 }
 
 // NewBinary creates a new binary synapse bound to a provider.
 // The synapse is immediately usable and can be enhanced with options.
 // Returns an error if the JSON schema cannot be generated.
 func NewBinary(question string, provider Provider, opts ...Option) (*BinarySynapse, error) {
-	// Generate schema once at construction
-	schema, err := generateJSONSchema[BinaryResponse]()
+	synapse := &BinarySynapse{question: question}
+
+	base, err := NewSynapse(SynapseConfig[BinaryInput, BinaryResponse]{
+		Type:        "binary",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("binary synapse: %w", err)
+		return nil, err
 	}
 
-	// Apply options to build pipeline
-	pipeline := NewTerminal(provider)
-	for _, opt := range opts {
-		pipeline = opt(pipeline)
+	// The score is only part of the schema when requested with
+	// WithBinaryScore or FireScore, and the unknown outcome with WithAbstain
+	schema, err := binarySchema(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("binary synapse: %w", err)
 	}
 
-	// Create service with final pipeline and default temperature
-	svc := NewService[BinaryResponse](pipeline, "binary", provider, DefaultTemperatureDeterministic)
-
-	return &BinarySynapse{
-		question: question,
-		schema:   schema,
-		service:  svc,
-	}, nil
+	synapse.schema = schema
+	synapse.base = base
+	return synapse, nil
 }
 
 // GetPipeline returns the internal pipeline for composition.
 // Implements ServiceProvider interface.
 func (b *BinarySynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
-	return b.service.GetPipeline()
+	return b.base.GetPipeline()
 }
 
 // WithDefaults creates a new Binary with default input values.
@@ -83,15 +248,32 @@ func (b *BinarySynapse) WithDefaults(defaults BinaryInput) *BinarySynapse {
 }
 
 // Fire executes the synapse against a simple string input.
-// Returns only the boolean decision.
+// Returns only the boolean decision, or ErrUndecided if the synapse was
+// built with WithAbstain and answered unknown.
 func (b *BinarySynapse) Fire(ctx context.Context, session *Session, input string) (bool, error) {
 	response, err := b.FireWithDetails(ctx, session, input)
 	if err != nil {
 		return false, err
 	}
+	if response.Unknown {
+		return false, ErrUndecided
+	}
 	return response.Decision, nil
 }
 
+// FireResult executes the synapse and returns the decision in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (b *BinarySynapse) FireResult(ctx context.Context, session *Session, input string) (Result[bool], error) {
+	result, err := b.execute(ctx, session, BinaryInput{Subject: input}, false)
+	if err != nil {
+		return withValue(result, false), err
+	}
+	if result.Value.Unknown {
+		return withValue(result, false), ErrUndecided
+	}
+	return withValue(result, result.Value.Decision), nil
+}
+
 // FireWithDetails executes the synapse and returns the full response.
 func (b *BinarySynapse) FireWithDetails(ctx context.Context, session *Session, input string) (BinaryResponse, error) {
 	binInput := BinaryInput{Subject: input}
@@ -100,14 +282,90 @@ func (b *BinarySynapse) FireWithDetails(ctx context.Context, session *Session, i
 
 // FireWithInput executes the synapse with rich input structure.
 func (b *BinarySynapse) FireWithInput(ctx context.Context, session *Session, input BinaryInput) (BinaryResponse, error) {
-	// Merge defaults with user input
-	merged := b.mergeInputs(input)
+	result, err := b.execute(ctx, session, input, false)
+	return result.Value, err
+}
+
+// FireScore executes the synapse asking for a probability score and returns
+// the probability that the answer is yes, whether or not the synapse was
+// built with WithBinaryScore. Like Fire, it returns ErrUndecided for an
+// unknown answer.
+func (b *BinarySynapse) FireScore(ctx context.Context, session *Session, input string) (float64, error) {
+	result, err := b.execute(ctx, session, BinaryInput{Subject: input}, true)
+	if err != nil {
+		return 0, err
+	}
+	response := result.Value
+	if response.Unknown {
+		return 0, ErrUndecided
+	}
+	return response.Score, nil
+}
+
+// BinaryBatch contains the results of FireMany.
+// Responses and Errors are parallel views of the batch: the response of a
+// failed subject is the zero value and its error is reported in Errors.
+type BinaryBatch struct {
+	Responses []BinaryResponse // Per-subject responses in input order
+	Errors    []ItemError      // Failed subjects in index order
+	Usage     TokenUsage       // Usage summed across all provider calls
+}
+
+// FireMany evaluates each subject independently, with up to opts.Concurrency
+// calls in flight. Each subject is evaluated in its own session. Calls go
+// through the synapse's pipeline, so options such as WithRateLimit and
+// WithConcurrencyLimit bound the batch as they bound any other calls.
+//
+// Failed subjects are reported in the batch's Errors and do not stop the batch
+// unless opts.StopOnError is set, in which case the first failure is also
+// returned as the error alongside the results collected so far.
+func (b *BinarySynapse) FireMany(ctx context.Context, subjects []string, opts BatchOptions) (*BinaryBatch, error) {
+	outcome := runBatch(ctx, subjects, opts.Concurrency, opts.StopOnError, "item",
+		func(ctx context.Context, subject string) (Result[BinaryResponse], error) {
+			result, err := b.execute(ctx, NewSession(), BinaryInput{Subject: subject}, false)
+			if err != nil {
+				return withValue(result, BinaryResponse{}), err
+			}
+			return result, nil
+		})
+
+	batch := &BinaryBatch{
+		Responses: make([]BinaryResponse, len(subjects)),
+		Errors:    outcome.errors,
+		Usage:     outcome.usage,
+	}
+	for i, result := range outcome.results {
+		batch.Responses[i] = result.Value
+	}
+	return batch, outcome.err
+}
 
-	// Build prompt
+// execute runs one binary call, asking for a probability score when score is
+// set even if the synapse was not built with WithBinaryScore.
+func (b *BinarySynapse) execute(ctx context.Context, session *Session, input BinaryInput, score bool) (Result[BinaryResponse], error) {
+	merged := b.mergeInputs(input)
 	prompt := b.buildPrompt(merged)
+	settings := &binarySettings{}
+	if score {
+		requestBinaryScore(prompt, settings)
+	}
+	return b.base.service.executeConfigured(ctx, session, prompt, settings, merged.Temperature, func(response BinaryResponse) error {
+		return validateBinary(settings, response)
+	})
+}
 
-	// Execute through service with session (service handles temperature fallback)
-	return b.service.Execute(ctx, session, prompt, merged.Temperature)
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a BinaryResponse.
+func (b *BinarySynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := b.FireWithInput(ctx, session, BinaryInput{
+		Subject:     input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // mergeInputs combines defaults with user input.
@@ -129,6 +387,9 @@ func (b *BinarySynapse) mergeInputs(input BinaryInput) BinaryInput {
 	if len(input.Constraints) > 0 {
 		merged.Constraints = append(merged.Constraints, input.Constraints...)
 	}
+	if len(input.Images) > 0 {
+		merged.Images = append(merged.Images, input.Images...)
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -147,7 +408,7 @@ func (b *BinarySynapse) buildPrompt(input BinaryInput) *Prompt {
 
 	// Build constraints
 	prompt.Constraints = []string{
-		"decision: true or false only",
+		binaryDecisionConstraint,
 		"confidence: 0.0 to 1.0",
 		"reasoning: ordered steps explaining decision",
 	}
@@ -160,6 +421,12 @@ func (b *BinarySynapse) buildPrompt(input BinaryInput) *Prompt {
 	// Add input constraints if provided
 	prompt.Constraints = append(prompt.Constraints, input.Constraints...)
 
+	// Attach images if provided
+	if len(input.Images) > 0 {
+		prompt.Images = input.Images
+		prompt.Constraints = append(prompt.Constraints, imagesConstraint)
+	}
+
 	// Add examples if provided
 	if len(input.Examples) > 0 {
 		prompt.Examples = map[string][]string{
@@ -180,7 +447,7 @@ func (b *BinarySynapse) buildPrompt(input BinaryInput) *Prompt {
 //	    WithRetry(3),
 //	    WithTimeout(10*time.Second),
 //	)
-//	result, err := synapse.Fire(ctx, "test@example.com")
+//	result, err := synapse.Fire(ctx, session, "test@example.com")
 func Binary(question string, provider Provider, opts ...Option) (*BinarySynapse, error) {
 	return NewBinary(question, provider, opts...)
 }
@@ -2,6 +2,9 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -486,4 +489,323 @@ func TestBinaryResponse_Validate(t *testing.T) {
 			t.Error("expected error for empty reasoning")
 		}
 	})
+
+	t.Run("score_out_of_range", func(t *testing.T) {
+		r := BinaryResponse{
+			Decision:   true,
+			Confidence: 0.9,
+			Score:      1.2,
+			Reasoning:  []string{"reason"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for score > 1")
+		}
+	})
+
+	t.Run("unknown_with_decision", func(t *testing.T) {
+		r := BinaryResponse{
+			Decision:   true,
+			Unknown:    true,
+			Confidence: 0.9,
+			Reasoning:  []string{"reason"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a decision alongside unknown")
+		}
+	})
+}
+
+func TestBinarySynapse_FireMany(t *testing.T) {
+	// Subjects starting with "spam" are flagged; "garbled" gets an unparseable response
+	callback := func(prompt string, _ float32) (string, error) {
+		switch {
+		case strings.Contains(prompt, "Input: garbled"):
+			return "not json", nil
+		case strings.Contains(prompt, "Input: spam"):
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["spam"]}`, nil
+		default:
+			return `{"decision": false, "confidence": 0.8, "reasoning": ["clean"]}`, nil
+		}
+	}
+
+	t.Run("order and per-item errors", func(t *testing.T) {
+		synapse, err := Binary("Is this spam?", NewMockProviderWithCallback(callback))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		subjects := []string{"spam offer", "hello", "garbled", "spam again", "thanks"}
+		batch, err := synapse.FireMany(context.Background(), subjects, BatchOptions{Concurrency: 3})
+		if err != nil {
+			t.Fatalf("expected a bad item not to fail the batch, got %v", err)
+		}
+
+		expected := []bool{true, false, false, true, false}
+		for i, decision := range expected {
+			if batch.Responses[i].Decision != decision {
+				t.Errorf("subject %d: expected %v, got %v", i, decision, batch.Responses[i].Decision)
+			}
+		}
+		if len(batch.Errors) != 1 || batch.Errors[0].Index != 2 {
+			t.Fatalf("expected item error for index 2, got %+v", batch.Errors)
+		}
+		if batch.Errors[0].Raw != "not json" {
+			t.Errorf("unexpected item error: %+v", batch.Errors[0])
+		}
+		if batch.Responses[2].Confidence != 0 {
+			t.Errorf("expected zero response for failed item, got %+v", batch.Responses[2])
+		}
+		if batch.Usage.Total != 750 {
+			t.Errorf("expected usage summed over 5 calls, got %+v", batch.Usage)
+		}
+	})
+
+	t.Run("respects concurrency limit", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+		provider := NewMockProviderWithCallback(func(prompt string, temperature float32) (string, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := peak.Load()
+				if current <= seen || peak.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return callback(prompt, temperature)
+		})
+		synapse, err := Binary("Is this spam?", provider, WithConcurrencyLimit(1))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		batch, err := synapse.FireMany(context.Background(), []string{"a", "b", "c", "d"}, BatchOptions{Concurrency: 4})
+		if err != nil || len(batch.Errors) != 0 {
+			t.Fatalf("unexpected failure: %v %v", err, batch.Errors)
+		}
+		if peak.Load() != 1 {
+			t.Errorf("expected the synapse's limit to bound the batch, got %d in flight", peak.Load())
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		synapse, err := Binary("Is this spam?", NewMockProviderWithCallback(callback))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		batch, err := synapse.FireMany(context.Background(), []string{"garbled", "hello"}, BatchOptions{StopOnError: true})
+		if err == nil {
+			t.Fatal("expected batch error")
+		}
+		if len(batch.Responses) != 2 || batch.Responses[1].Confidence != 0 {
+			t.Errorf("expected remaining subjects to be skipped, got %+v", batch.Responses)
+		}
+	})
+}
+
+func TestWithBinaryScore(t *testing.T) {
+	var prompts []string
+	respond := func(response string) Provider {
+		return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			prompts = append(prompts, prompt)
+			return response, nil
+		})
+	}
+
+	t.Run("schema omits score by default", func(t *testing.T) {
+		prompts = nil
+		synapse, err := Binary("Is this spam?", respond(`{"decision": true, "confidence": 0.9, "reasoning": ["r"]}`))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "win a prize")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Score != 0 || strings.Contains(prompts[0], `"score"`) || strings.Contains(prompts[0], binaryScoreConstraint) {
+			t.Errorf("expected no score requested, got %v in\n%s", response.Score, prompts[0])
+		}
+	})
+
+	t.Run("option requests score", func(t *testing.T) {
+		prompts = nil
+		synapse, err := Binary("Is this spam?",
+			respond(`{"decision": true, "confidence": 0.7, "score": 0.92, "reasoning": ["r"]}`), WithBinaryScore())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "win a prize")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Score != 0.92 {
+			t.Errorf("expected score 0.92, got %f", response.Score)
+		}
+		if !strings.Contains(prompts[0], `"score"`) || !strings.Contains(prompts[0], binaryScoreConstraint) {
+			t.Errorf("expected score in schema and constraints, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("rejects score contradicting decision", func(t *testing.T) {
+		synapse, err := Binary("Is this spam?",
+			respond(`{"decision": false, "confidence": 0.7, "score": 0.95, "reasoning": ["r"]}`), WithBinaryScore())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		session := NewSession()
+		_, err = synapse.FireWithDetails(context.Background(), session, "win a prize")
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "contradicts decision false") {
+			t.Errorf("expected inconsistent score rejected, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("tolerates borderline score", func(t *testing.T) {
+		synapse, _ := Binary("Is this spam?",
+			respond(`{"decision": true, "confidence": 0.5, "score": 0.48, "reasoning": ["r"]}`), WithBinaryScore())
+		if _, err := synapse.Fire(context.Background(), NewSession(), "win a prize"); err != nil {
+			t.Errorf("expected borderline score accepted, got %v", err)
+		}
+	})
+
+	t.Run("ignores score when not requested", func(t *testing.T) {
+		synapse, _ := Binary("Is this spam?",
+			respond(`{"decision": false, "confidence": 0.7, "score": 0.95, "reasoning": ["r"]}`))
+		if _, err := synapse.Fire(context.Background(), NewSession(), "win a prize"); err != nil {
+			t.Errorf("expected unrequested score unchecked, got %v", err)
+		}
+	})
+
+	t.Run("fire score", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Binary("Is this spam?",
+			respond(`{"decision": false, "confidence": 0.8, "score": 0.1, "reasoning": ["r"]}`))
+		score, err := synapse.FireScore(context.Background(), NewSession(), "hello")
+		if err != nil || score != 0.1 {
+			t.Errorf("expected score 0.1, got %f, %v", score, err)
+		}
+		if strings.Count(prompts[0], binaryScoreConstraint) != 1 {
+			t.Errorf("expected score requested once, got\n%s", prompts[0])
+		}
+
+		synapse, _ = Binary("Is this spam?",
+			respond(`{"decision": true, "confidence": 0.8, "score": 0.9, "reasoning": ["r"]}`), WithBinaryScore())
+		prompts = nil
+		if _, err := synapse.FireScore(context.Background(), NewSession(), "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Count(prompts[0], binaryScoreConstraint) != 1 {
+			t.Errorf("expected score requested once with the option, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("ignores other synapses", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Classification("Type?", []string{"a", "b"},
+			respond(`{"primary": "a", "confidence": 0.9, "reasoning": ["r"]}`), WithBinaryScore())
+		if _, err := synapse.Fire(context.Background(), NewSession(), "x"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompts[0], binaryScoreConstraint) {
+			t.Error("expected classification prompt unchanged")
+		}
+	})
+}
+
+func TestWithAbstain(t *testing.T) {
+	var prompts []string
+	respond := func(response string) Provider {
+		return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			prompts = append(prompts, prompt)
+			return response, nil
+		})
+	}
+	unknown := `{"decision": null, "unknown": true, "confidence": 0.8, "reasoning": ["the message is empty"]}`
+
+	t.Run("two-valued by default", func(t *testing.T) {
+		prompts = nil
+		synapse, err := Binary("Is this spam?", respond(unknown))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		_, err = synapse.Fire(context.Background(), NewSession(), "see attached")
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "unknown not allowed") {
+			t.Errorf("expected unknown rejected, got %v", err)
+		}
+		if strings.Contains(prompts[0], `"unknown"`) || !strings.Contains(prompts[0], binaryDecisionConstraint) {
+			t.Errorf("expected no unknown outcome offered, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("option allows unknown", func(t *testing.T) {
+		prompts = nil
+		synapse, err := Binary("Is this spam?", respond(unknown), WithAbstain())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "see attached")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !response.Unknown || response.Decision {
+			t.Errorf("expected an unknown response, got %+v", response)
+		}
+		if !strings.Contains(prompts[0], `"unknown"`) || !strings.Contains(prompts[0], `"null"`) {
+			t.Errorf("expected a nullable decision and unknown in the schema, got\n%s", prompts[0])
+		}
+		if !strings.Contains(prompts[0], binaryAbstainConstraint) || strings.Contains(prompts[0], binaryDecisionConstraint) {
+			t.Errorf("expected the abstain constraint in place of the two-valued one, got\n%s", prompts[0])
+		}
+	})
+
+	t.Run("fire returns undecided", func(t *testing.T) {
+		synapse, _ := Binary("Is this spam?", respond(unknown), WithAbstain())
+		if decision, err := synapse.Fire(context.Background(), NewSession(), "see attached"); !errors.Is(err, ErrUndecided) || decision {
+			t.Errorf("expected ErrUndecided, got %v, %v", decision, err)
+		}
+		result, err := synapse.FireResult(context.Background(), NewSession(), "see attached")
+		if !errors.Is(err, ErrUndecided) || result.Value || result.Provider == "" {
+			t.Errorf("expected ErrUndecided with the call metadata, got %+v, %v", result, err)
+		}
+		if _, err := synapse.FireScore(context.Background(), NewSession(), "see attached"); !errors.Is(err, ErrUndecided) {
+			t.Errorf("expected ErrUndecided from FireScore, got %v", err)
+		}
+	})
+
+	t.Run("decisions still returned", func(t *testing.T) {
+		synapse, _ := Binary("Is this spam?",
+			respond(`{"decision": true, "unknown": false, "confidence": 0.9, "reasoning": ["r"]}`), WithAbstain())
+		if decision, err := synapse.Fire(context.Background(), NewSession(), "win a prize"); err != nil || !decision {
+			t.Errorf("expected decision true, got %v, %v", decision, err)
+		}
+	})
+
+	t.Run("stacks with score", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Binary("Is this spam?",
+			respond(`{"decision": false, "confidence": 0.9, "score": 0.1, "reasoning": ["r"]}`), WithAbstain(), WithBinaryScore())
+		if _, err := synapse.Fire(context.Background(), NewSession(), "hello"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{`"score"`, `"unknown"`, binaryScoreConstraint, binaryAbstainConstraint} {
+			if !strings.Contains(prompts[0], want) {
+				t.Errorf("expected prompt to contain %q, got\n%s", want, prompts[0])
+			}
+		}
+	})
+
+	t.Run("ignores other synapses", func(t *testing.T) {
+		prompts = nil
+		synapse, _ := Classification("Type?", []string{"a", "b"},
+			respond(`{"primary": "a", "confidence": 0.9, "reasoning": ["r"]}`), WithAbstain())
+		if _, err := synapse.Fire(context.Background(), NewSession(), "x"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompts[0], binaryAbstainConstraint) {
+			t.Error("expected classification prompt unchanged")
+		}
+	})
 }
@@ -0,0 +1,262 @@
+package zyn
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// defaultCacheEntries is the number of responses a CachingProvider keeps
+// when CacheConfig.MaxEntries is unset.
+const defaultCacheEntries = 1000
+
+// CacheConfig configures a CachingProvider.
+type CacheConfig struct {
+	MaxEntries int           // Responses kept before the least recently used is evicted; defaults to 1000
+	TTL        time.Duration // How long a response is served from the cache; 0 keeps it until evicted
+}
+
+// CachingProvider is a Provider that caches the responses of another
+// provider in memory, so identical calls are answered without calling the
+// model again. Calls are identical when their messages, temperature, tools,
+// call parameters, and provider name match.
+//
+// Cached responses are returned with Cached set and the usage of the call
+// that produced them, and emit CacheHit. Errors and truncated responses are
+// never cached. It is safe for concurrent use; identical calls made while
+// the first is in flight all reach the provider.
+type CachingProvider struct {
+	provider Provider
+	config   CacheConfig
+	closed   atomic.Bool // Set by Close
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+// cacheEntry is a cached response and when it stops being served.
+type cacheEntry struct {
+	key      string
+	response ProviderResponse
+	expires  time.Time // Zero when the cache has no TTL
+}
+
+// NewCachingProvider wraps provider with an in-memory LRU cache.
+//
+// Example:
+//
+//	cached := zyn.NewCachingProvider(provider, zyn.CacheConfig{MaxEntries: 500, TTL: time.Hour})
+//	classifier, _ := zyn.Classification("ticket category", categories, cached)
+func NewCachingProvider(provider Provider, config CacheConfig) *CachingProvider {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultCacheEntries
+	}
+	return &CachingProvider{
+		provider: provider,
+		config:   config,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Name returns the wrapped provider's name.
+func (c *CachingProvider) Name() string {
+	return c.provider.Name()
+}
+
+// Call returns the cached response for identical calls, and otherwise
+// calls the wrapped provider and caches its response.
+func (c *CachingProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	return c.cached(ctx, messages, temperature, nil, func() (*ProviderResponse, error) {
+		return c.provider.Call(ctx, messages, temperature)
+	})
+}
+
+// CallWithTools is like Call, offering tools to the wrapped provider. Calls
+// with tools fail with a *ToolsUnsupportedError when the wrapped provider
+// does not implement ToolProvider.
+func (c *CachingProvider) CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error) {
+	if len(tools) == 0 {
+		return c.Call(ctx, messages, temperature)
+	}
+	toolProvider, ok := c.provider.(ToolProvider)
+	if !ok {
+		return nil, &ToolsUnsupportedError{Provider: c.provider.Name(), Tools: len(tools)}
+	}
+	return c.cached(ctx, messages, temperature, tools, func() (*ProviderResponse, error) {
+		return toolProvider.CallWithTools(ctx, messages, temperature, tools)
+	})
+}
+
+// Capabilities returns the wrapped provider's capabilities, if it
+// advertises any.
+func (c *CachingProvider) Capabilities() Capabilities {
+	if capable, ok := c.provider.(CapabilitiesProvider); ok {
+		return capable.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// EstimateMessages estimates tokens with the wrapped provider's estimator.
+func (c *CachingProvider) EstimateMessages(messages []Message) int {
+	return estimatorFor(c.provider).EstimateMessages(messages)
+}
+
+// Len returns the number of cached responses, including expired ones not
+// yet removed.
+func (c *CachingProvider) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Clear removes every cached response.
+func (c *CachingProvider) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Close clears the cache and closes the wrapped provider, if it implements
+// io.Closer. Calls after Close fail with a *ProviderClosedError, cached or
+// not. Closing again is a no-op.
+func (c *CachingProvider) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	c.Clear()
+	return closeProvider(c.provider)
+}
+
+// cached serves the call from the cache, or makes it with call and caches
+// a successful, complete response.
+func (c *CachingProvider) cached(ctx context.Context, messages []Message, temperature float32, tools []Tool, call func() (*ProviderResponse, error)) (*ProviderResponse, error) {
+	if c.closed.Load() {
+		return nil, &ProviderClosedError{Provider: c.provider.Name()}
+	}
+
+	params, _ := CallParamsFromContext(ctx)
+	key, err := c.key(messages, temperature, tools, params)
+	if err != nil {
+		// Calls that cannot be keyed are not cached
+		return call()
+	}
+
+	if response, ok := c.get(key); ok {
+		capitan.Info(ctx, CacheHit, HookFields(ctx,
+			ProviderKey.Field(c.provider.Name()),
+		)...)
+		return response, nil
+	}
+
+	response, err := call()
+	if err != nil || response == nil || response.Truncated {
+		return response, err
+	}
+	c.put(key, *response)
+	return response, nil
+}
+
+// key hashes everything that determines a call's response.
+func (c *CachingProvider) key(messages []Message, temperature float32, tools []Tool, params CallParams) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Provider    string
+		Temperature float32
+		Messages    []Message
+		Tools       []Tool
+		Params      CallParams
+	}{c.provider.Name(), temperature, messages, tools, params})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// get returns a copy of the live response cached under key, marking it most
+// recently used.
+func (c *CachingProvider) get(key string) (*ProviderResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	response := cloneResponse(entry.response)
+	response.Cached = true
+	return &response, true
+}
+
+// put caches response under key, evicting the least recently used response
+// when the cache is full.
+func (c *CachingProvider) put(key string, response ProviderResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, response: cloneResponse(response)}
+	if c.config.TTL > 0 {
+		entry.expires = time.Now().Add(c.config.TTL)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.config.MaxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// cloneResponse copies response deeply enough that callers modifying the
+// tool calls or logprobs of one copy do not affect the cached response.
+func cloneResponse(response ProviderResponse) ProviderResponse {
+	if response.ToolCalls != nil {
+		calls := make([]ToolCall, len(response.ToolCalls))
+		for i, call := range response.ToolCalls {
+			call.Arguments = bytes.Clone(call.Arguments)
+			calls[i] = call
+		}
+		response.ToolCalls = calls
+	}
+	response.Logprobs = cloneLogprobs(response.Logprobs)
+	return response
+}
+
+// cloneLogprobs copies logprobs and their top alternatives.
+func cloneLogprobs(logprobs []TokenLogprob) []TokenLogprob {
+	if logprobs == nil {
+		return nil
+	}
+	cloned := make([]TokenLogprob, len(logprobs))
+	for i, logprob := range logprobs {
+		logprob.TopLogprobs = cloneLogprobs(logprob.TopLogprobs)
+		cloned[i] = logprob
+	}
+	return cloned
+}
+
+// remove drops a cached response. The caller must hold c.mu.
+func (c *CachingProvider) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,347 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+// countingProvider is a MockProvider that counts its calls and can be made
+// to fail or truncate.
+type countingProvider struct {
+	*MockProvider
+	calls     atomic.Int64
+	fail      atomic.Bool
+	truncated atomic.Bool
+}
+
+func newCountingProvider() *countingProvider {
+	return &countingProvider{MockProvider: NewMockProviderWithName("counting")}
+}
+
+func (p *countingProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	p.calls.Add(1)
+	if p.fail.Load() {
+		return nil, errors.New("provider failed")
+	}
+	response, err := p.MockProvider.Call(ctx, messages, temperature)
+	if err == nil {
+		response.Truncated = p.truncated.Load()
+	}
+	return response, err
+}
+
+func userMessage(content string) []Message {
+	return []Message{{Role: RoleUser, Content: content}}
+}
+
+func TestCachingProvider_SkipsIdenticalCalls(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{})
+	synapse, _ := Binary("Is this a duplicate ticket?", cached)
+
+	first, err := synapse.FireResult(context.Background(), NewSession(), "printer offline")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := synapse.FireResult(context.Background(), NewSession(), "printer offline")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if inner.calls.Load() != 1 {
+		t.Errorf("expected one inner call, got %d", inner.calls.Load())
+	}
+	if first.Value != second.Value || string(first.Raw) != string(second.Raw) {
+		t.Errorf("expected identical results, got %+v and %+v", first, second)
+	}
+	if cached.Name() != "counting" || second.Provider != "counting" {
+		t.Errorf("expected inner provider name, got %s", second.Provider)
+	}
+
+	// A different input misses
+	if _, err := synapse.Fire(context.Background(), NewSession(), "login broken"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.calls.Load() != 2 || cached.Len() != 2 {
+		t.Errorf("expected a second inner call and entry, got %d calls and %d entries", inner.calls.Load(), cached.Len())
+	}
+}
+
+func TestCachingProvider_Key(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{})
+	ctx := context.Background()
+
+	calls := []struct {
+		name        string
+		ctx         context.Context
+		messages    []Message
+		temperature float32
+	}{
+		{"first", ctx, userMessage("input"), 0.1},
+		{"temperature", ctx, userMessage("input"), 0.7},
+		{"messages", ctx, append([]Message{{Role: RoleSystem, Content: "be brief"}}, userMessage("input")...), 0.1},
+		{"params", contextWithCallParams(ctx, CallParams{MaxTokens: 64}), userMessage("input"), 0.1},
+	}
+	for i, call := range calls {
+		response, err := cached.Call(call.ctx, call.messages, call.temperature)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", call.name, err)
+		}
+		if response.Cached || inner.calls.Load() != int64(i+1) {
+			t.Errorf("%s: expected a miss, got cached=%t after %d calls", call.name, response.Cached, inner.calls.Load())
+		}
+	}
+
+	response, _ := cached.Call(ctx, userMessage("input"), 0.1)
+	if !response.Cached || inner.calls.Load() != int64(len(calls)) {
+		t.Errorf("expected a hit, got cached=%t after %d calls", response.Cached, inner.calls.Load())
+	}
+	if response.Usage.Total != 150 {
+		t.Errorf("expected the original usage, got %+v", response.Usage)
+	}
+}
+
+func TestCachingProvider_DoesNotCacheFailures(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{})
+
+	inner.fail.Store(true)
+	if _, err := cached.Call(context.Background(), userMessage("input"), 0.1); err == nil {
+		t.Fatal("expected the inner error")
+	}
+	inner.fail.Store(false)
+	inner.truncated.Store(true)
+	if response, _ := cached.Call(context.Background(), userMessage("input"), 0.1); !response.Truncated {
+		t.Fatal("expected a truncated response")
+	}
+	if cached.Len() != 0 {
+		t.Errorf("expected nothing cached, got %d entries", cached.Len())
+	}
+
+	inner.truncated.Store(false)
+	_, _ = cached.Call(context.Background(), userMessage("input"), 0.1)
+	if inner.calls.Load() != 3 || cached.Len() != 1 {
+		t.Errorf("expected the successful response cached, got %d calls and %d entries", inner.calls.Load(), cached.Len())
+	}
+}
+
+func TestCachingProvider_Eviction(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{MaxEntries: 2})
+	ctx := context.Background()
+
+	_, _ = cached.Call(ctx, userMessage("a"), 0.1)
+	_, _ = cached.Call(ctx, userMessage("b"), 0.1)
+	_, _ = cached.Call(ctx, userMessage("a"), 0.1) // a becomes most recently used
+	_, _ = cached.Call(ctx, userMessage("c"), 0.1) // evicts b
+
+	if cached.Len() != 2 || inner.calls.Load() != 3 {
+		t.Fatalf("expected 2 entries after 3 calls, got %d entries after %d calls", cached.Len(), inner.calls.Load())
+	}
+	if response, _ := cached.Call(ctx, userMessage("a"), 0.1); !response.Cached {
+		t.Error("expected a to stay cached")
+	}
+	if response, _ := cached.Call(ctx, userMessage("b"), 0.1); response.Cached {
+		t.Error("expected b evicted")
+	}
+
+	cached.Clear()
+	if cached.Len() != 0 {
+		t.Errorf("expected an empty cache, got %d entries", cached.Len())
+	}
+}
+
+func TestCachingProvider_TTL(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{TTL: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	_, _ = cached.Call(ctx, userMessage("input"), 0.1)
+	if response, _ := cached.Call(ctx, userMessage("input"), 0.1); !response.Cached {
+		t.Error("expected a hit before the TTL")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if response, _ := cached.Call(ctx, userMessage("input"), 0.1); response.Cached {
+		t.Error("expected a miss after the TTL")
+	}
+	if inner.calls.Load() != 2 {
+		t.Errorf("expected 2 inner calls, got %d", inner.calls.Load())
+	}
+}
+
+func TestCachingProvider_CacheHitHook(t *testing.T) {
+	cached := NewCachingProvider(newCountingProvider(), CacheConfig{})
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		provider   string
+		requestIDs []string
+	)
+	wg.Add(1)
+	listener := capitan.Hook(CacheHit, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		provider, _ = ProviderKey.From(e)
+		id, _ := RequestIDKey.From(e)
+		requestIDs = append(requestIDs, id)
+	})
+	defer listener.Close()
+
+	_, _ = cached.Call(ContextWithRequestID(context.Background(), "req-1"), userMessage("input"), 0.1)
+	_, _ = cached.Call(ContextWithRequestID(context.Background(), "req-2"), userMessage("input"), 0.1)
+
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if provider != "counting" || len(requestIDs) != 1 || requestIDs[0] != "req-2" {
+		t.Errorf("expected one hit for req-2, got %q %v", provider, requestIDs)
+	}
+}
+
+func TestCachingProvider_Tools(t *testing.T) {
+	tools := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+	cached := NewCachingProvider(tools, CacheConfig{})
+	ctx := context.Background()
+
+	first, err := cached.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+	if err != nil || len(first.ToolCalls) != 1 {
+		t.Fatalf("expected tool calls, got %+v, %v", first, err)
+	}
+	second, _ := cached.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+	if !second.Cached || len(tools.tools) != 1 {
+		t.Errorf("expected a cached tool call response, got cached=%t after %d calls", second.Cached, len(tools.tools))
+	}
+
+	plain := NewCachingProvider(NewMockProviderWithName("plain"), CacheConfig{})
+	_, err = plain.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+	var unsupported *ToolsUnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Provider != "plain" {
+		t.Errorf("expected ToolsUnsupportedError, got %v", err)
+	}
+}
+
+func TestCachingProvider_CopiesResponses(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("logprobs", func(t *testing.T) {
+		inner := &usageProvider{
+			content:  `{"decision": true}`,
+			logprobs: []TokenLogprob{{Token: "true", Logprob: -0.1, TopLogprobs: []TokenLogprob{{Token: "true", Logprob: -0.1}}}},
+		}
+		cached := NewCachingProvider(inner, CacheConfig{})
+
+		first, _ := cached.Call(ctx, userMessage("printer offline"), 0.1)
+		inner.logprobs[0].Token = "provider"
+		first.Logprobs[0].TopLogprobs[0].Token = "first"
+
+		second, _ := cached.Call(ctx, userMessage("printer offline"), 0.1)
+		if !second.Cached || second.Logprobs[0].Token != "true" || second.Logprobs[0].TopLogprobs[0].Token != "true" {
+			t.Fatalf("expected the cached logprobs unchanged, got %+v", second.Logprobs)
+		}
+		second.Logprobs[0].TopLogprobs[0].Token = "second"
+
+		third, _ := cached.Call(ctx, userMessage("printer offline"), 0.1)
+		if third.Logprobs[0].TopLogprobs[0].Token != "true" {
+			t.Errorf("expected a hit to be unaffected by an earlier hit, got %+v", third.Logprobs)
+		}
+	})
+
+	t.Run("tool calls", func(t *testing.T) {
+		cached := NewCachingProvider(&toolProvider{MockProvider: NewMockProviderWithName("tools")}, CacheConfig{})
+
+		first, _ := cached.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+		first.ToolCalls[0].Name = "first"
+		first.ToolCalls[0].Arguments[2] = 'x'
+
+		second, _ := cached.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+		if !second.Cached || second.ToolCalls[0].Name != "lookup_order" || string(second.ToolCalls[0].Arguments) != `{"id":"A-1"}` {
+			t.Fatalf("expected the cached tool calls unchanged, got %+v", second.ToolCalls)
+		}
+		second.ToolCalls[0].Arguments[2] = 'y'
+
+		third, _ := cached.CallWithTools(ctx, userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+		if string(third.ToolCalls[0].Arguments) != `{"id":"A-1"}` {
+			t.Errorf("expected a hit to be unaffected by an earlier hit, got %s", third.ToolCalls[0].Arguments)
+		}
+	})
+}
+
+func TestCachingProvider_Capabilities(t *testing.T) {
+	capable := &capableProvider{MockProvider: NewMockProviderWithName("vision"), capabilities: Capabilities{Vision: true, Model: "gpt-4o"}}
+	if got := NewCachingProvider(capable, CacheConfig{}).Capabilities(); got != capable.capabilities {
+		t.Errorf("expected inner capabilities, got %+v", got)
+	}
+	if got := NewCachingProvider(NewMockProviderWithName("plain"), CacheConfig{}).Capabilities(); got != (Capabilities{}) {
+		t.Errorf("expected no capabilities, got %+v", got)
+	}
+
+	estimating := NewCachingProvider(&countingEstimator{tokens: 42}, CacheConfig{})
+	if got := estimating.EstimateMessages(nil); got != 42 {
+		t.Errorf("expected inner estimate, got %d", got)
+	}
+}
+
+func TestCachingProvider_Concurrent(t *testing.T) {
+	inner := newCountingProvider()
+	cached := NewCachingProvider(inner, CacheConfig{MaxEntries: 16})
+	synapse, _ := Binary("Is this a duplicate ticket?", cached)
+
+	// Warm the cache so every concurrent call hits
+	if _, err := synapse.Fire(context.Background(), NewSession(), "printer offline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := "printer offline"
+			if i%5 == 0 {
+				input = "ticket " + string(rune('a'+i%26))
+			}
+			if _, err := synapse.Fire(context.Background(), NewSession(), input); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The warm-up call and one per distinct ticket
+	if inner.calls.Load() != 11 || cached.Len() != 11 {
+		t.Errorf("expected 11 inner calls and entries, got %d calls and %d entries", inner.calls.Load(), cached.Len())
+	}
+}
+
+func TestCachingProvider_Close(t *testing.T) {
+	inner := newCountingProvider()
+	cache := NewCachingProvider(inner, CacheConfig{})
+	if _, err := cache.Call(context.Background(), userMessage("input"), 0.1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inner.Closed() || cache.Len() != 0 {
+		t.Errorf("expected the inner provider closed and the cache cleared, got closed=%v len=%d", inner.Closed(), cache.Len())
+	}
+
+	_, err := cache.Call(context.Background(), userMessage("input"), 0.1)
+	if !errors.Is(err, ErrProviderClosed) || inner.calls.Load() != 1 {
+		t.Errorf("expected ErrProviderClosed without a call, got %v after %d calls", err, inner.calls.Load())
+	}
+	if err := cache.Close(); err != nil {
+		t.Errorf("expected closing twice to be safe, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ChainStep is a single step of a SynapseChain.
+// Create steps with Step or StepWith so input and output types are checked
+// when the chain is built.
+type ChainStep struct {
+	name    string
+	inType  reflect.Type
+	outType reflect.Type
+	run     func(ctx context.Context, session *Session, input any) (any, error)
+}
+
+// Name returns the step name.
+func (s ChainStep) Name() string {
+	return s.name
+}
+
+// Step creates a chain step that passes the previous step's output directly
+// to fire. For the first step, the input is the value given to Run.
+//
+// Example:
+//
+//	zyn.Step("classify", classifier.FireWithDetails)
+func Step[In, Out any](name string, fire func(context.Context, *Session, In) (Out, error)) ChainStep {
+	return ChainStep{
+		name:    name,
+		inType:  reflect.TypeFor[In](),
+		outType: reflect.TypeFor[Out](),
+		run: func(ctx context.Context, session *Session, input any) (any, error) {
+			typed, ok := input.(In)
+			if !ok {
+				return nil, fmt.Errorf("expected input of type %v, got %T", reflect.TypeFor[In](), input)
+			}
+			return fire(ctx, session, typed)
+		},
+	}
+}
+
+// StepWith creates a chain step that maps the previous step's output to
+// fire's input. A mapping error fails the step without calling fire.
+//
+// Example:
+//
+//	zyn.StepWith("extract",
+//	    func(c zyn.ClassificationResponse) (string, error) { return ticket + "\nCategory: " + c.Primary, nil },
+//	    extractor.Fire,
+//	)
+func StepWith[Prev, In, Out any](name string, mapInput func(Prev) (In, error), fire func(context.Context, *Session, In) (Out, error)) ChainStep {
+	return ChainStep{
+		name:    name,
+		inType:  reflect.TypeFor[Prev](),
+		outType: reflect.TypeFor[Out](),
+		run: func(ctx context.Context, session *Session, input any) (any, error) {
+			prev, ok := input.(Prev)
+			if !ok {
+				return nil, fmt.Errorf("expected input of type %v, got %T", reflect.TypeFor[Prev](), input)
+			}
+			mapped, err := mapInput(prev)
+			if err != nil {
+				return nil, fmt.Errorf("failed to map input: %w", err)
+			}
+			return fire(ctx, session, mapped)
+		},
+	}
+}
+
+// SynapseChain runs steps sequentially on a shared session, feeding each
+// step's output to the next.
+type SynapseChain struct {
+	steps []ChainStep
+}
+
+// Chain composes steps into a SynapseChain.
+// Returns an error if no steps are given, a step name is empty or repeated,
+// or a step's input type does not accept the previous step's output type.
+//
+// Example:
+//
+//	chain, err := zyn.Chain(
+//	    zyn.Step("classify", classifier.FireWithDetails),
+//	    zyn.StepWith("extract", toTicketText, extractor.Fire),
+//	    zyn.Step("convert", converter.Fire),
+//	)
+//	result, err := chain.Run(ctx, session, "customer email text")
+func Chain(steps ...ChainStep) (*SynapseChain, error) {
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("chain requires at least one step")
+	}
+
+	names := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		if step.run == nil {
+			return nil, fmt.Errorf("chain step %d: not created with Step or StepWith", i)
+		}
+		if step.name == "" {
+			return nil, fmt.Errorf("chain step %d: name is required", i)
+		}
+		if names[step.name] {
+			return nil, fmt.Errorf("chain step %d: duplicate name %q", i, step.name)
+		}
+		names[step.name] = true
+
+		if i > 0 {
+			prev := steps[i-1]
+			if !prev.outType.AssignableTo(step.inType) {
+				return nil, fmt.Errorf("chain step %d (%s): input type %v does not accept output type %v of step %q",
+					i, step.name, step.inType, prev.outType, prev.name)
+			}
+		}
+	}
+
+	return &SynapseChain{steps: steps}, nil
+}
+
+// Steps returns the names of the chain's steps in order.
+func (c *SynapseChain) Steps() []string {
+	names := make([]string, len(c.steps))
+	for i, step := range c.steps {
+		names[i] = step.name
+	}
+	return names
+}
+
+// Run executes the chain's steps in order on session, starting with input.
+// Token usage is aggregated across steps from the calls each step made,
+// including calls that failed.
+//
+// If a step fails, Run returns a *ChainStepError naming the step, together
+// with a result holding the outputs of the steps that completed.
+func (c *SynapseChain) Run(ctx context.Context, session *Session, input any) (*ChainResult, error) {
+	result := &ChainResult{Outputs: make([]StepOutput, 0, len(c.steps))}
+	current := input
+
+	for i, step := range c.steps {
+		if err := ctx.Err(); err != nil {
+			return result, &ChainStepError{Step: step.name, Index: i, Err: err, Result: result}
+		}
+
+		start := time.Now()
+		stepCtx, calls := withCallRecorder(ctx)
+
+		output, err := step.run(stepCtx, session, current)
+		envelope := calls.summary()
+		result.addUsage(*envelope.Usage)
+		if err != nil {
+			return result, &ChainStepError{Step: step.name, Index: i, Err: err, Result: result}
+		}
+
+		envelope.Value = output
+		envelope.Duration = time.Since(start)
+		result.Outputs = append(result.Outputs, StepOutput{Name: step.name, Result: envelope})
+		current = output
+	}
+
+	return result, nil
+}
+
+// StepOutput is the output of one completed chain step.
+// The embedded Result holds the step's output as Value, the token usage and
+// duration of the whole step, the attempts of all synapse calls made by the
+// step, and the request ID, provider, and raw body of its last call.
+type StepOutput struct {
+	Name string // Step name
+	Result[any]
+}
+
+// ChainResult holds the outputs of a chain run.
+type ChainResult struct {
+	Outputs []StepOutput // Completed step outputs in order
+	Usage   TokenUsage   // Token usage of every call made by the steps, including failed calls
+}
+
+// addUsage adds usage to the aggregate.
+func (r *ChainResult) addUsage(usage TokenUsage) {
+	addUsage(&r.Usage, &usage)
+}
+
+// Final returns the output of the last completed step, or nil if none completed.
+func (r *ChainResult) Final() any {
+	if len(r.Outputs) == 0 {
+		return nil
+	}
+	return r.Outputs[len(r.Outputs)-1].Value
+}
+
+// Output returns the output of the named step.
+func (r *ChainResult) Output(name string) (any, bool) {
+	for _, output := range r.Outputs {
+		if output.Name == name {
+			return output.Value, true
+		}
+	}
+	return nil, false
+}
+
+// ChainOutput returns the output of the named step as T.
+// Returns false if the step did not complete or its output is not a T.
+func ChainOutput[T any](result *ChainResult, name string) (T, bool) {
+	var zero T
+	value, ok := result.Output(name)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return zero, false
+	}
+	return typed, true
+}
+
+// ChainStepError reports a failed chain step.
+// Result holds the outputs of the steps that completed before the failure.
+type ChainStepError struct {
+	Step   string       // Name of the failed step
+	Index  int          // Position of the failed step
+	Err    error        // Underlying error
+	Result *ChainResult // Outputs of earlier steps
+}
+
+// Error implements the error interface.
+func (e *ChainStepError) Error() string {
+	return fmt.Sprintf("chain step %d (%s) failed: %v", e.Index, e.Step, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ChainStepError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,291 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type chainTicket struct {
+	Customer string `json:"customer"`
+	Issue    string `json:"issue"`
+}
+
+func (t chainTicket) Validate() error {
+	if t.Issue == "" {
+		return fmt.Errorf("issue required")
+	}
+	return nil
+}
+
+func newChainSynapses(t *testing.T, provider Provider) (*ClassificationSynapse, *ExtractionSynapse[chainTicket], *TransformSynapse) {
+	t.Helper()
+	classifier, err := Classification("What kind of message is this?", []string{"support", "sales"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classifier: %v", err)
+	}
+	extractor, err := Extract[chainTicket]("support ticket", provider)
+	if err != nil {
+		t.Fatalf("failed to create extractor: %v", err)
+	}
+	summarizer, err := Transform("summarize the ticket", provider)
+	if err != nil {
+		t.Fatalf("failed to create transformer: %v", err)
+	}
+	return classifier, extractor, summarizer
+}
+
+func chainResponder(failOn string) func(string, float32) (string, error) {
+	return func(prompt string, _ float32) (string, error) {
+		switch {
+		case strings.Contains(prompt, "What kind of message"):
+			if failOn == "classify" {
+				return "", fmt.Errorf("classify unavailable")
+			}
+			return `{"primary": "support", "secondary": "", "confidence": 0.9, "reasoning": ["asks for help"]}`, nil
+		case strings.Contains(prompt, "support ticket"):
+			if failOn == "extract" {
+				return "", fmt.Errorf("extract unavailable")
+			}
+			return `{"customer": "Ada", "issue": "cannot log in"}`, nil
+		default:
+			if failOn == "summarize" {
+				return "", fmt.Errorf("summarize unavailable")
+			}
+			return `{"output": "Ada cannot log in", "confidence": 0.8, "changes": ["condensed"], "reasoning": ["summary"]}`, nil
+		}
+	}
+}
+
+func buildTicketChain(t *testing.T, provider Provider) *SynapseChain {
+	t.Helper()
+	classifier, extractor, summarizer := newChainSynapses(t, provider)
+	var email string
+	chain, err := Chain(
+		Step("classify", func(ctx context.Context, session *Session, text string) (ClassificationResponse, error) {
+			email = text
+			return classifier.FireWithDetails(ctx, session, text)
+		}),
+		StepWith("extract",
+			func(c ClassificationResponse) (string, error) {
+				if c.Primary != "support" {
+					return "", fmt.Errorf("not a support message: %s", c.Primary)
+				}
+				return email, nil
+			},
+			extractor.Fire,
+		),
+		StepWith("summarize",
+			func(ticket chainTicket) (string, error) {
+				return ticket.Customer + ": " + ticket.Issue, nil
+			},
+			summarizer.Fire,
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to build chain: %v", err)
+	}
+	return chain
+}
+
+func TestChain_Run(t *testing.T) {
+	chain := buildTicketChain(t, NewMockProviderWithCallback(chainResponder("")))
+	session := NewSession()
+
+	result, err := chain.Run(context.Background(), session, "Hi, I cannot log in. - Ada")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := chain.Steps(); strings.Join(got, ",") != "classify,extract,summarize" {
+		t.Errorf("unexpected steps: %v", got)
+	}
+	if len(result.Outputs) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(result.Outputs))
+	}
+	if result.Final() != "Ada cannot log in" {
+		t.Errorf("unexpected final output: %v", result.Final())
+	}
+
+	classification, ok := ChainOutput[ClassificationResponse](result, "classify")
+	if !ok || classification.Primary != "support" {
+		t.Errorf("unexpected classify output: %+v (found %v)", classification, ok)
+	}
+	ticket, ok := ChainOutput[chainTicket](result, "extract")
+	if !ok || ticket.Customer != "Ada" {
+		t.Errorf("unexpected extract output: %+v (found %v)", ticket, ok)
+	}
+	if _, ok := ChainOutput[string](result, "extract"); ok {
+		t.Error("expected type mismatch to report false")
+	}
+	if _, ok := result.Output("missing"); ok {
+		t.Error("expected missing step to report false")
+	}
+
+	for _, output := range result.Outputs {
+		if output.Usage.Total != 150 {
+			t.Errorf("step %s: expected 150 total tokens, got %d", output.Name, output.Usage.Total)
+		}
+	}
+	if result.Usage.Total != 450 || result.Usage.Prompt != 300 || result.Usage.Completion != 150 {
+		t.Errorf("unexpected aggregate usage: %+v", result.Usage)
+	}
+
+	// All steps share the session
+	if session.Len() != 6 {
+		t.Errorf("expected 6 session messages, got %d", session.Len())
+	}
+}
+
+func TestChain_StepFailure(t *testing.T) {
+	tests := []struct {
+		failOn    string
+		index     int
+		completed int
+	}{
+		{failOn: "classify", index: 0, completed: 0},
+		{failOn: "extract", index: 1, completed: 1},
+		{failOn: "summarize", index: 2, completed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.failOn, func(t *testing.T) {
+			chain := buildTicketChain(t, NewMockProviderWithCallback(chainResponder(tt.failOn)))
+
+			result, err := chain.Run(context.Background(), NewSession(), "Hi, I cannot log in. - Ada")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			var stepErr *ChainStepError
+			if !errors.As(err, &stepErr) {
+				t.Fatalf("expected ChainStepError, got %T", err)
+			}
+			if stepErr.Step != tt.failOn || stepErr.Index != tt.index {
+				t.Errorf("expected step %d (%s), got %d (%s)", tt.index, tt.failOn, stepErr.Index, stepErr.Step)
+			}
+			if !strings.Contains(err.Error(), tt.failOn+" unavailable") {
+				t.Errorf("expected underlying error in message, got %q", err.Error())
+			}
+			if stepErr.Result != result || len(result.Outputs) != tt.completed {
+				t.Errorf("expected %d completed outputs preserved, got %d", tt.completed, len(result.Outputs))
+			}
+			if result.Usage.Total != 150*tt.completed {
+				t.Errorf("expected usage of completed steps, got %+v", result.Usage)
+			}
+		})
+	}
+}
+
+func TestChain_FailedStepUsage(t *testing.T) {
+	responder := chainResponder("")
+	provider := NewMockProviderWithCallback(func(prompt string, temperature float32) (string, error) {
+		if strings.Contains(prompt, "summarize the ticket") {
+			return `{"output": "Ada cannot log in", "confidence": 5, "changes": ["condensed"], "reasoning": ["summary"]}`, nil
+		}
+		return responder(prompt, temperature)
+	})
+	chain := buildTicketChain(t, provider)
+
+	result, err := chain.Run(context.Background(), NewSession(), "Hi, I cannot log in. - Ada")
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+	if len(result.Outputs) != 2 || result.Usage.Total != 450 {
+		t.Errorf("expected the rejected response's usage counted, got %d outputs and %+v", len(result.Outputs), result.Usage)
+	}
+}
+
+func TestChain_MappingError(t *testing.T) {
+	provider := NewMockProviderWithCallback(func(prompt string, temp float32) (string, error) {
+		if strings.Contains(prompt, "What kind of message") {
+			return `{"primary": "sales", "secondary": "", "confidence": 0.9, "reasoning": ["pricing"]}`, nil
+		}
+		return chainResponder("")(prompt, temp)
+	})
+	chain := buildTicketChain(t, provider)
+
+	result, err := chain.Run(context.Background(), NewSession(), "How much is the pro plan?")
+	var stepErr *ChainStepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "extract" {
+		t.Fatalf("expected extract step error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "not a support message: sales") {
+		t.Errorf("expected mapping error in message, got %q", err.Error())
+	}
+	if len(result.Outputs) != 1 {
+		t.Errorf("expected classify output preserved, got %d outputs", len(result.Outputs))
+	}
+}
+
+func TestChain_CanceledContext(t *testing.T) {
+	chain := buildTicketChain(t, NewMockProviderWithCallback(chainResponder("")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := chain.Run(ctx, NewSession(), "Hi")
+	var stepErr *ChainStepError
+	if !errors.As(err, &stepErr) || stepErr.Index != 0 {
+		t.Fatalf("expected step 0 error, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestChain_InputTypeMismatch(t *testing.T) {
+	chain, err := Chain(Step("double", func(_ context.Context, _ *Session, n int) (int, error) {
+		return n * 2, nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = chain.Run(context.Background(), NewSession(), "not a number")
+	if err == nil || !strings.Contains(err.Error(), "expected input of type int, got string") {
+		t.Errorf("expected input type error, got %v", err)
+	}
+}
+
+func TestChain_Validation(t *testing.T) {
+	identity := func(_ context.Context, _ *Session, s string) (string, error) { return s, nil }
+	count := func(_ context.Context, _ *Session, s string) (int, error) { return len(s), nil }
+
+	tests := []struct {
+		name  string
+		steps []ChainStep
+		want  string
+	}{
+		{name: "empty", steps: nil, want: "at least one step"},
+		{name: "zero step", steps: []ChainStep{{}}, want: "not created with Step or StepWith"},
+		{name: "unnamed", steps: []ChainStep{Step("", identity)}, want: "name is required"},
+		{name: "duplicate", steps: []ChainStep{Step("a", identity), Step("a", identity)}, want: `duplicate name "a"`},
+		{
+			name:  "type mismatch",
+			steps: []ChainStep{Step("count", count), Step("echo", identity)},
+			want:  `chain step 1 (echo): input type string does not accept output type int of step "count"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Chain(tt.steps...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	mapped, err := Chain(Step("count", count), StepWith("format", func(n int) (string, error) {
+		return fmt.Sprint(n), nil
+	}, identity))
+	if err != nil {
+		t.Fatalf("expected mapped chain to build, got %v", err)
+	}
+	result, err := mapped.Run(context.Background(), NewSession(), "hello")
+	if err != nil || result.Final() != "5" {
+		t.Errorf("expected final output 5, got %v (err %v)", result.Final(), err)
+	}
+}
@@ -0,0 +1,259 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoobzio/pipz"
+)
+
+// ChooseInput contains rich input structure for choosing an item.
+type ChooseInput struct {
+	Items       []string // The items to choose from
+	Context     string   // Optional context, such as who the choice is for
+	Temperature float32  // LLM temperature setting for this specific request
+}
+
+// ChooseResponse contains the response from a choose synapse.
+type ChooseResponse struct {
+	SelectedIndex int      `json:"selected_index"`  // Index of the chosen item in the input
+	Selected      string   `json:"selected"`        // The chosen item
+	RunnerUpIndex int      `json:"runner_up_index"` // Index of the second-best item; -1 if there is none
+	Confidence    float64  `json:"confidence"`      // 0.0 to 1.0 confidence score
+	Reasoning     []string `json:"reasoning"`       // Explanation of the choice
+}
+
+// Validate checks if the response is valid.
+func (r ChooseResponse) Validate() error {
+	if r.SelectedIndex < 0 {
+		return fmt.Errorf("selected index must not be negative, got %d", r.SelectedIndex)
+	}
+	if r.RunnerUpIndex < -1 || r.RunnerUpIndex == r.SelectedIndex {
+		return fmt.Errorf("runner-up index must be another item or -1, got %d", r.RunnerUpIndex)
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// choice is a choose response as the model gives it, naming items by their
+// number in the prompt's list, from 1.
+type choice struct {
+	Selected   int      `json:"selected"`   // Number of the chosen item
+	RunnerUp   int      `json:"runner_up"`  // Number of the second-best item; 0 if there is none
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the choice
+}
+
+// Validate checks if the response is valid. The numbers are checked against
+// the list by the synapse.
+func (c choice) Validate() error {
+	if c.Confidence < 0 || c.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", c.Confidence)
+	}
+	if len(c.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// validateChoice checks that the numbers of a choice are in the prompt's
+// list, and that the runner-up is not the chosen item.
+func validateChoice(prompt *Prompt, response choice) error {
+	if response.Selected < 1 || response.Selected > len(prompt.Items) {
+		return fmt.Errorf("selected must be an item number from 1 to %d, got %d", len(prompt.Items), response.Selected)
+	}
+	if response.RunnerUp < 0 || response.RunnerUp > len(prompt.Items) {
+		return fmt.Errorf("runner_up must be an item number from 1 to %d or 0, got %d", len(prompt.Items), response.RunnerUp)
+	}
+	if response.RunnerUp == response.Selected {
+		return fmt.Errorf("runner_up must differ from selected, both %d", response.Selected)
+	}
+	return nil
+}
+
+// chooseResponse converts a choice among items to a ChooseResponse with
+// indices into items.
+func chooseResponse(items []string, c choice) ChooseResponse {
+	return ChooseResponse{
+		SelectedIndex: c.Selected - 1,
+		Selected:      items[c.Selected-1],
+		RunnerUpIndex: c.RunnerUp - 1,
+		Confidence:    c.Confidence,
+		Reasoning:     c.Reasoning,
+	}
+}
+
+// ChooseSynapse selects the single best item from a list.
+type ChooseSynapse struct {
+	criteria   string
+	defaults   ChooseInput
+	skipSingle bool // Select a lone item without calling the provider
+	base       *Base[ChooseInput, choice]
+}
+
+// NewChoose creates a new choose synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewChoose(criteria string, provider Provider, opts ...Option) (*ChooseSynapse, error) {
+	synapse := &ChooseSynapse{criteria: criteria}
+
+	base, err := NewSynapse(SynapseConfig[ChooseInput, choice]{
+		Type:        "choose",
+		Temperature: DefaultTemperatureAnalytical,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = validateChoice
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (c *ChooseSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.base.GetPipeline()
+}
+
+// WithDefaults creates a new Choose with default input values.
+// These are merged with user input at execution time.
+func (c *ChooseSynapse) WithDefaults(defaults ChooseInput) *ChooseSynapse {
+	c.defaults = defaults
+	return c
+}
+
+// SkipSingleItem returns a copy of the synapse that selects a list of one
+// item without calling the provider when skip is set. By default the
+// provider is still called, so the call is recorded in the session and hooks
+// like any other; when skipped, the item is selected with confidence 1 and
+// the session is left unchanged. The synapse itself is not modified, so the
+// copy can be made while it is in use.
+func (c *ChooseSynapse) SkipSingleItem(skip bool) *ChooseSynapse {
+	skipping := *c
+	skipping.skipSingle = skip
+	return &skipping
+}
+
+// Fire chooses the best of items and returns it.
+func (c *ChooseSynapse) Fire(ctx context.Context, session *Session, items []string) (string, error) {
+	response, err := c.FireWithInput(ctx, session, ChooseInput{Items: items})
+	if err != nil {
+		return "", err
+	}
+	return response.Selected, nil
+}
+
+// FireResult chooses the best of items and returns it in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (c *ChooseSynapse) FireResult(ctx context.Context, session *Session, items []string) (Result[string], error) {
+	result, err := c.execute(ctx, session, ChooseInput{Items: items})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Selected), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (c *ChooseSynapse) FireWithInput(ctx context.Context, session *Session, input ChooseInput) (ChooseResponse, error) {
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// Items default to the non-empty lines of the input text.
+// The returned Validator is a ChooseResponse.
+func (c *ChooseSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := c.FireWithInput(ctx, session, ChooseInput{
+		Items:       input.items(),
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and chooses an item, converting the
+// model's item numbers to indices into the items.
+func (c *ChooseSynapse) execute(ctx context.Context, session *Session, input ChooseInput) (Result[ChooseResponse], error) {
+	merged := c.mergeInputs(input)
+	if len(merged.Items) == 0 {
+		return Result[ChooseResponse]{Provider: c.base.service.providerName},
+			fmt.Errorf("%w: choose synapse needs items", ErrInvalidPrompt)
+	}
+	if len(merged.Items) == 1 && c.skipSingle {
+		return Result[ChooseResponse]{
+			Value: ChooseResponse{
+				Selected:      merged.Items[0],
+				RunnerUpIndex: -1,
+				Confidence:    1,
+				Reasoning:     []string{"only one item to choose from"},
+			},
+			Provider: c.base.service.providerName,
+		}, nil
+	}
+
+	result, err := c.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+	if err != nil {
+		return withValue(result, ChooseResponse{}), err
+	}
+	return withValue(result, chooseResponse(merged.Items, result.Value)), nil
+}
+
+// mergeInputs combines defaults with user input.
+func (c *ChooseSynapse) mergeInputs(input ChooseInput) ChooseInput {
+	merged := c.defaults
+
+	if len(input.Items) > 0 {
+		merged.Items = input.Items
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (c *ChooseSynapse) buildPrompt(input ChooseInput) *Prompt {
+	runnerUp := "runner_up: the number of the second-best item"
+	if len(input.Items) == 1 {
+		runnerUp = "runner_up: 0, as there is only one item"
+	}
+
+	return &Prompt{
+		Task:    fmt.Sprintf("Choose the best item by %s", c.criteria),
+		Items:   input.Items,
+		Context: input.Context,
+		Constraints: []string{
+			fmt.Sprintf("selected: the number of the chosen item as listed, from 1 to %d", len(input.Items)),
+			runnerUp,
+			"identify items by number only, never by their text",
+			"confidence: 0.0 to 1.0, lower when the runner-up is nearly as good",
+			"reasoning: ordered steps explaining the choice",
+		},
+	}
+}
+
+// Choose creates a new choose synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	picker, err := Choose("best fit for a first-time user", provider)
+//	plan, err := picker.Fire(ctx, session, []string{"Starter", "Team", "Enterprise"})
+//	// plan: "Starter"
+func Choose(criteria string, provider Provider, opts ...Option) (*ChooseSynapse, error) {
+	return NewChoose(criteria, provider, opts...)
+}
@@ -0,0 +1,227 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// plans are the items of the choose tests.
+var plans = []string{"Starter", "Team", "Enterprise"}
+
+func TestChooseResponse_Validate(t *testing.T) {
+	valid := ChooseResponse{SelectedIndex: 0, Selected: "Starter", RunnerUpIndex: -1, Confidence: 1, Reasoning: []string{"r"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	negative := valid
+	negative.SelectedIndex = -1
+	if err := negative.Validate(); err == nil {
+		t.Error("expected negative selected index rejected")
+	}
+	same := valid
+	same.RunnerUpIndex = 0
+	if err := same.Validate(); err == nil {
+		t.Error("expected runner-up equal to selected rejected")
+	}
+	unsure := valid
+	unsure.Confidence = 1.5
+	if err := unsure.Validate(); err == nil {
+		t.Error("expected confidence out of range rejected")
+	}
+}
+
+func TestValidateChoice(t *testing.T) {
+	prompt := &Prompt{Items: plans}
+	for _, tt := range []struct {
+		name    string
+		choice  choice
+		wantErr bool
+	}{
+		{"in range", choice{Selected: 3, RunnerUp: 1}, false},
+		{"no runner-up", choice{Selected: 1}, false},
+		{"selected zero", choice{Selected: 0, RunnerUp: 1}, true},
+		{"selected past the end", choice{Selected: 4, RunnerUp: 1}, true},
+		{"runner-up past the end", choice{Selected: 1, RunnerUp: 4}, true},
+		{"runner-up is selected", choice{Selected: 2, RunnerUp: 2}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateChoice(prompt, tt.choice); (err != nil) != tt.wantErr {
+				t.Errorf("validateChoice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChooseSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"selected": 2, "runner_up": 1, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`, nil
+	})
+	picker, err := Choose("best fit for a small team", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plan, err := picker.Fire(context.Background(), NewSession(), plans)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan != "Team" {
+		t.Errorf("expected Team, got %q", plan)
+	}
+	for _, want := range []string{"Choose the best item by best fit for a small team", "1. Starter", "3. Enterprise", "from 1 to 3"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestChooseSynapse_FireWithInput(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"selected": 3, "runner_up": 0, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`)
+	picker, _ := Choose("most features", provider)
+
+	response, err := picker.FireWithInput(context.Background(), NewSession(), ChooseInput{Items: plans})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.SelectedIndex != 2 || response.Selected != "Enterprise" || response.RunnerUpIndex != -1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("expected a valid response, got %v", err)
+	}
+}
+
+func TestChooseSynapse_OutOfRange(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"selected": 4, "runner_up": 1, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`, nil
+		}
+		return `{"selected": 1, "runner_up": 2, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`, nil
+	})
+
+	picker, _ := Choose("cheapest", provider)
+	session := NewSession()
+	_, err := picker.Fire(context.Background(), session, plans)
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected an out-of-range choice rejected, got %v", err)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected session untouched, got %d messages", session.Len())
+	}
+
+	retrying, _ := Choose("cheapest", provider, WithValidationRetry(2))
+	plan, err := retrying.Fire(context.Background(), NewSession(), plans)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan != "Starter" {
+		t.Errorf("expected Starter after a retry, got %q", plan)
+	}
+}
+
+func TestChooseSynapse_SingleItem(t *testing.T) {
+	calls := 0
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		calls++
+		prompt = p
+		return `{"selected": 1, "runner_up": 0, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`, nil
+	})
+	picker, _ := Choose("cheapest", provider)
+
+	plan, err := picker.Fire(context.Background(), NewSession(), []string{"Starter"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan != "Starter" || calls != 1 {
+		t.Errorf("expected the provider called by default, got %q in %d calls", plan, calls)
+	}
+	if !strings.Contains(prompt, "runner_up: 0") {
+		t.Errorf("expected prompt to ask for no runner-up, got %s", prompt)
+	}
+
+	skipping := picker.SkipSingleItem(true)
+	session := NewSession()
+	response, err := skipping.FireWithInput(context.Background(), session, ChooseInput{Items: []string{"Starter"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || session.Len() != 0 {
+		t.Errorf("expected no provider call, got %d calls and %d messages", calls, session.Len())
+	}
+	if response.Selected != "Starter" || response.SelectedIndex != 0 || response.RunnerUpIndex != -1 || response.Confidence != 1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("expected a valid response, got %v", err)
+	}
+
+	if _, err := picker.Fire(context.Background(), NewSession(), []string{"Starter"}); err != nil || calls != 2 {
+		t.Errorf("expected the original synapse to still call the provider, got %d calls and %v", calls, err)
+	}
+}
+
+func TestChooseSynapse_NoItems(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"selected": 1, "runner_up": 0, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`)
+	picker, _ := Choose("cheapest", provider)
+
+	result, err := picker.FireResult(context.Background(), NewSession(), nil)
+	if !errors.Is(err, ErrInvalidPrompt) || result.Provider == "" {
+		t.Errorf("expected no items rejected before the call, got %v", err)
+	}
+}
+
+func TestChooseSynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"selected": 2, "runner_up": 3, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`)
+	picker, _ := Choose("best fit for a small team", provider)
+
+	result, err := picker.FireResult(context.Background(), NewSession(), plans)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value != "Team" || result.Usage == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestChooseSynapse_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"selected": 1, "runner_up": 2, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`, nil
+	})
+	picker, _ := Choose("cheapest", provider)
+	picker.WithDefaults(ChooseInput{Items: plans, Context: "a team of three"})
+
+	response, err := picker.FireWithInput(context.Background(), NewSession(), ChooseInput{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Selected != "Starter" || !strings.Contains(prompt, "a team of three") {
+		t.Errorf("expected defaults used, got %+v from %s", response, prompt)
+	}
+}
+
+func TestChooseSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"selected": 2, "runner_up": 1, "confidence": 0.8, "reasoning": ["cheapest option that fits"]}`)
+	picker, _ := Choose("best fit for a small team", provider)
+
+	var synapse Synapse = picker
+	response, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Items: plans})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chosen, ok := response.(ChooseResponse); !ok || chosen.Selected != "Team" {
+		t.Errorf("expected a ChooseResponse, got %#v", response)
+	}
+	if picker.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
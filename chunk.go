@@ -0,0 +1,221 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ChunkConfig configures chunked extraction over texts too long for one call.
+type ChunkConfig struct {
+	MaxChars    int // Most characters per chunk, counted in runes; required
+	Overlap     int // Characters each chunk repeats from the end of the one before, so values on a boundary are whole in one; less than MaxChars
+	Concurrency int // Maximum chunk calls in flight; values below 1 run chunks sequentially
+}
+
+// chunking is the chunked extraction setting of an extraction synapse.
+type chunking[T any] struct {
+	config ChunkConfig
+	merge  func([]T) (T, error) // Combines per-chunk values in chunk order; nil if T cannot be merged by default
+}
+
+// WithChunking makes the synapse split texts longer than config.MaxChars
+// into overlapping chunks, extract from each chunk, and merge the values with
+// merge. Texts that fit in one chunk are extracted in a single call as usual.
+//
+// merge receives the chunk values in text order. It may be nil when every
+// exported field of T is a []string, or T is itself a string slice: the
+// lists are then concatenated, dropping repeats regardless of case and
+// keeping the first spelling, so a value found in two overlapping chunks
+// appears once. For any other T, a nil merge fails each chunked extraction
+// with ErrInvalidPrompt.
+//
+// Chunks are extracted in their own sessions, with up to config.Concurrency
+// calls in flight, and each finished chunk is reported to WithProgress
+// callbacks as "chunk i/n". The first failed chunk fails the extraction. The
+// shared session receives a single exchange with the merged value and the
+// usage summed across all chunk calls.
+//
+// Example:
+//
+//	type Names struct {
+//	    People []string `json:"people"`
+//	}
+//
+//	extractor, err := Extract[Names]("people mentioned", provider)
+//	extractor.WithChunking(ChunkConfig{MaxChars: 8000, Overlap: 200, Concurrency: 4}, nil)
+//	names, err := extractor.Fire(ctx, session, longDocument)
+func (e *ExtractionSynapse[T]) WithChunking(config ChunkConfig, merge func([]T) (T, error)) *ExtractionSynapse[T] {
+	if merge == nil {
+		merge = defaultChunkMerge[T]()
+	}
+	e.chunking = &chunking[T]{config: config, merge: merge}
+	return e
+}
+
+// fireChunked extracts from the chunks of the merged input's text and merges
+// their values.
+func (e *ExtractionSynapse[T]) fireChunked(ctx context.Context, session *Session, input ExtractionInput, chunks []string) (Result[T], error) {
+	result := Result[T]{Provider: e.service.providerName}
+	if e.chunking.merge == nil {
+		return result, fmt.Errorf("%w: chunked extraction of %T needs a merge func", ErrInvalidPrompt, result.Value)
+	}
+	start := time.Now()
+
+	outcome := runBatch(ctx, chunks, e.chunking.config.Concurrency, true, "chunk",
+		func(ctx context.Context, chunk string) (Result[T], error) {
+			chunkInput := input
+			chunkInput.Text = chunk
+			return e.service.ExecuteResult(ctx, NewSession(), e.buildPrompt(chunkInput), input.Temperature)
+		})
+	if session != nil {
+		session.SetUsage(&outcome.usage)
+	}
+	if len(outcome.errors) > 0 {
+		first := outcome.errors[0]
+		result.response = first.Raw
+		return result, fmt.Errorf("chunk %d of %d: %w", first.Index+1, len(chunks), first.Err)
+	}
+	if outcome.err != nil {
+		return result, outcome.err
+	}
+
+	values := make([]T, len(chunks))
+	for i, chunkResult := range outcome.results {
+		result = combineResults(result, chunkResult)
+		values[i] = chunkResult.Value
+	}
+	// The envelope describes the chunk calls together, not the last one
+	var zero T
+	result.Value, result.Raw, result.response = zero, nil, ""
+	result.Duration = time.Since(start)
+
+	merged, err := e.chunking.merge(values)
+	if err != nil {
+		return result, fmt.Errorf("merging %d chunks: %w", len(chunks), err)
+	}
+	if err := validateValue(merged); err != nil {
+		return result, &ValidationError[T]{Value: merged, Err: fmt.Errorf("merged chunks: %w", err)}
+	}
+	result.Value = merged
+
+	if session != nil {
+		output, _ := json.Marshal(merged)
+		session.Append(RoleUser, fmt.Sprintf("Task: Extract %s\nInput: text of %d characters in %d chunks",
+			e.what, len([]rune(input.Text)), len(chunks)))
+		session.Append(RoleAssistant, string(output))
+	}
+	return result, nil
+}
+
+// splitChunks splits text into chunks of at most maxChars runes, each
+// starting overlap runes before the end of the one before. A chunk ends at
+// the last whitespace in its second half when there is one, so words are not
+// cut. Returns text alone when it fits in one chunk.
+func splitChunks(text string, maxChars, overlap int) []string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return []string{text}
+	}
+
+	var chunks []string
+	for start := 0; ; {
+		end := start + maxChars
+		if end >= len(runes) {
+			return append(chunks, string(runes[start:]))
+		}
+		for cut := end; cut > start+maxChars/2; cut-- {
+			if unicode.IsSpace(runes[cut-1]) {
+				end = cut
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[start:end]))
+		start = max(end-overlap, start+1)
+	}
+}
+
+// checkChunkConfig rejects a chunk size below one and an overlap that is
+// negative or not smaller than the chunk size.
+func checkChunkConfig(config ChunkConfig) error {
+	if config.MaxChars < 1 {
+		return fmt.Errorf("%w: chunk size must be positive, got %d", ErrInvalidPrompt, config.MaxChars)
+	}
+	if config.Overlap < 0 || config.Overlap >= config.MaxChars {
+		return fmt.Errorf("%w: chunk overlap must be from 0 to %d, got %d", ErrInvalidPrompt, config.MaxChars-1, config.Overlap)
+	}
+	return nil
+}
+
+// defaultChunkMerge returns the merge of chunk values for T when T is a
+// string slice or a struct whose exported fields are all string slices, or
+// nil for any other T.
+func defaultChunkMerge[T any]() func([]T) (T, error) {
+	t := reflect.TypeFor[T]()
+	stringSlice := reflect.TypeFor[[]string]()
+	switch {
+	case t.Kind() == reflect.Slice && t.ConvertibleTo(stringSlice):
+		return func(values []T) (T, error) {
+			var merged T
+			lists := make([]reflect.Value, len(values))
+			for i := range values {
+				lists[i] = reflect.ValueOf(values[i])
+			}
+			reflect.ValueOf(&merged).Elem().Set(mergeStringLists(lists).Convert(t))
+			return merged, nil
+		}
+	case t.Kind() == reflect.Struct:
+		var fields []int
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Type.Kind() != reflect.Slice || !field.Type.ConvertibleTo(stringSlice) {
+				return nil
+			}
+			fields = append(fields, i)
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return func(values []T) (T, error) {
+			var merged T
+			target := reflect.ValueOf(&merged).Elem()
+			for _, i := range fields {
+				lists := make([]reflect.Value, len(values))
+				for j := range values {
+					lists[j] = reflect.ValueOf(values[j]).Field(i)
+				}
+				target.Field(i).Set(mergeStringLists(lists).Convert(t.Field(i).Type))
+			}
+			return merged, nil
+		}
+	default:
+		return nil
+	}
+}
+
+// mergeStringLists concatenates string slices in order, dropping values that
+// repeat an earlier one regardless of case and surrounding space. Returns a
+// []string, nil if every list is empty.
+func mergeStringLists(lists []reflect.Value) reflect.Value {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for i := range list.Len() {
+			value := list.Index(i).String()
+			key := strings.ToLower(strings.TrimSpace(value))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, value)
+		}
+	}
+	return reflect.ValueOf(merged)
+}
@@ -0,0 +1,252 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// chunkNames is the extracted type of the chunked extraction tests.
+type chunkNames struct {
+	People []string `json:"people"`
+}
+
+func (n chunkNames) Validate() error {
+	for _, person := range n.People {
+		if person == "" {
+			return fmt.Errorf("empty name")
+		}
+	}
+	return nil
+}
+
+// nameList is a string slice extracted type.
+type nameList []string
+
+func (nameList) Validate() error { return nil }
+
+// chunkPeople are the names the chunked extraction mock finds in a chunk.
+var chunkPeople = []string{"Ada Lovelace", "Grace Hopper", "Alan Turing"}
+
+// chunkPeopleProvider returns a mock that extracts the names of chunkPeople
+// found in each prompt's input, spelling Ada's in lower case.
+func chunkPeopleProvider() Provider {
+	return NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		input := p[strings.Index(p, "Input: "):strings.Index(p, "Response JSON Schema:")]
+		names := chunkNames{People: []string{}}
+		for _, person := range chunkPeople {
+			if strings.Contains(input, person) {
+				names.People = append(names.People, person)
+			}
+		}
+		if strings.Contains(input, "ada lovelace") {
+			names.People = append(names.People, "ada lovelace")
+		}
+		data, _ := json.Marshal(names)
+		return string(data), nil
+	})
+}
+
+func TestSplitChunks(t *testing.T) {
+	if chunks := splitChunks("short text", 20, 5); len(chunks) != 1 || chunks[0] != "short text" {
+		t.Errorf("expected text that fits returned alone, got %q", chunks)
+	}
+
+	text := "one two three four five six seven eight nine ten"
+	chunks := splitChunks(text, 16, 6)
+	for i, chunk := range chunks {
+		if n := len([]rune(chunk)); n > 16 {
+			t.Errorf("chunk %d has %d characters, limit 16", i, n)
+		}
+		if i < len(chunks)-1 && !strings.HasSuffix(chunk, " ") {
+			t.Errorf("expected chunk %d to end at a space, got %q", i, chunk)
+		}
+	}
+	if !strings.HasSuffix(chunks[len(chunks)-1], "ten") || !strings.HasPrefix(chunks[0], "one") {
+		t.Errorf("expected chunks to cover the text, got %q", chunks)
+	}
+	for i := 1; i < len(chunks); i++ {
+		previous := chunks[i-1]
+		if overlap := previous[len(previous)-6:]; !strings.HasPrefix(chunks[i], overlap) {
+			t.Errorf("expected chunk %d to repeat %q, got %q", i, overlap, chunks[i])
+		}
+	}
+
+	// Runes, not bytes: each "é" is two bytes
+	unspaced := splitChunks(strings.Repeat("é", 10), 4, 1)
+	if want := []string{"éééé", "éééé", "éééé"}; !slices.Equal(unspaced, want) {
+		t.Errorf("expected rune chunks cut mid-word without spaces, got %q", unspaced)
+	}
+}
+
+func TestCheckChunkConfig(t *testing.T) {
+	if err := checkChunkConfig(ChunkConfig{MaxChars: 100, Overlap: 99}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, config := range []ChunkConfig{{}, {MaxChars: 100, Overlap: -1}, {MaxChars: 100, Overlap: 100}} {
+		if err := checkChunkConfig(config); !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("expected %+v rejected, got %v", config, err)
+		}
+	}
+}
+
+func TestDefaultChunkMerge(t *testing.T) {
+	merge := defaultChunkMerge[chunkNames]()
+	if merge == nil {
+		t.Fatal("expected a merge for a struct of string slices")
+	}
+	merged, err := merge([]chunkNames{{People: []string{"Ada", "Grace"}}, {}, {People: []string{" grace", "Alan"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(merged.People, []string{"Ada", "Grace", "Alan"}) {
+		t.Errorf("expected repeats dropped regardless of case, got %q", merged.People)
+	}
+
+	list, _ := defaultChunkMerge[nameList]()([]nameList{{"Ada"}, {"ADA", "Grace"}})
+	if !slices.Equal(list, nameList{"Ada", "Grace"}) {
+		t.Errorf("expected a string slice type merged, got %q", list)
+	}
+
+	if defaultChunkMerge[spanPerson]() != nil {
+		t.Error("expected no default merge for a struct with other fields")
+	}
+}
+
+func TestExtractionSynapse_WithChunking(t *testing.T) {
+	// Split at 40 characters with 20 of overlap, Grace Hopper appears in two
+	// chunks and Ada Lovelace in two spellings
+	document := "Ada Lovelace wrote notes. Grace Hopper built compilers. Later ada lovelace was cited and Alan Turing too."
+
+	t.Run("overlapping chunks merged", func(t *testing.T) {
+		var mu sync.Mutex
+		var phases []string
+		extractor, err := Extract[chunkNames]("people mentioned", chunkPeopleProvider(),
+			WithProgress(func(p Progress) {
+				mu.Lock()
+				defer mu.Unlock()
+				phases = append(phases, p.Phase)
+			}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		extractor.WithChunking(ChunkConfig{MaxChars: 40, Overlap: 20, Concurrency: 2}, nil)
+		chunks := splitChunks(document, 40, 20)
+		found := 0
+		for _, chunk := range chunks {
+			if strings.Contains(chunk, "Grace Hopper") {
+				found++
+			}
+		}
+		if found < 2 {
+			t.Fatalf("expected Grace Hopper in two chunks, got %q", chunks)
+		}
+
+		session := NewSession()
+		result, err := extractor.FireResult(context.Background(), session, document)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"Ada Lovelace", "Grace Hopper", "Alan Turing"}; !slices.Equal(result.Value.People, want) {
+			t.Errorf("expected each person once, got %q", result.Value.People)
+		}
+		if result.Attempts != len(chunks) || result.Usage == nil || result.Usage.Total != 150*len(chunks) {
+			t.Errorf("expected the envelope to cover %d chunk calls, got %d attempts and %+v", len(chunks), result.Attempts, result.Usage)
+		}
+		if total := session.TotalUsage().Total; total != 150*len(chunks) {
+			t.Errorf("expected usage of every chunk on the session, got %d", total)
+		}
+		if session.Len() != 2 || !strings.Contains(session.Messages()[0].Content, fmt.Sprintf("in %d chunks", len(chunks))) {
+			t.Errorf("expected a single exchange for the chunks, got %+v", session.Messages())
+		}
+		slices.Sort(phases)
+		if len(phases) != len(chunks) || phases[0] != fmt.Sprintf("chunk 1/%d", len(chunks)) {
+			t.Errorf("expected a progress report per chunk, got %q", phases)
+		}
+	})
+
+	t.Run("short text in one call", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls++
+			return `{"people": ["Ada Lovelace"]}`, nil
+		})
+		extractor, _ := Extract[chunkNames]("people mentioned", provider)
+		extractor.WithChunking(ChunkConfig{MaxChars: 1000}, nil)
+
+		names, err := extractor.Fire(context.Background(), NewSession(), "Ada Lovelace wrote notes.")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 1 || len(names.People) != 1 {
+			t.Errorf("expected a single call, got %d calls and %+v", calls, names)
+		}
+	})
+
+	t.Run("custom merge", func(t *testing.T) {
+		extractor, _ := Extract[chunkNames]("people mentioned", chunkPeopleProvider())
+		extractor.WithChunking(ChunkConfig{MaxChars: 40, Overlap: 20}, func(values []chunkNames) (chunkNames, error) {
+			var all chunkNames
+			for _, value := range values {
+				all.People = append(all.People, value.People...)
+			}
+			return all, nil
+		})
+
+		names, err := extractor.Fire(context.Background(), NewSession(), document)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(names.People, "ada lovelace") || len(names.People) <= 3 {
+			t.Errorf("expected the custom merge to keep repeats, got %q", names.People)
+		}
+	})
+
+	t.Run("no merge for other types", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls++
+			return `{"name": "Ada"}`, nil
+		})
+		extractor, _ := Extract[spanPerson]("the first person", provider)
+		extractor.WithChunking(ChunkConfig{MaxChars: 40, Overlap: 20}, nil)
+
+		_, err := extractor.Fire(context.Background(), NewSession(), document)
+		if !errors.Is(err, ErrInvalidPrompt) || !strings.Contains(err.Error(), "needs a merge func") {
+			t.Errorf("expected a merge func required, got %v", err)
+		}
+		extractor.WithChunking(ChunkConfig{MaxChars: 40, Overlap: 40}, nil)
+		if _, err := extractor.Fire(context.Background(), NewSession(), document); !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("expected an invalid config rejected, got %v", err)
+		}
+		if calls != 0 {
+			t.Errorf("expected no provider call, got %d", calls)
+		}
+	})
+
+	t.Run("failed chunk", func(t *testing.T) {
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			if strings.Contains(p, "Turing") {
+				return `{"people": [""]}`, nil
+			}
+			return `{"people": []}`, nil
+		})
+		extractor, _ := Extract[chunkNames]("people mentioned", provider)
+		extractor.WithChunking(ChunkConfig{MaxChars: 40, Overlap: 20}, nil)
+
+		session := NewSession()
+		_, err := extractor.Fire(context.Background(), session, document)
+		chunks := len(splitChunks(document, 40, 20))
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), fmt.Sprintf("of %d:", chunks)) {
+			t.Errorf("expected the failed chunk named, got %v", err)
+		}
+		if session.Len() != 0 || session.TotalUsage().Total == 0 {
+			t.Errorf("expected usage but no exchange on the session, got %d messages", session.Len())
+		}
+	})
+}
@@ -3,6 +3,10 @@ package zyn
 import (
 	"context"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
 
 	"github.com/zoobzio/pipz"
 )
@@ -12,17 +16,32 @@ type ClassificationInput struct {
 	Subject     string              // The main item being classified
 	Context     string              // Background information
 	Examples    map[string][]string // Examples per category
+	Images      []ImageInput        // Images to classify, for providers with vision support
 	Temperature float32             // LLM temperature setting
 }
 
+// distributionTolerance is how far a distribution's sum may stray from 1.0
+// before it is rejected rather than normalized.
+const distributionTolerance = 0.05
+
 // ClassificationResponse contains the response from a classification synapse.
 type ClassificationResponse struct {
-	Primary    string   `json:"primary"`    // Best matching category
-	Secondary  string   `json:"secondary"`  // Optional second choice
-	Confidence float64  `json:"confidence"` // Confidence in primary choice
-	Reasoning  []string `json:"reasoning"`  // Explanation of classification
+	Primary      string             `json:"primary"`                // Best matching category
+	Secondary    string             `json:"secondary"`              // Optional second choice
+	Confidence   float64            `json:"confidence"`             // Confidence in primary choice
+	Distribution map[string]float64 `json:"distribution,omitempty"` // Probability per category, when returned
+	Reasoning    []string           `json:"reasoning"`              // Explanation of classification
+	Suppressed   string             `json:"suppressed,omitempty"`   // Primary replaced by the none category, set by WithNoneCategory
+	IsNone       bool               `json:"is_none,omitempty"`      // Primary is the none category of WithNoneCategory
 }
 
+// suppressedProperty and isNoneProperty are set client-side and left out of
+// the response schema.
+const (
+	suppressedProperty = "suppressed"
+	isNoneProperty     = "is_none"
+)
+
 // Validate checks if the response is valid.
 func (r ClassificationResponse) Validate() error {
 	if r.Primary == "" {
@@ -34,9 +53,206 @@ func (r ClassificationResponse) Validate() error {
 	if len(r.Reasoning) == 0 {
 		return fmt.Errorf("reasoning required but empty")
 	}
+	if len(r.Distribution) > 0 {
+		sum := 0.0
+		for category, p := range r.Distribution {
+			if p < 0 || p > 1 {
+				return fmt.Errorf("distribution probability for %q must be 0-1, got %f", category, p)
+			}
+			sum += p
+		}
+		if math.Abs(sum-1) > distributionTolerance {
+			return fmt.Errorf("distribution must sum to ~1.0, got %f", sum)
+		}
+		best := slices.Max(slices.Collect(maps.Values(r.Distribution)))
+		if p, ok := distributionProbability(r.Distribution, r.Primary); !ok || p < best {
+			return fmt.Errorf("primary %q is not the most probable category in the distribution", r.Primary)
+		}
+	}
+	return nil
+}
+
+// distributionProbability returns the probability of category in a
+// distribution, matching keys regardless of case and surrounding space.
+func distributionProbability(distribution map[string]float64, category string) (float64, bool) {
+	if p, ok := distribution[category]; ok {
+		return p, true
+	}
+	category = strings.TrimSpace(category)
+	for key, p := range distribution {
+		if strings.EqualFold(strings.TrimSpace(key), category) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// validateClassification checks that the primary and any secondary category
+// are among the prompt's categories, including a none category when one was
+// requested, and that a returned distribution covers exactly those
+// categories. Categories are compared regardless of case and surrounding
+// space.
+func (c *ClassificationSynapse) validateClassification(prompt *Prompt, response ClassificationResponse) error {
+	if _, ok := promptCategory(prompt, response.Primary); !ok {
+		return fmt.Errorf("primary %q is not one of the categories", response.Primary)
+	}
+	if _, ok := promptCategory(prompt, response.Secondary); !ok && strings.TrimSpace(response.Secondary) != "" {
+		return fmt.Errorf("secondary %q is not one of the categories", response.Secondary)
+	}
+	return c.validateDistribution(prompt, response)
+}
+
+// promptCategory returns the prompt's category matching name regardless of
+// case and surrounding space, or false if there is none.
+func promptCategory(prompt *Prompt, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, category := range prompt.Categories {
+		if strings.EqualFold(category, name) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// validateDistribution checks that a returned distribution covers exactly the
+// prompt's categories, including a none category when one was requested.
+// Keys are compared regardless of case and surrounding space, and a category
+// may appear only once. Responses without a distribution are accepted.
+func (c *ClassificationSynapse) validateDistribution(prompt *Prompt, response ClassificationResponse) error {
+	if len(response.Distribution) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(response.Distribution))
+	for key := range response.Distribution {
+		category, ok := promptCategory(prompt, key)
+		if !ok {
+			return fmt.Errorf("distribution has unknown category %q", key)
+		}
+		if seen[category] {
+			return fmt.Errorf("distribution has category %q more than once", category)
+		}
+		seen[category] = true
+	}
+	for _, category := range prompt.Categories {
+		if !seen[category] {
+			return fmt.Errorf("distribution missing category %q", category)
+		}
+	}
 	return nil
 }
 
+// normalizeDistribution scales the distribution to sum to exactly 1.0.
+// Validation has already bounded its drift by distributionTolerance.
+func normalizeDistribution(response ClassificationResponse) ClassificationResponse {
+	sum := 0.0
+	for _, p := range response.Distribution {
+		sum += p
+	}
+	if sum == 0 {
+		return response
+	}
+	normalized := make(map[string]float64, len(response.Distribution))
+	for category, p := range response.Distribution {
+		normalized[category] = p / sum
+	}
+	response.Distribution = normalized
+	return response
+}
+
+// Identity for the none category option.
+var noneCategoryID = pipz.NewIdentity("zyn:none-category", "Allows a none of the above category")
+
+// noneCategoryConstraint tells the LLM when to answer the none category.
+const noneCategoryConstraint = "none: primary %q when no category fits, and whenever confidence is below %g"
+
+// classificationSettings holds what options configure on a classification
+// call.
+type classificationSettings struct {
+	noneLabel string  // Category answered when none fits, set by WithNoneCategory
+	noneBelow float64 // Confidence below which noneLabel is answered
+}
+
+// WithNoneCategory lets a classification synapse answer label when no
+// category fits, instead of forcing the input into the closest one. The label
+// is offered to the LLM alongside the categories. A response whose confidence
+// is below threshold is also coerced to label, with the original primary kept
+// in Suppressed; when the response has a distribution, its highest
+// probability is compared instead. Fire returns label in both cases.
+// The option has no effect on other synapse types.
+//
+// Example:
+//
+//	classifier, _ := Classification("What kind of ticket?",
+//	    []string{"billing", "bug", "feature"}, provider,
+//	    WithNoneCategory("none", 0.4))
+func WithNoneCategory(label string, threshold float64) Option {
+	return withSettings(noneCategoryID, func(req *SynapseRequest, settings *classificationSettings) {
+		if label == "" || settings.noneLabel != "" {
+			return
+		}
+		settings.noneLabel = label
+		settings.noneBelow = threshold
+		if !slices.Contains(req.Prompt.Categories, label) {
+			req.Prompt.Categories = append(slices.Clone(req.Prompt.Categories), label)
+		}
+		req.Prompt.Constraints = append(slices.Clone(req.Prompt.Constraints), fmt.Sprintf(noneCategoryConstraint, label, threshold))
+	})
+}
+
+// applyNoneCategory coerces a low-confidence response to the call's none
+// category, keeping the original primary in Suppressed, and marks responses
+// whose primary is the none category.
+func applyNoneCategory(settings *classificationSettings, response ClassificationResponse) ClassificationResponse {
+	response.Suppressed = ""
+	response.IsNone = false
+	label, threshold := settings.noneLabel, settings.noneBelow
+	if label == "" {
+		return response
+	}
+	if response.Primary == label {
+		response.IsNone = true
+		return response
+	}
+	confidence := response.Confidence
+	if len(response.Distribution) > 0 {
+		confidence = slices.Max(slices.Collect(maps.Values(response.Distribution)))
+	}
+	if confidence < threshold {
+		response.Suppressed = response.Primary
+		response.Primary = label
+		response.IsNone = true
+	}
+	return response
+}
+
+// canonicalCategories returns a validated response with the primary and
+// secondary categories and the distribution keys as written in the prompt.
+func canonicalCategories(prompt *Prompt, response ClassificationResponse) ClassificationResponse {
+	if category, ok := promptCategory(prompt, response.Primary); ok {
+		response.Primary = category
+	}
+	if category, ok := promptCategory(prompt, response.Secondary); ok {
+		response.Secondary = category
+	}
+	if len(response.Distribution) > 0 {
+		distribution := make(map[string]float64, len(response.Distribution))
+		for key, p := range response.Distribution {
+			if category, ok := promptCategory(prompt, key); ok {
+				key = category
+			}
+			distribution[key] = p
+		}
+		response.Distribution = distribution
+	}
+	return response
+}
+
+// finishResponse names the categories as declared, normalizes the
+// distribution, and applies the none category.
+func finishResponse(prompt *Prompt, settings *classificationSettings, response ClassificationResponse) ClassificationResponse {
+	return applyNoneCategory(settings, normalizeDistribution(canonicalCategories(prompt, response)))
+}
+
 // ClassificationSynapse represents a multi-class classification synapse.
 type ClassificationSynapse struct {
 	question   string
@@ -51,6 +267,12 @@ type ClassificationSynapse struct {
 func NewClassification(question string, categories []string, provider Provider, opts ...Option) (*ClassificationSynapse, error) {
 	// Generate schema once at construction
 	schema, err := generateJSONSchema[ClassificationResponse]()
+	if err == nil {
+		schema, err = omitProperty(schema, suppressedProperty)
+	}
+	if err == nil {
+		schema, err = omitProperty(schema, isNoneProperty)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("classification synapse: %w", err)
 	}
@@ -64,12 +286,14 @@ func NewClassification(question string, categories []string, provider Provider,
 	// Create service with final pipeline and default temperature
 	svc := NewService[ClassificationResponse](pipeline, "classification", provider, DefaultTemperatureCreative)
 
-	return &ClassificationSynapse{
+	synapse := &ClassificationSynapse{
 		question:   question,
 		categories: categories,
 		schema:     schema,
 		service:    svc,
-	}, nil
+	}
+	svc.validate = synapse.validateClassification
+	return synapse, nil
 }
 
 // GetPipeline returns the internal pipeline for composition.
@@ -93,6 +317,13 @@ func (c *ClassificationSynapse) Fire(ctx context.Context, session *Session, inpu
 	return response.Primary, nil
 }
 
+// FireResult executes the synapse and returns the primary category in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (c *ClassificationSynapse) FireResult(ctx context.Context, session *Session, input string) (Result[string], error) {
+	result, err := c.execute(ctx, session, ClassificationInput{Subject: input})
+	return withValue(result, result.Value.Primary), err
+}
+
 // FireWithDetails executes the synapse and returns the full response.
 func (c *ClassificationSynapse) FireWithDetails(ctx context.Context, session *Session, input string) (ClassificationResponse, error) {
 	classInput := ClassificationInput{Subject: input}
@@ -101,14 +332,35 @@ func (c *ClassificationSynapse) FireWithDetails(ctx context.Context, session *Se
 
 // FireWithInput executes the synapse with rich input structure.
 func (c *ClassificationSynapse) FireWithInput(ctx context.Context, session *Session, input ClassificationInput) (ClassificationResponse, error) {
-	// Merge defaults with user input
-	merged := c.mergeInputs(input)
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
 
-	// Build prompt
+// execute merges defaults into input and runs the call, finishing a valid
+// response with the settings the synapse's options chose for it.
+func (c *ClassificationSynapse) execute(ctx context.Context, session *Session, input ClassificationInput) (Result[ClassificationResponse], error) {
+	merged := c.mergeInputs(input)
 	prompt := c.buildPrompt(merged)
+	settings := &classificationSettings{}
+	result, err := c.service.executeConfigured(ctx, session, prompt, settings, merged.Temperature, nil)
+	if err != nil {
+		return result, err
+	}
+	return withValue(result, finishResponse(prompt, settings, result.Value)), nil
+}
 
-	// Execute through service with session (service handles temperature fallback)
-	return c.service.Execute(ctx, session, prompt, merged.Temperature)
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a ClassificationResponse.
+func (c *ClassificationSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := c.FireWithInput(ctx, session, ClassificationInput{
+		Subject:     input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // mergeInputs combines defaults with user input.
@@ -129,6 +381,9 @@ func (c *ClassificationSynapse) mergeInputs(input ClassificationInput) Classific
 			merged.Examples[cat] = append(merged.Examples[cat], exs...)
 		}
 	}
+	if len(input.Images) > 0 {
+		merged.Images = append(merged.Images, input.Images...)
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -152,9 +407,16 @@ func (c *ClassificationSynapse) buildPrompt(input ClassificationInput) *Prompt {
 		"primary: required, from categories list",
 		"secondary: optional, from categories list or empty string",
 		"confidence: 0.0 to 1.0",
+		"distribution: probability 0.0 to 1.0 for every category, summing to 1.0, highest for primary",
 		"reasoning: ordered steps explaining classification",
 	}
 
+	// Attach images if provided
+	if len(input.Images) > 0 {
+		prompt.Images = input.Images
+		prompt.Constraints = append(prompt.Constraints, imagesConstraint)
+	}
+
 	return prompt
 }
 
@@ -169,7 +431,7 @@ func (c *ClassificationSynapse) buildPrompt(input ClassificationInput) *Prompt {
 //	    provider,
 //	    WithTimeout(10*time.Second),
 //	)
-//	category, err := synapse.Fire(ctx, "Connection refused on port 5432")
+//	category, err := synapse.Fire(ctx, session, "Connection refused on port 5432")
 func Classification(question string, categories []string, provider Provider, opts ...Option) (*ClassificationSynapse, error) {
 	return NewClassification(question, categories, provider, opts...)
 }
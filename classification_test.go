@@ -2,6 +2,9 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -636,3 +639,287 @@ func TestClassificationResponse_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestClassificationResponse_Distribution(t *testing.T) {
+	base := func(primary string, distribution map[string]float64) ClassificationResponse {
+		return ClassificationResponse{
+			Primary:      primary,
+			Confidence:   0.8,
+			Distribution: distribution,
+			Reasoning:    []string{"reason"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		response ClassificationResponse
+		wantErr  bool
+	}{
+		{"absent", base("a", nil), false},
+		{"consistent", base("a", map[string]float64{"a": 0.6, "b": 0.4}), false},
+		{"tie with primary", base("b", map[string]float64{"a": 0.5, "b": 0.5}), false},
+		{"within tolerance", base("a", map[string]float64{"a": 0.6, "b": 0.43}), false},
+		{"primary not argmax", base("b", map[string]float64{"a": 0.7, "b": 0.3}), true},
+		{"primary differs in case", base("A", map[string]float64{"a": 0.6, "b": 0.4}), false},
+		{"primary missing", base("c", map[string]float64{"a": 0.7, "b": 0.3}), true},
+		{"sum too low", base("a", map[string]float64{"a": 0.5, "b": 0.2}), true},
+		{"out of range", base("a", map[string]float64{"a": 1.2, "b": -0.2}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.response.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestClassificationSynapse_Distribution(t *testing.T) {
+	categories := []string{"bug", "feature", "question"}
+
+	t.Run("normalized", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"primary": "bug", "confidence": 0.7, "distribution": {"bug": 0.62, "feature": 0.31, "question": 0.1}, "reasoning": ["stack trace"]}`)
+		synapse, err := Classification("What type of issue?", categories, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "crash on save")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sum := 0.0
+		for _, p := range response.Distribution {
+			sum += p
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("expected normalized distribution, got sum %f", sum)
+		}
+		if math.Abs(response.Distribution["bug"]-0.62/1.03) > 1e-9 {
+			t.Errorf("expected proportional scaling, got %f", response.Distribution["bug"])
+		}
+	})
+
+	t.Run("inconsistent primary rejected", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"primary": "feature", "confidence": 0.7, "distribution": {"bug": 0.6, "feature": 0.3, "question": 0.1}, "reasoning": ["r"]}`)
+		synapse, err := Classification("What type of issue?", categories, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		if _, err := synapse.FireWithDetails(context.Background(), session, "crash on save"); err == nil {
+			t.Fatal("expected error for primary that is not the argmax")
+		}
+		if session.Len() != 0 {
+			t.Error("expected session untouched after rejected response")
+		}
+	})
+
+	t.Run("keys must match categories", func(t *testing.T) {
+		responses := map[string]string{
+			"missing":  `{"primary": "bug", "confidence": 0.7, "distribution": {"bug": 0.7, "feature": 0.3}, "reasoning": ["r"]}`,
+			"unknown":  `{"primary": "bug", "confidence": 0.7, "distribution": {"bug": 0.6, "feature": 0.2, "question": 0.1, "docs": 0.1}, "reasoning": ["r"]}`,
+			"repeated": `{"primary": "bug", "confidence": 0.7, "distribution": {"bug": 0.5, "Bug": 0.1, "feature": 0.3, "question": 0.1}, "reasoning": ["r"]}`,
+		}
+		for name, body := range responses {
+			synapse, err := Classification("What type of issue?", categories, NewMockProviderWithResponse(body))
+			if err != nil {
+				t.Fatalf("failed to create synapse: %v", err)
+			}
+			_, err = synapse.FireWithDetails(context.Background(), NewSession(), "crash on save")
+			if !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("%s: expected invalid response, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("mixed case", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"primary": "Bug", "confidence": 0.7, "distribution": {"bug": 0.6, "Feature": 0.3, " QUESTION": 0.1}, "reasoning": ["r"]}`)
+		synapse, err := Classification("What type of issue?", categories, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "crash on save")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Primary != "bug" {
+			t.Errorf("expected primary as declared, got %q", response.Primary)
+		}
+		for _, category := range categories {
+			if _, ok := response.Distribution[category]; !ok {
+				t.Errorf("expected distribution key %q as declared, got %v", category, response.Distribution)
+			}
+		}
+	})
+
+	t.Run("absent distribution accepted", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"primary": "bug", "confidence": 0.7, "reasoning": ["r"]}`)
+		synapse, err := Classification("What type of issue?", categories, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "crash on save")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Distribution != nil {
+			t.Errorf("expected no distribution, got %v", response.Distribution)
+		}
+	})
+}
+
+func TestClassificationSynapse_Categories(t *testing.T) {
+	classify := func(response string) (ClassificationResponse, error) {
+		synapse, err := Classification("What kind of ticket?", []string{"billing", "bug", "feature"}, NewMockProviderWithResponse(response))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		return synapse.FireWithDetails(context.Background(), NewSession(), "crash on save")
+	}
+
+	t.Run("declared names", func(t *testing.T) {
+		response, err := classify(`{"primary": "Bug ", "secondary": "FEATURE", "confidence": 0.8, "reasoning": ["stack trace"]}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Primary != "bug" || response.Secondary != "feature" || response.IsNone {
+			t.Errorf("expected categories as declared, got %+v", response)
+		}
+	})
+
+	t.Run("novel primary", func(t *testing.T) {
+		_, err := classify(`{"primary": "crash", "secondary": "", "confidence": 0.8, "reasoning": ["stack trace"]}`)
+		if !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected a novel primary rejected, got %v", err)
+		}
+	})
+
+	t.Run("novel secondary", func(t *testing.T) {
+		_, err := classify(`{"primary": "bug", "secondary": "other", "confidence": 0.8, "reasoning": ["stack trace"]}`)
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `secondary "other"`) {
+			t.Errorf("expected a novel secondary rejected, got %v", err)
+		}
+	})
+
+	t.Run("none label without the option", func(t *testing.T) {
+		_, err := classify(`{"primary": "none", "secondary": "", "confidence": 0.8, "reasoning": ["not a ticket"]}`)
+		if !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected an undeclared none label rejected, got %v", err)
+		}
+	})
+}
+
+func TestWithNoneCategory(t *testing.T) {
+	categories := []string{"billing", "bug", "feature"}
+	classify := func(t *testing.T, response string) (ClassificationResponse, string) {
+		t.Helper()
+		var received string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			received = prompt
+			return response, nil
+		})
+		synapse, err := Classification("What kind of ticket?", categories, provider, WithNoneCategory("none", 0.4))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		details, err := synapse.FireWithDetails(context.Background(), NewSession(), "the office plant is wilting")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		primary, err := synapse.Fire(context.Background(), NewSession(), "the office plant is wilting")
+		if err != nil || primary != details.Primary {
+			t.Errorf("expected Fire to return %q, got %q (%v)", details.Primary, primary, err)
+		}
+		return details, received
+	}
+
+	t.Run("explicit none", func(t *testing.T) {
+		details, prompt := classify(t, `{"primary": "none", "secondary": "", "confidence": 0.9,
+			"distribution": {"billing": 0.02, "bug": 0.04, "feature": 0.04, "none": 0.9}, "reasoning": ["not a ticket"]}`)
+		if details.Primary != "none" || details.Suppressed != "" || !details.IsNone {
+			t.Errorf("expected explicit none kept, got %+v", details)
+		}
+		if !strings.Contains(prompt, "none") || !strings.Contains(prompt, `none: primary "none" when no category fits`) {
+			t.Errorf("expected none category offered in prompt:\n%s", prompt)
+		}
+	})
+
+	t.Run("low confidence coerced", func(t *testing.T) {
+		details, _ := classify(t, `{"primary": "bug", "secondary": "feature", "confidence": 0.3, "reasoning": ["unclear"]}`)
+		if details.Primary != "none" || details.Suppressed != "bug" || details.Secondary != "feature" || !details.IsNone {
+			t.Errorf("expected bug suppressed in favour of none, got %+v", details)
+		}
+	})
+
+	t.Run("low distribution max coerced", func(t *testing.T) {
+		details, _ := classify(t, `{"primary": "bug", "secondary": "feature", "confidence": 0.8,
+			"distribution": {"billing": 0.2, "bug": 0.35, "feature": 0.3, "none": 0.15}, "reasoning": ["unclear"]}`)
+		if details.Primary != "none" || details.Suppressed != "bug" {
+			t.Errorf("expected distribution max below threshold coerced, got %+v", details)
+		}
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		details, _ := classify(t, `{"primary": "bug", "secondary": "", "confidence": 0.85, "reasoning": ["stack trace"]}`)
+		if details.Primary != "bug" || details.Suppressed != "" || details.IsNone {
+			t.Errorf("expected confident answer passed through, got %+v", details)
+		}
+	})
+
+	t.Run("none matched regardless of case", func(t *testing.T) {
+		details, _ := classify(t, `{"primary": " None", "secondary": "", "confidence": 0.9, "reasoning": ["not a ticket"]}`)
+		if details.Primary != "none" || !details.IsNone {
+			t.Errorf("expected the none label as declared, got %+v", details)
+		}
+	})
+
+	t.Run("label with spaces", func(t *testing.T) {
+		synapse, _ := Classification("What kind of ticket?", categories,
+			NewMockProviderWithResponse(`{"primary": "bug", "secondary": "", "confidence": 0.2, "reasoning": ["unclear"]}`),
+			WithNoneCategory("not a ticket", 0.4))
+		details, err := synapse.FireWithDetails(context.Background(), NewSession(), "the office plant is wilting")
+		if err != nil || details.Primary != "not a ticket" || details.Suppressed != "bug" || !details.IsNone {
+			t.Errorf("expected a low-confidence answer coerced to the label, got %+v (%v)", details, err)
+		}
+	})
+
+	t.Run("novel category rejected", func(t *testing.T) {
+		synapse, _ := Classification("What kind of ticket?", categories,
+			NewMockProviderWithResponse(`{"primary": "facilities", "secondary": "", "confidence": 0.9, "reasoning": ["plants"]}`),
+			WithNoneCategory("none", 0.4))
+		_, err := synapse.FireWithDetails(context.Background(), NewSession(), "the office plant is wilting")
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `primary "facilities" is not one of the categories`) {
+			t.Errorf("expected a novel category rejected, got %v", err)
+		}
+	})
+
+	t.Run("without option", func(t *testing.T) {
+		synapse, _ := Classification("What kind of ticket?", categories,
+			NewMockProviderWithResponse(`{"primary": "bug", "secondary": "", "confidence": 0.1, "suppressed": "billing", "reasoning": ["guess"]}`))
+		details, err := synapse.FireWithDetails(context.Background(), NewSession(), "input")
+		if err != nil || details.Primary != "bug" || details.Suppressed != "" {
+			t.Errorf("expected no coercion, got %+v (%v)", details, err)
+		}
+		if strings.Contains(synapse.schema, suppressedProperty) || strings.Contains(synapse.schema, isNoneProperty) {
+			t.Error("expected suppressed and is_none left out of the schema")
+		}
+	})
+
+	t.Run("other synapse types", func(t *testing.T) {
+		var received string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			received = prompt
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+		synapse, _ := Binary("question", provider, WithNoneCategory("none", 0.4))
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(received, "none:") {
+			t.Error("expected binary prompt unchanged")
+		}
+	})
+}
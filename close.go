@@ -0,0 +1,36 @@
+package zyn
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CloseProviders closes every provider that implements io.Closer, for
+// shutting down a long-running service: the HTTP providers release their
+// idle connections, and wrappers such as CachingProvider and WrapProvider
+// close the providers they wrap. Providers without Close are left alone.
+// Every provider is closed even when some fail, and their errors are
+// returned joined. Calls to a closed provider fail with a
+// *ProviderClosedError.
+//
+// Example:
+//
+//	defer zyn.CloseProviders(primary, fallback)
+func CloseProviders(providers ...Provider) error {
+	var errs []error
+	for _, provider := range providers {
+		if err := closeProvider(provider); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", provider.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeProvider closes provider if it implements io.Closer.
+func closeProvider(provider Provider) error {
+	if closer, ok := provider.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// failingCloser is a MockProvider whose Close returns err.
+type failingCloser struct {
+	*MockProvider
+	err error
+}
+
+func (p *failingCloser) Close() error { return p.err }
+
+func TestCloseProviders(t *testing.T) {
+	primary := NewMockProviderWithName("primary")
+	fallback := NewMockProviderWithName("fallback")
+
+	if err := CloseProviders(primary, NewMockProviderWithResponse(`{}`), fallback); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !primary.Closed() || !fallback.Closed() {
+		t.Error("expected every closer closed")
+	}
+
+	_, err := primary.Call(context.Background(), userMessage("input"), 0.1)
+	var closed *ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != "primary" {
+		t.Errorf("expected ProviderClosedError, got %v", err)
+	}
+
+	if err := CloseProviders(primary, fallback); err != nil {
+		t.Errorf("expected closing twice to be safe, got %v", err)
+	}
+}
+
+func TestCloseProviders_Errors(t *testing.T) {
+	failure := errors.New("connection pool busy")
+	after := NewMockProviderWithName("after")
+
+	err := CloseProviders(&failingCloser{MockProvider: NewMockProviderWithName("broken"), err: failure}, after)
+	if !errors.Is(err, failure) || err.Error() != "close broken: connection pool busy" {
+		t.Errorf("expected the close error named, got %v", err)
+	}
+	if !after.Closed() {
+		t.Error("expected providers after a failure closed")
+	}
+}
+
+func TestCloseProviders_StopsRetries(t *testing.T) {
+	provider := NewMockProviderWithName("closed")
+	provider.Close()
+
+	synapse, _ := Binary("Is this valid?", provider, WithRetry(3))
+	_, err := synapse.Fire(context.Background(), NewSession(), "input")
+	if !errors.Is(err, ErrProviderClosed) {
+		t.Errorf("expected ErrProviderClosed, got %v", err)
+	}
+}
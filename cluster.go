@@ -0,0 +1,240 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultClusterMaxItems is the most items a cluster synapse accepts in one
+// call unless ClusterInput.MaxItems says otherwise. Beyond it, models start
+// dropping or repeating items.
+const DefaultClusterMaxItems = 100
+
+// ClusterInput contains rich input structure for clustering.
+type ClusterInput struct {
+	Items          []string // The items to cluster
+	TargetClusters int      // Optional hint for the number of clusters wanted
+	Context        string   // Optional context, such as what the clusters are for
+	MaxItems       int      // Most distinct items accepted in one call; 0 uses DefaultClusterMaxItems
+	Temperature    float32  // LLM temperature setting for this specific request
+}
+
+// ItemCluster is a named group of related items. It is not named Cluster,
+// which is the synapse constructor.
+type ItemCluster struct {
+	Label string   `json:"label"` // Short name of what the items have in common
+	Items []string `json:"items"` // The items in the cluster, exactly as given
+}
+
+// ClusterResponse contains the response from a cluster synapse.
+type ClusterResponse struct {
+	Clusters   []ItemCluster `json:"clusters"`   // The clusters, one per theme
+	Confidence float64       `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string      `json:"reasoning"`  // Explanation of the clustering
+}
+
+// Validate checks if the response is valid. That the clusters partition the
+// input items is checked by the synapse.
+func (r ClusterResponse) Validate() error {
+	if len(r.Clusters) == 0 {
+		return fmt.Errorf("clusters required but empty")
+	}
+	labels := make(map[string]int, len(r.Clusters))
+	for i, cluster := range r.Clusters {
+		label := strings.ToLower(strings.TrimSpace(cluster.Label))
+		if label == "" {
+			return fmt.Errorf("cluster %d: label required but empty", i)
+		}
+		if previous, ok := labels[label]; ok {
+			return fmt.Errorf("cluster %d: label %q already used by cluster %d", i, cluster.Label, previous)
+		}
+		labels[label] = i
+		if len(cluster.Items) == 0 {
+			return fmt.Errorf("cluster %d is empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// validateClusters checks that every item of the prompt appears in exactly
+// one cluster, exactly once, and that the clusters hold nothing else.
+func validateClusters(prompt *Prompt, response ClusterResponse) error {
+	groups := make([][]string, len(response.Clusters))
+	for i, cluster := range response.Clusters {
+		groups[i] = cluster.Items
+	}
+	return checkPartition(prompt.Items, groups, "cluster")
+}
+
+// ClusterSynapse groups items by theme and names each group.
+type ClusterSynapse struct {
+	criteria string
+	defaults ClusterInput
+	base     *Base[ClusterInput, ClusterResponse]
+}
+
+// NewCluster creates a new cluster synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewCluster(criteria string, provider Provider, opts ...Option) (*ClusterSynapse, error) {
+	synapse := &ClusterSynapse{criteria: criteria}
+
+	base, err := NewSynapse(SynapseConfig[ClusterInput, ClusterResponse]{
+		Type:        "cluster",
+		Temperature: DefaultTemperatureAnalytical,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = validateClusters
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (c *ClusterSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.base.GetPipeline()
+}
+
+// WithDefaults creates a new Cluster with default input values.
+// These are merged with user input at execution time.
+func (c *ClusterSynapse) WithDefaults(defaults ClusterInput) *ClusterSynapse {
+	c.defaults = defaults
+	return c
+}
+
+// Fire clusters items and returns the clusters.
+func (c *ClusterSynapse) Fire(ctx context.Context, session *Session, items []string) ([]ItemCluster, error) {
+	response, err := c.FireWithInput(ctx, session, ClusterInput{Items: items})
+	if err != nil {
+		return nil, err
+	}
+	return response.Clusters, nil
+}
+
+// FireResult clusters items and returns the clusters in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (c *ClusterSynapse) FireResult(ctx context.Context, session *Session, items []string) (Result[[]ItemCluster], error) {
+	result, err := c.execute(ctx, session, ClusterInput{Items: items})
+	if err != nil {
+		return withValue[ClusterResponse, []ItemCluster](result, nil), err
+	}
+	return withValue(result, result.Value.Clusters), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (c *ClusterSynapse) FireWithInput(ctx context.Context, session *Session, input ClusterInput) (ClusterResponse, error) {
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a ClusterResponse.
+func (c *ClusterSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := c.FireWithInput(ctx, session, ClusterInput{
+		Items:       input.items(),
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and clusters the distinct items.
+// Lists over the item limit are rejected with a *TooManyItemsError rather
+// than risk items being dropped.
+func (c *ClusterSynapse) execute(ctx context.Context, session *Session, input ClusterInput) (Result[ClusterResponse], error) {
+	merged := c.mergeInputs(input)
+	if len(merged.Items) == 0 {
+		return Result[ClusterResponse]{Provider: c.base.service.providerName},
+			fmt.Errorf("%w: cluster synapse needs items", ErrInvalidPrompt)
+	}
+	if merged.TargetClusters < 0 {
+		return Result[ClusterResponse]{Provider: c.base.service.providerName},
+			fmt.Errorf("%w: target clusters must not be negative, got %d", ErrInvalidPrompt, merged.TargetClusters)
+	}
+	limit := merged.MaxItems
+	if limit <= 0 {
+		limit = DefaultClusterMaxItems
+	}
+	if len(merged.Items) > limit {
+		return Result[ClusterResponse]{Provider: c.base.service.providerName},
+			&TooManyItemsError{Synapse: "cluster", Items: len(merged.Items), Limit: limit}
+	}
+	return c.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+}
+
+// mergeInputs combines defaults with user input. Exact repeats are dropped
+// from the items, keeping the first of each.
+func (c *ClusterSynapse) mergeInputs(input ClusterInput) ClusterInput {
+	merged := c.defaults
+
+	if len(input.Items) > 0 {
+		merged.Items = input.Items
+	}
+	if input.TargetClusters != 0 {
+		merged.TargetClusters = input.TargetClusters
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.MaxItems != 0 {
+		merged.MaxItems = input.MaxItems
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+	merged.Items = distinctItems(merged.Items)
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (c *ClusterSynapse) buildPrompt(input ClusterInput) *Prompt {
+	constraints := []string{
+		"clusters: every item in exactly one cluster, exactly once; an item that fits nowhere else is a cluster of its own",
+		"items: preserve exact item text",
+		"label: a short, distinct name for what the cluster's items have in common",
+	}
+	if input.TargetClusters > 0 {
+		constraints = append(constraints, fmt.Sprintf("clusters: about %d, unless the items clearly call for more or fewer", input.TargetClusters))
+	}
+	constraints = append(constraints,
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining the clustering",
+	)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Cluster the items by %s", c.criteria),
+		Items:       input.Items,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Cluster creates a new cluster synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	themes, err := Cluster("the problem the customer reports", provider, WithValidationRetry(2))
+//	themes.WithDefaults(ClusterInput{TargetClusters: 5})
+//	clusters, err := themes.Fire(ctx, session, feedback)
+//	// clusters[0]: {Label: "Login failures", Items: [...]}
+func Cluster(criteria string, provider Provider, opts ...Option) (*ClusterSynapse, error) {
+	return NewCluster(criteria, provider, opts...)
+}
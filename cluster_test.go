@@ -0,0 +1,248 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// feedbackItems are the items of the cluster tests.
+var feedbackItems = []string{"can't log in", "password reset email never arrives", "app is slow", "pages take ages to load", "love the new design"}
+
+// validFeedbackClusters is a valid cluster response for feedbackItems.
+const validFeedbackClusters = `{
+	"clusters": [
+		{"label": "Login", "items": ["can't log in", "password reset email never arrives"]},
+		{"label": "Performance", "items": ["app is slow", "pages take ages to load"]},
+		{"label": "Praise", "items": ["love the new design"]}
+	],
+	"confidence": 0.9,
+	"reasoning": ["grouped by problem"]
+}`
+
+func TestClusterResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: "a", Items: []string{"x"}}, {Label: "b", Items: []string{"y"}}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_clusters", func(t *testing.T) {
+		r := ClusterResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no clusters")
+		}
+	})
+
+	t.Run("empty_label", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: " ", Items: []string{"x"}}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an empty label")
+		}
+	})
+
+	t.Run("repeated_label", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: "Login", Items: []string{"x"}}, {Label: "login ", Items: []string{"y"}}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a label repeated up to case and spacing")
+		}
+	})
+
+	t.Run("empty_cluster", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: "a"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a cluster without items")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: "a", Items: []string{"x"}}, {Label: "b", Items: []string{"y"}}},
+			Confidence: 2,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := ClusterResponse{
+			Clusters:   []ItemCluster{{Label: "a", Items: []string{"x"}}, {Label: "b", Items: []string{"y"}}},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestClusterSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return validFeedbackClusters, nil
+	})
+	synapse, err := Cluster("the problem the customer reports", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clusters, err := synapse.Fire(context.Background(), NewSession(), feedbackItems)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusters) != 3 || clusters[0].Label != "Login" || len(clusters[1].Items) != 2 {
+		t.Errorf("unexpected clusters: %+v", clusters)
+	}
+	for _, want := range []string{"the problem the customer reports", "1. can't log in", "5. love the new design", "exactly one cluster"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "clusters: about") {
+		t.Errorf("expected no target without TargetClusters, got %s", prompt)
+	}
+}
+
+func TestClusterSynapse_TargetClusters(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return validFeedbackClusters, nil
+	})
+	synapse, _ := Cluster("problem", provider)
+
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), ClusterInput{Items: feedbackItems, TargetClusters: 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "clusters: about 3") {
+		t.Errorf("expected the target in the prompt, got %s", prompt)
+	}
+
+	_, err := synapse.FireWithInput(context.Background(), NewSession(), ClusterInput{Items: feedbackItems, TargetClusters: -1})
+	if !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt for a negative target, got %v", err)
+	}
+}
+
+func TestClusterSynapse_Partition(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters string
+		expected string
+	}{
+		{"missing item", `[{"label": "Login", "items": ["can't log in", "password reset email never arrives"]}, {"label": "Performance", "items": ["app is slow", "pages take ages to load"]}]`, `items missing from the clusters: "love the new design"`},
+		{"repeated item", `[{"label": "Login", "items": ["can't log in", "password reset email never arrives"]}, {"label": "Performance", "items": ["app is slow", "pages take ages to load", "can't log in"]}, {"label": "Praise", "items": ["love the new design"]}]`, `item "can't log in" appears in cluster 0 and cluster 1`},
+		{"unknown item", `[{"label": "Login", "items": ["can't log in", "password reset email never arrives"]}, {"label": "Performance", "items": ["app is slow", "pages take ages to load"]}, {"label": "Praise", "items": ["love the new design", "great support"]}]`, `cluster 2: unknown item "great support"`},
+		{"repeated label", `[{"label": "Login", "items": ["can't log in", "password reset email never arrives"]}, {"label": "login", "items": ["app is slow", "pages take ages to load", "love the new design"]}]`, `label "login" already used by cluster 0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, _ := Cluster("problem", NewMockProviderWithResponse(`{"clusters": `+tt.clusters+`, "confidence": 0.9, "reasoning": ["grouped by problem"]}`))
+
+			_, err := synapse.Fire(context.Background(), NewSession(), feedbackItems)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestClusterSynapse_TooManyItems(t *testing.T) {
+	var calls int
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		return validFeedbackClusters, nil
+	})
+	synapse, _ := Cluster("problem", provider)
+
+	items := make([]string, DefaultClusterMaxItems+1)
+	for i := range items {
+		items[i] = strings.Repeat("x", i+1)
+	}
+	_, err := synapse.Fire(context.Background(), NewSession(), items)
+	var tooMany *TooManyItemsError
+	if !errors.As(err, &tooMany) || tooMany.Synapse != "cluster" || tooMany.Items != 101 || tooMany.Limit != 100 {
+		t.Fatalf("expected a TooManyItemsError, got %v", err)
+	}
+
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), ClusterInput{Items: feedbackItems, MaxItems: 4}); !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("expected the input's limit applied, got %v", err)
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), append(feedbackItems, "app is slow")); err != nil {
+		t.Errorf("expected exact repeats sent once, got %v", err)
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), nil); !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt without items, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected rejected lists never sent, got %d calls", calls)
+	}
+}
+
+func TestClusterSynapse_FireResult(t *testing.T) {
+	synapse, _ := Cluster("problem", NewMockProviderWithResponse(validFeedbackClusters))
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), feedbackItems)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value) != 3 || result.Provider == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestClusterSynapse_Invoke(t *testing.T) {
+	synapse, _ := Cluster("problem", NewMockProviderWithResponse(validFeedbackClusters))
+
+	validator, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: strings.Join(feedbackItems, "\n")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(ClusterResponse); !ok || len(response.Clusters) != 3 {
+		t.Errorf("expected ClusterResponse, got %#v", validator)
+	}
+}
+
+func TestClusterSynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"clusters": [{"label": "Login", "items": ["can't log in"]}], "confidence": 0.9, "reasoning": ["grouped by problem"]}`, nil
+		}
+		return validFeedbackClusters, nil
+	})
+	synapse, _ := Cluster("problem", provider, WithValidationRetry(2))
+
+	clusters, err := synapse.Fire(context.Background(), NewSession(), feedbackItems)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(clusters) != 3 {
+		t.Errorf("expected a retry after the missing items, got %d calls and %+v", calls, clusters)
+	}
+}
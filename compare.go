@@ -0,0 +1,313 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Winners of a comparison, in CompareResponse.Winner.
+const (
+	CompareWinnerA   = "a"   // The first candidate is better
+	CompareWinnerB   = "b"   // The second candidate is better
+	CompareWinnerTie = "tie" // Neither is better
+)
+
+// positionSwapID identifies the position swap stage.
+var positionSwapID = pipz.NewIdentity("zyn:position-swap", "Compares in both orders to cancel position bias")
+
+// CompareInput contains rich input structure for pairwise comparisons.
+type CompareInput struct {
+	A           string  // The first candidate
+	B           string  // The second candidate
+	Context     string  // Background information, such as the prompt both candidates answer
+	Temperature float32 // LLM temperature setting for this specific request
+}
+
+// CompareResponse contains the response from a compare synapse.
+type CompareResponse struct {
+	Winner     string   `json:"winner"`     // "a", "b", or "tie"
+	Margin     float64  `json:"margin"`     // 0.0 to 1.0, how clearly the winner is better; 0 for a tie
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the judgment
+}
+
+// UnmarshalJSON normalizes the winner, accepting "A", " Tie " and the like.
+func (r *CompareResponse) UnmarshalJSON(data []byte) error {
+	type plain CompareResponse
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	r.Winner = strings.ToLower(strings.TrimSpace(r.Winner))
+	return nil
+}
+
+// Validate checks if the response is valid.
+func (r CompareResponse) Validate() error {
+	switch r.Winner {
+	case CompareWinnerA, CompareWinnerB, CompareWinnerTie:
+	default:
+		return fmt.Errorf("winner must be a, b, or tie, got %q", r.Winner)
+	}
+	if r.Margin < 0 || r.Margin > 1 {
+		return fmt.Errorf("margin must be 0-1, got %f", r.Margin)
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// WithPositionSwap counters position bias in compare synapses: each
+// comparison is made twice through the rest of the pipeline, the second time
+// with A and B swapped, and the two judgments reconciled. When both name the
+// same winner it is returned with their mean margin and confidence; when
+// they disagree the result is a tie at half the lower confidence.
+//
+// Both calls count toward Result.Attempts and their usage is summed. A failed
+// call fails the comparison, so options applied after this one, such as
+// WithRetry, retry the pair.
+// The option has no effect on other synapse types.
+func WithPositionSwap() Option {
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		return pipz.Apply(positionSwapID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			if req.SynapseType != "compare" || len(req.Prompt.Items) != 2 {
+				return pipeline.Process(ctx, req)
+			}
+			return runPositionSwap(ctx, pipeline, req)
+		})
+	}
+}
+
+// runPositionSwap compares req's candidates in both orders and sets the
+// reconciled judgment as the request's response.
+func runPositionSwap(ctx context.Context, pipeline pipz.Chainable[*SynapseRequest], req *SynapseRequest) (*SynapseRequest, error) {
+	// Previews record both calls, which return no response
+	preview := previewing(ctx)
+
+	var usage TokenUsage
+	first, err := comparePass(ctx, pipeline, req, req.Prompt, &usage)
+	if err != nil && !preview {
+		return req, err
+	}
+
+	swapped := *req.Prompt
+	swapped.Items = []string{req.Prompt.Items[1], req.Prompt.Items[0]}
+	second, err := comparePass(ctx, pipeline, req, &swapped, &usage)
+	if preview {
+		return req, nil
+	}
+	if err != nil {
+		return req, err
+	}
+	second.Winner = swapWinner(second.Winner)
+
+	response, err := json.Marshal(reconcileComparisons(first, second))
+	if err != nil {
+		return req, err
+	}
+	req.Response = string(response)
+	req.Usage = &usage
+	return req, nil
+}
+
+// comparePass runs one comparison with prompt through the pipeline, adding
+// its usage and attempts to the totals, and returns the validated judgment.
+func comparePass(ctx context.Context, pipeline pipz.Chainable[*SynapseRequest], req *SynapseRequest,
+	prompt *Prompt, usage *TokenUsage) (CompareResponse, error) {
+	call := *req
+	call.Prompt = prompt
+	call.Response, call.Usage, call.Error = "", nil, nil
+	call.Attempts, call.EstimatedPromptTokens = 0, 0
+
+	_, err := pipeline.Process(ctx, &call)
+	req.Attempts += call.Attempts
+	addUsage(usage, call.Usage)
+	if err != nil {
+		return CompareResponse{}, err
+	}
+
+	response, _, err := parseResponse[CompareResponse](call.Response, prompt)
+	if err != nil {
+		return response, fmt.Errorf("%w: %w", ErrParseFailed, err)
+	}
+	if err := response.Validate(); err != nil {
+		return response, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	return response, nil
+}
+
+// swapWinner maps a winner judged with the candidates swapped back to the
+// original order.
+func swapWinner(winner string) string {
+	switch winner {
+	case CompareWinnerA:
+		return CompareWinnerB
+	case CompareWinnerB:
+		return CompareWinnerA
+	default:
+		return winner
+	}
+}
+
+// reconcileComparisons combines the judgments of both orders, given in the
+// original order's terms.
+func reconcileComparisons(first, second CompareResponse) CompareResponse {
+	if first.Winner == second.Winner {
+		return CompareResponse{
+			Winner:     first.Winner,
+			Margin:     (first.Margin + second.Margin) / 2,
+			Confidence: (first.Confidence + second.Confidence) / 2,
+			Reasoning: append([]string{fmt.Sprintf("Both orders judged %s", first.Winner)},
+				first.Reasoning...),
+		}
+	}
+	return CompareResponse{
+		Winner:     CompareWinnerTie,
+		Confidence: min(first.Confidence, second.Confidence) / 2,
+		Reasoning: append([]string{fmt.Sprintf("The orders disagreed: %s as given, %s with the candidates swapped", first.Winner, second.Winner)},
+			first.Reasoning...),
+	}
+}
+
+// CompareSynapse represents a pairwise comparison synapse.
+type CompareSynapse struct {
+	criteria string
+	defaults CompareInput
+	base     *Base[CompareInput, CompareResponse]
+}
+
+// NewCompare creates a new compare synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewCompare(criteria string, provider Provider, opts ...Option) (*CompareSynapse, error) {
+	synapse := &CompareSynapse{criteria: criteria}
+
+	base, err := NewSynapse(SynapseConfig[CompareInput, CompareResponse]{
+		Type:        "compare",
+		Temperature: DefaultTemperatureAnalytical,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (c *CompareSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.base.GetPipeline()
+}
+
+// WithDefaults creates a new Compare with default input values.
+// These are merged with user input at execution time.
+func (c *CompareSynapse) WithDefaults(defaults CompareInput) *CompareSynapse {
+	c.defaults = defaults
+	return c
+}
+
+// Fire compares a and b and returns the winner: "a", "b", or "tie".
+func (c *CompareSynapse) Fire(ctx context.Context, session *Session, a, b string) (string, error) {
+	response, err := c.FireWithInput(ctx, session, CompareInput{A: a, B: b})
+	if err != nil {
+		return "", err
+	}
+	return response.Winner, nil
+}
+
+// FireResult compares a and b and returns the winner in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (c *CompareSynapse) FireResult(ctx context.Context, session *Session, a, b string) (Result[string], error) {
+	result, err := c.execute(ctx, session, CompareInput{A: a, B: b})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Winner), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (c *CompareSynapse) FireWithInput(ctx context.Context, session *Session, input CompareInput) (CompareResponse, error) {
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, comparing the
+// first two items. The returned Validator is a CompareResponse.
+func (c *CompareSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	compare := CompareInput{Context: input.Context, Temperature: input.Temperature}
+	if items := input.items(); len(items) >= 2 {
+		compare.A, compare.B = items[0], items[1]
+	}
+	response, err := c.FireWithInput(ctx, session, compare)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and runs the comparison.
+func (c *CompareSynapse) execute(ctx context.Context, session *Session, input CompareInput) (Result[CompareResponse], error) {
+	merged := c.mergeInputs(input)
+	if merged.A == "" || merged.B == "" {
+		return Result[CompareResponse]{Provider: c.base.service.providerName},
+			fmt.Errorf("%w: compare synapse needs both A and B", ErrInvalidPrompt)
+	}
+	return c.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+}
+
+// mergeInputs combines defaults with user input.
+func (c *CompareSynapse) mergeInputs(input CompareInput) CompareInput {
+	merged := c.defaults
+
+	if input.A != "" {
+		merged.A = input.A
+	}
+	if input.B != "" {
+		merged.B = input.B
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. The candidates
+// are the prompt's items, A first.
+func (c *CompareSynapse) buildPrompt(input CompareInput) *Prompt {
+	return &Prompt{
+		Task:    fmt.Sprintf("Compare two candidates by %s and judge which is better", c.criteria),
+		Context: input.Context,
+		Items:   []string{input.A, input.B},
+		Constraints: []string{
+			`winner: "a" if item 1 is better, "b" if item 2 is better, "tie" if neither is`,
+			"margin: 0.0 to 1.0, how clearly the winner is better; 0.0 for a tie",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the judgment",
+			"judge on the criteria only, not on the order or length of the candidates",
+		},
+	}
+}
+
+// Compare creates a new compare synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	judge, err := Compare("helpfulness and accuracy", provider, WithPositionSwap())
+//	winner, err := judge.Fire(ctx, session, answerA, answerB)
+func Compare(criteria string, provider Provider, opts ...Option) (*CompareSynapse, error) {
+	return NewCompare(criteria, provider, opts...)
+}
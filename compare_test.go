@@ -0,0 +1,259 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// scriptedJudge answers compare prompts with judge, given the rendered
+// candidates in order, and records every pair it is asked to compare.
+type scriptedJudge struct {
+	mu    sync.Mutex
+	pairs [][]string
+	judge func(first, second string) CompareResponse
+}
+
+func (s *scriptedJudge) provider() Provider {
+	return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		_, section, _ := strings.Cut(prompt, "Items:\n")
+		lines := strings.SplitN(section, "\n", 3)
+		_, first, _ := strings.Cut(lines[0], "1. ")
+		_, second, _ := strings.Cut(lines[1], "2. ")
+
+		s.mu.Lock()
+		s.pairs = append(s.pairs, []string{first, second})
+		s.mu.Unlock()
+
+		body, _ := json.Marshal(s.judge(first, second))
+		return string(body), nil
+	})
+}
+
+// prefersPolite is a consistent judge picking the candidate saying please.
+func prefersPolite(first, second string) CompareResponse {
+	first, second = strings.ToLower(first), strings.ToLower(second)
+	switch {
+	case strings.Contains(first, "please"):
+		return CompareResponse{Winner: "A", Margin: 0.6, Confidence: 0.9, Reasoning: []string{"first is polite"}}
+	case strings.Contains(second, "please"):
+		return CompareResponse{Winner: " B", Margin: 0.8, Confidence: 0.7, Reasoning: []string{"second is polite"}}
+	default:
+		return CompareResponse{Winner: "Tie", Confidence: 0.5, Reasoning: []string{"equally polite"}}
+	}
+}
+
+// prefersFirst is a judge with position bias, always picking the first.
+func prefersFirst(string, string) CompareResponse {
+	return CompareResponse{Winner: "a", Margin: 0.4, Confidence: 0.8, Reasoning: []string{"first reads better"}}
+}
+
+func TestCompareResponse_Validate(t *testing.T) {
+	valid := CompareResponse{Winner: "tie", Confidence: 0.5, Reasoning: []string{"even"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		response CompareResponse
+	}{
+		{"unknown winner", CompareResponse{Winner: "c", Confidence: 0.5, Reasoning: []string{"x"}}},
+		{"empty winner", CompareResponse{Confidence: 0.5, Reasoning: []string{"x"}}},
+		{"margin", CompareResponse{Winner: "a", Margin: 1.5, Confidence: 0.5, Reasoning: []string{"x"}}},
+		{"confidence", CompareResponse{Winner: "a", Confidence: -0.1, Reasoning: []string{"x"}}},
+		{"reasoning", CompareResponse{Winner: "a", Confidence: 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.response.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestCompareResponse_NormalizesWinner(t *testing.T) {
+	for raw, expected := range map[string]string{"A": "a", " b ": "b", "TIE": "tie", "Tie": "tie"} {
+		var response CompareResponse
+		if err := json.Unmarshal([]byte(`{"winner": "`+raw+`"}`), &response); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Winner != expected {
+			t.Errorf("expected %q normalized to %q, got %q", raw, expected, response.Winner)
+		}
+	}
+}
+
+func TestCompareSynapse_Fire(t *testing.T) {
+	judge := &scriptedJudge{judge: prefersPolite}
+	synapse, err := Compare("politeness", judge.provider())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	winner, err := synapse.Fire(context.Background(), NewSession(), "Send the report.", "Please send the report.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if winner != CompareWinnerB {
+		t.Errorf("expected b, got %q", winner)
+	}
+	if len(judge.pairs) != 1 || judge.pairs[0][0] != "Send the report." {
+		t.Errorf("expected one call with A first, got %v", judge.pairs)
+	}
+}
+
+func TestCompareSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"winner": "A", "margin": 0.3, "confidence": 0.85, "reasoning": ["cites sources"]}`, nil
+	})
+	synapse, _ := Compare("factual accuracy", provider)
+
+	response, err := synapse.FireWithInput(context.Background(), NewSession(), CompareInput{
+		A:       "Paris, per the 2024 census.",
+		B:       "Probably Paris.",
+		Context: "Question: what is the capital of France?",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Winner != "a" || response.Margin != 0.3 || response.Confidence != 0.85 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{"factual accuracy", "Context: Question: what is the capital of France?", "1. Paris, per the 2024 census.", "2. Probably Paris."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestCompareSynapse_RejectsUnknownWinner(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"winner": "both", "confidence": 0.9, "reasoning": ["equal"]}`)
+	synapse, _ := Compare("clarity", provider)
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "x", "y"); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("expected ErrInvalidResponse, got %v", err)
+	}
+}
+
+func TestCompareSynapse_RequiresBoth(t *testing.T) {
+	judge := &scriptedJudge{judge: prefersPolite}
+	synapse, _ := Compare("politeness", judge.provider())
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "only one", ""); !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+	if len(judge.pairs) != 0 {
+		t.Errorf("expected no provider call, got %d", len(judge.pairs))
+	}
+
+	// Defaults can supply a fixed baseline
+	synapse.WithDefaults(CompareInput{A: "Please send the report."})
+	winner, err := synapse.Fire(context.Background(), NewSession(), "", "Send it.")
+	if err != nil || winner != CompareWinnerA {
+		t.Errorf("expected the default A to win, got %q, %v", winner, err)
+	}
+}
+
+func TestCompareSynapse_Invoke(t *testing.T) {
+	judge := &scriptedJudge{judge: prefersPolite}
+	synapse, _ := Compare("politeness", judge.provider())
+
+	result, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "please help\nhelp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := result.(CompareResponse); !ok || response.Winner != CompareWinnerA {
+		t.Errorf("expected a CompareResponse naming a, got %+v", result)
+	}
+}
+
+func TestWithPositionSwap(t *testing.T) {
+	t.Run("consistent judge", func(t *testing.T) {
+		judge := &scriptedJudge{judge: prefersPolite}
+		synapse, _ := Compare("politeness", judge.provider(), WithPositionSwap())
+
+		session := NewSession()
+		result, err := synapse.FireResult(context.Background(), session, "Send the report.", "Please send the report.")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Value != CompareWinnerB {
+			t.Errorf("expected b, got %q", result.Value)
+		}
+		if len(judge.pairs) != 2 || judge.pairs[1][0] != "Please send the report." {
+			t.Errorf("expected a second call with the candidates swapped, got %v", judge.pairs)
+		}
+		if result.Attempts != 2 || result.Usage == nil || result.Usage.Total != 300 {
+			t.Errorf("expected both calls counted, got %d attempts and usage %+v", result.Attempts, result.Usage)
+		}
+
+		response, _ := synapse.FireWithInput(context.Background(), NewSession(), CompareInput{A: "Send the report.", B: "Please send the report."})
+		if response.Margin != 0.7 || response.Confidence != 0.8 || response.Reasoning[0] != "Both orders judged b" {
+			t.Errorf("expected the judgments averaged, got %+v", response)
+		}
+	})
+
+	t.Run("position bias", func(t *testing.T) {
+		judge := &scriptedJudge{judge: prefersFirst}
+		synapse, _ := Compare("clarity", judge.provider(), WithPositionSwap())
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), CompareInput{A: "first", B: "second"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Winner != CompareWinnerTie || response.Margin != 0 || response.Confidence != 0.4 {
+			t.Errorf("expected a tie at lowered confidence, got %+v", response)
+		}
+		if !strings.Contains(response.Reasoning[0], "disagreed: a as given, b with the candidates swapped") {
+			t.Errorf("expected the disagreement explained, got %v", response.Reasoning)
+		}
+	})
+
+	t.Run("failed pass", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			if calls == 2 {
+				return `{"winner": "neither", "confidence": 0.9, "reasoning": ["x"]}`, nil
+			}
+			return `{"winner": "a", "confidence": 0.9, "reasoning": ["x"]}`, nil
+		})
+		synapse, _ := Compare("clarity", provider, WithPositionSwap())
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "first", "second"); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse from the swapped pass, got %v", err)
+		}
+	})
+
+	t.Run("other synapses", func(t *testing.T) {
+		var calls int
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+		synapse, _ := Binary("Is this polite?", provider, WithPositionSwap())
+		if _, err := synapse.Fire(context.Background(), NewSession(), "please"); err != nil || calls != 1 {
+			t.Errorf("expected one call, got %d calls and %v", calls, err)
+		}
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		judge := &scriptedJudge{judge: prefersFirst}
+		synapse, _ := Compare("clarity", judge.provider(), WithPositionSwap())
+
+		calls, err := previewSynapse(synapse, SynapseInput{Items: []string{"first", "second"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(calls) != 2 || len(judge.pairs) != 0 {
+			t.Errorf("expected both orders previewed without calls, got %d previews and %d calls", len(calls), len(judge.pairs))
+		}
+	})
+}
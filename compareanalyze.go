@@ -0,0 +1,230 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Default labels of the datasets of a comparative analysis.
+const (
+	DefaultCompareLabelA = "A"
+	DefaultCompareLabelB = "B"
+)
+
+// CompareAnalyzeInput contains rich input structure for comparative analysis.
+type CompareAnalyzeInput[T any] struct {
+	A           T       // The first dataset
+	B           T       // The second dataset
+	LabelA      string  // Name of the first dataset, e.g. "Q3"; defaults to DefaultCompareLabelA
+	LabelB      string  // Name of the second dataset, e.g. "Q4"; defaults to DefaultCompareLabelB
+	Context     string  // Optional context for analysis
+	Focus       string  // Optional specific aspect to focus on
+	Temperature float32 // Temperature for analysis
+}
+
+// Difference is a material difference between the two datasets.
+type Difference struct {
+	Aspect       string `json:"aspect"`       // What differs, e.g. "revenue growth"
+	A            string `json:"a"`            // How the first dataset stands on the aspect
+	B            string `json:"b"`            // How the second dataset stands on the aspect
+	Significance string `json:"significance"` // Why the difference matters
+}
+
+// CompareAnalyzeResponse contains the response from a comparative analysis.
+type CompareAnalyzeResponse struct {
+	Summary               string       `json:"summary"`                 // How the datasets compare overall
+	Differences           []Difference `json:"differences"`             // Material differences; empty only with NoMaterialDifferences
+	NoMaterialDifferences bool         `json:"no_material_differences"` // Set when the datasets do not differ materially
+	Confidence            float64      `json:"confidence"`              // 0.0 to 1.0 confidence score
+	Reasoning             []string     `json:"reasoning"`               // Explanation of the analysis
+}
+
+// Validate checks if the response is valid. A response must report at
+// least one difference or state that there are none, but not both.
+func (r CompareAnalyzeResponse) Validate() error {
+	if strings.TrimSpace(r.Summary) == "" {
+		return fmt.Errorf("summary required but empty")
+	}
+	if len(r.Differences) == 0 && !r.NoMaterialDifferences {
+		return fmt.Errorf("differences required unless no_material_differences is set")
+	}
+	if len(r.Differences) > 0 && r.NoMaterialDifferences {
+		return fmt.Errorf("no_material_differences set but %d differences given", len(r.Differences))
+	}
+	for i, difference := range r.Differences {
+		if strings.TrimSpace(difference.Aspect) == "" {
+			return fmt.Errorf("difference %d: aspect required but empty", i)
+		}
+		if strings.TrimSpace(difference.Significance) == "" {
+			return fmt.Errorf("difference %d: significance required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// CompareAnalyzeSynapse analyzes how two datasets of type T compare and
+// which differences matter. Unlike Diff, which reports field-level changes
+// between versions of a value, it reports differences by aspect.
+type CompareAnalyzeSynapse[T any] struct {
+	what     string // What kind of comparison to perform
+	schema   string // Pre-computed JSON schema
+	defaults CompareAnalyzeInput[T]
+	service  *Service[CompareAnalyzeResponse]
+}
+
+// CompareAnalyze creates a new comparative analysis synapse for datasets of
+// type T.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	analyst, err := CompareAnalyze[SalesReport]("quarterly sales performance", provider)
+//	response, err := analyst.FireWithInput(ctx, session, CompareAnalyzeInput[SalesReport]{
+//	    A: q3, B: q4, LabelA: "Q3", LabelB: "Q4",
+//	})
+//	for _, difference := range response.Differences {
+//	    log.Printf("%s: Q3 %s, Q4 %s (%s)", difference.Aspect, difference.A, difference.B, difference.Significance)
+//	}
+func CompareAnalyze[T any](what string, provider Provider, opts ...Option) (*CompareAnalyzeSynapse[T], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[CompareAnalyzeResponse]()
+	if err != nil {
+		return nil, fmt.Errorf("compare analyze synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[CompareAnalyzeResponse](pipeline, "compare_analyze", provider, DefaultTemperatureAnalytical)
+
+	return &CompareAnalyzeSynapse[T]{
+		what:    what,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the underlying pipeline.
+func (c *CompareAnalyzeSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.service.GetPipeline()
+}
+
+// WithDefaults creates a new CompareAnalyze with default input values.
+// These are merged with user input at execution time.
+func (c *CompareAnalyzeSynapse[T]) WithDefaults(defaults CompareAnalyzeInput[T]) *CompareAnalyzeSynapse[T] {
+	c.defaults = defaults
+	return c
+}
+
+// Fire analyzes how a and b compare, labeled A and B.
+func (c *CompareAnalyzeSynapse[T]) Fire(ctx context.Context, session *Session, a, b T) (CompareAnalyzeResponse, error) {
+	return c.FireWithInput(ctx, session, CompareAnalyzeInput[T]{A: a, B: b})
+}
+
+// FireResult analyzes how a and b compare and returns the response in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (c *CompareAnalyzeSynapse[T]) FireResult(ctx context.Context, session *Session, a, b T) (Result[CompareAnalyzeResponse], error) {
+	return c.execute(ctx, session, CompareAnalyzeInput[T]{A: a, B: b})
+}
+
+// FireWithInput analyzes how the datasets compare with rich input.
+func (c *CompareAnalyzeSynapse[T]) FireWithInput(ctx context.Context, session *Session, input CompareAnalyzeInput[T]) (CompareAnalyzeResponse, error) {
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// execute merges input with the defaults and analyzes the comparison.
+// Labels that name both datasets alike are rejected before the call.
+func (c *CompareAnalyzeSynapse[T]) execute(ctx context.Context, session *Session, input CompareAnalyzeInput[T]) (Result[CompareAnalyzeResponse], error) {
+	merged := c.mergeInputs(input)
+	if strings.EqualFold(merged.LabelA, merged.LabelB) {
+		return Result[CompareAnalyzeResponse]{Provider: c.service.providerName},
+			fmt.Errorf("%w: compare analyze synapse needs distinct labels, both are %q", ErrInvalidPrompt, merged.LabelA)
+	}
+
+	result, err := c.service.ExecuteResult(ctx, session, c.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		return result, fmt.Errorf("comparative analysis failed: %w", err)
+	}
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input and fills in the default
+// labels. The datasets are always the input's, as a zero value is a valid
+// dataset.
+func (c *CompareAnalyzeSynapse[T]) mergeInputs(input CompareAnalyzeInput[T]) CompareAnalyzeInput[T] {
+	merged := c.defaults
+	merged.A = input.A
+	merged.B = input.B
+
+	if input.LabelA != "" {
+		merged.LabelA = input.LabelA
+	}
+	if input.LabelB != "" {
+		merged.LabelB = input.LabelB
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Focus != "" {
+		merged.Focus = input.Focus
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	merged.LabelA = strings.TrimSpace(merged.LabelA)
+	if merged.LabelA == "" {
+		merged.LabelA = DefaultCompareLabelA
+	}
+	merged.LabelB = strings.TrimSpace(merged.LabelB)
+	if merged.LabelB == "" {
+		merged.LabelB = DefaultCompareLabelB
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input, with both
+// datasets under their labels.
+func (c *CompareAnalyzeSynapse[T]) buildPrompt(input CompareAnalyzeInput[T]) *Prompt {
+	constraints := []string{
+		fmt.Sprintf("summary: how %s and %s compare overall, referring to them by name", input.LabelA, input.LabelB),
+		"differences: one per material difference, by aspect rather than by field",
+		fmt.Sprintf("a: how %s stands on the aspect; b: how %s stands on it", input.LabelA, input.LabelB),
+		"significance: why the difference matters",
+		"no_material_differences: true only if the datasets do not differ in any way that matters, with differences empty",
+		"confidence: 0.0 to 1.0",
+		fmt.Sprintf("reasoning: ordered steps explaining the analysis, referring to the datasets as %s and %s", input.LabelA, input.LabelB),
+	}
+	if input.Focus != "" {
+		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
+	}
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Compare %s with %s: %s", input.LabelA, input.LabelB, c.what),
+		Input:       renderLabeled(input.LabelA, input.A, input.LabelB, input.B),
+		Context:     input.Context,
+		Schema:      c.schema,
+		Constraints: constraints,
+	}
+}
+
+// renderLabeled renders two values as JSON, each under its label.
+func renderLabeled(labelA string, a any, labelB string, b any) string {
+	return labelA + ":\n" + renderJSON(a) + "\n\n" + labelB + ":\n" + renderJSON(b)
+}
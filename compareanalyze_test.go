@@ -0,0 +1,257 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// salesQuarter is the dataset type of the comparative analysis tests.
+type salesQuarter struct {
+	Revenue int    `json:"revenue"`
+	Churn   string `json:"churn"`
+}
+
+var (
+	thirdQuarter  = salesQuarter{Revenue: 1200, Churn: "2%"}
+	fourthQuarter = salesQuarter{Revenue: 1500, Churn: "5%"}
+)
+
+// churnComparison is a comparative analysis response for thirdQuarter and
+// fourthQuarter.
+const churnComparison = `{
+	"summary": "Q4 grew revenue but lost more customers than Q3.",
+	"differences": [
+		{"aspect": "churn", "a": "2%", "b": "5%", "significance": "retention is slipping"}
+	],
+	"no_material_differences": false,
+	"confidence": 0.8,
+	"reasoning": ["compared Q3 and Q4"]
+}`
+
+func TestCompareAnalyzeResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     "x",
+			Differences: []Difference{{Aspect: "churn", A: "2%", B: "5%", Significance: "retention"}},
+			Confidence:  0.5,
+			Reasoning:   []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_material_differences", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:               "x",
+			NoMaterialDifferences: true,
+			Confidence:            0.5,
+			Reasoning:             []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected no material differences accepted, got error: %v", err)
+		}
+	})
+
+	t.Run("no_summary", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     " ",
+			Differences: []Difference{{Aspect: "churn", A: "2%", B: "5%", Significance: "retention"}},
+			Confidence:  0.5,
+			Reasoning:   []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank summary")
+		}
+	})
+
+	t.Run("no_differences", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:    "x",
+			Confidence: 0.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no differences without the flag")
+		}
+	})
+
+	t.Run("differences_and_flag", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:               "x",
+			Differences:           []Difference{{Aspect: "churn", A: "2%", B: "5%", Significance: "retention"}},
+			NoMaterialDifferences: true,
+			Confidence:            0.5,
+			Reasoning:             []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for differences alongside no material differences")
+		}
+	})
+
+	t.Run("no_aspect", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     "x",
+			Differences: []Difference{{Significance: "x"}},
+			Confidence:  0.5,
+			Reasoning:   []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a difference without an aspect")
+		}
+	})
+
+	t.Run("no_significance", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     "x",
+			Differences: []Difference{{Aspect: "x"}},
+			Confidence:  0.5,
+			Reasoning:   []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a difference without significance")
+		}
+	})
+
+	t.Run("confidence_too_low", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     "x",
+			Differences: []Difference{{Aspect: "churn", A: "2%", B: "5%", Significance: "retention"}},
+			Confidence:  -0.1,
+			Reasoning:   []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for negative confidence")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := CompareAnalyzeResponse{
+			Summary:     "x",
+			Differences: []Difference{{Aspect: "churn", A: "2%", B: "5%", Significance: "retention"}},
+			Confidence:  0.5,
+			Reasoning:   []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestCompareAnalyzeSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return churnComparison, nil
+	})
+	analyst, err := CompareAnalyze[salesQuarter]("quarterly sales performance", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := analyst.Fire(context.Background(), NewSession(), thirdQuarter, fourthQuarter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Differences) != 1 || response.Differences[0].Aspect != "churn" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{"Compare A with B: quarterly sales performance", "A:\n{", "\"revenue\": 1200", "B:\n{", "\"revenue\": 1500"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestCompareAnalyzeSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"summary": "Q4 grew revenue but lost more customers than Q3.", "differences": [], "no_material_differences": true, "confidence": 0.8, "reasoning": ["compared Q3 and Q4"]}`, nil
+	})
+	analyst, _ := CompareAnalyze[salesQuarter]("quarterly sales performance", provider)
+	analyst.WithDefaults(CompareAnalyzeInput[salesQuarter]{LabelA: "Q3", Context: "EMEA region"})
+
+	response, err := analyst.FireWithInput(context.Background(), NewSession(), CompareAnalyzeInput[salesQuarter]{
+		A:      thirdQuarter,
+		B:      thirdQuarter,
+		LabelB: " Q4 ",
+		Focus:  "retention",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.NoMaterialDifferences || len(response.Differences) != 0 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{"Compare Q3 with Q4", "Q3:\n{", "\n\nQ4:\n{", "how Q3 stands on the aspect", "focus: retention", "EMEA region"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestCompareAnalyzeSynapse_NoDifferences(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"summary": "Q4 grew revenue but lost more customers than Q3.", "differences": [], "no_material_differences": false, "confidence": 0.8, "reasoning": ["compared Q3 and Q4"]}`, nil
+		}
+		return churnComparison, nil
+	})
+
+	analyst, _ := CompareAnalyze[salesQuarter]("quarterly sales performance", provider)
+	session := NewSession()
+	_, err := analyst.Fire(context.Background(), session, thirdQuarter, fourthQuarter)
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "no_material_differences") {
+		t.Errorf("expected an empty comparison rejected, got %v", err)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected session untouched, got %d messages", session.Len())
+	}
+
+	retrying, _ := CompareAnalyze[salesQuarter]("quarterly sales performance", provider, WithValidationRetry(2))
+	response, err := retrying.Fire(context.Background(), NewSession(), thirdQuarter, fourthQuarter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Differences) != 1 {
+		t.Errorf("expected a difference after a retry, got %+v", response)
+	}
+}
+
+func TestCompareAnalyzeSynapse_SameLabels(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		return churnComparison, nil
+	})
+	analyst, _ := CompareAnalyze[salesQuarter]("quarterly sales performance", provider)
+
+	result, err := analyst.execute(context.Background(), NewSession(), CompareAnalyzeInput[salesQuarter]{LabelA: "Q3", LabelB: "q3"})
+	if !errors.Is(err, ErrInvalidPrompt) || result.Provider == "" {
+		t.Errorf("expected the same labels rejected before the call, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no provider call, got %d", calls)
+	}
+}
+
+func TestCompareAnalyzeSynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(churnComparison)
+	analyst, _ := CompareAnalyze[salesQuarter]("quarterly sales performance", provider)
+
+	result, err := analyst.FireResult(context.Background(), NewSession(), thirdQuarter, fourthQuarter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value.Differences) != 1 || result.Usage == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if analyst.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
@@ -0,0 +1,281 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// CompleteInput contains rich input structure for completion.
+type CompleteInput[T any] struct {
+	Partial     T        // The value with the known fields set
+	Locked      []string // Fields that must come back unchanged, by Go or JSON name
+	Context     string   // Optional context for inferring the other fields
+	Temperature float32  // Temperature for completion
+}
+
+// completion is a completed value as parsed from a response, keeping the
+// response JSON so locked fields can be restored field by field.
+type completion[T any] struct {
+	value T
+	raw   json.RawMessage
+}
+
+// UnmarshalJSON decodes the response into the value and keeps it.
+func (c *completion[T]) UnmarshalJSON(data []byte) error {
+	c.raw = append(json.RawMessage(nil), data...)
+	return json.Unmarshal(data, &c.value)
+}
+
+// MarshalJSON encodes the value.
+func (c completion[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.value)
+}
+
+// Validate implements Validator. The value's own Validate runs once locked
+// fields are restored.
+func (completion[T]) Validate() error {
+	return nil
+}
+
+// validateValue runs value's Validate method, if it has one.
+func validateValue[T any](value T) error {
+	if validator, ok := any(value).(Validator); ok {
+		return validator.Validate()
+	}
+	if validator, ok := any(&value).(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// lockedKeys returns the JSON keys of the fields of T named in locked, by
+// Go or JSON name. Locked fields require T to be a struct.
+func lockedKeys[T any](locked []string) ([]string, error) {
+	if len(locked) == 0 {
+		return nil, nil
+	}
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("locked fields need a struct type, got %v", t)
+	}
+	keys := make([]string, 0, len(locked))
+	for _, name := range locked {
+		key, ok := jsonKey(t, name)
+		if !ok {
+			return nil, fmt.Errorf("%v has no field %q", t, name)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// jsonKey returns the JSON key of the exported field of t with the given Go
+// or JSON name, or false if there is none.
+func jsonKey(t reflect.Type, name string) (string, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		key := field.Name
+		if tag != "" {
+			key = tag
+		}
+		if name == field.Name || name == key {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// restoreLocked returns the completed value with the fields at keys set to
+// their values in partial, the marshaled input. A locked field the input
+// leaves out is left out of the result too.
+func restoreLocked[T any](partial []byte, keys []string, completed completion[T]) (T, error) {
+	if len(keys) == 0 {
+		return completed.value, nil
+	}
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(partial, &before); err != nil {
+		return completed.value, err
+	}
+	if err := json.Unmarshal(completed.raw, &after); err != nil {
+		return completed.value, fmt.Errorf("response is not a JSON object: %w", err)
+	}
+	for _, key := range keys {
+		if value, ok := before[key]; ok {
+			after[key] = value
+		} else {
+			delete(after, key)
+		}
+	}
+
+	data, err := json.Marshal(after)
+	if err != nil {
+		return completed.value, err
+	}
+	var restored T
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return completed.value, err
+	}
+	return restored, nil
+}
+
+// CompleteSynapse fills in the unknown fields of a partially populated
+// value of type T.
+type CompleteSynapse[T any] struct {
+	instruction string // How the missing fields are inferred
+	schema      string // Pre-computed JSON schema for T
+	defaults    CompleteInput[T]
+	service     *Service[completion[T]]
+}
+
+// Complete creates a new completion synapse for values of type T.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	filler, err := Complete[Address]("normalize the address and infer the missing parts", provider)
+//	address, err := filler.FireWithInput(ctx, session, CompleteInput[Address]{
+//	    Partial: Address{Street: "1600 Amphitheatre Pkwy", City: "mountain view"},
+//	    Locked:  []string{"Street"},
+//	})
+//	// address.State: "CA", address.Country: "US"
+func Complete[T any](instruction string, provider Provider, opts ...Option) (*CompleteSynapse[T], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[T]()
+	if err != nil {
+		return nil, fmt.Errorf("complete synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[completion[T]](pipeline, "complete", provider, DefaultTemperatureDeterministic)
+
+	return &CompleteSynapse[T]{
+		instruction: instruction,
+		schema:      schema,
+		service:     svc,
+	}, nil
+}
+
+// GetPipeline returns the underlying pipeline.
+func (c *CompleteSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.service.GetPipeline()
+}
+
+// WithDefaults creates a new Complete with default input values, such as
+// the locked fields. These are merged with user input at execution time.
+func (c *CompleteSynapse[T]) WithDefaults(defaults CompleteInput[T]) *CompleteSynapse[T] {
+	c.defaults = defaults
+	return c
+}
+
+// Fire fills in the unknown fields of partial.
+func (c *CompleteSynapse[T]) Fire(ctx context.Context, session *Session, partial T) (T, error) {
+	return c.FireWithInput(ctx, session, CompleteInput[T]{Partial: partial})
+}
+
+// FireResult fills in the unknown fields of partial and returns the
+// completed value in a Result envelope carrying the call's usage, timing,
+// and request metadata.
+func (c *CompleteSynapse[T]) FireResult(ctx context.Context, session *Session, partial T) (Result[T], error) {
+	return c.execute(ctx, session, CompleteInput[T]{Partial: partial})
+}
+
+// FireWithInput fills in the unknown fields with rich input.
+func (c *CompleteSynapse[T]) FireWithInput(ctx context.Context, session *Session, input CompleteInput[T]) (T, error) {
+	result, err := c.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// execute merges input with the defaults and completes the value. Locked
+// fields are restored from the input, then the value's own Validate runs.
+func (c *CompleteSynapse[T]) execute(ctx context.Context, session *Session, input CompleteInput[T]) (Result[T], error) {
+	merged := c.mergeInputs(input)
+
+	partial, err := json.Marshal(merged.Partial)
+	if err != nil {
+		return Result[T]{Provider: c.service.providerName},
+			fmt.Errorf("%w: complete synapse cannot marshal the partial value: %w", ErrInvalidPrompt, err)
+	}
+	keys, err := lockedKeys[T](merged.Locked)
+	if err != nil {
+		return Result[T]{Provider: c.service.providerName}, fmt.Errorf("%w: %w", ErrInvalidPrompt, err)
+	}
+
+	result, err := c.service.executeChecked(ctx, session, c.buildPrompt(merged, keys), merged.Temperature, func(completed completion[T]) error {
+		value, err := restoreLocked(partial, keys, completed)
+		if err != nil {
+			return err
+		}
+		return validateValue(value)
+	})
+	if err != nil {
+		return withValue(result, result.Value.value), fmt.Errorf("completion failed: %w", err)
+	}
+
+	value, err := restoreLocked(partial, keys, result.Value)
+	if err != nil {
+		return withValue(result, result.Value.value), fmt.Errorf("completion failed: %w", err)
+	}
+	return withValue(result, value), nil
+}
+
+// mergeInputs combines defaults with user input.
+func (c *CompleteSynapse[T]) mergeInputs(input CompleteInput[T]) CompleteInput[T] {
+	merged := c.defaults
+
+	// Partial is always taken from input
+	merged.Partial = input.Partial
+
+	if len(input.Locked) > 0 {
+		merged.Locked = input.Locked
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input and the JSON keys
+// of its locked fields.
+func (c *CompleteSynapse[T]) buildPrompt(input CompleteInput[T], locked []string) *Prompt {
+	prompt := &Prompt{
+		Task:    fmt.Sprintf("Complete the partially filled object: %s", c.instruction),
+		Input:   renderJSON(input.Partial),
+		Context: input.Context,
+		Schema:  c.schema,
+	}
+
+	constraints := []string{
+		"output: the whole object, with the fields given in the input kept unless the task says to correct them",
+		"fill in the empty and missing fields from the given ones and the context; leave a field empty when it cannot be inferred",
+	}
+	if len(locked) > 0 {
+		constraints = append(constraints, fmt.Sprintf("locked: keep these fields exactly as in the input: %s", strings.Join(locked, ", ")))
+	}
+
+	prompt.Constraints = constraints
+
+	return prompt
+}
@@ -0,0 +1,187 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// completeAddress is the value type of the complete tests.
+type completeAddress struct {
+	Street  string `json:"street"`
+	City    string `json:"city"`
+	State   string `json:"state,omitempty"`
+	Country string `json:"country"`
+	Notes   string `json:"-"`
+}
+
+// Validate requires a two-letter country code.
+func (a completeAddress) Validate() error {
+	if len(a.Country) != 2 {
+		return fmt.Errorf("country must be a two-letter code, got %q", a.Country)
+	}
+	return nil
+}
+
+// partialAddress is the partial value of the complete tests.
+var partialAddress = completeAddress{Street: "1600 Amphitheatre Pkwy", City: "mountain view"}
+
+func TestLockedKeys(t *testing.T) {
+	keys, err := lockedKeys[completeAddress]([]string{"Street", "city"})
+	if err != nil || strings.Join(keys, ",") != "street,city" {
+		t.Errorf("expected JSON keys for Go and JSON names, got %v, %v", keys, err)
+	}
+
+	for _, locked := range [][]string{{"Zip"}, {"Notes"}} {
+		if _, err := lockedKeys[completeAddress](locked); err == nil {
+			t.Errorf("expected an error for %v", locked)
+		}
+	}
+	if _, err := lockedKeys[map[string]string]([]string{"street"}); err == nil {
+		t.Error("expected locked fields rejected for a non-struct type")
+	}
+	if keys, err := lockedKeys[map[string]string](nil); err != nil || keys != nil {
+		t.Errorf("expected no keys without locked fields, got %v, %v", keys, err)
+	}
+}
+
+func TestCompleteSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"street": "1600 Amphitheatre Pkwy", "city": "Mountain View", "state": "CA", "country": "US"}`, nil
+	})
+	filler, err := Complete[completeAddress]("normalize the address and infer the missing parts", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	address, err := filler.Fire(context.Background(), NewSession(), partialAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := completeAddress{Street: "1600 Amphitheatre Pkwy", City: "Mountain View", State: "CA", Country: "US"}
+	if address != expected {
+		t.Errorf("expected %+v, got %+v", expected, address)
+	}
+	for _, want := range []string{"normalize the address", `"city": "mountain view"`, "Response JSON Schema"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "locked:") {
+		t.Errorf("expected no locked fields in the prompt, got %s", prompt)
+	}
+}
+
+func TestCompleteSynapse_LockedFields(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"street": "1600 Amphitheatre Parkway", "city": "Mountain View", "state": "CA", "country": "US"}`, nil
+	})
+	filler, _ := Complete[completeAddress]("infer the missing parts", provider)
+
+	address, err := filler.FireWithInput(context.Background(), NewSession(), CompleteInput[completeAddress]{
+		Partial: partialAddress,
+		Locked:  []string{"Street", "state"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address.Street != partialAddress.Street {
+		t.Errorf("expected the locked street restored, got %q", address.Street)
+	}
+	if address.State != "" {
+		t.Errorf("expected the locked, omitted state left empty, got %q", address.State)
+	}
+	if address.City != "Mountain View" || address.Country != "US" {
+		t.Errorf("expected unlocked fields completed, got %+v", address)
+	}
+	if !strings.Contains(prompt, "locked: keep these fields exactly as in the input: street, state") {
+		t.Errorf("expected the locked fields in the prompt, got %s", prompt)
+	}
+}
+
+func TestCompleteSynapse_ValidateAfterRestore(t *testing.T) {
+	// The model fixes the country, but it is locked to the invalid input
+	provider := NewMockProviderWithResponse(`{"street": "x", "city": "y", "country": "US"}`)
+	filler, _ := Complete[completeAddress]("infer the missing parts", provider)
+
+	_, err := filler.FireWithInput(context.Background(), NewSession(), CompleteInput[completeAddress]{
+		Partial: completeAddress{Country: "USA"},
+		Locked:  []string{"country"},
+	})
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `two-letter code, got "USA"`) {
+		t.Errorf("expected the value's Validate run on the restored value, got %v", err)
+	}
+}
+
+func TestCompleteSynapse_InvalidInput(t *testing.T) {
+	var calls int
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		return `{}`, nil
+	})
+	filler, _ := Complete[completeAddress]("x", provider)
+
+	_, err := filler.FireWithInput(context.Background(), NewSession(), CompleteInput[completeAddress]{Partial: partialAddress, Locked: []string{"zip"}})
+	if !errors.Is(err, ErrInvalidPrompt) || !strings.Contains(err.Error(), `no field "zip"`) {
+		t.Errorf("expected ErrInvalidPrompt for an unknown locked field, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no call, got %d", calls)
+	}
+}
+
+func TestCompleteSynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"street": "x", "city": "Mountain View", "country": "United States"}`, nil
+		}
+		return `{"street": "x", "city": "Mountain View", "country": "US"}`, nil
+	})
+	filler, _ := Complete[completeAddress]("x", provider, WithValidationRetry(2))
+
+	result, err := filler.FireResult(context.Background(), NewSession(), partialAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || result.Value.Country != "US" || result.Attempts != 2 {
+		t.Errorf("expected a retry after the invalid country, got %d calls and %+v", calls, result)
+	}
+}
+
+func TestCompleteSynapse_WithoutValidate(t *testing.T) {
+	type tags struct {
+		Primary   string   `json:"primary"`
+		Secondary []string `json:"secondary"`
+	}
+	filler, _ := Complete[tags]("infer the secondary tags", NewMockProviderWithResponse(`{"primary": "go", "secondary": ["concurrency"]}`))
+
+	completed, err := filler.Fire(context.Background(), NewSession(), tags{Primary: "go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if completed.Primary != "go" || len(completed.Secondary) != 1 {
+		t.Errorf("unexpected value: %+v", completed)
+	}
+}
+
+func TestCompleteSynapse_WithDefaults(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"street": "changed", "city": "Mountain View", "country": "US"}`)
+	filler, _ := Complete[completeAddress]("x", provider)
+	filler.WithDefaults(CompleteInput[completeAddress]{Locked: []string{"street"}})
+
+	address, err := filler.Fire(context.Background(), NewSession(), partialAddress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if address.Street != partialAddress.Street {
+		t.Errorf("expected the default locked fields applied, got %+v", address)
+	}
+}
@@ -0,0 +1,654 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TiebreakMode selects how a consensus call settles a tie between answers
+// with the same number of votes.
+type TiebreakMode int
+
+const (
+	// TiebreakFail fails the call with ErrNoConsensus on a tie.
+	TiebreakFail TiebreakMode = iota
+	// TiebreakConfidence picks the answer with the highest summed confidence.
+	// A tie that remains after comparing confidence fails the call.
+	TiebreakConfidence
+	// TiebreakFirst picks the answer given by the earliest backend in the
+	// constructor's provider list.
+	TiebreakFirst
+)
+
+// String returns the mode's name.
+func (m TiebreakMode) String() string {
+	switch m {
+	case TiebreakFail:
+		return "fail"
+	case TiebreakConfidence:
+		return "confidence"
+	case TiebreakFirst:
+		return "first"
+	default:
+		return "TiebreakMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// ConsensusPolicy configures how backend answers are combined.
+type ConsensusPolicy struct {
+	MinAgree int          // Backends that must give the consensus answer; values below 1 require a strict majority
+	Tiebreak TiebreakMode // How to settle answers with equal votes
+}
+
+// required returns the number of agreeing backends needed out of backends.
+func (p ConsensusPolicy) required(backends int) int {
+	if p.MinAgree < 1 {
+		return backends/2 + 1
+	}
+	return p.MinAgree
+}
+
+// check validates the policy against the number of providers.
+func (p ConsensusPolicy) check(providers []Provider) error {
+	if len(providers) < 2 {
+		return fmt.Errorf("at least two providers are required, got %d", len(providers))
+	}
+	for i, provider := range providers {
+		if provider == nil {
+			return fmt.Errorf("provider %d is nil", i)
+		}
+	}
+	if p.MinAgree > len(providers) {
+		return fmt.Errorf("min agree %d exceeds %d providers", p.MinAgree, len(providers))
+	}
+	if p.Tiebreak < TiebreakFail || p.Tiebreak > TiebreakFirst {
+		return fmt.Errorf("unknown tiebreak mode %s", p.Tiebreak)
+	}
+	return nil
+}
+
+// ConsensusVote is one backend's contribution to a consensus call.
+type ConsensusVote[T any] struct {
+	Backend   int         // Position of the backend's provider in the constructor's list
+	Provider  string      // Name of the backend's provider
+	Response  T           // The backend's response; zero when Err is set
+	Agrees    bool        // Whether the response matches the consensus answer
+	Usage     *TokenUsage // Token usage reported for the backend's call
+	RequestID string      // Request ID of the backend's call, as carried by its hook events
+	Err       error       // Set when the backend's call failed
+}
+
+// ConsensusResponse contains the combined answer of a consensus call and the
+// votes it was derived from.
+type ConsensusResponse[T any] struct {
+	Answer    T                  // Consensus answer; its confidence is the mean over agreeing backends
+	Votes     []ConsensusVote[T] // One vote per backend, in provider order
+	Agreed    int                // Backends whose response matches the answer
+	Agreement float64            // Agreed divided by the number of backends
+	Usage     TokenUsage         // Usage summed across backends, including failed ones
+}
+
+// consensusBackends fires every backend concurrently, each on its own copy
+// of the session so they do not see one another's exchanges. Each backend's
+// hook events carry its position under ConsensusBackendKey.
+func consensusBackends[T any](ctx context.Context, session *Session, providers []string,
+	fire func(ctx context.Context, backend int, session *Session) (Result[T], error)) ([]ConsensusVote[T], TokenUsage) {
+	backends := make([]int, len(providers))
+	for i := range backends {
+		backends[i] = i
+	}
+
+	outcome := runBatch(ctx, backends, len(backends), false, "", func(ctx context.Context, backend int) (Result[T], error) {
+		fork := NewSession()
+		if session != nil {
+			fork.SetMessages(session.Messages())
+		}
+		ctx = ContextWithMeta(ctx, ConsensusBackendKey.Name(), strconv.Itoa(backend))
+		return fire(ctx, backend, fork)
+	})
+
+	votes := make([]ConsensusVote[T], len(providers))
+	for i, result := range outcome.results {
+		votes[i] = ConsensusVote[T]{
+			Backend:   i,
+			Provider:  providers[i],
+			Response:  result.Value,
+			Usage:     result.Usage,
+			RequestID: result.RequestID,
+		}
+	}
+	for _, itemErr := range outcome.errors {
+		var zero T
+		votes[itemErr.Index].Response = zero
+		votes[itemErr.Index].Err = itemErr.Err
+	}
+	return votes, outcome.usage
+}
+
+// majority returns the answer key with the most votes. keys holds each
+// backend's answer and confidences its confidence; failed backends are
+// marked in failed. tied reports an unresolved tie for the top place.
+func majority(keys []string, confidences []float64, failed []bool, tiebreak TiebreakMode) (winner string, tied bool) {
+	counts := make(map[string]int)
+	sums := make(map[string]float64)
+	first := make(map[string]int)
+	var order []string
+	for i, key := range keys {
+		if failed[i] {
+			continue
+		}
+		if _, seen := counts[key]; !seen {
+			first[key] = i
+			order = append(order, key)
+		}
+		counts[key]++
+		sums[key] += confidences[i]
+	}
+	if len(order) == 0 {
+		return "", false
+	}
+
+	best := 0
+	for _, key := range order {
+		best = max(best, counts[key])
+	}
+	var leaders []string
+	for _, key := range order {
+		if counts[key] == best {
+			leaders = append(leaders, key)
+		}
+	}
+	if len(leaders) == 1 {
+		return leaders[0], false
+	}
+
+	switch tiebreak {
+	case TiebreakFirst:
+		// leaders follow backend order, so the first is the earliest backend's
+		return leaders[0], false
+	case TiebreakConfidence:
+		slices.SortStableFunc(leaders, func(a, b string) int {
+			switch {
+			case sums[a] > sums[b]:
+				return -1
+			case sums[a] < sums[b]:
+				return 1
+			default:
+				return 0
+			}
+		})
+		return leaders[0], sums[leaders[0]] == sums[leaders[1]]
+	default:
+		return "", true
+	}
+}
+
+// bordaCount combines rankings into a single order. Each ranking awards an
+// item n-1-position points, where n is the number of distinct items across
+// all rankings; items a ranking omits get none. Items with equal points keep
+// the order of a reference ranking: the most confident one under
+// TiebreakConfidence, otherwise the earliest. tied reports equal points for
+// the top place under TiebreakFail. Failed backends are marked in failed.
+func bordaCount(rankings [][]string, confidences []float64, failed []bool, tiebreak TiebreakMode) (order []string, points map[string]int, tied bool) {
+	reference := -1
+	var items []string
+	seen := make(map[string]bool)
+	for i, ranking := range rankings {
+		if failed[i] {
+			continue
+		}
+		if reference < 0 || (tiebreak == TiebreakConfidence && confidences[i] > confidences[reference]) {
+			reference = i
+		}
+		for _, item := range ranking {
+			if !seen[item] {
+				seen[item] = true
+				items = append(items, item)
+			}
+		}
+	}
+	if reference < 0 {
+		return nil, nil, false
+	}
+
+	points = make(map[string]int, len(items))
+	for i, ranking := range rankings {
+		if failed[i] {
+			continue
+		}
+		for position, item := range ranking {
+			points[item] += len(items) - 1 - position
+		}
+	}
+
+	rank := func(item string) int {
+		if position := slices.Index(rankings[reference], item); position >= 0 {
+			return position
+		}
+		return len(items) + slices.Index(items, item)
+	}
+	order = slices.Clone(items)
+	slices.SortStableFunc(order, func(a, b string) int {
+		if points[a] != points[b] {
+			return points[b] - points[a]
+		}
+		return rank(a) - rank(b)
+	})
+
+	tied = tiebreak == TiebreakFail && len(order) > 1 && points[order[0]] == points[order[1]]
+	return order, points, tied
+}
+
+// settleConsensus marks agreeing votes, fills in the agreement figures, and
+// returns a *NoConsensusError when fewer than required backends agree or the
+// vote is tied.
+func settleConsensus[T any](response *ConsensusResponse[T], agrees func(ConsensusVote[T]) bool, required int, tied bool) error {
+	failed := 0
+	for i := range response.Votes {
+		vote := &response.Votes[i]
+		if vote.Err != nil {
+			failed++
+			continue
+		}
+		vote.Agrees = !tied && agrees(*vote)
+		if vote.Agrees {
+			response.Agreed++
+		}
+	}
+	response.Agreement = float64(response.Agreed) / float64(len(response.Votes))
+
+	if tied || response.Agreed < required {
+		return &NoConsensusError{
+			Agreed:   response.Agreed,
+			Required: required,
+			Backends: len(response.Votes),
+			Failed:   failed,
+			Tied:     tied,
+		}
+	}
+	return nil
+}
+
+// failedVotes reports which votes carry an error.
+func failedVotes[T any](votes []ConsensusVote[T]) []bool {
+	failed := make([]bool, len(votes))
+	for i, vote := range votes {
+		failed[i] = vote.Err != nil
+	}
+	return failed
+}
+
+// recordConsensus adds a single exchange for a consensus call to the session:
+// the prompt sent to the backends and the combined answer, along with the
+// usage summed across backends.
+func recordConsensus(session *Session, prompt *Prompt, answer any, usage TokenUsage) {
+	if session == nil {
+		return
+	}
+	body, err := json.Marshal(answer)
+	if err != nil {
+		return
+	}
+	session.Append(RoleUser, prompt.Render())
+	session.Append(RoleAssistant, string(body))
+	session.SetUsage(&usage)
+}
+
+// providerNames returns the name of each provider.
+func providerNames(providers []Provider) []string {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.Name()
+	}
+	return names
+}
+
+// BinaryConsensusSynapse asks the same yes/no question of several providers
+// and combines their decisions by majority vote.
+type BinaryConsensusSynapse struct {
+	backends  []*BinarySynapse
+	providers []string
+	policy    ConsensusPolicy
+}
+
+// BinaryConsensus creates a binary synapse that fires question against every
+// provider concurrently and returns the decision agreed on by the policy.
+// Each backend is a Binary synapse built with the same options, so
+// reliability options apply to each provider separately.
+//
+// Example:
+//
+//	judge, err := zyn.BinaryConsensus("Is this email spam?",
+//	    []zyn.Provider{openaiProvider, anthropicProvider, geminiProvider},
+//	    zyn.ConsensusPolicy{MinAgree: 2})
+//	isSpam, err := judge.Fire(ctx, session, email)
+func BinaryConsensus(question string, providers []Provider, policy ConsensusPolicy, opts ...Option) (*BinaryConsensusSynapse, error) {
+	if err := policy.check(providers); err != nil {
+		return nil, fmt.Errorf("binary consensus: %w", err)
+	}
+	backends := make([]*BinarySynapse, len(providers))
+	for i, provider := range providers {
+		backend, err := NewBinary(question, provider, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("binary consensus: backend %d: %w", i, err)
+		}
+		backends[i] = backend
+	}
+	return &BinaryConsensusSynapse{
+		backends:  backends,
+		providers: providerNames(providers),
+		policy:    policy,
+	}, nil
+}
+
+// WithDefaults sets default input values for every backend.
+func (b *BinaryConsensusSynapse) WithDefaults(defaults BinaryInput) *BinaryConsensusSynapse {
+	for _, backend := range b.backends {
+		backend.WithDefaults(defaults)
+	}
+	return b
+}
+
+// Fire executes the consensus call and returns the agreed decision, or
+// ErrUndecided if the backends agreed the answer is unknown.
+func (b *BinaryConsensusSynapse) Fire(ctx context.Context, session *Session, input string) (bool, error) {
+	response, err := b.FireWithDetails(ctx, session, input)
+	if err != nil {
+		return false, err
+	}
+	if response.Answer.Unknown {
+		return false, ErrUndecided
+	}
+	return response.Answer.Decision, nil
+}
+
+// FireWithDetails executes the consensus call and returns every backend's
+// vote alongside the agreed response.
+func (b *BinaryConsensusSynapse) FireWithDetails(ctx context.Context, session *Session, input string) (*ConsensusResponse[BinaryResponse], error) {
+	return b.FireWithInput(ctx, session, BinaryInput{Subject: input})
+}
+
+// FireWithInput executes the consensus call with rich input structure.
+// When the backends do not reach consensus, the response is returned with
+// the votes alongside an error matching ErrNoConsensus, and the session is
+// left unchanged.
+func (b *BinaryConsensusSynapse) FireWithInput(ctx context.Context, session *Session, input BinaryInput) (*ConsensusResponse[BinaryResponse], error) {
+	votes, usage := consensusBackends(ctx, session, b.providers,
+		func(ctx context.Context, i int, fork *Session) (Result[BinaryResponse], error) {
+			return b.backends[i].execute(ctx, fork, input, false)
+		})
+	response := &ConsensusResponse[BinaryResponse]{Votes: votes, Usage: usage}
+
+	keys := make([]string, len(votes))
+	confidences := make([]float64, len(votes))
+	for i, vote := range votes {
+		keys[i] = binaryVote(vote.Response)
+		confidences[i] = vote.Response.Confidence
+	}
+	winner, tied := majority(keys, confidences, failedVotes(votes), b.policy.Tiebreak)
+
+	agrees := func(vote ConsensusVote[BinaryResponse]) bool {
+		return binaryVote(vote.Response) == winner
+	}
+	if err := settleConsensus(response, agrees, b.policy.required(len(votes)), tied); err != nil {
+		return response, err
+	}
+	response.Answer = consensusAnswer(response.Votes, func(r BinaryResponse) float64 { return r.Confidence },
+		func(r *BinaryResponse, confidence float64) { r.Confidence = confidence })
+
+	merged := b.backends[0].mergeInputs(input)
+	recordConsensus(session, b.backends[0].buildPrompt(merged), response.Answer, usage)
+	return response, nil
+}
+
+// binaryVote returns the outcome a binary response votes for: true, false,
+// or unknown for an abstention allowed by WithAbstain.
+func binaryVote(response BinaryResponse) string {
+	if response.Unknown {
+		return "unknown"
+	}
+	return strconv.FormatBool(response.Decision)
+}
+
+// consensusAnswer returns the most confident agreeing response with its
+// confidence replaced by the mean over agreeing responses.
+func consensusAnswer[T any](votes []ConsensusVote[T], confidence func(T) float64, setConfidence func(*T, float64)) T {
+	var (
+		answer T
+		best   = -1.0
+		sum    float64
+		count  int
+	)
+	for _, vote := range votes {
+		if !vote.Agrees {
+			continue
+		}
+		c := confidence(vote.Response)
+		sum += c
+		count++
+		if c > best {
+			best = c
+			answer = vote.Response
+		}
+	}
+	if count > 0 {
+		setConfidence(&answer, sum/float64(count))
+	}
+	return answer
+}
+
+// ClassificationConsensusSynapse asks several providers to classify the same
+// input and combines their primary categories by majority vote.
+type ClassificationConsensusSynapse struct {
+	backends  []*ClassificationSynapse
+	providers []string
+	policy    ConsensusPolicy
+}
+
+// ClassificationConsensus creates a classification synapse that fires against
+// every provider concurrently and returns the primary category agreed on by
+// the policy. Categories are compared case-insensitively. Each backend is a
+// Classification synapse built with the same options.
+func ClassificationConsensus(question string, categories []string, providers []Provider, policy ConsensusPolicy, opts ...Option) (*ClassificationConsensusSynapse, error) {
+	if err := policy.check(providers); err != nil {
+		return nil, fmt.Errorf("classification consensus: %w", err)
+	}
+	backends := make([]*ClassificationSynapse, len(providers))
+	for i, provider := range providers {
+		backend, err := NewClassification(question, categories, provider, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("classification consensus: backend %d: %w", i, err)
+		}
+		backends[i] = backend
+	}
+	return &ClassificationConsensusSynapse{
+		backends:  backends,
+		providers: providerNames(providers),
+		policy:    policy,
+	}, nil
+}
+
+// WithDefaults sets default input values for every backend.
+func (c *ClassificationConsensusSynapse) WithDefaults(defaults ClassificationInput) *ClassificationConsensusSynapse {
+	for _, backend := range c.backends {
+		backend.WithDefaults(defaults)
+	}
+	return c
+}
+
+// Fire executes the consensus call and returns the agreed primary category.
+func (c *ClassificationConsensusSynapse) Fire(ctx context.Context, session *Session, input string) (string, error) {
+	response, err := c.FireWithDetails(ctx, session, input)
+	if err != nil {
+		return "", err
+	}
+	return response.Answer.Primary, nil
+}
+
+// FireWithDetails executes the consensus call and returns every backend's
+// vote alongside the agreed response.
+func (c *ClassificationConsensusSynapse) FireWithDetails(ctx context.Context, session *Session, input string) (*ConsensusResponse[ClassificationResponse], error) {
+	return c.FireWithInput(ctx, session, ClassificationInput{Subject: input})
+}
+
+// FireWithInput executes the consensus call with rich input structure.
+// When the backends do not reach consensus, the response is returned with
+// the votes alongside an error matching ErrNoConsensus, and the session is
+// left unchanged.
+func (c *ClassificationConsensusSynapse) FireWithInput(ctx context.Context, session *Session, input ClassificationInput) (*ConsensusResponse[ClassificationResponse], error) {
+	votes, usage := consensusBackends(ctx, session, c.providers,
+		func(ctx context.Context, i int, fork *Session) (Result[ClassificationResponse], error) {
+			return c.backends[i].execute(ctx, fork, input)
+		})
+	response := &ConsensusResponse[ClassificationResponse]{Votes: votes, Usage: usage}
+
+	keys := make([]string, len(votes))
+	confidences := make([]float64, len(votes))
+	for i, vote := range votes {
+		keys[i] = strings.ToLower(vote.Response.Primary)
+		confidences[i] = vote.Response.Confidence
+	}
+	winner, tied := majority(keys, confidences, failedVotes(votes), c.policy.Tiebreak)
+
+	agrees := func(vote ConsensusVote[ClassificationResponse]) bool {
+		return strings.ToLower(vote.Response.Primary) == winner
+	}
+	if err := settleConsensus(response, agrees, c.policy.required(len(votes)), tied); err != nil {
+		return response, err
+	}
+	response.Answer = consensusAnswer(response.Votes, func(r ClassificationResponse) float64 { return r.Confidence },
+		func(r *ClassificationResponse, confidence float64) { r.Confidence = confidence })
+
+	merged := c.backends[0].mergeInputs(input)
+	recordConsensus(session, c.backends[0].buildPrompt(merged), response.Answer, usage)
+	return response, nil
+}
+
+// RankingConsensusSynapse asks several providers to rank the same items and
+// combines their rankings with a Borda count.
+type RankingConsensusSynapse struct {
+	backends  []*RankingSynapse
+	providers []string
+	policy    ConsensusPolicy
+}
+
+// RankingConsensus creates a ranking synapse that fires against every
+// provider concurrently and combines their rankings with a Borda count. A
+// backend agrees with the consensus when it ranks the same item first, and
+// the policy's MinAgree applies to those backends. Each backend is a Ranking
+// synapse built with the same options.
+func RankingConsensus(criteria string, providers []Provider, policy ConsensusPolicy, opts ...Option) (*RankingConsensusSynapse, error) {
+	if err := policy.check(providers); err != nil {
+		return nil, fmt.Errorf("ranking consensus: %w", err)
+	}
+	backends := make([]*RankingSynapse, len(providers))
+	for i, provider := range providers {
+		backend, err := NewRanking(criteria, provider, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("ranking consensus: backend %d: %w", i, err)
+		}
+		backends[i] = backend
+	}
+	return &RankingConsensusSynapse{
+		backends:  backends,
+		providers: providerNames(providers),
+		policy:    policy,
+	}, nil
+}
+
+// WithDefaults sets default input values for every backend.
+func (r *RankingConsensusSynapse) WithDefaults(defaults RankingInput) *RankingConsensusSynapse {
+	for _, backend := range r.backends {
+		backend.WithDefaults(defaults)
+	}
+	return r
+}
+
+// Fire executes the consensus call and returns the combined ranking.
+func (r *RankingConsensusSynapse) Fire(ctx context.Context, session *Session, items []string) ([]string, error) {
+	response, err := r.FireWithDetails(ctx, session, items)
+	if err != nil {
+		return nil, err
+	}
+	return response.Answer.Ranked, nil
+}
+
+// FireWithDetails executes the consensus call and returns every backend's
+// ranking alongside the combined one.
+func (r *RankingConsensusSynapse) FireWithDetails(ctx context.Context, session *Session, items []string) (*ConsensusResponse[RankingResponse], error) {
+	return r.FireWithInput(ctx, session, RankingInput{Items: items})
+}
+
+// FireWithInput executes the consensus call with rich input structure.
+// The combined ranking is cut to TopN when set. When the backends do not
+// reach consensus, the response is returned with the votes alongside an
+// error matching ErrNoConsensus, and the session is left unchanged.
+func (r *RankingConsensusSynapse) FireWithInput(ctx context.Context, session *Session, input RankingInput) (*ConsensusResponse[RankingResponse], error) {
+	votes, usage := consensusBackends(ctx, session, r.providers,
+		func(ctx context.Context, i int, fork *Session) (Result[RankingResponse], error) {
+			return r.backends[i].execute(ctx, fork, input)
+		})
+	response := &ConsensusResponse[RankingResponse]{Votes: votes, Usage: usage}
+
+	rankings := make([][]string, len(votes))
+	confidences := make([]float64, len(votes))
+	for i, vote := range votes {
+		rankings[i] = vote.Response.Ranked
+		confidences[i] = vote.Response.Confidence
+	}
+	order, points, tied := bordaCount(rankings, confidences, failedVotes(votes), r.policy.Tiebreak)
+
+	agrees := func(vote ConsensusVote[RankingResponse]) bool {
+		return len(order) > 0 && len(vote.Response.Ranked) > 0 && vote.Response.Ranked[0] == order[0]
+	}
+	if err := settleConsensus(response, agrees, r.policy.required(len(votes)), tied); err != nil {
+		return response, err
+	}
+
+	merged := r.backends[0].mergeInputs(input)
+	if merged.TopN > 0 && len(order) > merged.TopN {
+		order = order[:merged.TopN]
+	}
+	var confidence float64
+	for _, vote := range response.Votes {
+		if vote.Agrees {
+			confidence += vote.Response.Confidence
+		}
+	}
+	response.Answer = RankingResponse{
+		Ranked:     order,
+		Confidence: confidence / float64(response.Agreed),
+		Reasoning: []string{
+			fmt.Sprintf("Borda count over %d rankings; %d of %d backends ranked %q first",
+				len(votes)-countFailed(votes), response.Agreed, len(votes), order[0]),
+			fmt.Sprintf("Points: %s", formatPoints(order, points)),
+		},
+	}
+
+	recordConsensus(session, r.backends[0].buildPrompt(merged), response.Answer, usage)
+	return response, nil
+}
+
+// countFailed returns the number of votes carrying an error.
+func countFailed[T any](votes []ConsensusVote[T]) int {
+	count := 0
+	for _, vote := range votes {
+		if vote.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// formatPoints renders the Borda points of items in order.
+func formatPoints(order []string, points map[string]int) string {
+	parts := make([]string, len(order))
+	for i, item := range order {
+		parts[i] = fmt.Sprintf("%s=%d", item, points[item])
+	}
+	return strings.Join(parts, ", ")
+}
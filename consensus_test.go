@@ -0,0 +1,414 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+func TestConsensusPolicy_Check(t *testing.T) {
+	providers := []Provider{NewMockProvider(), NewMockProvider()}
+
+	tests := []struct {
+		name      string
+		policy    ConsensusPolicy
+		providers []Provider
+		wantErr   bool
+	}{
+		{"majority default", ConsensusPolicy{}, providers, false},
+		{"unanimous", ConsensusPolicy{MinAgree: 2}, providers, false},
+		{"single provider", ConsensusPolicy{}, providers[:1], true},
+		{"nil provider", ConsensusPolicy{}, []Provider{providers[0], nil}, true},
+		{"min agree too high", ConsensusPolicy{MinAgree: 3}, providers, true},
+		{"unknown tiebreak", ConsensusPolicy{Tiebreak: TiebreakMode(9)}, providers, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BinaryConsensus("valid?", tt.providers, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if (ConsensusPolicy{}).required(3) != 2 || (ConsensusPolicy{}).required(4) != 3 {
+		t.Error("expected default to require a strict majority")
+	}
+}
+
+func TestBinaryConsensus(t *testing.T) {
+	t.Run("agreeing backends", func(t *testing.T) {
+		synapse, err := BinaryConsensus("Is this spam?", []Provider{
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.7, "reasoning": ["yes"]}`),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.8, "reasoning": ["yes"]}`),
+		}, ConsensusPolicy{MinAgree: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		session := NewSession()
+		response, err := synapse.FireWithDetails(context.Background(), session, "win a prize")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !response.Answer.Decision || response.Agreed != 3 || response.Agreement != 1 {
+			t.Errorf("expected unanimous true, got %+v", response)
+		}
+		if response.Answer.Confidence < 0.799 || response.Answer.Confidence > 0.801 {
+			t.Errorf("expected mean confidence 0.8, got %f", response.Answer.Confidence)
+		}
+		if response.Usage.Total != 450 {
+			t.Errorf("expected usage summed across backends, got %d", response.Usage.Total)
+		}
+		for i, vote := range response.Votes {
+			if vote.Backend != i || !vote.Agrees || vote.Usage == nil || vote.RequestID == "" {
+				t.Errorf("unexpected vote %d: %+v", i, vote)
+			}
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one exchange in session, got %d messages", session.Len())
+		}
+		if usage := session.LastUsage(); usage == nil || usage.Total != 450 {
+			t.Errorf("expected session usage 450, got %+v", usage)
+		}
+	})
+
+	t.Run("split backends", func(t *testing.T) {
+		synapse, err := BinaryConsensus("Is this spam?", []Provider{
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+			NewMockProviderWithResponse(`{"decision": false, "confidence": 0.6, "reasoning": ["no"]}`),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.7, "reasoning": ["yes"]}`),
+		}, ConsensusPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "win a prize")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !response.Answer.Decision || response.Agreed != 2 {
+			t.Errorf("expected majority true, got %+v", response)
+		}
+		if response.Agreement < 0.66 || response.Agreement > 0.67 {
+			t.Errorf("expected agreement 2/3, got %f", response.Agreement)
+		}
+		if response.Votes[1].Agrees || response.Votes[1].Response.Decision {
+			t.Errorf("expected dissenting vote recorded, got %+v", response.Votes[1])
+		}
+	})
+
+	t.Run("min agree not met", func(t *testing.T) {
+		synapse, err := BinaryConsensus("Is this spam?", []Provider{
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+			NewMockProviderWithResponse(`{"decision": false, "confidence": 0.6, "reasoning": ["no"]}`),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.7, "reasoning": ["yes"]}`),
+		}, ConsensusPolicy{MinAgree: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		session := NewSession()
+		response, err := synapse.FireWithDetails(context.Background(), session, "win a prize")
+		if !errors.Is(err, ErrNoConsensus) {
+			t.Fatalf("expected ErrNoConsensus, got %v", err)
+		}
+		var consensusErr *NoConsensusError
+		if !errors.As(err, &consensusErr) || consensusErr.Agreed != 2 || consensusErr.Required != 3 {
+			t.Errorf("expected 2 of 3 required, got %+v", consensusErr)
+		}
+		if response == nil || len(response.Votes) != 3 {
+			t.Fatalf("expected votes returned with the error, got %+v", response)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("tie", func(t *testing.T) {
+		providers := []Provider{
+			NewMockProviderWithResponse(`{"decision": false, "confidence": 0.6, "reasoning": ["no"]}`),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+		}
+
+		synapse, err := BinaryConsensus("Is this spam?", providers, ConsensusPolicy{MinAgree: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = synapse.Fire(context.Background(), NewSession(), "win a prize")
+		var consensusErr *NoConsensusError
+		if !errors.As(err, &consensusErr) || !consensusErr.Tied {
+			t.Errorf("expected tied error, got %v", err)
+		}
+
+		synapse, _ = BinaryConsensus("Is this spam?", providers, ConsensusPolicy{MinAgree: 1, Tiebreak: TiebreakConfidence})
+		decision, err := synapse.Fire(context.Background(), NewSession(), "win a prize")
+		if err != nil || !decision {
+			t.Errorf("expected more confident true, got %v, %v", decision, err)
+		}
+
+		synapse, _ = BinaryConsensus("Is this spam?", providers, ConsensusPolicy{MinAgree: 1, Tiebreak: TiebreakFirst})
+		decision, err = synapse.Fire(context.Background(), NewSession(), "win a prize")
+		if err != nil || decision {
+			t.Errorf("expected first backend's false, got %v, %v", decision, err)
+		}
+	})
+
+	t.Run("failed backend", func(t *testing.T) {
+		synapse, err := BinaryConsensus("Is this spam?", []Provider{
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+			NewMockProviderWithError("backend down"),
+			NewMockProviderWithResponse(`{"decision": true, "confidence": 0.7, "reasoning": ["yes"]}`),
+		}, ConsensusPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "win a prize")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Votes[1].Err == nil || response.Votes[1].Agrees {
+			t.Errorf("expected failed vote, got %+v", response.Votes[1])
+		}
+		if response.Agreed != 2 {
+			t.Errorf("expected 2 agreeing, got %d", response.Agreed)
+		}
+	})
+
+	t.Run("backends see shared history only", func(t *testing.T) {
+		var mu sync.Mutex
+		var lengths []int
+		record := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			return `{"decision": true, "confidence": 0.8, "reasoning": ["yes"]}`, nil
+		})
+		counting := &consensusHistoryProvider{Provider: record, mu: &mu, lengths: &lengths}
+
+		synapse, err := BinaryConsensus("Is this spam?", []Provider{counting, counting}, ConsensusPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		session := NewSession()
+		session.Append(RoleUser, "earlier question")
+		session.Append(RoleAssistant, "earlier answer")
+
+		if _, err := synapse.Fire(context.Background(), session, "win a prize"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(lengths, []int{3, 3}) {
+			t.Errorf("expected each backend to see history plus its prompt, got %v", lengths)
+		}
+		if session.Len() != 4 {
+			t.Errorf("expected one exchange added, got %d messages", session.Len())
+		}
+	})
+}
+
+func TestBinaryConsensus_Abstain(t *testing.T) {
+	unknown := `{"decision": null, "unknown": true, "confidence": 0.8, "reasoning": ["no information"]}`
+	synapse, err := BinaryConsensus("Is this spam?", []Provider{
+		NewMockProviderWithResponse(unknown),
+		NewMockProviderWithResponse(`{"decision": false, "confidence": 0.6, "reasoning": ["no"]}`),
+		NewMockProviderWithResponse(unknown),
+	}, ConsensusPolicy{}, WithAbstain())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := synapse.FireWithDetails(context.Background(), NewSession(), "see attached")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.Answer.Unknown || response.Agreed != 2 || response.Votes[1].Agrees {
+		t.Errorf("expected abstentions counted apart from false, got %+v", response)
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), "see attached"); !errors.Is(err, ErrUndecided) {
+		t.Errorf("expected ErrUndecided, got %v", err)
+	}
+}
+
+// consensusHistoryProvider records how many messages each call receives.
+type consensusHistoryProvider struct {
+	Provider
+	mu      *sync.Mutex
+	lengths *[]int
+}
+
+func (p *consensusHistoryProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	p.mu.Lock()
+	*p.lengths = append(*p.lengths, len(messages))
+	p.mu.Unlock()
+	return p.Provider.Call(ctx, messages, temperature)
+}
+
+func TestBinaryConsensus_Hooks(t *testing.T) {
+	synapse, err := BinaryConsensus("Is this spam?", []Provider{
+		NewMockProviderWithResponse(`{"decision": true, "confidence": 0.9, "reasoning": ["yes"]}`),
+		NewMockProviderWithResponse(`{"decision": true, "confidence": 0.7, "reasoning": ["yes"]}`),
+	}, ConsensusPolicy{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		backends []string
+	)
+	wg.Add(2)
+	listener := capitan.Hook(RequestCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		backend, _ := ConsensusBackendKey.From(e)
+		mu.Lock()
+		backends = append(backends, backend)
+		mu.Unlock()
+	})
+	defer listener.Close()
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "win a prize"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+
+	slices.Sort(backends)
+	if !slices.Equal(backends, []string{"0", "1"}) {
+		t.Errorf("expected events attributed to each backend, got %v", backends)
+	}
+}
+
+func TestClassificationConsensus(t *testing.T) {
+	categories := []string{"bug", "feature", "question"}
+	classify := func(primary, confidence string) Provider {
+		return NewMockProviderWithResponse(`{"primary": "` + primary + `", "confidence": ` + confidence + `, "reasoning": ["r"]}`)
+	}
+
+	t.Run("majority", func(t *testing.T) {
+		synapse, err := ClassificationConsensus("Ticket type?", categories, []Provider{
+			classify("bug", "0.9"),
+			classify("Bug", "0.7"),
+			classify("feature", "0.8"),
+		}, ConsensusPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "app crashes")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Answer.Primary != "bug" || response.Agreed != 2 {
+			t.Errorf("expected bug by 2 votes, got %+v", response)
+		}
+		if response.Votes[2].Agrees {
+			t.Error("expected feature vote to disagree")
+		}
+	})
+
+	t.Run("three-way split", func(t *testing.T) {
+		synapse, err := ClassificationConsensus("Ticket type?", categories, []Provider{
+			classify("bug", "0.6"),
+			classify("feature", "0.9"),
+			classify("question", "0.7"),
+		}, ConsensusPolicy{MinAgree: 1, Tiebreak: TiebreakConfidence})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		category, err := synapse.Fire(context.Background(), NewSession(), "add dark mode")
+		if err != nil || category != "feature" {
+			t.Errorf("expected most confident feature, got %q, %v", category, err)
+		}
+
+		synapse, _ = ClassificationConsensus("Ticket type?", categories, []Provider{
+			classify("bug", "0.6"),
+			classify("feature", "0.9"),
+			classify("question", "0.7"),
+		}, ConsensusPolicy{})
+		if _, err := synapse.Fire(context.Background(), NewSession(), "add dark mode"); !errors.Is(err, ErrNoConsensus) {
+			t.Errorf("expected ErrNoConsensus, got %v", err)
+		}
+	})
+}
+
+func TestRankingConsensus(t *testing.T) {
+	rank := func(ranked, confidence string) Provider {
+		return NewMockProviderWithResponse(`{"ranked": ` + ranked + `, "confidence": ` + confidence + `, "reasoning": ["r"]}`)
+	}
+	items := []string{"a", "b", "c"}
+
+	t.Run("borda count", func(t *testing.T) {
+		synapse, err := RankingConsensus("priority", []Provider{
+			rank(`["a", "b", "c"]`, "0.9"),
+			rank(`["b", "a", "c"]`, "0.6"),
+			rank(`["a", "c", "b"]`, "0.7"),
+		}, ConsensusPolicy{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		session := NewSession()
+		response, err := synapse.FireWithDetails(context.Background(), session, items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// a: 2+1+2=5, b: 1+2+0=3, c: 0+0+1=1
+		if !slices.Equal(response.Answer.Ranked, []string{"a", "b", "c"}) {
+			t.Errorf("expected [a b c], got %v", response.Answer.Ranked)
+		}
+		if response.Agreed != 2 || response.Votes[1].Agrees {
+			t.Errorf("expected the two backends ranking a first to agree, got %+v", response.Votes)
+		}
+		if err := response.Answer.Validate(); err != nil {
+			t.Errorf("expected valid answer, got %v", err)
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one exchange in session, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("top n", func(t *testing.T) {
+		synapse, _ := RankingConsensus("priority", []Provider{
+			rank(`["a", "b", "c"]`, "0.9"),
+			rank(`["a", "c", "b"]`, "0.7"),
+		}, ConsensusPolicy{})
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), RankingInput{Items: items, TopN: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(response.Answer.Ranked, []string{"a"}) {
+			t.Errorf("expected [a], got %v", response.Answer.Ranked)
+		}
+	})
+
+	t.Run("tie", func(t *testing.T) {
+		providers := []Provider{
+			rank(`["a", "b", "c"]`, "0.6"),
+			rank(`["b", "a", "c"]`, "0.9"),
+		}
+
+		synapse, _ := RankingConsensus("priority", providers, ConsensusPolicy{MinAgree: 1})
+		_, err := synapse.Fire(context.Background(), NewSession(), items)
+		var consensusErr *NoConsensusError
+		if !errors.As(err, &consensusErr) || !consensusErr.Tied {
+			t.Errorf("expected tied error, got %v", err)
+		}
+
+		synapse, _ = RankingConsensus("priority", providers, ConsensusPolicy{MinAgree: 1, Tiebreak: TiebreakConfidence})
+		ranked, err := synapse.Fire(context.Background(), NewSession(), items)
+		if err != nil || !slices.Equal(ranked, []string{"b", "a", "c"}) {
+			t.Errorf("expected most confident order [b a c], got %v, %v", ranked, err)
+		}
+
+		synapse, _ = RankingConsensus("priority", providers, ConsensusPolicy{MinAgree: 1, Tiebreak: TiebreakFirst})
+		ranked, err = synapse.Fire(context.Background(), NewSession(), items)
+		if err != nil || !slices.Equal(ranked, []string{"a", "b", "c"}) {
+			t.Errorf("expected first backend's order [a b c], got %v, %v", ranked, err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package zyn
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/zoobzio/capitan"
+)
+
+// contextKey is the type for zyn's context keys, preventing collisions with other packages.
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	parentRequestIDContextKey
+	sessionContextKey
+	metaContextKey
+	callRecorderContextKey
+	previewContextKey
+	progressContextKey
+	auditContextKey
+	retryStopContextKey
+	callParamsContextKey
+	attemptsContextKey
+	retryAttemptsContextKey
+)
+
+// ContextWithRequestID returns a context carrying the request ID.
+// Every synapse call still gets its own request ID; calls fired with this
+// context record id as their parent request ID, so callers can correlate
+// all the calls made for one of their requests with their own logs.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx.
+// Inside a synapse's pipeline, including custom stages and provider calls,
+// this is the ID of the request being served.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey).(string)
+	return id, ok && id != ""
+}
+
+// ParentRequestIDFromContext returns the parent request ID of the request
+// being served: the ID the caller set with ContextWithRequestID, or the ID of
+// the enclosing request when a synapse is fired from inside another's pipeline.
+func ParentRequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(parentRequestIDContextKey).(string)
+	return id, ok && id != ""
+}
+
+// contextWithRequest returns a context serving the request with the given ID.
+// The request ID ctx already carries, if any, becomes the parent request ID.
+func contextWithRequest(ctx context.Context, requestID string) context.Context {
+	parent, _ := RequestIDFromContext(ctx)
+	ctx = context.WithValue(ctx, parentRequestIDContextKey, parent)
+	return ContextWithRequestID(ctx, requestID)
+}
+
+// SessionFromContext returns the session of the request being served.
+// It is set for code running inside a synapse's pipeline.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionContextKey).(*Session)
+	return session, ok && session != nil
+}
+
+// contextWithSession returns a context carrying the session.
+func contextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey, session)
+}
+
+// CallParamsFromContext returns the parameters of the provider call being
+// made with ctx. Providers use it to pass on settings that the Provider
+// interface does not carry.
+func CallParamsFromContext(ctx context.Context) (CallParams, bool) {
+	params, ok := ctx.Value(callParamsContextKey).(CallParams)
+	return params, ok
+}
+
+// contextWithCallParams returns a context carrying the call parameters.
+func contextWithCallParams(ctx context.Context, params CallParams) context.Context {
+	return context.WithValue(ctx, callParamsContextKey, params)
+}
+
+// ContextWithMeta returns a context carrying an additional metadata value.
+// Metadata is added to every hook event emitted for requests made with the
+// context, using key as the field name. Setting an existing key replaces its value.
+func ContextWithMeta(ctx context.Context, key, value string) context.Context {
+	meta := maps.Clone(MetaFromContext(ctx))
+	if meta == nil {
+		meta = make(map[string]string, 1)
+	}
+	meta[key] = value
+	return context.WithValue(ctx, metaContextKey, meta)
+}
+
+// MetaFromContext returns the metadata carried by ctx.
+// The returned map must not be modified.
+func MetaFromContext(ctx context.Context) map[string]string {
+	meta, ok := ctx.Value(metaContextKey).(map[string]string)
+	if !ok {
+		return nil
+	}
+	return meta
+}
+
+// HookFields returns fields extended with the request context: the request
+// ID and parent request ID, when ctx carries them and fields do not already
+// include them, followed by metadata fields in key order. Providers use it so their hook events carry
+// the same correlation data as the synapse's own events.
+func HookFields(ctx context.Context, fields ...capitan.Field) []capitan.Field {
+	meta := MetaFromContext(ctx)
+	result := make([]capitan.Field, 0, len(fields)+len(meta)+2)
+	result = append(result, fields...)
+
+	if id, ok := RequestIDFromContext(ctx); ok && !hasField(fields, RequestIDKey) {
+		result = append(result, RequestIDKey.Field(id))
+	}
+	if id, ok := ParentRequestIDFromContext(ctx); ok && !hasField(fields, ParentRequestIDKey) {
+		result = append(result, ParentRequestIDKey.Field(id))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(meta)) {
+		result = append(result, capitan.NewStringKey(key).Field(meta[key]))
+	}
+	return result
+}
+
+// hasField reports whether fields include a field for key.
+func hasField(fields []capitan.Field, key capitan.Key) bool {
+	for _, field := range fields {
+		if field.Key().Name() == key.Name() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package zyn
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/pipz"
+)
+
+// contextRecordingProvider records the request context it is called with.
+type contextRecordingProvider struct {
+	requestID string
+	parentID  string
+	session   *Session
+	meta      map[string]string
+}
+
+func (p *contextRecordingProvider) Call(ctx context.Context, _ []Message, _ float32) (*ProviderResponse, error) {
+	p.requestID, _ = RequestIDFromContext(ctx)
+	p.parentID, _ = ParentRequestIDFromContext(ctx)
+	p.session, _ = SessionFromContext(ctx)
+	p.meta = MetaFromContext(ctx)
+	return &ProviderResponse{
+		Content: `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`,
+		Usage:   TokenUsage{Prompt: 10, Completion: 5, Total: 15},
+	}, nil
+}
+
+func (*contextRecordingProvider) Name() string {
+	return "context-recorder"
+}
+
+// recordContextStage is an option adding a stage that records the request context.
+func recordContextStage(requestID *string, session **Session) Option {
+	identity := pipz.NewIdentity("test:record-context", "Records request context")
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		record := pipz.Effect(identity, func(ctx context.Context, _ *SynapseRequest) error {
+			*requestID, _ = RequestIDFromContext(ctx)
+			*session, _ = SessionFromContext(ctx)
+			return nil
+		})
+		return pipz.NewSequence(identity, record, pipeline)
+	}
+}
+
+func TestContext_Propagation(t *testing.T) {
+	provider := &contextRecordingProvider{}
+	var stageRequestID string
+	var stageSession *Session
+
+	synapse, err := Binary("valid", provider, recordContextStage(&stageRequestID, &stageSession))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var hookRequestID, hookParentID, hookTenant string
+	wg.Add(1)
+	listener := capitan.Hook(RequestCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		hookRequestID, _ = RequestIDKey.From(e)
+		hookParentID, _ = ParentRequestIDKey.From(e)
+		hookTenant, _ = capitan.NewStringKey("tenant").From(e)
+	})
+	defer listener.Close()
+
+	session := NewSession()
+	ctx := ContextWithMeta(ContextWithRequestID(context.Background(), "req-123"), "tenant", "acme")
+
+	result, err := synapse.FireResult(ctx, session, "input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.RequestID == "" || result.RequestID == "req-123" {
+		t.Fatalf("expected generated request ID for the call, got %q", result.RequestID)
+	}
+	if stageRequestID != result.RequestID || provider.requestID != result.RequestID {
+		t.Errorf("expected call request ID in stage and provider, got %q and %q", stageRequestID, provider.requestID)
+	}
+	if provider.parentID != "req-123" {
+		t.Errorf("expected context request ID as parent in provider, got %q", provider.parentID)
+	}
+	if stageSession != session || provider.session != session {
+		t.Error("expected session in stage and provider context")
+	}
+	if provider.meta["tenant"] != "acme" {
+		t.Errorf("expected meta in provider context, got %v", provider.meta)
+	}
+
+	wg.Wait()
+	if hookRequestID != result.RequestID || hookParentID != "req-123" {
+		t.Errorf("expected hook request ID %q with parent req-123, got %q and %q", result.RequestID, hookRequestID, hookParentID)
+	}
+	if hookTenant != "acme" {
+		t.Errorf("expected meta merged into hook event, got %q", hookTenant)
+	}
+}
+
+func TestContext_GeneratedRequestID(t *testing.T) {
+	provider := &contextRecordingProvider{}
+	synapse, err := Binary("valid", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.requestID == "" {
+		t.Error("expected generated request ID in provider context")
+	}
+	if provider.parentID != "" {
+		t.Errorf("expected no parent request ID, got %q", provider.parentID)
+	}
+}
+
+func TestContext_DistinctRequestIDs(t *testing.T) {
+	provider := &contextRecordingProvider{}
+	synapse, err := Binary("valid", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-shared")
+	first, err := synapse.FireResult(ctx, NewSession(), "first")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := synapse.FireResult(ctx, NewSession(), "second")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first.RequestID == second.RequestID {
+		t.Errorf("expected distinct request IDs per call, both were %q", first.RequestID)
+	}
+	if provider.parentID != "req-shared" {
+		t.Errorf("expected shared context ID as parent, got %q", provider.parentID)
+	}
+}
+
+func TestContext_Accessors(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := RequestIDFromContext(ctx); ok {
+		t.Error("expected no request ID")
+	}
+	if _, ok := ParentRequestIDFromContext(ctx); ok {
+		t.Error("expected no parent request ID")
+	}
+	if _, ok := SessionFromContext(ctx); ok {
+		t.Error("expected no session")
+	}
+	if MetaFromContext(ctx) != nil {
+		t.Error("expected no meta")
+	}
+	if _, ok := RequestIDFromContext(ContextWithRequestID(ctx, "")); ok {
+		t.Error("expected empty request ID to be treated as absent")
+	}
+
+	parent := ContextWithMeta(ctx, "a", "1")
+	child := ContextWithMeta(parent, "a", "2")
+	child = ContextWithMeta(child, "b", "3")
+	if got := MetaFromContext(parent); len(got) != 1 || got["a"] != "1" {
+		t.Errorf("expected parent meta unchanged, got %v", got)
+	}
+	if got := MetaFromContext(child); len(got) != 2 || got["a"] != "2" || got["b"] != "3" {
+		t.Errorf("unexpected child meta: %v", got)
+	}
+}
+
+func TestHookFields(t *testing.T) {
+	ctx := ContextWithMeta(ContextWithMeta(ContextWithRequestID(context.Background(), "req-1"), "zone", "eu"), "app", "web")
+
+	fields := HookFields(ctx, ProviderKey.Field("mock"))
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Key().Name()
+	}
+	expected := []string{ProviderKey.Name(), RequestIDKey.Name(), "app", "zone"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected fields %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected fields %v, got %v", expected, names)
+			break
+		}
+	}
+
+	// An explicit request ID field is not duplicated
+	fields = HookFields(ctx, RequestIDKey.Field("explicit"))
+	count := 0
+	for _, field := range fields {
+		if field.Key().Name() == RequestIDKey.Name() {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected one request ID field, got %d", count)
+	}
+
+	if got := HookFields(context.Background(), ProviderKey.Field("mock")); len(got) != 1 {
+		t.Errorf("expected fields unchanged without context values, got %d", len(got))
+	}
+}
@@ -1,39 +1,331 @@
 package zyn
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/zoobzio/pipz"
 )
 
+// DefaultConvertMaxExamples is the number of example pairs a conversion
+// prompt includes unless ConvertInput.MaxExamples says otherwise.
+const DefaultConvertMaxExamples = 5
+
 // ConvertInput contains rich input structure for conversion.
-type ConvertInput[T any] struct {
-	Data        T       // The structured data to convert
-	Context     string  // Optional context for conversion
-	Rules       string  // Optional conversion rules or mappings
-	Temperature float32 // Temperature for conversion
+type ConvertInput[TIn, TOut any] struct {
+	Data        TIn                         // The structured data to convert
+	Context     string                      // Optional context for conversion
+	Rules       string                      // Optional conversion rules or mappings
+	Constraints []string                    // Optional rules the output must follow, added after the built-in ones
+	Examples    []ConvertExample[TIn, TOut] // Optional worked conversions, shown to the LLM in order
+	MaxExamples int                         // Most examples in the prompt; values below 1 use DefaultConvertMaxExamples
+	Temperature float32                     // Temperature for conversion
+}
+
+// ConvertExample is a worked conversion: an input and the output it should
+// convert to.
+type ConvertExample[TIn, TOut any] struct {
+	Input  TIn
+	Output TOut
+}
+
+// ExamplePair is a worked example rendered in a prompt, with its input and
+// output as JSON.
+type ExamplePair struct {
+	Input  string
+	Output string
+}
+
+// ConvertResponse contains a converted value with a report of how the input
+// was used.
+type ConvertResponse[T Validator] struct {
+	Data             T        `json:"data"`               // The converted value
+	SourceFieldsUsed []string `json:"source_fields_used"` // Input fields the value was taken from
+	AssumedFields    []string `json:"assumed_fields"`     // Output fields whose values were not taken from the input
+	Confidence       float64  `json:"confidence"`         // 0.0 to 1.0 confidence score
+	Reasoning        []string `json:"reasoning"`          // Explanation of the conversion
+}
+
+// Validate checks if the response is valid, including the converted value.
+func (r ConvertResponse[T]) Validate() error {
+	if err := r.Data.Validate(); err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	return nil
+}
+
+// DefaultConvertBatchSize is the number of items FireMany converts in one
+// provider call unless ManyOptions.BatchSize says otherwise.
+const DefaultConvertBatchSize = 20
+
+// ManyOptions configures a call that packs several items into each provider
+// call.
+type ManyOptions struct {
+	BatchSize   int  // Maximum items per provider call; values below 1 use the synapse's default
+	Concurrency int  // Maximum calls in flight; values below 1 run calls sequentially
+	StopOnError bool // Stop at the first failed provider call
+}
+
+// convertedItem is one output of a batched conversion, tagged with the index
+// of its input item.
+type convertedItem[T any] struct {
+	Index  int `json:"index"`  // Index of the input item, as given in the prompt
+	Output T   `json:"output"` // The converted item
+}
+
+// convertedBatch is the response of a batched conversion call. Outputs are
+// validated one by one by FireMany, so one invalid output does not fail the
+// others.
+type convertedBatch[T any] struct {
+	Items []convertedItem[T] `json:"items"`
+}
+
+// Validate accepts any decoded batch; indices are checked against the
+// prompt's items by FireMany.
+func (convertedBatch[T]) Validate() error {
+	return nil
+}
+
+// batchSchema wraps element, the schema of one converted item, in the schema
+// of a convertedBatch.
+func batchSchema(element string) (string, error) {
+	return wrapSchema(element, func(output *JSONSchema) map[string]*JSONSchema {
+		return map[string]*JSONSchema{
+			"items": {Type: jsonTypeArray, Items: &JSONSchema{
+				Type: jsonTypeObject,
+				Properties: map[string]*JSONSchema{
+					"index":  {Type: jsonTypeInteger},
+					"output": output,
+				},
+				Required:                []string{"index", "output"},
+				DisallowAdditionalProps: true,
+			}},
+		}
+	})
+}
+
+// detailsSchema wraps element, the schema of a converted value, in the
+// schema of a ConvertResponse.
+func detailsSchema(element string) (string, error) {
+	return wrapSchema(element, func(output *JSONSchema) map[string]*JSONSchema {
+		return map[string]*JSONSchema{
+			"data":               output,
+			"source_fields_used": {Type: jsonTypeArray, Items: &JSONSchema{Type: jsonTypeString}},
+			"assumed_fields":     {Type: jsonTypeArray, Items: &JSONSchema{Type: jsonTypeString}},
+			"confidence":         {Type: jsonTypeNumber},
+			"reasoning":          {Type: jsonTypeArray, Items: &JSONSchema{Type: jsonTypeString}},
+		}
+	})
+}
+
+// wrapSchema returns the schema of an object with the properties returned by
+// properties, all required, given the schema of element to nest in them. The
+// element's definitions move to the wrapper, and an element that refers to
+// itself with "#" refers to its own definition instead.
+func wrapSchema(element string, properties func(element *JSONSchema) map[string]*JSONSchema) (string, error) {
+	// arraySchema moves the element's definitions and self-references
+	wrapped, err := arraySchema(element)
+	if err != nil {
+		return "", err
+	}
+	var schema JSONSchema
+	if err := json.Unmarshal([]byte(wrapped), &schema); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+
+	schema.Properties = properties(schema.Properties["items"].Items)
+	schema.Required = slices.Sorted(maps.Keys(schema.Properties))
+
+	jsonBytes, err := json.MarshalIndent(&schema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// FieldMapping is how to fill a field of a conversion's output, declared on
+// the output struct with a zyn tag:
+//
+//	Active bool `json:"active" zyn:"from=is_active,desc=1 means true"`
+//
+// from names the input field the value comes from, by Go or JSON name. desc
+// describes the mapping and must come last, as it runs to the end of the tag
+// and may contain commas.
+type FieldMapping struct {
+	Target string // JSON key of the output field
+	From   string // JSON key of the input field; empty if not declared
+	Desc   string // Description of the mapping; empty if not declared
+}
+
+// fieldMappings returns the mappings declared with zyn tags on the fields of
+// TOutput, in field order. Each from must name a field of TInput; it is not
+// checked when TInput is a map. Untagged fields and non-struct outputs have
+// no mappings.
+func fieldMappings[TInput, TOutput any]() ([]FieldMapping, error) {
+	target := reflect.TypeFor[TOutput]()
+	if target.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	source := reflect.TypeFor[TInput]()
+	for source.Kind() == reflect.Pointer {
+		source = source.Elem()
+	}
+
+	var mappings []FieldMapping
+	for i := range target.NumField() {
+		field := target.Field(i)
+		tag, ok := field.Tag.Lookup("zyn")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		key, ok := jsonKey(target, field.Name)
+		if !ok {
+			return nil, fmt.Errorf("field %s: zyn tag on a field left out of JSON", field.Name)
+		}
+		mapping, err := parseMappingTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		mapping.Target = key
+
+		if mapping.From != "" {
+			switch source.Kind() {
+			case reflect.Struct:
+				from, ok := jsonKey(source, mapping.From)
+				if !ok {
+					return nil, fmt.Errorf("field %s: %v has no field %q", field.Name, source, mapping.From)
+				}
+				mapping.From = from
+			case reflect.Map:
+				// Keys are only known at runtime
+			default:
+				return nil, fmt.Errorf("field %s: maps from %q, but %v has no fields", field.Name, mapping.From, source)
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// parseMappingTag parses the value of a zyn tag: comma-separated key=value
+// pairs, where desc takes the rest of the tag.
+func parseMappingTag(tag string) (FieldMapping, error) {
+	var mapping FieldMapping
+	for rest := tag; strings.TrimSpace(rest) != ""; {
+		var pair string
+		if strings.HasPrefix(strings.TrimSpace(rest), "desc=") {
+			pair, rest = strings.TrimSpace(rest), ""
+		} else {
+			pair, rest, _ = strings.Cut(rest, ",")
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		value = strings.TrimSpace(value)
+		switch {
+		case !ok:
+			return FieldMapping{}, fmt.Errorf("zyn tag entry %q is not key=value", pair)
+		case value == "":
+			return FieldMapping{}, fmt.Errorf("zyn tag key %q has no value", key)
+		case key == "from":
+			mapping.From = value
+		case key == "desc":
+			mapping.Desc = value
+		default:
+			return FieldMapping{}, fmt.Errorf("unknown zyn tag key %q", key)
+		}
+	}
+	if mapping.From == "" && mapping.Desc == "" {
+		return FieldMapping{}, fmt.Errorf("zyn tag needs from or desc")
+	}
+	return mapping, nil
+}
+
+// describeMappings returns schema with each mapping added to the description
+// of its top-level property, after any description from a desc tag.
+func describeMappings(schema string, mappings []FieldMapping) (string, error) {
+	if len(mappings) == 0 {
+		return schema, nil
+	}
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	for _, mapping := range mappings {
+		property := parsed.Properties[mapping.Target]
+		if property == nil {
+			continue
+		}
+		var notes []string
+		if property.Description != "" {
+			notes = append(notes, property.Description)
+		}
+		if mapping.From != "" {
+			notes = append(notes, fmt.Sprintf("from input field %s", mapping.From))
+		}
+		if mapping.Desc != "" {
+			notes = append(notes, mapping.Desc)
+		}
+		property.Description = strings.Join(notes, "; ")
+	}
+	jsonBytes, err := json.MarshalIndent(&parsed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	return string(jsonBytes), nil
 }
 
 // ConvertSynapse converts structured data from one type to another.
 // TOutput must implement Validator to ensure converted data is valid.
 type ConvertSynapse[TInput any, TOutput Validator] struct {
-	instruction  string // What conversion to perform
-	outputSchema string // Pre-computed JSON schema for output type
-	defaults     ConvertInput[TInput]
+	instruction  string         // What conversion to perform
+	outputSchema string         // Pre-computed JSON schema for output type
+	batchSchema  string         // Pre-computed JSON schema for FireMany responses
+	mappings     []FieldMapping // Declared with zyn tags on TOutput
+	defaults     ConvertInput[TInput, TOutput]
 	service      *Service[TOutput]
+	batch        *Service[convertedBatch[TOutput]]  // Service for FireMany, sharing the pipeline
+	details      *Service[ConvertResponse[TOutput]] // Service for FireWithInputDetails, sharing the pipeline
+	detailSchema string                             // Pre-computed JSON schema for ConvertResponse
 }
 
 // Convert creates a new struct-to-struct conversion synapse.
 // TOutput must implement Validator to ensure converted data is valid.
-// Returns an error if the JSON schema cannot be generated.
+// Fields of TOutput may declare how they are filled with zyn tags; see
+// FieldMapping.
+// Returns an error if the JSON schema cannot be generated, or if a zyn tag is
+// malformed or maps from a field TInput does not have.
 func Convert[TInput any, TOutput Validator](instruction string, provider Provider, opts ...Option) (*ConvertSynapse[TInput, TOutput], error) {
+	mappings, err := fieldMappings[TInput, TOutput]()
+	if err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
+
 	// Pre-compute the output schema once at construction
 	outputSchema, err := generateJSONSchema[TOutput]()
 	if err != nil {
 		return nil, fmt.Errorf("convert synapse: %w", err)
 	}
+	if outputSchema, err = describeMappings(outputSchema, mappings); err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
+	manySchema, err := batchSchema(outputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
+	detailSchema, err := detailsSchema(outputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
 
 	// Apply options to build pipeline
 	pipeline := NewTerminal(provider)
@@ -47,7 +339,12 @@ func Convert[TInput any, TOutput Validator](instruction string, provider Provide
 	return &ConvertSynapse[TInput, TOutput]{
 		instruction:  instruction,
 		outputSchema: outputSchema,
+		batchSchema:  manySchema,
+		mappings:     mappings,
 		service:      svc,
+		batch:        NewService[convertedBatch[TOutput]](pipeline, "convert", provider, DefaultTemperatureDeterministic),
+		details:      NewService[ConvertResponse[TOutput]](pipeline, "convert", provider, DefaultTemperatureDeterministic),
+		detailSchema: detailSchema,
 	}, nil
 }
 
@@ -58,12 +355,156 @@ func (c *ConvertSynapse[TInput, TOutput]) GetPipeline() pipz.Chainable[*SynapseR
 
 // Fire performs the conversion with structured input.
 func (c *ConvertSynapse[TInput, TOutput]) Fire(ctx context.Context, session *Session, data TInput) (TOutput, error) {
-	input := ConvertInput[TInput]{Data: data}
+	input := ConvertInput[TInput, TOutput]{Data: data}
 	return c.FireWithInput(ctx, session, input)
 }
 
+// FireResult performs the conversion and returns the output in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (c *ConvertSynapse[TInput, TOutput]) FireResult(ctx context.Context, session *Session, data TInput) (Result[TOutput], error) {
+	merged := c.mergeInputs(ConvertInput[TInput, TOutput]{Data: data})
+	result, err := c.service.ExecuteResult(ctx, session, c.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		var zero TOutput
+		return withValue(result, zero), fmt.Errorf("conversion failed: %w", err)
+	}
+	return result, nil
+}
+
+// FireSlice converts each item independently, with up to opts.Concurrency
+// calls in flight. Outputs are returned in input order; the output of a failed
+// or unprocessed item is the zero value. Each failed item is reported as an
+// ItemError carrying its index and raw response.
+//
+// Items are converted in their own sessions so they cannot influence one
+// another. The shared session receives a single summary exchange and the
+// usage summed across all items.
+//
+// Unless opts.ContinueOnError is set, the first failure stops the batch and is
+// returned as the error alongside the outputs and item errors collected so far.
+func (c *ConvertSynapse[TInput, TOutput]) FireSlice(ctx context.Context, session *Session, items []TInput, opts SliceOptions) ([]TOutput, []ItemError, error) {
+	outcome := runBatch(ctx, items, opts.Concurrency, !opts.ContinueOnError, "item",
+		func(ctx context.Context, item TInput) (Result[TOutput], error) {
+			return c.FireResult(ctx, NewSession(), item)
+		})
+	recordBatch(session, fmt.Sprintf("Convert: %s", c.instruction), outcome)
+
+	outputs := make([]TOutput, len(items))
+	for i, result := range outcome.results {
+		outputs[i] = result.Value
+	}
+	return outputs, outcome.errors, outcome.err
+}
+
+// FireMany converts items in batches of up to opts.BatchSize per provider
+// call, with up to opts.Concurrency calls in flight. Each item is numbered by
+// its index in the prompt and each output is matched back by index, so
+// outputs are returned in input order. Every output is validated on its own;
+// the output of a failed, missing, or unprocessed item is the zero value, and
+// each failed item is reported as an ItemError carrying its index and the raw
+// response of its call. A call that fails as a whole, including one that
+// answers with an index outside its batch or twice for the same item, fails
+// every item of its batch.
+//
+// Calls run in their own sessions. The shared session receives a single
+// summary exchange and the usage summed across all calls.
+//
+// When opts.StopOnError is set, the first failed call stops the batch and is
+// returned as the error alongside the outputs and item errors collected so
+// far.
+func (c *ConvertSynapse[TInput, TOutput]) FireMany(ctx context.Context, session *Session, items []TInput, opts ManyOptions) ([]TOutput, []ItemError, error) {
+	size := opts.BatchSize
+	if size < 1 {
+		size = DefaultConvertBatchSize
+	}
+	var chunks []int // Start index of each batch
+	for start := 0; start < len(items); start += size {
+		chunks = append(chunks, start)
+	}
+
+	merged := c.mergeInputs(ConvertInput[TInput, TOutput]{})
+	calls := runBatch(ctx, chunks, opts.Concurrency, opts.StopOnError, "batch",
+		func(ctx context.Context, start int) (Result[convertedBatch[TOutput]], error) {
+			end := min(start+size, len(items))
+			return c.batch.executeChecked(ctx, NewSession(), c.buildBatchPrompt(merged, items, start, end), merged.Temperature,
+				func(response convertedBatch[TOutput]) error {
+					return checkBatchIndices(response, start, end)
+				})
+		})
+
+	outcome := batchOutcome[TOutput]{results: make([]Result[TOutput], len(items)), usage: calls.usage}
+	itemErrs := make(map[int]ItemError) // First error of each failed item, by index
+	for i, start := range chunks {
+		end := min(start+size, len(items))
+		call := calls.results[i]
+		if callErr, failed := calls.failedWith(i); failed {
+			for index := start; index < end; index++ {
+				itemErrs[index] = ItemError{Index: index, Err: callErr.Err, Raw: callErr.Raw}
+			}
+			continue
+		}
+		if call.RequestID == "" {
+			continue // Not run after an early stop
+		}
+
+		outputs := make(map[int]TOutput, len(call.Value.Items))
+		for _, item := range call.Value.Items {
+			outputs[item.Index] = item.Output
+		}
+		for index := start; index < end; index++ {
+			var zero TOutput
+			outcome.results[index] = withValue(call, zero)
+			output, ok := outputs[index]
+			if !ok {
+				itemErrs[index] = ItemError{Index: index, Err: fmt.Errorf("%w: no output for item", ErrInvalidResponse), Raw: call.response}
+				continue
+			}
+			if err := output.Validate(); err != nil {
+				itemErrs[index] = ItemError{Index: index, Err: fmt.Errorf("%w: %w", ErrInvalidResponse, err), Raw: call.response}
+				continue
+			}
+			outcome.results[index].Value = output
+		}
+	}
+	for index := range items {
+		if itemErr, ok := itemErrs[index]; ok {
+			outcome.errors = append(outcome.errors, itemErr)
+		}
+	}
+
+	// Report a stop by the first item of the failed call rather than the call
+	outcome.err = calls.err
+	var callErr ItemError
+	if errors.As(calls.err, &callErr) {
+		outcome.err = fmt.Errorf("batch stopped: %w", itemErrs[chunks[callErr.Index]])
+	}
+	recordBatch(session, fmt.Sprintf("Convert: %s", c.instruction), outcome)
+
+	outputs := make([]TOutput, len(items))
+	for i, result := range outcome.results {
+		outputs[i] = result.Value
+	}
+	return outputs, outcome.errors, outcome.err
+}
+
+// checkBatchIndices rejects a batched conversion that answers with an index
+// outside [start, end) or twice for the same index.
+func checkBatchIndices[T any](response convertedBatch[T], start, end int) error {
+	seen := make(map[int]bool, len(response.Items))
+	for _, item := range response.Items {
+		if item.Index < start || item.Index >= end {
+			return fmt.Errorf("index must be from %d to %d, got %d", start, end-1, item.Index)
+		}
+		if seen[item.Index] {
+			return fmt.Errorf("index %d converted twice", item.Index)
+		}
+		seen[item.Index] = true
+	}
+	return nil
+}
+
 // FireWithInput performs the conversion with rich input.
-func (c *ConvertSynapse[TInput, TOutput]) FireWithInput(ctx context.Context, session *Session, input ConvertInput[TInput]) (TOutput, error) {
+func (c *ConvertSynapse[TInput, TOutput]) FireWithInput(ctx context.Context, session *Session, input ConvertInput[TInput, TOutput]) (TOutput, error) {
 	// Merge defaults with user input
 	merged := c.mergeInputs(input)
 
@@ -80,8 +521,54 @@ func (c *ConvertSynapse[TInput, TOutput]) FireWithInput(ctx context.Context, ses
 	return result, nil
 }
 
-// mergeInputs combines defaults with user input.
-func (c *ConvertSynapse[TInput, TOutput]) mergeInputs(input ConvertInput[TInput]) ConvertInput[TInput] {
+// FireWithDetails performs the conversion and returns the converted value
+// with a report of the input fields used and the output fields assumed.
+func (c *ConvertSynapse[TInput, TOutput]) FireWithDetails(ctx context.Context, session *Session, data TInput) (*ConvertResponse[TOutput], error) {
+	return c.FireWithInputDetails(ctx, session, ConvertInput[TInput, TOutput]{Data: data})
+}
+
+// FireWithInputDetails performs the conversion with rich input and returns
+// the converted value with a report of the input fields used and the output
+// fields assumed. The report is cross-checked against the data: input fields
+// the input does not have are dropped, and output fields whose values cannot
+// be traced to any input value are added to AssumedFields.
+func (c *ConvertSynapse[TInput, TOutput]) FireWithInputDetails(ctx context.Context, session *Session, input ConvertInput[TInput, TOutput]) (*ConvertResponse[TOutput], error) {
+	merged := c.mergeInputs(input)
+
+	// The caller's constraints go last, after the report's fields
+	builtIn := merged
+	builtIn.Constraints = nil
+	prompt := c.buildPrompt(builtIn)
+	prompt.Schema = c.detailSchema
+	prompt.Constraints = append(prompt.Constraints,
+		"data: the converted output",
+		"source_fields_used: names of the input fields the output takes values from",
+		"assumed_fields: names of the output fields whose values are not taken from the input, such as defaults or guesses",
+		"confidence: 0.0 to 1.0",
+		"reasoning: explanation of the conversion",
+	)
+	prompt.Constraints = append(prompt.Constraints, merged.Constraints...)
+
+	response, err := c.details.Execute(ctx, session, prompt, merged.Temperature)
+	if err != nil {
+		return nil, fmt.Errorf("conversion failed: %w", err)
+	}
+	response = auditFields(merged.Data, response)
+	return &response, nil
+}
+
+// WithDefaults creates a new Convert with default input values.
+// These are merged with user input at execution time. Data is always taken
+// from the input, and default examples are shown before the call's own.
+func (c *ConvertSynapse[TInput, TOutput]) WithDefaults(defaults ConvertInput[TInput, TOutput]) *ConvertSynapse[TInput, TOutput] {
+	c.defaults = defaults
+	return c
+}
+
+// mergeInputs combines defaults with user input. Constraints and examples
+// from both are kept, defaults first, and only the last MaxExamples examples
+// are kept so the call's own examples win over defaults.
+func (c *ConvertSynapse[TInput, TOutput]) mergeInputs(input ConvertInput[TInput, TOutput]) ConvertInput[TInput, TOutput] {
 	merged := c.defaults
 
 	// Data is always taken from input
@@ -93,6 +580,21 @@ func (c *ConvertSynapse[TInput, TOutput]) mergeInputs(input ConvertInput[TInput]
 	if input.Rules != "" {
 		merged.Rules = input.Rules
 	}
+	if len(input.Constraints) > 0 {
+		merged.Constraints = append(append([]string(nil), c.defaults.Constraints...), input.Constraints...)
+	}
+	if len(input.Examples) > 0 {
+		merged.Examples = append(append([]ConvertExample[TInput, TOutput](nil), c.defaults.Examples...), input.Examples...)
+	}
+	if input.MaxExamples > 0 {
+		merged.MaxExamples = input.MaxExamples
+	}
+	if merged.MaxExamples < 1 {
+		merged.MaxExamples = DefaultConvertMaxExamples
+	}
+	if len(merged.Examples) > merged.MaxExamples {
+		merged.Examples = merged.Examples[len(merged.Examples)-merged.MaxExamples:]
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -100,8 +602,21 @@ func (c *ConvertSynapse[TInput, TOutput]) mergeInputs(input ConvertInput[TInput]
 	return merged
 }
 
+// examplePairs renders examples for a prompt, with inputs and outputs as
+// indented JSON.
+func examplePairs[TInput, TOutput any](examples []ConvertExample[TInput, TOutput]) []ExamplePair {
+	if len(examples) == 0 {
+		return nil
+	}
+	pairs := make([]ExamplePair, len(examples))
+	for i, example := range examples {
+		pairs[i] = ExamplePair{Input: renderJSON(example.Input), Output: renderJSON(example.Output)}
+	}
+	return pairs
+}
+
 // buildPrompt constructs the prompt from the merged input.
-func (c *ConvertSynapse[TInput, TOutput]) buildPrompt(input ConvertInput[TInput]) *Prompt {
+func (c *ConvertSynapse[TInput, TOutput]) buildPrompt(input ConvertInput[TInput, TOutput]) *Prompt {
 	// Convert the input data to JSON string
 	inputJSON, err := json.MarshalIndent(input.Data, "", "  ")
 	if err != nil {
@@ -110,9 +625,11 @@ func (c *ConvertSynapse[TInput, TOutput]) buildPrompt(input ConvertInput[TInput]
 	}
 
 	prompt := &Prompt{
-		Task:    fmt.Sprintf("Convert: %s", c.instruction),
-		Input:   string(inputJSON),
-		Context: input.Context,
+		Task:     fmt.Sprintf("Convert: %s", c.instruction),
+		Input:    string(inputJSON),
+		Context:  input.Context,
+		Mappings: c.mappings,
+		Pairs:    examplePairs(input.Examples),
 	}
 
 	// Use pre-computed output schema
@@ -126,11 +643,294 @@ func (c *ConvertSynapse[TInput, TOutput]) buildPrompt(input ConvertInput[TInput]
 		"Ensure output is valid JSON matching the schema",
 	}
 
+	if len(c.mappings) > 0 {
+		constraints = append(constraints, "Fill the output fields listed under field mappings from the named input fields, as described")
+	}
 	if input.Rules != "" {
 		constraints = append(constraints, fmt.Sprintf("Conversion rules: %s", input.Rules))
 	}
+	if len(input.Examples) > 0 {
+		constraints = append(constraints, "Convert the input the way the examples convert theirs")
+	}
+	constraints = append(constraints, input.Constraints...)
 
 	prompt.Constraints = constraints
 
 	return prompt
 }
+
+// minContainment is the shortest value whose containment in another value
+// counts as tracing one to the other, so that short values like "a" or "1"
+// are only traced by equality.
+const minContainment = 3
+
+// auditFields cross-checks the field report of a conversion of data.
+// SourceFieldsUsed keeps only fields of the marshaled data, named by their
+// JSON keys. AssumedFields keeps only fields of the marshaled output, and
+// gains every output field with a value that cannot be traced to a value of
+// the data by equality or containment. Empty values are never assumed.
+func auditFields[TInput any, TOutput Validator](data TInput, response ConvertResponse[TOutput]) ConvertResponse[TOutput] {
+	source := jsonObject(data)
+	target := jsonObject(response.Data)
+	response.SourceFieldsUsed = knownFields(response.SourceFieldsUsed, source)
+
+	var sourceValues []string
+	for _, value := range source {
+		sourceValues = append(sourceValues, jsonLeaves(value)...)
+	}
+	assumed := knownFields(response.AssumedFields, target)
+	for key, value := range target {
+		if !slices.Contains(assumed, key) && !traceable(value, sourceValues) {
+			assumed = append(assumed, key)
+		}
+	}
+	slices.Sort(assumed)
+	response.AssumedFields = assumed
+	return response
+}
+
+// jsonObject returns value marshaled and decoded as a JSON object, or nil if
+// it is not one.
+func jsonObject(value any) map[string]any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	var object map[string]any
+	if json.Unmarshal(data, &object) != nil {
+		return nil
+	}
+	return object
+}
+
+// knownFields returns the names of fields that object has, as its keys,
+// matching names regardless of case and dropping repeats.
+func knownFields(names []string, object map[string]any) []string {
+	var known []string
+	for _, name := range names {
+		key, ok := fieldKey(object, strings.TrimSpace(name))
+		if ok && !slices.Contains(known, key) {
+			known = append(known, key)
+		}
+	}
+	return known
+}
+
+// fieldKey returns the key of object matching name, preferring an exact
+// match to one that differs in case.
+func fieldKey(object map[string]any, name string) (string, bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// jsonLeaves returns the non-empty scalar values of a decoded JSON value in
+// a comparable form: lower-case trimmed strings, and numbers and booleans as
+// written in JSON.
+func jsonLeaves(value any) []string {
+	switch v := value.(type) {
+	case map[string]any:
+		var leaves []string
+		for _, nested := range v {
+			leaves = append(leaves, jsonLeaves(nested)...)
+		}
+		return leaves
+	case []any:
+		var leaves []string
+		for _, nested := range v {
+			leaves = append(leaves, jsonLeaves(nested)...)
+		}
+		return leaves
+	case string:
+		if s := strings.ToLower(strings.TrimSpace(v)); s != "" {
+			return []string{s}
+		}
+	case float64:
+		if v != 0 {
+			return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+	case bool:
+		if v {
+			return []string{"true"}
+		}
+	}
+	return nil
+}
+
+// truthy are the forms an input may give a true output value in.
+var truthy = []string{"true", "1", "yes", "y"}
+
+// traceable reports whether value, a decoded JSON value of the output, has
+// no non-empty leaves or has one equal to, containing, or contained in one
+// of the source values.
+func traceable(value any, sources []string) bool {
+	leaves := jsonLeaves(value)
+	if len(leaves) == 0 {
+		return true
+	}
+	for _, leaf := range leaves {
+		for _, source := range sources {
+			switch {
+			case leaf == source:
+				return true
+			case leaf == "true" && slices.Contains(truthy, source):
+				return true
+			case len(source) >= minContainment && strings.Contains(leaf, source):
+				return true
+			case len(leaf) >= minContainment && strings.Contains(source, leaf):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// indexedItem is an input item of a batched conversion, numbered by its index.
+type indexedItem[T any] struct {
+	Index int `json:"index"`
+	Data  T   `json:"data"`
+}
+
+// buildBatchPrompt constructs the prompt converting items[start:end] from
+// the merged input's context and rules.
+func (c *ConvertSynapse[TInput, TOutput]) buildBatchPrompt(input ConvertInput[TInput, TOutput], items []TInput, start, end int) *Prompt {
+	batch := make([]indexedItem[TInput], 0, end-start)
+	for index := start; index < end; index++ {
+		batch = append(batch, indexedItem[TInput]{Index: index, Data: items[index]})
+	}
+	inputJSON, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		// Fallback to simple string representation
+		inputJSON = []byte(fmt.Sprintf("%+v", batch))
+	}
+
+	constraints := []string{
+		"Convert the data of each input item separately to match the output schema",
+		"items: exactly one entry per input item, with the item's index as given",
+		"Preserve all relevant information during conversion",
+		"Apply the specified transformation rules",
+	}
+	if len(c.mappings) > 0 {
+		constraints = append(constraints, "Fill the output fields listed under field mappings from the named input fields, as described")
+	}
+	if input.Rules != "" {
+		constraints = append(constraints, fmt.Sprintf("Conversion rules: %s", input.Rules))
+	}
+	if len(input.Examples) > 0 {
+		constraints = append(constraints, "Convert each item the way the examples convert theirs")
+	}
+	constraints = append(constraints, input.Constraints...)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Convert each item: %s", c.instruction),
+		Input:       string(inputJSON),
+		Context:     input.Context,
+		Mappings:    c.mappings,
+		Pairs:       examplePairs(input.Examples),
+		Schema:      c.batchSchema,
+		Constraints: constraints,
+	}
+}
+
+// dynamicObject is the response type of DynamicConvertSynapse. Its structure
+// is checked against the runtime schema by the synapse rather than here.
+type dynamicObject map[string]any
+
+// Validate checks that the response is a JSON object.
+func (o dynamicObject) Validate() error {
+	if o == nil {
+		return fmt.Errorf("response must be a JSON object")
+	}
+	return nil
+}
+
+// DynamicConvertSynapse converts structured data to a JSON object whose
+// schema is only known at runtime.
+type DynamicConvertSynapse[TInput any] struct {
+	convert *ConvertSynapse[TInput, dynamicObject]
+	schema  map[string]any // Parsed output schema responses are validated against
+}
+
+// ConvertDynamic creates a conversion synapse whose output schema is given as
+// a JSON Schema string instead of a Go type. The schema is embedded in the
+// prompt like a generated one, and responses are validated against it: types,
+// required and additional properties, enums, array items, anyOf, and $ref into
+// $defs are enforced; other keywords are passed to the LLM but not checked.
+// Returns an error if the schema is not valid JSON Schema for an object.
+func ConvertDynamic[TInput any](instruction string, schema string, provider Provider, opts ...Option) (*DynamicConvertSynapse[TInput], error) {
+	parsed, err := parseSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
+
+	// Indent like generated schemas so prompts render the same way
+	var outputSchema bytes.Buffer
+	if err := json.Indent(&outputSchema, []byte(schema), "", "  "); err != nil {
+		return nil, fmt.Errorf("convert synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	svc := NewService[dynamicObject](pipeline, "convert", provider, DefaultTemperatureDeterministic)
+	synapse := &DynamicConvertSynapse[TInput]{
+		convert: &ConvertSynapse[TInput, dynamicObject]{
+			instruction:  instruction,
+			outputSchema: outputSchema.String(),
+			service:      svc,
+		},
+		schema: parsed,
+	}
+	svc.validate = synapse.validateSchema
+
+	return synapse, nil
+}
+
+// GetPipeline returns the underlying pipeline.
+func (c *DynamicConvertSynapse[TInput]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return c.convert.GetPipeline()
+}
+
+// Fire performs the conversion with structured input.
+func (c *DynamicConvertSynapse[TInput]) Fire(ctx context.Context, session *Session, data TInput) (map[string]any, error) {
+	return c.FireWithInput(ctx, session, ConvertInput[TInput, map[string]any]{Data: data})
+}
+
+// FireResult performs the conversion and returns the output in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (c *DynamicConvertSynapse[TInput]) FireResult(ctx context.Context, session *Session, data TInput) (Result[map[string]any], error) {
+	result, err := c.convert.FireResult(ctx, session, data)
+	return withValue(result, map[string]any(result.Value)), err
+}
+
+// FireWithInput performs the conversion with rich input.
+func (c *DynamicConvertSynapse[TInput]) FireWithInput(ctx context.Context, session *Session, input ConvertInput[TInput, map[string]any]) (map[string]any, error) {
+	examples := make([]ConvertExample[TInput, dynamicObject], len(input.Examples))
+	for i, example := range input.Examples {
+		examples[i] = ConvertExample[TInput, dynamicObject]{Input: example.Input, Output: example.Output}
+	}
+	output, err := c.convert.FireWithInput(ctx, session, ConvertInput[TInput, dynamicObject]{
+		Data:        input.Data,
+		Context:     input.Context,
+		Rules:       input.Rules,
+		Constraints: input.Constraints,
+		Examples:    examples,
+		MaxExamples: input.MaxExamples,
+		Temperature: input.Temperature,
+	})
+	return map[string]any(output), err
+}
+
+// validateSchema checks the response against the output schema.
+func (c *DynamicConvertSynapse[TInput]) validateSchema(_ *Prompt, output dynamicObject) error {
+	return validateSchemaValue(c.schema, c.schema, map[string]any(output), "output")
+}
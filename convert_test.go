@@ -2,6 +2,13 @@ package zyn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -192,7 +199,7 @@ func TestConvertSynapse_FireWithInput(t *testing.T) {
 		}
 
 		ctx := context.Background()
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:    SimpleInput{Value: 10, Name: "test"},
 			Context: "test context",
 		}
@@ -214,7 +221,7 @@ func TestConvertSynapse_FireWithInput(t *testing.T) {
 		}
 
 		ctx := context.Background()
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:        SimpleInput{Value: 1, Name: "test"},
 			Temperature: 0.3,
 		}
@@ -229,7 +236,7 @@ func TestConvertSynapse_FireWithInput(t *testing.T) {
 
 	t.Run("chaining", func(t *testing.T) {
 		provider := NewMockProviderWithResponse(`{"count": 42, "label": "test", "active": true}`)
-		defaults := ConvertInput[SimpleInput]{
+		defaults := ConvertInput[SimpleInput, SimpleOutput]{
 			Context: "default context",
 			Rules:   "default rules",
 		}
@@ -240,7 +247,7 @@ func TestConvertSynapse_FireWithInput(t *testing.T) {
 		synapse.defaults = defaults
 
 		ctx := context.Background()
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:  SimpleInput{Value: 42, Name: "test"},
 			Rules: "override rules",
 		}
@@ -254,6 +261,58 @@ func TestConvertSynapse_FireWithInput(t *testing.T) {
 	})
 }
 
+func TestConvertSynapse_WithDefaults(t *testing.T) {
+	t.Run("sets_defaults", func(t *testing.T) {
+		provider := NewMockProvider()
+		synapse, err := Convert[SimpleInput, SimpleOutput]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		synapseWithDefaults := synapse.WithDefaults(ConvertInput[SimpleInput, SimpleOutput]{
+			Rules:       "labels in upper case",
+			Temperature: 0.2,
+		})
+
+		if synapseWithDefaults != synapse {
+			t.Fatal("WithDefaults should return the synapse")
+		}
+		if synapse.defaults.Rules != "labels in upper case" || synapse.defaults.Temperature != 0.2 {
+			t.Error("Defaults not set correctly")
+		}
+	})
+
+	t.Run("applied_to_prompt", func(t *testing.T) {
+		var prompt string
+		var temperature float32
+		provider := NewMockProviderWithCallback(func(p string, temp float32) (string, error) {
+			prompt, temperature = p, temp
+			return `{"count": 1, "label": "X", "active": true}`, nil
+		})
+		synapse, err := Convert[SimpleInput, SimpleOutput]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse = synapse.WithDefaults(ConvertInput[SimpleInput, SimpleOutput]{
+			Context:     "legacy import",
+			Rules:       "labels in upper case",
+			Temperature: 0.2,
+		})
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), SimpleInput{Value: 1, Name: "x"}); err != nil {
+			t.Fatalf("Fire failed with defaults: %v", err)
+		}
+		for _, want := range []string{"legacy import", "labels in upper case"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected default %q in prompt, got:\n%s", want, prompt)
+			}
+		}
+		if temperature != 0.2 {
+			t.Errorf("expected default temperature 0.2, got %v", temperature)
+		}
+	})
+}
+
 func TestConvertSynapse_mergeInputs(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		provider := NewMockProvider()
@@ -261,11 +320,11 @@ func TestConvertSynapse_mergeInputs(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
-		synapse.defaults = ConvertInput[SimpleInput]{
+		synapse.defaults = ConvertInput[SimpleInput, SimpleOutput]{
 			Context: "default context",
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data: SimpleInput{Value: 42, Name: "test"},
 		}
 		merged := synapse.mergeInputs(input)
@@ -284,12 +343,12 @@ func TestConvertSynapse_mergeInputs(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
-		synapse.defaults = ConvertInput[SimpleInput]{
+		synapse.defaults = ConvertInput[SimpleInput, SimpleOutput]{
 			Context:     "default",
 			Temperature: 0.5,
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:        SimpleInput{Value: 1, Name: "test"},
 			Context:     "override",
 			Temperature: 0.7,
@@ -310,12 +369,12 @@ func TestConvertSynapse_mergeInputs(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
-		synapse.defaults = ConvertInput[SimpleInput]{
+		synapse.defaults = ConvertInput[SimpleInput, SimpleOutput]{
 			Context: "default",
 			Rules:   "default rules",
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:  SimpleInput{Value: 42, Name: "test"},
 			Rules: "override rules",
 		}
@@ -328,6 +387,39 @@ func TestConvertSynapse_mergeInputs(t *testing.T) {
 			t.Error("Should keep default context when not overridden")
 		}
 	})
+
+	t.Run("fallback", func(t *testing.T) {
+		provider := NewMockProvider()
+		synapse, err := Convert[SimpleInput, SimpleOutput]("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse.defaults = ConvertInput[SimpleInput, SimpleOutput]{
+			Data:        SimpleInput{Value: 7},
+			Context:     "default",
+			Rules:       "default rules",
+			Examples:    []ConvertExample[SimpleInput, SimpleOutput]{{Input: SimpleInput{Value: 1}, Output: SimpleOutput{Count: 1}}},
+			Temperature: 0.5,
+		}
+
+		merged := synapse.mergeInputs(ConvertInput[SimpleInput, SimpleOutput]{
+			Examples:    []ConvertExample[SimpleInput, SimpleOutput]{{Input: SimpleInput{Value: 2}, Output: SimpleOutput{Count: 2}}},
+			Temperature: TemperatureUnset,
+		})
+
+		if merged.Data.Value != 0 {
+			t.Error("Data should never come from defaults")
+		}
+		if merged.Context != "default" || merged.Rules != "default rules" {
+			t.Error("Empty context and rules should fall back to defaults")
+		}
+		if len(merged.Examples) != 2 || merged.Examples[0].Output.Count != 1 || merged.Examples[1].Output.Count != 2 {
+			t.Errorf("Expected default examples before the input's, got %+v", merged.Examples)
+		}
+		if merged.Temperature != 0.5 {
+			t.Errorf("Unset temperature should fall back to default, got %v", merged.Temperature)
+		}
+	})
 }
 
 func TestConvertSynapse_buildPrompt(t *testing.T) {
@@ -338,7 +430,7 @@ func TestConvertSynapse_buildPrompt(t *testing.T) {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data: SimpleInput{Value: 42, Name: "test"},
 		}
 		prompt := synapse.buildPrompt(input)
@@ -361,7 +453,7 @@ func TestConvertSynapse_buildPrompt(t *testing.T) {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data:    SimpleInput{Value: 1, Name: "test"},
 			Context: "conversion context",
 			Rules:   "apply rules",
@@ -383,7 +475,7 @@ func TestConvertSynapse_buildPrompt(t *testing.T) {
 			t.Fatalf("failed to create synapse: %v", err)
 		}
 
-		input := ConvertInput[SimpleInput]{
+		input := ConvertInput[SimpleInput, SimpleOutput]{
 			Data: SimpleInput{Value: 42, Name: "test"},
 		}
 		prompt := synapse.buildPrompt(input)
@@ -393,3 +485,810 @@ func TestConvertSynapse_buildPrompt(t *testing.T) {
 		}
 	})
 }
+
+func TestConvertSynapse_FireSlice(t *testing.T) {
+	// The mock answers based on the item name in the prompt; "broken" items get
+	// a response that cannot be parsed.
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		if strings.Contains(prompt, `"broken`) {
+			return "not json", nil
+		}
+		for _, name := range []string{"alpha", "beta", "gamma", "delta"} {
+			if strings.Contains(prompt, `"`+name+`"`) {
+				return fmt.Sprintf(`{"count": 1, "label": %q, "active": true}`, name), nil
+			}
+		}
+		return "", fmt.Errorf("unexpected prompt")
+	})
+	synapse, err := Convert[SimpleInput, SimpleOutput]("convert records", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	t.Run("preserves order", func(t *testing.T) {
+		items := []SimpleInput{{Name: "alpha"}, {Name: "beta"}, {Name: "gamma"}, {Name: "delta"}}
+		session := NewSession()
+
+		outputs, itemErrs, err := synapse.FireSlice(context.Background(), session, items, SliceOptions{Concurrency: 3})
+		if err != nil || len(itemErrs) != 0 {
+			t.Fatalf("unexpected failure: %v %v", err, itemErrs)
+		}
+		for i, item := range items {
+			if outputs[i].Label != item.Name {
+				t.Errorf("output %d: expected label %q, got %q", i, item.Name, outputs[i].Label)
+			}
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one summary exchange in session, got %d messages", session.Len())
+		}
+		if usage := session.TotalUsage(); usage.Total != 600 {
+			t.Errorf("expected usage summed over 4 items, got %+v", usage)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		items := []SimpleInput{{Name: "alpha"}, {Name: "broken-1"}, {Name: "gamma"}, {Name: "broken-2"}}
+		session := NewSession()
+
+		outputs, itemErrs, err := synapse.FireSlice(context.Background(), session, items, SliceOptions{Concurrency: 2, ContinueOnError: true})
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+		if len(itemErrs) != 2 || itemErrs[0].Index != 1 || itemErrs[1].Index != 3 {
+			t.Fatalf("expected item errors for 1 and 3, got %+v", itemErrs)
+		}
+		if itemErrs[0].Raw != "not json" {
+			t.Errorf("expected raw response in item error, got %q", itemErrs[0].Raw)
+		}
+		if outputs[0].Label != "alpha" || outputs[2].Label != "gamma" {
+			t.Errorf("expected successful outputs kept, got %+v", outputs)
+		}
+		if outputs[1] != (SimpleOutput{}) {
+			t.Errorf("expected zero output for failed item, got %+v", outputs[1])
+		}
+		last := session.Messages()[1].Content
+		if last != "Processed 2 of 4 items. Failed items: 1, 3." {
+			t.Errorf("unexpected summary: %q", last)
+		}
+	})
+
+	t.Run("stops on first error", func(t *testing.T) {
+		items := []SimpleInput{{Name: "alpha"}, {Name: "broken"}, {Name: "gamma"}}
+
+		outputs, itemErrs, err := synapse.FireSlice(context.Background(), NewSession(), items, SliceOptions{})
+		var itemErr ItemError
+		if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+			t.Fatalf("expected batch error for item 1, got %v", err)
+		}
+		if len(itemErrs) != 1 {
+			t.Errorf("expected one item error, got %+v", itemErrs)
+		}
+		if outputs[0].Label != "alpha" || outputs[2] != (SimpleOutput{}) {
+			t.Errorf("expected items after the failure to be skipped, got %+v", outputs)
+		}
+	})
+}
+
+// LegacyUser and MigratedUser are the types of the FireMany tests.
+type LegacyUser struct {
+	ID       int    `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+type MigratedUser struct {
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (u MigratedUser) Validate() error {
+	if u.Name == "" {
+		return fmt.Errorf("name required but empty")
+	}
+	return nil
+}
+
+// legacyItem matches an item of a batched conversion prompt.
+var legacyItem = regexp.MustCompile(`"index": (\d+),\s*"data": \{\s*"id": (\d+),\s*"full_name": "([^"]*)"`)
+
+// migrateUsers answers a batched conversion prompt, converting each item by
+// its name: "skip" is left out, "stray" is answered with an index outside
+// the batch.
+func migrateUsers(prompt string) string {
+	var items []string
+	for _, match := range legacyItem.FindAllStringSubmatch(prompt, -1) {
+		index := match[1]
+		switch match[3] {
+		case "skip":
+			continue
+		case "stray":
+			index = "99"
+		}
+		items = append(items, fmt.Sprintf(`{"index": %s, "output": {"user_id": %s, "name": %q}}`, index, match[2], match[3]))
+	}
+	return `{"items": [` + strings.Join(items, ", ") + `]}`
+}
+
+func TestBatchSchema(t *testing.T) {
+	element, err := generateJSONSchema[MigratedUser]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema, err := batchSchema(element)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	items := parsed.Properties["items"]
+	if len(parsed.Properties) != 1 || items == nil || items.Type != jsonTypeArray {
+		t.Fatalf("expected a single items array, got %s", schema)
+	}
+	item := items.Items
+	if item.Properties["index"].Type != jsonTypeInteger || item.Properties["output"].Properties["user_id"] == nil {
+		t.Errorf("expected items tagged with an index, got %s", schema)
+	}
+
+	if _, err := batchSchema("not json"); err == nil {
+		t.Error("expected invalid element schema rejected")
+	}
+}
+
+func TestDetailsSchema(t *testing.T) {
+	element, _ := generateJSONSchema[customer]()
+	schema, err := detailsSchema(element)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	if want := []string{"assumed_fields", "confidence", "data", "reasoning", "source_fields_used"}; !slices.Equal(parsed.Required, want) {
+		t.Errorf("expected %q required, got %q", want, parsed.Required)
+	}
+	if parsed.Properties["data"].Properties["email"] == nil {
+		t.Errorf("expected the converted value under data, got %s", schema)
+	}
+}
+
+func TestConvertSynapse_FireMany(t *testing.T) {
+	var calls atomic.Int32
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		calls.Add(1)
+		return migrateUsers(prompt), nil
+	})
+	synapse, err := Convert[LegacyUser, MigratedUser]("migrate legacy user records", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	users := func(names ...string) []LegacyUser {
+		items := make([]LegacyUser, len(names))
+		for i, name := range names {
+			items[i] = LegacyUser{ID: 100 + i, FullName: name}
+		}
+		return items
+	}
+
+	t.Run("batches in order", func(t *testing.T) {
+		calls.Store(0)
+		session := NewSession()
+		outputs, itemErrs, err := synapse.FireMany(context.Background(), session,
+			users("Ada", "Grace", "Linus", "Ken", "Barbara"), ManyOptions{BatchSize: 2, Concurrency: 2})
+		if err != nil || len(itemErrs) != 0 {
+			t.Fatalf("unexpected failure: %v %v", err, itemErrs)
+		}
+		for i, name := range []string{"Ada", "Grace", "Linus", "Ken", "Barbara"} {
+			if outputs[i] != (MigratedUser{UserID: 100 + i, Name: name}) {
+				t.Errorf("output %d: unexpected %+v", i, outputs[i])
+			}
+		}
+		if calls.Load() != 3 {
+			t.Errorf("expected 3 calls for 5 items in batches of 2, got %d", calls.Load())
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one summary exchange in session, got %d messages", session.Len())
+		}
+		if usage := session.TotalUsage(); usage.Total != 450 {
+			t.Errorf("expected usage summed over 3 calls, got %+v", usage)
+		}
+	})
+
+	t.Run("default batch size", func(t *testing.T) {
+		calls.Store(0)
+		names := make([]string, DefaultConvertBatchSize+1)
+		for i := range names {
+			names[i] = fmt.Sprintf("user-%d", i)
+		}
+		outputs, itemErrs, err := synapse.FireMany(context.Background(), NewSession(), users(names...), ManyOptions{})
+		if err != nil || len(itemErrs) != 0 {
+			t.Fatalf("unexpected failure: %v %v", err, itemErrs)
+		}
+		if calls.Load() != 2 || outputs[DefaultConvertBatchSize].Name != names[DefaultConvertBatchSize] {
+			t.Errorf("expected the last item converted in a second call, got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("per-item errors", func(t *testing.T) {
+		session := NewSession()
+		outputs, itemErrs, err := synapse.FireMany(context.Background(), session, users("Ada", "", "skip", "Ken"), ManyOptions{})
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+		if len(itemErrs) != 2 || itemErrs[0].Index != 1 || itemErrs[1].Index != 2 {
+			t.Fatalf("expected item errors for 1 and 2, got %+v", itemErrs)
+		}
+		if !errors.Is(itemErrs[0].Err, ErrInvalidResponse) || !strings.Contains(itemErrs[0].Error(), "name required") {
+			t.Errorf("expected the invalid output reported, got %v", itemErrs[0])
+		}
+		if !strings.Contains(itemErrs[1].Error(), "no output for item") || itemErrs[1].Raw == "" {
+			t.Errorf("expected the missing output reported with the raw response, got %+v", itemErrs[1])
+		}
+		if outputs[0].Name != "Ada" || outputs[3].Name != "Ken" || outputs[1] != (MigratedUser{}) {
+			t.Errorf("expected valid outputs kept, got %+v", outputs)
+		}
+		if last := session.Messages()[1].Content; last != "Processed 2 of 4 items. Failed items: 1, 2." {
+			t.Errorf("unexpected summary: %q", last)
+		}
+	})
+
+	t.Run("failed call fails its batch", func(t *testing.T) {
+		outputs, itemErrs, err := synapse.FireMany(context.Background(), NewSession(),
+			users("Ada", "Grace", "Linus", "stray", "Barbara"), ManyOptions{BatchSize: 2})
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+		if len(itemErrs) != 2 || itemErrs[0].Index != 2 || itemErrs[1].Index != 3 {
+			t.Fatalf("expected item errors for the second batch, got %+v", itemErrs)
+		}
+		if !errors.Is(itemErrs[0].Err, ErrInvalidResponse) || !strings.Contains(itemErrs[0].Error(), "index must be from 2 to 3, got 99") {
+			t.Errorf("expected the stray index reported, got %v", itemErrs[0])
+		}
+		if outputs[4].Name != "Barbara" {
+			t.Errorf("expected later batches converted, got %+v", outputs)
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		calls.Store(0)
+		_, itemErrs, err := synapse.FireMany(context.Background(), NewSession(),
+			users("Ada", "Grace", "stray", "Ken", "Barbara"), ManyOptions{BatchSize: 2, StopOnError: true})
+		var itemErr ItemError
+		if !errors.As(err, &itemErr) || itemErr.Index != 2 {
+			t.Fatalf("expected batch error for item 2, got %v", err)
+		}
+		if len(itemErrs) != 2 || calls.Load() != 2 {
+			t.Errorf("expected the last batch skipped, got %+v after %d calls", itemErrs, calls.Load())
+		}
+	})
+}
+
+// legacyAccount and account are the types of the field mapping tests.
+type legacyAccount struct {
+	ID       int    `json:"id"`
+	IsActive int    `json:"is_active"`
+	Plan     string // No JSON tag
+}
+
+type account struct {
+	AccountID int    `json:"account_id" zyn:"from=id"`
+	Active    bool   `json:"active" zyn:"from=IsActive,desc=1 means true, 0 means false"`
+	Tier      string `json:"tier" desc:"billing tier" zyn:"from=Plan, desc=gold, silver, or bronze"`
+	Note      string `json:"note"`
+}
+
+func (account) Validate() error {
+	return nil
+}
+
+func TestParseMappingTag(t *testing.T) {
+	for _, tt := range []struct {
+		tag     string
+		want    FieldMapping
+		wantErr string
+	}{
+		{tag: "from=is_active", want: FieldMapping{From: "is_active"}},
+		{tag: "desc=a, b, or c", want: FieldMapping{Desc: "a, b, or c"}},
+		{tag: " from=is_active , desc=1 means true", want: FieldMapping{From: "is_active", Desc: "1 means true"}},
+		{tag: "", wantErr: "needs from or desc"},
+		{tag: "from=", wantErr: `key "from" has no value`},
+		{tag: "source=id", wantErr: `unknown zyn tag key "source"`},
+		{tag: "from=id,optional", wantErr: `entry "optional" is not key=value`},
+	} {
+		got, err := parseMappingTag(tt.tag)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: expected error containing %q, got %v", tt.tag, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %+v, got %+v, %v", tt.tag, tt.want, got, err)
+		}
+	}
+}
+
+func TestConvertSynapse_FieldMappings(t *testing.T) {
+	t.Run("mapping table and schema", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"account_id": 7, "active": true, "tier": "gold", "note": ""}`, nil
+		})
+		synapse, err := Convert[legacyAccount, account]("migrate accounts", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		output, err := synapse.Fire(context.Background(), NewSession(), legacyAccount{ID: 7, IsActive: 1, Plan: "gold"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != (account{AccountID: 7, Active: true, Tier: "gold"}) {
+			t.Errorf("unexpected output: %+v", output)
+		}
+		want := "Field mappings:\n  account_id <- id\n  active <- is_active: 1 means true, 0 means false\n  tier <- Plan: gold, silver, or bronze"
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected mapping table in prompt, got %s", prompt)
+		}
+
+		var schema JSONSchema
+		if err := json.Unmarshal([]byte(synapse.outputSchema), &schema); err != nil {
+			t.Fatalf("invalid schema: %v", err)
+		}
+		for key, desc := range map[string]string{
+			"active": "from input field is_active; 1 means true, 0 means false",
+			"tier":   "billing tier; from input field Plan; gold, silver, or bronze",
+			"note":   "",
+		} {
+			if got := schema.Properties[key].Description; got != desc {
+				t.Errorf("%s: expected description %q, got %q", key, desc, got)
+			}
+		}
+		if !strings.Contains(synapse.batchSchema, "from input field id") {
+			t.Error("expected mappings in the FireMany schema")
+		}
+	})
+
+	t.Run("untagged output", func(t *testing.T) {
+		synapse, err := Convert[SimpleInput, SimpleOutput]("convert", NewMockProvider())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if synapse.mappings != nil || strings.Contains(synapse.buildPrompt(ConvertInput[SimpleInput, SimpleOutput]{}).Render(), "Field mappings") {
+			t.Error("expected no mappings without zyn tags")
+		}
+	})
+
+	t.Run("map input is not checked", func(t *testing.T) {
+		if _, err := Convert[map[string]any, account]("migrate accounts", NewMockProvider()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unknown source field", func(t *testing.T) {
+		_, err := Convert[SimpleInput, account]("migrate accounts", NewMockProvider())
+		if err == nil || !strings.Contains(err.Error(), `field AccountID: zyn.SimpleInput has no field "id"`) {
+			t.Errorf("expected the typo reported at construction, got %v", err)
+		}
+	})
+
+	t.Run("non-struct input", func(t *testing.T) {
+		_, err := Convert[string, account]("migrate accounts", NewMockProvider())
+		if err == nil || !strings.Contains(err.Error(), "string has no fields") {
+			t.Errorf("expected mapping from a string rejected, got %v", err)
+		}
+	})
+}
+
+// customerRecord and customer are the types of the field report tests.
+type customerRecord struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Active   int    `json:"active"`
+	Phone    string `json:"phone"`
+}
+
+type customer struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Enabled  bool   `json:"enabled"`
+	Country  string `json:"country"`
+	Verified bool   `json:"verified"`
+}
+
+func (c customer) Validate() error {
+	if c.Email == "" {
+		return fmt.Errorf("email required but empty")
+	}
+	return nil
+}
+
+func TestTraceable(t *testing.T) {
+	sources := jsonLeaves(map[string]any{"name": "Ada Lovelace", "active": float64(1), "id": float64(42), "tags": []any{"VIP"}})
+	for _, tt := range []struct {
+		value any
+		want  bool
+	}{
+		{"Ada Lovelace", true},
+		{"ada", true},                   // Contained in a source value
+		{"Countess Ada Lovelace", true}, // Contains a source value
+		{"UK", false},
+		{float64(42), true},
+		{"42", true},
+		{true, true}, // From active: 1
+		{false, true},
+		{"", true},
+		{nil, true},
+		{[]any{"vip", "gold"}, true},
+		{map[string]any{"city": "London"}, false},
+		{"a", false}, // Too short to be traced by containment
+	} {
+		if got := traceable(tt.value, sources); got != tt.want {
+			t.Errorf("traceable(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestConvertSynapse_FireWithInputDetails(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{
+			"data": {"name": "Ada Lovelace", "email": "ada@example.com", "enabled": true, "country": "UK", "verified": false},
+			"source_fields_used": ["full_name", "EMAIL", "active", "email", "address"],
+			"assumed_fields": ["Verified", "currency"],
+			"confidence": 0.8,
+			"reasoning": ["country guessed from the name"]
+		}`, nil
+	})
+	synapse, err := Convert[customerRecord, customer]("migrate to customer schema", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	session := NewSession()
+	response, err := synapse.FireWithInputDetails(context.Background(), session, ConvertInput[customerRecord, customer]{
+		Data:  customerRecord{FullName: "Ada Lovelace", Email: "ada@example.com", Active: 1, Phone: "555-0100"},
+		Rules: "active 1 means enabled",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Data.Name != "Ada Lovelace" || response.Confidence != 0.8 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if want := []string{"full_name", "email", "active"}; !slices.Equal(response.SourceFieldsUsed, want) {
+		t.Errorf("expected source fields %q, got %q", want, response.SourceFieldsUsed)
+	}
+	if want := []string{"country", "verified"}; !slices.Equal(response.AssumedFields, want) {
+		t.Errorf("expected assumed fields %q, got %q", want, response.AssumedFields)
+	}
+	for _, want := range []string{`"source_fields_used"`, `"assumed_fields"`, "assumed_fields: names of the output fields", "Conversion rules: active 1 means enabled"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if session.Len() != 2 {
+		t.Errorf("expected the exchange recorded, got %d messages", session.Len())
+	}
+}
+
+func TestConvertSynapse_FireWithDetails(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"data": {"name": "Ada", "email": "", "enabled": false, "country": "", "verified": false}, "source_fields_used": [], "assumed_fields": [], "confidence": 0.9, "reasoning": ["r"]}`)
+	synapse, _ := Convert[customerRecord, customer]("migrate to customer schema", provider)
+
+	_, err := synapse.FireWithDetails(context.Background(), NewSession(), customerRecord{FullName: "Ada"})
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "data: email required") {
+		t.Errorf("expected the converted value validated, got %v", err)
+	}
+}
+
+func TestConvertDynamic(t *testing.T) {
+	t.Run("invalid schema", func(t *testing.T) {
+		_, err := ConvertDynamic[SimpleInput]("convert", `{"type": "object", "properties": {"a": {"type": "text"}}}`, NewMockProvider())
+		if err == nil {
+			t.Fatal("expected error for invalid schema")
+		}
+	})
+
+	t.Run("nested output", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"id": 42, "status": "shipped", "customer": {"name": "test"}, "lines": [{"sku": "A1", "quantity": 2}]}`, nil
+		})
+		synapse, err := ConvertDynamic[SimpleInput]("convert to order", dynamicOrderSchema, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		output, err := synapse.Fire(context.Background(), session, SimpleInput{Value: 42, Name: "test"})
+		if err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if output["status"] != "shipped" || output["id"] != float64(42) {
+			t.Errorf("unexpected output: %v", output)
+		}
+		customer, ok := output["customer"].(map[string]any)
+		if !ok || customer["name"] != "test" {
+			t.Errorf("unexpected customer: %v", output["customer"])
+		}
+		if !strings.Contains(prompt, "Response JSON Schema:\n{\n  \"type\": \"object\"") {
+			t.Errorf("expected indented schema in prompt, got:\n%s", prompt)
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected session to record the exchange, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("enum violation", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"id": 1, "status": "lost", "customer": {"name": "x"}, "lines": []}`)
+		synapse, err := ConvertDynamic[SimpleInput]("convert to order", dynamicOrderSchema, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		result, err := synapse.FireResult(context.Background(), session, SimpleInput{Value: 1})
+		if !errors.Is(err, ErrInvalidResponse) {
+			t.Fatalf("expected ErrInvalidResponse, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "output.status") {
+			t.Errorf("expected error to name the field, got %v", err)
+		}
+		if result.Value != nil {
+			t.Errorf("expected nil output on failure, got %v", result.Value)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("non-object response", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`null`)
+		synapse, err := ConvertDynamic[SimpleInput]("convert to order", dynamicOrderSchema, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), NewSession(), SimpleInput{}); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+	})
+
+	t.Run("with input", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"id": 1, "status": "open", "customer": {"name": "x"}, "lines": []}`, nil
+		})
+		synapse, err := ConvertDynamic[SimpleInput]("convert to order", dynamicOrderSchema, provider, WithRetry(2))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if synapse.GetPipeline() == nil {
+			t.Error("expected pipeline")
+		}
+
+		output, err := synapse.FireWithInput(context.Background(), NewSession(), ConvertInput[SimpleInput, map[string]any]{
+			Data:  SimpleInput{Value: 1, Name: "x"},
+			Rules: "value becomes id",
+		})
+		if err != nil {
+			t.Fatalf("FireWithInput failed: %v", err)
+		}
+		if output["status"] != "open" {
+			t.Errorf("unexpected output: %v", output)
+		}
+		if !strings.Contains(prompt, "Conversion rules: value becomes id") {
+			t.Errorf("expected rules in prompt, got:\n%s", prompt)
+		}
+	})
+}
+
+// modernUser is a conversion target that requires an email.
+type modernUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (u modernUser) Validate() error {
+	if u.Email == "" {
+		return fmt.Errorf("email required but empty")
+	}
+	return nil
+}
+
+func TestConvertSynapse_ValidationError(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"name": "Ada Lovelace", "email": ""}`)
+	synapse, _ := Convert[SimpleInput, modernUser]("a modern user record", provider)
+
+	session := NewSession()
+	_, err := synapse.Fire(context.Background(), session, SimpleInput{Value: 1, Name: "Ada Lovelace"})
+	var invalid *ValidationError[modernUser]
+	if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if invalid.Value.Name != "Ada Lovelace" || !strings.Contains(invalid.Err.Error(), "email required") {
+		t.Errorf("expected the parsed user and its failure, got %+v", invalid)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected the session untouched, got %d messages", session.Len())
+	}
+
+	// The envelope keeps the zero value; the parsed value is on the error only
+	result, err := synapse.FireResult(context.Background(), session, SimpleInput{Value: 1, Name: "Ada Lovelace"})
+	if !errors.As(err, &invalid) || result.Value != (modernUser{}) {
+		t.Errorf("expected the zero value with a validation error, got %+v and %v", result.Value, err)
+	}
+}
+
+func TestConvertSynapse_Examples(t *testing.T) {
+	example := func(n int) ConvertExample[SimpleInput, SimpleOutput] {
+		return ConvertExample[SimpleInput, SimpleOutput]{
+			Input:  SimpleInput{Value: n, Name: fmt.Sprintf("item-%d", n)},
+			Output: SimpleOutput{Count: n, Label: fmt.Sprintf("ITEM-%d", n), Active: true},
+		}
+	}
+
+	t.Run("merged and capped", func(t *testing.T) {
+		synapse, _ := Convert[SimpleInput, SimpleOutput]("test", NewMockProvider())
+		synapse.WithDefaults(ConvertInput[SimpleInput, SimpleOutput]{
+			Examples: []ConvertExample[SimpleInput, SimpleOutput]{example(1), example(2)},
+		})
+
+		merged := synapse.mergeInputs(ConvertInput[SimpleInput, SimpleOutput]{})
+		if len(merged.Examples) != 2 || merged.MaxExamples != DefaultConvertMaxExamples {
+			t.Errorf("expected the default examples, got %+v", merged)
+		}
+
+		merged = synapse.mergeInputs(ConvertInput[SimpleInput, SimpleOutput]{
+			Examples:    []ConvertExample[SimpleInput, SimpleOutput]{example(3), example(4)},
+			MaxExamples: 3,
+		})
+		got := make([]int, len(merged.Examples))
+		for i, ex := range merged.Examples {
+			got[i] = ex.Input.Value
+		}
+		if !slices.Equal(got, []int{2, 3, 4}) {
+			t.Errorf("expected the last 3 examples, defaults first, got %v", got)
+		}
+		if len(synapse.defaults.Examples) != 2 {
+			t.Errorf("expected the defaults unchanged, got %d examples", len(synapse.defaults.Examples))
+		}
+	})
+
+	t.Run("in the provider call", func(t *testing.T) {
+		var prompts []string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompts = append(prompts, p)
+			if strings.Contains(p, "Convert each item") {
+				return `{"items": [{"index": 0, "output": {"count": 9, "label": "ITEM-9", "active": true}}]}`, nil
+			}
+			return `{"count": 9, "label": "ITEM-9", "active": true}`, nil
+		})
+		synapse, _ := Convert[SimpleInput, SimpleOutput]("uppercase names", provider)
+
+		_, err := synapse.FireWithInput(context.Background(), NewSession(), ConvertInput[SimpleInput, SimpleOutput]{
+			Data:     SimpleInput{Value: 9, Name: "item-9"},
+			Examples: []ConvertExample[SimpleInput, SimpleOutput]{example(1)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "Examples:\nExample 1 input:\n{\n  \"value\": 1,\n  \"name\": \"item-1\"\n}\nExample 1 output:\n{\n  \"count\": 1,\n  \"label\": \"ITEM-1\",\n  \"active\": true\n}"
+		if !strings.Contains(prompts[0], want) || !strings.Contains(prompts[0], "the way the examples convert theirs") {
+			t.Errorf("expected the example pair in the prompt, got %s", prompts[0])
+		}
+
+		// Examples set as defaults reach FireMany's batched calls too
+		synapse.WithDefaults(ConvertInput[SimpleInput, SimpleOutput]{
+			Examples: []ConvertExample[SimpleInput, SimpleOutput]{example(1)},
+		})
+		if _, _, err := synapse.FireMany(context.Background(), NewSession(), []SimpleInput{{Value: 9, Name: "item-9"}}, ManyOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("expected the example pair in the batch prompt, got %s", prompts[1])
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		synapse, _ := Convert[SimpleInput, SimpleOutput]("test", NewMockProvider())
+		if rendered := synapse.buildPrompt(ConvertInput[SimpleInput, SimpleOutput]{}).Render(); strings.Contains(rendered, "Examples:") {
+			t.Errorf("expected no examples section, got %s", rendered)
+		}
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"id": 2}`, nil
+		})
+		synapse, err := ConvertDynamic[SimpleInput]("to record", `{"type": "object", "properties": {"id": {"type": "integer"}}}`, provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = synapse.FireWithInput(context.Background(), NewSession(), ConvertInput[SimpleInput, map[string]any]{
+			Data:     SimpleInput{Value: 2},
+			Examples: []ConvertExample[SimpleInput, map[string]any]{{Input: SimpleInput{Value: 1}, Output: map[string]any{"id": 1}}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompt, "Example 1 output:\n{\n  \"id\": 1\n}") {
+			t.Errorf("expected the example in the prompt, got %s", prompt)
+		}
+	})
+}
+
+func TestConvertSynapse_Constraints(t *testing.T) {
+	synapse, _ := Convert[SimpleInput, SimpleOutput]("test", NewMockProvider())
+	synapse.WithDefaults(ConvertInput[SimpleInput, SimpleOutput]{Constraints: []string{"labels in upper case"}})
+
+	merged := synapse.mergeInputs(ConvertInput[SimpleInput, SimpleOutput]{Constraints: []string{"count never negative"}})
+	if !slices.Equal(merged.Constraints, []string{"labels in upper case", "count never negative"}) {
+		t.Errorf("expected default constraints first, got %q", merged.Constraints)
+	}
+	if merged := synapse.mergeInputs(ConvertInput[SimpleInput, SimpleOutput]{}); len(merged.Constraints) != 1 {
+		t.Errorf("expected the default constraints alone, got %q", merged.Constraints)
+	}
+
+	for name, constraints := range map[string][]string{
+		"single": synapse.buildPrompt(merged).Constraints,
+		"batch":  synapse.buildBatchPrompt(merged, []SimpleInput{{}}, 0, 1).Constraints,
+	} {
+		if n := len(constraints); n < 5 || constraints[n-1] != "count never negative" || constraints[n-2] != "labels in upper case" {
+			t.Errorf("%s: expected the caller's constraints last, got %q", name, constraints)
+		}
+	}
+
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"data": {"count": 1, "label": "A", "active": true}, "source_fields_used": [], "assumed_fields": [], "confidence": 0.9, "reasoning": ["x"]}`, nil
+	})
+	details, _ := Convert[SimpleInput, SimpleOutput]("test", provider)
+	if _, err := details.FireWithInputDetails(context.Background(), NewSession(), ConvertInput[SimpleInput, SimpleOutput]{Constraints: []string{"count never negative"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "reasoning: explanation of the conversion\n- count never negative") {
+		t.Errorf("expected the caller's constraints after the report's, got %s", prompt)
+	}
+}
+
+func TestConvertSynapse_MarshaledTypes(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"name": "latency", "at": "2026-10-16T09:30:00Z", "seen": null, "day": "2026-10-16T00:00:00Z",
+			"count": 3, "raw": {"p99": 120}, "window": "1m30s", "windows": ["5m0s"], "level": "2",
+			"point": {"x": 1}, "previous": null}`, nil
+	})
+	synapse, err := Convert[SimpleInput, processedMetric]("process the metric", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	metric, err := synapse.Fire(context.Background(), NewSession(), SimpleInput{Value: 3, Name: "latency"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC); !metric.At.Equal(want) {
+		t.Errorf("expected the RFC 3339 time parsed, got %v", metric.At)
+	}
+	if metric.Seen != nil || metric.Count == nil || *metric.Count != 3 {
+		t.Errorf("expected pointers decoded from null and a number, got %v and %v", metric.Seen, metric.Count)
+	}
+	if time.Duration(metric.Window) != 90*time.Second || string(metric.Raw) != `{"p99": 120}` {
+		t.Errorf("expected the custom types decoded, got %v and %s", metric.Window, metric.Raw)
+	}
+	if !strings.Contains(prompt, `"format": "date-time"`) {
+		t.Errorf("expected the date-time format in the prompt schema, got %s", prompt)
+	}
+}
@@ -0,0 +1,324 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// errPreview is returned by a synapse fired for a preview, after its prompt
+// has been recorded in place of calling the provider.
+var errPreview = errors.New("preview: provider not called")
+
+// Relative error bounds used to express an estimate's uncertainty.
+const (
+	heuristicPromptError  = 0.25 // HeuristicEstimator against real tokenizers
+	estimatorPromptError  = 0.05 // Provider-supplied TokenEstimator
+	assumedCompletionErr  = 0.25 // Caller-supplied average completion tokens
+	heuristicCompletionEr = 0.5  // Per-synapse completion heuristics
+)
+
+// ModelPrice is a model's token pricing in US dollars per million tokens.
+type ModelPrice struct {
+	Prompt       float64 `json:"prompt"`                  // Per million prompt tokens
+	Completion   float64 `json:"completion"`              // Per million completion tokens
+	CachedPrompt float64 `json:"cached_prompt,omitempty"` // Per million cached prompt tokens; zero bills them as prompt tokens
+}
+
+// PriceTable maps model names to their prices.
+type PriceTable map[string]ModelPrice
+
+// Lookup returns the price of model: its own entry, or else the longest entry
+// it extends with a "-" suffix, so a dated snapshot such as
+// "gpt-4o-2024-08-06" is priced as "gpt-4o".
+func (t PriceTable) Lookup(model string) (ModelPrice, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	var (
+		best  ModelPrice
+		found string
+	)
+	for name, price := range t {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(found) {
+			best, found = price, name
+		}
+	}
+	return best, found != ""
+}
+
+// Cost returns the cost of usage at this price. Cached prompt tokens are billed
+// at CachedPrompt when it is set.
+func (p ModelPrice) Cost(usage TokenUsage) float64 {
+	cached := min(max(usage.CachedPrompt, 0), usage.Prompt)
+	cachedRate := p.CachedPrompt
+	if cachedRate == 0 {
+		cachedRate = p.Prompt
+	}
+	return (float64(usage.Prompt-cached)*p.Prompt +
+		float64(cached)*cachedRate +
+		float64(usage.Completion)*p.Completion) / 1e6
+}
+
+// Cost returns the cost of usage for model, and false when the table has no
+// price for it.
+func (t PriceTable) Cost(model string, usage TokenUsage) (float64, bool) {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return 0, false
+	}
+	return price.Cost(usage), true
+}
+
+// CostAssumptions supplies what EstimateCost cannot observe from prompts.
+type CostAssumptions struct {
+	AvgCompletionTokens int    // Completion tokens per call; zero uses a per-synapse heuristic
+	Calls               int    // Calls priced in Total; zero prices one call per sample
+	Model               string // Model to price; empty uses the model the provider reports
+}
+
+// CostEstimate is a pre-flight estimate of what a set of calls will cost, in
+// US dollars. Total is a point estimate; Low and High bound it using the
+// expected error of the token estimates, which Uncertainty states relative to
+// Total. Retries, fallbacks, and session history added later are not included.
+type CostEstimate struct {
+	PerCall          float64 // Mean estimated cost of one call
+	Total            float64 // PerCall times Calls
+	Low              float64 // Lower bound of Total
+	High             float64 // Upper bound of Total
+	Uncertainty      float64 // Relative error bound of Total, e.g. 0.3 for ±30%
+	PromptTokens     int     // Mean estimated prompt tokens per call
+	CompletionTokens int     // Mean completion tokens per call, assumed or estimated
+	Calls            int     // Calls priced in Total
+	Model            string  // Model the estimate is priced for; the first call's when providers differ
+}
+
+// EstimateCost estimates the cost of firing synapse, without calling its
+// provider. Each sample is rendered through the synapse's pipeline, including
+// its options, and the resulting prompts are measured with the provider's
+// TokenEstimator when it has one or HeuristicEstimator otherwise. Completion
+// tokens come from assumptions or a rough per-synapse heuristic, and the model
+// is priced from prices by the model each provider reports.
+//
+// Samples may be strings, string slices (ranking items), or SynapseInput
+// values. The estimate scales to assumptions.Calls, so a few representative
+// samples can price a large batch.
+//
+// Example:
+//
+//	estimate, err := zyn.EstimateCost(extractor, sampleRecords, prices,
+//	    zyn.CostAssumptions{Calls: 50000})
+//	fmt.Printf("about $%.2f ($%.2f-$%.2f)\n", estimate.Total, estimate.Low, estimate.High)
+func EstimateCost(synapse Synapse, sampleInputs []any, prices PriceTable, assumptions CostAssumptions) (CostEstimate, error) {
+	if synapse == nil {
+		return CostEstimate{}, fmt.Errorf("estimate cost: synapse is required")
+	}
+	if len(sampleInputs) == 0 {
+		return CostEstimate{}, fmt.Errorf("estimate cost: at least one sample input is required")
+	}
+
+	var calls []previewCall
+	for i, sample := range sampleInputs {
+		input, err := sampleInput(sample)
+		if err != nil {
+			return CostEstimate{}, fmt.Errorf("estimate cost: sample %d: %w", i, err)
+		}
+		previewed, err := previewSynapse(synapse, input)
+		if err != nil {
+			return CostEstimate{}, fmt.Errorf("estimate cost: sample %d: %w", i, err)
+		}
+		calls = append(calls, previewed...)
+	}
+
+	var (
+		cost, low, high          float64
+		promptTokens, completion int
+	)
+	for _, call := range calls {
+		if assumptions.Model != "" {
+			call.model = assumptions.Model
+		}
+		if call.model == "" {
+			return CostEstimate{}, fmt.Errorf("estimate cost: provider does not report its model; set CostAssumptions.Model")
+		}
+		price, ok := prices.Lookup(call.model)
+		if !ok {
+			return CostEstimate{}, fmt.Errorf("estimate cost: no price for model %q", call.model)
+		}
+
+		promptErr := heuristicPromptError
+		if call.exact {
+			promptErr = estimatorPromptError
+		}
+		completionTokens, completionErr := assumptions.AvgCompletionTokens, assumedCompletionErr
+		if completionTokens <= 0 {
+			completionTokens, completionErr = estimateCompletionTokens(call), heuristicCompletionEr
+		}
+
+		promptCost := float64(call.promptTokens) * price.Prompt / 1e6
+		completionCost := float64(completionTokens) * price.Completion / 1e6
+		cost += promptCost + completionCost
+		low += promptCost*(1-promptErr) + completionCost*(1-completionErr)
+		high += promptCost*(1+promptErr) + completionCost*(1+completionErr)
+		promptTokens += call.promptTokens
+		completion += completionTokens
+	}
+
+	// Samples that fan out into several calls, such as tournament rankings,
+	// are priced per sample
+	perSample := float64(len(sampleInputs))
+	total := assumptions.Calls
+	if total <= 0 {
+		total = len(sampleInputs)
+	}
+	scale := float64(total) / perSample
+
+	model := assumptions.Model
+	if model == "" {
+		model = calls[0].model
+	}
+	estimate := CostEstimate{
+		PerCall:          cost / perSample,
+		Total:            cost * scale,
+		Low:              low * scale,
+		High:             high * scale,
+		PromptTokens:     promptTokens / len(sampleInputs),
+		CompletionTokens: completion / len(sampleInputs),
+		Calls:            total,
+		Model:            model,
+	}
+	if estimate.Total > 0 {
+		estimate.Uncertainty = (estimate.High - estimate.Total) / estimate.Total
+	}
+	return estimate, nil
+}
+
+// sampleInput converts a sample to the input accepted by Synapse.Invoke.
+func sampleInput(sample any) (SynapseInput, error) {
+	switch input := sample.(type) {
+	case string:
+		return SynapseInput{Input: input}, nil
+	case []string:
+		return SynapseInput{Items: input}, nil
+	case SynapseInput:
+		return input, nil
+	default:
+		return SynapseInput{}, fmt.Errorf("unsupported input type %T", sample)
+	}
+}
+
+// previewCall is a provider call captured by a preview.
+type previewCall struct {
+	synapseType  string
+	prompt       Prompt
+	promptTokens int    // Estimated prompt tokens, including messages before the prompt
+	exact        bool   // Whether the provider estimated the tokens itself
+	model        string // Model the provider reports, if any
+}
+
+// previewRecorder collects the provider calls of a preview.
+type previewRecorder struct {
+	mu    sync.Mutex
+	calls []previewCall
+	seen  map[string]bool
+}
+
+// previewSynapse fires synapse with input on a fresh session, capturing each
+// distinct prompt that would have been sent in place of calling the provider.
+func previewSynapse(synapse Synapse, input SynapseInput) ([]previewCall, error) {
+	recorder := &previewRecorder{seen: make(map[string]bool)}
+	ctx := context.WithValue(context.Background(), previewContextKey, recorder)
+
+	_, err := synapse.Invoke(ctx, NewSession(), input)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.calls) == 0 {
+		if err == nil {
+			err = fmt.Errorf("synapse made no provider call")
+		}
+		return nil, err
+	}
+	return recorder.calls, nil
+}
+
+// previewing reports whether ctx belongs to a preview.
+func previewing(ctx context.Context) bool {
+	_, ok := ctx.Value(previewContextKey).(*previewRecorder)
+	return ok
+}
+
+// recordPreview captures a provider call when ctx belongs to a preview and
+// reports whether it did. Retries of the same prompt are recorded once.
+func recordPreview(ctx context.Context, provider Provider, req *SynapseRequest, messages []Message) bool {
+	recorder, ok := ctx.Value(previewContextKey).(*previewRecorder)
+	if !ok {
+		return false
+	}
+	content := messages[len(messages)-1].Content
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.seen[content] {
+		return true
+	}
+	recorder.seen[content] = true
+
+	estimator := estimatorFor(provider)
+	_, heuristic := estimator.(HeuristicEstimator)
+	call := previewCall{
+		synapseType:  req.SynapseType,
+		prompt:       *req.Prompt,
+		promptTokens: estimator.EstimateMessages(messages),
+		exact:        !heuristic,
+	}
+	if capable, ok := provider.(CapabilitiesProvider); ok {
+		call.model = capable.Capabilities().Model
+	}
+	recorder.calls = append(recorder.calls, call)
+	return true
+}
+
+// estimateCompletionTokens roughly estimates the completion tokens of a call
+// from its synapse type and prompt. Responses are JSON with reasoning, so
+// even a yes/no answer costs tens of tokens.
+func estimateCompletionTokens(call previewCall) int {
+	inputTokens := (len(call.prompt.Input) + charsPerToken - 1) / charsPerToken
+	switch call.synapseType {
+	case "binary":
+		return 60
+	case "classification", "taxonomy":
+		return 80
+	case "intent":
+		return 100
+	case "choose":
+		return 80
+	case "ranking":
+		return 40 + 12*len(call.prompt.Items)
+	case "sentiment":
+		return 120
+	case "compare":
+		return 90
+	case "answer":
+		return 200
+	case "verify":
+		return 40 + 40*len(call.prompt.Items)
+	case "dedupe", "cluster":
+		return 40 + 15*len(call.prompt.Items)
+	case "match":
+		return 40 + 20*min(len(call.prompt.Left), len(call.prompt.Right))
+	case "moderation":
+		return 60 + 10*len(call.prompt.Categories)
+	case "transform", "translate", "segment", "redact", "complete":
+		return 80 + inputTokens
+	case "summarize":
+		return 120 + inputTokens/4
+	case "extraction", "extract_all", "convert", "diff":
+		return 80 + inputTokens/2
+	case "analyze", "compare_analyze":
+		return 300
+	default:
+		return 150
+	}
+}
@@ -0,0 +1,220 @@
+package zyn
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// pricedProvider reports its model and estimates every call at a fixed count.
+type pricedProvider struct {
+	model  string
+	tokens int // Estimated prompt tokens; zero leaves estimation to the heuristic
+	calls  atomic.Int32
+}
+
+func (p *pricedProvider) Call(_ context.Context, _ []Message, _ float32) (*ProviderResponse, error) {
+	p.calls.Add(1)
+	return &ProviderResponse{Content: tokensTestResponse}, nil
+}
+
+func (*pricedProvider) Name() string { return "priced" }
+
+func (p *pricedProvider) Capabilities() Capabilities {
+	return Capabilities{Model: p.model}
+}
+
+// estimatingProvider adds a provider-supplied estimator to pricedProvider.
+type estimatingProvider struct {
+	pricedProvider
+}
+
+func (p *estimatingProvider) EstimateMessages(_ []Message) int { return p.tokens }
+
+var testPrices = PriceTable{
+	"test-model":       {Prompt: 2, Completion: 10},
+	"test-model-large": {Prompt: 5, Completion: 20},
+}
+
+func TestEstimateCost(t *testing.T) {
+	t.Run("prices estimated tokens", func(t *testing.T) {
+		provider := &estimatingProvider{pricedProvider{model: "test-model", tokens: 1000}}
+		synapse, _ := Binary("Is this valid?", provider)
+
+		estimate, err := EstimateCost(synapse, []any{"first", "second"}, testPrices,
+			CostAssumptions{AvgCompletionTokens: 100, Calls: 1000})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if provider.calls.Load() != 0 {
+			t.Errorf("expected no provider calls, got %d", provider.calls.Load())
+		}
+
+		// 1000 prompt tokens at $2/M plus 100 completion tokens at $10/M
+		perCall := 0.002 + 0.001
+		if math.Abs(estimate.PerCall-perCall) > 1e-12 || math.Abs(estimate.Total-perCall*1000) > 1e-9 {
+			t.Errorf("expected $%f per call and $%f total, got %+v", perCall, perCall*1000, estimate)
+		}
+		if estimate.PromptTokens != 1000 || estimate.CompletionTokens != 100 || estimate.Calls != 1000 {
+			t.Errorf("unexpected token counts: %+v", estimate)
+		}
+		if estimate.Model != "test-model" {
+			t.Errorf("expected provider model, got %q", estimate.Model)
+		}
+
+		// ±5% on the prompt and ±25% on the assumed completion
+		low, high := (0.002*0.95+0.001*0.75)*1000, (0.002*1.05+0.001*1.25)*1000
+		if math.Abs(estimate.Low-low) > 1e-9 || math.Abs(estimate.High-high) > 1e-9 {
+			t.Errorf("expected bounds %f-%f, got %f-%f", low, high, estimate.Low, estimate.High)
+		}
+		if math.Abs(estimate.Uncertainty-(high-estimate.Total)/estimate.Total) > 1e-9 {
+			t.Errorf("unexpected uncertainty %f", estimate.Uncertainty)
+		}
+	})
+
+	t.Run("heuristic estimates widen bounds", func(t *testing.T) {
+		exact, _ := Binary("Is this valid?", &estimatingProvider{pricedProvider{model: "test-model", tokens: 500}})
+		heuristic, _ := Binary("Is this valid?", &pricedProvider{model: "test-model"})
+
+		exactEstimate, err := EstimateCost(exact, []any{"input"}, testPrices, CostAssumptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		heuristicEstimate, err := EstimateCost(heuristic, []any{"input"}, testPrices, CostAssumptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if heuristicEstimate.PromptTokens <= 0 || heuristicEstimate.CompletionTokens != 60 {
+			t.Errorf("expected heuristic tokens, got %+v", heuristicEstimate)
+		}
+		if heuristicEstimate.Uncertainty <= exactEstimate.Uncertainty {
+			t.Errorf("expected heuristic estimate to be less certain: %f <= %f",
+				heuristicEstimate.Uncertainty, exactEstimate.Uncertainty)
+		}
+		if heuristicEstimate.Low >= heuristicEstimate.Total || heuristicEstimate.High <= heuristicEstimate.Total {
+			t.Errorf("expected bounds around the total, got %+v", heuristicEstimate)
+		}
+	})
+
+	t.Run("includes options that shape the prompt", func(t *testing.T) {
+		provider := &pricedProvider{model: "test-model"}
+		plain, _ := Binary("Is this valid?", provider)
+		scored, _ := Binary("Is this valid?", provider, WithBinaryScore())
+
+		plainEstimate, _ := EstimateCost(plain, []any{"input"}, testPrices, CostAssumptions{AvgCompletionTokens: 50})
+		scoredEstimate, _ := EstimateCost(scored, []any{"input"}, testPrices, CostAssumptions{AvgCompletionTokens: 50})
+		if scoredEstimate.PromptTokens <= plainEstimate.PromptTokens {
+			t.Errorf("expected the score request to add prompt tokens: %d <= %d",
+				scoredEstimate.PromptTokens, plainEstimate.PromptTokens)
+		}
+	})
+
+	t.Run("prices every group call of a tournament", func(t *testing.T) {
+		provider := &estimatingProvider{pricedProvider{model: "test-model", tokens: 100}}
+		synapse, _ := Ranking("priority", provider, WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 1}))
+
+		estimate, err := EstimateCost(synapse, []any{shuffledItems(20)}, testPrices, CostAssumptions{AvgCompletionTokens: 100})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if estimate.PromptTokens != 400 || estimate.CompletionTokens != 400 {
+			t.Errorf("expected four group calls per sample, got %+v", estimate)
+		}
+	})
+
+	t.Run("dated snapshot uses base price", func(t *testing.T) {
+		synapse, _ := Binary("Is this valid?", &pricedProvider{model: "test-model-large-2025-01-01"})
+		estimate, err := EstimateCost(synapse, []any{"input"}, testPrices, CostAssumptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if estimate.Model != "test-model-large-2025-01-01" {
+			t.Errorf("unexpected model %q", estimate.Model)
+		}
+	})
+
+	t.Run("model assumption overrides provider", func(t *testing.T) {
+		synapse, _ := Binary("Is this valid?", NewMockProvider())
+		if _, err := EstimateCost(synapse, []any{"input"}, testPrices, CostAssumptions{}); err == nil ||
+			!strings.Contains(err.Error(), "does not report its model") {
+			t.Errorf("expected missing model error, got %v", err)
+		}
+		estimate, err := EstimateCost(synapse, []any{"input"}, testPrices, CostAssumptions{Model: "test-model"})
+		if err != nil || estimate.Model != "test-model" {
+			t.Errorf("expected assumed model, got %+v, %v", estimate, err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		synapse, _ := Binary("Is this valid?", &pricedProvider{model: "unknown-model"})
+		cases := map[string][]any{
+			"no price":          {"input"},
+			"unsupported input": {42},
+			"sample input":      nil,
+		}
+		for want, samples := range cases {
+			if _, err := EstimateCost(synapse, samples, testPrices, CostAssumptions{}); err == nil ||
+				!strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q error, got %v", want, err)
+			}
+		}
+	})
+}
+
+func TestPriceTable_Lookup(t *testing.T) {
+	tests := map[string]string{
+		"test-model":                  "test-model",
+		"test-model-2025-01-01":       "test-model",
+		"test-model-large":            "test-model-large",
+		"test-model-large-2025-01-01": "test-model-large",
+		"test-modelx":                 "",
+	}
+	for model, want := range tests {
+		price, ok := testPrices.Lookup(model)
+		if want == "" {
+			if ok {
+				t.Errorf("expected no price for %q", model)
+			}
+			continue
+		}
+		if !ok || price != testPrices[want] {
+			t.Errorf("expected %q priced as %q, got %+v", model, want, price)
+		}
+	}
+}
+
+func TestEstimateCompletionTokens(t *testing.T) {
+	ranking := estimateCompletionTokens(previewCall{synapseType: "ranking", prompt: Prompt{Items: []string{"a", "b"}}})
+	if ranking != 64 {
+		t.Errorf("expected 64 tokens for two items, got %d", ranking)
+	}
+	short := estimateCompletionTokens(previewCall{synapseType: "transform", prompt: Prompt{Input: "text"}})
+	long := estimateCompletionTokens(previewCall{synapseType: "transform", prompt: Prompt{Input: strings.Repeat("text ", 200)}})
+	if long <= short {
+		t.Errorf("expected longer transforms to cost more: %d <= %d", long, short)
+	}
+}
+
+func TestModelPrice_Cost(t *testing.T) {
+	price := ModelPrice{Prompt: 2, Completion: 10, CachedPrompt: 0.5}
+	usage := TokenUsage{Prompt: 1_000_000, Completion: 100_000, CachedPrompt: 400_000}
+
+	// 600k uncached at $2/M, 400k cached at $0.50/M, 100k completion at $10/M
+	if cost := price.Cost(usage); math.Abs(cost-(1.2+0.2+1.0)) > 1e-9 {
+		t.Errorf("expected $2.40, got $%f", cost)
+	}
+
+	price.CachedPrompt = 0
+	if cost := price.Cost(usage); math.Abs(cost-(2.0+1.0)) > 1e-9 {
+		t.Errorf("expected cached tokens at the prompt rate, got $%f", cost)
+	}
+
+	if _, ok := testPrices.Cost("unknown-model", usage); ok {
+		t.Error("expected no price for unknown model")
+	}
+	if cost, ok := testPrices.Cost("test-model-2025-01-01", usage); !ok || math.Abs(cost-3.0) > 1e-9 {
+		t.Errorf("expected $3.00 from the base price, got $%f, %v", cost, ok)
+	}
+}
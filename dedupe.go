@@ -0,0 +1,253 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultDedupeMaxItems is the most items a dedupe synapse accepts in one
+// call unless DedupeInput.MaxItems says otherwise. Beyond it, models start
+// dropping or repeating items.
+const DefaultDedupeMaxItems = 100
+
+// DedupeInput contains rich input structure for deduplication.
+type DedupeInput struct {
+	Items       []string // The items to group
+	Context     string   // Optional context, such as what makes two items the same
+	MaxItems    int      // Most distinct items accepted in one call; 0 uses DefaultDedupeMaxItems
+	Temperature float32  // LLM temperature setting for this specific request
+}
+
+// DedupeResponse contains the response from a dedupe synapse.
+type DedupeResponse struct {
+	Groups     [][]string `json:"groups"`     // Items judged to be the same entity, one group per entity
+	Canonical  []string   `json:"canonical"`  // Preferred representative of each group, by index
+	Confidence float64    `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string   `json:"reasoning"`  // Explanation of the grouping
+}
+
+// Validate checks if the response is valid. That the groups partition the
+// input items is checked by the synapse.
+func (r DedupeResponse) Validate() error {
+	if len(r.Groups) == 0 {
+		return fmt.Errorf("groups required but empty")
+	}
+	for i, group := range r.Groups {
+		if len(group) == 0 {
+			return fmt.Errorf("group %d is empty", i)
+		}
+	}
+	if len(r.Canonical) != len(r.Groups) {
+		return fmt.Errorf("canonical must name one representative per group: %d for %d groups", len(r.Canonical), len(r.Groups))
+	}
+	for i, canonical := range r.Canonical {
+		if strings.TrimSpace(canonical) == "" {
+			return fmt.Errorf("canonical %d is empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// validateGroups checks that every item of the prompt appears in exactly one
+// group, exactly once, and that the groups hold nothing else.
+func validateGroups(prompt *Prompt, response DedupeResponse) error {
+	return checkPartition(prompt.Items, response.Groups, "group")
+}
+
+// checkPartition checks that every item appears in exactly one of groups,
+// exactly once, and that the groups hold nothing else. Errors name a group
+// by noun and index, e.g. "group 2".
+func checkPartition(items []string, groups [][]string, noun string) error {
+	seen := make(map[string]int, len(items))
+	for i, group := range groups {
+		for _, item := range group {
+			if !slices.Contains(items, item) {
+				return fmt.Errorf("%s %d: unknown item %q", noun, i, item)
+			}
+			if previous, ok := seen[item]; ok {
+				if previous == i {
+					return fmt.Errorf("item %q appears twice in %s %d", item, noun, i)
+				}
+				return fmt.Errorf("item %q appears in %s %d and %s %d", item, noun, previous, noun, i)
+			}
+			seen[item] = i
+		}
+	}
+	var missing []string
+	for _, item := range items {
+		if _, ok := seen[item]; !ok {
+			missing = append(missing, fmt.Sprintf("%q", item))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("items missing from the %ss: %s", noun, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// distinctItems returns items with exact repeats dropped, keeping the first
+// of each.
+func distinctItems(items []string) []string {
+	distinct := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			distinct = append(distinct, item)
+		}
+	}
+	return distinct
+}
+
+// DedupeSynapse groups fuzzy duplicates, such as differently spelled names
+// of the same company.
+type DedupeSynapse struct {
+	what     string
+	defaults DedupeInput
+	base     *Base[DedupeInput, DedupeResponse]
+}
+
+// NewDedupe creates a new dedupe synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewDedupe(what string, provider Provider, opts ...Option) (*DedupeSynapse, error) {
+	synapse := &DedupeSynapse{what: what}
+
+	base, err := NewSynapse(SynapseConfig[DedupeInput, DedupeResponse]{
+		Type:        "dedupe",
+		Temperature: DefaultTemperatureAnalytical,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = validateGroups
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (d *DedupeSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return d.base.GetPipeline()
+}
+
+// WithDefaults creates a new Dedupe with default input values.
+// These are merged with user input at execution time.
+func (d *DedupeSynapse) WithDefaults(defaults DedupeInput) *DedupeSynapse {
+	d.defaults = defaults
+	return d
+}
+
+// Fire groups items that name the same entity.
+func (d *DedupeSynapse) Fire(ctx context.Context, session *Session, items []string) (DedupeResponse, error) {
+	return d.FireWithInput(ctx, session, DedupeInput{Items: items})
+}
+
+// FireResult groups items and returns the response in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (d *DedupeSynapse) FireResult(ctx context.Context, session *Session, items []string) (Result[DedupeResponse], error) {
+	return d.execute(ctx, session, DedupeInput{Items: items})
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (d *DedupeSynapse) FireWithInput(ctx context.Context, session *Session, input DedupeInput) (DedupeResponse, error) {
+	result, err := d.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a DedupeResponse.
+func (d *DedupeSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := d.FireWithInput(ctx, session, DedupeInput{
+		Items:       input.items(),
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and groups the distinct items.
+// Lists over the item limit are rejected with a *TooManyItemsError rather
+// than risk items being dropped.
+func (d *DedupeSynapse) execute(ctx context.Context, session *Session, input DedupeInput) (Result[DedupeResponse], error) {
+	merged := d.mergeInputs(input)
+	if len(merged.Items) == 0 {
+		return Result[DedupeResponse]{Provider: d.base.service.providerName},
+			fmt.Errorf("%w: dedupe synapse needs items", ErrInvalidPrompt)
+	}
+	limit := merged.MaxItems
+	if limit <= 0 {
+		limit = DefaultDedupeMaxItems
+	}
+	if len(merged.Items) > limit {
+		return Result[DedupeResponse]{Provider: d.base.service.providerName},
+			&TooManyItemsError{Synapse: "dedupe", Items: len(merged.Items), Limit: limit}
+	}
+	return d.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+}
+
+// mergeInputs combines defaults with user input. Exact repeats are dropped
+// from the items, keeping the first of each.
+func (d *DedupeSynapse) mergeInputs(input DedupeInput) DedupeInput {
+	merged := d.defaults
+
+	if len(input.Items) > 0 {
+		merged.Items = input.Items
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.MaxItems != 0 {
+		merged.MaxItems = input.MaxItems
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	merged.Items = distinctItems(merged.Items)
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (d *DedupeSynapse) buildPrompt(input DedupeInput) *Prompt {
+	return &Prompt{
+		Task:    fmt.Sprintf("Group the items that refer to the same %s", d.what),
+		Items:   input.Items,
+		Context: input.Context,
+		Constraints: []string{
+			"groups: every item in exactly one group, exactly once; an item with no duplicates is a group of its own",
+			"groups: preserve exact item text",
+			"canonical: the preferred name of each group's entity, in group order",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the grouping",
+		},
+	}
+}
+
+// Dedupe creates a new dedupe synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	companies, err := Dedupe("company", provider, WithValidationRetry(2))
+//	response, err := companies.Fire(ctx, session, []string{"Acme Inc.", "ACME", "Globex"})
+//	// response.Groups: [["Acme Inc.", "ACME"], ["Globex"]]
+func Dedupe(what string, provider Provider, opts ...Option) (*DedupeSynapse, error) {
+	return NewDedupe(what, provider, opts...)
+}
@@ -0,0 +1,217 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// companyNames are the items of the dedupe tests.
+var companyNames = []string{"Acme Inc.", "ACME", "Globex", "Acme Incorporated", "Initech"}
+
+// companyGroups is a valid dedupe response for companyNames.
+const companyGroups = `{
+	"groups": [["Acme Inc.", "ACME", "Acme Incorporated"], ["Globex"], ["Initech"]],
+	"canonical": ["Acme Inc.", "Globex", "Initech"],
+	"confidence": 0.9,
+	"reasoning": ["the Acme spellings name one company"]
+}`
+
+func TestDedupeResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a", "A"}, {"b"}},
+			Canonical:  []string{"a", "b"},
+			Confidence: 0.8,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_groups", func(t *testing.T) {
+		r := DedupeResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no groups")
+		}
+	})
+
+	t.Run("empty_group", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a"}, {}},
+			Canonical:  []string{"a", "b"},
+			Confidence: 0.8,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an empty group")
+		}
+	})
+
+	t.Run("canonical_count", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a", "A"}, {"b"}},
+			Canonical:  []string{"a"},
+			Confidence: 0.8,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a canonical count that differs from the groups")
+		}
+	})
+
+	t.Run("empty_canonical", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a", "A"}, {"b"}},
+			Canonical:  []string{"a", " "},
+			Confidence: 0.8,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank canonical item")
+		}
+	})
+
+	t.Run("confidence_too_low", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a", "A"}, {"b"}},
+			Canonical:  []string{"a", "b"},
+			Confidence: -1,
+			Reasoning:  []string{"case differs"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for negative confidence")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := DedupeResponse{
+			Groups:     [][]string{{"a", "A"}, {"b"}},
+			Canonical:  []string{"a", "b"},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestDedupeSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return companyGroups, nil
+	})
+	synapse, err := Dedupe("company", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := synapse.Fire(context.Background(), NewSession(), companyNames)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Groups) != 3 || len(response.Groups[0]) != 3 || response.Canonical[0] != "Acme Inc." {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{"same company", "1. Acme Inc.", "5. Initech", "exactly one group"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestDedupeSynapse_Partition(t *testing.T) {
+	tests := []struct {
+		name     string
+		groups   string
+		expected string
+	}{
+		{"missing item", `[["Acme Inc.", "ACME", "Acme Incorporated"], ["Globex"]]`, `items missing from the groups: "Initech"`},
+		{"repeated item", `[["Acme Inc.", "ACME", "Acme Incorporated"], ["Globex", "ACME"], ["Initech"]]`, `item "ACME" appears in group 0 and group 1`},
+		{"repeated within a group", `[["Acme Inc.", "ACME", "ACME", "Acme Incorporated"], ["Globex"], ["Initech"]]`, `item "ACME" appears twice in group 0`},
+		{"unknown item", `[["Acme Inc.", "ACME", "Acme Incorporated"], ["Globex", "Globex Corp"], ["Initech"]]`, `unknown item "Globex Corp"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := strings.Count(tt.groups, "[") - 1
+			canonical := `"x"` + strings.Repeat(`, "x"`, count-1)
+			response := `{"groups": ` + tt.groups + `, "canonical": [` + canonical + `], "confidence": 0.9, "reasoning": ["x"]}`
+			synapse, _ := Dedupe("company", NewMockProviderWithResponse(response))
+
+			_, err := synapse.Fire(context.Background(), NewSession(), companyNames)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDedupeSynapse_ExactRepeats(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return companyGroups, nil
+	})
+	synapse, _ := Dedupe("company", provider)
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), append(companyNames, "ACME", "Globex")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(prompt, ". ACME\n") != 1 || strings.Contains(prompt, "6.") {
+		t.Errorf("expected exact repeats sent once, got %s", prompt)
+	}
+}
+
+func TestDedupeSynapse_TooManyItems(t *testing.T) {
+	var calls int
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		return companyGroups, nil
+	})
+	synapse, _ := Dedupe("company", provider)
+
+	items := make([]string, DefaultDedupeMaxItems+1)
+	for i := range items {
+		items[i] = strings.Repeat("x", i+1)
+	}
+	_, err := synapse.Fire(context.Background(), NewSession(), items)
+	var tooMany *TooManyItemsError
+	if !errors.As(err, &tooMany) || tooMany.Items != 101 || tooMany.Limit != 100 {
+		t.Fatalf("expected a TooManyItemsError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "split the list into chunks of at most 100") {
+		t.Errorf("expected chunking suggested, got %v", err)
+	}
+
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), DedupeInput{Items: companyNames, MaxItems: 4}); !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("expected the input's limit applied, got %v", err)
+	}
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), DedupeInput{Items: companyNames, MaxItems: 5}); err != nil {
+		t.Errorf("expected a list at the limit accepted, got %v", err)
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), nil); !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt without items, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected one provider call, got %d", calls)
+	}
+}
+
+func TestDedupeSynapse_Invoke(t *testing.T) {
+	synapse, _ := Dedupe("company", NewMockProviderWithResponse(companyGroups))
+
+	result, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: strings.Join(companyNames, "\n")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := result.(DedupeResponse); !ok || len(response.Groups) != 3 {
+		t.Errorf("expected a DedupeResponse, got %+v", result)
+	}
+}
@@ -0,0 +1,248 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DiffInput contains rich input structure for diff summaries.
+type DiffInput[T any] struct {
+	Before      T       // The earlier version
+	After       T       // The later version
+	Context     string  // Optional context, such as what the value configures
+	Temperature float32 // Temperature for the summary
+}
+
+// Change is a difference between the two versions.
+type Change struct {
+	Path   string `json:"path"`   // Key path of the changed field, e.g. spec.containers[0].image; $ for the whole value
+	Before string `json:"before"` // The earlier value; empty if the field was added
+	After  string `json:"after"`  // The later value; empty if the field was removed
+	Impact string `json:"impact"` // What the change means and whether it matters
+}
+
+// DiffResponse contains the response from a diff synapse.
+type DiffResponse struct {
+	Summary    string   `json:"summary"`    // What changed, in a sentence or two
+	Changes    []Change `json:"changes"`    // The changed fields; empty if the versions are the same
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the assessment
+}
+
+// Validate checks if the response is valid. Paths are checked against the
+// values by the synapse.
+func (r DiffResponse) Validate() error {
+	if strings.TrimSpace(r.Summary) == "" {
+		return fmt.Errorf("summary required but empty")
+	}
+	for i, change := range r.Changes {
+		if strings.TrimSpace(change.Path) == "" {
+			return fmt.Errorf("change %d: path required but empty", i)
+		}
+		if strings.TrimSpace(change.Impact) == "" {
+			return fmt.Errorf("change %d: impact required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkPaths rejects changes whose path is not a key path of either value.
+func checkPaths(paths map[string]bool, response DiffResponse) error {
+	for i, change := range response.Changes {
+		if !paths[normalizePath(change.Path)] {
+			return fmt.Errorf("change %d: path %q is in neither version", i, change.Path)
+		}
+	}
+	return nil
+}
+
+// walkPaths adds path and the paths of the values nested in value, a
+// decoded JSON value, to paths, in the form normalizePath returns. The whole
+// value is "$".
+func walkPaths(value any, path string, paths map[string]bool) {
+	if path == "" {
+		paths["$"] = true
+	} else {
+		paths[path] = true
+	}
+	var nested map[string]any
+	switch v := value.(type) {
+	case map[string]any:
+		nested = v
+	case []any:
+		nested = make(map[string]any, len(v))
+		for i, element := range v {
+			nested[strconv.Itoa(i)] = element
+		}
+	}
+	for key, child := range nested {
+		if path == "" {
+			walkPaths(child, key, paths)
+		} else {
+			walkPaths(child, path+"."+key, paths)
+		}
+	}
+}
+
+// normalizePath returns path with keys and array indices all joined by dots,
+// e.g. containers.0.image for containers[0].image, and a leading "$." or "."
+// removed. The whole value is "$".
+func normalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+// DiffSynapse summarizes what changed between two versions of a value of
+// type T and whether it matters.
+type DiffSynapse[T any] struct {
+	focus    string // What the changes are assessed for
+	schema   string // Pre-computed JSON schema
+	defaults DiffInput[T]
+	service  *Service[DiffResponse]
+}
+
+// Diff creates a new diff synapse for values of type T.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	audit, err := Diff[Deployment]("availability and security risk", provider)
+//	response, err := audit.Fire(ctx, session, current, proposed)
+//	for _, change := range response.Changes {
+//	    log.Printf("%s: %s -> %s (%s)", change.Path, change.Before, change.After, change.Impact)
+//	}
+func Diff[T any](focus string, provider Provider, opts ...Option) (*DiffSynapse[T], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[DiffResponse]()
+	if err != nil {
+		return nil, fmt.Errorf("diff synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[DiffResponse](pipeline, "diff", provider, DefaultTemperatureAnalytical)
+
+	return &DiffSynapse[T]{
+		focus:   focus,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the underlying pipeline.
+func (d *DiffSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return d.service.GetPipeline()
+}
+
+// WithDefaults creates a new Diff with default input values.
+// These are merged with user input at execution time.
+func (d *DiffSynapse[T]) WithDefaults(defaults DiffInput[T]) *DiffSynapse[T] {
+	d.defaults = defaults
+	return d
+}
+
+// Fire summarizes what changed from before to after.
+func (d *DiffSynapse[T]) Fire(ctx context.Context, session *Session, before, after T) (DiffResponse, error) {
+	return d.FireWithInput(ctx, session, DiffInput[T]{Before: before, After: after})
+}
+
+// FireResult summarizes what changed from before to after and returns the
+// response in a Result envelope carrying the call's usage, timing, and
+// request metadata.
+func (d *DiffSynapse[T]) FireResult(ctx context.Context, session *Session, before, after T) (Result[DiffResponse], error) {
+	return d.execute(ctx, session, DiffInput[T]{Before: before, After: after})
+}
+
+// FireWithInput summarizes what changed with rich input.
+func (d *DiffSynapse[T]) FireWithInput(ctx context.Context, session *Session, input DiffInput[T]) (DiffResponse, error) {
+	result, err := d.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// execute merges input with the defaults and summarizes the changes,
+// rejecting changes to paths in neither version.
+func (d *DiffSynapse[T]) execute(ctx context.Context, session *Session, input DiffInput[T]) (Result[DiffResponse], error) {
+	merged := d.mergeInputs(input)
+
+	before, err := toJSONValue(merged.Before)
+	if err != nil {
+		return Result[DiffResponse]{Provider: d.service.providerName},
+			fmt.Errorf("%w: diff synapse cannot marshal before: %w", ErrInvalidPrompt, err)
+	}
+	after, err := toJSONValue(merged.After)
+	if err != nil {
+		return Result[DiffResponse]{Provider: d.service.providerName},
+			fmt.Errorf("%w: diff synapse cannot marshal after: %w", ErrInvalidPrompt, err)
+	}
+	paths := make(map[string]bool)
+	walkPaths(before, "", paths)
+	walkPaths(after, "", paths)
+
+	result, err := d.service.executeChecked(ctx, session, d.buildPrompt(merged), merged.Temperature, func(response DiffResponse) error {
+		return checkPaths(paths, response)
+	})
+	if err != nil {
+		return result, fmt.Errorf("diff failed: %w", err)
+	}
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input. The versions are always
+// the input's, as a zero value is a valid version.
+func (d *DiffSynapse[T]) mergeInputs(input DiffInput[T]) DiffInput[T] {
+	merged := d.defaults
+	merged.Before = input.Before
+	merged.After = input.After
+
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input, with both
+// versions labeled BEFORE and AFTER.
+func (d *DiffSynapse[T]) buildPrompt(input DiffInput[T]) *Prompt {
+	return &Prompt{
+		Task:    fmt.Sprintf("Summarize what changed between the two versions and whether it matters for %s", d.focus),
+		Input:   renderSnapshots(input.Before, input.After),
+		Context: input.Context,
+		Schema:  d.schema,
+		Constraints: []string{
+			"summary: what changed, in a sentence or two",
+			"changes: one per changed field, at the deepest path that changed; empty if nothing changed",
+			"path: the field's key path in the JSON, with keys joined by dots and array elements as [i], e.g. spec.containers[0].image; $ for the whole value",
+			"before and after: the field's values, empty when the field was added or removed",
+			"impact: what the change means and whether it matters; say so when it does not",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the assessment",
+		},
+	}
+}
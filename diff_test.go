@@ -0,0 +1,257 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// diffContainer is a nested value of the diff tests.
+type diffContainer struct {
+	Image string `json:"image"`
+}
+
+// diffDeployment is the value type of the diff tests.
+type diffDeployment struct {
+	Replicas   int             `json:"replicas"`
+	Containers []diffContainer `json:"containers"`
+	Public     bool            `json:"public,omitempty"`
+}
+
+var (
+	currentDeployment  = diffDeployment{Replicas: 3, Containers: []diffContainer{{Image: "api:1.4"}}}
+	proposedDeployment = diffDeployment{Replicas: 1, Containers: []diffContainer{{Image: "api:1.5"}}, Public: true}
+)
+
+func TestDiffResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    "x",
+			Changes:    []Change{{Path: "a", Before: "1", After: "2", Impact: "minor"}},
+			Confidence: 0.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_summary", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    " ",
+			Changes:    []Change{{Path: "a", Before: "1", After: "2", Impact: "minor"}},
+			Confidence: 0.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank summary")
+		}
+	})
+
+	t.Run("no_path", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    "x",
+			Changes:    []Change{{Impact: "x"}},
+			Confidence: 0.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a change without a path")
+		}
+	})
+
+	t.Run("no_impact", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    "x",
+			Changes:    []Change{{Path: "a"}},
+			Confidence: 0.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a change without an impact")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    "x",
+			Changes:    []Change{{Path: "a", Before: "1", After: "2", Impact: "minor"}},
+			Confidence: 1.5,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := DiffResponse{
+			Summary:    "x",
+			Changes:    []Change{{Path: "a", Before: "1", After: "2", Impact: "minor"}},
+			Confidence: 0.5,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := map[string]string{
+		"replicas":                "replicas",
+		"$.replicas":              "replicas",
+		".replicas":               "replicas",
+		"containers[0].image":     "containers.0.image",
+		"containers.0.image":      "containers.0.image",
+		"$":                       "$",
+		" spec.containers[12] ":   "spec.containers.12",
+		"$.containers[0].image":   "containers.0.image",
+		"labels.2024.description": "labels.2024.description",
+	}
+	for path, expected := range tests {
+		if got := normalizePath(path); got != expected {
+			t.Errorf("normalizePath(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestDiffSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{
+			"summary": "Fewer replicas, a new image, and public exposure.",
+			"changes": [
+				{"path": "replicas", "before": "3", "after": "1", "impact": "no redundancy"},
+				{"path": "$.containers.0.image", "before": "api:1.4", "after": "api:1.5", "impact": "minor upgrade"},
+				{"path": "public", "before": "", "after": "true", "impact": "exposed to the internet"}
+			],
+			"confidence": 0.9,
+			"reasoning": ["compared fields"]
+		}`, nil
+	})
+	audit, err := Diff[diffDeployment]("availability and security risk", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := audit.Fire(context.Background(), NewSession(), currentDeployment, proposedDeployment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Changes) != 3 || response.Changes[0].Impact != "no redundancy" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{
+		"availability and security risk",
+		"BEFORE:\n{\n  \"replicas\": 3,",
+		"AFTER:\n{\n  \"replicas\": 1,",
+		"\"public\": true",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Index(prompt, "BEFORE:") > strings.Index(prompt, "AFTER:") {
+		t.Errorf("expected BEFORE ahead of AFTER, got %s", prompt)
+	}
+}
+
+func TestDiffSynapse_UnknownPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"unknown field", "memory"},
+		{"index out of range", "containers[1].image"},
+		{"unknown nested field", "containers[0].tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [{"path": "` + tt.path + `", "before": "", "after": "x", "impact": "x"}], "confidence": 0.9, "reasoning": ["compared fields"]}`
+			audit, _ := Diff[diffDeployment]("risk", NewMockProviderWithResponse(response))
+
+			_, err := audit.Fire(context.Background(), NewSession(), currentDeployment, proposedDeployment)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `path "`+tt.path+`" is in neither version`) {
+				t.Errorf("expected ErrInvalidResponse naming the path, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDiffSynapse_NumericKeys(t *testing.T) {
+	response := `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [{"path": "releases.2024", "before": "beta", "after": "stable", "impact": "x"}], "confidence": 0.9, "reasoning": ["compared fields"]}`
+	audit, _ := Diff[map[string]map[string]string]("release channels", NewMockProviderWithResponse(response))
+
+	before := map[string]map[string]string{"releases": {"2024": "beta"}}
+	after := map[string]map[string]string{"releases": {"2024": "stable"}}
+	if _, err := audit.Fire(context.Background(), NewSession(), before, after); err != nil {
+		t.Errorf("expected a numeric object key accepted, got %v", err)
+	}
+}
+
+func TestDiffSynapse_ScalarValues(t *testing.T) {
+	response := `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [{"path": "$", "before": "30s", "after": "5m", "impact": "slower failure detection"}], "confidence": 0.9, "reasoning": ["compared fields"]}`
+	audit, _ := Diff[string]("timeouts", NewMockProviderWithResponse(response))
+
+	result, err := audit.FireResult(context.Background(), NewSession(), "30s", "5m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value.Changes) != 1 || result.Value.Changes[0].Path != "$" {
+		t.Errorf("unexpected response: %+v", result.Value)
+	}
+}
+
+func TestDiffSynapse_UnmarshalableValue(t *testing.T) {
+	var calls int
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		return `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [], "confidence": 0.9, "reasoning": ["compared fields"]}`, nil
+	})
+	audit, _ := Diff[chan int]("x", provider)
+
+	_, err := audit.Fire(context.Background(), NewSession(), make(chan int), make(chan int))
+	if !errors.Is(err, ErrInvalidPrompt) || calls != 0 {
+		t.Errorf("expected ErrInvalidPrompt before any call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDiffSynapse_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [], "confidence": 0.9, "reasoning": ["compared fields"]}`, nil
+	})
+	audit, _ := Diff[diffDeployment]("risk", provider)
+	audit.WithDefaults(DiffInput[diffDeployment]{Context: "production cluster"})
+
+	if _, err := audit.FireWithInput(context.Background(), NewSession(), DiffInput[diffDeployment]{Before: currentDeployment, After: currentDeployment}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Context: production cluster") {
+		t.Errorf("expected the default context, got %s", prompt)
+	}
+}
+
+func TestDiffSynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [{"path": "memory", "before": "", "after": "1Gi", "impact": "x"}], "confidence": 0.9, "reasoning": ["compared fields"]}`, nil
+		}
+		return `{"summary": "Fewer replicas, a new image, and public exposure.", "changes": [{"path": "replicas", "before": "3", "after": "1", "impact": "no redundancy"}], "confidence": 0.9, "reasoning": ["compared fields"]}`, nil
+	})
+	audit, _ := Diff[diffDeployment]("risk", provider, WithValidationRetry(2))
+
+	response, err := audit.Fire(context.Background(), NewSession(), currentDeployment, proposedDeployment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(response.Changes) != 1 {
+		t.Errorf("expected a retry after the invented path, got %d calls and %+v", calls, response)
+	}
+}
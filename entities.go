@@ -0,0 +1,201 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Entity is a named entity found in text, labeled with one of the declared
+// entity types.
+type Entity struct {
+	Text       string `json:"text"`       // The entity exactly as written in the input
+	Type       string `json:"type"`       // One of the declared entity types
+	Normalized string `json:"normalized"` // Canonical form, e.g. "Microsoft" for "Microsoft Corporation"; Text if none
+}
+
+// EntityResponse contains the response from an entity extraction synapse.
+type EntityResponse struct {
+	Entities   []Entity `json:"entities"`   // Entities in order of appearance; empty if there are none
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the extraction
+}
+
+// Validate checks if the response is valid. Entity types are checked
+// against the declared types by the synapse.
+func (r EntityResponse) Validate() error {
+	for i, entity := range r.Entities {
+		if strings.TrimSpace(entity.Text) == "" {
+			return fmt.Errorf("entity %d: text required but empty", i)
+		}
+		if strings.TrimSpace(entity.Type) == "" {
+			return fmt.Errorf("entity %d: type required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// EntitySynapse extracts named entities from text, labeling each with one of
+// a fixed set of entity types.
+type EntitySynapse struct {
+	types      []string // Declared entity types, in order
+	extraction *ExtractionSynapse[EntityResponse]
+}
+
+// ExtractEntities creates a new entity extraction synapse bound to a
+// provider. Each entity is labeled with one of types, such as "person" or
+// "organization", so names of different kinds can be told apart.
+// Returns an error if types is empty, a type is empty or declared twice, or
+// the JSON schema cannot be generated.
+//
+// Example:
+//
+//	extractor, err := ExtractEntities([]string{"person", "organization"}, provider)
+//	entities, err := extractor.Fire(ctx, session, "Satya Nadella runs Microsoft Corporation.")
+//	// entities[1]: {Text: "Microsoft Corporation", Type: "organization", Normalized: "Microsoft"}
+func ExtractEntities(types []string, provider Provider, opts ...Option) (*EntitySynapse, error) {
+	if err := checkEntityTypes(types); err != nil {
+		return nil, fmt.Errorf("entity synapse: %w", err)
+	}
+	extraction, err := NewExtraction[EntityResponse]("named entities", provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	extraction.constraints = []string{
+		"entities: one entry per mention, in order of appearance; empty if there are none",
+		"text: the entity exactly as written in the input",
+		"type: exactly one of the entity types: " + strings.Join(types, ", "),
+		"normalized: the entity's canonical name, e.g. without legal suffixes or titles; the text itself if it is already canonical",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining the extraction",
+	}
+
+	synapse := &EntitySynapse{types: append([]string(nil), types...), extraction: extraction}
+	extraction.service.validate = synapse.validateTypes
+	return synapse, nil
+}
+
+// checkEntityTypes rejects an empty list of types and empty or repeated
+// types, compared regardless of case.
+func checkEntityTypes(types []string) error {
+	if len(types) == 0 {
+		return fmt.Errorf("at least one entity type is required")
+	}
+	seen := make(map[string]string, len(types))
+	for _, name := range types {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			return fmt.Errorf("entity type required but empty")
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("entity type %q declared twice, as %q", other, name)
+		}
+		seen[key] = name
+	}
+	return nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+func (e *EntitySynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return e.extraction.GetPipeline()
+}
+
+// WithDefaults creates a new entity extraction with default input values.
+// These are merged with user input at execution time.
+func (e *EntitySynapse) WithDefaults(defaults ExtractionInput) *EntitySynapse {
+	e.extraction.WithDefaults(defaults)
+	return e
+}
+
+// Fire extracts the entities of text.
+func (e *EntitySynapse) Fire(ctx context.Context, session *Session, text string) ([]Entity, error) {
+	response, err := e.FireWithInput(ctx, session, ExtractionInput{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return response.Entities, nil
+}
+
+// FireResult extracts the entities of text and returns them in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (e *EntitySynapse) FireResult(ctx context.Context, session *Session, text string) (Result[[]Entity], error) {
+	result, err := e.execute(ctx, session, ExtractionInput{Text: text})
+	if err != nil {
+		return withValue[EntityResponse, []Entity](result, nil), err
+	}
+	return withValue(result, result.Value.Entities), nil
+}
+
+// FireWithInput executes the extraction with rich input structure.
+func (e *EntitySynapse) FireWithInput(ctx context.Context, session *Session, input ExtractionInput) (EntityResponse, error) {
+	result, err := e.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is an EntityResponse.
+func (e *EntitySynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := e.FireWithInput(ctx, session, ExtractionInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, extracts the entities, and
+// returns them with their types as declared.
+func (e *EntitySynapse) execute(ctx context.Context, session *Session, input ExtractionInput) (Result[EntityResponse], error) {
+	merged := e.extraction.mergeInputs(input)
+	result, err := e.extraction.service.ExecuteResult(ctx, session, e.extraction.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		return withValue(result, EntityResponse{}), err
+	}
+	result.Value = e.canonical(result.Value)
+	return result, nil
+}
+
+// entityType returns the declared type matching name regardless of case and
+// surrounding space, or false if there is none.
+func (e *EntitySynapse) entityType(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, declared := range e.types {
+		if strings.EqualFold(declared, name) {
+			return declared, true
+		}
+	}
+	return "", false
+}
+
+// validateTypes checks that every entity is labeled with a declared type.
+func (e *EntitySynapse) validateTypes(_ *Prompt, response EntityResponse) error {
+	for i, entity := range response.Entities {
+		if _, ok := e.entityType(entity.Type); !ok {
+			return fmt.Errorf("entity %d: unknown type %q, expected one of %s", i, entity.Type, strings.Join(e.types, ", "))
+		}
+	}
+	return nil
+}
+
+// canonical returns a validated response with types as declared and the
+// text standing in for missing normalized forms.
+func (e *EntitySynapse) canonical(response EntityResponse) EntityResponse {
+	for i, entity := range response.Entities {
+		response.Entities[i].Type, _ = e.entityType(entity.Type)
+		if strings.TrimSpace(entity.Normalized) == "" {
+			response.Entities[i].Normalized = entity.Text
+		}
+	}
+	return response
+}
@@ -0,0 +1,207 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// entityTypes are the entity types of the entity extraction tests.
+var entityTypes = []string{"person", "organization", "location"}
+
+func TestEntityResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := EntityResponse{
+			Entities:   []Entity{{Text: "Ada", Type: "person"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"r"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_entities", func(t *testing.T) {
+		r := EntityResponse{
+			Confidence: 0.9,
+			Reasoning:  []string{"r"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected no entities accepted, got error: %v", err)
+		}
+	})
+
+	t.Run("no_text", func(t *testing.T) {
+		r := EntityResponse{
+			Entities:   []Entity{{Text: " ", Type: "person"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"r"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an entity without text")
+		}
+	})
+
+	t.Run("no_type", func(t *testing.T) {
+		r := EntityResponse{
+			Entities:   []Entity{{Text: "Ada"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"r"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an entity without a type")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := EntityResponse{
+			Entities:   []Entity{{Text: "Ada", Type: "person"}},
+			Confidence: 1.5,
+			Reasoning:  []string{"r"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := EntityResponse{
+			Entities:   []Entity{{Text: "Ada", Type: "person"}},
+			Confidence: 0.9,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestExtractEntities_InvalidTypes(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		types []string
+		want  string
+	}{
+		{"none", nil, "at least one entity type"},
+		{"empty", []string{"person", " "}, "entity type required"},
+		{"repeated", []string{"person", "Person "}, `entity type "person" declared twice`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExtractEntities(tt.types, NewMockProvider())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestEntitySynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"entities": [{"text": "Satya Nadella", "type": "Person", "normalized": ""},
+			{"text": "Microsoft Corporation", "type": "organization", "normalized": "Microsoft"}], "confidence": 0.9, "reasoning": ["found the names"]}`, nil
+	})
+	extractor, err := ExtractEntities(entityTypes, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entities, err := extractor.Fire(context.Background(), NewSession(), "Satya Nadella runs Microsoft Corporation.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entities) != 2 {
+		t.Fatalf("expected 2 entities, got %+v", entities)
+	}
+	if entities[0].Type != "person" || entities[0].Normalized != "Satya Nadella" {
+		t.Errorf("expected the declared type and the text as normalized form, got %+v", entities[0])
+	}
+	if entities[1].Normalized != "Microsoft" {
+		t.Errorf("expected the normalized form kept, got %+v", entities[1])
+	}
+	if !strings.Contains(prompt, "entity types: person, organization, location") {
+		t.Errorf("expected the types in the prompt, got %s", prompt)
+	}
+}
+
+func TestEntitySynapse_UnknownType(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"entities": [{"text": "Redmond", "type": "city", "normalized": "Redmond"}], "confidence": 0.9, "reasoning": ["found the names"]}`, nil
+		}
+		return `{"entities": [{"text": "Redmond", "type": "location", "normalized": "Redmond"}], "confidence": 0.9, "reasoning": ["found the names"]}`, nil
+	})
+
+	extractor, _ := ExtractEntities(entityTypes, provider)
+	session := NewSession()
+	_, err := extractor.Fire(context.Background(), session, "Based in Redmond.")
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `unknown type "city"`) {
+		t.Errorf("expected an undeclared type rejected, got %v", err)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected session untouched, got %d messages", session.Len())
+	}
+
+	retrying, _ := ExtractEntities(entityTypes, provider, WithValidationRetry(2))
+	entities, err := retrying.Fire(context.Background(), NewSession(), "Based in Redmond.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entities[0].Type != "location" {
+		t.Errorf("expected location after a retry, got %+v", entities)
+	}
+}
+
+func TestEntitySynapse_MockProvider(t *testing.T) {
+	extractor, _ := ExtractEntities([]string{"organization"}, NewMockProvider())
+
+	response, err := extractor.FireWithInput(context.Background(), NewSession(), ExtractionInput{
+		Text:     "Shares of Acme Corp rose.",
+		Examples: "Globex Inc -> organization",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var texts []string
+	for _, entity := range response.Entities {
+		texts = append(texts, entity.Text)
+	}
+	if strings.Join(texts, "|") != "Shares|Acme Corp" {
+		t.Errorf("expected capitalized runs from the mock, got %q", texts)
+	}
+}
+
+func TestEntitySynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"entities": [{"text": "Ada", "type": "person", "normalized": "Ada Lovelace"}], "confidence": 0.9, "reasoning": ["found the names"]}`)
+	extractor, _ := ExtractEntities(entityTypes, provider)
+	extractor.WithDefaults(ExtractionInput{Context: "history of computing"})
+
+	result, err := extractor.FireResult(context.Background(), NewSession(), "Ada wrote the first program.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value) != 1 || result.Value[0].Normalized != "Ada Lovelace" || result.Usage == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestEntitySynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"entities": [{"text": "Ada", "type": "person", "normalized": "Ada"}], "confidence": 0.9, "reasoning": ["found the names"]}`)
+	extractor, _ := ExtractEntities(entityTypes, provider)
+
+	var synapse Synapse = extractor
+	response, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "Ada wrote the first program."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entities, ok := response.(EntityResponse); !ok || len(entities.Entities) != 1 {
+		t.Errorf("expected an EntityResponse, got %#v", response)
+	}
+	if extractor.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
@@ -0,0 +1,388 @@
+package zyn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for conditions callers may want to handle with errors.Is.
+var (
+	// ErrInvalidPrompt indicates the prompt failed validation before being
+	// sent, typically because required input is missing.
+	ErrInvalidPrompt = errors.New("invalid prompt")
+
+	// ErrParseFailed indicates the provider response could not be decoded
+	// into the synapse's response type.
+	ErrParseFailed = errors.New("failed to parse response")
+
+	// ErrInvalidResponse indicates the decoded response failed validation.
+	ErrInvalidResponse = errors.New("invalid response")
+
+	// ErrRateLimited indicates the provider rejected the call for exceeding
+	// its rate limit.
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrNotRetryable indicates the provider rejected the call in a way that
+	// repeating it cannot fix, such as a malformed request. WithRetry and
+	// WithBackoff stop at the first attempt that fails with it.
+	ErrNotRetryable = errors.New("not retryable")
+
+	// ErrSessionNotFound indicates a SessionStore has no session with the
+	// requested ID.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrNoRoute indicates a router classified the input into a category
+	// with no route, or below its confidence threshold, and has no fallback.
+	ErrNoRoute = errors.New("no route")
+
+	// ErrPromptTooLarge indicates the estimated prompt size exceeds the
+	// configured or advertised token limit. The provider is not called.
+	ErrPromptTooLarge = errors.New("prompt too large")
+
+	// ErrOutputTooLong indicates a transform's output exceeded its maximum
+	// length after any corrective attempts.
+	ErrOutputTooLong = errors.New("output too long")
+
+	// ErrProtectedTokens indicates a transform's output lost or changed
+	// tokens of its input matched by TransformInput.ProtectedPatterns.
+	ErrProtectedTokens = errors.New("protected tokens not preserved")
+
+	// ErrVisionUnsupported indicates a request with attached images was sent
+	// to a provider that does not advertise vision support. The provider is
+	// not called.
+	ErrVisionUnsupported = errors.New("vision unsupported")
+
+	// ErrInsufficientDeadline indicates the context's deadline left less time
+	// than the request's minimum expected latency. The provider is not called.
+	ErrInsufficientDeadline = errors.New("insufficient deadline")
+
+	// ErrNoConsensus indicates the backends of a consensus synapse did not
+	// agree on an answer under its policy.
+	ErrNoConsensus = errors.New("no consensus")
+
+	// ErrToolsUnsupported indicates a request offering tools was sent to a
+	// provider that does not implement ToolProvider. The provider is not
+	// called.
+	ErrToolsUnsupported = errors.New("tools unsupported")
+
+	// ErrToolCalls indicates the model called tools instead of answering.
+	ErrToolCalls = errors.New("tool calls requested")
+
+	// ErrResponseTruncated indicates the provider stopped the response at its
+	// token limit, before the model finished answering.
+	ErrResponseTruncated = errors.New("response truncated")
+
+	// ErrProviderUnavailable indicates the provider could not be reached or
+	// is not ready to serve calls yet, such as a local server that is down
+	// or still loading its model. Retrying later may succeed.
+	ErrProviderUnavailable = errors.New("provider unavailable")
+
+	// ErrProviderClosed indicates a call to a provider after it was closed.
+	ErrProviderClosed = errors.New("provider closed")
+
+	// ErrUnknownEmotion indicates a sentiment response named an emotion
+	// outside the taxonomy set with WithEmotionTaxonomy, in strict mode.
+	ErrUnknownEmotion = errors.New("unknown emotion")
+
+	// ErrUndecided indicates a binary synapse built with WithAbstain answered
+	// unknown: the input did not contain the information needed to decide.
+	ErrUndecided = errors.New("undecided")
+
+	// ErrTooManyItems indicates a list longer than a synapse can process in
+	// one call, rejected before the provider call.
+	ErrTooManyItems = errors.New("too many items")
+)
+
+// PromptTooLargeError reports a prompt rejected before the provider call
+// because its estimated size exceeds the token limit.
+// It matches ErrPromptTooLarge with errors.Is.
+type PromptTooLargeError struct {
+	Estimated int // Estimated prompt tokens, including session history
+	Limit     int // Maximum prompt tokens allowed
+}
+
+// Error implements the error interface.
+func (e *PromptTooLargeError) Error() string {
+	return fmt.Sprintf("%s: estimated %d tokens exceeds limit of %d", ErrPromptTooLarge, e.Estimated, e.Limit)
+}
+
+// Is reports whether target is ErrPromptTooLarge.
+func (*PromptTooLargeError) Is(target error) bool {
+	return target == ErrPromptTooLarge
+}
+
+// InsufficientDeadlineError reports a provider call skipped because too
+// little time remained before the context's deadline.
+// It matches ErrInsufficientDeadline with errors.Is, and ErrNotRetryable
+// since the time left only shrinks on a retry.
+type InsufficientDeadlineError struct {
+	Remaining time.Duration // Time left before the deadline
+	Required  time.Duration // Minimum remaining time configured for a call
+}
+
+// Error implements the error interface.
+func (e *InsufficientDeadlineError) Error() string {
+	return fmt.Sprintf("%s: %v remaining, %v required", ErrInsufficientDeadline, e.Remaining, e.Required)
+}
+
+// Is reports whether target is ErrInsufficientDeadline or ErrNotRetryable.
+func (*InsufficientDeadlineError) Is(target error) bool {
+	return target == ErrInsufficientDeadline || target == ErrNotRetryable
+}
+
+// OutputTooLongError reports output longer than the requested maximum length.
+// It matches ErrOutputTooLong with errors.Is.
+type OutputTooLongError struct {
+	Length int        // Measured output length
+	Limit  int        // Maximum length allowed
+	Unit   LengthUnit // Unit of Length and Limit
+}
+
+// Error implements the error interface.
+func (e *OutputTooLongError) Error() string {
+	return fmt.Sprintf("%s: %d %s exceeds limit of %d", ErrOutputTooLong, e.Length, e.Unit, e.Limit)
+}
+
+// Is reports whether target is ErrOutputTooLong.
+func (*OutputTooLongError) Is(target error) bool {
+	return target == ErrOutputTooLong
+}
+
+// ProtectedTokensError reports protected tokens of the input that the output
+// does not repeat verbatim.
+// It matches ErrProtectedTokens with errors.Is.
+type ProtectedTokensError struct {
+	Missing []string // Tokens appearing fewer times in the output than in the input
+}
+
+// Error implements the error interface.
+func (e *ProtectedTokensError) Error() string {
+	quoted := make([]string, len(e.Missing))
+	for i, token := range e.Missing {
+		quoted[i] = fmt.Sprintf("%q", token)
+	}
+	return fmt.Sprintf("%s: missing %s", ErrProtectedTokens, strings.Join(quoted, ", "))
+}
+
+// Is reports whether target is ErrProtectedTokens.
+func (*ProtectedTokensError) Is(target error) bool {
+	return target == ErrProtectedTokens
+}
+
+// VisionUnsupportedError reports a request with attached images rejected
+// before the provider call because the provider cannot accept images.
+// It matches ErrVisionUnsupported with errors.Is.
+type VisionUnsupportedError struct {
+	Provider string // Name of the provider
+	Images   int    // Number of images attached to the request
+}
+
+// Error implements the error interface.
+func (e *VisionUnsupportedError) Error() string {
+	return fmt.Sprintf("%s: provider %q cannot accept %d attached images", ErrVisionUnsupported, e.Provider, e.Images)
+}
+
+// Is reports whether target is ErrVisionUnsupported.
+func (*VisionUnsupportedError) Is(target error) bool {
+	return target == ErrVisionUnsupported
+}
+
+// ToolsUnsupportedError reports a request offering tools rejected before the
+// provider call because the provider cannot pass them on.
+// It matches ErrToolsUnsupported with errors.Is.
+type ToolsUnsupportedError struct {
+	Provider string // Name of the provider
+	Tools    int    // Number of tools offered
+}
+
+// Error implements the error interface.
+func (e *ToolsUnsupportedError) Error() string {
+	return fmt.Sprintf("%s: provider %q cannot offer %d tools", ErrToolsUnsupported, e.Provider, e.Tools)
+}
+
+// Is reports whether target is ErrToolsUnsupported.
+func (*ToolsUnsupportedError) Is(target error) bool {
+	return target == ErrToolsUnsupported
+}
+
+// ToolCallsError reports a response in which the model called tools instead
+// of answering. The session holds the calls; answer each with
+// Session.AppendToolResult and fire again.
+// It matches ErrToolCalls with errors.Is.
+type ToolCallsError struct {
+	Calls []ToolCall // Calls requested by the model, in order
+}
+
+// Error implements the error interface.
+func (e *ToolCallsError) Error() string {
+	names := make([]string, len(e.Calls))
+	for i, call := range e.Calls {
+		names[i] = call.Name
+	}
+	return fmt.Sprintf("%s: %s", ErrToolCalls, strings.Join(names, ", "))
+}
+
+// Is reports whether target is ErrToolCalls.
+func (*ToolCallsError) Is(target error) bool {
+	return target == ErrToolCalls
+}
+
+// ProviderUnavailableError reports a call that failed because the provider
+// could not be reached or was not ready. It is retryable: WithRetry and
+// WithBackoff make further attempts.
+// It matches ErrProviderUnavailable with errors.Is and unwraps to the cause.
+type ProviderUnavailableError struct {
+	Provider   string        // Name of the provider
+	RetryAfter time.Duration // Provider's estimate of when it will be ready; 0 when unknown
+	Err        error         // Cause, such as a connection error
+}
+
+// Error implements the error interface.
+func (e *ProviderUnavailableError) Error() string {
+	message := fmt.Sprintf("%s: provider %q: %v", ErrProviderUnavailable, e.Provider, e.Err)
+	if e.RetryAfter > 0 {
+		message += fmt.Sprintf(" (retry after %v)", e.RetryAfter)
+	}
+	return message
+}
+
+// Is reports whether target is ErrProviderUnavailable.
+func (*ProviderUnavailableError) Is(target error) bool {
+	return target == ErrProviderUnavailable
+}
+
+// Unwrap returns the cause.
+func (e *ProviderUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// ProviderClosedError reports a call to a provider after its Close. It is
+// not retryable: the provider stays closed.
+// It matches ErrProviderClosed and ErrNotRetryable with errors.Is.
+type ProviderClosedError struct {
+	Provider string // Name of the provider
+}
+
+// Error implements the error interface.
+func (e *ProviderClosedError) Error() string {
+	return fmt.Sprintf("%s: provider %q", ErrProviderClosed, e.Provider)
+}
+
+// Is reports whether target is ErrProviderClosed or ErrNotRetryable.
+func (*ProviderClosedError) Is(target error) bool {
+	return target == ErrProviderClosed || target == ErrNotRetryable
+}
+
+// UnknownEmotionError reports an emotion outside the configured taxonomy.
+// The response is rejected as invalid, so a fresh call may succeed.
+// It matches ErrUnknownEmotion with errors.Is.
+type UnknownEmotionError struct {
+	Emotion string   // Emotion as returned by the provider
+	Allowed []string // Emotions in the taxonomy
+}
+
+// Error implements the error interface.
+func (e *UnknownEmotionError) Error() string {
+	return fmt.Sprintf("%s: %q is not one of %s", ErrUnknownEmotion, e.Emotion, strings.Join(e.Allowed, ", "))
+}
+
+// Is reports whether target is ErrUnknownEmotion.
+func (*UnknownEmotionError) Is(target error) bool {
+	return target == ErrUnknownEmotion
+}
+
+// NoConsensusError reports a consensus call whose backends did not agree
+// often enough, or tied under a policy that fails on ties.
+// It matches ErrNoConsensus with errors.Is.
+type NoConsensusError struct {
+	Agreed   int  // Backends that gave the leading answer; zero on a tie
+	Required int  // Agreeing backends required by the policy
+	Backends int  // Backends called
+	Failed   int  // Backends whose call failed
+	Tied     bool // Whether the leading answers tied
+}
+
+// Error implements the error interface.
+func (e *NoConsensusError) Error() string {
+	if e.Tied {
+		return fmt.Sprintf("%s: answers tied across %d backends (%d failed)", ErrNoConsensus, e.Backends, e.Failed)
+	}
+	return fmt.Sprintf("%s: %d of %d backends agreed, %d required (%d failed)", ErrNoConsensus, e.Agreed, e.Backends, e.Required, e.Failed)
+}
+
+// Is reports whether target is ErrNoConsensus.
+func (*NoConsensusError) Is(target error) bool {
+	return target == ErrNoConsensus
+}
+
+// ResponseTruncatedError reports a response the provider cut off at its token
+// limit, which would otherwise fail to parse. Raise the limit set with
+// WithMaxTokens, or shorten the expected output.
+// It matches ErrResponseTruncated with errors.Is, and ErrNotRetryable since
+// repeating the call with the same limit is expected to truncate again.
+type ResponseTruncatedError struct {
+	Provider         string // Name of the provider
+	MaxTokens        int    // Completion token limit set with WithMaxTokens; zero for the provider's own limit
+	CompletionTokens int    // Completion tokens the provider reported
+}
+
+// Error implements the error interface.
+func (e *ResponseTruncatedError) Error() string {
+	if e.MaxTokens == 0 {
+		return fmt.Sprintf("%s: provider %q stopped after %d completion tokens", ErrResponseTruncated, e.Provider, e.CompletionTokens)
+	}
+	return fmt.Sprintf("%s: provider %q stopped after %d completion tokens (max tokens %d)", ErrResponseTruncated, e.Provider, e.CompletionTokens, e.MaxTokens)
+}
+
+// Is reports whether target is ErrResponseTruncated or ErrNotRetryable.
+func (*ResponseTruncatedError) Is(target error) bool {
+	return target == ErrResponseTruncated || target == ErrNotRetryable
+}
+
+// TooManyItemsError reports a list rejected before the provider call because
+// it has more items than the synapse accepts in one call. Split the list into
+// chunks of at most Limit items.
+// It matches ErrTooManyItems with errors.Is.
+type TooManyItemsError struct {
+	Synapse string // Type of the synapse, e.g. "dedupe"
+	Items   int    // Number of items given
+	Limit   int    // Maximum items accepted in one call
+}
+
+// Error implements the error interface.
+func (e *TooManyItemsError) Error() string {
+	return fmt.Sprintf("%s: %s synapse got %d items, limit is %d; split the list into chunks of at most %d",
+		ErrTooManyItems, e.Synapse, e.Items, e.Limit, e.Limit)
+}
+
+// Is reports whether target is ErrTooManyItems.
+func (*TooManyItemsError) Is(target error) bool {
+	return target == ErrTooManyItems
+}
+
+// ValidationError reports a response that was parsed but failed validation,
+// by its type's Validate method or the synapse's own checks. Value holds the
+// parsed value, so a caller can correct it instead of calling again; the
+// session is left untouched either way.
+// It matches ErrInvalidResponse with errors.Is and unwraps to the cause.
+type ValidationError[T any] struct {
+	Value T     // Parsed value that failed validation
+	Err   error // Validation failure
+}
+
+// Error implements the error interface.
+func (e *ValidationError[T]) Error() string {
+	return fmt.Sprintf("%s: %v", ErrInvalidResponse, e.Err)
+}
+
+// Is reports whether target is ErrInvalidResponse.
+func (*ValidationError[T]) Is(target error) bool {
+	return target == ErrInvalidResponse
+}
+
+// Unwrap returns the cause.
+func (e *ValidationError[T]) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,253 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromptTooLargeError(t *testing.T) {
+	err := &PromptTooLargeError{Estimated: 1200, Limit: 1000}
+
+	expected := "prompt too large: estimated 1200 tokens exceeds limit of 1000"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("call failed: %w", err)
+	if !errors.Is(wrapped, ErrPromptTooLarge) {
+		t.Error("expected wrapped error to match ErrPromptTooLarge")
+	}
+
+	var target *PromptTooLargeError
+	if !errors.As(wrapped, &target) || target.Estimated != 1200 {
+		t.Errorf("expected errors.As to recover estimate, got %+v", target)
+	}
+}
+
+func TestInsufficientDeadlineError(t *testing.T) {
+	err := &InsufficientDeadlineError{Remaining: 50 * time.Millisecond, Required: 800 * time.Millisecond}
+
+	expected := "insufficient deadline: 50ms remaining, 800ms required"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(fmt.Errorf("call failed: %w", err), ErrInsufficientDeadline) {
+		t.Error("expected wrapped error to match ErrInsufficientDeadline")
+	}
+}
+
+func TestOutputTooLongError(t *testing.T) {
+	err := &OutputTooLongError{Length: 172, Limit: 160, Unit: LengthCharacters}
+
+	expected := "output too long: 172 characters exceeds limit of 160"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("transform failed: %w", err)
+	if !errors.Is(wrapped, ErrOutputTooLong) {
+		t.Error("expected wrapped error to match ErrOutputTooLong")
+	}
+}
+
+func TestProtectedTokensError(t *testing.T) {
+	err := &ProtectedTokensError{Missing: []string{"{{first_name}}", "%s"}}
+
+	expected := `protected tokens not preserved: missing "{{first_name}}", "%s"`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("transform failed: %w", err)
+	if !errors.Is(wrapped, ErrProtectedTokens) {
+		t.Error("expected wrapped error to match ErrProtectedTokens")
+	}
+}
+
+func TestVisionUnsupportedError(t *testing.T) {
+	err := &VisionUnsupportedError{Provider: "openai", Images: 2}
+
+	expected := `vision unsupported: provider "openai" cannot accept 2 attached images`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("binary synapse execution failed: %w", err)
+	if !errors.Is(wrapped, ErrVisionUnsupported) {
+		t.Error("expected wrapped error to match ErrVisionUnsupported")
+	}
+}
+
+func TestToolsUnsupportedError(t *testing.T) {
+	err := &ToolsUnsupportedError{Provider: "anthropic", Tools: 2}
+
+	expected := `tools unsupported: provider "anthropic" cannot offer 2 tools`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrToolsUnsupported) {
+		t.Error("expected wrapped error to match ErrToolsUnsupported")
+	}
+}
+
+func TestToolCallsError(t *testing.T) {
+	err := &ToolCallsError{Calls: []ToolCall{{Name: "lookup_order"}, {Name: "cancel_order"}}}
+
+	expected := "tool calls requested: lookup_order, cancel_order"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrToolCalls) {
+		t.Error("expected wrapped error to match ErrToolCalls")
+	}
+}
+
+func TestResponseTruncatedError(t *testing.T) {
+	err := &ResponseTruncatedError{Provider: "openai", MaxTokens: 64, CompletionTokens: 64}
+	expected := `response truncated: provider "openai" stopped after 64 completion tokens (max tokens 64)`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	unlimited := &ResponseTruncatedError{Provider: "openai", CompletionTokens: 4096}
+	expected = `response truncated: provider "openai" stopped after 4096 completion tokens`
+	if unlimited.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, unlimited.Error())
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !errors.Is(wrapped, ErrResponseTruncated) || !errors.Is(wrapped, ErrNotRetryable) {
+		t.Error("expected wrapped error to match ErrResponseTruncated and ErrNotRetryable")
+	}
+}
+
+func TestTooManyItemsError(t *testing.T) {
+	err := &TooManyItemsError{Synapse: "dedupe", Items: 250, Limit: 100}
+	expected := "too many items: dedupe synapse got 250 items, limit is 100; split the list into chunks of at most 100"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+	if !errors.Is(fmt.Errorf("wrapped: %w", err), ErrTooManyItems) {
+		t.Error("expected wrapped error to match ErrTooManyItems")
+	}
+}
+
+func TestProviderUnavailableError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := &ProviderUnavailableError{Provider: "llamacpp", Err: cause}
+	expected := `provider unavailable: provider "llamacpp": connection refused`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	loading := &ProviderUnavailableError{Provider: "huggingface", RetryAfter: 20 * time.Second, Err: cause}
+	expected = `provider unavailable: provider "huggingface": connection refused (retry after 20s)`
+	if loading.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, loading.Error())
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !errors.Is(wrapped, ErrProviderUnavailable) || !errors.Is(wrapped, cause) || errors.Is(wrapped, ErrNotRetryable) {
+		t.Error("expected wrapped error to match ErrProviderUnavailable and its cause only")
+	}
+}
+
+func TestProviderClosedError(t *testing.T) {
+	err := &ProviderClosedError{Provider: "openai"}
+	expected := `provider closed: provider "openai"`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	if !errors.Is(wrapped, ErrProviderClosed) || !errors.Is(wrapped, ErrNotRetryable) {
+		t.Error("expected wrapped error to match ErrProviderClosed and ErrNotRetryable")
+	}
+}
+
+func TestNoConsensusError(t *testing.T) {
+	err := &NoConsensusError{Agreed: 1, Required: 2, Backends: 3, Failed: 1}
+	expected := "no consensus: 1 of 3 backends agreed, 2 required (1 failed)"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	tied := &NoConsensusError{Backends: 2, Tied: true}
+	expected = "no consensus: answers tied across 2 backends (0 failed)"
+	if tied.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, tied.Error())
+	}
+	if !errors.Is(tied, ErrNoConsensus) {
+		t.Error("expected error to match ErrNoConsensus")
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		response string
+		target   error
+		message  string
+	}{
+		{
+			name:     "invalid prompt",
+			input:    "",
+			response: `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`,
+			target:   ErrInvalidPrompt,
+			message:  "invalid prompt: ",
+		},
+		{
+			name:     "parse failure",
+			input:    "x",
+			response: "not json",
+			target:   ErrParseFailed,
+			message:  "failed to parse response: ",
+		},
+		{
+			name:     "invalid response",
+			input:    "x",
+			response: `{"decision": true, "confidence": 2, "reasoning": ["ok"]}`,
+			target:   ErrInvalidResponse,
+			message:  "invalid response: confidence must be 0-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, err := Binary("question", NewMockProviderWithResponse(tt.response))
+			if err != nil {
+				t.Fatalf("failed to create synapse: %v", err)
+			}
+
+			_, err = synapse.Fire(context.Background(), NewSession(), tt.input)
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("expected %v, got %v", tt.target, err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.message) {
+				t.Errorf("expected message starting with %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	cause := errors.New("email required but empty")
+	err := &ValidationError[spanPerson]{Value: spanPerson{Name: "Ada"}, Err: cause}
+	if expected := "invalid response: email required but empty"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	wrapped := fmt.Errorf("conversion failed: %w", err)
+	if !errors.Is(wrapped, ErrInvalidResponse) || !errors.Is(wrapped, cause) {
+		t.Error("expected wrapped error to match ErrInvalidResponse and its cause")
+	}
+	var invalid *ValidationError[spanPerson]
+	if !errors.As(wrapped, &invalid) || invalid.Value.Name != "Ada" {
+		t.Errorf("expected the value from the wrapped error, got %+v", invalid)
+	}
+}
@@ -0,0 +1,85 @@
+// Package main demonstrates scoring a system configuration with an Analyze
+// synapse.
+//
+// Setting AnalyzeInput.Scored asks for a 0 to 100 score and its basis
+// alongside the prose analysis, so a production-readiness check can gate on
+// a number while people read the findings. FireScore is the shortcut when
+// only the number is needed.
+//
+// The example uses a mock provider so it runs without an API key; swap in
+// any zyn.Provider to call a real model.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zoobzio/zyn"
+)
+
+// ServerConfig is the configuration under review.
+type ServerConfig struct {
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	TLS            bool   `json:"tls"`
+	MaxConnections int    `json:"max_connections"`
+	DebugLogging   bool   `json:"debug_logging"`
+}
+
+// readinessThreshold is the lowest score allowed to ship.
+const readinessThreshold = 80
+
+func main() {
+	provider := zyn.NewMockProviderWithResponse(`{
+		"analysis": "The server is reachable and sized sensibly, but serves plain HTTP and logs at debug level.",
+		"confidence": 0.85,
+		"findings": [
+			{"title": "TLS is disabled", "severity": "critical", "area": "transport", "field": "tls", "description": "traffic, including credentials, is sent in plain text", "recommendation": "terminate TLS at the server or a proxy"},
+			{"title": "Debug logging is on", "severity": "warning", "area": "operations", "field": "debug_logging", "description": "debug logs are verbose and may include request bodies", "recommendation": "log at info in production"}
+		],
+		"score": 55,
+		"score_basis": "production readiness; 0 is unusable, 100 is ready to ship with no changes",
+		"reasoning": ["checked transport security", "checked capacity", "checked operational settings"]
+	}`)
+
+	analyzer, err := zyn.Analyze[ServerConfig]("server configuration", provider,
+		zyn.WithRetry(3),
+		zyn.WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		log.Fatalf("failed to create analyze synapse: %v", err)
+	}
+
+	ctx := context.Background()
+	session := zyn.NewSession()
+	config := ServerConfig{Host: "0.0.0.0", Port: 8080, MaxConnections: 500, DebugLogging: true}
+
+	response, err := analyzer.FireWithInputDetails(ctx, session, zyn.AnalyzeInput[ServerConfig]{
+		Data:   config,
+		Focus:  "production readiness",
+		Scored: true,
+	})
+	if err != nil {
+		log.Fatalf("analysis failed: %v", err)
+	}
+
+	fmt.Printf("Analysis: %s\n", response.Analysis)
+	fmt.Printf("Score:    %.0f (%s)\n", response.Score, response.ScoreBasis)
+	for _, finding := range response.Findings {
+		fmt.Printf("Finding:  [%s] %s (%s)\n", finding.Severity, finding.Title, finding.Field)
+		fmt.Printf("          %s; fix: %s\n", finding.Description, finding.Recommendation)
+	}
+
+	// Only the number, e.g. for a deploy gate
+	score, err := analyzer.FireScore(ctx, session, zyn.AnalyzeInput[ServerConfig]{
+		Data:  config,
+		Focus: "production readiness",
+	})
+	if err != nil {
+		log.Fatalf("scoring failed: %v", err)
+	}
+	fmt.Printf("Ready:    %t (score %.0f, threshold %d)\n", score >= readinessThreshold, score, readinessThreshold)
+	fmt.Printf("Session:  %d messages\n", session.Len())
+}
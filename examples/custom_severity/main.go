@@ -0,0 +1,158 @@
+// Package main demonstrates authoring a custom synapse type on top of zyn.Base.
+//
+// A custom synapse supplies three things:
+//   - an input type and a response type implementing zyn.Validator
+//   - a BuildPrompt function turning an input into a *zyn.Prompt
+//   - optionally, a Validate function for checks that depend on the synapse's
+//     configuration rather than the response alone
+//
+// Everything else (options, schema generation, prompt validation, parsing,
+// transactional session updates, and hook emission) is handled by zyn.Base,
+// so custom synapses pick up fixes to that machinery automatically.
+//
+// The example uses a mock provider so it runs without an API key; swap in
+// any zyn.Provider to call a real model.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zoobzio/pipz"
+	"github.com/zoobzio/zyn"
+)
+
+// SeverityInput is the input for a severity synapse.
+type SeverityInput struct {
+	Incident    string  // Incident description
+	Context     string  // Optional system or business context
+	Temperature float32 // LLM temperature setting for this request
+}
+
+// SeverityResponse is the response from a severity synapse.
+type SeverityResponse struct {
+	Level      string   `json:"level"`      // One of the synapse's levels
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Impact     []string `json:"impact"`     // Affected users or systems
+	Reasoning  []string `json:"reasoning"`  // Explanation of the rating
+}
+
+// Validate checks the response on its own terms.
+func (r SeverityResponse) Validate() error {
+	if r.Level == "" {
+		return fmt.Errorf("level required but empty")
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// SeveritySynapse rates incidents against a fixed scale of levels.
+type SeveritySynapse struct {
+	levels []string
+	base   *zyn.Base[SeverityInput, SeverityResponse]
+}
+
+// NewSeverity creates a severity synapse. Levels are ordered from least to
+// most severe.
+func NewSeverity(levels []string, provider zyn.Provider, opts ...zyn.Option) (*SeveritySynapse, error) {
+	s := &SeveritySynapse{levels: levels}
+
+	base, err := zyn.NewSynapse(zyn.SynapseConfig[SeverityInput, SeverityResponse]{
+		Type:        "severity",
+		Temperature: zyn.DefaultTemperatureAnalytical,
+		BuildPrompt: s.buildPrompt,
+		Validate:    s.validate,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.base = base
+	return s, nil
+}
+
+// GetPipeline exposes the pipeline so the synapse works with WithFallback.
+func (s *SeveritySynapse) GetPipeline() pipz.Chainable[*zyn.SynapseRequest] {
+	return s.base.GetPipeline()
+}
+
+// Fire rates an incident and returns its level.
+func (s *SeveritySynapse) Fire(ctx context.Context, session *zyn.Session, incident string) (string, error) {
+	response, err := s.FireWithInput(ctx, session, SeverityInput{Incident: incident})
+	if err != nil {
+		return "", err
+	}
+	return response.Level, nil
+}
+
+// FireWithInput rates an incident and returns the full response.
+func (s *SeveritySynapse) FireWithInput(ctx context.Context, session *zyn.Session, input SeverityInput) (SeverityResponse, error) {
+	return s.base.Execute(ctx, session, input, input.Temperature)
+}
+
+// buildPrompt renders the prompt. The schema is filled in by zyn.Base.
+func (s *SeveritySynapse) buildPrompt(input SeverityInput) *zyn.Prompt {
+	return &zyn.Prompt{
+		Task:       "Rate the severity of this incident",
+		Input:      input.Incident,
+		Context:    input.Context,
+		Categories: s.levels,
+		Constraints: []string{
+			"level: required, from categories list (ordered least to most severe)",
+			"confidence: 0.0 to 1.0",
+			"impact: affected users or systems",
+			"reasoning: ordered steps explaining the rating",
+		},
+	}
+}
+
+// validate rejects levels outside the synapse's scale.
+func (s *SeveritySynapse) validate(response SeverityResponse) error {
+	if !slices.Contains(s.levels, strings.ToLower(response.Level)) {
+		return fmt.Errorf("level %q not in %s", response.Level, strings.Join(s.levels, ", "))
+	}
+	return nil
+}
+
+func main() {
+	provider := zyn.NewMockProviderWithResponse(`{
+		"level": "critical",
+		"confidence": 0.92,
+		"impact": ["all customers", "checkout service"],
+		"reasoning": ["checkout is fully down", "revenue is directly affected"]
+	}`)
+
+	severity, err := NewSeverity([]string{"low", "medium", "high", "critical"}, provider,
+		zyn.WithRetry(3),
+		zyn.WithTimeout(10*time.Second),
+	)
+	if err != nil {
+		log.Fatalf("failed to create severity synapse: %v", err)
+	}
+
+	ctx := context.Background()
+	session := zyn.NewSession()
+
+	response, err := severity.FireWithInput(ctx, session, SeverityInput{
+		Incident: "Checkout returns HTTP 500 for every request since the 14:02 deploy",
+		Context:  "E-commerce platform, peak shopping hours",
+	})
+	if err != nil {
+		log.Fatalf("severity failed: %v", err)
+	}
+
+	fmt.Printf("Level:      %s\n", response.Level)
+	fmt.Printf("Confidence: %.2f\n", response.Confidence)
+	fmt.Printf("Impact:     %s\n", strings.Join(response.Impact, ", "))
+	fmt.Printf("Reasoning:  %s\n", strings.Join(response.Reasoning, "; "))
+	fmt.Printf("Session:    %d messages\n", session.Len())
+}
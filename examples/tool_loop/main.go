@@ -0,0 +1,119 @@
+// Package main demonstrates a tool invocation loop gated by a Binary synapse.
+//
+// The synapse is offered a tool with zyn.WithTools. Each Fire either answers
+// the question or fails with a *zyn.ToolCallsError listing the tools the model
+// wants called. The loop runs those tools, adds their results to the session
+// with Session.AppendToolResult, and fires again until the model answers.
+//
+// The example uses a scripted provider so it runs without an API key; swap in
+// openai.New, which implements zyn.ToolProvider, to call a real model.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/zoobzio/zyn"
+)
+
+// maxToolRounds bounds the tool calls made before giving up.
+const maxToolRounds = 5
+
+// orders is the data behind the lookup_order tool.
+var orders = map[string]string{
+	"A-1042": `{"status": "shipped", "carrier": "DHL", "shipped_at": "2026-10-14"}`,
+}
+
+// lookupOrder describes the lookup_order tool to the model.
+var lookupOrder = zyn.Tool{
+	Name:        "lookup_order",
+	Description: "Look up an order's fulfillment status by order ID",
+	Parameters:  json.RawMessage(`{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`),
+}
+
+// runTool executes a tool call and returns its result for the model.
+func runTool(call zyn.ToolCall) string {
+	if call.Name != lookupOrder.Name {
+		return fmt.Sprintf(`{"error": "unknown tool %q"}`, call.Name)
+	}
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return `{"error": "invalid arguments"}`
+	}
+	if order, ok := orders[args.ID]; ok {
+		return order
+	}
+	return `{"error": "order not found"}`
+}
+
+// scriptedProvider stands in for a model: it looks the order up first, then
+// answers once the tool result is in the conversation.
+type scriptedProvider struct{}
+
+func (scriptedProvider) Name() string { return "scripted" }
+
+func (p scriptedProvider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	return p.CallWithTools(ctx, messages, temperature, nil)
+}
+
+func (scriptedProvider) CallWithTools(_ context.Context, messages []zyn.Message, _ float32, tools []zyn.Tool) (*zyn.ProviderResponse, error) {
+	usage := zyn.TokenUsage{Prompt: 120, Completion: 30, Total: 150}
+	for _, msg := range messages {
+		if msg.Role == zyn.RoleTool {
+			return &zyn.ProviderResponse{
+				Content: `{"decision": true, "confidence": 0.97, "reasoning": ["lookup_order reports the order shipped with DHL on 2026-10-14"]}`,
+				Usage:   usage,
+			}, nil
+		}
+	}
+	if len(tools) == 0 {
+		return nil, errors.New("no tools offered")
+	}
+	return &zyn.ProviderResponse{
+		ToolCalls: []zyn.ToolCall{{ID: "call-1", Name: "lookup_order", Arguments: json.RawMessage(`{"id": "A-1042"}`)}},
+		Usage:     usage,
+	}, nil
+}
+
+func main() {
+	shipped, err := zyn.Binary("the order has shipped", scriptedProvider{}, zyn.WithTools(lookupOrder))
+	if err != nil {
+		log.Fatalf("failed to create binary synapse: %v", err)
+	}
+
+	ctx := context.Background()
+	session := zyn.NewSession()
+
+	for round := 1; ; round++ {
+		response, err := shipped.FireWithInput(ctx, session, zyn.BinaryInput{
+			Subject: "Customer asks: has order A-1042 shipped yet?",
+		})
+
+		var calls *zyn.ToolCallsError
+		if errors.As(err, &calls) {
+			if round == maxToolRounds {
+				log.Fatalf("no answer after %d tool rounds", maxToolRounds)
+			}
+			for _, call := range calls.Calls {
+				result := runTool(call)
+				fmt.Printf("Tool:       %s(%s) -> %s\n", call.Name, call.Arguments, result)
+				session.AppendToolResult(call.ID, result)
+			}
+			continue
+		}
+		if err != nil {
+			log.Fatalf("binary failed: %v", err)
+		}
+
+		fmt.Printf("Shipped:    %t\n", response.Decision)
+		fmt.Printf("Confidence: %.2f\n", response.Confidence)
+		fmt.Printf("Reasoning:  %s\n", response.Reasoning[0])
+		fmt.Printf("Session:    %d messages over %d rounds\n", session.Len(), round)
+		return
+	}
+}
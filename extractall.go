@@ -0,0 +1,315 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// extractAllItemDef is the definition name the element type is moved to
+// when it refers to itself, since "#" then names the array wrapper.
+const extractAllItemDef = "Item"
+
+// requireItemsConstraint is added to extract-all prompts by WithRequireItems.
+const requireItemsConstraint = "items: at least one; the text is known to contain some"
+
+// requireItemsID identifies the require items option.
+var requireItemsID = pipz.NewIdentity("zyn:require-items", "Rejects empty extract-all results")
+
+// ExtractAllResponse contains the response from an extract-all synapse.
+type ExtractAllResponse[T any] struct {
+	Items      []T      `json:"items"`      // Every record found, in order of appearance
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of what was extracted
+}
+
+// Validate checks if the response is valid. Items that implement Validator
+// are validated one by one, and errors name the item by index.
+func (r ExtractAllResponse[T]) Validate() error {
+	for i, item := range r.Items {
+		if err := validateValue(item); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// extractAllSettings holds the settings of one extract-all call.
+type extractAllSettings struct {
+	requireItems bool // Reject empty results, with WithRequireItems
+}
+
+// WithRequireItems makes extract-all synapses reject responses with no
+// items, for text known to contain at least one record. Without it an empty
+// result is a valid answer.
+// The option has no effect on other synapse types.
+func WithRequireItems() Option {
+	return withSettings(requireItemsID, func(req *SynapseRequest, settings *extractAllSettings) {
+		if settings.requireItems {
+			return
+		}
+		settings.requireItems = true
+		req.Prompt.Constraints = append(slices.Clone(req.Prompt.Constraints), requireItemsConstraint)
+	})
+}
+
+// arraySchema wraps element, the schema of one record, in the schema of an
+// ExtractAllResponse. The element's definitions move to the wrapper, and an
+// element that refers to itself with "#" becomes a definition of its own.
+func arraySchema(element string) (string, error) {
+	var item JSONSchema
+	if err := json.Unmarshal([]byte(element), &item); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	defs := item.Defs
+	item.Defs = nil
+
+	items := &item
+	if refersToRoot(&item) {
+		name := extractAllItemDef
+		for i := 2; defs[name] != nil; i++ {
+			name = fmt.Sprintf("%s%d", extractAllItemDef, i)
+		}
+		ref := "#/$defs/" + name
+		rerootRefs(&item, ref)
+		for _, def := range defs {
+			rerootRefs(def, ref)
+		}
+		if defs == nil {
+			defs = make(map[string]*JSONSchema)
+		}
+		defs[name] = &item
+		items = &JSONSchema{Ref: ref}
+	}
+
+	wrapper := &JSONSchema{
+		Type: jsonTypeObject,
+		Properties: map[string]*JSONSchema{
+			"items":      {Type: jsonTypeArray, Items: items},
+			"confidence": {Type: jsonTypeNumber},
+			"reasoning":  {Type: jsonTypeArray, Items: &JSONSchema{Type: jsonTypeString}},
+		},
+		Required:                []string{"items", "confidence", "reasoning"},
+		DisallowAdditionalProps: true,
+		Defs:                    defs,
+	}
+
+	jsonBytes, err := json.MarshalIndent(wrapper, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// refersToRoot reports whether schema or a schema nested in it is "#".
+func refersToRoot(schema *JSONSchema) bool {
+	if schema == nil {
+		return false
+	}
+	if schema.Ref == "#" {
+		return true
+	}
+	for _, property := range schema.Properties {
+		if refersToRoot(property) {
+			return true
+		}
+	}
+	return refersToRoot(schema.Items) || refersToRoot(schema.AdditionalProperties)
+}
+
+// rerootRefs replaces "#" with ref in schema and the schemas nested in it.
+func rerootRefs(schema *JSONSchema, ref string) {
+	if schema == nil {
+		return
+	}
+	if schema.Ref == "#" {
+		schema.Ref = ref
+	}
+	for _, property := range schema.Properties {
+		rerootRefs(property, ref)
+	}
+	rerootRefs(schema.Items, ref)
+	rerootRefs(schema.AdditionalProperties, ref)
+}
+
+// ExtractAllSynapse extracts every record of type T from unstructured text.
+type ExtractAllSynapse[T any] struct {
+	what     string
+	schema   string // Pre-computed JSON schema of the response
+	defaults ExtractionInput
+	service  *Service[ExtractAllResponse[T]]
+}
+
+// ExtractAll creates a new extract-all synapse bound to a provider.
+// The type parameter T defines one record; records that implement Validator
+// are validated individually. Empty results are valid unless
+// WithRequireItems is given.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	lines, err := ExtractAll[LineItem]("invoice line items", provider, WithRequireItems())
+//	items, err := lines.Fire(ctx, session, email)
+//	// items: [{Description: "Widgets", Amount: 20}, {Description: "Shipping", Amount: 5}]
+func ExtractAll[T any](what string, provider Provider, opts ...Option) (*ExtractAllSynapse[T], error) {
+	// Generate schema once at construction, wrapping the record schema
+	element, err := generateJSONSchema[T]()
+	if err != nil {
+		return nil, fmt.Errorf("extract all synapse: %w", err)
+	}
+	schema, err := arraySchema(element)
+	if err != nil {
+		return nil, fmt.Errorf("extract all synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[ExtractAllResponse[T]](pipeline, "extract_all", provider, DefaultTemperatureDeterministic)
+
+	return &ExtractAllSynapse[T]{
+		what:    what,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+func (e *ExtractAllSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return e.service.GetPipeline()
+}
+
+// WithDefaults creates a new ExtractAll with default input values.
+// These are merged with user input at execution time.
+func (e *ExtractAllSynapse[T]) WithDefaults(defaults ExtractionInput) *ExtractAllSynapse[T] {
+	e.defaults = defaults
+	return e
+}
+
+// Fire extracts every record from text.
+func (e *ExtractAllSynapse[T]) Fire(ctx context.Context, session *Session, text string) ([]T, error) {
+	response, err := e.FireWithInput(ctx, session, ExtractionInput{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return response.Items, nil
+}
+
+// FireWithDetails extracts every record from text and returns the full
+// response, including confidence and reasoning.
+func (e *ExtractAllSynapse[T]) FireWithDetails(ctx context.Context, session *Session, text string) (ExtractAllResponse[T], error) {
+	return e.FireWithInput(ctx, session, ExtractionInput{Text: text})
+}
+
+// FireResult extracts every record from text and returns the records in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (e *ExtractAllSynapse[T]) FireResult(ctx context.Context, session *Session, text string) (Result[[]T], error) {
+	result, err := e.execute(ctx, session, ExtractionInput{Text: text})
+	if err != nil {
+		return withValue[ExtractAllResponse[T], []T](result, nil), err
+	}
+	return withValue(result, result.Value.Items), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (e *ExtractAllSynapse[T]) FireWithInput(ctx context.Context, session *Session, input ExtractionInput) (ExtractAllResponse[T], error) {
+	result, err := e.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is an ExtractAllResponse[T].
+func (e *ExtractAllSynapse[T]) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := e.FireWithInput(ctx, session, ExtractionInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and extracts the records,
+// rejecting empty results when WithRequireItems is set.
+func (e *ExtractAllSynapse[T]) execute(ctx context.Context, session *Session, input ExtractionInput) (Result[ExtractAllResponse[T]], error) {
+	merged := e.mergeInputs(input)
+
+	// WithRequireItems sets the settings as the request passes through it
+	settings := &extractAllSettings{}
+	return e.service.executeConfigured(ctx, session, e.buildPrompt(merged), settings, merged.Temperature, func(response ExtractAllResponse[T]) error {
+		if len(response.Items) == 0 && settings.requireItems {
+			return fmt.Errorf("items required but empty")
+		}
+		return nil
+	})
+}
+
+// mergeInputs combines defaults with user input.
+func (e *ExtractAllSynapse[T]) mergeInputs(input ExtractionInput) ExtractionInput {
+	merged := e.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Examples != "" {
+		merged.Examples = input.Examples
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (e *ExtractAllSynapse[T]) buildPrompt(input ExtractionInput) *Prompt {
+	prompt := &Prompt{
+		Task:    fmt.Sprintf("Extract every %s", e.what),
+		Input:   input.Text,
+		Context: input.Context,
+		Schema:  e.schema,
+	}
+
+	// Add examples if provided, one per line
+	var lines []string
+	for _, line := range strings.Split(input.Examples, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 {
+		prompt.Examples = map[string][]string{
+			"examples": lines,
+		}
+	}
+
+	prompt.Constraints = []string{
+		fmt.Sprintf("items: one per %s in the text, in order of appearance; empty if there are none", e.what),
+		"never merge, split, or repeat records",
+		"use null for missing values",
+		"match exact JSON structure",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining what was extracted",
+	}
+
+	return prompt
+}
@@ -0,0 +1,324 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// extractLineItem is the record type of the extract-all tests.
+type extractLineItem struct {
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+}
+
+// Validate requires a positive amount.
+func (l extractLineItem) Validate() error {
+	if l.Amount <= 0 {
+		return errors.New("amount required")
+	}
+	return nil
+}
+
+// lineItemsResponse is a valid extract-all response with two line items.
+const lineItemsResponse = `{"items": [{"description": "Widgets", "amount": 20}, {"description": "Shipping", "amount": 5}], "confidence": 0.9, "reasoning": ["two lines on the invoice"]}`
+
+// emptyItemsResponse is a valid extract-all response with no items.
+const emptyItemsResponse = `{"items": [], "confidence": 0.9, "reasoning": ["the text has no line items"]}`
+
+func TestArraySchema(t *testing.T) {
+	t.Run("wraps the record schema", func(t *testing.T) {
+		element, err := generateJSONSchema[extractLineItem]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		schema, err := arraySchema(element)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"items": {"type": "array", "items": {
+					"type": "object",
+					"properties": {
+						"description": {"type": "string"},
+						"amount": {"type": "number"}
+					},
+					"required": ["description", "amount"],
+					"additionalProperties": false
+				}},
+				"confidence": {"type": "number"},
+				"reasoning": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["items", "confidence", "reasoning"],
+			"additionalProperties": false
+		}`, schema)
+	})
+
+	t.Run("hoists definitions", func(t *testing.T) {
+		element, err := generateJSONSchema[SchemaOutline]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		schema, err := arraySchema(element)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Count(schema, `"$defs"`) != 1 || !strings.Contains(schema, `"#/$defs/SchemaOutlineSection"`) {
+			t.Fatalf("expected one $defs at the root, got %s", schema)
+		}
+		if _, err := strictSchemaJSON(schema); err != nil {
+			t.Errorf("expected the wrapper to convert to a strict schema, got %v", err)
+		}
+	})
+
+	t.Run("recursive record becomes a definition", func(t *testing.T) {
+		element, err := generateJSONSchema[SchemaTreeNode]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		schema, err := arraySchema(element)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"items": {"type": "array", "items": {"$ref": "#/$defs/Item"}},
+				"confidence": {"type": "number"},
+				"reasoning": {"type": "array", "items": {"type": "string"}}
+			},
+			"required": ["items", "confidence", "reasoning"],
+			"additionalProperties": false,
+			"$defs": {
+				"Item": {
+					"type": "object",
+					"properties": {
+						"label": {"type": "string"},
+						"children": {"type": "array", "items": {"$ref": "#/$defs/Item"}}
+					},
+					"required": ["label", "children"],
+					"additionalProperties": false
+				}
+			}
+		}`, schema)
+	})
+}
+
+func TestExtractAllResponse_Validate(t *testing.T) {
+	response := ExtractAllResponse[extractLineItem]{
+		Items:      []extractLineItem{{Description: "Widgets", Amount: 20}, {Description: "Shipping"}},
+		Confidence: 0.9,
+		Reasoning:  []string{"two lines"},
+	}
+	err := response.Validate()
+	if err == nil || err.Error() != "item 1: amount required" {
+		t.Errorf("expected an index-annotated item error, got %v", err)
+	}
+
+	response.Items = nil
+	if err := response.Validate(); err != nil {
+		t.Errorf("expected no items to be valid, got %v", err)
+	}
+	response.Confidence = 2
+	if err := response.Validate(); err == nil {
+		t.Error("expected confidence out of range to fail")
+	}
+}
+
+func TestExtractAllSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return lineItemsResponse, nil
+	})
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := lines.Fire(context.Background(), NewSession(), "Widgets $20, shipping $5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Description != "Widgets" || items[1].Amount != 5 {
+		t.Errorf("unexpected items: %+v", items)
+	}
+	for _, want := range []string{"Extract every invoice line item", "one per invoice line item", "Widgets $20"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, requireItemsConstraint) {
+		t.Errorf("expected no require items constraint, got %s", prompt)
+	}
+}
+
+func TestExtractAllSynapse_FireWithDetails(t *testing.T) {
+	provider := NewMockProviderWithResponse(lineItemsResponse)
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := lines.FireWithDetails(context.Background(), NewSession(), "Widgets $20, shipping $5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Items) != 2 || response.Confidence != 0.9 || len(response.Reasoning) != 1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestExtractAllSynapse_EmptyAllowed(t *testing.T) {
+	provider := NewMockProviderWithResponse(emptyItemsResponse)
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := lines.Fire(context.Background(), NewSession(), "Thanks for your order!")
+	if err != nil {
+		t.Fatalf("expected empty results to be valid, got %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no items, got %+v", items)
+	}
+}
+
+func TestExtractAllSynapse_InvalidItem(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"items": [{"description": "Widgets", "amount": 20}, {"description": "Shipping"}], "confidence": 0.9, "reasoning": ["two lines"]}`)
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = lines.Fire(context.Background(), NewSession(), "Widgets $20, shipping")
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "item 1: amount required") {
+		t.Errorf("expected an invalid response naming the item, got %v", err)
+	}
+}
+
+func TestExtractAllSynapse_WithRequireItems(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return emptyItemsResponse, nil
+	})
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider, WithRequireItems())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = lines.Fire(context.Background(), NewSession(), "Widgets $20, shipping $5")
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "items required but empty") {
+		t.Errorf("expected empty results rejected, got %v", err)
+	}
+	if !strings.Contains(prompt, requireItemsConstraint) {
+		t.Errorf("expected prompt to contain the require items constraint, got %s", prompt)
+	}
+}
+
+func TestExtractAllSynapse_WithRequireItemsRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return emptyItemsResponse, nil
+		}
+		return lineItemsResponse, nil
+	})
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider, WithRequireItems(), WithValidationRetry(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := lines.Fire(context.Background(), NewSession(), "Widgets $20, shipping $5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || calls != 2 {
+		t.Errorf("expected two items after one retry, got %+v in %d calls", items, calls)
+	}
+}
+
+func TestWithRequireItems_OtherSynapses(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"description": "Widgets", "amount": 20}`, nil
+	})
+	line, err := Extract[extractLineItem]("the first invoice line item", provider, WithRequireItems())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := line.Fire(context.Background(), NewSession(), "Widgets $20"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(prompt, requireItemsConstraint) {
+		t.Errorf("expected the option to leave other synapses alone, got %s", prompt)
+	}
+}
+
+func TestExtractAllSynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(lineItemsResponse)
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := lines.FireResult(context.Background(), NewSession(), "Widgets $20, shipping $5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value) != 2 || result.Provider != "mock-fixed" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestExtractAllSynapse_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return lineItemsResponse, nil
+	})
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines.WithDefaults(ExtractionInput{Context: "amounts are in USD", Examples: "Widgets $20\n\nShipping $5"})
+
+	if _, err := lines.Fire(context.Background(), NewSession(), "Widgets $20, shipping $5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"amounts are in USD", "Shipping $5"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestExtractAllSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(lineItemsResponse)
+	lines, err := ExtractAll[extractLineItem]("invoice line item", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var synapse Synapse = lines
+	response, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "Widgets $20, shipping $5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if details, ok := response.(ExtractAllResponse[extractLineItem]); !ok || len(details.Items) != 2 {
+		t.Errorf("expected an ExtractAllResponse with two items, got %#v", response)
+	}
+	if lines.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
@@ -0,0 +1,144 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoobzio/pipz"
+)
+
+// ExtractFromInput contains rich input structure for extraction from
+// structured data.
+type ExtractFromInput[I any] struct {
+	Data        I       // The structured data to extract from
+	Context     string  // Optional context for extraction
+	Focus       string  // Optional part of the data to look in
+	Temperature float32 // LLM temperature setting
+}
+
+// ExtractFromSynapse extracts structured data of type O from structured data
+// of type I, such as normalized line items from a nested API payload.
+// O must implement Validator to ensure extracted data is valid.
+type ExtractFromSynapse[I any, O Validator] struct {
+	what     string
+	schema   string // Pre-computed JSON schema
+	defaults ExtractFromInput[I]
+	service  *Service[O]
+}
+
+// ExtractFrom creates a new extraction synapse for structured input. The input
+// is sent as JSON, like Analyze input; the output follows the schema of O.
+// Unlike Convert, which maps a whole record to another shape, ExtractFrom
+// finds the requested information inside the input and leaves the rest.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	extractor, err := ExtractFrom[OrderPayload, LineItems]("normalized line items", provider)
+//	items, err := extractor.Fire(ctx, session, payload)
+func ExtractFrom[I any, O Validator](what string, provider Provider, opts ...Option) (*ExtractFromSynapse[I, O], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[O]()
+	if err != nil {
+		return nil, fmt.Errorf("extraction synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[O](pipeline, "extraction", provider, DefaultTemperatureDeterministic)
+
+	return &ExtractFromSynapse[I, O]{
+		what:    what,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+func (e *ExtractFromSynapse[I, O]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return e.service.GetPipeline()
+}
+
+// WithDefaults sets default input values. Data is always taken from the input.
+func (e *ExtractFromSynapse[I, O]) WithDefaults(defaults ExtractFromInput[I]) *ExtractFromSynapse[I, O] {
+	e.defaults = defaults
+	return e
+}
+
+// Fire executes the extraction against structured data.
+func (e *ExtractFromSynapse[I, O]) Fire(ctx context.Context, session *Session, data I) (O, error) {
+	return e.FireWithInput(ctx, session, ExtractFromInput[I]{Data: data})
+}
+
+// FireResult executes the extraction and returns the extracted value in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (e *ExtractFromSynapse[I, O]) FireResult(ctx context.Context, session *Session, data I) (Result[O], error) {
+	merged := e.mergeInputs(ExtractFromInput[I]{Data: data})
+	result, err := e.service.ExecuteResult(ctx, session, e.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		var zero O
+		return withValue(result, zero), err
+	}
+	return result, nil
+}
+
+// FireWithInput executes the extraction with rich input structure.
+func (e *ExtractFromSynapse[I, O]) FireWithInput(ctx context.Context, session *Session, input ExtractFromInput[I]) (O, error) {
+	// Merge defaults with user input
+	merged := e.mergeInputs(input)
+
+	// Execute through service with session (service handles temperature fallback)
+	return e.service.Execute(ctx, session, e.buildPrompt(merged), merged.Temperature)
+}
+
+// mergeInputs combines defaults with user input.
+func (e *ExtractFromSynapse[I, O]) mergeInputs(input ExtractFromInput[I]) ExtractFromInput[I] {
+	merged := e.defaults
+
+	// Data is always taken from input
+	merged.Data = input.Data
+
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Focus != "" {
+		merged.Focus = input.Focus
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (e *ExtractFromSynapse[I, O]) buildPrompt(input ExtractFromInput[I]) *Prompt {
+	prompt := &Prompt{
+		Task:    fmt.Sprintf("Extract %s", e.what),
+		Input:   renderJSON(input.Data),
+		Context: input.Context,
+		Schema:  e.schema,
+	}
+
+	// Build constraints
+	constraints := []string{
+		fmt.Sprintf("extract only %s", e.what),
+		"input: structured JSON data; find the requested information within it",
+		"use values from the input; do not invent values",
+		"use null for missing values",
+		"match exact JSON structure",
+	}
+
+	if input.Focus != "" {
+		constraints = append(constraints, fmt.Sprintf("focus: %s", input.Focus))
+	}
+
+	prompt.Constraints = constraints
+
+	return prompt
+}
@@ -0,0 +1,167 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// orderPayload is a nested API payload fixture.
+type orderPayload struct {
+	ID       string `json:"id"`
+	Customer struct {
+		Name    string `json:"name"`
+		Country string `json:"country"`
+	} `json:"customer"`
+	Fulfillments []fulfillment `json:"fulfillments"`
+}
+
+type fulfillment struct {
+	Warehouse string      `json:"warehouse"`
+	Lines     []orderLine `json:"lines"`
+}
+
+type orderLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"qty"`
+	Price    money  `json:"price"`
+}
+
+type money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func testOrderPayload() orderPayload {
+	payload := orderPayload{ID: "ord-42"}
+	payload.Customer.Name = "Ada"
+	payload.Customer.Country = "GB"
+	for i, sku := range []string{"SKU-1", "SKU-2"} {
+		payload.Fulfillments = append(payload.Fulfillments, fulfillment{
+			Warehouse: fmt.Sprintf("wh-%d", i+1),
+			Lines:     []orderLine{{SKU: sku, Quantity: i + 1, Price: money{Amount: 1250 * (i + 1), Currency: "GBP"}}},
+		})
+	}
+	return payload
+}
+
+type lineItem struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"quantity"`
+	Total    float64 `json:"total"`
+}
+
+type lineItems struct {
+	Items []lineItem `json:"items"`
+}
+
+func (l lineItems) Validate() error {
+	if len(l.Items) == 0 {
+		return errors.New("at least one item required")
+	}
+	return nil
+}
+
+func TestExtractFrom(t *testing.T) {
+	synapse, err := ExtractFrom[orderPayload, lineItems]("normalized line items", NewMockProvider())
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	if synapse.what != "normalized line items" || !strings.Contains(synapse.schema, `"quantity"`) {
+		t.Errorf("unexpected synapse: %q %q", synapse.what, synapse.schema)
+	}
+	if synapse.GetPipeline() == nil {
+		t.Error("GetPipeline returned nil")
+	}
+}
+
+func TestExtractFromSynapse_Fire(t *testing.T) {
+	payload := testOrderPayload()
+
+	t.Run("extracts from nested data", func(t *testing.T) {
+		var received string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			received = prompt
+			return `{"items": [{"sku": "SKU-1", "quantity": 1, "total": 12.5}, {"sku": "SKU-2", "quantity": 2, "total": 25}]}`, nil
+		})
+		synapse, _ := ExtractFrom[orderPayload, lineItems]("normalized line items", provider)
+		session := NewSession()
+
+		result, err := synapse.Fire(context.Background(), session, payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Items) != 2 || result.Items[1].SKU != "SKU-2" || result.Items[1].Total != 25 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		for _, want := range []string{"Extract normalized line items", `"warehouse": "wh-2"`, `"currency": "GBP"`, `"country": "GB"`} {
+			if !strings.Contains(received, want) {
+				t.Errorf("expected prompt to contain %q", want)
+			}
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one exchange in session, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("rejects invalid output", func(t *testing.T) {
+		synapse, _ := ExtractFrom[orderPayload, lineItems]("normalized line items",
+			NewMockProviderWithResponse(`{"items": []}`))
+		session := NewSession()
+
+		if _, err := synapse.Fire(context.Background(), session, payload); err == nil {
+			t.Error("expected validation error")
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("result envelope", func(t *testing.T) {
+		synapse, _ := ExtractFrom[orderPayload, lineItems]("normalized line items",
+			NewMockProviderWithResponse(`{"items": [{"sku": "SKU-1", "quantity": 1, "total": 12.5}]}`))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Value.Items) != 1 || result.Usage == nil || result.RequestID == "" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+}
+
+func TestExtractFromSynapse_mergeInputs(t *testing.T) {
+	synapse, _ := ExtractFrom[orderPayload, lineItems]("line items", NewMockProvider())
+	synapse.WithDefaults(ExtractFromInput[orderPayload]{Context: "default context", Focus: "lines", Temperature: 0.2})
+
+	payload := testOrderPayload()
+	merged := synapse.mergeInputs(ExtractFromInput[orderPayload]{Data: payload, Focus: "fulfillments"})
+	if merged.Data.ID != "ord-42" || merged.Context != "default context" || merged.Focus != "fulfillments" || merged.Temperature != 0.2 {
+		t.Errorf("unexpected merge: %+v", merged)
+	}
+}
+
+func TestExtractFromSynapse_buildPrompt(t *testing.T) {
+	synapse, _ := ExtractFrom[orderPayload, lineItems]("line items", NewMockProvider())
+
+	prompt := synapse.buildPrompt(ExtractFromInput[orderPayload]{
+		Data:    testOrderPayload(),
+		Context: "checkout export",
+		Focus:   "only fulfilled lines",
+	})
+	if !strings.HasPrefix(prompt.Input, "{\n  \"id\": \"ord-42\"") {
+		t.Errorf("expected indented JSON input, got %q", prompt.Input)
+	}
+	if prompt.Context != "checkout export" || prompt.Schema != synapse.schema {
+		t.Errorf("unexpected prompt: %+v", prompt)
+	}
+	if prompt.Constraints[0] != "extract only line items" || prompt.Constraints[len(prompt.Constraints)-1] != "focus: only fulfilled lines" {
+		t.Errorf("unexpected constraints: %v", prompt.Constraints)
+	}
+	if err := prompt.Validate(); err != nil {
+		t.Errorf("expected valid prompt: %v", err)
+	}
+}
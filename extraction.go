@@ -20,10 +20,12 @@ type ExtractionInput struct {
 // It extracts structured data of type T from unstructured text.
 // T must implement Validator to ensure extracted data is valid.
 type ExtractionSynapse[T Validator] struct {
-	what     string
-	schema   string // Pre-computed JSON schema
-	defaults ExtractionInput
-	service  *Service[T]
+	what        string
+	schema      string   // Pre-computed JSON schema
+	constraints []string // Constraints added by wrapping synapses
+	defaults    ExtractionInput
+	chunking    *chunking[T] // Set by WithChunking
+	service     *Service[T]
 }
 
 // NewExtraction creates a new extraction synapse bound to a provider.
@@ -69,16 +71,54 @@ func (e *ExtractionSynapse[T]) Fire(ctx context.Context, session *Session, text
 	return e.FireWithInput(ctx, session, input)
 }
 
+// FireResult executes the extraction and returns the extracted value in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (e *ExtractionSynapse[T]) FireResult(ctx context.Context, session *Session, text string) (Result[T], error) {
+	result, err := e.execute(ctx, session, ExtractionInput{Text: text})
+	if err != nil {
+		var zero T
+		return withValue(result, zero), err
+	}
+	return result, nil
+}
+
 // FireWithInput executes the extraction with rich input structure.
 func (e *ExtractionSynapse[T]) FireWithInput(ctx context.Context, session *Session, input ExtractionInput) (T, error) {
+	result, err := e.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is the extracted T.
+func (e *ExtractionSynapse[T]) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := e.FireWithInput(ctx, session, ExtractionInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and extracts from the text, in
+// chunks when chunking is set and the text is longer than a chunk.
+func (e *ExtractionSynapse[T]) execute(ctx context.Context, session *Session, input ExtractionInput) (Result[T], error) {
 	// Merge defaults with user input
 	merged := e.mergeInputs(input)
 
-	// Build prompt
-	prompt := e.buildPrompt(merged)
+	if e.chunking != nil {
+		if err := checkChunkConfig(e.chunking.config); err != nil {
+			return Result[T]{Provider: e.service.providerName}, err
+		}
+		if chunks := splitChunks(merged.Text, e.chunking.config.MaxChars, e.chunking.config.Overlap); len(chunks) > 1 {
+			return e.fireChunked(ctx, session, merged, chunks)
+		}
+	}
 
 	// Execute through service with session (service handles temperature fallback)
-	return e.service.Execute(ctx, session, prompt, merged.Temperature)
+	return e.service.ExecuteResult(ctx, session, e.buildPrompt(merged), merged.Temperature)
 }
 
 // mergeInputs combines defaults with user input.
@@ -132,6 +172,7 @@ func (e *ExtractionSynapse[T]) buildPrompt(input ExtractionInput) *Prompt {
 		"use null for missing values",
 		"match exact JSON structure",
 	}
+	prompt.Constraints = append(prompt.Constraints, e.constraints...)
 
 	return prompt
 }
@@ -155,7 +196,7 @@ func (e *ExtractionSynapse[T]) buildPrompt(input ExtractionInput) *Prompt {
 //	}
 //
 //	extractor, err := Extract[Contact]("contact information", provider)
-//	contact, err := extractor.Fire(ctx, "John Doe at john@example.com")
+//	contact, err := extractor.Fire(ctx, session, "John Doe at john@example.com")
 func Extract[T Validator](what string, provider Provider, opts ...Option) (*ExtractionSynapse[T], error) {
 	return NewExtraction[T](what, provider, opts...)
 }
@@ -2,6 +2,8 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -498,3 +500,34 @@ func TestExtract(t *testing.T) {
 		}
 	})
 }
+
+// contactDetails is an extracted type that requires a phone number.
+type contactDetails struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+func (c contactDetails) Validate() error {
+	if c.Phone == "" {
+		return fmt.Errorf("phone required but empty")
+	}
+	return nil
+}
+
+func TestExtractionSynapse_ValidationError(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"name": "Grace Hopper", "phone": ""}`)
+	synapse, _ := Extract[contactDetails]("contact details", provider)
+
+	session := NewSession()
+	_, err := synapse.Fire(context.Background(), session, "Call Grace Hopper tomorrow.")
+	var invalid *ValidationError[contactDetails]
+	if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if invalid.Value.Name != "Grace Hopper" {
+		t.Errorf("expected the parsed contact on the error, got %+v", invalid.Value)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected the session untouched, got %d messages", session.Len())
+	}
+}
@@ -0,0 +1,382 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+	"gopkg.in/yaml.v3"
+)
+
+// Identity for the output format option.
+var outputFormatID = pipz.NewIdentity("zyn:output-format", "Sets the response output format")
+
+// OutputFormat selects the structured format the LLM is asked to respond in.
+// The format only changes the instructions rendered into the prompt and the
+// parser applied to the raw response; response types and their Validate
+// logic are identical across formats.
+type OutputFormat int
+
+// Supported output formats.
+const (
+	// OutputFormatJSON asks for a JSON object matching the schema (default).
+	OutputFormatJSON OutputFormat = iota
+
+	// OutputFormatYAML asks for a YAML document matching the schema.
+	// Many small local models produce YAML more reliably than JSON.
+	OutputFormatYAML
+
+	// OutputFormatKeyValue asks for one "KEY: value" pair per line.
+	// List fields repeat their key once per entry and nested fields use
+	// dotted keys (e.g. "scores.positive: 0.7").
+	OutputFormatKeyValue
+)
+
+// String returns the format name.
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputFormatJSON:
+		return "json"
+	case OutputFormatYAML:
+		return "yaml"
+	case OutputFormatKeyValue:
+		return "key-value"
+	default:
+		return fmt.Sprintf("OutputFormat(%d)", int(f))
+	}
+}
+
+// instructions returns the format guidance rendered into the prompt.
+// JSON needs no extra guidance beyond the schema section.
+func (f OutputFormat) instructions() string {
+	switch f {
+	case OutputFormatYAML:
+		return "Respond with a YAML document matching the schema above. Do not respond with JSON."
+	case OutputFormatKeyValue:
+		return "Respond with one KEY: value pair per line matching the schema above.\n" +
+			"Repeat a key once per entry for list fields.\n" +
+			"Use dotted keys (parent.child: value) for nested fields."
+	default:
+		return ""
+	}
+}
+
+// WithOutputFormat sets the structured format the LLM is asked to respond in.
+// JSON is the default; YAML and key-value lines are useful for smaller models
+// that struggle to emit valid JSON.
+func WithOutputFormat(format OutputFormat) Option {
+	return withRequest(outputFormatID, func(req *SynapseRequest) {
+		req.Prompt.Format = format
+	})
+}
+
+// withRequest returns an Option that applies fn to every request before it
+// reaches the wrapped pipeline. It is the building block for options that
+// configure a call rather than add reliability behavior.
+func withRequest(identity pipz.Identity, fn func(*SynapseRequest)) Option {
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		configure := pipz.Transform(identity, func(_ context.Context, req *SynapseRequest) *SynapseRequest {
+			fn(req)
+			return req
+		})
+		return pipz.NewSequence(identity, configure, pipeline)
+	}
+}
+
+// withSettings returns an Option that applies fn to requests from synapses
+// whose calls carry settings of type S, such as a classification synapse's
+// none category. Requests from other synapses pass through unchanged, so
+// the option has no effect on them.
+func withSettings[S any](identity pipz.Identity, fn func(*SynapseRequest, *S)) Option {
+	return withRequest(identity, func(req *SynapseRequest) {
+		if settings, ok := req.settings.(*S); ok {
+			fn(req, settings)
+		}
+	})
+}
+
+// parseResponse decodes a raw provider response into T using the prompt's
+// format and schema, and returns the response body converted to JSON.
+// Markdown code fences around the payload are tolerated for every format.
+// In strict mode, top-level fields outside the schema are rejected.
+func parseResponse[T any](raw string, prompt *Prompt) (T, json.RawMessage, error) {
+	var result T
+
+	body, err := decodeResponse(stripCodeFences(raw), prompt.Format, prompt.Schema)
+	if err != nil {
+		return result, nil, err
+	}
+
+	if prompt.Strict {
+		unexpected, err := unexpectedFields(body, prompt.Schema)
+		if err != nil {
+			return result, body, err
+		}
+		if len(unexpected) > 0 {
+			return result, body, fmt.Errorf("unexpected fields in response: %s", strings.Join(unexpected, ", "))
+		}
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, body, err
+	}
+	return result, body, nil
+}
+
+// decodeResponse converts a response body in the given format to JSON.
+func decodeResponse(body string, format OutputFormat, schema string) ([]byte, error) {
+	switch format {
+	case OutputFormatJSON:
+		return []byte(body), nil
+	case OutputFormatYAML:
+		return decodeYAML(body)
+	case OutputFormatKeyValue:
+		// Models frequently ignore the format request and answer in JSON anyway
+		if strings.HasPrefix(body, "{") && json.Valid([]byte(body)) {
+			return []byte(body), nil
+		}
+		return decodeKeyValue(body, schema)
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// stripCodeFences removes a surrounding markdown code fence (```json ... ```).
+func stripCodeFences(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	if !strings.HasPrefix(trimmed, "```") {
+		return trimmed
+	}
+
+	// Drop the opening fence line, including any language tag
+	newline := strings.Index(trimmed, "\n")
+	if newline == -1 {
+		return strings.Trim(trimmed, "`")
+	}
+	trimmed = trimmed[newline+1:]
+
+	// Drop the closing fence if present
+	if idx := strings.LastIndex(trimmed, "```"); idx != -1 {
+		trimmed = trimmed[:idx]
+	}
+	return strings.TrimSpace(trimmed)
+}
+
+// decodeYAML converts a YAML document to JSON.
+func decodeYAML(body string) ([]byte, error) {
+	var data any
+	if err := yaml.Unmarshal([]byte(body), &data); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return json.Marshal(normalizeYAML(data))
+}
+
+// normalizeYAML converts map[any]any values produced by the YAML decoder
+// into map[string]any so the result can be marshaled as JSON.
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeYAML(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// decodeKeyValue converts "KEY: value" lines to JSON.
+// The JSON schema is used to coerce values to their declared types and to
+// collect repeated keys into arrays.
+func decodeKeyValue(body, schema string) ([]byte, error) {
+	var root map[string]any
+	if schema != "" {
+		if err := json.Unmarshal([]byte(schema), &root); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+	}
+
+	result := make(map[string]any)
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected KEY: value, got %q", i+1, line)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", i+1)
+		}
+
+		if err := setKeyValue(result, root, root, strings.Split(key, "."), strings.TrimSpace(value)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+
+	return json.Marshal(result)
+}
+
+// setKeyValue assigns a value at a dotted key path, guided by the schema node.
+// The root schema is used to resolve $ref pointers to shared definitions.
+func setKeyValue(target map[string]any, root, node map[string]any, path []string, raw string) error {
+	name, propSchema := resolveProperty(node, path[0])
+	propSchema = derefSchema(root, propSchema)
+
+	if len(path) > 1 {
+		child, ok := target[name].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			target[name] = child
+		}
+		return setKeyValue(child, root, propSchema, path[1:], raw)
+	}
+
+	if schemaType(propSchema) == jsonTypeArray {
+		var items []any
+		if existing, ok := target[name].([]any); ok {
+			items = existing
+		}
+		value, err := coerceValue(raw, propSchema)
+		if err == nil {
+			if list, isList := value.([]any); isList {
+				target[name] = append(items, list...)
+				return nil
+			}
+		}
+		value, err = coerceValue(raw, derefSchema(root, schemaChild(propSchema, "items")))
+		if err != nil {
+			return err
+		}
+		target[name] = append(items, value)
+		return nil
+	}
+
+	value, err := coerceValue(raw, propSchema)
+	if err != nil {
+		return err
+	}
+	target[name] = value
+	return nil
+}
+
+// resolveProperty finds the schema for a key, matching property names
+// case-insensitively. Keys of map-typed objects keep their original case.
+func resolveProperty(node map[string]any, key string) (string, map[string]any) {
+	if props, ok := node["properties"].(map[string]any); ok {
+		for name := range props {
+			if strings.EqualFold(name, key) {
+				return name, schemaChild(props, name)
+			}
+		}
+	}
+	if additional, ok := node["additionalProperties"].(map[string]any); ok {
+		return key, additional
+	}
+	return strings.ToLower(key), nil
+}
+
+// coerceValue converts a raw string to the type declared by the schema node.
+func coerceValue(raw string, node map[string]any) (any, error) {
+	switch schemaType(node) {
+	case jsonTypeBoolean:
+		b, err := strconv.ParseBool(strings.ToLower(raw))
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", raw)
+		}
+		return b, nil
+	case jsonTypeInteger, jsonTypeNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected number, got %q", raw)
+		}
+		return n, nil
+	case jsonTypeString:
+		return unquote(raw), nil
+	default:
+		// Untyped, array, or object values may be given as JSON literals
+		var value any
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value, nil
+		}
+		if schemaType(node) == jsonTypeArray || schemaType(node) == jsonTypeObject {
+			return nil, fmt.Errorf("expected JSON %s, got %q", schemaType(node), raw)
+		}
+		return unquote(raw), nil
+	}
+}
+
+// derefSchema resolves a schema node that is a "$ref" (or a nullable anyOf
+// wrapping one) to the referenced node within root. Other nodes are returned as is.
+func derefSchema(root, node map[string]any) map[string]any {
+	if options, ok := node["anyOf"].([]any); ok {
+		for _, option := range options {
+			if child, isNode := option.(map[string]any); isNode && schemaType(child) != jsonTypeNull {
+				node = child
+				break
+			}
+		}
+	}
+
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	return resolveRef(root, ref)
+}
+
+// resolveRef returns the node a "$ref" points to within root: the root itself
+// for "#", or a shared definition for "#/$defs/<name>". Returns nil otherwise.
+func resolveRef(root map[string]any, ref string) map[string]any {
+	if ref == "#" {
+		return root
+	}
+	if name, found := strings.CutPrefix(ref, "#/$defs/"); found {
+		return schemaChild(schemaChild(root, "$defs"), name)
+	}
+	return nil
+}
+
+// schemaType returns the "type" of a schema node, or "" when unknown.
+func schemaType(node map[string]any) string {
+	if t, ok := node["type"].(string); ok {
+		return t
+	}
+	return ""
+}
+
+// schemaChild returns the nested schema node stored under key, or nil.
+func schemaChild(node map[string]any, key string) map[string]any {
+	if child, ok := node[key].(map[string]any); ok {
+		return child
+	}
+	return nil
+}
+
+// unquote removes matching surrounding quotes from a value.
+func unquote(raw string) string {
+	if len(raw) >= 2 {
+		first, last := raw[0], raw[len(raw)-1]
+		if (first == '"' && last == '"') || (first == '\'' && last == '\'') {
+			return raw[1 : len(raw)-1]
+		}
+	}
+	return raw
+}
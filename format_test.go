@@ -0,0 +1,572 @@
+package zyn
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/pipz"
+)
+
+// canonicalResponse pairs a synapse's JSON response with its YAML equivalent.
+type canonicalResponse struct {
+	name  string
+	json  string
+	yaml  string
+	parse func(raw string, format OutputFormat) (any, error)
+}
+
+// parseAs returns a parse function for a concrete response type.
+func parseAs[T Validator]() func(string, OutputFormat) (any, error) {
+	return func(raw string, format OutputFormat) (any, error) {
+		schema, err := generateJSONSchema[T]()
+		if err != nil {
+			return nil, err
+		}
+		result, _, err := parseResponse[T](raw, &Prompt{Format: format, Schema: schema})
+		if err != nil {
+			return nil, err
+		}
+		return result, result.Validate()
+	}
+}
+
+var canonicalResponses = []canonicalResponse{
+	{
+		name:  "binary",
+		json:  `{"decision": true, "confidence": 0.9, "reasoning": ["has @", "has domain"]}`,
+		yaml:  "decision: true\nconfidence: 0.9\nreasoning:\n  - has @\n  - has domain\n",
+		parse: parseAs[BinaryResponse](),
+	},
+	{
+		name:  "classification",
+		json:  `{"primary": "bug", "secondary": "feature", "confidence": 0.8, "reasoning": ["error message"]}`,
+		yaml:  "primary: bug\nsecondary: feature\nconfidence: 0.8\nreasoning:\n  - error message\n",
+		parse: parseAs[ClassificationResponse](),
+	},
+	{
+		name:  "ranking",
+		json:  `{"ranked": ["a", "b", "c"], "confidence": 0.7, "reasoning": ["a first"]}`,
+		yaml:  "ranked: [a, b, c]\nconfidence: 0.7\nreasoning:\n  - a first\n",
+		parse: parseAs[RankingResponse](),
+	},
+	{
+		name:  "sentiment",
+		json:  `{"overall": "positive", "confidence": 0.85, "scores": {"positive": 0.7, "negative": 0.1, "neutral": 0.2}, "aspects": {"price": "negative"}, "emotions": ["joy"], "reasoning": ["upbeat"]}`,
+		yaml:  "overall: positive\nconfidence: 0.85\nscores:\n  positive: 0.7\n  negative: 0.1\n  neutral: 0.2\naspects:\n  price: negative\nemotions:\n  - joy\nreasoning:\n  - upbeat\n",
+		parse: parseAs[SentimentResponse](),
+	},
+	{
+		name:  "transform",
+		json:  `{"output": "Hello: world", "confidence": 0.9, "changes": ["capitalized"], "reasoning": ["style"]}`,
+		yaml:  "output: \"Hello: world\"\nconfidence: 0.9\nchanges:\n  - capitalized\nreasoning:\n  - style\n",
+		parse: parseAs[TransformResponse](),
+	},
+	{
+		name:  "analyze",
+		json:  `{"analysis": "multi\nline", "confidence": 0.6, "findings": ["one"], "reasoning": ["looked"]}`,
+		yaml:  "analysis: |-\n  multi\n  line\nconfidence: 0.6\nfindings:\n  - one\nreasoning:\n  - looked\n",
+		parse: parseAs[AnalyzeResponse](),
+	},
+	{
+		name:  "analyze slice",
+		json:  `{"analysis": "steady", "confidence": 0.7, "findings": [{"severity": "warning", "title": "gap", "field": "ts"}], "per_record": [{"index": 1, "note": "late"}], "reasoning": ["scanned"]}`,
+		yaml:  "analysis: steady\nconfidence: 0.7\nfindings:\n  - severity: warning\n    title: gap\n    field: ts\nper_record:\n  - index: 1\n    note: late\nreasoning:\n  - scanned\n",
+		parse: parseAs[AnalyzeSliceResponse](),
+	},
+	{
+		name:  "anomaly",
+		json:  `{"anomalies": [{"index": 2, "reason": "amount 100x the median", "severity": "high"}], "confidence": 0.8, "reasoning": ["amounts near 40"]}`,
+		yaml:  "anomalies:\n  - index: 2\n    reason: amount 100x the median\n    severity: high\nconfidence: 0.8\nreasoning:\n  - amounts near 40\n",
+		parse: parseAs[AnomalyResponse](),
+	},
+	{
+		name:  "answer",
+		json:  `{"answer": "30 days", "citations": [{"doc_id": "doc-1", "quote": "Refunds: within 30 days"}], "confidence": 0.9, "reasoning": ["policy states it"]}`,
+		yaml:  "answer: 30 days\ncitations:\n  - doc_id: doc-1\n    quote: \"Refunds: within 30 days\"\nconfidence: 0.9\nreasoning:\n  - policy states it\n",
+		parse: parseAs[AnswerResponse](),
+	},
+	{
+		name:  "choose",
+		json:  `{"selected": 2, "runner_up": 0, "confidence": 0.75, "reasoning": ["best fit"]}`,
+		yaml:  "selected: 2\nrunner_up: 0\nconfidence: 0.75\nreasoning:\n  - best fit\n",
+		parse: parseAs[choice](),
+	},
+	{
+		name:  "cluster",
+		json:  `{"clusters": [{"label": "billing", "items": ["charged twice", "refund late"]}, {"label": "login", "items": ["reset fails"]}], "confidence": 0.8, "reasoning": ["by topic"]}`,
+		yaml:  "clusters:\n  - label: billing\n    items: [charged twice, refund late]\n  - label: login\n    items: [reset fails]\nconfidence: 0.8\nreasoning:\n  - by topic\n",
+		parse: parseAs[ClusterResponse](),
+	},
+	{
+		name:  "compare",
+		json:  `{"winner": "tie", "margin": 0, "confidence": 0.6, "reasoning": ["equally clear"]}`,
+		yaml:  "winner: tie\nmargin: 0\nconfidence: 0.6\nreasoning:\n  - equally clear\n",
+		parse: parseAs[CompareResponse](),
+	},
+	{
+		name:  "compareanalyze",
+		json:  `{"summary": "Q2 grew faster", "differences": [{"aspect": "revenue growth", "a": "4%", "b": "9%", "significance": "doubling"}], "no_material_differences": false, "confidence": 0.85, "reasoning": ["compared totals"]}`,
+		yaml:  "summary: Q2 grew faster\ndifferences:\n  - aspect: revenue growth\n    a: 4%\n    b: 9%\n    significance: doubling\nno_material_differences: false\nconfidence: 0.85\nreasoning:\n  - compared totals\n",
+		parse: parseAs[CompareAnalyzeResponse](),
+	},
+	{
+		name:  "convert",
+		json:  `{"data": {"count": 3, "label": "three", "active": true}, "source_fields_used": ["value", "name"], "assumed_fields": ["active"], "confidence": 0.9, "reasoning": ["mapped value"]}`,
+		yaml:  "data:\n  count: 3\n  label: three\n  active: true\nsource_fields_used: [value, name]\nassumed_fields: [active]\nconfidence: 0.9\nreasoning:\n  - mapped value\n",
+		parse: parseAs[ConvertResponse[SimpleOutput]](),
+	},
+	{
+		name:  "dedupe",
+		json:  `{"groups": [["IBM", "I.B.M."], ["Apple"]], "canonical": ["IBM", "Apple"], "confidence": 0.9, "reasoning": ["same company"]}`,
+		yaml:  "groups:\n  - [IBM, I.B.M.]\n  - [Apple]\ncanonical: [IBM, Apple]\nconfidence: 0.9\nreasoning:\n  - same company\n",
+		parse: parseAs[DedupeResponse](),
+	},
+	{
+		name:  "diff",
+		json:  `{"summary": "Image bumped", "changes": [{"path": "spec.image", "before": "app:1.0", "after": "app:1.1", "impact": "new release"}], "confidence": 0.95, "reasoning": ["one field"]}`,
+		yaml:  "summary: Image bumped\nchanges:\n  - path: spec.image\n    before: \"app:1.0\"\n    after: \"app:1.1\"\n    impact: new release\nconfidence: 0.95\nreasoning:\n  - one field\n",
+		parse: parseAs[DiffResponse](),
+	},
+	{
+		name:  "entities",
+		json:  `{"entities": [{"text": "Microsoft Corp.", "type": "organization", "normalized": "Microsoft"}], "confidence": 0.9, "reasoning": ["company name"]}`,
+		yaml:  "entities:\n  - text: Microsoft Corp.\n    type: organization\n    normalized: Microsoft\nconfidence: 0.9\nreasoning:\n  - company name\n",
+		parse: parseAs[EntityResponse](),
+	},
+	{
+		name:  "extraction",
+		json:  `{"name": "widget", "value": 42, "items": ["a", "b"]}`,
+		yaml:  "name: widget\nvalue: 42\nitems:\n  - a\n  - b\n",
+		parse: parseAs[ExtractData](),
+	},
+	{
+		name:  "extractall",
+		json:  `{"items": [{"name": "widget", "value": 42, "items": []}], "confidence": 0.8, "reasoning": ["one record"]}`,
+		yaml:  "items:\n  - name: widget\n    value: 42\n    items: []\nconfidence: 0.8\nreasoning:\n  - one record\n",
+		parse: parseAs[ExtractAllResponse[ExtractData]](),
+	},
+	{
+		name:  "generate",
+		json:  `{"name": "widget", "value": 42, "items": ["a"]}`,
+		yaml:  "name: widget\nvalue: 42\nitems: [a]\n",
+		parse: parseAs[generated[ExtractData]](),
+	},
+	{
+		name:  "grade",
+		json:  `{"per_criterion": {"accuracy": 0.9, "clarity": 0.5}, "feedback": ["shorten the intro"], "confidence": 0.7, "reasoning": ["correct but long"]}`,
+		yaml:  "per_criterion:\n  accuracy: 0.9\n  clarity: 0.5\nfeedback:\n  - shorten the intro\nconfidence: 0.7\nreasoning:\n  - correct but long\n",
+		parse: parseAs[GradeResponse](),
+	},
+	{
+		name:  "intent",
+		json:  `{"intent": "book_flight", "slots": {"destination": "Paris", "date": "2026-11-02"}, "missing": ["origin"], "confidence": 0.85, "reasoning": ["asks for a flight"]}`,
+		yaml:  "intent: book_flight\nslots:\n  destination: Paris\n  date: \"2026-11-02\"\nmissing:\n  - origin\nconfidence: 0.85\nreasoning:\n  - asks for a flight\n",
+		parse: parseAs[IntentResponse](),
+	},
+	{
+		name:  "match",
+		json:  `{"pairs": [{"left": "2x bolts", "right": "bolts (2)", "score": 0.9}], "unmatched_left": [], "unmatched_right": ["nuts"], "confidence": 0.8, "reasoning": ["same part"]}`,
+		yaml:  "pairs:\n  - left: 2x bolts\n    right: bolts (2)\n    score: 0.9\nunmatched_left: []\nunmatched_right:\n  - nuts\nconfidence: 0.8\nreasoning:\n  - same part\n",
+		parse: parseAs[MatchResponse](),
+	},
+	{
+		name:  "moderation",
+		json:  `{"categories": {"harassment": 0.1, "spam": 0.8}, "confidence": 0.9, "reasoning": ["link farm"]}`,
+		yaml:  "categories:\n  harassment: 0.1\n  spam: 0.8\nconfidence: 0.9\nreasoning:\n  - link farm\n",
+		parse: parseAs[ModerationResponse](),
+	},
+	{
+		name:  "redact",
+		json:  `{"redacted": "Mail [EMAIL]", "entities": [{"type": "email", "original": "a@b.com", "placeholder": "[EMAIL]"}], "confidence": 0.95, "reasoning": ["one address"]}`,
+		yaml:  "redacted: Mail [EMAIL]\nentities:\n  - type: email\n    original: a@b.com\n    placeholder: \"[EMAIL]\"\nconfidence: 0.95\nreasoning:\n  - one address\n",
+		parse: parseAs[RedactResponse](),
+	},
+	{
+		name:  "segment",
+		json:  `{"segments": [{"title": "Intro", "text": "First part.\nStill first.", "start_hint": "First part"}], "confidence": 0.8, "reasoning": ["topic shift"]}`,
+		yaml:  "segments:\n  - title: Intro\n    text: |-\n      First part.\n      Still first.\n    start_hint: First part\nconfidence: 0.8\nreasoning:\n  - topic shift\n",
+		parse: parseAs[SegmentResponse](),
+	},
+	{
+		name:  "spans",
+		json:  `{"items": [{"value": {"name": "widget", "value": 42, "items": []}, "text": "widget", "confidence": 0.9, "start": 4, "end": 10}]}`,
+		yaml:  "items:\n  - value:\n      name: widget\n      value: 42\n      items: []\n    text: widget\n    confidence: 0.9\n    start: 4\n    end: 10\n",
+		parse: parseAs[SpanResponse[ExtractData]](),
+	},
+	{
+		name:  "summarize",
+		json:  `{"summary": "Costs rose.", "key_points": ["Costs rose 5%"], "omitted_topics": ["hiring"], "confidence": 0.8, "reasoning": ["focused on costs"]}`,
+		yaml:  "summary: Costs rose.\nkey_points:\n  - Costs rose 5%\nomitted_topics:\n  - hiring\nconfidence: 0.8\nreasoning:\n  - focused on costs\n",
+		parse: parseAs[SummarizeResponse](),
+	},
+	{
+		name:  "tag",
+		json:  `{"tags": ["go", "concurrency"], "confidence": 0.9, "reasoning": ["about goroutines"]}`,
+		yaml:  "tags: [go, concurrency]\nconfidence: 0.9\nreasoning:\n  - about goroutines\n",
+		parse: parseAs[TagResponse](),
+	},
+	{
+		name:  "taxonomy",
+		json:  `{"primary": "hardware", "secondary": "laptops", "confidence": 0.8, "reasoning": ["mentions a laptop"]}`,
+		yaml:  "primary: hardware\nsecondary: laptops\nconfidence: 0.8\nreasoning:\n  - mentions a laptop\n",
+		parse: parseAs[TaxonomyResponse](),
+	},
+	{
+		name:  "translate",
+		json:  `{"output": "Bonjour: le monde", "detected_source_lang": "en", "confidence": 0.9, "notes": ["kept the colon"]}`,
+		yaml:  "output: \"Bonjour: le monde\"\ndetected_source_lang: en\nconfidence: 0.9\nnotes:\n  - kept the colon\n",
+		parse: parseAs[TranslateResponse](),
+	},
+	{
+		name:  "verify",
+		json:  `{"verdicts": [{"claim": "Sales rose", "verdict": "supported", "quote": "sales up 4%"}, {"claim": "Costs fell", "verdict": "unverifiable", "quote": ""}], "confidence": 0.85, "reasoning": ["checked the report"]}`,
+		yaml:  "verdicts:\n  - claim: Sales rose\n    verdict: supported\n    quote: sales up 4%\n  - claim: Costs fell\n    verdict: unverifiable\n    quote: \"\"\nconfidence: 0.85\nreasoning:\n  - checked the report\n",
+		parse: parseAs[VerifyResponse](),
+	},
+}
+
+func TestParseResponse_YAMLMatchesJSON(t *testing.T) {
+	for _, tc := range canonicalResponses {
+		t.Run(tc.name, func(t *testing.T) {
+			fromJSON, err := tc.parse(tc.json, OutputFormatJSON)
+			if err != nil {
+				t.Fatalf("JSON parse failed: %v", err)
+			}
+			fromYAML, err := tc.parse(tc.yaml, OutputFormatYAML)
+			if err != nil {
+				t.Fatalf("YAML parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(fromJSON, fromYAML) {
+				t.Errorf("YAML result differs from JSON:\njson: %+v\nyaml: %+v", fromJSON, fromYAML)
+			}
+		})
+	}
+}
+
+func TestParseResponse_YAMLAcceptsJSON(t *testing.T) {
+	// JSON is valid YAML, so models that ignore the format still parse
+	for _, tc := range canonicalResponses {
+		t.Run(tc.name, func(t *testing.T) {
+			fromJSON, err := tc.parse(tc.json, OutputFormatJSON)
+			if err != nil {
+				t.Fatalf("JSON parse failed: %v", err)
+			}
+			viaYAML, err := tc.parse(tc.json, OutputFormatYAML)
+			if err != nil {
+				t.Fatalf("YAML parse of JSON failed: %v", err)
+			}
+			if !reflect.DeepEqual(fromJSON, viaYAML) {
+				t.Errorf("results differ:\njson: %+v\nyaml: %+v", fromJSON, viaYAML)
+			}
+		})
+	}
+}
+
+func TestParseResponse_CodeFences(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		format OutputFormat
+	}{
+		{"json fenced", "```json\n{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}\n```", OutputFormatJSON},
+		{"json bare fence", "```\n{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}\n```", OutputFormatJSON},
+		{"yaml fenced", "```yaml\ndecision: true\nconfidence: 0.9\nreasoning:\n  - ok\n```", OutputFormatYAML},
+		{"key-value fenced", "```\nDECISION: true\nCONFIDENCE: 0.9\nREASONING: ok\n```", OutputFormatKeyValue},
+		{"surrounding whitespace", "\n\n  {\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}  \n", OutputFormatJSON},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseAs[BinaryResponse]()(tc.raw, tc.format)
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			expected := BinaryResponse{Decision: true, Confidence: 0.9, Reasoning: []string{"ok"}}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("expected %+v, got %+v", expected, result)
+			}
+		})
+	}
+}
+
+func TestParseResponse_KeyValue(t *testing.T) {
+	t.Run("binary with repeated keys", func(t *testing.T) {
+		raw := "DECISION: false\nCONFIDENCE: 0.75\nREASONING: missing @\nREASONING: no domain\n"
+		result, err := parseAs[BinaryResponse]()(raw, OutputFormatKeyValue)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		expected := BinaryResponse{Decision: false, Confidence: 0.75, Reasoning: []string{"missing @", "no domain"}}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("sentiment with dotted keys", func(t *testing.T) {
+		raw := strings.Join([]string{
+			"overall: positive",
+			"confidence: 0.85",
+			"scores.positive: 0.7",
+			"scores.negative: 0.1",
+			"scores.neutral: 0.2",
+			"aspects.price: negative",
+			"emotions: joy",
+			"reasoning: upbeat",
+		}, "\n")
+		fromKV, err := parseAs[SentimentResponse]()(raw, OutputFormatKeyValue)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		fromJSON, err := parseAs[SentimentResponse]()(canonicalResponses[3].json, OutputFormatJSON)
+		if err != nil {
+			t.Fatalf("JSON parse failed: %v", err)
+		}
+		if !reflect.DeepEqual(fromJSON, fromKV) {
+			t.Errorf("key-value result differs from JSON:\njson: %+v\nkv:   %+v", fromJSON, fromKV)
+		}
+	})
+
+	t.Run("json array literal", func(t *testing.T) {
+		raw := "ranked: [\"a\", \"b\"]\nconfidence: 0.5\nreasoning: ok"
+		result, err := parseAs[RankingResponse]()(raw, OutputFormatKeyValue)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		if !reflect.DeepEqual(result.(RankingResponse).Ranked, []string{"a", "b"}) {
+			t.Errorf("unexpected ranked: %v", result.(RankingResponse).Ranked)
+		}
+	})
+
+	t.Run("json fallback", func(t *testing.T) {
+		result, err := parseAs[BinaryResponse]()(canonicalResponses[0].json, OutputFormatKeyValue)
+		if err != nil {
+			t.Fatalf("parse failed: %v", err)
+		}
+		if !result.(BinaryResponse).Decision {
+			t.Error("expected decision true")
+		}
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		_, err := parseAs[BinaryResponse]()("decision true", OutputFormatKeyValue)
+		if err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("expected line error, got %v", err)
+		}
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		_, err := parseAs[BinaryResponse]()("decision: maybe\nconfidence: 0.5\nreasoning: x", OutputFormatKeyValue)
+		if err == nil || !strings.Contains(err.Error(), "expected boolean") {
+			t.Errorf("expected boolean error, got %v", err)
+		}
+	})
+}
+
+func TestParseResponse_InvalidYAML(t *testing.T) {
+	_, err := parseAs[BinaryResponse]()("decision: [unclosed", OutputFormatYAML)
+	if err == nil {
+		t.Fatal("expected error for invalid YAML")
+	}
+}
+
+func TestOutputFormat_String(t *testing.T) {
+	tests := map[OutputFormat]string{
+		OutputFormatJSON:     "json",
+		OutputFormatYAML:     "yaml",
+		OutputFormatKeyValue: "key-value",
+		OutputFormat(42):     "OutputFormat(42)",
+	}
+	for format, expected := range tests {
+		if format.String() != expected {
+			t.Errorf("expected %q, got %q", expected, format.String())
+		}
+	}
+}
+
+func TestWithOutputFormat(t *testing.T) {
+	t.Run("yaml end to end", func(t *testing.T) {
+		var captured string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			captured = prompt
+			return "decision: true\nconfidence: 0.9\nreasoning:\n  - looks valid\n", nil
+		})
+
+		synapse, err := Binary("Is this valid?", provider, WithOutputFormat(OutputFormatYAML))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		response, err := synapse.FireWithDetails(context.Background(), session, "test")
+		if err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if !response.Decision || response.Confidence != 0.9 {
+			t.Errorf("unexpected response: %+v", response)
+		}
+
+		if !strings.Contains(captured, "Response Format:") || !strings.Contains(captured, "YAML") {
+			t.Errorf("prompt missing YAML instructions:\n%s", captured)
+		}
+
+		// Session records the prompt exactly as sent
+		msg, err := session.At(0)
+		if err != nil {
+			t.Fatalf("session missing prompt: %v", err)
+		}
+		if msg.Content != captured {
+			t.Error("session prompt differs from prompt sent to provider")
+		}
+	})
+
+	t.Run("key-value end to end", func(t *testing.T) {
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			return "PRIMARY: billing\nSECONDARY:\nCONFIDENCE: 0.7\nREASONING: mentions invoice", nil
+		})
+
+		synapse, err := Classification("Which team?", []string{"billing", "support"}, provider,
+			WithOutputFormat(OutputFormatKeyValue))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "invoice is wrong")
+		if err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if response.Primary != "billing" || response.Secondary != "" {
+			t.Errorf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("json default has no format section", func(t *testing.T) {
+		var captured string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			captured = prompt
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+
+		synapse, err := Binary("Is this valid?", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), NewSession(), "test"); err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if strings.Contains(captured, "Response Format:") {
+			t.Error("JSON prompt should not include format section")
+		}
+	})
+}
+
+func TestParseResponse_StrictMode(t *testing.T) {
+	schema, err := generateJSONSchema[BinaryResponse]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+	raw := `{"decision": true, "confidence": 0.9, "reasoning": ["ok"], "padding": "x", "extra": 1}`
+
+	t.Run("lenient tolerates extra fields", func(t *testing.T) {
+		result, _, err := parseResponse[BinaryResponse](raw, &Prompt{Schema: schema})
+		if err != nil {
+			t.Fatalf("lenient parse failed: %v", err)
+		}
+		if !result.Decision {
+			t.Error("expected decision true")
+		}
+	})
+
+	t.Run("strict rejects extra fields", func(t *testing.T) {
+		_, _, err := parseResponse[BinaryResponse](raw, &Prompt{Schema: schema, Strict: true})
+		if err == nil {
+			t.Fatal("expected strict parse to fail")
+		}
+		if !strings.Contains(err.Error(), "unexpected fields in response: extra, padding") {
+			t.Errorf("error should list unexpected fields, got: %v", err)
+		}
+	})
+
+	t.Run("strict accepts exact fields", func(t *testing.T) {
+		_, _, err := parseResponse[BinaryResponse](canonicalResponses[0].json, &Prompt{Schema: schema, Strict: true})
+		if err != nil {
+			t.Errorf("strict parse failed: %v", err)
+		}
+	})
+
+	t.Run("strict applies to yaml", func(t *testing.T) {
+		yamlRaw := canonicalResponses[0].yaml + "padding: x\n"
+		_, _, err := parseResponse[BinaryResponse](yamlRaw, &Prompt{Schema: schema, Strict: true, Format: OutputFormatYAML})
+		if err == nil || !strings.Contains(err.Error(), "padding") {
+			t.Errorf("expected unexpected field error, got: %v", err)
+		}
+	})
+}
+
+func TestWithStrictSchema(t *testing.T) {
+	var captured string
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		captured = prompt
+		return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"], "note": "extra"}`, nil
+	})
+
+	synapse, err := Binary("Is this valid?", provider, WithStrictSchema())
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	session := NewSession()
+	_, err = synapse.Fire(context.Background(), session, "test")
+	if err == nil {
+		t.Fatal("expected strict mode to reject extra field")
+	}
+	if !strings.Contains(err.Error(), "note") {
+		t.Errorf("error should name the extra field, got: %v", err)
+	}
+	if session.Len() != 0 {
+		t.Error("session should not be updated on strict parse failure")
+	}
+
+	// All properties are required in the rendered schema
+	if !strings.Contains(captured, `"confidence",`) || !strings.Contains(captured, `"reasoning"`) {
+		t.Errorf("prompt should contain strict schema:\n%s", captured)
+	}
+}
+
+func TestWithStrictSchema_InvalidSchema(t *testing.T) {
+	pipeline := WithStrictSchema()(pipz.Transform(testID, func(_ context.Context, req *SynapseRequest) *SynapseRequest {
+		return req
+	}))
+
+	req, err := pipeline.Process(context.Background(), &SynapseRequest{Prompt: &Prompt{Schema: "not json"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Prompt.Strict || req.Prompt.Schema != "not json" {
+		t.Errorf("expected the prompt unchanged, got strict=%t %s", req.Prompt.Strict, req.Prompt.Schema)
+	}
+}
+
+func TestParseResponse_KeyValueSharedDefinitions(t *testing.T) {
+	schema, err := generateJSONSchema[SchemaInvoice]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+
+	raw := "ordered: [{\"sku\": \"A\", \"quantity\": 2}]\nshipped: {\"sku\": \"A\", \"quantity\": 1}\nprimary.sku: B\nprimary.quantity: 3"
+	result, _, err := parseResponse[SchemaInvoice](raw, &Prompt{Format: OutputFormatKeyValue, Schema: schema})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Ordered) != 1 || result.Ordered[0].Quantity != 2 {
+		t.Errorf("unexpected ordered items: %+v", result.Ordered)
+	}
+	if len(result.Shipped) != 1 || result.Shipped[0].SKU != "A" {
+		t.Errorf("unexpected shipped items: %+v", result.Shipped)
+	}
+	if result.Primary.SKU != "B" || result.Primary.Quantity != 3 {
+		t.Errorf("unexpected primary item: %+v", result.Primary)
+	}
+}
@@ -3,11 +3,13 @@ package gemini
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/capitan"
@@ -21,6 +23,10 @@ type Provider struct {
 	baseURL    string
 	httpClient *http.Client
 	name       string
+	vision     bool
+	httpConfig zyn.HTTPConfig
+	httpErr    error       // Invalid HTTPConfig, reported by every call
+	closed     atomic.Bool // Set by Close
 }
 
 // Config holds configuration for the Gemini provider.
@@ -28,10 +34,15 @@ type Config struct {
 	APIKey  string
 	Model   string        // e.g. "gemini-1.5-flash", "gemini-1.5-pro"
 	BaseURL string        // Optional, defaults to "https://generativelanguage.googleapis.com/v1beta"
-	Timeout time.Duration // Optional, defaults to 30s
+	Timeout time.Duration // Optional, defaults to 30s; unused with HTTPConfig.HTTPClient
+	Vision  bool          // Optional, set when the model accepts image inputs
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
 }
 
 // New creates a new Gemini provider.
+// If config.Headers would override a credential header, every call fails
+// with the error from config.HTTPConfig.Validate.
 func New(config Config) *Provider {
 	if config.Model == "" {
 		config.Model = "gemini-1.5-flash"
@@ -44,13 +55,14 @@ func New(config Config) *Provider {
 	}
 
 	return &Provider{
-		apiKey:  config.APIKey,
-		model:   config.Model,
-		baseURL: config.BaseURL,
-		name:    "gemini",
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		apiKey:     config.APIKey,
+		model:      config.Model,
+		baseURL:    config.BaseURL,
+		name:       "gemini",
+		vision:     config.Vision,
+		httpConfig: config.HTTPConfig,
+		httpErr:    config.HTTPConfig.Validate(),
+		httpClient: config.HTTPConfig.Client(config.Timeout),
 	}
 }
 
@@ -59,15 +71,37 @@ func (p *Provider) Name() string {
 	return p.name
 }
 
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
 // Call sends messages to Gemini and returns the response with usage stats.
 func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
 	startTime := time.Now()
 
 	// Emit provider.call.started hook
-	capitan.Info(ctx, zyn.ProviderCallStarted,
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
 		zyn.ProviderKey.Field(p.name),
 		zyn.ModelKey.Field(p.model),
-	)
+	)...)
 
 	// Extract system messages and conversation messages
 	var systemParts []string
@@ -82,10 +116,8 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 				role = "model"
 			}
 			contents = append(contents, content{
-				Role: role,
-				Parts: []part{
-					{Text: msg.Content},
-				},
+				Role:  role,
+				Parts: messageParts(msg),
 			})
 		}
 	}
@@ -120,6 +152,7 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	p.httpConfig.Apply(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Make the request
@@ -154,17 +187,20 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 				zyn.APIErrorTypeKey.Field(fmt.Sprintf("%d", errorResp.Error.Code)),
 			)
 
-			capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+			capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
 
 			// Check for rate limit
 			if resp.StatusCode == http.StatusTooManyRequests {
-				return nil, fmt.Errorf("rate limit exceeded: %s", errorResp.Error.Message)
+				return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, errorResp.Error.Message)
 			}
 			return nil, fmt.Errorf("gemini error (%d): %s", resp.StatusCode, errorResp.Error.Message)
 		}
 
 		fields = append(fields, zyn.ErrorKey.Field(fmt.Sprintf("status %d", resp.StatusCode)))
-		capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: status %d", zyn.ErrRateLimited, resp.StatusCode)
+		}
 		return nil, fmt.Errorf("gemini error: status %d", resp.StatusCode)
 	}
 
@@ -215,14 +251,16 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		fields = append(fields, zyn.ResponseFinishReasonKey.Field(candidate.FinishReason))
 	}
 
-	capitan.Info(ctx, zyn.ProviderCallCompleted, fields...)
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
 
 	return &zyn.ProviderResponse{
-		Content: textContent,
+		Content:   textContent,
+		Truncated: candidate.FinishReason == "MAX_TOKENS",
 		Usage: zyn.TokenUsage{
-			Prompt:     promptTokens,
-			Completion: completionTokens,
-			Total:      totalTokens,
+			Prompt:       promptTokens,
+			Completion:   completionTokens,
+			Total:        totalTokens,
+			CachedPrompt: generateResp.UsageMetadata.CachedContentTokenCount,
 		},
 	}, nil
 }
@@ -241,7 +279,37 @@ type content struct {
 }
 
 type part struct {
-	Text string `json:"text"`
+	Text       string    `json:"text,omitempty"`
+	InlineData *blobData `json:"inlineData,omitempty"`
+	FileData   *fileData `json:"fileData,omitempty"`
+}
+
+type blobData struct {
+	MIMEType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+type fileData struct {
+	MIMEType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+// messageParts converts a message's text and attached images to parts.
+// Images given by URL are sent as file references, others inline.
+func messageParts(msg zyn.Message) []part {
+	parts := make([]part, 0, len(msg.Images)+1)
+	parts = append(parts, part{Text: msg.Content})
+	for _, image := range msg.Images {
+		if image.URL != "" {
+			parts = append(parts, part{FileData: &fileData{MIMEType: image.MIME, FileURI: image.URL}})
+			continue
+		}
+		parts = append(parts, part{InlineData: &blobData{
+			MIMEType: image.MIME,
+			Data:     base64.StdEncoding.EncodeToString(image.Data),
+		}})
+	}
+	return parts
 }
 
 type generationConfig struct {
@@ -264,9 +332,10 @@ type candidate struct {
 }
 
 type usageMetadata struct {
-	PromptTokenCount     int `json:"promptTokenCount"`
-	CandidatesTokenCount int `json:"candidatesTokenCount"`
-	TotalTokenCount      int `json:"totalTokenCount"`
+	PromptTokenCount        int `json:"promptTokenCount"`
+	CandidatesTokenCount    int `json:"candidatesTokenCount"`
+	TotalTokenCount         int `json:"totalTokenCount"`
+	CachedContentTokenCount int `json:"cachedContentTokenCount"`
 }
 
 type errorResponse struct {
@@ -3,10 +3,12 @@ package gemini
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/zoobzio/zyn"
@@ -55,9 +57,10 @@ func TestProviderCall(t *testing.T) {
 				},
 			},
 			UsageMetadata: usageMetadata{
-				PromptTokenCount:     10,
-				CandidatesTokenCount: 5,
-				TotalTokenCount:      15,
+				PromptTokenCount:        10,
+				CandidatesTokenCount:    5,
+				TotalTokenCount:         15,
+				CachedContentTokenCount: 4,
 			},
 		}
 
@@ -92,6 +95,9 @@ func TestProviderCall(t *testing.T) {
 	if response.Usage.Total != 15 {
 		t.Errorf("Expected 15 total tokens, got %d", response.Usage.Total)
 	}
+	if response.Usage.CachedPrompt != 4 {
+		t.Errorf("Expected 4 cached prompt tokens, got %d", response.Usage.CachedPrompt)
+	}
 }
 
 func TestGeminiIntegration(t *testing.T) {
@@ -385,3 +391,187 @@ func TestNoSystemInstruction(t *testing.T) {
 		t.Fatalf("Call failed: %v", err)
 	}
 }
+
+func TestProviderCapabilities(t *testing.T) {
+	if New(Config{APIKey: "test-key"}).Capabilities().Vision {
+		t.Error("Expected vision to be off by default")
+	}
+	if !New(Config{APIKey: "test-key", Vision: true}).Capabilities().Vision {
+		t.Error("Expected vision when configured")
+	}
+	if model := New(Config{APIKey: "test-key", Model: "gemini-1.5-pro"}).Capabilities().Model; model != "gemini-1.5-pro" {
+		t.Errorf("Expected configured model, got %q", model)
+	}
+}
+
+func TestImageAttachments(t *testing.T) {
+	var req generateContentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "{}"}]}}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL, Vision: true})
+	_, err := provider.Call(context.Background(), []zyn.Message{
+		{Role: zyn.RoleUser, Content: "describe", Images: []zyn.ImageInput{
+			{URL: "https://example.com/a.png", MIME: "image/png"},
+			{Data: []byte("hi"), MIME: "image/png"},
+		}},
+	}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	parts := req.Contents[0].Parts
+	if len(parts) != 3 || parts[0].Text != "describe" {
+		t.Fatalf("Unexpected parts: %+v", parts)
+	}
+	if parts[1].FileData == nil || parts[1].FileData.FileURI != "https://example.com/a.png" {
+		t.Errorf("Unexpected URL image part: %+v", parts[1])
+	}
+	if parts[2].InlineData == nil || parts[2].InlineData.MIMEType != "image/png" || parts[2].InlineData.Data != "aGk=" {
+		t.Errorf("Unexpected inline image part: %+v", parts[2])
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	bodies := map[string]string{
+		"with error body": `{"error": {"message": "Too many requests", "type": "rate_limit_error"}}`,
+		"without body":    ``,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+
+			_, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if !errors.Is(err, zyn.ErrRateLimited) {
+				t.Errorf("Expected ErrRateLimited, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "ok"}]}, "finishReason": "STOP"}]}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	t.Run("default user agent", func(t *testing.T) {
+		provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != zyn.DefaultUserAgent() {
+			t.Errorf("Expected default User-Agent, got %q", got.Get("User-Agent"))
+		}
+	})
+
+	t.Run("custom headers", func(t *testing.T) {
+		provider := New(Config{
+			APIKey:  "test-key",
+			BaseURL: server.URL,
+			HTTPConfig: zyn.HTTPConfig{
+				UserAgent: "billing/2.3",
+				Headers:   map[string]string{"X-Team": "billing"},
+			},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != "billing/2.3" || got.Get("X-Team") != "billing" {
+			t.Errorf("Expected custom headers, got %v", got)
+		}
+	})
+
+	t.Run("rejects credential override", func(t *testing.T) {
+		got = nil
+		provider := New(Config{
+			APIKey:     "test-key",
+			BaseURL:    server.URL,
+			HTTPConfig: zyn.HTTPConfig{Headers: map[string]string{"X-Goog-Api-Key": "stolen"}},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err == nil || !strings.Contains(err.Error(), "credentials") {
+			t.Errorf("Expected credential header rejected, got %v", err)
+		}
+		if got != nil {
+			t.Error("Expected no request sent")
+		}
+	})
+}
+
+func TestTruncatedResponse(t *testing.T) {
+	bodies := map[string]string{
+		"truncated": `{"candidates": [{"content": {"parts": [{"text": "{\"decision\": tr"}]}, "finishReason": "MAX_TOKENS"}]}`,
+		"complete":  `{"candidates": [{"content": {"parts": [{"text": "{}"}]}, "finishReason": "STOP"}]}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+			response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if response.Truncated != (name == "truncated") {
+				t.Errorf("Expected truncated %t, got %t", name == "truncated", response.Truncated)
+			}
+		})
+	}
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates": [{"content": {"parts": [{"text": "ok"}]}, "finishReason": "STOP"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
@@ -0,0 +1,184 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zoobzio/pipz"
+)
+
+// GenerateInput contains rich input structure for generation.
+type GenerateInput struct {
+	Description string   // What the generated value should be like
+	Constraints []string // Additional requirements the value must meet
+	Count       int      // Number of values wanted; single-value calls ignore it
+	Temperature float32  // LLM temperature setting
+}
+
+// generated carries a generated value through the service, which requires a
+// Validator. It encodes as the bare value and validates with the value's own
+// Validate method, when it has one.
+type generated[T any] struct {
+	value T
+}
+
+// UnmarshalJSON decodes the bare value.
+func (g *generated[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &g.value)
+}
+
+// MarshalJSON encodes the bare value.
+func (g generated[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.value)
+}
+
+// Validate checks the value if T implements Validator, on a value or
+// pointer receiver.
+func (g generated[T]) Validate() error {
+	switch v := any(&g.value).(type) {
+	case Validator:
+		return v.Validate()
+	default:
+		return nil
+	}
+}
+
+// GenerateSynapse produces structured values of type T from a description.
+// Values are validated with T's Validate method when it has one.
+type GenerateSynapse[T any] struct {
+	what     string
+	schema   string // Pre-computed JSON schema for T
+	defaults GenerateInput
+	service  *Service[generated[T]]
+}
+
+// NewGenerate creates a new generation synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewGenerate[T any](what string, provider Provider, opts ...Option) (*GenerateSynapse[T], error) {
+	// Generate schema once at construction
+	schema, err := generateJSONSchema[T]()
+	if err != nil {
+		return nil, fmt.Errorf("generate synapse: %w", err)
+	}
+
+	// Apply options to build pipeline
+	pipeline := NewTerminal(provider)
+	for _, opt := range opts {
+		pipeline = opt(pipeline)
+	}
+
+	// Create service with final pipeline and default temperature
+	svc := NewService[generated[T]](pipeline, "generate", provider, DefaultTemperatureCreative)
+
+	return &GenerateSynapse[T]{
+		what:    what,
+		schema:  schema,
+		service: svc,
+	}, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+func (g *GenerateSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return g.service.GetPipeline()
+}
+
+// WithDefaults creates a new Generate with default input values.
+// These are merged with user input at execution time.
+func (g *GenerateSynapse[T]) WithDefaults(defaults GenerateInput) *GenerateSynapse[T] {
+	g.defaults = defaults
+	return g
+}
+
+// Fire generates a value matching description.
+func (g *GenerateSynapse[T]) Fire(ctx context.Context, session *Session, description string) (T, error) {
+	return g.FireWithInput(ctx, session, GenerateInput{Description: description})
+}
+
+// FireResult generates a value matching description and returns it in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (g *GenerateSynapse[T]) FireResult(ctx context.Context, session *Session, description string) (Result[T], error) {
+	merged := g.mergeInputs(GenerateInput{Description: description})
+	result, err := g.service.ExecuteResult(ctx, session, g.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		var zero T
+		return withValue(result, zero), fmt.Errorf("generation failed: %w", unwrapGenerated[T](err))
+	}
+	return withValue(result, result.Value.value), nil
+}
+
+// FireWithInput generates a value with rich input.
+func (g *GenerateSynapse[T]) FireWithInput(ctx context.Context, session *Session, input GenerateInput) (T, error) {
+	merged := g.mergeInputs(input)
+	result, err := g.service.Execute(ctx, session, g.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("generation failed: %w", unwrapGenerated[T](err))
+	}
+	return result.value, nil
+}
+
+// unwrapGenerated returns err with a validation failure of the internal
+// wrapper replaced by one carrying the bare value, so callers can match
+// *ValidationError[T].
+func unwrapGenerated[T any](err error) error {
+	if invalid, ok := err.(*ValidationError[generated[T]]); ok {
+		return &ValidationError[T]{Value: invalid.Value.value, Err: invalid.Err}
+	}
+	return err
+}
+
+// mergeInputs combines defaults with user input. Constraints from both are
+// kept, defaults first.
+func (g *GenerateSynapse[T]) mergeInputs(input GenerateInput) GenerateInput {
+	merged := g.defaults
+
+	if input.Description != "" {
+		merged.Description = input.Description
+	}
+	if len(input.Constraints) > 0 {
+		merged.Constraints = append(append([]string(nil), g.defaults.Constraints...), input.Constraints...)
+	}
+	if input.Count != 0 {
+		merged.Count = input.Count
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (g *GenerateSynapse[T]) buildPrompt(input GenerateInput) *Prompt {
+	prompt := &Prompt{
+		Task:   fmt.Sprintf("Generate %s", g.what),
+		Input:  input.Description,
+		Schema: g.schema,
+	}
+
+	// Prompts need input; a bare request generates from the task alone
+	if prompt.Input == "" {
+		prompt.Input = fmt.Sprintf("Any realistic %s", g.what)
+	}
+
+	prompt.Constraints = append([]string{
+		"Generate a single value matching the description",
+		"Fill every field with realistic, internally consistent data",
+		"Ensure output is valid JSON matching the schema",
+	}, input.Constraints...)
+
+	return prompt
+}
+
+// Generate creates a new generation synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	users, err := Generate[User]("a user account for tests", provider, WithValidationRetry(3))
+//	user, err := users.Fire(ctx, session, "an admin in Germany with two linked devices")
+func Generate[T any](what string, provider Provider, opts ...Option) (*GenerateSynapse[T], error) {
+	return NewGenerate[T](what, provider, opts...)
+}
@@ -0,0 +1,184 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// FixtureOrder is a generated test fixture with nested slices and maps.
+type FixtureOrder struct {
+	ID         string              `json:"id"`
+	Lines      []FixtureLine       `json:"lines"`
+	Tags       map[string]string   `json:"tags"`
+	Warehouses map[string][]string `json:"warehouses"`
+}
+
+// FixtureLine is a line of a FixtureOrder.
+type FixtureLine struct {
+	SKU      string   `json:"sku"`
+	Quantity int      `json:"quantity"`
+	Options  []string `json:"options"`
+}
+
+// Validate requires at least one line with a positive quantity.
+func (o FixtureOrder) Validate() error {
+	if len(o.Lines) == 0 {
+		return fmt.Errorf("order needs at least one line")
+	}
+	for i, line := range o.Lines {
+		if line.Quantity <= 0 {
+			return fmt.Errorf("line %d: quantity must be positive, got %d", i, line.Quantity)
+		}
+	}
+	return nil
+}
+
+// FixtureNote has no Validate method.
+type FixtureNote struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// fixtureOrderJSON is a valid FixtureOrder response.
+const fixtureOrderJSON = `{
+	"id": "ord_1",
+	"lines": [{"sku": "A-1", "quantity": 2, "options": ["gift wrap", "express"]}],
+	"tags": {"channel": "web"},
+	"warehouses": {"eu": ["ber", "ams"], "us": []}
+}`
+
+func TestGenerate_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return fixtureOrderJSON, nil
+	})
+	synapse, err := Generate[FixtureOrder]("an order fixture", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order, err := synapse.Fire(context.Background(), NewSession(), "a gift order shipped from Europe")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.ID != "ord_1" || len(order.Lines) != 1 || order.Lines[0].Options[1] != "express" {
+		t.Errorf("unexpected order: %+v", order)
+	}
+	if order.Tags["channel"] != "web" || len(order.Warehouses["eu"]) != 2 {
+		t.Errorf("expected the maps decoded, got %v and %v", order.Tags, order.Warehouses)
+	}
+	for _, want := range []string{"Generate an order fixture", "a gift order shipped from Europe", `"warehouses"`, `"options"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestGenerate_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"title": "Standup", "body": "Shipped the importer."}`, nil
+	})
+	synapse, _ := Generate[FixtureNote]("a meeting note", provider)
+	synapse.WithDefaults(GenerateInput{Constraints: []string{"Write in English"}})
+
+	note, err := synapse.FireWithInput(context.Background(), NewSession(), GenerateInput{
+		Description: "a daily standup",
+		Constraints: []string{"Keep the body under 20 words"},
+		Count:       5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if note.Title != "Standup" {
+		t.Errorf("unexpected note: %+v", note)
+	}
+	if !strings.Contains(prompt, "Write in English") || !strings.Contains(prompt, "Keep the body under 20 words") {
+		t.Errorf("expected both constraints in the prompt, got %s", prompt)
+	}
+
+	// Without a description the task alone drives generation
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), GenerateInput{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Any realistic a meeting note") {
+		t.Errorf("expected a default input, got %s", prompt)
+	}
+}
+
+func TestGenerate_Validates(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"id": "ord_1", "lines": [{"sku": "A-1", "quantity": 0}]}`)
+	synapse, _ := Generate[FixtureOrder]("an order fixture", provider)
+
+	session := NewSession()
+	_, err := synapse.Fire(context.Background(), session, "an order")
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "quantity must be positive") {
+		t.Errorf("expected the order's validation error, got %v", err)
+	}
+	var invalid *ValidationError[FixtureOrder]
+	if !errors.As(err, &invalid) || invalid.Value.ID != "ord_1" || len(invalid.Value.Lines) != 1 {
+		t.Errorf("expected the generated order on the error, got %v", err)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected the session untouched, got %d messages", session.Len())
+	}
+}
+
+func TestGenerate_ValidationRetry(t *testing.T) {
+	var prompts []string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompts = append(prompts, p)
+		if len(prompts) == 1 {
+			return `{"id": "ord_1", "lines": []}`, nil
+		}
+		return fixtureOrderJSON, nil
+	})
+	synapse, _ := Generate[FixtureOrder]("an order fixture", provider, WithValidationRetry(3))
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "an order")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value.Lines) != 1 || result.Attempts != 2 {
+		t.Errorf("expected the second response after 2 attempts, got %+v", result)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "order needs at least one line") {
+		t.Errorf("expected the rejection fed back, got %v", prompts)
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	provider := NewMockProviderWithResponse(`not json`)
+	synapse, _ := Generate[FixtureNote]("a meeting note", provider)
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "a note")
+	if !errors.Is(err, ErrParseFailed) {
+		t.Errorf("expected ErrParseFailed, got %v", err)
+	}
+	if result.Value != (FixtureNote{}) {
+		t.Errorf("expected the zero value, got %+v", result.Value)
+	}
+}
+
+func TestGenerated_JSON(t *testing.T) {
+	var value generated[FixtureNote]
+	if err := json.Unmarshal([]byte(`{"title": "a", "body": "b"}`), &value); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := json.Marshal(value)
+	if string(encoded) != `{"title":"a","body":"b"}` {
+		t.Errorf("expected the bare value, got %s", encoded)
+	}
+	if err := value.Validate(); err != nil {
+		t.Errorf("expected a value without Validate accepted, got %v", err)
+	}
+	if err := (generated[FixtureOrder]{}).Validate(); err == nil {
+		t.Error("expected the value's Validate used")
+	}
+}
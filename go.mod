@@ -9,6 +9,7 @@ require (
 	github.com/zoobzio/capitan v1.0.0
 	github.com/zoobzio/pipz v1.0.4
 	github.com/zoobzio/sentinel v1.0.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/zoobzio/clockz v1.0.0 // indirect
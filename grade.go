@@ -0,0 +1,338 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// gradeOverallProperty is the property of GradeResponse computed from the
+// criterion scores rather than asked of the LLM.
+const gradeOverallProperty = "overall"
+
+// Criterion is one part of a rubric a submission is graded on.
+type Criterion struct {
+	Name        string  // Key of the criterion's score in GradeResponse.PerCriterion
+	Description string  // What the criterion rewards
+	Weight      float64 // Relative weight in the overall score; 0 counts as 1
+}
+
+// weight returns the criterion's weight, treating 0 as 1.
+func (c Criterion) weight() float64 {
+	if c.Weight == 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+// GradeInput contains rich input structure for grading.
+type GradeInput struct {
+	Submission  string      // The work to grade
+	Reference   string      // Optional reference answer to grade against
+	Criteria    []Criterion // The criteria to score
+	Context     string      // Optional background, such as the task the submission answers
+	Temperature float32     // LLM temperature setting for this specific request
+}
+
+// GradeResponse contains the response from a grade synapse.
+// Overall is computed from the criterion scores and weights, not by the LLM.
+type GradeResponse struct {
+	Overall      float64            `json:"overall"`       // Weighted average of the criterion scores
+	PerCriterion map[string]float64 `json:"per_criterion"` // Score per criterion, 0.0 to 1.0
+	Feedback     []string           `json:"feedback"`      // Points for improving the submission
+	Confidence   float64            `json:"confidence"`    // 0.0 to 1.0 confidence score
+	Reasoning    []string           `json:"reasoning"`     // Explanation of the scores
+}
+
+// Validate checks if the response is valid. Criterion names are checked
+// against the input by the synapse.
+func (r GradeResponse) Validate() error {
+	if len(r.PerCriterion) == 0 {
+		return fmt.Errorf("per_criterion required but empty")
+	}
+	for name, score := range r.PerCriterion {
+		if score < 0 || score > 1 {
+			return fmt.Errorf("criterion %q: score must be 0-1, got %f", name, score)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// WithWeights returns the response with Overall computed as the weighted
+// average of its scores for criteria, so scores averaged across several
+// judges, or reweighted later, can be totaled without calling the LLM again.
+// Criteria without a score are left out.
+func (r GradeResponse) WithWeights(criteria []Criterion) GradeResponse {
+	var total, weights float64
+	for _, criterion := range criteria {
+		if score, ok := r.PerCriterion[criterion.Name]; ok {
+			total += criterion.weight() * score
+			weights += criterion.weight()
+		}
+	}
+	r.Overall = 0
+	if weights > 0 {
+		r.Overall = total / weights
+	}
+	return r
+}
+
+// checkCriteria rejects input without a submission or criteria, or whose
+// criteria share a name or have a negative weight.
+func checkCriteria(input GradeInput) error {
+	if input.Submission == "" {
+		return fmt.Errorf("%w: grade synapse needs a submission", ErrInvalidPrompt)
+	}
+	if len(input.Criteria) == 0 {
+		return fmt.Errorf("%w: grade synapse needs criteria", ErrInvalidPrompt)
+	}
+	seen := make(map[string]bool, len(input.Criteria))
+	for _, criterion := range input.Criteria {
+		key := strings.ToLower(strings.TrimSpace(criterion.Name))
+		if key == "" {
+			return fmt.Errorf("%w: criterion name required but empty", ErrInvalidPrompt)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate criterion %q", ErrInvalidPrompt, criterion.Name)
+		}
+		seen[key] = true
+		if criterion.Weight < 0 {
+			return fmt.Errorf("%w: criterion %q: weight must not be negative, got %f", ErrInvalidPrompt, criterion.Name, criterion.Weight)
+		}
+	}
+	return nil
+}
+
+// checkScores checks that the response scores every criterion and only
+// those, matching names regardless of case.
+func checkScores(criteria []Criterion, response GradeResponse) error {
+	for name := range response.PerCriterion {
+		if criterionName(criteria, name) == "" {
+			return fmt.Errorf("unknown criterion %q", name)
+		}
+	}
+	scores := canonicalCriterionScores(criteria, response.PerCriterion)
+	if len(scores) != len(response.PerCriterion) {
+		return fmt.Errorf("criterion scored more than once")
+	}
+	for _, criterion := range criteria {
+		if _, ok := scores[criterion.Name]; !ok {
+			return fmt.Errorf("criterion %q not scored", criterion.Name)
+		}
+	}
+	return nil
+}
+
+// criterionName returns the name of the criterion matching name regardless
+// of case and surrounding space, or "" if there is none.
+func criterionName(criteria []Criterion, name string) string {
+	name = strings.TrimSpace(name)
+	for _, criterion := range criteria {
+		if strings.EqualFold(criterion.Name, name) {
+			return criterion.Name
+		}
+	}
+	return ""
+}
+
+// canonicalCriterionScores returns scores keyed by the criteria's names,
+// dropping unknown names.
+func canonicalCriterionScores(criteria []Criterion, scores map[string]float64) map[string]float64 {
+	canonical := make(map[string]float64, len(scores))
+	for name, score := range scores {
+		if criterion := criterionName(criteria, name); criterion != "" {
+			canonical[criterion] = score
+		}
+	}
+	return canonical
+}
+
+// GradeSynapse scores submissions against a rubric, criterion by criterion.
+type GradeSynapse struct {
+	rubric   string
+	schema   string // Response schema without the computed overall score
+	defaults GradeInput
+	base     *Base[GradeInput, GradeResponse]
+}
+
+// NewGrade creates a new grade synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewGrade(rubric string, provider Provider, opts ...Option) (*GradeSynapse, error) {
+	synapse := &GradeSynapse{rubric: rubric}
+
+	base, err := NewSynapse(SynapseConfig[GradeInput, GradeResponse]{
+		Type:        "grade",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The LLM only scores criteria; the overall score is computed from them
+	schema, err := omitProperty(base.Schema(), gradeOverallProperty)
+	if err != nil {
+		return nil, fmt.Errorf("grade synapse: %w", err)
+	}
+
+	synapse.schema = schema
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (g *GradeSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return g.base.GetPipeline()
+}
+
+// WithDefaults creates a new Grade with default input values, typically the
+// rubric's criteria. These are merged with user input at execution time.
+func (g *GradeSynapse) WithDefaults(defaults GradeInput) *GradeSynapse {
+	g.defaults = defaults
+	return g
+}
+
+// Fire grades submission against the default criteria and returns the
+// overall score.
+func (g *GradeSynapse) Fire(ctx context.Context, session *Session, submission string) (float64, error) {
+	response, err := g.FireWithInput(ctx, session, GradeInput{Submission: submission})
+	if err != nil {
+		return 0, err
+	}
+	return response.Overall, nil
+}
+
+// FireResult grades submission against the default criteria and returns the
+// overall score in a Result envelope carrying the call's usage, timing, and
+// request metadata.
+func (g *GradeSynapse) FireResult(ctx context.Context, session *Session, submission string) (Result[float64], error) {
+	result, err := g.execute(ctx, session, GradeInput{Submission: submission})
+	if err != nil {
+		return withValue(result, 0.0), err
+	}
+	return withValue(result, result.Value.Overall), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (g *GradeSynapse) FireWithInput(ctx context.Context, session *Session, input GradeInput) (GradeResponse, error) {
+	result, err := g.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, grading the
+// input against the default criteria. The returned Validator is a
+// GradeResponse.
+func (g *GradeSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := g.FireWithInput(ctx, session, GradeInput{
+		Submission:  input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, scores each criterion, and
+// computes the overall score from the weights.
+func (g *GradeSynapse) execute(ctx context.Context, session *Session, input GradeInput) (Result[GradeResponse], error) {
+	merged := g.mergeInputs(input)
+	if err := checkCriteria(merged); err != nil {
+		return Result[GradeResponse]{Provider: g.base.service.providerName}, err
+	}
+
+	result, err := g.base.service.executeChecked(ctx, session, g.buildPrompt(merged), merged.Temperature, func(response GradeResponse) error {
+		return checkScores(merged.Criteria, response)
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Value.PerCriterion = canonicalCriterionScores(merged.Criteria, result.Value.PerCriterion)
+	result.Value = result.Value.WithWeights(merged.Criteria)
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input.
+func (g *GradeSynapse) mergeInputs(input GradeInput) GradeInput {
+	merged := g.defaults
+
+	if input.Submission != "" {
+		merged.Submission = input.Submission
+	}
+	if input.Reference != "" {
+		merged.Reference = input.Reference
+	}
+	if len(input.Criteria) > 0 {
+		merged.Criteria = input.Criteria
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. Weights are not
+// part of the prompt, so reweighting never changes the scores.
+func (g *GradeSynapse) buildPrompt(input GradeInput) *Prompt {
+	background := input.Context
+	if input.Reference != "" {
+		if background != "" {
+			background += "\n\n"
+		}
+		background += "Reference answer:\n" + input.Reference
+	}
+
+	constraints := []string{
+		"per_criterion: a score from 0.0 (not met) to 1.0 (fully met) for every criterion below, keyed by its exact name",
+	}
+	for _, criterion := range input.Criteria {
+		constraints = append(constraints, fmt.Sprintf("criterion %q: %s", criterion.Name, criterion.Description))
+	}
+	constraints = append(constraints,
+		"score each criterion independently of the others",
+		"feedback: specific, actionable points for improving the submission",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining the scores",
+	)
+	if input.Reference != "" {
+		constraints = append(constraints, "grade against the reference answer, but credit correct work that differs from it")
+	}
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Grade the submission against this rubric: %s", g.rubric),
+		Input:       input.Submission,
+		Context:     background,
+		Schema:      g.schema,
+		Constraints: constraints,
+	}
+}
+
+// Grade creates a new grade synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	judge, err := Grade("answers to customer questions", provider)
+//	judge.WithDefaults(GradeInput{Criteria: []Criterion{
+//	    {Name: "accuracy", Description: "facts match the reference", Weight: 2},
+//	    {Name: "tone", Description: "friendly and professional"},
+//	}})
+//	score, err := judge.Fire(ctx, session, reply)
+func Grade(rubric string, provider Provider, opts ...Option) (*GradeSynapse, error) {
+	return NewGrade(rubric, provider, opts...)
+}
@@ -0,0 +1,191 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// replyCriteria are the criteria of the grade tests.
+var replyCriteria = []Criterion{
+	{Name: "accuracy", Description: "facts match the reference", Weight: 3},
+	{Name: "tone", Description: "friendly and professional"},
+}
+
+func TestGradeResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := GradeResponse{
+			PerCriterion: map[string]float64{"accuracy": 0.5},
+			Confidence:   0.8,
+			Reasoning:    []string{"partly right"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_scores", func(t *testing.T) {
+		r := GradeResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"partly right"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no scores")
+		}
+	})
+
+	t.Run("score_too_high", func(t *testing.T) {
+		r := GradeResponse{
+			PerCriterion: map[string]float64{"accuracy": 1.2},
+			Confidence:   0.8,
+			Reasoning:    []string{"partly right"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a score > 1")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := GradeResponse{
+			PerCriterion: map[string]float64{"accuracy": 0.5},
+			Confidence:   2,
+			Reasoning:    []string{"partly right"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := GradeResponse{
+			PerCriterion: map[string]float64{"accuracy": 0.5},
+			Confidence:   0.8,
+			Reasoning:    []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestGradeResponse_WithWeights(t *testing.T) {
+	response := GradeResponse{PerCriterion: map[string]float64{"accuracy": 1, "tone": 0.5}}
+
+	if overall := response.WithWeights(replyCriteria).Overall; math.Abs(overall-0.875) > 1e-9 {
+		t.Errorf("expected weighted overall 0.875, got %f", overall)
+	}
+	if overall := response.WithWeights([]Criterion{{Name: "tone", Weight: 2}, {Name: "speed"}}).Overall; overall != 0.5 {
+		t.Errorf("expected unscored criteria left out, got %f", overall)
+	}
+	if overall := response.WithWeights(nil).Overall; overall != 0 {
+		t.Errorf("expected 0 without criteria, got %f", overall)
+	}
+}
+
+func TestGradeSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"overall": 0.99, "per_criterion": {"Accuracy": 0.6, "tone": 1.0}, "feedback": ["cite the policy"], "confidence": 0.8, "reasoning": ["x"]}`, nil
+	})
+	judge, err := Grade("support replies", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := judge.FireWithInput(context.Background(), NewSession(), GradeInput{
+		Submission: "Refunds take 5 days.",
+		Reference:  "Refunds take 3-5 business days.",
+		Criteria:   replyCriteria,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(response.Overall-0.7) > 1e-9 {
+		t.Errorf("expected overall computed from the weights as 0.7, not the claimed 0.99, got %f", response.Overall)
+	}
+	if response.PerCriterion["accuracy"] != 0.6 || len(response.PerCriterion) != 2 {
+		t.Errorf("expected scores keyed by criterion names, got %v", response.PerCriterion)
+	}
+	for _, want := range []string{"support replies", `criterion "accuracy": facts match the reference`, "Reference answer:\nRefunds take 3-5 business days."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, `"overall"`) || strings.Contains(prompt, "Weight") {
+		t.Errorf("expected neither the overall score nor weights in the prompt, got %s", prompt)
+	}
+}
+
+func TestGradeSynapse_Fire(t *testing.T) {
+	judge, _ := Grade("support replies", NewMockProviderWithResponse(`{"overall": 0.99, "per_criterion": {"accuracy": 1, "tone": 0.5}, "feedback": ["cite the policy"], "confidence": 0.8, "reasoning": ["x"]}`))
+	judge.WithDefaults(GradeInput{Criteria: replyCriteria})
+
+	score, err := judge.Fire(context.Background(), NewSession(), "Refunds take 5 days.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(score-0.875) > 1e-9 {
+		t.Errorf("expected 0.875, got %f", score)
+	}
+}
+
+func TestGradeSynapse_Scores(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   string
+		expected string
+	}{
+		{"missing criterion", `"accuracy": 0.9`, `criterion "tone" not scored`},
+		{"unknown criterion", `"accuracy": 0.9, "tone": 0.9, "length": 0.2`, `unknown criterion "length"`},
+		{"scored twice", `"accuracy": 0.9, "Accuracy": 0.8, "tone": 0.9`, "scored more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge, _ := Grade("support replies", NewMockProviderWithResponse(`{"overall": 0.99, "per_criterion": {`+tt.scores+`}, "feedback": ["cite the policy"], "confidence": 0.8, "reasoning": ["x"]}`))
+
+			_, err := judge.FireWithInput(context.Background(), NewSession(), GradeInput{Submission: "reply", Criteria: replyCriteria})
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestGradeSynapse_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input GradeInput
+	}{
+		{"no submission", GradeInput{Criteria: replyCriteria}},
+		{"no criteria", GradeInput{Submission: "reply"}},
+		{"unnamed criterion", GradeInput{Submission: "reply", Criteria: []Criterion{{Description: "x"}}}},
+		{"duplicate criterion", GradeInput{Submission: "reply", Criteria: []Criterion{{Name: "tone"}, {Name: "Tone"}}}},
+		{"negative weight", GradeInput{Submission: "reply", Criteria: []Criterion{{Name: "tone", Weight: -1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			judge, _ := Grade("support replies", NewMockProviderWithName("mock"))
+
+			_, err := judge.FireWithInput(context.Background(), NewSession(), tt.input)
+			if !errors.Is(err, ErrInvalidPrompt) {
+				t.Errorf("expected ErrInvalidPrompt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGradeSynapse_Invoke(t *testing.T) {
+	judge, _ := Grade("support replies", NewMockProviderWithResponse(`{"overall": 0.99, "per_criterion": {"accuracy": 1, "tone": 0.5}, "feedback": ["cite the policy"], "confidence": 0.8, "reasoning": ["x"]}`))
+	judge.WithDefaults(GradeInput{Criteria: replyCriteria})
+
+	validator, err := judge.Invoke(context.Background(), NewSession(), SynapseInput{Input: "reply"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(GradeResponse); !ok || math.Abs(response.Overall-0.875) > 1e-9 {
+		t.Errorf("expected GradeResponse with computed overall, got %#v", validator)
+	}
+}
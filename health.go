@@ -0,0 +1,54 @@
+package zyn
+
+import (
+	"context"
+	"sync"
+)
+
+// HealthChecker is an optional interface for providers that can check they
+// are reachable and accept their credentials without calling a model.
+type HealthChecker interface {
+	// Ping returns nil when the provider is ready to serve calls.
+	Ping(ctx context.Context) error
+}
+
+// CheckProviders pings every provider that implements HealthChecker,
+// concurrently, and returns each one's result by name: nil for healthy
+// providers, the Ping error otherwise. Providers without health checks are
+// left out. When providers share a name, a failure is kept over a success.
+//
+// Example:
+//
+//	for name, err := range zyn.CheckProviders(ctx, primary, fallback) {
+//	    if err != nil {
+//	        log.Fatalf("provider %s unavailable: %v", name, err)
+//	    }
+//	}
+func CheckProviders(ctx context.Context, providers ...Provider) map[string]error {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]error, len(providers))
+	)
+
+	for _, provider := range providers {
+		checker, ok := provider.(HealthChecker)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := checker.Ping(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if previous, seen := results[provider.Name()]; !seen || previous == nil {
+				results[provider.Name()] = err
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,49 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// pingProvider is a MockProvider whose Ping returns err.
+type pingProvider struct {
+	*MockProvider
+	err error
+}
+
+func (p *pingProvider) Ping(context.Context) error { return p.err }
+
+func TestCheckProviders(t *testing.T) {
+	down := NewMockProviderWithName("down")
+	down.SetAvailable(false)
+	failure := errors.New("unauthorized")
+
+	results := CheckProviders(context.Background(),
+		NewMockProviderWithName("up"),
+		down,
+		NewMockProviderWithResponse(`{}`),
+		&pingProvider{MockProvider: NewMockProviderWithName("shared"), err: failure},
+		&pingProvider{MockProvider: NewMockProviderWithName("shared")},
+	)
+
+	if len(results) != 3 {
+		t.Fatalf("expected results for providers with health checks, got %v", results)
+	}
+	if err, ok := results["up"]; !ok || err != nil {
+		t.Errorf("expected up to be healthy, got %v", err)
+	}
+	if results["down"] == nil {
+		t.Error("expected down to fail")
+	}
+	if !errors.Is(results["shared"], failure) {
+		t.Errorf("expected the failure to win for a shared name, got %v", results["shared"])
+	}
+	if _, ok := results[MockFixedProviderName]; ok {
+		t.Error("expected providers without health checks to be left out")
+	}
+
+	if results := CheckProviders(context.Background()); len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
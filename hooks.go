@@ -11,15 +11,20 @@ var (
 	ProviderCallCompleted = capitan.NewSignal("llm.provider.call.completed", "LLM provider HTTP call succeeded with token usage and timing metrics")
 	ProviderCallFailed    = capitan.NewSignal("llm.provider.call.failed", "LLM provider HTTP call failed with status code and API error details")
 	ResponseParseFailed   = capitan.NewSignal("llm.response.failed", "LLM response parsing failed with validation or JSON decode error")
+	PromptSizeWarning     = capitan.NewSignal("llm.prompt.size.warning", "Estimated LLM prompt size exceeds the warning threshold")
+	AuditWriteFailed      = capitan.NewSignal("llm.audit.write.failed", "Audit log record could not be written")
+	CacheHit              = capitan.NewSignal("llm.cache.hit", "Cached LLM response served without calling the provider")
+	ProviderRouted        = capitan.NewSignal("llm.provider.routed", "Routing provider chose the provider for an LLM call")
 )
 
 // Keys for hook event fields.
 var (
 	// Request identification.
-	RequestIDKey   = capitan.NewStringKey("llm.request.id")
-	SynapseTypeKey = capitan.NewStringKey("llm.synapse.type")
-	PromptTaskKey  = capitan.NewStringKey("llm.prompt.task")
-	TemperatureKey = capitan.NewFloat64Key("llm.temperature")
+	RequestIDKey       = capitan.NewStringKey("llm.request.id")
+	ParentRequestIDKey = capitan.NewStringKey("llm.request.parent_id") // Caller's ID from ContextWithRequestID; absent when none was set
+	SynapseTypeKey     = capitan.NewStringKey("llm.synapse.type")
+	PromptTaskKey      = capitan.NewStringKey("llm.prompt.task")
+	TemperatureKey     = capitan.NewFloat64Key("llm.temperature")
 
 	// Input/Output data.
 	InputKey  = capitan.NewStringKey("llm.input")
@@ -40,8 +45,21 @@ var (
 	PromptTokensKey     = capitan.NewIntKey("llm.tokens.prompt")
 	CompletionTokensKey = capitan.NewIntKey("llm.tokens.completion")
 	TotalTokensKey      = capitan.NewIntKey("llm.tokens.total")
+	ReasoningTokensKey  = capitan.NewIntKey("llm.tokens.reasoning")
 	DurationMsKey       = capitan.NewIntKey("llm.duration.ms")
 
+	// Prompt size estimation.
+	EstimatedTokensKey = capitan.NewIntKey("llm.tokens.estimated")
+	TokenLimitKey      = capitan.NewIntKey("llm.tokens.limit")
+	TokenThresholdKey  = capitan.NewIntKey("llm.tokens.threshold")
+
+	// Consensus backend position, carried as metadata by each backend's events.
+	ConsensusBackendKey = capitan.NewStringKey("llm.consensus.backend")
+
+	// Routing provider fields.
+	RoutedProviderKey = capitan.NewStringKey("llm.route.provider")
+	RouteFallbackKey  = capitan.NewBoolKey("llm.route.fallback")
+
 	// HTTP/API metadata.
 	HTTPStatusCodeKey = capitan.NewIntKey("llm.http.status.code")
 	APIErrorTypeKey   = capitan.NewStringKey("llm.api.error.type")
@@ -51,4 +69,5 @@ var (
 	ResponseIDKey           = capitan.NewStringKey("llm.response.id")
 	ResponseFinishReasonKey = capitan.NewStringKey("llm.response.finish.reason")
 	ResponseCreatedKey      = capitan.NewIntKey("llm.response.created")
+	SystemFingerprintKey    = capitan.NewStringKey("llm.response.system.fingerprint")
 )
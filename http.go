@@ -0,0 +1,121 @@
+package zyn
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Version is the zyn release, reported in the default User-Agent.
+const Version = "0.1.0"
+
+// protectedHeaders carry provider credentials and are set by the provider
+// itself. HTTPConfig.Headers may not override them.
+var protectedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+}
+
+// HTTPConfig configures the requests sent by HTTP-based providers.
+// Providers embed it in their Config.
+//
+// Example:
+//
+//	provider := openai.New(openai.Config{
+//	    APIKey: key,
+//	    HTTPConfig: zyn.HTTPConfig{
+//	        UserAgent: "billing-service/2.3",
+//	        Headers:   map[string]string{"X-Team": "billing"},
+//	    },
+//	})
+type HTTPConfig struct {
+	UserAgent string            // Optional, defaults to DefaultUserAgent()
+	Headers   map[string]string // Optional, added to every request; may not include credential headers
+
+	// HTTPClient sends every request when set, for proxies, mTLS, tracing or
+	// record/replay transports. It is used as is: the provider's Timeout
+	// does not apply, so set the client's own Timeout if needed.
+	HTTPClient *http.Client
+
+	// LogWriter receives an HTTPLogRecord as a line of JSON for every
+	// request sent and response received, for debugging what went over the
+	// wire. Credential headers, such as Authorization, and credential query
+	// parameters, such as key, are redacted; bodies are logged as sent.
+	// Nil disables logging.
+	LogWriter io.Writer
+
+	// LogBodyLimit truncates logged bodies to this many bytes. Zero logs
+	// them whole.
+	LogBodyLimit int
+}
+
+// DefaultUserAgent returns the User-Agent sent when HTTPConfig.UserAgent is
+// empty, such as "zyn/0.1.0 go/1.24.1".
+func DefaultUserAgent() string {
+	return "zyn/" + Version + " go/" + strings.TrimPrefix(runtime.Version(), "go")
+}
+
+// Validate reports an error if Headers would override a credential header,
+// such as Authorization, or includes an empty name.
+func (c HTTPConfig) Validate() error {
+	for name := range c.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("http config: empty header name")
+		}
+		if protectedHeader(name) {
+			return fmt.Errorf("http config: header %q carries provider credentials and cannot be set", name)
+		}
+	}
+	return nil
+}
+
+// Apply sets the User-Agent and custom headers on req. Credential headers in
+// Headers are skipped even when Validate was not called.
+func (c HTTPConfig) Apply(req *http.Request) {
+	userAgent := c.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for name, value := range c.Headers {
+		if !protectedHeader(name) {
+			req.Header.Set(name, value)
+		}
+	}
+}
+
+// Client returns HTTPClient, or a new client with the given timeout when
+// HTTPClient is nil. With a LogWriter, it returns a copy of the client whose
+// transport logs every request.
+func (c HTTPConfig) Client(timeout time.Duration) *http.Client {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+	if c.LogWriter == nil {
+		return client
+	}
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	logged := *client
+	logged.Transport = &loggingTransport{next: next, w: c.LogWriter, bodyLimit: c.LogBodyLimit}
+	return &logged
+}
+
+// protectedHeader reports whether name is a credential header.
+func protectedHeader(name string) bool {
+	for _, protected := range protectedHeaders {
+		if strings.EqualFold(strings.TrimSpace(name), protected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package zyn
+
+import (
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultUserAgent(t *testing.T) {
+	expected := "zyn/" + Version + " go/" + strings.TrimPrefix(runtime.Version(), "go")
+	if got := DefaultUserAgent(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestHTTPConfig_Validate(t *testing.T) {
+	valid := HTTPConfig{Headers: map[string]string{"X-Team": "billing", "X-Trace": "abc"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"Authorization", "authorization", " AUTHORIZATION ", "x-api-key", "X-Goog-Api-Key", "Proxy-Authorization", ""} {
+		config := HTTPConfig{Headers: map[string]string{name: "value"}}
+		if err := config.Validate(); err == nil {
+			t.Errorf("expected header %q rejected", name)
+		}
+	}
+}
+
+func TestHTTPConfig_Apply(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+		HTTPConfig{}.Apply(req)
+		if req.Header.Get("User-Agent") != DefaultUserAgent() {
+			t.Errorf("expected default User-Agent, got %q", req.Header.Get("User-Agent"))
+		}
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+		req.Header.Set("Authorization", "Bearer real")
+		HTTPConfig{
+			UserAgent: "billing/2.3",
+			Headers:   map[string]string{"X-Team": "billing", "authorization": "Bearer stolen"},
+		}.Apply(req)
+
+		if req.Header.Get("User-Agent") != "billing/2.3" || req.Header.Get("X-Team") != "billing" {
+			t.Errorf("expected custom headers, got %v", req.Header)
+		}
+		if req.Header.Get("Authorization") != "Bearer real" {
+			t.Errorf("expected credentials untouched, got %q", req.Header.Get("Authorization"))
+		}
+	})
+}
+
+func TestHTTPConfig_Client(t *testing.T) {
+	client := HTTPConfig{}.Client(5 * time.Second)
+	if client == nil || client.Timeout != 5*time.Second {
+		t.Errorf("expected default client with timeout, got %+v", client)
+	}
+
+	custom := &http.Client{}
+	if got := (HTTPConfig{HTTPClient: custom}).Client(5 * time.Second); got != custom {
+		t.Error("expected custom client")
+	}
+	if custom.Timeout != 0 {
+		t.Errorf("expected custom client unchanged, got timeout %v", custom.Timeout)
+	}
+}
@@ -0,0 +1,187 @@
+package zyn
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// credentialQueryParams carry credentials in request URLs, such as Gemini's
+// key parameter. HTTP logs redact their values.
+var credentialQueryParams = []string{"key", "api_key", "apikey", "access_token", "token"}
+
+// credentialHeaderWords mark custom credential headers, such as Azure
+// OpenAI's api-key. HTTP logs redact headers whose names contain them, as
+// well as the headers in protectedHeaders.
+var credentialHeaderWords = []string{"auth", "key", "token", "secret"}
+
+// HTTPLogRecord is one line of an HTTP log, written as JSON for each
+// request a provider sends. Credential headers and query parameters are
+// replaced by "[REDACTED]".
+type HTTPLogRecord struct {
+	Timestamp       time.Time         `json:"timestamp"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	RequestBody     string            `json:"request_body,omitempty"`
+	StatusCode      int               `json:"status_code,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	DurationMs      int64             `json:"duration_ms"`
+	Error           string            `json:"error,omitempty"` // Transport or body read error
+}
+
+// loggingTransport writes an HTTPLogRecord for every round trip of next.
+type loggingTransport struct {
+	next      http.RoundTripper
+	mu        sync.Mutex // Serializes writes to w
+	w         io.Writer
+	bodyLimit int
+}
+
+// RoundTrip sends req with next and logs the exchange. The response body is
+// read in full before it is returned, so the caller reads a copy.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := HTTPLogRecord{
+		Timestamp:      time.Now(),
+		Method:         req.Method,
+		URL:            redactURL(req.URL),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := requestBody(req)
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		record.RequestBody = t.truncate(body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		record.DurationMs = time.Since(start).Milliseconds()
+		record.Error = err.Error()
+		t.write(record)
+		return nil, err
+	}
+
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	record.DurationMs = time.Since(start).Milliseconds()
+	record.StatusCode = resp.StatusCode
+	record.ResponseHeaders = redactHeaders(resp.Header)
+	record.ResponseBody = t.truncate(body)
+	if readErr != nil {
+		record.Error = readErr.Error()
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), &errorReader{readErr}))
+	} else {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	t.write(record)
+	return resp, nil
+}
+
+// truncate returns body, cut to the body limit when one is set.
+func (t *loggingTransport) truncate(body []byte) string {
+	if t.bodyLimit <= 0 || len(body) <= t.bodyLimit {
+		return string(body)
+	}
+	return fmt.Sprintf("%s...[%d bytes truncated]", body[:t.bodyLimit], len(body)-t.bodyLimit)
+}
+
+// write appends record to the log as one line. Write errors are dropped:
+// logging never fails a call.
+func (t *loggingTransport) write(record HTTPLogRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(line) //nolint:errcheck // logging is best effort
+}
+
+// requestBody returns a copy of req's body, leaving req readable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// redactHeaders flattens header for logging, redacting credential headers.
+func redactHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		if credentialHeader(name) {
+			redacted[name] = redactedText
+		} else {
+			redacted[name] = strings.Join(values, ", ")
+		}
+	}
+	return redacted
+}
+
+// credentialHeader reports whether name may carry credentials.
+func credentialHeader(name string) bool {
+	if protectedHeader(name) {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, word := range credentialHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns u with credential query parameters and user info
+// redacted.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redactedText)
+	}
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for name := range query {
+			for _, param := range credentialQueryParams {
+				if strings.EqualFold(name, param) {
+					query.Set(name, redactedText)
+				}
+			}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// errorReader returns err from every read.
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
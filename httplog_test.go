@@ -0,0 +1,186 @@
+package zyn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readHTTPLog decodes every line of an HTTP log.
+func readHTTPLog(t *testing.T, buf *bytes.Buffer) []HTTPLogRecord {
+	t.Helper()
+	var records []HTTPLogRecord
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record HTTPLogRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestHTTPConfig_LogWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req_123")
+		w.Write([]byte(`{"echo": ` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := HTTPConfig{LogWriter: &buf}.Client(5 * time.Second)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/models/m:generate?key=sk-query-secret&alt=json", strings.NewReader(`{"prompt":"hello"}`))
+	req.Header.Set("Authorization", "Bearer sk-header-secret")
+	req.Header.Set("X-Api-Key", "sk-anthropic-secret")
+	req.Header.Set("Api-Key", "sk-azure-secret")
+	req.Header.Set("X-Team", "billing")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"echo": {"prompt":"hello"}}` {
+		t.Errorf("expected the response body intact for the caller, got %s", body)
+	}
+
+	logged := buf.String()
+	for _, secret := range []string{"sk-query-secret", "sk-header-secret", "sk-anthropic-secret", "sk-azure-secret"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %s redacted, got %s", secret, logged)
+		}
+	}
+
+	records := readHTTPLog(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	record := records[0]
+	for _, name := range []string{"Authorization", "X-Api-Key", "Api-Key"} {
+		if record.RequestHeaders[name] != "[REDACTED]" {
+			t.Errorf("expected %s redacted, got %q", name, record.RequestHeaders[name])
+		}
+	}
+	if record.RequestHeaders["X-Team"] != "billing" || record.ResponseHeaders["X-Request-Id"] != "req_123" {
+		t.Errorf("expected other headers logged, got %v and %v", record.RequestHeaders, record.ResponseHeaders)
+	}
+	if !strings.Contains(record.URL, "key=%5BREDACTED%5D") || !strings.Contains(record.URL, "alt=json") {
+		t.Errorf("expected the key parameter redacted, got %s", record.URL)
+	}
+	if record.Method != http.MethodPost || record.StatusCode != http.StatusOK {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if record.RequestBody != `{"prompt":"hello"}` || record.ResponseBody != `{"echo": {"prompt":"hello"}}` {
+		t.Errorf("expected bodies logged, got %q and %q", record.RequestBody, record.ResponseBody)
+	}
+}
+
+func TestHTTPConfig_LogBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(strings.Repeat("r", 100)))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := HTTPConfig{LogWriter: &buf, LogBodyLimit: 10}.Client(5 * time.Second)
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(strings.Repeat("q", 30)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(body) != 100 {
+		t.Errorf("expected the whole response for the caller, got %d bytes", len(body))
+	}
+
+	record := readHTTPLog(t, &buf)[0]
+	if record.RequestBody != "qqqqqqqqqq...[20 bytes truncated]" {
+		t.Errorf("unexpected request body: %q", record.RequestBody)
+	}
+	if record.ResponseBody != "rrrrrrrrrr...[90 bytes truncated]" {
+		t.Errorf("unexpected response body: %q", record.ResponseBody)
+	}
+}
+
+func TestHTTPConfig_LogWriterDisabled(t *testing.T) {
+	if client := (HTTPConfig{}).Client(5 * time.Second); client.Transport != nil {
+		t.Errorf("expected no logging transport, got %T", client.Transport)
+	}
+
+	custom := &http.Client{Timeout: time.Second}
+	logged := HTTPConfig{HTTPClient: custom, LogWriter: io.Discard}.Client(5 * time.Second)
+	if logged == custom || custom.Transport != nil {
+		t.Error("expected the custom client copied, not modified")
+	}
+	if logged.Timeout != time.Second {
+		t.Errorf("expected the custom client's timeout, got %v", logged.Timeout)
+	}
+}
+
+func TestHTTPConfig_LogWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	client := HTTPConfig{LogWriter: &buf}.Client(time.Second)
+	client.Transport.(*loggingTransport).next = roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:1/models", nil)
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("expected the transport error")
+	}
+
+	record := readHTTPLog(t, &buf)[0]
+	if record.Error != "connection refused" || record.StatusCode != 0 {
+		t.Errorf("expected the error logged, got %+v", record)
+	}
+	if record.RequestHeaders["Authorization"] != "[REDACTED]" {
+		t.Errorf("expected credentials redacted, got %q", record.RequestHeaders["Authorization"])
+	}
+}
+
+func TestHTTPConfig_LogWriterConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := HTTPConfig{LogWriter: &buf}.Client(5 * time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if records := readHTTPLog(t, &buf); len(records) != 20 {
+		t.Errorf("expected 20 records, got %d", len(records))
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
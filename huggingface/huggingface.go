@@ -0,0 +1,375 @@
+// Package huggingface provides a zyn Provider for the Hugging Face serverless
+// Inference API and Inference Endpoints.
+package huggingface
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+// Provider implements the zyn Provider interface for Hugging Face chat
+// completion routes.
+type Provider struct {
+	apiKey     string
+	model      string
+	url        string
+	httpClient *http.Client
+	name       string
+	vision     bool
+	httpConfig zyn.HTTPConfig
+	httpErr    error       // Invalid HTTPConfig, reported by every call
+	closed     atomic.Bool // Set by Close
+}
+
+// Config holds configuration for the Hugging Face provider.
+type Config struct {
+	APIKey      string
+	Model       string        // Hub model ID, e.g. "meta-llama/Llama-3.1-8B-Instruct"; defaults to it on the serverless API
+	EndpointURL string        // Optional, a dedicated Inference Endpoint; when set, calls go there instead of the serverless API
+	BaseURL     string        // Optional, defaults to "https://api-inference.huggingface.co"; unused with EndpointURL
+	Timeout     time.Duration // Optional, defaults to 60s; unused with HTTPConfig.HTTPClient
+	Vision      bool          // Optional, set when the model accepts image inputs
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
+}
+
+// New creates a new Hugging Face provider.
+//
+// Calls go to EndpointURL + "/v1/chat/completions" when config.EndpointURL
+// is set, and otherwise to the serverless route
+// BaseURL + "/models/{Model}/v1/chat/completions".
+// If config.Headers would override a credential header, every call fails
+// with the error from config.HTTPConfig.Validate.
+func New(config Config) *Provider {
+	config.EndpointURL = strings.TrimRight(config.EndpointURL, "/")
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Model == "" && config.EndpointURL == "" {
+		config.Model = "meta-llama/Llama-3.1-8B-Instruct"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api-inference.huggingface.co"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 60 * time.Second
+	}
+
+	url := config.BaseURL + "/models/" + config.Model + "/v1/chat/completions"
+	if config.EndpointURL != "" {
+		url = config.EndpointURL + "/v1/chat/completions"
+	}
+
+	return &Provider{
+		apiKey:     config.APIKey,
+		model:      config.Model,
+		url:        url,
+		name:       "huggingface",
+		vision:     config.Vision,
+		httpConfig: config.HTTPConfig,
+		httpErr:    config.HTTPConfig.Validate(),
+		httpClient: config.HTTPConfig.Client(config.Timeout),
+	}
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Call sends messages to Hugging Face and returns the response.
+//
+// A model that is still loading answers 503 with an estimated load time;
+// such calls fail with a retryable *zyn.ProviderUnavailableError whose
+// RetryAfter is that estimate, which WithRetry and WithBackoff wait out.
+// Requests the server rejects as invalid (422) fail with
+// zyn.ErrNotRetryable. Responses without usage return a zero
+// zyn.TokenUsage.
+func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
+	startTime := time.Now()
+
+	// Emit provider.call.started hook
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(p.model),
+	)...)
+
+	apiMessages := make([]requestMessage, len(messages))
+	for i, msg := range messages {
+		apiMessages[i] = newRequestMessage(msg)
+	}
+
+	requestBody := chatCompletionRequest{
+		Model:    p.model,
+		Messages: apiMessages,
+	}
+	if requestBody.Model == "" {
+		// Inference Endpoints serve one model and accept any name
+		requestBody.Model = "tgi"
+	}
+	// Text Generation Inference rejects a temperature of 0; leaving it
+	// unset decodes greedily
+	if temperature > 0 {
+		requestBody.Temperature = &temperature
+	}
+	if params, ok := zyn.CallParamsFromContext(ctx); ok {
+		requestBody.Seed = params.Seed
+		requestBody.MaxTokens = params.MaxTokens
+		requestBody.TopP = params.TopP
+		requestBody.Stop = params.Stop
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.httpConfig.Apply(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		duration := time.Since(startTime)
+
+		detail := fmt.Sprintf("status %d", resp.StatusCode)
+		var errorResp errorResponse
+		if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.text() != "" {
+			detail = errorResp.text()
+		}
+
+		// Emit provider.call.failed hook
+		fields := []capitan.Field{
+			zyn.ProviderKey.Field(p.name),
+			zyn.ModelKey.Field(p.model),
+			zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+			zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+			zyn.ErrorKey.Field(detail),
+		}
+		if errorResp.ErrorType != "" {
+			fields = append(fields, zyn.APIErrorTypeKey.Field(errorResp.ErrorType))
+		}
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
+
+		switch resp.StatusCode {
+		case http.StatusServiceUnavailable:
+			return nil, &zyn.ProviderUnavailableError{
+				Provider:   p.name,
+				RetryAfter: retryAfter(errorResp.EstimatedTime, resp.Header.Get("Retry-After")),
+				Err:        fmt.Errorf("huggingface error (%d): %s", resp.StatusCode, detail),
+			}
+		case http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, detail)
+		case http.StatusUnprocessableEntity:
+			return nil, fmt.Errorf("%w: huggingface error (%d): %s", zyn.ErrNotRetryable, resp.StatusCode, detail)
+		}
+		return nil, fmt.Errorf("huggingface error (%d): %s", resp.StatusCode, detail)
+	}
+
+	var completionResp chatCompletionResponse
+	if err := json.Unmarshal(body, &completionResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(completionResp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned")
+	}
+
+	duration := time.Since(startTime)
+
+	model := completionResp.Model
+	if model == "" || model == "tgi" {
+		model = p.model
+	}
+
+	// Emit provider.call.completed hook, with token counts when reported
+	fields := []capitan.Field{
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(model),
+		zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+		zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+	}
+	var usage zyn.TokenUsage
+	if completionResp.Usage != nil {
+		usage = zyn.TokenUsage{
+			Prompt:     completionResp.Usage.PromptTokens,
+			Completion: completionResp.Usage.CompletionTokens,
+			Total:      completionResp.Usage.TotalTokens,
+		}
+		fields = append(fields,
+			zyn.PromptTokensKey.Field(usage.Prompt),
+			zyn.CompletionTokensKey.Field(usage.Completion),
+			zyn.TotalTokensKey.Field(usage.Total),
+		)
+	}
+	if completionResp.ID != "" {
+		fields = append(fields, zyn.ResponseIDKey.Field(completionResp.ID))
+	}
+	if completionResp.Created != 0 {
+		fields = append(fields, zyn.ResponseCreatedKey.Field(int(completionResp.Created)))
+	}
+	if completionResp.Choices[0].FinishReason != "" {
+		fields = append(fields, zyn.ResponseFinishReasonKey.Field(completionResp.Choices[0].FinishReason))
+	}
+
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
+
+	return &zyn.ProviderResponse{
+		Content:   completionResp.Choices[0].Message.Content,
+		Truncated: completionResp.Choices[0].FinishReason == "length",
+		Usage:     usage,
+	}, nil
+}
+
+// retryAfter returns the wait before a loading model is expected to be
+// ready: the estimated load time from the error body in seconds, or else
+// the Retry-After header's seconds.
+func retryAfter(estimatedTime float64, header string) time.Duration {
+	if estimatedTime > 0 {
+		return time.Duration(estimatedTime * float64(time.Second))
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// Request/Response types for the Hugging Face chat completion API
+
+type chatCompletionRequest struct {
+	Model       string           `json:"model"`
+	Messages    []requestMessage `json:"messages"`
+	Temperature *float32         `json:"temperature,omitempty"`
+	Seed        *int             `json:"seed,omitempty"`
+	MaxTokens   int              `json:"max_tokens,omitempty"`
+	TopP        float32          `json:"top_p,omitempty"`
+	Stop        []string         `json:"stop,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// requestMessage is a message sent to the API. Content is a string, or a list
+// of content parts when images are attached.
+type requestMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// newRequestMessage converts a zyn.Message, sending attached images as
+// image_url content parts after the text.
+func newRequestMessage(msg zyn.Message) requestMessage {
+	if len(msg.Images) == 0 {
+		return requestMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	parts := make([]contentPart, 0, len(msg.Images)+1)
+	parts = append(parts, contentPart{Type: "text", Text: msg.Content})
+	for _, image := range msg.Images {
+		parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: image.DataURL()}})
+	}
+	return requestMessage{Role: msg.Role, Content: parts}
+}
+
+type chatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []choice `json:"choices"`
+	Usage   *usage   `json:"usage"` // Missing from some deployments
+}
+
+type choice struct {
+	Index        int     `json:"index"`
+	Message      message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+}
+
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// errorResponse is the error body. Error is a plain string from the
+// serverless API and Text Generation Inference, or an object with a message
+// from OpenAI-compatible routes.
+type errorResponse struct {
+	Error         json.RawMessage `json:"error"`
+	ErrorType     string          `json:"error_type"`
+	EstimatedTime float64         `json:"estimated_time"` // Seconds until a loading model is ready
+}
+
+// text returns the error message.
+func (e errorResponse) text() string {
+	var message string
+	if err := json.Unmarshal(e.Error, &message); err == nil {
+		return message
+	}
+	var detail struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(e.Error, &detail); err == nil {
+		return detail.Message
+	}
+	return ""
+}
@@ -0,0 +1,369 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+// coldStart is the serverless API's answer while a model is loading.
+const coldStart = `{"error": "Model meta-llama/Llama-3.1-8B-Instruct is currently loading", "estimated_time": 0.05}`
+
+// binaryAnswer is a chat completion answering a Binary synapse, without
+// usage as some deployments send it.
+const binaryAnswer = `{
+	"object": "chat.completion",
+	"created": 1760601600,
+	"model": "meta-llama/Llama-3.1-8B-Instruct",
+	"choices": [{"index": 0, "message": {"role": "assistant", "content": "{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"The order shipped\"]}"}, "finish_reason": "stop"}]
+}`
+
+func TestProviderCall(t *testing.T) {
+	ctx := context.Background()
+	// Create a test server that mimics the serverless Inference API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/meta-llama/Llama-3.1-8B-Instruct/v1/chat/completions" {
+			t.Errorf("Expected serverless chat completion route, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer hf_test" {
+			t.Errorf("Expected Bearer token, got %s", r.Header.Get("Authorization"))
+		}
+
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.Model != "meta-llama/Llama-3.1-8B-Instruct" {
+			t.Errorf("Expected model meta-llama/Llama-3.1-8B-Instruct, got %s", req.Model)
+		}
+		if req.Temperature == nil || *req.Temperature != 0.7 {
+			t.Errorf("Expected temperature 0.7, got %v", req.Temperature)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "",
+			"object": "chat.completion",
+			"created": 1760601600,
+			"model": "meta-llama/Llama-3.1-8B-Instruct",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "test response"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+		}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var provider string
+	var total int
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		provider, _ = zyn.ProviderKey.From(e)
+		total, _ = zyn.TotalTokensKey.From(e)
+	})
+	defer listener.Close()
+
+	hf := New(Config{APIKey: "hf_test", BaseURL: server.URL + "/"})
+
+	response, err := hf.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test prompt"}}, 0.7)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if response.Content != "test response" {
+		t.Errorf("Expected 'test response', got '%s'", response.Content)
+	}
+	if response.Usage != (zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15}) {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+
+	wg.Wait()
+	if provider != "huggingface" || total != 15 {
+		t.Errorf("Unexpected hook fields: provider %q, total %d", provider, total)
+	}
+}
+
+func TestProviderEndpointURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Expected endpoint chat completion route, got %s", r.URL.Path)
+		}
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "tgi" {
+			t.Errorf("Expected placeholder model for an endpoint, got %s", req.Model)
+		}
+		if req.Temperature != nil {
+			t.Errorf("Expected no temperature for 0, got %v", *req.Temperature)
+		}
+		w.Write([]byte(`{"model": "tgi", "choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	hf := New(Config{APIKey: "hf_test", EndpointURL: server.URL + "/"})
+	if _, err := hf.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if hf.Capabilities().Model != "" {
+		t.Errorf("Expected no model for an endpoint, got %q", hf.Capabilities().Model)
+	}
+}
+
+func TestProviderWithoutUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(binaryAnswer))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var hasTokens bool
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		_, hasTokens = zyn.TotalTokensKey.From(e)
+	})
+	defer listener.Close()
+
+	synapse, _ := zyn.Binary("Has the order shipped?", New(Config{APIKey: "hf_test", BaseURL: server.URL}))
+	session := zyn.NewSession()
+	result, err := synapse.FireResult(context.Background(), session, "Order A-1 shipped")
+	if err != nil {
+		t.Fatalf("Fire failed: %v", err)
+	}
+	if !result.Value || result.Usage == nil || *result.Usage != (zyn.TokenUsage{}) {
+		t.Errorf("Expected true with zero usage, got %v and %+v", result.Value, result.Usage)
+	}
+	if session.Len() != 2 {
+		t.Errorf("Expected the exchange in the session, got %d messages", session.Len())
+	}
+
+	wg.Wait()
+	if hasTokens {
+		t.Error("Expected no token counts in hook when usage is missing")
+	}
+}
+
+func TestColdStart(t *testing.T) {
+	t.Run("estimated time", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "Model meta-llama/Llama-3.1-8B-Instruct is currently loading", "estimated_time": 20.0}`))
+		}))
+		defer server.Close()
+
+		_, err := New(Config{APIKey: "hf_test", BaseURL: server.URL}).Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+		var unavailable *zyn.ProviderUnavailableError
+		if !errors.As(err, &unavailable) {
+			t.Fatalf("Expected ProviderUnavailableError, got %v", err)
+		}
+		if unavailable.Provider != "huggingface" || unavailable.RetryAfter != 20*time.Second {
+			t.Errorf("Unexpected error fields: %+v", unavailable)
+		}
+		if !strings.Contains(err.Error(), "currently loading") || !strings.Contains(err.Error(), "retry after 20s") {
+			t.Errorf("Expected loading message and wait hint, got %v", err)
+		}
+		if errors.Is(err, zyn.ErrNotRetryable) {
+			t.Errorf("Expected a retryable error, got %v", err)
+		}
+	})
+
+	t.Run("retry after header", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "Service Unavailable"}`))
+		}))
+		defer server.Close()
+
+		_, err := New(Config{APIKey: "hf_test", BaseURL: server.URL}).Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+		var unavailable *zyn.ProviderUnavailableError
+		if !errors.As(err, &unavailable) || unavailable.RetryAfter != 30*time.Second {
+			t.Errorf("Expected a 30s wait hint, got %v", err)
+		}
+	})
+
+	t.Run("backoff waits for the model", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(coldStart))
+				return
+			}
+			w.Write([]byte(binaryAnswer))
+		}))
+		defer server.Close()
+
+		var wg sync.WaitGroup
+		var status int
+		var provider string
+		wg.Add(1)
+		listener := capitan.Hook(zyn.ProviderCallFailed, func(_ context.Context, e *capitan.Event) {
+			defer wg.Done()
+			status, _ = zyn.HTTPStatusCodeKey.From(e)
+			provider, _ = zyn.ProviderKey.From(e)
+		})
+		defer listener.Close()
+
+		synapse, _ := zyn.Binary("Has the order shipped?", New(Config{APIKey: "hf_test", BaseURL: server.URL}), zyn.WithBackoff(3, time.Millisecond))
+		start := time.Now()
+		decision, err := synapse.Fire(context.Background(), zyn.NewSession(), "Order A-1 shipped")
+		if err != nil {
+			t.Fatalf("Expected success once the model loaded, got %v", err)
+		}
+		if !decision {
+			t.Error("Expected true")
+		}
+		if elapsed := time.Since(start); calls.Load() != 2 || elapsed < 50*time.Millisecond {
+			t.Errorf("Expected a second call after the 50ms estimate, got %d calls in %v", calls.Load(), elapsed)
+		}
+
+		wg.Wait()
+		if status != http.StatusServiceUnavailable || provider != "huggingface" {
+			t.Errorf("Unexpected failed hook fields: status %d, provider %q", status, provider)
+		}
+	})
+}
+
+func TestProviderErrorHandling(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseBody  string
+		expectedError string
+		rateLimited   bool
+		notRetryable  bool
+	}{
+		{
+			name:          "Unauthorized",
+			statusCode:    http.StatusUnauthorized,
+			responseBody:  `{"error": "Invalid credentials in Authorization header"}`,
+			expectedError: "huggingface error (401): Invalid credentials in Authorization header",
+		},
+		{
+			name:          "Rate limited",
+			statusCode:    http.StatusTooManyRequests,
+			responseBody:  `{"error": "Rate limit reached. You reached free usage limit (reset hourly)."}`,
+			expectedError: "Rate limit reached",
+			rateLimited:   true,
+		},
+		{
+			name:          "Validation",
+			statusCode:    http.StatusUnprocessableEntity,
+			responseBody:  `{"error": "Input validation error: temperature must be strictly positive", "error_type": "validation"}`,
+			expectedError: "huggingface error (422): Input validation error",
+			notRetryable:  true,
+		},
+		{
+			name:          "Object error",
+			statusCode:    http.StatusBadRequest,
+			responseBody:  `{"error": {"message": "Model not supported by provider", "type": "invalid_request_error"}}`,
+			expectedError: "huggingface error (400): Model not supported by provider",
+		},
+		{
+			name:          "Empty body",
+			statusCode:    http.StatusInternalServerError,
+			expectedError: "huggingface error (500): status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			_, err := New(Config{APIKey: "hf_test", BaseURL: server.URL}).Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%s'", tt.expectedError, err.Error())
+			}
+			if errors.Is(err, zyn.ErrRateLimited) != tt.rateLimited {
+				t.Errorf("Expected rate limited %v, got %v", tt.rateLimited, err)
+			}
+			if errors.Is(err, zyn.ErrNotRetryable) != tt.notRetryable {
+				t.Errorf("Expected not retryable %v, got %v", tt.notRetryable, err)
+			}
+			if errors.Is(err, zyn.ErrProviderUnavailable) {
+				t.Errorf("Expected an available provider, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProviderDefaults(t *testing.T) {
+	hf := New(Config{APIKey: "hf_test"})
+	if hf.url != "https://api-inference.huggingface.co/models/meta-llama/Llama-3.1-8B-Instruct/v1/chat/completions" {
+		t.Errorf("Unexpected default URL: %s", hf.url)
+	}
+	if hf.httpClient.Timeout != 60*time.Second {
+		t.Errorf("Expected 60s default timeout, got %s", hf.httpClient.Timeout)
+	}
+	if hf.Name() != "huggingface" {
+		t.Errorf("Expected name 'huggingface', got '%s'", hf.Name())
+	}
+	if got := New(Config{Model: "Qwen/Qwen2-VL-7B-Instruct", Vision: true}).Capabilities(); got != (zyn.Capabilities{Vision: true, Model: "Qwen/Qwen2-VL-7B-Instruct"}) {
+		t.Errorf("Unexpected capabilities: %+v", got)
+	}
+}
+
+func TestHuggingFaceIntegration(t *testing.T) {
+	apiKey := os.Getenv("HF_TOKEN")
+	if apiKey == "" {
+		t.Skip("HF_TOKEN not set, skipping integration test")
+	}
+
+	synapse, _ := zyn.Binary("Is this a question?", New(Config{APIKey: apiKey}), zyn.WithBackoff(3, time.Second))
+	decision, err := synapse.Fire(context.Background(), zyn.NewSession(), "What time is it?")
+	if err != nil {
+		t.Fatalf("Fire failed: %v", err)
+	}
+
+	t.Logf("Decision: %v", decision)
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "tgi", "choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", EndpointURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
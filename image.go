@@ -0,0 +1,54 @@
+package zyn
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// imagesConstraint tells the model to use the images attached to the prompt.
+const imagesConstraint = "images: consider the attached images when answering"
+
+// ImageInput is an image attached to a synapse input. Give either a URL the
+// provider can fetch, or the raw Data with its MIME type.
+type ImageInput struct {
+	URL  string // Image URL, for images the provider can fetch
+	Data []byte // Raw image bytes, used when URL is empty
+	MIME string // MIME type of Data, e.g. "image/png"
+}
+
+// Validate checks that the image has exactly one source.
+func (i ImageInput) Validate() error {
+	switch {
+	case i.URL != "" && len(i.Data) > 0:
+		return fmt.Errorf("image must have a URL or data, not both")
+	case i.URL != "":
+		return nil
+	case len(i.Data) == 0:
+		return fmt.Errorf("image requires a URL or data")
+	case i.MIME == "":
+		return fmt.Errorf("image data requires a MIME type")
+	default:
+		return nil
+	}
+}
+
+// DataURL returns the image as a URL: URL when set, otherwise Data encoded
+// as a base64 data URL. Providers use it to build multi-part messages.
+func (i ImageInput) DataURL() string {
+	if i.URL != "" {
+		return i.URL
+	}
+	return fmt.Sprintf("data:%s;base64,%s", i.MIME, base64.StdEncoding.EncodeToString(i.Data))
+}
+
+// checkVision returns a *VisionUnsupportedError when the request carries
+// images and the provider does not advertise vision support.
+func checkVision(provider Provider, req *SynapseRequest) error {
+	if len(req.Prompt.Images) == 0 {
+		return nil
+	}
+	if capable, ok := provider.(CapabilitiesProvider); ok && capable.Capabilities().Vision {
+		return nil
+	}
+	return &VisionUnsupportedError{Provider: provider.Name(), Images: len(req.Prompt.Images)}
+}
@@ -0,0 +1,220 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// visionProvider fakes a provider with configurable vision support and
+// records the messages of its last call.
+type visionProvider struct {
+	vision   bool
+	response string
+	calls    int
+	messages []Message
+}
+
+func (p *visionProvider) Call(_ context.Context, messages []Message, _ float32) (*ProviderResponse, error) {
+	p.calls++
+	p.messages = messages
+	return &ProviderResponse{Content: p.response}, nil
+}
+
+func (*visionProvider) Name() string { return "vision" }
+
+func (p *visionProvider) Capabilities() Capabilities {
+	return Capabilities{Vision: p.vision}
+}
+
+// lastImages returns the images attached to the last message sent.
+func (p *visionProvider) lastImages() []ImageInput {
+	if len(p.messages) == 0 {
+		return nil
+	}
+	return p.messages[len(p.messages)-1].Images
+}
+
+func TestImageInput_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   ImageInput
+		wantErr bool
+	}{
+		{"url", ImageInput{URL: "https://example.com/a.png"}, false},
+		{"data", ImageInput{Data: []byte{0x89, 0x50}, MIME: "image/png"}, false},
+		{"empty", ImageInput{}, true},
+		{"both", ImageInput{URL: "https://example.com/a.png", Data: []byte{1}, MIME: "image/png"}, true},
+		{"data without mime", ImageInput{Data: []byte{1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.image.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageInput_DataURL(t *testing.T) {
+	if got := (ImageInput{URL: "https://example.com/a.png"}).DataURL(); got != "https://example.com/a.png" {
+		t.Errorf("expected URL unchanged, got %q", got)
+	}
+	if got := (ImageInput{Data: []byte("hi"), MIME: "image/png"}).DataURL(); got != "data:image/png;base64,aGk=" {
+		t.Errorf("unexpected data URL %q", got)
+	}
+}
+
+func TestPrompt_ValidateImages(t *testing.T) {
+	prompt := &Prompt{Task: "t", Schema: "{}", Images: []ImageInput{{URL: "https://example.com/a.png"}}}
+	if err := prompt.Validate(); err != nil {
+		t.Errorf("expected images to satisfy input requirement, got %v", err)
+	}
+
+	prompt.Images = append(prompt.Images, ImageInput{Data: []byte{1}})
+	if err := prompt.Validate(); err == nil || !strings.Contains(err.Error(), "image 1") {
+		t.Errorf("expected error for invalid image, got %v", err)
+	}
+}
+
+func TestCheckVision(t *testing.T) {
+	images := []ImageInput{{URL: "https://example.com/a.png"}}
+
+	t.Run("no images", func(t *testing.T) {
+		req := &SynapseRequest{Prompt: &Prompt{}}
+		if err := checkVision(NewMockProvider(), req); err != nil {
+			t.Errorf("expected no error without images, got %v", err)
+		}
+	})
+
+	t.Run("vision provider", func(t *testing.T) {
+		req := &SynapseRequest{Prompt: &Prompt{Images: images}}
+		if err := checkVision(&visionProvider{vision: true}, req); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("no vision", func(t *testing.T) {
+		req := &SynapseRequest{Prompt: &Prompt{Images: images}}
+		var target *VisionUnsupportedError
+		err := checkVision(&visionProvider{}, req)
+		if !errors.As(err, &target) || target.Provider != "vision" || target.Images != 1 {
+			t.Errorf("expected VisionUnsupportedError, got %v", err)
+		}
+	})
+
+	t.Run("no capabilities", func(t *testing.T) {
+		req := &SynapseRequest{Prompt: &Prompt{Images: images}}
+		if err := checkVision(NewMockProvider(), req); !errors.Is(err, ErrVisionUnsupported) {
+			t.Errorf("expected ErrVisionUnsupported, got %v", err)
+		}
+	})
+}
+
+func TestBinarySynapse_Images(t *testing.T) {
+	screenshot := ImageInput{Data: []byte("png"), MIME: "image/png"}
+
+	t.Run("attaches images", func(t *testing.T) {
+		provider := &visionProvider{vision: true, response: `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`}
+		synapse, err := Binary("Does this screenshot contain personal data?", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		_, err = synapse.FireWithInput(context.Background(), session, BinaryInput{
+			Subject: "settings page",
+			Images:  []ImageInput{screenshot},
+		})
+		if err != nil {
+			t.Fatalf("FireWithInput failed: %v", err)
+		}
+		if images := provider.lastImages(); len(images) != 1 || string(images[0].Data) != "png" {
+			t.Errorf("expected screenshot attached to user message, got %+v", images)
+		}
+		if !strings.Contains(provider.messages[len(provider.messages)-1].Content, imagesConstraint) {
+			t.Error("expected images constraint in prompt")
+		}
+		if messages := session.Messages(); len(messages) != 2 || len(messages[0].Images) != 0 {
+			t.Errorf("expected session to keep text only, got %+v", messages)
+		}
+	})
+
+	t.Run("images without subject", func(t *testing.T) {
+		provider := &visionProvider{vision: true, response: `{"decision": false, "confidence": 0.8, "reasoning": ["ok"]}`}
+		synapse, err := Binary("Is this image blurry?", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.FireWithInput(context.Background(), NewSession(), BinaryInput{Images: []ImageInput{screenshot}}); err != nil {
+			t.Errorf("expected images alone to be valid input, got %v", err)
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		provider := &visionProvider{response: `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`}
+		synapse, err := Binary("Does this screenshot contain personal data?", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		_, err = synapse.FireWithInput(context.Background(), NewSession(), BinaryInput{
+			Subject: "settings page",
+			Images:  []ImageInput{screenshot},
+		})
+		if !errors.Is(err, ErrVisionUnsupported) {
+			t.Errorf("expected ErrVisionUnsupported, got %v", err)
+		}
+		if provider.calls != 0 {
+			t.Errorf("expected provider not to be called, got %d calls", provider.calls)
+		}
+	})
+}
+
+func TestClassificationSynapse_Images(t *testing.T) {
+	photo := ImageInput{URL: "https://example.com/cat.jpg"}
+	response := `{"primary": "animal", "secondary": "", "confidence": 0.9, "reasoning": ["whiskers"]}`
+
+	t.Run("attaches images", func(t *testing.T) {
+		provider := &visionProvider{vision: true, response: response}
+		synapse, err := Classification("What is in the photo?", []string{"animal", "vehicle"}, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		result, err := synapse.FireWithInput(context.Background(), NewSession(), ClassificationInput{
+			Subject: "uploaded photo",
+			Images:  []ImageInput{photo},
+		})
+		if err != nil {
+			t.Fatalf("FireWithInput failed: %v", err)
+		}
+		if result.Primary != "animal" {
+			t.Errorf("expected animal, got %q", result.Primary)
+		}
+		if images := provider.lastImages(); len(images) != 1 || images[0].URL != photo.URL {
+			t.Errorf("expected photo attached to user message, got %+v", provider.lastImages())
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		provider := &visionProvider{response: response}
+		synapse, err := Classification("What is in the photo?", []string{"animal", "vehicle"}, provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		_, err = synapse.FireWithInput(context.Background(), NewSession(), ClassificationInput{
+			Subject: "uploaded photo",
+			Images:  []ImageInput{photo},
+		})
+		var target *VisionUnsupportedError
+		if !errors.As(err, &target) || target.Images != 1 {
+			t.Errorf("expected VisionUnsupportedError, got %v", err)
+		}
+		if provider.calls != 0 {
+			t.Errorf("expected provider not to be called, got %d calls", provider.calls)
+		}
+	})
+}
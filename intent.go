@@ -0,0 +1,354 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// SlotDef declares a parameter an intent carries.
+type SlotDef struct {
+	Name        string // Key of the slot in IntentResponse.Slots
+	Type        string // Kind of value, e.g. date, city, or number
+	Required    bool   // Whether the intent cannot be acted on without it
+	Description string // Optional explanation of the slot
+}
+
+// IntentDef declares an intent a message can express.
+type IntentDef struct {
+	Name        string    // The intent's name, returned as IntentResponse.Intent
+	Description string    // What a message with this intent asks for
+	Slots       []SlotDef // Parameters the intent carries
+}
+
+// slot returns the slot of the intent matching name regardless of case and
+// surrounding space, or false if there is none.
+func (d IntentDef) slot(name string) (SlotDef, bool) {
+	name = strings.TrimSpace(name)
+	for _, slot := range d.Slots {
+		if strings.EqualFold(slot.Name, name) {
+			return slot, true
+		}
+	}
+	return SlotDef{}, false
+}
+
+// IntentInput contains rich input structure for intent detection.
+type IntentInput struct {
+	Utterance   string  // The message to interpret
+	Context     string  // Optional context, such as the conversation so far
+	Temperature float32 // LLM temperature setting for this specific request
+}
+
+// IntentResponse contains the response from an intent synapse.
+type IntentResponse struct {
+	Intent     string            `json:"intent"`     // Name of the detected intent
+	Slots      map[string]string `json:"slots"`      // Slot values given by the message, by slot name
+	Missing    []string          `json:"missing"`    // Required slots the message does not give
+	Confidence float64           `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string          `json:"reasoning"`  // Explanation of the intent and slots
+}
+
+// Validate checks if the response is valid. The intent and slots are checked
+// against the declared intents by the synapse.
+func (r IntentResponse) Validate() error {
+	if strings.TrimSpace(r.Intent) == "" {
+		return fmt.Errorf("intent required but empty")
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// IntentSynapse detects the intent of a message and fills its slots.
+type IntentSynapse struct {
+	domain   string
+	intents  []IntentDef
+	defaults IntentInput
+	base     *Base[IntentInput, IntentResponse]
+}
+
+// NewIntent creates a new intent synapse bound to a provider.
+// Returns an error if no intents are given, an intent or slot name is empty
+// or declared twice, or the JSON schema cannot be generated.
+func NewIntent(domain string, intents []IntentDef, provider Provider, opts ...Option) (*IntentSynapse, error) {
+	if err := checkIntents(intents); err != nil {
+		return nil, fmt.Errorf("intent synapse: %w", err)
+	}
+	synapse := &IntentSynapse{domain: domain, intents: slices.Clone(intents)}
+
+	base, err := NewSynapse(SynapseConfig[IntentInput, IntentResponse]{
+		Type:        "intent",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = synapse.validateIntent
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// checkIntents rejects an empty intent list and empty or repeated intent and
+// slot names, comparing names regardless of case.
+func checkIntents(intents []IntentDef) error {
+	if len(intents) == 0 {
+		return fmt.Errorf("at least one intent is required")
+	}
+	seen := make(map[string]bool, len(intents))
+	for _, intent := range intents {
+		key := strings.ToLower(strings.TrimSpace(intent.Name))
+		if key == "" {
+			return fmt.Errorf("intent name required but empty")
+		}
+		if seen[key] {
+			return fmt.Errorf("intent %q declared twice", intent.Name)
+		}
+		seen[key] = true
+
+		slots := make(map[string]bool, len(intent.Slots))
+		for _, slot := range intent.Slots {
+			key := strings.ToLower(strings.TrimSpace(slot.Name))
+			if key == "" {
+				return fmt.Errorf("intent %q: slot name required but empty", intent.Name)
+			}
+			if slots[key] {
+				return fmt.Errorf("intent %q: slot %q declared twice", intent.Name, slot.Name)
+			}
+			slots[key] = true
+		}
+	}
+	return nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (i *IntentSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return i.base.GetPipeline()
+}
+
+// WithDefaults creates a new Intent with default input values.
+// These are merged with user input at execution time.
+func (i *IntentSynapse) WithDefaults(defaults IntentInput) *IntentSynapse {
+	i.defaults = defaults
+	return i
+}
+
+// Fire detects the intent of utterance and returns its name.
+func (i *IntentSynapse) Fire(ctx context.Context, session *Session, utterance string) (string, error) {
+	response, err := i.FireWithInput(ctx, session, IntentInput{Utterance: utterance})
+	if err != nil {
+		return "", err
+	}
+	return response.Intent, nil
+}
+
+// FireResult detects the intent of utterance and returns its name in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (i *IntentSynapse) FireResult(ctx context.Context, session *Session, utterance string) (Result[string], error) {
+	result, err := i.execute(ctx, session, IntentInput{Utterance: utterance})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Intent), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (i *IntentSynapse) FireWithInput(ctx context.Context, session *Session, input IntentInput) (IntentResponse, error) {
+	result, err := i.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is an IntentResponse.
+func (i *IntentSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := i.FireWithInput(ctx, session, IntentInput{
+		Utterance:   input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, detects the intent, and returns
+// the intent and slot names as declared.
+func (i *IntentSynapse) execute(ctx context.Context, session *Session, input IntentInput) (Result[IntentResponse], error) {
+	merged := i.mergeInputs(input)
+	result, err := i.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+	if err != nil {
+		return result, err
+	}
+	result.Value = i.canonical(result.Value)
+	return result, nil
+}
+
+// intent returns the declared intent matching name regardless of case and
+// surrounding space, or false if there is none.
+func (i *IntentSynapse) intent(name string) (IntentDef, bool) {
+	name = strings.TrimSpace(name)
+	for _, intent := range i.intents {
+		if strings.EqualFold(intent.Name, name) {
+			return intent, true
+		}
+	}
+	return IntentDef{}, false
+}
+
+// validateIntent checks that the response names a declared intent, fills
+// only its declared slots, and fills or lists as missing each required slot.
+func (i *IntentSynapse) validateIntent(_ *Prompt, response IntentResponse) error {
+	intent, ok := i.intent(response.Intent)
+	if !ok {
+		return fmt.Errorf("unknown intent %q", response.Intent)
+	}
+	filled := make(map[string]bool, len(response.Slots))
+	for name, value := range response.Slots {
+		slot, ok := intent.slot(name)
+		if !ok {
+			return fmt.Errorf("intent %q has no slot %q", intent.Name, name)
+		}
+		if strings.TrimSpace(value) != "" {
+			filled[slot.Name] = true
+		}
+	}
+	missing := make(map[string]bool, len(response.Missing))
+	for _, name := range response.Missing {
+		slot, ok := intent.slot(name)
+		if !ok {
+			return fmt.Errorf("intent %q has no slot %q", intent.Name, name)
+		}
+		missing[slot.Name] = true
+	}
+	for _, slot := range intent.Slots {
+		if slot.Required && !filled[slot.Name] && !missing[slot.Name] {
+			return fmt.Errorf("required slot %q is neither filled nor listed as missing", slot.Name)
+		}
+	}
+	return nil
+}
+
+// canonical returns a validated response with the intent and slot names as
+// declared, empty slot values dropped, and filled slots left out of Missing.
+func (i *IntentSynapse) canonical(response IntentResponse) IntentResponse {
+	intent, _ := i.intent(response.Intent)
+	response.Intent = intent.Name
+
+	slots := make(map[string]string, len(response.Slots))
+	for name, value := range response.Slots {
+		if slot, _ := intent.slot(name); strings.TrimSpace(value) != "" {
+			slots[slot.Name] = value
+		}
+	}
+	response.Slots = slots
+
+	var missing []string
+	for _, name := range response.Missing {
+		slot, _ := intent.slot(name)
+		if _, filled := slots[slot.Name]; !filled && !slices.Contains(missing, slot.Name) {
+			missing = append(missing, slot.Name)
+		}
+	}
+	response.Missing = missing
+	return response
+}
+
+// mergeInputs combines defaults with user input.
+func (i *IntentSynapse) mergeInputs(input IntentInput) IntentInput {
+	merged := i.defaults
+
+	if input.Utterance != "" {
+		merged.Utterance = input.Utterance
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input, describing each
+// intent and its slots so the semantics do not rest on the schema alone.
+func (i *IntentSynapse) buildPrompt(input IntentInput) *Prompt {
+	constraints := []string{"intent: exactly one of the intent names below"}
+	for _, intent := range i.intents {
+		constraints = append(constraints, describeIntent(intent))
+	}
+	constraints = append(constraints,
+		"slots: only slots of the chosen intent, keyed by slot name, with values taken from the message or context; leave out slots not given",
+		"missing: names of the chosen intent's required slots the message does not give; empty if none",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining the intent and slots",
+	)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Identify the intent of the message for %s and fill its slots", i.domain),
+		Input:       input.Utterance,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// describeIntent renders an intent and its slots as a single constraint,
+// e.g. `intent "book_flight": book a plane ticket; slots: "to" (city, required)`.
+func describeIntent(intent IntentDef) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "intent %q: %s", intent.Name, intent.Description)
+	if len(intent.Slots) == 0 {
+		b.WriteString("; no slots")
+		return b.String()
+	}
+	b.WriteString("; slots: ")
+	for j, slot := range intent.Slots {
+		if j > 0 {
+			b.WriteString("; ")
+		}
+		requirement := "optional"
+		if slot.Required {
+			requirement = "required"
+		}
+		if slot.Type != "" {
+			fmt.Fprintf(&b, "%q (%s, %s)", slot.Name, slot.Type, requirement)
+		} else {
+			fmt.Fprintf(&b, "%q (%s)", slot.Name, requirement)
+		}
+		if slot.Description != "" {
+			fmt.Fprintf(&b, ": %s", slot.Description)
+		}
+	}
+	return b.String()
+}
+
+// Intent creates a new intent synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if no intents are given, an intent or slot name is empty
+// or declared twice, or the JSON schema cannot be generated.
+//
+// Example:
+//
+//	router, err := Intent("an airline support bot", []IntentDef{
+//	    {Name: "book_flight", Description: "book a plane ticket", Slots: []SlotDef{
+//	        {Name: "to", Type: "city", Required: true},
+//	        {Name: "date", Type: "date"},
+//	    }},
+//	    {Name: "baggage", Description: "questions about luggage allowances"},
+//	}, provider)
+//	response, err := router.FireWithInput(ctx, session, IntentInput{Utterance: "I need a flight to Lisbon"})
+//	// response.Intent: "book_flight", response.Slots: {"to": "Lisbon"}
+func Intent(domain string, intents []IntentDef, provider Provider, opts ...Option) (*IntentSynapse, error) {
+	return NewIntent(domain, intents, provider, opts...)
+}
@@ -0,0 +1,202 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// airlineIntents are the intents of the intent tests.
+var airlineIntents = []IntentDef{
+	{Name: "book_flight", Description: "book a plane ticket", Slots: []SlotDef{
+		{Name: "to", Type: "city", Required: true, Description: "the destination"},
+		{Name: "date", Type: "date", Required: true},
+		{Name: "class", Type: "cabin class"},
+	}},
+	{Name: "baggage", Description: "questions about luggage allowances"},
+}
+
+func TestIntentResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := IntentResponse{
+			Intent:     "baggage",
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_intent", func(t *testing.T) {
+		r := IntentResponse{
+			Intent:     " ",
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank intent")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := IntentResponse{
+			Intent:     "baggage",
+			Confidence: 1.1,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := IntentResponse{
+			Intent:     "baggage",
+			Confidence: 0.9,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestNewIntent_InvalidIntents(t *testing.T) {
+	tests := []struct {
+		name    string
+		intents []IntentDef
+	}{
+		{"no intents", nil},
+		{"unnamed intent", []IntentDef{{Description: "x"}}},
+		{"repeated intent", []IntentDef{{Name: "greet"}, {Name: "Greet"}}},
+		{"unnamed slot", []IntentDef{{Name: "greet", Slots: []SlotDef{{Type: "name"}}}}},
+		{"repeated slot", []IntentDef{{Name: "greet", Slots: []SlotDef{{Name: "who"}, {Name: "WHO"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Intent("a bot", tt.intents, NewMockProvider()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestIntentSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"intent": "Book_Flight", "slots": {"To": "Lisbon", "class": ""}, "missing": ["date"], "confidence": 0.9, "reasoning": ["x"]}`, nil
+	})
+	router, err := Intent("an airline support bot", airlineIntents, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := router.FireWithInput(context.Background(), NewSession(), IntentInput{Utterance: "I need a flight to Lisbon"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Intent != "book_flight" {
+		t.Errorf("expected the declared intent name, got %q", response.Intent)
+	}
+	if len(response.Slots) != 1 || response.Slots["to"] != "Lisbon" {
+		t.Errorf("expected declared slot names and empty values dropped, got %v", response.Slots)
+	}
+	if !slices.Equal(response.Missing, []string{"date"}) {
+		t.Errorf("unexpected missing slots: %v", response.Missing)
+	}
+	for _, want := range []string{
+		"an airline support bot",
+		`intent "book_flight": book a plane ticket; slots: "to" (city, required): the destination; "date" (date, required); "class" (cabin class, optional)`,
+		`intent "baggage": questions about luggage allowances; no slots`,
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestIntentSynapse_Fire(t *testing.T) {
+	router, _ := Intent("an airline support bot", airlineIntents, NewMockProviderWithResponse(`{"intent": "baggage", "slots": {}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`))
+
+	intent, err := router.Fire(context.Background(), NewSession(), "How many bags can I bring?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if intent != "baggage" {
+		t.Errorf("unexpected intent: %q", intent)
+	}
+}
+
+func TestIntentSynapse_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{"unknown intent", `{"intent": "cancel_flight", "slots": {}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, `unknown intent "cancel_flight"`},
+		{"undeclared slot", `{"intent": "book_flight", "slots": {"to": "Lisbon", "date": "May 1", "seat": "12A"}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, `intent "book_flight" has no slot "seat"`},
+		{"slot of another intent", `{"intent": "baggage", "slots": {"to": "Lisbon"}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, `intent "baggage" has no slot "to"`},
+		{"undeclared missing slot", `{"intent": "book_flight", "slots": {"to": "Lisbon", "date": "May 1"}, "missing": ["seat"], "confidence": 0.9, "reasoning": ["x"]}`, `intent "book_flight" has no slot "seat"`},
+		{"required slot unaccounted for", `{"intent": "book_flight", "slots": {"to": "Lisbon"}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, `required slot "date" is neither filled nor listed as missing`},
+		{"required slot empty", `{"intent": "book_flight", "slots": {"to": "Lisbon", "date": " "}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, `required slot "date" is neither filled nor listed as missing`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := Intent("an airline support bot", airlineIntents, NewMockProviderWithResponse(tt.response))
+
+			_, err := router.Fire(context.Background(), NewSession(), "message")
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestIntentSynapse_FilledSlotNotMissing(t *testing.T) {
+	response := `{"intent": "book_flight", "slots": {"to": "Lisbon", "date": "May 1"}, "missing": ["date", "Date"], "confidence": 0.9, "reasoning": ["x"]}`
+	router, _ := Intent("an airline support bot", airlineIntents, NewMockProviderWithResponse(response))
+
+	details, err := router.FireWithInput(context.Background(), NewSession(), IntentInput{Utterance: "Lisbon on May 1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(details.Missing) != 0 {
+		t.Errorf("expected filled slots left out of missing, got %v", details.Missing)
+	}
+}
+
+func TestIntentSynapse_Invoke(t *testing.T) {
+	router, _ := Intent("an airline support bot", airlineIntents, NewMockProviderWithResponse(`{"intent": "baggage", "slots": {}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`))
+
+	validator, err := router.Invoke(context.Background(), NewSession(), SynapseInput{Input: "bags?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(IntentResponse); !ok || response.Intent != "baggage" {
+		t.Errorf("expected IntentResponse, got %#v", validator)
+	}
+}
+
+func TestIntentSynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"intent": "cancel_flight", "slots": {}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, nil
+		}
+		return `{"intent": "baggage", "slots": {}, "missing": [], "confidence": 0.9, "reasoning": ["x"]}`, nil
+	})
+	router, _ := Intent("an airline support bot", airlineIntents, provider, WithValidationRetry(2))
+
+	intent, err := router.Fire(context.Background(), NewSession(), "bags?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || intent != "baggage" {
+		t.Errorf("expected a retry after the unknown intent, got %d calls and %q", calls, intent)
+	}
+}
@@ -0,0 +1,372 @@
+// Package llamacpp provides a zyn Provider for local OpenAI-compatible
+// servers such as llama.cpp's llama-server and LM Studio.
+package llamacpp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+// Provider implements the zyn Provider interface for llama.cpp and LM Studio
+// servers.
+type Provider struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+	name       string
+	vision     bool
+	httpConfig zyn.HTTPConfig
+	httpErr    error       // Invalid HTTPConfig, reported by every call
+	closed     atomic.Bool // Set by Close
+}
+
+// Config holds configuration for the llama.cpp provider.
+type Config struct {
+	BaseURL string        // Optional, defaults to "http://localhost:8080/v1" (llama-server); LM Studio serves "http://localhost:1234/v1"
+	Model   string        // Optional; llama-server serves the model it was started with, LM Studio needs the model identifier
+	APIKey  string        // Optional, for servers started with --api-key
+	Timeout time.Duration // Optional, defaults to 5m for slow local inference; unused with HTTPConfig.HTTPClient
+	Vision  bool          // Optional, set when the server has a multimodal projector loaded
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
+}
+
+// New creates a new llama.cpp provider. Trailing slashes in config.BaseURL
+// are ignored.
+// If config.Headers would override a credential header, every call fails
+// with the error from config.HTTPConfig.Validate.
+func New(config Config) *Provider {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.BaseURL == "" {
+		config.BaseURL = "http://localhost:8080/v1"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Minute
+	}
+
+	return &Provider{
+		apiKey:     config.APIKey,
+		model:      config.Model,
+		baseURL:    config.BaseURL,
+		name:       "llamacpp",
+		vision:     config.Vision,
+		httpConfig: config.HTTPConfig,
+		httpErr:    config.HTTPConfig.Validate(),
+		httpClient: config.HTTPConfig.Client(config.Timeout),
+	}
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls and pings after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Call sends messages to the server and returns the response.
+//
+// The response content is returned verbatim. Local models often wrap their
+// JSON in markdown code fences, which synapses strip before parsing.
+// Builds that report no usage return a zero zyn.TokenUsage. Servers that
+// cannot be reached, or answer 503 while loading a model, fail with a
+// retryable *zyn.ProviderUnavailableError.
+func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
+	startTime := time.Now()
+
+	// Emit provider.call.started hook
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(p.model),
+	)...)
+
+	apiMessages := make([]requestMessage, len(messages))
+	for i, msg := range messages {
+		apiMessages[i] = newRequestMessage(msg)
+	}
+
+	// No response_format: LM Studio rejects json_object, and zyn prompts
+	// already ask for JSON
+	requestBody := chatCompletionRequest{
+		Model:       p.model,
+		Messages:    apiMessages,
+		Temperature: temperature,
+	}
+	if params, ok := zyn.CallParamsFromContext(ctx); ok {
+		requestBody.Seed = params.Seed
+		requestBody.MaxTokens = params.MaxTokens
+		requestBody.TopP = params.TopP
+		requestBody.Stop = params.Stop
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.httpConfig.Apply(req)
+	req.Header.Set("Content-Type", "application/json")
+	p.authorize(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, p.requestFailed(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		duration := time.Since(startTime)
+		detail := errorDetail(body, resp.StatusCode)
+
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx,
+			zyn.ProviderKey.Field(p.name),
+			zyn.ModelKey.Field(p.model),
+			zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+			zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+			zyn.ErrorKey.Field(detail),
+		)...)
+
+		switch resp.StatusCode {
+		case http.StatusServiceUnavailable:
+			return nil, &zyn.ProviderUnavailableError{Provider: p.name, Err: fmt.Errorf("status %d: %s", resp.StatusCode, detail)}
+		case http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, detail)
+		}
+		return nil, fmt.Errorf("llamacpp error (%d): %s", resp.StatusCode, detail)
+	}
+
+	var completionResp chatCompletionResponse
+	if err := json.Unmarshal(body, &completionResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(completionResp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned")
+	}
+
+	duration := time.Since(startTime)
+
+	model := completionResp.Model
+	if model == "" {
+		model = p.model
+	}
+
+	// Emit provider.call.completed hook, with token counts when reported
+	fields := []capitan.Field{
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(model),
+		zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+		zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+	}
+	var usage zyn.TokenUsage
+	if completionResp.Usage != nil {
+		usage = zyn.TokenUsage{
+			Prompt:     completionResp.Usage.PromptTokens,
+			Completion: completionResp.Usage.CompletionTokens,
+			Total:      completionResp.Usage.TotalTokens,
+		}
+		fields = append(fields,
+			zyn.PromptTokensKey.Field(usage.Prompt),
+			zyn.CompletionTokensKey.Field(usage.Completion),
+			zyn.TotalTokensKey.Field(usage.Total),
+		)
+	}
+	if completionResp.ID != "" {
+		fields = append(fields, zyn.ResponseIDKey.Field(completionResp.ID))
+	}
+	if completionResp.Choices[0].FinishReason != "" {
+		fields = append(fields, zyn.ResponseFinishReasonKey.Field(completionResp.Choices[0].FinishReason))
+	}
+
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
+
+	return &zyn.ProviderResponse{
+		Content:   completionResp.Choices[0].Message.Content,
+		Truncated: completionResp.Choices[0].FinishReason == "length",
+		Usage:     usage,
+	}, nil
+}
+
+// Ping lists the server's models, checking that it is up and has finished
+// loading without running inference.
+func (p *Provider) Ping(ctx context.Context) error {
+	if p.httpErr != nil {
+		return p.httpErr
+	}
+	if p.closed.Load() {
+		return &zyn.ProviderClosedError{Provider: p.name}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	p.httpConfig.Apply(req)
+	p.authorize(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return p.requestFailed(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body) //nolint:errcheck // the status is reported either way
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return &zyn.ProviderUnavailableError{Provider: p.name, Err: fmt.Errorf("status %d: %s", resp.StatusCode, errorDetail(body, resp.StatusCode))}
+	}
+	return fmt.Errorf("llamacpp ping failed (%d): %s", resp.StatusCode, errorDetail(body, resp.StatusCode))
+}
+
+// authorize sets the credential header, if the provider has an API key.
+func (p *Provider) authorize(req *http.Request) {
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+}
+
+// requestFailed reports a request that got no response. Connection errors
+// mean the server is down or restarting, so they are retryable; errors
+// caused by the call's context are returned as they are.
+func (p *Provider) requestFailed(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	return &zyn.ProviderUnavailableError{Provider: p.name, Err: err}
+}
+
+// errorDetail returns the error message from a response body, which
+// llama-server sends as an object and LM Studio sometimes as a string, or
+// the status when the body has none.
+func errorDetail(body []byte, status int) string {
+	var errorResp errorResponse
+	if err := json.Unmarshal(body, &errorResp); err == nil {
+		var message string
+		if err := json.Unmarshal(errorResp.Error, &message); err == nil && message != "" {
+			return message
+		}
+		var detail struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(errorResp.Error, &detail); err == nil && detail.Message != "" {
+			return detail.Message
+		}
+	}
+	return fmt.Sprintf("status %d", status)
+}
+
+// Request/Response types for the OpenAI-compatible API
+
+type chatCompletionRequest struct {
+	Model       string           `json:"model,omitempty"`
+	Messages    []requestMessage `json:"messages"`
+	Temperature float32          `json:"temperature"`
+	Seed        *int             `json:"seed,omitempty"`
+	MaxTokens   int              `json:"max_tokens,omitempty"`
+	TopP        float32          `json:"top_p,omitempty"`
+	Stop        []string         `json:"stop,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// requestMessage is a message sent to the API. Content is a string, or a list
+// of content parts when images are attached.
+type requestMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// newRequestMessage converts a zyn.Message, sending attached images as
+// image_url content parts after the text.
+func newRequestMessage(msg zyn.Message) requestMessage {
+	if len(msg.Images) == 0 {
+		return requestMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	parts := make([]contentPart, 0, len(msg.Images)+1)
+	parts = append(parts, contentPart{Type: "text", Text: msg.Content})
+	for _, image := range msg.Images {
+		parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: image.DataURL()}})
+	}
+	return requestMessage{Role: msg.Role, Content: parts}
+}
+
+type chatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Choices []choice `json:"choices"`
+	Usage   *usage   `json:"usage"` // Missing from some builds
+}
+
+type choice struct {
+	Index        int     `json:"index"`
+	Message      message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+}
+
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// errorResponse is the error body. Error is an object with a message, or a
+// plain string.
+type errorResponse struct {
+	Error json.RawMessage `json:"error"`
+}
@@ -0,0 +1,462 @@
+package llamacpp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+func TestProviderCall(t *testing.T) {
+	ctx := context.Background()
+	// Create a test server that mimics llama-server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("Expected /chat/completions, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected no Authorization header without an API key, got %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", r.Header.Get("Content-Type"))
+		}
+
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if _, ok := req["response_format"]; ok {
+			t.Errorf("Expected no response_format, got %v", req["response_format"])
+		}
+		if _, ok := req["model"]; ok {
+			t.Errorf("Expected no model without Config.Model, got %v", req["model"])
+		}
+		if req["temperature"] != 0.5 {
+			t.Errorf("Expected temperature 0.5, got %v", req["temperature"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "chatcmpl-local",
+			"object": "chat.completion",
+			"created": 1760601600,
+			"model": "qwen2.5-7b-instruct-q4_k_m.gguf",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "test response"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+		}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var total int
+	var model string
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		total, _ = zyn.TotalTokensKey.From(e)
+		model, _ = zyn.ModelKey.From(e)
+	})
+	defer listener.Close()
+
+	provider := New(Config{BaseURL: server.URL})
+
+	response, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test prompt"}}, 0.5)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if response.Content != "test response" {
+		t.Errorf("Expected 'test response', got '%s'", response.Content)
+	}
+	if response.Usage != (zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15}) {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+
+	wg.Wait()
+	if total != 15 || model != "qwen2.5-7b-instruct-q4_k_m.gguf" {
+		t.Errorf("Unexpected hook fields: total %d, model %q", total, model)
+	}
+}
+
+func TestProviderCallWithoutUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "{\"decision\": true}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var hasTokens bool
+	var model string
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		_, hasTokens = zyn.TotalTokensKey.From(e)
+		model, _ = zyn.ModelKey.From(e)
+	})
+	defer listener.Close()
+
+	provider := New(Config{BaseURL: server.URL, Model: "local-model"})
+	response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test prompt"}}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if response.Usage != (zyn.TokenUsage{}) {
+		t.Errorf("Expected zero usage, got %+v", response.Usage)
+	}
+
+	wg.Wait()
+	if hasTokens {
+		t.Error("Expected no token counts in hook when usage is missing")
+	}
+	if model != "local-model" {
+		t.Errorf("Expected configured model in hook, got %q", model)
+	}
+}
+
+func TestProviderRequest(t *testing.T) {
+	seed := 7
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer local-key" {
+			t.Errorf("Expected Bearer token, got %s", r.Header.Get("Authorization"))
+		}
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "lmstudio-community/qwen2.5-7b-instruct" {
+			t.Errorf("Expected configured model, got %s", req.Model)
+		}
+		if req.Seed == nil || *req.Seed != seed || req.MaxTokens != 256 {
+			t.Errorf("Expected call params in request, got %+v", req)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{BaseURL: server.URL + "/", APIKey: "local-key", Model: "lmstudio-community/qwen2.5-7b-instruct"})
+	synapse, _ := zyn.Binary("Is this valid?", provider, zyn.WithSeed(seed), zyn.WithMaxTokens(256))
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("Fire failed: %v", err)
+	}
+}
+
+func TestProviderDefaults(t *testing.T) {
+	provider := New(Config{})
+	if provider.baseURL != "http://localhost:8080/v1" {
+		t.Errorf("Expected llama-server default URL, got %s", provider.baseURL)
+	}
+	if provider.httpClient.Timeout != 5*time.Minute {
+		t.Errorf("Expected 5m default timeout, got %s", provider.httpClient.Timeout)
+	}
+	if provider.Name() != "llamacpp" {
+		t.Errorf("Expected name 'llamacpp', got '%s'", provider.Name())
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	provider := New(Config{Model: "llava-v1.6", Vision: true})
+	if got := provider.Capabilities(); got != (zyn.Capabilities{Vision: true, Model: "llava-v1.6"}) {
+		t.Errorf("Unexpected capabilities: %+v", got)
+	}
+}
+
+func TestProviderErrorHandling(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseBody  string
+		expectedError string
+		unavailable   bool
+		rateLimited   bool
+	}{
+		{
+			name:          "Loading model",
+			statusCode:    http.StatusServiceUnavailable,
+			responseBody:  `{"error": {"code": 503, "message": "Loading model", "type": "unavailable_error"}}`,
+			expectedError: `provider unavailable: provider "llamacpp": status 503: Loading model`,
+			unavailable:   true,
+		},
+		{
+			name:          "Busy",
+			statusCode:    http.StatusTooManyRequests,
+			responseBody:  `{"error": {"code": 429, "message": "no slot available", "type": "unavailable_error"}}`,
+			expectedError: "no slot available",
+			rateLimited:   true,
+		},
+		{
+			name:          "String error",
+			statusCode:    http.StatusBadRequest,
+			responseBody:  `{"error": "No models loaded. Please load a model in the developer page."}`,
+			expectedError: "llamacpp error (400): No models loaded. Please load a model in the developer page.",
+		},
+		{
+			name:          "Unauthorized",
+			statusCode:    http.StatusUnauthorized,
+			responseBody:  `{"error": {"code": 401, "message": "Invalid API Key", "type": "authentication_error"}}`,
+			expectedError: "llamacpp error (401): Invalid API Key",
+		},
+		{
+			name:          "Empty body",
+			statusCode:    http.StatusInternalServerError,
+			expectedError: "llamacpp error (500): status 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			provider := New(Config{BaseURL: server.URL})
+			_, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%s'", tt.expectedError, err.Error())
+			}
+			if errors.Is(err, zyn.ErrProviderUnavailable) != tt.unavailable {
+				t.Errorf("Expected unavailable %v, got %v", tt.unavailable, err)
+			}
+			if errors.Is(err, zyn.ErrRateLimited) != tt.rateLimited {
+				t.Errorf("Expected rate limited %v, got %v", tt.rateLimited, err)
+			}
+			if errors.Is(err, zyn.ErrNotRetryable) {
+				t.Errorf("Expected a retryable error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConnectionErrorRetried(t *testing.T) {
+	// The first two connections are dropped, as by a server restarting
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) <= 2 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Failed to hijack connection: %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{BaseURL: server.URL})
+
+	_, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+	var unavailable *zyn.ProviderUnavailableError
+	if !errors.As(err, &unavailable) || unavailable.Provider != "llamacpp" {
+		t.Fatalf("Expected ProviderUnavailableError, got %v", err)
+	}
+
+	synapse, _ := zyn.Binary("Is this valid?", provider, zyn.WithRetry(3))
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	provider := New(Config{BaseURL: url})
+	if _, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1); !errors.Is(err, zyn.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable, got %v", err)
+	}
+	if err := provider.Ping(context.Background()); !errors.Is(err, zyn.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable from Ping, got %v", err)
+	}
+}
+
+func TestContextCancelledNotUnavailable(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	provider := New(Config{BaseURL: server.URL})
+	_, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+	if err == nil || errors.Is(err, zyn.ErrProviderUnavailable) {
+		t.Errorf("Expected a deadline error, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestProviderPing(t *testing.T) {
+	loading := true
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/models" {
+			t.Errorf("Expected GET /models, got %s %s", r.Method, r.URL.Path)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if loading {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": {"code": 503, "message": "Loading model", "type": "unavailable_error"}}`))
+			return
+		}
+		w.Write([]byte(`{"object": "list", "data": [{"id": "qwen2.5-7b-instruct-q4_k_m.gguf", "object": "model"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{BaseURL: server.URL})
+	if err := provider.Ping(context.Background()); !errors.Is(err, zyn.ErrProviderUnavailable) {
+		t.Errorf("Expected ErrProviderUnavailable while loading, got %v", err)
+	}
+
+	mu.Lock()
+	loading = false
+	mu.Unlock()
+	if err := provider.Ping(context.Background()); err != nil {
+		t.Errorf("Expected ping to succeed, got %v", err)
+	}
+}
+
+func TestTruncatedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": tr"}, "finish_reason": "length"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{BaseURL: server.URL})
+	response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if !response.Truncated {
+		t.Error("Expected truncated response")
+	}
+}
+
+// fencedResponse is a case in testdata/fenced_responses.json: content a
+// local model answered with, and whether synapses parse it.
+type fencedResponse struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Parses  bool   `json:"parses"`
+}
+
+// TestFencedResponses replays testdata/fenced_responses.json. Content is
+// returned verbatim; synapses strip a fence wrapping the whole answer, and
+// text after the closing fence, but not prose before the opening fence.
+func TestFencedResponses(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/fenced_responses.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var cases []fencedResponse
+	if err := json.Unmarshal(fixture, &cases); err != nil {
+		t.Fatalf("Invalid fixture: %v", err)
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				json.NewEncoder(w).Encode(chatCompletionResponse{
+					Choices: []choice{{Message: message{Role: zyn.RoleAssistant, Content: c.Content}, FinishReason: "stop"}},
+				})
+			}))
+			defer server.Close()
+
+			provider := New(Config{BaseURL: server.URL})
+			response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.1)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if response.Content != c.Content {
+				t.Errorf("Expected content verbatim, got %q", response.Content)
+			}
+
+			synapse, _ := zyn.Binary("Has the order shipped?", provider)
+			_, err = synapse.Fire(context.Background(), zyn.NewSession(), "order A-1")
+			if c.Parses && err != nil {
+				t.Errorf("Expected response to parse, got %v", err)
+			}
+			if !c.Parses && !errors.Is(err, zyn.ErrParseFailed) {
+				t.Errorf("Expected ErrParseFailed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLlamaCppIntegration(t *testing.T) {
+	baseURL := os.Getenv("LLAMACPP_BASE_URL")
+	if baseURL == "" {
+		t.Skip("LLAMACPP_BASE_URL not set, skipping integration test")
+	}
+
+	provider := New(Config{BaseURL: baseURL, Model: os.Getenv("LLAMACPP_MODEL")})
+
+	response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: `Reply with {"status": "test successful"} and nothing else.`}}, 0.7)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if response.Content == "" {
+		t.Error("Expected non-empty response")
+	}
+
+	t.Logf("Response: %s", response.Content)
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{BaseURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if err := provider.Ping(context.Background()); !errors.Is(err, zyn.ErrProviderClosed) {
+		t.Errorf("Expected ErrProviderClosed from Ping, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
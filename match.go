@@ -0,0 +1,294 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Properties of MatchResponse computed from the pairs rather than asked of
+// the LLM.
+const (
+	matchUnmatchedLeftProperty  = "unmatched_left"
+	matchUnmatchedRightProperty = "unmatched_right"
+)
+
+// MatchInput contains rich input structure for matching.
+type MatchInput struct {
+	Left        []string // Items to match from, such as invoice lines
+	Right       []string // Items to match against, such as purchase order lines
+	Context     string   // Optional context, such as accepted tolerances
+	ManyToMany  bool     // Allow an item in several pairs; by default each item is used at most once
+	Temperature float32  // LLM temperature setting for this specific request
+}
+
+// Pair is a left item matched to a right item.
+type Pair struct {
+	Left  string  `json:"left"`  // Exact text of the left item
+	Right string  `json:"right"` // Exact text of the right item
+	Score float64 `json:"score"` // 0.0 to 1.0 strength of the match
+}
+
+// MatchResponse contains the response from a match synapse.
+// UnmatchedLeft and UnmatchedRight are computed from the pairs, not by the
+// LLM.
+type MatchResponse struct {
+	Pairs          []Pair   `json:"pairs"`           // Matched items
+	UnmatchedLeft  []string `json:"unmatched_left"`  // Left items in no pair, in list order
+	UnmatchedRight []string `json:"unmatched_right"` // Right items in no pair, in list order
+	Confidence     float64  `json:"confidence"`      // 0.0 to 1.0 confidence score
+	Reasoning      []string `json:"reasoning"`       // Explanation of the matching
+}
+
+// Validate checks if the response is valid. Pairs are checked against the
+// lists by the synapse.
+func (r MatchResponse) Validate() error {
+	for i, pair := range r.Pairs {
+		if strings.TrimSpace(pair.Left) == "" {
+			return fmt.Errorf("pair %d: left required but empty", i)
+		}
+		if strings.TrimSpace(pair.Right) == "" {
+			return fmt.Errorf("pair %d: right required but empty", i)
+		}
+		if pair.Score < 0 || pair.Score > 1 {
+			return fmt.Errorf("pair %d: score must be 0-1, got %f", i, pair.Score)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkPairs checks that every pair names listed items and, unless input
+// allows many-to-many matches, that no item is paired more often than it is
+// listed.
+func checkPairs(input MatchInput, response MatchResponse) error {
+	left, right := occurrences(input.Left), occurrences(input.Right)
+	seen := make(map[[2]string]bool, len(response.Pairs))
+	for i, pair := range response.Pairs {
+		if _, ok := left[pair.Left]; !ok {
+			return fmt.Errorf("pair %d: unknown left item %q", i, pair.Left)
+		}
+		if _, ok := right[pair.Right]; !ok {
+			return fmt.Errorf("pair %d: unknown right item %q", i, pair.Right)
+		}
+		if input.ManyToMany {
+			key := [2]string{pair.Left, pair.Right}
+			if seen[key] {
+				return fmt.Errorf("pair %d: %q and %q are already paired", i, pair.Left, pair.Right)
+			}
+			seen[key] = true
+			continue
+		}
+		if left[pair.Left]--; left[pair.Left] < 0 {
+			return fmt.Errorf("pair %d: left item %q is already used", i, pair.Left)
+		}
+		if right[pair.Right]--; right[pair.Right] < 0 {
+			return fmt.Errorf("pair %d: right item %q is already used", i, pair.Right)
+		}
+	}
+	return nil
+}
+
+// occurrences counts how often each item is listed.
+func occurrences(items []string) map[string]int {
+	counts := make(map[string]int, len(items))
+	for _, item := range items {
+		counts[item]++
+	}
+	return counts
+}
+
+// unmatched returns the items, in order, not accounted for by the used
+// counts. With many-to-many matching, every occurrence of a used item counts
+// as paired.
+func unmatched(items []string, used map[string]int, manyToMany bool) []string {
+	var rest []string
+	for _, item := range items {
+		if used[item] > 0 {
+			if !manyToMany {
+				used[item]--
+			}
+			continue
+		}
+		rest = append(rest, item)
+	}
+	return rest
+}
+
+// MatchSynapse aligns the items of two lists, such as invoice lines and
+// purchase order lines.
+type MatchSynapse struct {
+	criteria string
+	schema   string // Response schema without the computed properties
+	defaults MatchInput
+	base     *Base[MatchInput, MatchResponse]
+}
+
+// NewMatch creates a new match synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewMatch(criteria string, provider Provider, opts ...Option) (*MatchSynapse, error) {
+	synapse := &MatchSynapse{criteria: criteria}
+
+	base, err := NewSynapse(SynapseConfig[MatchInput, MatchResponse]{
+		Type:        "match",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The LLM only pairs; what is left over is computed from the pairs
+	schema := base.Schema()
+	for _, property := range []string{matchUnmatchedLeftProperty, matchUnmatchedRightProperty} {
+		if schema, err = omitProperty(schema, property); err != nil {
+			return nil, fmt.Errorf("match synapse: %w", err)
+		}
+	}
+
+	synapse.schema = schema
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (m *MatchSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return m.base.GetPipeline()
+}
+
+// WithDefaults creates a new Match with default input values.
+// These are merged with user input at execution time.
+func (m *MatchSynapse) WithDefaults(defaults MatchInput) *MatchSynapse {
+	m.defaults = defaults
+	return m
+}
+
+// Fire matches the items of left to the items of right.
+func (m *MatchSynapse) Fire(ctx context.Context, session *Session, left, right []string) (MatchResponse, error) {
+	return m.FireWithInput(ctx, session, MatchInput{Left: left, Right: right})
+}
+
+// FireResult matches the items of left to the items of right and returns the
+// response in a Result envelope carrying the call's usage, timing, and
+// request metadata.
+func (m *MatchSynapse) FireResult(ctx context.Context, session *Session, left, right []string) (Result[MatchResponse], error) {
+	return m.execute(ctx, session, MatchInput{Left: left, Right: right})
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (m *MatchSynapse) FireWithInput(ctx context.Context, session *Session, input MatchInput) (MatchResponse, error) {
+	result, err := m.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, matching the
+// items to the non-empty lines of the input. The returned Validator is a
+// MatchResponse.
+func (m *MatchSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := m.FireWithInput(ctx, session, MatchInput{
+		Left:        input.Items,
+		Right:       SynapseInput{Input: input.Input}.items(),
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, pairs the items, and works out
+// which items were left unmatched.
+func (m *MatchSynapse) execute(ctx context.Context, session *Session, input MatchInput) (Result[MatchResponse], error) {
+	merged := m.mergeInputs(input)
+	if len(merged.Left) == 0 || len(merged.Right) == 0 {
+		return Result[MatchResponse]{Provider: m.base.service.providerName},
+			fmt.Errorf("%w: match synapse needs items on both sides", ErrInvalidPrompt)
+	}
+
+	result, err := m.base.service.executeChecked(ctx, session, m.buildPrompt(merged), merged.Temperature, func(response MatchResponse) error {
+		return checkPairs(merged, response)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	left, right := make(map[string]int), make(map[string]int)
+	for _, pair := range result.Value.Pairs {
+		left[pair.Left]++
+		right[pair.Right]++
+	}
+	result.Value.UnmatchedLeft = unmatched(merged.Left, left, merged.ManyToMany)
+	result.Value.UnmatchedRight = unmatched(merged.Right, right, merged.ManyToMany)
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input.
+func (m *MatchSynapse) mergeInputs(input MatchInput) MatchInput {
+	merged := m.defaults
+
+	if len(input.Left) > 0 {
+		merged.Left = input.Left
+	}
+	if len(input.Right) > 0 {
+		merged.Right = input.Right
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.ManyToMany {
+		merged.ManyToMany = true
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. The lists are
+// rendered with indices, and pairs must quote the items exactly.
+func (m *MatchSynapse) buildPrompt(input MatchInput) *Prompt {
+	usage := "pairs: use each listed item in at most one pair; an item listed twice may be used twice"
+	if input.ManyToMany {
+		usage = "pairs: an item may be paired with several items of the other list, but never with the same item twice"
+	}
+
+	return &Prompt{
+		Task:    fmt.Sprintf("Match the items of the left list to the items of the right list by %s", m.criteria),
+		Context: input.Context,
+		Left:    input.Left,
+		Right:   input.Right,
+		Schema:  m.schema,
+		Constraints: []string{
+			"pairs: left and right are the exact text of a listed item, without its index",
+			usage,
+			"leave items without a good match out of the pairs rather than force a pair",
+			"score: 0.0 to 1.0 strength of each match",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the matching",
+		},
+	}
+}
+
+// Match creates a new match synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	reconcile, err := Match("the same product and quantity", provider, WithValidationRetry(2))
+//	response, err := reconcile.Fire(ctx, session, invoiceLines, orderLines)
+//	for _, pair := range response.Pairs { ... }
+//	// response.UnmatchedLeft: invoice lines with no order line
+func Match(criteria string, provider Provider, opts ...Option) (*MatchSynapse, error) {
+	return NewMatch(criteria, provider, opts...)
+}
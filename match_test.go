@@ -0,0 +1,268 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// invoiceLines and orderLines are the lists of the match tests.
+var (
+	invoiceLines = []string{"Widget x2", "Gadget x1", "Shipping"}
+	orderLines   = []string{"2 widgets", "1 gadget", "Sprocket x4"}
+)
+
+// invoicePairs is a valid match response for invoiceLines and orderLines.
+const invoicePairs = `{
+	"pairs": [
+		{"left": "Widget x2", "right": "2 widgets", "score": 0.95},
+		{"left": "Gadget x1", "right": "1 gadget", "score": 0.9}
+	],
+	"confidence": 0.9,
+	"reasoning": ["products and quantities agree"]
+}`
+
+func TestMatchResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Left: "a", Right: "b", Score: 0.5}},
+			Confidence: 0.8,
+			Reasoning:  []string{"close"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_pairs", func(t *testing.T) {
+		r := MatchResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"nothing matches"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected no pairs accepted, got error: %v", err)
+		}
+	})
+
+	t.Run("empty_left", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Right: "b"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"close"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a pair without a left item")
+		}
+	})
+
+	t.Run("empty_right", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Left: "a", Right: " "}},
+			Confidence: 0.8,
+			Reasoning:  []string{"close"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a pair without a right item")
+		}
+	})
+
+	t.Run("score_too_high", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Left: "a", Right: "b", Score: 1.5}},
+			Confidence: 0.8,
+			Reasoning:  []string{"close"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a pair score > 1")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Left: "a", Right: "b", Score: 0.5}},
+			Confidence: 2,
+			Reasoning:  []string{"close"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := MatchResponse{
+			Pairs:      []Pair{{Left: "a", Right: "b", Score: 0.5}},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestMatchSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return invoicePairs, nil
+	})
+	synapse, err := Match("the same product and quantity", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := synapse.Fire(context.Background(), NewSession(), invoiceLines, orderLines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.Pairs) != 2 || response.Pairs[0].Right != "2 widgets" {
+		t.Errorf("unexpected pairs: %+v", response.Pairs)
+	}
+	if !slices.Equal(response.UnmatchedLeft, []string{"Shipping"}) || !slices.Equal(response.UnmatchedRight, []string{"Sprocket x4"}) {
+		t.Errorf("unexpected unmatched items: %v, %v", response.UnmatchedLeft, response.UnmatchedRight)
+	}
+	for _, want := range []string{"same product and quantity", "L1. Widget x2", "R3. Sprocket x4", "at most one pair"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "unmatched_left") {
+		t.Errorf("expected computed properties omitted from the schema, got %s", prompt)
+	}
+}
+
+func TestMatchSynapse_Pairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		pairs    string
+		expected string
+	}{
+		{"unknown left", `{"left": "Widgets", "right": "2 widgets", "score": 0.9}`, `pair 0: unknown left item "Widgets"`},
+		{"unknown right", `{"left": "Widget x2", "right": "widgets", "score": 0.9}`, `pair 0: unknown right item "widgets"`},
+		{"left used twice", `{"left": "Widget x2", "right": "2 widgets", "score": 0.9}, {"left": "Widget x2", "right": "1 gadget", "score": 0.2}`, `pair 1: left item "Widget x2" is already used`},
+		{"right used twice", `{"left": "Widget x2", "right": "2 widgets", "score": 0.9}, {"left": "Shipping", "right": "2 widgets", "score": 0.2}`, `pair 1: right item "2 widgets" is already used`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, _ := Match("product", NewMockProviderWithResponse(`{"pairs": [`+tt.pairs+`], "confidence": 0.8, "reasoning": ["x"]}`))
+
+			_, err := synapse.Fire(context.Background(), NewSession(), invoiceLines, orderLines)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMatchSynapse_RepeatedItems(t *testing.T) {
+	pairs := `{"left": "Widget", "right": "widget", "score": 0.9}, {"left": "Widget", "right": "widget", "score": 0.9}`
+	synapse, _ := Match("product", NewMockProviderWithResponse(`{"pairs": [`+pairs+`], "confidence": 0.8, "reasoning": ["x"]}`))
+
+	response, err := synapse.Fire(context.Background(), NewSession(), []string{"Widget", "Gadget", "Widget"}, []string{"widget", "widget", "widget"})
+	if err != nil {
+		t.Fatalf("expected an item listed twice to be usable twice, got %v", err)
+	}
+	if !slices.Equal(response.UnmatchedLeft, []string{"Gadget"}) || !slices.Equal(response.UnmatchedRight, []string{"widget"}) {
+		t.Errorf("unexpected unmatched items: %v, %v", response.UnmatchedLeft, response.UnmatchedRight)
+	}
+}
+
+func TestMatchSynapse_ManyToMany(t *testing.T) {
+	t.Run("items in several pairs", func(t *testing.T) {
+		var prompt string
+		pairs := `{"left": "Widget x2", "right": "2 widgets", "score": 0.6}, {"left": "Widget x2", "right": "1 gadget", "score": 0.4}`
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"pairs": [` + pairs + `], "confidence": 0.8, "reasoning": ["x"]}`, nil
+		})
+		synapse, _ := Match("product", provider)
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), MatchInput{Left: invoiceLines, Right: orderLines, ManyToMany: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(response.UnmatchedLeft, []string{"Gadget x1", "Shipping"}) || !slices.Equal(response.UnmatchedRight, []string{"Sprocket x4"}) {
+			t.Errorf("unexpected unmatched items: %v, %v", response.UnmatchedLeft, response.UnmatchedRight)
+		}
+		if !strings.Contains(prompt, "paired with several items") {
+			t.Errorf("expected many-to-many constraint, got %s", prompt)
+		}
+	})
+
+	t.Run("repeated pair", func(t *testing.T) {
+		pairs := `{"left": "Widget x2", "right": "2 widgets", "score": 0.6}, {"left": "Widget x2", "right": "2 widgets", "score": 0.6}`
+		synapse, _ := Match("product", NewMockProviderWithResponse(`{"pairs": [`+pairs+`], "confidence": 0.8, "reasoning": ["x"]}`))
+
+		_, err := synapse.FireWithInput(context.Background(), NewSession(), MatchInput{Left: invoiceLines, Right: orderLines, ManyToMany: true})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "already paired") {
+			t.Errorf("expected ErrInvalidResponse for a repeated pair, got %v", err)
+		}
+	})
+}
+
+func TestMatchSynapse_EmptyList(t *testing.T) {
+	provider := NewMockProviderWithName("mock")
+	synapse, _ := Match("product", provider)
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), invoiceLines, nil)
+	if !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+	if result.Provider != "mock" {
+		t.Errorf("expected provider name on the result, got %q", result.Provider)
+	}
+}
+
+func TestMatchSynapse_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return invoicePairs, nil
+	})
+	synapse, _ := Match("product", provider)
+	synapse.WithDefaults(MatchInput{Right: orderLines, Context: "quantities must agree"})
+
+	if _, err := synapse.FireWithInput(context.Background(), NewSession(), MatchInput{Left: invoiceLines}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "R1. 2 widgets") || !strings.Contains(prompt, "quantities must agree") {
+		t.Errorf("expected defaults in prompt, got %s", prompt)
+	}
+}
+
+func TestMatchSynapse_Invoke(t *testing.T) {
+	synapse, _ := Match("product", NewMockProviderWithResponse(invoicePairs))
+
+	validator, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{
+		Items: invoiceLines,
+		Input: strings.Join(orderLines, "\n"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(MatchResponse); !ok || len(response.Pairs) != 2 {
+		t.Errorf("expected MatchResponse, got %#v", validator)
+	}
+}
+
+func TestMatchSynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"pairs": [{"left": "Widgets", "right": "2 widgets", "score": 0.9}], "confidence": 0.8, "reasoning": ["x"]}`, nil
+		}
+		return invoicePairs, nil
+	})
+	synapse, _ := Match("product", provider, WithValidationRetry(2))
+
+	response, err := synapse.Fire(context.Background(), NewSession(), invoiceLines, orderLines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(response.Pairs) != 2 {
+		t.Errorf("expected a retry after the invented item, got %d calls and %+v", calls, response)
+	}
+}
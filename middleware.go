@@ -0,0 +1,139 @@
+package zyn
+
+import "context"
+
+// ProviderMiddleware wraps a provider with cross-cutting behavior, such as
+// refreshing credentials, audit logging, or injecting headers, by returning
+// a provider whose Call runs around next's.
+type ProviderMiddleware func(next Provider) Provider
+
+// CallFunc is the signature of Provider.Call.
+type CallFunc func(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error)
+
+// CallMiddleware returns a ProviderMiddleware from a function wrapping Call,
+// for middleware that only needs to run around calls. The provider it
+// returns keeps next's name and runs wrap around calls with tools as well,
+// when next implements ToolProvider.
+//
+// Example:
+//
+//	timing := zyn.CallMiddleware(func(next zyn.CallFunc) zyn.CallFunc {
+//	    return func(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+//	        start := time.Now()
+//	        resp, err := next(ctx, messages, temperature)
+//	        log.Printf("provider call took %v", time.Since(start))
+//	        return resp, err
+//	    }
+//	})
+func CallMiddleware(wrap func(next CallFunc) CallFunc) ProviderMiddleware {
+	return func(next Provider) Provider {
+		return &middlewareProvider{next: next, wrap: wrap, call: wrap(next.Call)}
+	}
+}
+
+// middlewareProvider is the provider returned by a CallMiddleware.
+type middlewareProvider struct {
+	next Provider
+	wrap func(next CallFunc) CallFunc
+	call CallFunc // wrap applied to next.Call
+}
+
+func (p *middlewareProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	return p.call(ctx, messages, temperature)
+}
+
+func (p *middlewareProvider) Name() string {
+	return p.next.Name()
+}
+
+// CallWithTools runs wrap around next's CallWithTools.
+func (p *middlewareProvider) CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error) {
+	toolProvider, ok := p.next.(ToolProvider)
+	if !ok {
+		return nil, &ToolsUnsupportedError{Provider: p.next.Name(), Tools: len(tools)}
+	}
+	return p.wrap(func(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+		return toolProvider.CallWithTools(ctx, messages, temperature, tools)
+	})(ctx, messages, temperature)
+}
+
+// WrapProvider applies middlewares to provider in order: the first
+// middleware is outermost, so it sees each call first and its result last.
+//
+// The returned provider keeps provider's name whatever the middlewares
+// report, and its capabilities, token estimates, health checks, and Close,
+// which do not pass through the middlewares. Calls with tools pass through them
+// when the outermost middleware's provider implements ToolProvider, and
+// otherwise fail with a *ToolsUnsupportedError.
+//
+// Example:
+//
+//	provider := zyn.WrapProvider(openai.New(config), refreshToken, auditLog)
+func WrapProvider(provider Provider, middlewares ...ProviderMiddleware) Provider {
+	wrapped := provider
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		wrapped = middlewares[i](wrapped)
+	}
+	if _, ok := provider.(HealthChecker); ok {
+		return &checkedWrappedProvider{wrappedProvider{provider: provider, wrapped: wrapped}}
+	}
+	return &wrappedProvider{provider: provider, wrapped: wrapped}
+}
+
+// wrappedProvider is the provider returned by WrapProvider.
+type wrappedProvider struct {
+	provider Provider // The provider the middlewares wrap
+	wrapped  Provider // The outermost middleware's provider
+}
+
+// Call runs the call through the middlewares.
+func (p *wrappedProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	return p.wrapped.Call(ctx, messages, temperature)
+}
+
+// Name returns the wrapped provider's name.
+func (p *wrappedProvider) Name() string {
+	return p.provider.Name()
+}
+
+// CallWithTools runs a call with tools through the middlewares.
+func (p *wrappedProvider) CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error) {
+	if len(tools) == 0 {
+		return p.Call(ctx, messages, temperature)
+	}
+	toolProvider, ok := p.wrapped.(ToolProvider)
+	if !ok {
+		return nil, &ToolsUnsupportedError{Provider: p.Name(), Tools: len(tools)}
+	}
+	return toolProvider.CallWithTools(ctx, messages, temperature, tools)
+}
+
+// Capabilities returns the wrapped provider's capabilities, if it
+// advertises any.
+func (p *wrappedProvider) Capabilities() Capabilities {
+	if capable, ok := p.provider.(CapabilitiesProvider); ok {
+		return capable.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// EstimateMessages estimates tokens with the wrapped provider's estimator.
+func (p *wrappedProvider) EstimateMessages(messages []Message) int {
+	return estimatorFor(p.provider).EstimateMessages(messages)
+}
+
+// Close closes the wrapped provider, if it implements io.Closer.
+func (p *wrappedProvider) Close() error {
+	return closeProvider(p.provider)
+}
+
+// checkedWrappedProvider is the provider returned by WrapProvider for a
+// provider with a health check.
+type checkedWrappedProvider struct {
+	wrappedProvider
+}
+
+// Ping checks the wrapped provider.
+func (p *checkedWrappedProvider) Ping(ctx context.Context) error {
+	return p.provider.(HealthChecker).Ping(ctx)
+}
@@ -0,0 +1,182 @@
+package zyn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logDuration is a middleware logging how long each call took.
+func logDuration(logger *log.Logger) ProviderMiddleware {
+	return func(next Provider) Provider {
+		return CallMiddleware(func(call CallFunc) CallFunc {
+			return func(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+				start := time.Now()
+				resp, err := call(ctx, messages, temperature)
+				logger.Printf("%s call took %v (error: %v)", next.Name(), time.Since(start), err)
+				return resp, err
+			}
+		})(next)
+	}
+}
+
+// trace is a middleware recording when calls enter and leave it.
+func trace(name string, mu *sync.Mutex, events *[]string) ProviderMiddleware {
+	return CallMiddleware(func(next CallFunc) CallFunc {
+		return func(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+			mu.Lock()
+			*events = append(*events, name+" in")
+			mu.Unlock()
+			resp, err := next(ctx, messages, temperature)
+			mu.Lock()
+			*events = append(*events, name+" out")
+			mu.Unlock()
+			return resp, err
+		}
+	})
+}
+
+func TestWrapProvider_LogsDuration(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	provider := WrapProvider(NewMockProviderWithName("mock"), logDuration(logger))
+	synapse, _ := Binary("Is this text positive?", provider)
+	if _, err := synapse.Fire(context.Background(), NewSession(), "great product"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "mock call took ") || !strings.HasSuffix(lines[0], "(error: <nil>)") {
+		t.Errorf("expected one duration line, got %q", buf.String())
+	}
+}
+
+func TestWrapProvider_Order(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	provider := WrapProvider(NewMockProvider(), trace("outer", &mu, &events), trace("inner", &mu, &events))
+
+	if _, err := provider.Call(context.Background(), userMessage("input"), 0.1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"outer in", "inner in", "inner out", "outer out"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, events)
+	}
+}
+
+func TestWrapProvider_PreservesName(t *testing.T) {
+	rename := func(Provider) Provider { return NewMockProviderWithName("renamed") }
+
+	provider := WrapProvider(NewMockProviderWithName("openai"), rename)
+	if provider.Name() != "openai" {
+		t.Errorf("expected name openai, got %s", provider.Name())
+	}
+
+	synapse, _ := Binary("Is this valid?", provider)
+	result, err := synapse.FireResult(context.Background(), NewSession(), "input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Provider != "openai" {
+		t.Errorf("expected result from openai, got %s", result.Provider)
+	}
+}
+
+func TestWrapProvider_Errors(t *testing.T) {
+	inner := NewMockProviderWithName("mock")
+	inner.SetAvailable(false)
+
+	var buf bytes.Buffer
+	provider := WrapProvider(inner, logDuration(log.New(&buf, "", 0)))
+	if _, err := provider.Call(context.Background(), userMessage("input"), 0.1); err == nil {
+		t.Fatal("expected the inner error")
+	}
+	if strings.Contains(buf.String(), "<nil>") {
+		t.Errorf("expected the error logged, got %q", buf.String())
+	}
+
+	failing := CallMiddleware(func(CallFunc) CallFunc {
+		return func(context.Context, []Message, float32) (*ProviderResponse, error) {
+			return nil, errors.New("token refresh failed")
+		}
+	})
+	if _, err := WrapProvider(NewMockProvider(), failing).Call(context.Background(), userMessage("input"), 0.1); err == nil || err.Error() != "token refresh failed" {
+		t.Errorf("expected the middleware's error, got %v", err)
+	}
+}
+
+func TestWrapProvider_Tools(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	tools := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+	provider := WrapProvider(tools, trace("audit", &mu, &events))
+
+	toolProvider, ok := provider.(ToolProvider)
+	if !ok {
+		t.Fatal("expected a ToolProvider")
+	}
+	response, err := toolProvider.CallWithTools(context.Background(), userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+	if err != nil || len(response.ToolCalls) != 1 {
+		t.Fatalf("expected tool calls, got %+v, %v", response, err)
+	}
+	if len(tools.tools) != 1 || len(events) != 2 {
+		t.Errorf("expected the tool call through the middleware, got %d calls and events %v", len(tools.tools), events)
+	}
+
+	plain := WrapProvider(NewMockProviderWithName("plain"), trace("audit", &mu, &events)).(ToolProvider)
+	_, err = plain.CallWithTools(context.Background(), userMessage("order A-1"), 0.1, []Tool{lookupOrder})
+	var unsupported *ToolsUnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Provider != "plain" {
+		t.Errorf("expected ToolsUnsupportedError, got %v", err)
+	}
+}
+
+func TestWrapProvider_OptionalInterfaces(t *testing.T) {
+	capable := &capableProvider{MockProvider: NewMockProviderWithName("vision"), capabilities: Capabilities{Vision: true, Model: "gpt-4o"}}
+	wrapped := WrapProvider(capable, logDuration(log.New(&bytes.Buffer{}, "", 0)))
+
+	if got := wrapped.(CapabilitiesProvider).Capabilities(); got != capable.capabilities {
+		t.Errorf("expected inner capabilities, got %+v", got)
+	}
+
+	capable.SetAvailable(false)
+	checker, ok := wrapped.(HealthChecker)
+	if !ok || checker.Ping(context.Background()) == nil {
+		t.Error("expected the inner health check")
+	}
+
+	estimating := WrapProvider(&countingEstimator{tokens: 42})
+	if got := estimating.(TokenEstimator).EstimateMessages(nil); got != 42 {
+		t.Errorf("expected inner estimate, got %d", got)
+	}
+	if _, ok := estimating.(HealthChecker); ok {
+		t.Error("expected no health check for a provider without one")
+	}
+}
+
+func TestWrapProvider_Close(t *testing.T) {
+	inner := NewMockProviderWithName("inner")
+	provider := WrapProvider(inner, logDuration(log.New(&bytes.Buffer{}, "", 0)))
+
+	if err := CloseProviders(provider); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inner.Closed() {
+		t.Error("expected the wrapped provider closed")
+	}
+	if _, err := provider.Call(context.Background(), userMessage("input"), 0.1); !errors.Is(err, ErrProviderClosed) {
+		t.Errorf("expected ErrProviderClosed through the middleware, got %v", err)
+	}
+}
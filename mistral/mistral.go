@@ -0,0 +1,330 @@
+package mistral
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+// Provider implements the zyn Provider interface for the Mistral API.
+type Provider struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+	name       string
+	vision     bool
+	httpConfig zyn.HTTPConfig
+	httpErr    error       // Invalid HTTPConfig, reported by every call
+	closed     atomic.Bool // Set by Close
+}
+
+// Config holds configuration for the Mistral provider.
+type Config struct {
+	APIKey  string
+	Model   string        // e.g. "mistral-large-latest", "mistral-small-latest"
+	BaseURL string        // Optional, defaults to "https://api.mistral.ai/v1"
+	Timeout time.Duration // Optional, defaults to 30s; unused with HTTPConfig.HTTPClient
+	Vision  bool          // Optional, set when the model accepts image inputs (e.g. "pixtral-large-latest")
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
+}
+
+// New creates a new Mistral provider.
+// If config.Headers would override a credential header, every call fails
+// with the error from config.HTTPConfig.Validate.
+func New(config Config) *Provider {
+	if config.Model == "" {
+		config.Model = "mistral-small-latest"
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.mistral.ai/v1"
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &Provider{
+		apiKey:     config.APIKey,
+		model:      config.Model,
+		baseURL:    config.BaseURL,
+		name:       "mistral",
+		vision:     config.Vision,
+		httpConfig: config.HTTPConfig,
+		httpErr:    config.HTTPConfig.Validate(),
+		httpClient: config.HTTPConfig.Client(config.Timeout),
+	}
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Call sends messages to Mistral and returns the response with usage stats.
+// Requests Mistral rejects as invalid (422) fail with zyn.ErrNotRetryable.
+func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
+	startTime := time.Now()
+
+	// Emit provider.call.started hook
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(p.model),
+	)...)
+
+	// Convert zyn.Message to mistral message format
+	apiMessages := make([]requestMessage, len(messages))
+	for i, msg := range messages {
+		apiMessages[i] = newRequestMessage(msg)
+	}
+
+	// Build request body with JSON mode enabled
+	requestBody := chatCompletionRequest{
+		Model:       p.model,
+		Messages:    apiMessages,
+		Temperature: temperature,
+		ResponseFormat: &responseFormat{
+			Type: "json_object",
+		},
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	p.httpConfig.Apply(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	// Make the request
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Handle errors
+	if resp.StatusCode != http.StatusOK {
+		duration := time.Since(startTime)
+
+		// Emit provider.call.failed hook
+		fields := []capitan.Field{
+			zyn.ProviderKey.Field(p.name),
+			zyn.ModelKey.Field(p.model),
+			zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+			zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+		}
+
+		detail := fmt.Sprintf("status %d", resp.StatusCode)
+		var errorResp errorResponse
+		if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.text() != "" {
+			detail = errorResp.text()
+			fields = append(fields, zyn.ErrorKey.Field(detail))
+			if errorResp.Type != "" {
+				fields = append(fields, zyn.APIErrorTypeKey.Field(errorResp.Type))
+			}
+			if code := rawText(errorResp.Code); code != "" {
+				fields = append(fields, zyn.APIErrorCodeKey.Field(code))
+			}
+		} else {
+			fields = append(fields, zyn.ErrorKey.Field(detail))
+		}
+
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
+
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, detail)
+		case http.StatusUnprocessableEntity:
+			return nil, fmt.Errorf("%w: mistral error (%d): %s", zyn.ErrNotRetryable, resp.StatusCode, detail)
+		}
+		return nil, fmt.Errorf("mistral error (%d): %s", resp.StatusCode, detail)
+	}
+
+	// Parse successful response
+	var completionResp chatCompletionResponse
+	if err := json.Unmarshal(body, &completionResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(completionResp.Choices) == 0 {
+		return nil, fmt.Errorf("no response choices returned")
+	}
+
+	// Calculate duration
+	duration := time.Since(startTime)
+
+	// Emit provider.call.completed hook with token usage and metadata
+	fields := []capitan.Field{
+		zyn.ProviderKey.Field(p.name),
+		zyn.ModelKey.Field(completionResp.Model),
+		zyn.PromptTokensKey.Field(completionResp.Usage.PromptTokens),
+		zyn.CompletionTokensKey.Field(completionResp.Usage.CompletionTokens),
+		zyn.TotalTokensKey.Field(completionResp.Usage.TotalTokens),
+		zyn.DurationMsKey.Field(int(duration.Milliseconds())),
+		zyn.HTTPStatusCodeKey.Field(resp.StatusCode),
+		zyn.ResponseIDKey.Field(completionResp.ID),
+		zyn.ResponseCreatedKey.Field(int(completionResp.Created)),
+	}
+
+	if completionResp.Choices[0].FinishReason != "" {
+		fields = append(fields, zyn.ResponseFinishReasonKey.Field(completionResp.Choices[0].FinishReason))
+	}
+
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
+
+	return &zyn.ProviderResponse{
+		Content:   completionResp.Choices[0].Message.Content,
+		Truncated: completionResp.Choices[0].FinishReason == "length",
+		Usage: zyn.TokenUsage{
+			Prompt:     completionResp.Usage.PromptTokens,
+			Completion: completionResp.Usage.CompletionTokens,
+			Total:      completionResp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// Request/Response types for Mistral API
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatCompletionRequest struct {
+	Model          string           `json:"model"`
+	Messages       []requestMessage `json:"messages"`
+	Temperature    float32          `json:"temperature"`
+	ResponseFormat *responseFormat  `json:"response_format,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// requestMessage is a message sent to the API. Content is a string, or a list
+// of content chunks when images are attached.
+type requestMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+// contentChunk is a text or image chunk. Unlike OpenAI, Mistral takes the
+// image URL as a plain string.
+type contentChunk struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// newRequestMessage converts a zyn.Message, sending attached images as
+// image_url chunks after the text.
+func newRequestMessage(msg zyn.Message) requestMessage {
+	if len(msg.Images) == 0 {
+		return requestMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	chunks := make([]contentChunk, 0, len(msg.Images)+1)
+	chunks = append(chunks, contentChunk{Type: "text", Text: msg.Content})
+	for _, image := range msg.Images {
+		chunks = append(chunks, contentChunk{Type: "image_url", ImageURL: image.DataURL()})
+	}
+	return requestMessage{Role: msg.Role, Content: chunks}
+}
+
+type chatCompletionResponse struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []choice `json:"choices"`
+	Usage   usage    `json:"usage"`
+}
+
+type choice struct {
+	Index        int     `json:"index"`
+	Message      message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+}
+
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// errorResponse is Mistral's error body. Message is a string, or an object
+// with validation details for 422 responses; Code may be a string or number.
+type errorResponse struct {
+	Message json.RawMessage `json:"message"`
+	Detail  json.RawMessage `json:"detail"`
+	Type    string          `json:"type"`
+	Code    json.RawMessage `json:"code"`
+}
+
+// text returns the error's message, or its details as JSON when it has no
+// plain message.
+func (e errorResponse) text() string {
+	if message := rawText(e.Message); message != "" {
+		return message
+	}
+	return rawText(e.Detail)
+}
+
+// rawText returns a JSON string's value, other JSON values as written, and
+// an empty string for null or missing values.
+func rawText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	if trimmed := strings.TrimSpace(string(raw)); trimmed != "null" {
+		return trimmed
+	}
+	return ""
+}
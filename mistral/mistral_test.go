@@ -0,0 +1,397 @@
+package mistral
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/zyn"
+)
+
+func TestProviderCall(t *testing.T) {
+	ctx := context.Background()
+	// Create a test server that mimics the Mistral API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("Expected /chat/completions, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected Bearer token, got %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", r.Header.Get("Content-Type"))
+		}
+
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.Model != "mistral-large-latest" {
+			t.Errorf("Expected model mistral-large-latest, got %s", req.Model)
+		}
+		if req.Temperature != 0.7 {
+			t.Errorf("Expected temperature 0.7, got %f", req.Temperature)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+			t.Errorf("Expected JSON mode, got %+v", req.ResponseFormat)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Content != "test prompt" {
+			t.Errorf("Unexpected prompt: %v", req.Messages)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"id": "cmpl-1",
+			"object": "chat.completion",
+			"created": 1234567890,
+			"model": "mistral-large-latest",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "test response"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+		}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var status, total int
+	var model string
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		status, _ = zyn.HTTPStatusCodeKey.From(e)
+		total, _ = zyn.TotalTokensKey.From(e)
+		model, _ = zyn.ModelKey.From(e)
+		if _, ok := zyn.DurationMsKey.From(e); !ok {
+			t.Error("Expected duration in hook")
+		}
+	})
+	defer listener.Close()
+
+	provider := New(Config{
+		APIKey:  "test-key",
+		Model:   "mistral-large-latest",
+		BaseURL: server.URL,
+	})
+
+	response, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test prompt"}}, 0.7)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if response.Content != "test response" {
+		t.Errorf("Expected 'test response', got '%s'", response.Content)
+	}
+	if response.Usage != (zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15}) {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+
+	wg.Wait()
+	if status != http.StatusOK || total != 15 || model != "mistral-large-latest" {
+		t.Errorf("Unexpected hook fields: status %d, total %d, model %q", status, total, model)
+	}
+}
+
+func TestMistralIntegration(t *testing.T) {
+	apiKey := os.Getenv("MISTRAL_API_KEY")
+	if apiKey == "" {
+		t.Skip("MISTRAL_API_KEY not set, skipping integration test")
+	}
+
+	ctx := context.Background()
+	provider := New(Config{
+		APIKey: apiKey,
+		Model:  "mistral-small-latest",
+	})
+
+	response, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: `Reply with {"status": "test successful"} and nothing else.`}}, 0.7)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if response.Content == "" {
+		t.Error("Expected non-empty response")
+	}
+
+	t.Logf("Response: %s", response.Content)
+}
+
+func TestProviderErrorHandling(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseBody  string
+		expectedError string
+		rateLimited   bool
+		notRetryable  bool
+	}{
+		{
+			name:          "Unauthorized",
+			statusCode:    http.StatusUnauthorized,
+			responseBody:  `{"message": "Unauthorized", "request_id": "abc"}`,
+			expectedError: "mistral error (401): Unauthorized",
+		},
+		{
+			name:       "Validation error",
+			statusCode: http.StatusUnprocessableEntity,
+			responseBody: `{
+				"object": "error",
+				"message": {"detail": [{"type": "missing", "loc": ["body", "messages"], "msg": "Field required"}]},
+				"type": "invalid_request_message_error",
+				"code": null
+			}`,
+			expectedError: "mistral error (422): {\"detail\"",
+			notRetryable:  true,
+		},
+		{
+			name:          "Rate limit error",
+			statusCode:    http.StatusTooManyRequests,
+			responseBody:  `{"object": "error", "message": "Requests rate limit exceeded", "type": "rate_limited", "code": "1300"}`,
+			expectedError: "rate limit exceeded: Requests rate limit exceeded",
+			rateLimited:   true,
+		},
+		{
+			name:          "Rate limit without body",
+			statusCode:    http.StatusTooManyRequests,
+			expectedError: "rate limit exceeded: status 429",
+			rateLimited:   true,
+		},
+		{
+			name:          "Generic error",
+			statusCode:    http.StatusInternalServerError,
+			responseBody:  `not json`,
+			expectedError: "mistral error (500): status 500",
+		},
+		{
+			name:          "Empty response",
+			statusCode:    http.StatusOK,
+			responseBody:  `{"choices": []}`,
+			expectedError: "no response choices returned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			provider := New(Config{
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+
+			_, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%s'", tt.expectedError, err.Error())
+			}
+			if errors.Is(err, zyn.ErrRateLimited) != tt.rateLimited {
+				t.Errorf("Expected rate limited %v, got %v", tt.rateLimited, err)
+			}
+			if errors.Is(err, zyn.ErrNotRetryable) != tt.notRetryable {
+				t.Errorf("Expected not retryable %v, got %v", tt.notRetryable, err)
+			}
+		})
+	}
+}
+
+func TestValidationErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"object": "error", "message": "Invalid response_format", "type": "invalid_request_error"}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	synapse, err := zyn.Binary("Is this valid?", provider, zyn.WithRetry(3))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); !errors.Is(err, zyn.ErrNotRetryable) {
+		t.Fatalf("Expected ErrNotRetryable, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 call, got %d", calls.Load())
+	}
+}
+
+func TestProviderName(t *testing.T) {
+	provider := New(Config{
+		APIKey: "test-key",
+		Model:  "mistral-large-latest",
+	})
+
+	name := provider.Name()
+	if name != "mistral" {
+		t.Errorf("Expected 'mistral', got '%s'", name)
+	}
+}
+
+func TestProviderCapabilities(t *testing.T) {
+	if New(Config{APIKey: "test-key"}).Capabilities().Vision {
+		t.Error("Expected vision to be off by default")
+	}
+	if !New(Config{APIKey: "test-key", Model: "pixtral-large-latest", Vision: true}).Capabilities().Vision {
+		t.Error("Expected vision when configured")
+	}
+	if model := New(Config{APIKey: "test-key"}).Capabilities().Model; model != "mistral-small-latest" {
+		t.Errorf("Expected default model, got %q", model)
+	}
+}
+
+func TestProviderCallWithImages(t *testing.T) {
+	var body struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{}"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", Model: "pixtral-large-latest", BaseURL: server.URL, Vision: true})
+	_, err := provider.Call(context.Background(), []zyn.Message{
+		{Role: zyn.RoleAssistant, Content: "earlier"},
+		{Role: zyn.RoleUser, Content: "describe", Images: []zyn.ImageInput{
+			{Data: []byte("hi"), MIME: "image/png"},
+		}},
+	}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if string(body.Messages[0].Content) != `"earlier"` {
+		t.Errorf("Expected plain string content without images, got %s", body.Messages[0].Content)
+	}
+
+	var chunks []contentChunk
+	if err := json.Unmarshal(body.Messages[1].Content, &chunks); err != nil {
+		t.Fatalf("Expected content chunks, got %s", body.Messages[1].Content)
+	}
+	if len(chunks) != 2 || chunks[0].Text != "describe" || chunks[1].ImageURL != "data:image/png;base64,aGk=" {
+		t.Errorf("Unexpected content chunks: %+v", chunks)
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	t.Run("custom headers", func(t *testing.T) {
+		provider := New(Config{
+			APIKey:  "test-key",
+			BaseURL: server.URL,
+			HTTPConfig: zyn.HTTPConfig{
+				UserAgent: "billing/2.3",
+				Headers:   map[string]string{"X-Team": "billing"},
+			},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != "billing/2.3" || got.Get("X-Team") != "billing" {
+			t.Errorf("Expected custom headers, got %v", got)
+		}
+	})
+
+	t.Run("rejects credential override", func(t *testing.T) {
+		got = nil
+		provider := New(Config{
+			APIKey:     "test-key",
+			BaseURL:    server.URL,
+			HTTPConfig: zyn.HTTPConfig{Headers: map[string]string{"Authorization": "stolen"}},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err == nil || !strings.Contains(err.Error(), "credentials") {
+			t.Errorf("Expected credential header rejected, got %v", err)
+		}
+		if got != nil {
+			t.Error("Expected no request sent")
+		}
+	})
+}
+
+func TestTruncatedResponse(t *testing.T) {
+	bodies := map[string]string{
+		"truncated": `{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": tr"}, "finish_reason": "length"}]}`,
+		"complete":  `{"choices": [{"message": {"role": "assistant", "content": "{}"}, "finish_reason": "stop"}]}`,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+			response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			if response.Truncated != (name == "truncated") {
+				t.Errorf("Expected truncated %t, got %t", name == "truncated", response.Truncated)
+			}
+		})
+	}
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 )
 
 // MockFixedProviderName is the name for the fixed mock provider.
@@ -15,6 +18,7 @@ const MockFixedProviderName = "mock-fixed"
 type MockProvider struct {
 	name      string
 	available bool
+	closed    atomic.Bool
 }
 
 // NewMockProvider creates a new mock provider for testing.
@@ -36,6 +40,9 @@ func NewMockProviderWithName(name string) *MockProvider {
 // Call simulates an LLM call with deterministic responses.
 // For testing, it uses the last message content as the prompt.
 func (m *MockProvider) Call(_ context.Context, messages []Message, _ float32) (*ProviderResponse, error) {
+	if m.closed.Load() {
+		return nil, &ProviderClosedError{Provider: m.name}
+	}
 	if !m.available {
 		return nil, fmt.Errorf("provider %s is unavailable", m.name)
 	}
@@ -67,10 +74,38 @@ func (m *MockProvider) SetAvailable(available bool) {
 	m.available = available
 }
 
+// Close closes the provider: later calls and pings fail with a
+// *ProviderClosedError. Closing again is a no-op.
+func (m *MockProvider) Close() error {
+	m.closed.Store(true)
+	return nil
+}
+
+// Closed reports whether Close was called.
+func (m *MockProvider) Closed() bool {
+	return m.closed.Load()
+}
+
+// Ping fails while the provider is set unavailable or closed.
+func (m *MockProvider) Ping(_ context.Context) error {
+	if m.closed.Load() {
+		return &ProviderClosedError{Provider: m.name}
+	}
+	if !m.available {
+		return fmt.Errorf("provider %s is unavailable", m.name)
+	}
+	return nil
+}
+
 // generateResponse creates a response based on prompt patterns.
 func (m *MockProvider) generateResponse(prompt string) string {
 	// Check for JSON response request
 	if strings.Contains(prompt, "Response JSON Schema:") {
+		// Entity extraction pattern
+		if strings.Contains(prompt, "entity types: ") {
+			return m.generateEntityResponse(prompt)
+		}
+
 		// Classification pattern
 		if strings.Contains(prompt, "Categories:") {
 			return m.generateClassificationResponse(prompt)
@@ -93,7 +128,7 @@ func (m *MockProvider) generateResponse(prompt string) string {
 
 		// Analyze pattern
 		if strings.Contains(prompt, "analyze") || strings.Contains(prompt, "Analyze") {
-			return `{"analysis": "mock analysis", "confidence": 0.9, "findings": ["finding1"], "reasoning": ["mock"]}`
+			return `{"analysis": "mock analysis", "confidence": 0.9, "findings": [{"title": "finding1", "severity": "info", "area": "mock", "field": "", "description": "mock finding", "recommendation": ""}], "reasoning": ["mock"]}`
 		}
 
 		// Binary decision pattern
@@ -141,12 +176,25 @@ func (*MockProvider) generateClassificationResponse(prompt string) string {
 func (*MockProvider) generateRankingResponse(prompt string) string {
 	items := extractItems(prompt)
 
+	// Scores fall evenly down the ranking; repeated items cannot be keyed, so
+	// their rankings carry no scores
+	scores := make(map[string]float64, len(items))
+	for i, item := range items {
+		if _, ok := scores[item]; ok {
+			scores = nil
+			break
+		}
+		scores[item] = 1 - float64(i)/float64(len(items))
+	}
+
 	response := struct {
-		Ranked     []string `json:"ranked"`
-		Confidence float64  `json:"confidence"`
-		Reasoning  []string `json:"reasoning"`
+		Ranked     []string           `json:"ranked"`
+		Scores     map[string]float64 `json:"scores,omitempty"`
+		Confidence float64            `json:"confidence"`
+		Reasoning  []string           `json:"reasoning"`
 	}{
 		Ranked:     items,
+		Scores:     scores,
 		Confidence: 0.85,
 		Reasoning:  []string{"Mock ranking"},
 	}
@@ -245,6 +293,54 @@ func extractItems(prompt string) []string {
 	return items
 }
 
+// generateEntityResponse creates entity extraction responses, labeling each
+// run of capitalized words in the input with the first declared entity type.
+func (*MockProvider) generateEntityResponse(prompt string) string {
+	types := extractEntityTypes(prompt)
+	entities := []Entity{}
+	var run []string
+	flush := func() {
+		if len(run) > 0 && len(types) > 0 {
+			text := strings.Join(run, " ")
+			entities = append(entities, Entity{Text: text, Type: types[0], Normalized: text})
+		}
+		run = nil
+	}
+	for _, word := range strings.Fields(extractSubject(prompt)) {
+		word = strings.Trim(word, ".,;:!?\"'()")
+		if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+			run = append(run, word)
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	response := EntityResponse{
+		Entities:   entities,
+		Confidence: 0.85,
+		Reasoning:  []string{"Mock entity extraction of capitalized names"},
+	}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return `{"entities": [], "confidence": 0.5, "reasoning": ["error"]}`
+	}
+	return string(jsonBytes)
+}
+
+// extractEntityTypes extracts the declared entity types from prompt.
+func extractEntityTypes(prompt string) []string {
+	idx := strings.Index(prompt, "entity types: ")
+	if idx == -1 {
+		return nil
+	}
+	line := prompt[idx+len("entity types: "):]
+	if end := strings.Index(line, "\n"); end != -1 {
+		line = line[:end]
+	}
+	return strings.Split(line, ", ")
+}
+
 // generateEmailValidationResponse creates email validation responses.
 func (*MockProvider) generateEmailValidationResponse(prompt string) string {
 	// Extract the subject from prompt
@@ -2,6 +2,8 @@ package zyn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -212,6 +214,35 @@ func TestMockProvider_SetAvailable(t *testing.T) {
 	})
 }
 
+func TestMockProvider_Ping(t *testing.T) {
+	provider := NewMockProviderWithName("test")
+	if err := provider.Ping(context.Background()); err != nil {
+		t.Errorf("Expected healthy provider, got %v", err)
+	}
+
+	provider.SetAvailable(false)
+	if err := provider.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("Expected unavailable error, got %v", err)
+	}
+}
+
+func TestMockProvider_Close(t *testing.T) {
+	provider := NewMockProviderWithName("test")
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	if _, err := provider.Call(context.Background(), []Message{{Role: RoleUser, Content: "test"}}, 0.5); !errors.Is(err, ErrProviderClosed) {
+		t.Errorf("Expected ErrProviderClosed from Call, got %v", err)
+	}
+	if err := provider.Ping(context.Background()); !errors.Is(err, ErrProviderClosed) {
+		t.Errorf("Expected ErrProviderClosed from Ping, got %v", err)
+	}
+}
+
 func TestNewMockProviderWithResponse(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		provider := NewMockProviderWithResponse(`{"test": "value"}`)
@@ -358,6 +389,17 @@ Items:
 		if !strings.Contains(response.Content, "apple") {
 			t.Errorf("Expected response to contain extracted item 'apple', got: %s", response.Content)
 		}
+
+		var ranking RankingResponse
+		if err := json.Unmarshal([]byte(response.Content), &ranking); err != nil {
+			t.Fatalf("Failed to parse ranking response: %v", err)
+		}
+		if len(ranking.Scores) != 3 {
+			t.Errorf("Expected a score per item, got %v", ranking.Scores)
+		}
+		if err := ranking.Validate(); err != nil {
+			t.Errorf("Expected mock scores to validate, got %v", err)
+		}
 	})
 
 	t.Run("empty_items", func(t *testing.T) {
@@ -423,6 +465,32 @@ Sentiment analysis required.`
 	})
 }
 
+func TestMockProvider_GenerateEntityResponse(t *testing.T) {
+	provider := NewMockProvider()
+	prompt := `Input: Satya Nadella runs Microsoft Corporation in Redmond.
+
+Response JSON Schema:
+{"type": "object"}
+
+Constraints:
+- type: exactly one of the entity types: person, organization`
+
+	response, err := provider.Call(context.Background(), []Message{{Role: RoleUser, Content: prompt}}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	var entities EntityResponse
+	if err := json.Unmarshal([]byte(response.Content), &entities); err != nil {
+		t.Fatalf("expected an entity response, got %s", response.Content)
+	}
+	if err := entities.Validate(); err != nil {
+		t.Errorf("expected a valid response, got %v", err)
+	}
+	if len(entities.Entities) != 3 || entities.Entities[1].Text != "Microsoft Corporation" || entities.Entities[1].Type != "person" {
+		t.Errorf("expected capitalized runs labeled with the first type, got %+v", entities.Entities)
+	}
+}
+
 func TestMockProvider_GenerateEmailValidationResponse(t *testing.T) {
 	t.Run("valid_email", func(t *testing.T) {
 		provider := NewMockProvider()
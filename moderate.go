@@ -0,0 +1,309 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultModerationThreshold is the severity at or above which a category
+// is a violation, unless a threshold override is set for it.
+const DefaultModerationThreshold = 0.5
+
+// Properties of ModerationResponse computed from the scores rather than
+// asked of the LLM.
+const (
+	moderationFlaggedProperty    = "flagged"
+	moderationViolationsProperty = "violations"
+)
+
+// ModerationInput contains rich input structure for moderation.
+type ModerationInput struct {
+	Content     string             // The content to moderate
+	Context     string             // Optional background, such as where the content was posted
+	Thresholds  map[string]float64 // Per-category threshold overrides; others use DefaultModerationThreshold
+	Temperature float32            // LLM temperature setting for this specific request
+}
+
+// ModerationResponse contains the response from a moderation synapse.
+// Flagged and Violations are computed from the category scores and
+// thresholds, not by the LLM.
+type ModerationResponse struct {
+	Flagged    bool               `json:"flagged"`    // Whether any category reached its threshold
+	Categories map[string]float64 `json:"categories"` // Severity per category, 0.0 to 1.0
+	Violations []string           `json:"violations"` // Categories that reached their threshold, most severe first
+	Confidence float64            `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string           `json:"reasoning"`  // Explanation of the scores
+}
+
+// Validate checks if the response is valid. Category names are checked
+// against the policy by the synapse.
+func (r ModerationResponse) Validate() error {
+	if len(r.Categories) == 0 {
+		return fmt.Errorf("categories required but empty")
+	}
+	for name, score := range r.Categories {
+		if score < 0 || score > 1 {
+			return fmt.Errorf("category %q: severity must be 0-1, got %f", name, score)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// WithThresholds returns the response with Flagged and Violations computed
+// from its scores for thresholds, so a policy change can be applied to a
+// stored response without calling the LLM again. Categories without a
+// threshold use DefaultModerationThreshold.
+func (r ModerationResponse) WithThresholds(thresholds map[string]float64) ModerationResponse {
+	r.Violations = nil
+	for name, score := range r.Categories {
+		threshold, ok := thresholds[name]
+		if !ok {
+			threshold = DefaultModerationThreshold
+		}
+		if score >= threshold {
+			r.Violations = append(r.Violations, name)
+		}
+	}
+	slices.SortFunc(r.Violations, func(a, b string) int {
+		if r.Categories[a] != r.Categories[b] {
+			if r.Categories[a] > r.Categories[b] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	r.Flagged = len(r.Violations) > 0
+	return r
+}
+
+// ModerationSynapse scores content against a policy's categories.
+type ModerationSynapse struct {
+	policy     string
+	categories []string
+	schema     string // Response schema without the computed properties
+	defaults   ModerationInput
+	base       *Base[ModerationInput, ModerationResponse]
+}
+
+// NewModeration creates a new moderation synapse bound to a provider.
+// Returns an error if no categories are given or the JSON schema cannot be
+// generated.
+func NewModeration(policy string, categories []string, provider Provider, opts ...Option) (*ModerationSynapse, error) {
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("moderation synapse: at least one category is required")
+	}
+	synapse := &ModerationSynapse{policy: policy, categories: slices.Clone(categories)}
+
+	base, err := NewSynapse(SynapseConfig[ModerationInput, ModerationResponse]{
+		Type:        "moderation",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The LLM only scores; flags are computed from the scores
+	schema := base.Schema()
+	for _, property := range []string{moderationFlaggedProperty, moderationViolationsProperty} {
+		if schema, err = omitProperty(schema, property); err != nil {
+			return nil, fmt.Errorf("moderation synapse: %w", err)
+		}
+	}
+	base.service.validate = synapse.validateCategories
+
+	synapse.schema = schema
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (m *ModerationSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return m.base.GetPipeline()
+}
+
+// WithDefaults creates a new Moderation with default input values.
+// These are merged with user input at execution time; threshold overrides
+// from both apply, the input's taking precedence.
+func (m *ModerationSynapse) WithDefaults(defaults ModerationInput) *ModerationSynapse {
+	m.defaults = defaults
+	return m
+}
+
+// Fire moderates content and reports whether it was flagged.
+func (m *ModerationSynapse) Fire(ctx context.Context, session *Session, content string) (bool, error) {
+	response, err := m.FireWithInput(ctx, session, ModerationInput{Content: content})
+	if err != nil {
+		return false, err
+	}
+	return response.Flagged, nil
+}
+
+// FireResult moderates content and returns whether it was flagged in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (m *ModerationSynapse) FireResult(ctx context.Context, session *Session, content string) (Result[bool], error) {
+	result, err := m.execute(ctx, session, ModerationInput{Content: content})
+	if err != nil {
+		return withValue(result, false), err
+	}
+	return withValue(result, result.Value.Flagged), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (m *ModerationSynapse) FireWithInput(ctx context.Context, session *Session, input ModerationInput) (ModerationResponse, error) {
+	result, err := m.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a ModerationResponse.
+func (m *ModerationSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := m.FireWithInput(ctx, session, ModerationInput{
+		Content:     input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, scores the content, and flags it
+// against the merged thresholds.
+func (m *ModerationSynapse) execute(ctx context.Context, session *Session, input ModerationInput) (Result[ModerationResponse], error) {
+	merged := m.mergeInputs(input)
+	for name, threshold := range merged.Thresholds {
+		if !slices.Contains(m.categories, name) {
+			return Result[ModerationResponse]{Provider: m.base.service.providerName},
+				fmt.Errorf("%w: threshold for unknown category %q", ErrInvalidPrompt, name)
+		}
+		if threshold < 0 || threshold > 1 {
+			return Result[ModerationResponse]{Provider: m.base.service.providerName},
+				fmt.Errorf("%w: threshold for %q must be 0-1, got %f", ErrInvalidPrompt, name, threshold)
+		}
+	}
+
+	result, err := m.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+	if err != nil {
+		return result, err
+	}
+	result.Value.Categories = m.canonicalScores(result.Value.Categories)
+	result.Value = result.Value.WithThresholds(merged.Thresholds)
+	return result, nil
+}
+
+// validateCategories checks that the response scores every category and
+// only those, matching names regardless of case.
+func (m *ModerationSynapse) validateCategories(_ *Prompt, response ModerationResponse) error {
+	for name := range response.Categories {
+		if m.category(name) == "" {
+			return fmt.Errorf("unknown category %q", name)
+		}
+	}
+	scores := m.canonicalScores(response.Categories)
+	if len(scores) != len(response.Categories) {
+		return fmt.Errorf("category scored more than once")
+	}
+	for _, name := range m.categories {
+		if _, ok := scores[name]; !ok {
+			return fmt.Errorf("category %q not scored", name)
+		}
+	}
+	return nil
+}
+
+// category returns the configured category matching name regardless of
+// case and surrounding space, or "" if there is none.
+func (m *ModerationSynapse) category(name string) string {
+	name = strings.TrimSpace(name)
+	for _, category := range m.categories {
+		if strings.EqualFold(category, name) {
+			return category
+		}
+	}
+	return ""
+}
+
+// canonicalScores returns scores keyed by the configured category names,
+// dropping unknown names.
+func (m *ModerationSynapse) canonicalScores(scores map[string]float64) map[string]float64 {
+	canonical := make(map[string]float64, len(scores))
+	for name, score := range scores {
+		if category := m.category(name); category != "" {
+			canonical[category] = score
+		}
+	}
+	return canonical
+}
+
+// mergeInputs combines defaults with user input.
+func (m *ModerationSynapse) mergeInputs(input ModerationInput) ModerationInput {
+	merged := m.defaults
+
+	if input.Content != "" {
+		merged.Content = input.Content
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if len(input.Thresholds) > 0 {
+		merged.Thresholds = maps.Clone(m.defaults.Thresholds)
+		if merged.Thresholds == nil {
+			merged.Thresholds = make(map[string]float64, len(input.Thresholds))
+		}
+		maps.Copy(merged.Thresholds, input.Thresholds)
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. Thresholds are
+// not part of the prompt, so changing them never changes the scores.
+func (m *ModerationSynapse) buildPrompt(input ModerationInput) *Prompt {
+	return &Prompt{
+		Task:       fmt.Sprintf("Score the content against this moderation policy: %s", m.policy),
+		Input:      input.Content,
+		Context:    input.Context,
+		Categories: m.categories,
+		Schema:     m.schema,
+		Constraints: []string{
+			"categories: a severity from 0.0 (absent) to 1.0 (severe) for every listed category, keyed by its exact name",
+			"score each category independently of the others",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the scores",
+		},
+	}
+}
+
+// Moderate creates a new moderation synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if no categories are given or the JSON schema cannot be
+// generated.
+//
+// Example:
+//
+//	moderator, err := Moderate("community guidelines", []string{"harassment", "hate", "spam"}, provider)
+//	response, err := moderator.FireWithInput(ctx, session, ModerationInput{
+//	    Content:    comment,
+//	    Thresholds: map[string]float64{"spam": 0.8},
+//	})
+func Moderate(policy string, categories []string, provider Provider, opts ...Option) (*ModerationSynapse, error) {
+	return NewModeration(policy, categories, provider, opts...)
+}
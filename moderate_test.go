@@ -0,0 +1,210 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// moderationCategories are the categories of the moderation tests.
+var moderationCategories = []string{"harassment", "hate", "spam"}
+
+func TestModerationResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := ModerationResponse{
+			Categories: map[string]float64{"spam": 0.2},
+			Confidence: 0.9,
+			Reasoning:  []string{"mostly benign"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_categories", func(t *testing.T) {
+		r := ModerationResponse{
+			Confidence: 0.9,
+			Reasoning:  []string{"mostly benign"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no categories")
+		}
+	})
+
+	t.Run("score_too_high", func(t *testing.T) {
+		r := ModerationResponse{
+			Categories: map[string]float64{"spam": 1.2},
+			Confidence: 0.9,
+			Reasoning:  []string{"mostly benign"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a score > 1")
+		}
+	})
+
+	t.Run("score_too_low", func(t *testing.T) {
+		r := ModerationResponse{
+			Categories: map[string]float64{"spam": -0.1},
+			Confidence: 0.9,
+			Reasoning:  []string{"mostly benign"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a negative score")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := ModerationResponse{
+			Categories: map[string]float64{"spam": 0.2},
+			Confidence: 2,
+			Reasoning:  []string{"mostly benign"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := ModerationResponse{
+			Categories: map[string]float64{"spam": 0.2},
+			Confidence: 0.9,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestModerationResponse_WithThresholds(t *testing.T) {
+	response := ModerationResponse{Categories: map[string]float64{"harassment": 0.6, "hate": 0.1, "spam": 0.9}}
+
+	flagged := response.WithThresholds(nil)
+	if !flagged.Flagged || strings.Join(flagged.Violations, ",") != "spam,harassment" {
+		t.Errorf("expected the default threshold applied, most severe first, got %+v", flagged)
+	}
+
+	relaxed := flagged.WithThresholds(map[string]float64{"harassment": 0.7, "spam": 0.95})
+	if relaxed.Flagged || len(relaxed.Violations) != 0 {
+		t.Errorf("expected nothing flagged at relaxed thresholds, got %+v", relaxed)
+	}
+
+	strict := response.WithThresholds(map[string]float64{"hate": 0.1})
+	if strings.Join(strict.Violations, ",") != "spam,harassment,hate" {
+		t.Errorf("expected a score at the threshold flagged, got %v", strict.Violations)
+	}
+}
+
+func TestModerationSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"flagged": false, "categories": {"Harassment": 0.7, "hate": 0.0, "SPAM": 0.1}, "violations": [], "confidence": 0.85, "reasoning": ["insults the reader"]}`, nil
+	})
+	synapse, err := Moderate("community guidelines", moderationCategories, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagged, err := synapse.Fire(context.Background(), NewSession(), "you are an idiot")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !flagged {
+		t.Error("expected the content flagged from its scores, not the model's flag")
+	}
+	for _, want := range []string{"community guidelines", "Input: you are an idiot", "1. harassment", "3. spam"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, `"flagged"`) || strings.Contains(prompt, `"violations"`) {
+		t.Errorf("expected the computed properties left out of the schema, got %s", prompt)
+	}
+
+	response, _ := synapse.FireWithInput(context.Background(), NewSession(), ModerationInput{Content: "you are an idiot"})
+	if response.Categories["harassment"] != 0.7 || response.Categories["spam"] != 0.1 {
+		t.Errorf("expected scores keyed by the configured names, got %v", response.Categories)
+	}
+	if len(response.Violations) != 1 || response.Violations[0] != "harassment" {
+		t.Errorf("expected harassment violated, got %v", response.Violations)
+	}
+}
+
+func TestModerationSynapse_Thresholds(t *testing.T) {
+	var prompts []string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompts = append(prompts, p)
+		return `{"categories": {"harassment": 0.3, "hate": 0.0, "spam": 0.6}, "confidence": 0.9, "reasoning": ["promotional"]}`, nil
+	})
+	synapse, _ := Moderate("community guidelines", moderationCategories, provider)
+	synapse.WithDefaults(ModerationInput{Thresholds: map[string]float64{"spam": 0.8, "harassment": 0.25}})
+
+	response, err := synapse.FireWithInput(context.Background(), NewSession(), ModerationInput{
+		Content:    "buy now",
+		Thresholds: map[string]float64{"harassment": 0.5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Flagged {
+		t.Errorf("expected spam below its default override and harassment below the input's, got %+v", response)
+	}
+
+	flagged, _ := synapse.Fire(context.Background(), NewSession(), "buy now")
+	if !flagged {
+		t.Error("expected harassment flagged at the default override")
+	}
+	if prompts[0] != prompts[1] {
+		t.Error("expected thresholds kept out of the prompt")
+	}
+
+	for name, thresholds := range map[string]map[string]float64{
+		"unknown category": {"violence": 0.5},
+		"out of range":     {"spam": 1.5},
+	} {
+		if _, err := synapse.FireWithInput(context.Background(), NewSession(), ModerationInput{Content: "x", Thresholds: thresholds}); !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("%s: expected ErrInvalidPrompt, got %v", name, err)
+		}
+	}
+}
+
+func TestModerationSynapse_InvalidScores(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"unknown category", `{"categories": {"harassment": 0.1, "hate": 0.1, "spam": 0.1, "violence": 0.9}, "confidence": 0.9, "reasoning": ["x"]}`},
+		{"missing category", `{"categories": {"harassment": 0.1, "hate": 0.1}, "confidence": 0.9, "reasoning": ["x"]}`},
+		{"duplicate category", `{"categories": {"harassment": 0.1, "Harassment": 0.2, "hate": 0.1, "spam": 0.1}, "confidence": 0.9, "reasoning": ["x"]}`},
+		{"out of range", `{"categories": {"harassment": 1.4, "hate": 0.1, "spam": 0.1}, "confidence": 0.9, "reasoning": ["x"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, _ := Moderate("community guidelines", moderationCategories, NewMockProviderWithResponse(tt.response))
+			if _, err := synapse.Fire(context.Background(), NewSession(), "x"); !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("expected ErrInvalidResponse, got %v", err)
+			}
+		})
+	}
+}
+
+func TestModerationSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"categories": {"harassment": 0.0, "hate": 0.0, "spam": 0.9}, "confidence": 0.9, "reasoning": ["x"]}`)
+	synapse, _ := Moderate("community guidelines", moderationCategories, provider)
+
+	result, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "buy now"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := result.(ModerationResponse); !ok || !response.Flagged {
+		t.Errorf("expected a flagged ModerationResponse, got %+v", result)
+	}
+}
+
+func TestModerate_RequiresCategories(t *testing.T) {
+	if _, err := Moderate("community guidelines", nil, NewMockProvider()); err == nil {
+		t.Error("expected an error without categories")
+	}
+}
@@ -16,4 +16,5 @@ require (
 	github.com/zoobzio/clockz v1.0.0 // indirect
 	github.com/zoobzio/pipz v1.0.4 // indirect
 	github.com/zoobzio/sentinel v1.0.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
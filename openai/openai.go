@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/capitan"
@@ -15,26 +17,71 @@ import (
 
 // Provider implements the zyn Provider interface for OpenAI API.
 type Provider struct {
-	apiKey     string
-	model      string
-	baseURL    string
-	httpClient *http.Client
-	name       string
+	apiKey       string
+	apiKeyHeader string
+	model        string
+	baseURL      string
+	httpClient   *http.Client
+	name         string
+	vision       bool
+	structured   bool   // Send response schemas as strict json_schema formats
+	logprobs     bool   // Request token log probabilities
+	topLogprobs  int    // Alternatives returned with each token's log probability
+	effort       string // Reasoning effort for reasoning models
+	encoding     *Encoding
+	httpConfig   zyn.HTTPConfig
+	httpErr      error       // Invalid HTTPConfig, reported by every call
+	closed       atomic.Bool // Set by Close
 }
 
 // Config holds configuration for the OpenAI provider.
 type Config struct {
-	APIKey  string
-	Model   string        // e.g. "gpt-4", "gpt-3.5-turbo"
-	BaseURL string        // Optional, defaults to "https://api.openai.com/v1"
-	Timeout time.Duration // Optional, defaults to 30s
+	APIKey       string
+	APIKeyHeader string        // Optional header carrying APIKey as is, e.g. "api-key"; defaults to "Authorization: Bearer <key>"
+	Model        string        // e.g. "gpt-4", "gpt-3.5-turbo"
+	BaseURL      string        // Optional, defaults to "https://api.openai.com/v1"; any OpenAI-compatible server, e.g. "http://localhost:8000/v1"
+	Timeout      time.Duration // Optional, defaults to 30s; unused with HTTPConfig.HTTPClient
+	Vision       bool          // Optional, set when the model accepts image inputs (e.g. "gpt-4o")
+
+	// UseStructuredOutputs sends the synapse's response schema as a strict
+	// json_schema response format, so the model's output always matches it.
+	// It applies to models with structured-output support (gpt-4o and later,
+	// o1, o3 and o4-mini) and to schemas strict mode can express; other calls
+	// keep the json_object format with the schema in the prompt.
+	UseStructuredOutputs bool
+
+	// Logprobs returns the log probability of each response token in
+	// zyn.ProviderResponse.Logprobs, surfaced by FireResult as
+	// Result.Logprobs. TopLogprobs (0-20) adds the most likely alternatives
+	// at each position.
+	Logprobs    bool
+	TopLogprobs int
+
+	// Encoding counts prompt tokens for zyn's prompt size limits, loaded
+	// with LoadEncoding for the model's EncodingForModel. Without it,
+	// prompts are estimated with zyn.HeuristicEstimator.
+	Encoding *Encoding
+
+	// ReasoningEffort sets how much reasoning models (o1, o3, o4-mini,
+	// gpt-5) think before answering: "low", "medium" or "high", and
+	// "minimal" for gpt-5. Empty keeps the model's default, and other
+	// models ignore it. Reasoning tokens are reported in
+	// zyn.TokenUsage.Reasoning.
+	ReasoningEffort string
+
+	zyn.HTTPConfig // Optional User-Agent and custom headers for every request
 }
 
-// New creates a new OpenAI provider.
+// New creates a new OpenAI provider. Trailing slashes in config.BaseURL are
+// ignored. When config.APIKey is empty no credential header is sent, for
+// local servers without authentication.
+// If config.Headers would override a credential header, including
+// config.APIKeyHeader, every call fails with an error describing it.
 func New(config Config) *Provider {
 	if config.Model == "" {
 		config.Model = "gpt-3.5-turbo"
 	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
 	if config.BaseURL == "" {
 		config.BaseURL = "https://api.openai.com/v1"
 	}
@@ -42,14 +89,31 @@ func New(config Config) *Provider {
 		config.Timeout = 30 * time.Second
 	}
 
+	httpErr := config.HTTPConfig.Validate()
+	if httpErr == nil && config.APIKeyHeader != "" {
+		for name := range config.Headers {
+			if strings.EqualFold(strings.TrimSpace(name), config.APIKeyHeader) {
+				httpErr = fmt.Errorf("http config: header %q carries provider credentials and cannot be set", name)
+				break
+			}
+		}
+	}
+
 	return &Provider{
-		apiKey:  config.APIKey,
-		model:   config.Model,
-		baseURL: config.BaseURL,
-		name:    "openai",
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		apiKey:       config.APIKey,
+		apiKeyHeader: config.APIKeyHeader,
+		model:        config.Model,
+		baseURL:      config.BaseURL,
+		name:         "openai",
+		vision:       config.Vision,
+		structured:   config.UseStructuredOutputs && supportsStructuredOutputs(config.Model),
+		logprobs:     config.Logprobs,
+		topLogprobs:  config.TopLogprobs,
+		encoding:     config.Encoding,
+		effort:       config.ReasoningEffort,
+		httpConfig:   config.HTTPConfig,
+		httpErr:      httpErr,
+		httpClient:   config.HTTPConfig.Client(config.Timeout),
 	}
 }
 
@@ -58,34 +122,82 @@ func (p *Provider) Name() string {
 	return p.name
 }
 
+// Capabilities returns the features the configured model supports.
+func (p *Provider) Capabilities() zyn.Capabilities {
+	return zyn.Capabilities{Vision: p.vision, Model: p.model}
+}
+
+// Close releases the provider's idle HTTP connections. Calls and pings after Close
+// fail with a *zyn.ProviderClosedError; calls in flight complete. Closing
+// again is a no-op.
+func (p *Provider) Close() error {
+	if !p.closed.Swap(true) {
+		p.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
 // Call sends messages to OpenAI and returns the response with usage stats.
 // OpenAI automatically handles prompt caching for prompts >1024 tokens.
 func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	return p.CallWithTools(ctx, messages, temperature, nil)
+}
+
+// CallWithTools is like Call, offering tools as functions the model may call.
+// Calls the model makes are returned in the response's ToolCalls.
+func (p *Provider) CallWithTools(ctx context.Context, messages []zyn.Message, temperature float32, tools []zyn.Tool) (*zyn.ProviderResponse, error) {
+	if p.httpErr != nil {
+		return nil, p.httpErr
+	}
+	if p.closed.Load() {
+		return nil, &zyn.ProviderClosedError{Provider: p.name}
+	}
+
 	startTime := time.Now()
 
 	// Emit provider.call.started hook
-	capitan.Info(ctx, zyn.ProviderCallStarted,
+	capitan.Info(ctx, zyn.ProviderCallStarted, zyn.HookFields(ctx,
 		zyn.ProviderKey.Field(p.name),
 		zyn.ModelKey.Field(p.model),
-	)
+	)...)
 
 	// Convert zyn.Message to openai message format
-	apiMessages := make([]message, len(messages))
+	apiMessages := make([]requestMessage, len(messages))
 	for i, msg := range messages {
-		apiMessages[i] = message{
-			Role:    msg.Role,
-			Content: msg.Content,
-		}
+		apiMessages[i] = newRequestMessage(msg)
 	}
 
 	// Build request body with JSON mode enabled
 	requestBody := chatCompletionRequest{
-		Model:       p.model,
-		Messages:    apiMessages,
-		Temperature: temperature,
-		ResponseFormat: &responseFormat{
-			Type: "json_object",
-		},
+		Model:          p.model,
+		Messages:       apiMessages,
+		ResponseFormat: p.responseFormat(ctx),
+	}
+	if isReasoningModel(p.model) {
+		// Reasoning models reject any temperature but the default.
+		requestBody.ReasoningEffort = p.effort
+	} else {
+		requestBody.Temperature = &temperature
+	}
+	if p.logprobs {
+		requestBody.Logprobs = true
+		requestBody.TopLogprobs = p.topLogprobs
+	}
+	if params, ok := zyn.CallParamsFromContext(ctx); ok {
+		requestBody.Seed = params.Seed
+		requestBody.TopP = params.TopP
+		requestBody.Stop = params.Stop
+		if isReasoningModel(p.model) {
+			requestBody.MaxCompletionTokens = params.MaxTokens
+		} else {
+			requestBody.MaxTokens = params.MaxTokens
+		}
+	}
+	for _, tool := range tools {
+		requestBody.Tools = append(requestBody.Tools, requestTool{
+			Type:     "function",
+			Function: functionDefinition{Name: tool.Name, Description: tool.Description, Parameters: tool.Parameters},
+		})
 	}
 
 	jsonBody, err := json.Marshal(requestBody)
@@ -99,8 +211,9 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	p.httpConfig.Apply(req)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	p.authorize(req)
 
 	// Make the request
 	resp, err := p.httpClient.Do(req)
@@ -137,17 +250,20 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 				fields = append(fields, zyn.APIErrorCodeKey.Field(errorResp.Error.Code))
 			}
 
-			capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+			capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
 
 			// Check for rate limit
 			if resp.StatusCode == http.StatusTooManyRequests {
-				return nil, fmt.Errorf("rate limit exceeded: %s", errorResp.Error.Message)
+				return nil, fmt.Errorf("%w: %s", zyn.ErrRateLimited, errorResp.Error.Message)
 			}
 			return nil, fmt.Errorf("openai error (%d): %s", resp.StatusCode, errorResp.Error.Message)
 		}
 
 		fields = append(fields, zyn.ErrorKey.Field(fmt.Sprintf("status %d", resp.StatusCode)))
-		capitan.Error(ctx, zyn.ProviderCallFailed, fields...)
+		capitan.Error(ctx, zyn.ProviderCallFailed, zyn.HookFields(ctx, fields...)...)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, fmt.Errorf("%w: status %d", zyn.ErrRateLimited, resp.StatusCode)
+		}
 		return nil, fmt.Errorf("openai error: status %d", resp.StatusCode)
 	}
 
@@ -176,60 +292,321 @@ func (p *Provider) Call(ctx context.Context, messages []zyn.Message, temperature
 		zyn.ResponseIDKey.Field(completionResp.ID),
 		zyn.ResponseCreatedKey.Field(int(completionResp.Created)),
 	}
+	if reasoning := completionResp.Usage.CompletionTokensDetails.ReasoningTokens; reasoning > 0 {
+		fields = append(fields, zyn.ReasoningTokensKey.Field(reasoning))
+	}
 
 	if len(completionResp.Choices) > 0 && completionResp.Choices[0].FinishReason != "" {
 		fields = append(fields, zyn.ResponseFinishReasonKey.Field(completionResp.Choices[0].FinishReason))
 	}
+	if completionResp.SystemFingerprint != "" {
+		fields = append(fields, zyn.SystemFingerprintKey.Field(completionResp.SystemFingerprint))
+	}
 
-	capitan.Info(ctx, zyn.ProviderCallCompleted, fields...)
+	capitan.Info(ctx, zyn.ProviderCallCompleted, zyn.HookFields(ctx, fields...)...)
 
 	return &zyn.ProviderResponse{
-		Content: completionResp.Choices[0].Message.Content,
+		Content:   completionResp.Choices[0].Message.Content,
+		ToolCalls: newToolCalls(completionResp.Choices[0].Message.ToolCalls),
+		Truncated: completionResp.Choices[0].FinishReason == "length",
+		Logprobs:  newLogprobs(completionResp.Choices[0].Logprobs),
 		Usage: zyn.TokenUsage{
-			Prompt:     completionResp.Usage.PromptTokens,
-			Completion: completionResp.Usage.CompletionTokens,
-			Total:      completionResp.Usage.TotalTokens,
+			Prompt:       completionResp.Usage.PromptTokens,
+			Completion:   completionResp.Usage.CompletionTokens,
+			Total:        completionResp.Usage.TotalTokens,
+			CachedPrompt: completionResp.Usage.PromptTokensDetails.CachedTokens,
+			Reasoning:    completionResp.Usage.CompletionTokensDetails.ReasoningTokens,
 		},
 	}, nil
 }
 
+// Ping lists the available models, checking that the API is reachable and
+// accepts the credentials without calling a model.
+func (p *Provider) Ping(ctx context.Context) error {
+	if p.httpErr != nil {
+		return p.httpErr
+	}
+	if p.closed.Load() {
+		return &zyn.ProviderClosedError{Provider: p.name}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	p.httpConfig.Apply(req)
+	p.authorize(req)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body) //nolint:errcheck // the status is reported either way
+	var errorResp errorResponse
+	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error.Message != "" {
+		return fmt.Errorf("openai ping failed (%d): %s", resp.StatusCode, errorResp.Error.Message)
+	}
+	return fmt.Errorf("openai ping failed: status %d", resp.StatusCode)
+}
+
+// authorize sets the credential header, if the provider has an API key.
+func (p *Provider) authorize(req *http.Request) {
+	switch {
+	case p.apiKey == "":
+		// Local servers without authentication
+	case p.apiKeyHeader != "":
+		req.Header.Set(p.apiKeyHeader, p.apiKey)
+	default:
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+}
+
+// responseFormat returns a strict json_schema format for the call's response
+// schema when structured outputs apply, and the json_object format otherwise.
+func (p *Provider) responseFormat(ctx context.Context) *responseFormat {
+	if p.structured {
+		if params, ok := zyn.CallParamsFromContext(ctx); ok && params.ResponseSchema != "" {
+			if schema, ok := zyn.StrictSchema(params.ResponseSchema); ok {
+				return &responseFormat{
+					Type:       "json_schema",
+					JSONSchema: &jsonSchemaFormat{Name: "response", Strict: true, Schema: json.RawMessage(schema)},
+				}
+			}
+		}
+	}
+	return &responseFormat{Type: "json_object"}
+}
+
+// structuredOutputModels are the model families that accept json_schema
+// response formats, and structuredOutputSnapshots their models that predate
+// structured outputs.
+var (
+	structuredOutputModels    = []string{"gpt-4o", "gpt-4.1", "gpt-5", "o1", "o3", "o4-mini"}
+	structuredOutputSnapshots = []string{"gpt-4o-2024-05-13", "o1-preview", "o1-mini"}
+)
+
+// supportsStructuredOutputs reports whether the model accepts json_schema
+// response formats.
+func supportsStructuredOutputs(model string) bool {
+	for _, snapshot := range structuredOutputSnapshots {
+		if strings.HasPrefix(model, snapshot) {
+			return false
+		}
+	}
+	for _, family := range structuredOutputModels {
+		if strings.HasPrefix(model, family) {
+			return true
+		}
+	}
+	return false
+}
+
+// reasoningModels are the reasoning model families, which limit completions
+// with max_completion_tokens, take a reasoning effort, and reject
+// temperatures.
+var reasoningModels = []string{"o1", "o3", "o4-mini", "gpt-5"}
+
+// isReasoningModel reports whether the model is a reasoning model.
+func isReasoningModel(model string) bool {
+	for _, family := range reasoningModels {
+		if strings.HasPrefix(model, family) {
+			return true
+		}
+	}
+	return false
+}
+
 // Request/Response types for OpenAI API
 
 type responseFormat struct {
-	Type string `json:"type"`
+	Type       string            `json:"type"`
+	JSONSchema *jsonSchemaFormat `json:"json_schema,omitempty"`
+}
+
+type jsonSchemaFormat struct {
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
 }
 
 type chatCompletionRequest struct {
-	Model          string          `json:"model"`
-	Messages       []message       `json:"messages"`
-	Temperature    float32         `json:"temperature"`
-	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Model          string           `json:"model"`
+	Messages       []requestMessage `json:"messages"`
+	Temperature    *float32         `json:"temperature,omitempty"`
+	Seed           *int             `json:"seed,omitempty"`
+	TopP           float32          `json:"top_p,omitempty"`
+	Stop           []string         `json:"stop,omitempty"`
+	ResponseFormat *responseFormat  `json:"response_format,omitempty"`
+	Tools          []requestTool    `json:"tools,omitempty"`
+	Logprobs       bool             `json:"logprobs,omitempty"`
+	TopLogprobs    int              `json:"top_logprobs,omitempty"`
+
+	// Reasoning models take max_completion_tokens and reject max_tokens,
+	// which OpenAI-compatible servers expect.
+	MaxTokens           int    `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int    `json:"max_completion_tokens,omitempty"`
+	ReasoningEffort     string `json:"reasoning_effort,omitempty"`
+}
+
+type requestTool struct {
+	Type     string             `json:"type"`
+	Function functionDefinition `json:"function"`
+}
+
+type functionDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// toolCall is a function call. Arguments is a JSON object encoded as a string.
+type toolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function functionCall `json:"function"`
+}
+
+type functionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 type message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+}
+
+// requestMessage is a message sent to the API. Content is a string, or a list
+// of content parts when images are attached, and null for an assistant
+// message that only calls tools.
+type requestMessage struct {
+	Role       string     `json:"role"`
+	Content    any        `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+// newRequestMessage converts a zyn.Message, sending attached images as
+// image_url content parts after the text.
+func newRequestMessage(msg zyn.Message) requestMessage {
+	if len(msg.ToolCalls) > 0 || msg.ToolCallID != "" {
+		converted := requestMessage{Role: msg.Role, ToolCallID: msg.ToolCallID}
+		if msg.Content != "" || msg.ToolCallID != "" {
+			converted.Content = msg.Content
+		}
+		for _, call := range msg.ToolCalls {
+			converted.ToolCalls = append(converted.ToolCalls, toolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: functionCall{Name: call.Name, Arguments: string(call.Arguments)},
+			})
+		}
+		return converted
+	}
+	if len(msg.Images) == 0 {
+		return requestMessage{Role: msg.Role, Content: msg.Content}
+	}
+
+	parts := make([]contentPart, 0, len(msg.Images)+1)
+	parts = append(parts, contentPart{Type: "text", Text: msg.Content})
+	for _, image := range msg.Images {
+		parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: image.DataURL()}})
+	}
+	return requestMessage{Role: msg.Role, Content: parts}
+}
+
+// newToolCalls converts the API's function calls. Arguments that are not
+// valid JSON are passed on as a JSON string.
+func newToolCalls(calls []toolCall) []zyn.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	converted := make([]zyn.ToolCall, len(calls))
+	for i, call := range calls {
+		arguments := json.RawMessage(call.Function.Arguments)
+		if !json.Valid(arguments) {
+			arguments, _ = json.Marshal(call.Function.Arguments) //nolint:errcheck // strings always marshal
+		}
+		converted[i] = zyn.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments}
+	}
+	return converted
 }
 
 type chatCompletionResponse struct {
-	ID      string   `json:"id"`
-	Object  string   `json:"object"`
-	Created int64    `json:"created"`
-	Model   string   `json:"model"`
-	Choices []choice `json:"choices"`
-	Usage   usage    `json:"usage"`
+	ID                string   `json:"id"`
+	Object            string   `json:"object"`
+	Created           int64    `json:"created"`
+	Model             string   `json:"model"`
+	SystemFingerprint string   `json:"system_fingerprint"`
+	Choices           []choice `json:"choices"`
+	Usage             usage    `json:"usage"`
 }
 
 type choice struct {
-	Index        int     `json:"index"`
-	Message      message `json:"message"`
-	FinishReason string  `json:"finish_reason"`
+	Index        int             `json:"index"`
+	Message      message         `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+	Logprobs     *choiceLogprobs `json:"logprobs"`
+}
+
+// choiceLogprobs holds the log probabilities of a choice's content tokens,
+// returned when requested.
+type choiceLogprobs struct {
+	Content []tokenLogprob `json:"content"`
+}
+
+type tokenLogprob struct {
+	Token       string         `json:"token"`
+	Logprob     float64        `json:"logprob"`
+	TopLogprobs []tokenLogprob `json:"top_logprobs,omitempty"`
+}
+
+// newLogprobs converts a choice's token log probabilities, returning nil
+// when none were returned.
+func newLogprobs(logprobs *choiceLogprobs) []zyn.TokenLogprob {
+	if logprobs == nil || len(logprobs.Content) == 0 {
+		return nil
+	}
+	converted := make([]zyn.TokenLogprob, len(logprobs.Content))
+	for i, token := range logprobs.Content {
+		converted[i] = zyn.TokenLogprob{Token: token.Token, Logprob: token.Logprob}
+		if len(token.TopLogprobs) > 0 {
+			converted[i].TopLogprobs = newLogprobs(&choiceLogprobs{Content: token.TopLogprobs})
+		}
+	}
+	return converted
 }
 
 type usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                     `json:"prompt_tokens"`
+	CompletionTokens        int                     `json:"completion_tokens"`
+	TotalTokens             int                     `json:"total_tokens"`
+	PromptTokensDetails     promptTokensDetails     `json:"prompt_tokens_details"`
+	CompletionTokensDetails completionTokensDetails `json:"completion_tokens_details"`
+}
+
+type promptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+type completionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
 }
 
 type errorResponse struct {
@@ -3,12 +3,18 @@ package openai
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/zoobzio/capitan"
 	"github.com/zoobzio/zyn"
 )
 
@@ -33,8 +39,8 @@ func TestProviderCall(t *testing.T) {
 		if req.Model != "gpt-3.5-turbo" {
 			t.Errorf("Expected model gpt-3.5-turbo, got %s", req.Model)
 		}
-		if req.Temperature != 0.7 {
-			t.Errorf("Expected temperature 0.7, got %f", req.Temperature)
+		if req.Temperature == nil || *req.Temperature != 0.7 {
+			t.Errorf("Expected temperature 0.7, got %v", req.Temperature)
 		}
 		if len(req.Messages) != 1 || req.Messages[0].Content != "test prompt" {
 			t.Errorf("Unexpected prompt: %v", req.Messages)
@@ -57,9 +63,10 @@ func TestProviderCall(t *testing.T) {
 				},
 			},
 			Usage: usage{
-				PromptTokens:     10,
-				CompletionTokens: 5,
-				TotalTokens:      15,
+				PromptTokens:        10,
+				CompletionTokens:    5,
+				TotalTokens:         15,
+				PromptTokensDetails: promptTokensDetails{CachedTokens: 4},
 			},
 		}
 
@@ -84,6 +91,9 @@ func TestProviderCall(t *testing.T) {
 	if response.Content != "test response" {
 		t.Errorf("Expected 'test response', got '%s'", response.Content)
 	}
+	if response.Usage.CachedPrompt != 4 {
+		t.Errorf("Expected 4 cached prompt tokens, got %d", response.Usage.CachedPrompt)
+	}
 }
 
 func TestOpenAIIntegration(t *testing.T) {
@@ -191,3 +201,885 @@ func TestProviderName(t *testing.T) {
 		t.Errorf("Expected 'openai', got '%s'", name)
 	}
 }
+
+func TestProviderCapabilities(t *testing.T) {
+	if New(Config{APIKey: "test-key"}).Capabilities().Vision {
+		t.Error("Expected vision to be off by default")
+	}
+	if !New(Config{APIKey: "test-key", Model: "gpt-4o", Vision: true}).Capabilities().Vision {
+		t.Error("Expected vision when configured")
+	}
+	if model := New(Config{APIKey: "test-key", Model: "gpt-4o"}).Capabilities().Model; model != "gpt-4o" {
+		t.Errorf("Expected configured model, got %q", model)
+	}
+}
+
+func TestProviderCallWithImages(t *testing.T) {
+	var body struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{}"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", Model: "gpt-4o", BaseURL: server.URL, Vision: true})
+	_, err := provider.Call(context.Background(), []zyn.Message{
+		{Role: zyn.RoleAssistant, Content: "earlier"},
+		{Role: zyn.RoleUser, Content: "describe", Images: []zyn.ImageInput{
+			{URL: "https://example.com/a.png"},
+			{Data: []byte("hi"), MIME: "image/png"},
+		}},
+	}, 0.1)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if string(body.Messages[0].Content) != `"earlier"` {
+		t.Errorf("Expected plain string content without images, got %s", body.Messages[0].Content)
+	}
+
+	var parts []contentPart
+	if err := json.Unmarshal(body.Messages[1].Content, &parts); err != nil {
+		t.Fatalf("Expected content parts, got %s", body.Messages[1].Content)
+	}
+	if len(parts) != 3 || parts[0].Type != "text" || parts[0].Text != "describe" {
+		t.Fatalf("Unexpected content parts: %+v", parts)
+	}
+	if parts[1].Type != "image_url" || parts[1].ImageURL.URL != "https://example.com/a.png" {
+		t.Errorf("Unexpected URL image part: %+v", parts[1])
+	}
+	if parts[2].ImageURL == nil || parts[2].ImageURL.URL != "data:image/png;base64,aGk=" {
+		t.Errorf("Unexpected data image part: %+v", parts[2])
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	bodies := map[string]string{
+		"with error body": `{"error": {"message": "Too many requests", "type": "rate_limit_error"}}`,
+		"without body":    ``,
+	}
+
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			provider := New(Config{
+				APIKey:  "test-key",
+				BaseURL: server.URL,
+			})
+
+			_, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7)
+			if !errors.Is(err, zyn.ErrRateLimited) {
+				t.Errorf("Expected ErrRateLimited, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHookFieldsFromContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var requestID, tenant string
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallFailed, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		requestID, _ = zyn.RequestIDKey.From(e)
+		tenant, _ = capitan.NewStringKey("tenant").From(e)
+	})
+	defer listener.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	ctx := zyn.ContextWithMeta(zyn.ContextWithRequestID(context.Background(), "req-42"), "tenant", "acme")
+	if _, err := provider.Call(ctx, []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.7); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	wg.Wait()
+	if requestID != "req-42" || tenant != "acme" {
+		t.Errorf("Expected request ID and meta in hook, got %q and %q", requestID, tenant)
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	t.Run("default user agent", func(t *testing.T) {
+		provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != zyn.DefaultUserAgent() {
+			t.Errorf("Expected default User-Agent, got %q", got.Get("User-Agent"))
+		}
+	})
+
+	t.Run("custom headers", func(t *testing.T) {
+		provider := New(Config{
+			APIKey:  "test-key",
+			BaseURL: server.URL,
+			HTTPConfig: zyn.HTTPConfig{
+				UserAgent: "billing/2.3",
+				Headers:   map[string]string{"X-Team": "billing"},
+			},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("User-Agent") != "billing/2.3" || got.Get("X-Team") != "billing" {
+			t.Errorf("Expected custom headers, got %v", got)
+		}
+	})
+
+	t.Run("rejects credential override", func(t *testing.T) {
+		got = nil
+		provider := New(Config{
+			APIKey:     "test-key",
+			BaseURL:    server.URL,
+			HTTPConfig: zyn.HTTPConfig{Headers: map[string]string{"Authorization": "stolen"}},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err == nil || !strings.Contains(err.Error(), "credentials") {
+			t.Errorf("Expected credential header rejected, got %v", err)
+		}
+		if got != nil {
+			t.Error("Expected no request sent")
+		}
+	})
+}
+
+func TestProviderBaseURL(t *testing.T) {
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	for _, baseURL := range []string{server.URL + "/v1", server.URL + "/v1/", server.URL + "/v1//"} {
+		provider := New(Config{APIKey: "test-key", BaseURL: baseURL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call to %s failed: %v", baseURL, err)
+		}
+		if path != "/v1/chat/completions" || auth != "Bearer test-key" {
+			t.Errorf("BaseURL %s: unexpected request to %q with %q", baseURL, path, auth)
+		}
+	}
+
+	if provider := New(Config{APIKey: "test-key"}); provider.baseURL != "https://api.openai.com/v1" {
+		t.Errorf("Expected default base URL, got %q", provider.baseURL)
+	}
+}
+
+func TestProviderAPIKeyHeader(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer server.Close()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	t.Run("custom header", func(t *testing.T) {
+		provider := New(Config{APIKey: "test-key", APIKeyHeader: "api-key", BaseURL: server.URL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("Api-Key") != "test-key" || got.Get("Authorization") != "" {
+			t.Errorf("Expected key in api-key header only, got %v", got)
+		}
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		provider := New(Config{BaseURL: server.URL})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got.Get("Authorization") != "" {
+			t.Errorf("Expected no credentials, got %q", got.Get("Authorization"))
+		}
+	})
+
+	t.Run("rejects key header override", func(t *testing.T) {
+		provider := New(Config{
+			APIKey:       "test-key",
+			APIKeyHeader: "api-key",
+			BaseURL:      server.URL,
+			HTTPConfig:   zyn.HTTPConfig{Headers: map[string]string{"Api-Key": "stolen"}},
+		})
+		if _, err := provider.Call(context.Background(), messages, 0.5); err == nil || !strings.Contains(err.Error(), "credentials") {
+			t.Errorf("Expected key header rejected, got %v", err)
+		}
+	})
+}
+
+func TestProviderCallWithTools(t *testing.T) {
+	var body struct {
+		Tools []struct {
+			Type     string `json:"type"`
+			Function struct {
+				Name       string          `json:"name"`
+				Parameters json.RawMessage `json:"parameters"`
+			} `json:"function"`
+		} `json:"tools"`
+		Messages []map[string]any `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": null, "tool_calls": [
+			{"id": "call-2", "type": "function", "function": {"name": "lookup_order", "arguments": "{\"id\":\"B-2\"}"}}
+		]}, "finish_reason": "tool_calls"}], "usage": {"prompt_tokens": 20, "completion_tokens": 5, "total_tokens": 25}}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	tools := []zyn.Tool{{
+		Name:        "lookup_order",
+		Description: "Look up an order",
+		Parameters:  json.RawMessage(`{"type":"object"}`),
+	}}
+	messages := []zyn.Message{
+		{Role: zyn.RoleUser, Content: "Has A-1 shipped?"},
+		{Role: zyn.RoleAssistant, ToolCalls: []zyn.ToolCall{{ID: "call-1", Name: "lookup_order", Arguments: json.RawMessage(`{"id":"A-1"}`)}}},
+		{Role: zyn.RoleTool, ToolCallID: "call-1", Content: `{"status":"shipped"}`},
+		{Role: zyn.RoleUser, Content: "And B-2?"},
+	}
+
+	response, err := provider.CallWithTools(context.Background(), messages, 0.5, tools)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if len(body.Tools) != 1 || body.Tools[0].Type != "function" || body.Tools[0].Function.Name != "lookup_order" ||
+		string(body.Tools[0].Function.Parameters) != `{"type":"object"}` {
+		t.Errorf("Unexpected tools: %+v", body.Tools)
+	}
+	assistant, tool := body.Messages[1], body.Messages[2]
+	if assistant["content"] != nil || assistant["tool_calls"].([]any)[0].(map[string]any)["function"].(map[string]any)["arguments"] != `{"id":"A-1"}` {
+		t.Errorf("Unexpected assistant message: %v", assistant)
+	}
+	if tool["role"] != "tool" || tool["tool_call_id"] != "call-1" || tool["content"] != `{"status":"shipped"}` {
+		t.Errorf("Unexpected tool message: %v", tool)
+	}
+
+	if len(response.ToolCalls) != 1 || response.ToolCalls[0].ID != "call-2" || string(response.ToolCalls[0].Arguments) != `{"id":"B-2"}` {
+		t.Errorf("Unexpected tool calls: %+v", response.ToolCalls)
+	}
+	if response.Usage.Total != 25 {
+		t.Errorf("Unexpected usage: %+v", response.Usage)
+	}
+}
+
+func TestProviderCallOmitsTools(t *testing.T) {
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{}"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.5)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if _, ok := raw["tools"]; ok {
+		t.Error("Expected no tools in request")
+	}
+	if response.ToolCalls != nil {
+		t.Errorf("Expected no tool calls, got %+v", response.ToolCalls)
+	}
+}
+
+// tagCounts is an extraction response whose map cannot be expressed in strict mode.
+type tagCounts struct {
+	Counts map[string]int `json:"counts"`
+}
+
+func (tagCounts) Validate() error { return nil }
+
+func TestProviderStructuredOutputs(t *testing.T) {
+	var format responseFormat
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		format = *req.ResponseFormat
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"], \"counts\": {}}"}}]}`))
+	}))
+	defer server.Close()
+
+	binary := func(t *testing.T, config Config) {
+		t.Helper()
+		config.BaseURL = server.URL
+		synapse, _ := zyn.Binary("Has the order shipped?", New(config))
+		if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "order A-1"); err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+	}
+
+	t.Run("strict schema", func(t *testing.T) {
+		binary(t, Config{Model: "gpt-4o", UseStructuredOutputs: true})
+		if format.Type != "json_schema" || format.JSONSchema == nil {
+			t.Fatalf("Expected json_schema format, got %+v", format)
+		}
+		if format.JSONSchema.Name != "response" || !format.JSONSchema.Strict {
+			t.Errorf("Expected strict schema named response, got %+v", format.JSONSchema)
+		}
+
+		var schema zyn.JSONSchema
+		if err := json.Unmarshal(format.JSONSchema.Schema, &schema); err != nil {
+			t.Fatalf("Invalid schema: %v", err)
+		}
+		if !schema.DisallowAdditionalProps || len(schema.Required) != len(schema.Properties) || schema.Properties["decision"] == nil {
+			t.Errorf("Expected strict binary schema, got %s", format.JSONSchema.Schema)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		binary(t, Config{Model: "gpt-4o"})
+		if format.Type != "json_object" || format.JSONSchema != nil {
+			t.Errorf("Expected json_object format, got %+v", format)
+		}
+	})
+
+	t.Run("unsupported model", func(t *testing.T) {
+		binary(t, Config{Model: "gpt-3.5-turbo", UseStructuredOutputs: true})
+		if format.Type != "json_object" {
+			t.Errorf("Expected json_object format, got %+v", format)
+		}
+	})
+
+	t.Run("schema without strict form", func(t *testing.T) {
+		synapse, _ := zyn.Extract[tagCounts]("tag counts", New(Config{Model: "gpt-4o", BaseURL: server.URL, UseStructuredOutputs: true}))
+		if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "a b a"); err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if format.Type != "json_object" {
+			t.Errorf("Expected json_object format, got %+v", format)
+		}
+	})
+
+	t.Run("direct call", func(t *testing.T) {
+		provider := New(Config{Model: "gpt-4o", BaseURL: server.URL, UseStructuredOutputs: true})
+		if _, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if format.Type != "json_object" {
+			t.Errorf("Expected json_object format, got %+v", format)
+		}
+	})
+}
+
+func TestSupportsStructuredOutputs(t *testing.T) {
+	tests := map[string]bool{
+		"gpt-4o":                true,
+		"gpt-4o-mini":           true,
+		"gpt-4o-2024-08-06":     true,
+		"gpt-4o-2024-05-13":     false,
+		"gpt-4.1-nano":          true,
+		"gpt-5":                 true,
+		"o1":                    true,
+		"o1-mini":               false,
+		"o1-preview":            false,
+		"o3-mini":               true,
+		"o4-mini":               true,
+		"gpt-4-turbo":           false,
+		"gpt-3.5-turbo":         false,
+		"llama-3.1-8b-instruct": false,
+	}
+	for model, want := range tests {
+		if got := supportsStructuredOutputs(model); got != want {
+			t.Errorf("supportsStructuredOutputs(%q) = %t, want %t", model, got, want)
+		}
+	}
+}
+
+// structuredOutputsCase is a fixture question with the model's answers under
+// each response format.
+type structuredOutputsCase struct {
+	Input      string `json:"input"`
+	JSONObject string `json:"json_object"`
+	JSONSchema string `json:"json_schema"`
+}
+
+// TestStructuredOutputsParseFailures replays testdata/structured_outputs.json,
+// answers to one Binary synapse under each response format, and counts the
+// ResponseParseFailed events. The json_object answers include the model's
+// type and shape slips (5 of 10 fail); strict json_schema answers always
+// match the schema, leaving only range checks the schema cannot express
+// (1 of 10).
+func TestStructuredOutputsParseFailures(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/structured_outputs.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var cases []structuredOutputsCase
+	if err := json.Unmarshal(fixture, &cases); err != nil {
+		t.Fatalf("Invalid fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt, _ := req.Messages[len(req.Messages)-1].Content.(string)
+		for _, c := range cases {
+			if !strings.Contains(prompt, c.Input) {
+				continue
+			}
+			content := c.JSONObject
+			if req.ResponseFormat.Type == "json_schema" {
+				content = c.JSONSchema
+			}
+			json.NewEncoder(w).Encode(chatCompletionResponse{
+				Model:   req.Model,
+				Choices: []choice{{Message: message{Role: zyn.RoleAssistant, Content: content}, FinishReason: "stop"}},
+			})
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	failed := make(chan string, 2*len(cases))
+	listener := capitan.Hook(zyn.ResponseParseFailed, func(_ context.Context, e *capitan.Event) {
+		mode, _ := capitan.NewStringKey("mode").From(e)
+		failed <- mode
+	})
+	defer listener.Close()
+
+	countFailures := func(t *testing.T, mode string, structured bool) int {
+		t.Helper()
+		synapse, _ := zyn.Binary("Has the order shipped?", New(Config{Model: "gpt-4o", BaseURL: server.URL, UseStructuredOutputs: structured}))
+		ctx := zyn.ContextWithMeta(context.Background(), "mode", mode)
+
+		errs := 0
+		for _, c := range cases {
+			if _, err := synapse.Fire(ctx, zyn.NewSession(), c.Input); err != nil {
+				errs++
+			}
+		}
+
+		events := 0
+		timeout := time.After(time.Second)
+		for events < errs {
+			select {
+			case got := <-failed:
+				if got != mode {
+					t.Fatalf("Unexpected event from %q", got)
+				}
+				events++
+			case <-timeout:
+				t.Fatalf("Expected %d ResponseParseFailed events, got %d", errs, events)
+			}
+		}
+		return events
+	}
+
+	jsonObject := countFailures(t, "json_object", false)
+	jsonSchema := countFailures(t, "json_schema", true)
+	t.Logf("ResponseParseFailed: %d/%d with json_object, %d/%d with json_schema", jsonObject, len(cases), jsonSchema, len(cases))
+
+	if jsonObject != 5 || jsonSchema != 1 {
+		t.Errorf("Expected 5 failures with json_object and 1 with json_schema, got %d and %d", jsonObject, jsonSchema)
+	}
+}
+
+func TestProviderSeed(t *testing.T) {
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "gpt-4o", "system_fingerprint": "fp_44709d6fcb", "choices": [{"message": {"role": "assistant", "content": "{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var fingerprint string
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		fingerprint, _ = zyn.SystemFingerprintKey.From(e)
+	})
+	defer listener.Close()
+
+	synapse, _ := zyn.Binary("question", New(Config{APIKey: "test-key", BaseURL: server.URL}), zyn.WithSeed(42))
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("Fire failed: %v", err)
+	}
+
+	wg.Wait()
+	if string(raw["seed"]) != "42" {
+		t.Errorf("Expected seed 42 in request, got %s", raw["seed"])
+	}
+	if fingerprint != "fp_44709d6fcb" {
+		t.Errorf("Expected system fingerprint in hook, got %q", fingerprint)
+	}
+
+	listener.Close()
+	raw = nil
+	unseeded, _ := zyn.Binary("question", New(Config{APIKey: "test-key", BaseURL: server.URL}))
+	if _, err := unseeded.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("Fire failed: %v", err)
+	}
+	if _, ok := raw["seed"]; ok {
+		t.Errorf("Expected no seed in request, got %s", raw["seed"])
+	}
+}
+
+func TestProviderSamplingParams(t *testing.T) {
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = nil
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"output\": \"ok\", \"confidence\": 0.9, \"changes\": [\"none\"], \"reasoning\": [\"ok\"]}"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	fire := func(t *testing.T, model string, opts ...zyn.Option) {
+		t.Helper()
+		synapse, _ := zyn.Transform("summarize", New(Config{APIKey: "test-key", Model: model, BaseURL: server.URL}), opts...)
+		_, _ = synapse.Fire(context.Background(), zyn.NewSession(), "input")
+	}
+
+	t.Run("set", func(t *testing.T) {
+		fire(t, "gpt-4o", zyn.WithMaxTokens(256), zyn.WithTopP(0.5), zyn.WithStopSequences("END"))
+		if string(raw["max_tokens"]) != "256" || string(raw["top_p"]) != "0.5" || string(raw["stop"]) != `["END"]` {
+			t.Errorf("Unexpected sampling params: max_tokens=%s top_p=%s stop=%s", raw["max_tokens"], raw["top_p"], raw["stop"])
+		}
+		if _, ok := raw["max_completion_tokens"]; ok {
+			t.Error("Expected no max_completion_tokens")
+		}
+	})
+
+	t.Run("reasoning model", func(t *testing.T) {
+		fire(t, "o3-mini", zyn.WithMaxTokens(256))
+		if string(raw["max_completion_tokens"]) != "256" {
+			t.Errorf("Expected max_completion_tokens 256, got %s", raw["max_completion_tokens"])
+		}
+		if _, ok := raw["max_tokens"]; ok {
+			t.Error("Expected no max_tokens")
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		fire(t, "gpt-4o")
+		for _, field := range []string{"max_tokens", "max_completion_tokens", "top_p", "stop"} {
+			if _, ok := raw[field]; ok {
+				t.Errorf("Expected no %s, got %s", field, raw[field])
+			}
+		}
+	})
+}
+
+func TestProviderTruncatedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"decision\": true, \"confi"}, "finish_reason": "length"}], "usage": {"prompt_tokens": 90, "completion_tokens": 8, "total_tokens": 98}}`))
+	}))
+	defer server.Close()
+
+	synapse, _ := zyn.Binary("question", New(Config{APIKey: "test-key", BaseURL: server.URL}), zyn.WithMaxTokens(8))
+	_, err := synapse.Fire(context.Background(), zyn.NewSession(), "input")
+
+	var truncated *zyn.ResponseTruncatedError
+	if !errors.As(err, &truncated) {
+		t.Fatalf("Expected ResponseTruncatedError, got %v", err)
+	}
+	if truncated.Provider != "openai" || truncated.MaxTokens != 8 || truncated.CompletionTokens != 8 {
+		t.Errorf("Unexpected error: %+v", truncated)
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestProviderHTTPClient(t *testing.T) {
+	var seen *http.Request
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		seen = req
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`)),
+		}, nil
+	})}
+
+	provider := New(Config{APIKey: "test-key", HTTPConfig: zyn.HTTPConfig{HTTPClient: client}})
+	if provider.httpClient != client {
+		t.Error("Expected the configured client")
+	}
+
+	response, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}, 0.5)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if seen == nil || seen.URL.String() != "https://api.openai.com/v1/chat/completions" || seen.Header.Get("Authorization") != "Bearer test-key" {
+		t.Errorf("Expected the transport to see the request, got %+v", seen)
+	}
+	if response.Content != "ok" {
+		t.Errorf("Expected response through the transport, got %q", response.Content)
+	}
+
+	if New(Config{APIKey: "test-key", Timeout: time.Minute}).httpClient.Timeout != time.Minute {
+		t.Error("Expected default client with the configured timeout")
+	}
+}
+
+func TestProviderLogWriter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	var buf strings.Builder
+	for _, config := range []Config{
+		{APIKey: "sk-live-secret", BaseURL: server.URL},
+		{APIKey: "azure-live-secret", APIKeyHeader: "api-key", BaseURL: server.URL},
+	} {
+		config.HTTPConfig = zyn.HTTPConfig{LogWriter: &buf}
+		if _, err := New(config).Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "test prompt"}}, 0.5); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+	}
+
+	logged := buf.String()
+	if strings.Contains(logged, "sk-live-secret") || strings.Contains(logged, "azure-live-secret") {
+		t.Errorf("Expected API keys redacted, got %s", logged)
+	}
+	if strings.Count(logged, "\n") != 2 || !strings.Contains(logged, "test prompt") || !strings.Contains(logged, `"status_code":200`) {
+		t.Errorf("Expected two logged exchanges, got %s", logged)
+	}
+}
+
+func TestProviderPing(t *testing.T) {
+	var method, path, auth string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		w.WriteHeader(status)
+		if status == http.StatusUnauthorized {
+			w.Write([]byte(`{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	if err := provider.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if method != http.MethodGet || path != "/models" || auth != "Bearer test-key" {
+		t.Errorf("Unexpected ping request: %s %s (%q)", method, path, auth)
+	}
+
+	status = http.StatusUnauthorized
+	if err := provider.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "Incorrect API key") {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := provider.Ping(context.Background()); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+
+	results := zyn.CheckProviders(context.Background(), provider)
+	if err, ok := results["openai"]; !ok || err == nil {
+		t.Errorf("Expected openai failure from CheckProviders, got %v", results)
+	}
+}
+
+// TestProviderLogprobs replays testdata/logprobs_response.json, a recorded
+// response with log probabilities, and checks they reach FireResult.
+func TestProviderLogprobs(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/logprobs_response.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = nil
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", Model: "gpt-4o-mini", BaseURL: server.URL, Logprobs: true, TopLogprobs: 2})
+	synapse, _ := zyn.Binary("Has the order shipped?", provider)
+	result, err := synapse.FireResult(context.Background(), zyn.NewSession(), "input")
+	if err != nil {
+		t.Fatalf("FireResult failed: %v", err)
+	}
+
+	if string(raw["logprobs"]) != "true" || string(raw["top_logprobs"]) != "2" {
+		t.Errorf("Expected logprobs requested, got logprobs=%s top_logprobs=%s", raw["logprobs"], raw["top_logprobs"])
+	}
+	if len(result.Logprobs) != 15 {
+		t.Fatalf("Expected 15 token logprobs, got %d", len(result.Logprobs))
+	}
+
+	var content strings.Builder
+	for _, token := range result.Logprobs {
+		content.WriteString(token.Token)
+	}
+	if content.String() != `{"decision":true,"confidence":0.9,"reasoning":["The order shipped"]}` {
+		t.Errorf("Expected tokens to spell the response, got %s", content.String())
+	}
+
+	decision := result.Logprobs[3]
+	if decision.Token != "true" || decision.Logprob != -0.1053605 {
+		t.Errorf("Unexpected decision token: %+v", decision)
+	}
+	if p := decision.Probability(); p < 0.899 || p > 0.901 {
+		t.Errorf("Expected decision probability 0.9, got %f", p)
+	}
+	if len(decision.TopLogprobs) != 2 || decision.TopLogprobs[1].Token != "false" || decision.TopLogprobs[1].Logprob != -2.3025851 {
+		t.Errorf("Unexpected alternatives: %+v", decision.TopLogprobs)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		synapse, _ := zyn.Binary("Has the order shipped?", New(Config{APIKey: "test-key", BaseURL: server.URL}))
+		if _, err := synapse.FireResult(context.Background(), zyn.NewSession(), "input"); err != nil {
+			t.Fatalf("FireResult failed: %v", err)
+		}
+		if _, ok := raw["logprobs"]; ok {
+			t.Errorf("Expected no logprobs requested, got %s", raw["logprobs"])
+		}
+	})
+}
+
+func TestProviderReasoningModel(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/reasoning_response.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw = nil
+		json.NewDecoder(r.Body).Decode(&raw)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	var reasoning int
+	wg.Add(1)
+	listener := capitan.Hook(zyn.ProviderCallCompleted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		reasoning, _ = zyn.ReasoningTokensKey.From(e)
+	})
+	defer listener.Close()
+
+	provider := New(Config{APIKey: "test-key", Model: "o4-mini", BaseURL: server.URL, ReasoningEffort: "high"})
+	synapse, _ := zyn.Binary("Is this order eligible for a refund?", provider)
+	session := zyn.NewSession()
+	result, err := synapse.FireResult(context.Background(), session, "ordered 42 days ago")
+	if err != nil {
+		t.Fatalf("FireResult failed: %v", err)
+	}
+	wg.Wait()
+	listener.Close()
+
+	if string(raw["reasoning_effort"]) != `"high"` {
+		t.Errorf("Expected reasoning_effort high, got %s", raw["reasoning_effort"])
+	}
+	if _, ok := raw["temperature"]; ok {
+		t.Errorf("Expected no temperature for a reasoning model, got %s", raw["temperature"])
+	}
+
+	want := zyn.TokenUsage{Prompt: 214, Completion: 431, Total: 645, Reasoning: 384}
+	if result.Usage == nil || *result.Usage != want {
+		t.Errorf("Expected usage %+v, got %+v", want, result.Usage)
+	}
+	if usage := session.LastUsage(); usage == nil || usage.Reasoning != 384 {
+		t.Errorf("Expected session usage with reasoning tokens, got %+v", usage)
+	}
+	if reasoning != 384 {
+		t.Errorf("Expected 384 reasoning tokens in hook, got %d", reasoning)
+	}
+
+	t.Run("other models", func(t *testing.T) {
+		provider := New(Config{APIKey: "test-key", Model: "gpt-4o", BaseURL: server.URL, ReasoningEffort: "high"})
+		synapse, _ := zyn.Binary("Is this order eligible for a refund?", provider)
+		if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+			t.Fatalf("Fire failed: %v", err)
+		}
+		if _, ok := raw["reasoning_effort"]; ok {
+			t.Errorf("Expected no reasoning_effort, got %s", raw["reasoning_effort"])
+		}
+		if _, ok := raw["temperature"]; !ok {
+			t.Error("Expected a temperature")
+		}
+	})
+}
+
+func TestProviderClose(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}, "finish_reason": "stop"}]}`))
+	}))
+	defer server.Close()
+
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL})
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+	if _, err := provider.Call(context.Background(), messages, 0.5); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if err := provider.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := provider.Close(); err != nil {
+		t.Errorf("Expected closing twice to be safe, got %v", err)
+	}
+
+	_, err := provider.Call(context.Background(), messages, 0.5)
+	var closed *zyn.ProviderClosedError
+	if !errors.As(err, &closed) || closed.Provider != provider.Name() {
+		t.Errorf("Expected ProviderClosedError, got %v", err)
+	}
+	if err := provider.Ping(context.Background()); !errors.Is(err, zyn.ErrProviderClosed) {
+		t.Errorf("Expected ErrProviderClosed from Ping, got %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected no request after Close, got %d requests", requests.Load())
+	}
+}
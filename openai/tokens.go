@@ -0,0 +1,319 @@
+package openai
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/zoobzio/zyn"
+)
+
+// Encoding names, as used by tiktoken for its BPE files.
+const (
+	EncodingCL100K = "cl100k_base" // gpt-4, gpt-3.5-turbo
+	EncodingO200K  = "o200k_base"  // gpt-4o and later, o-series
+)
+
+// Chat format framing, per OpenAI's token counting guide.
+const (
+	tokensPerMessage = 3 // Start, role separator, and end markers of a message
+	tokensPerReply   = 3 // Tokens priming the assistant's reply
+)
+
+// o200kModels are the model families tokenized with o200k_base.
+var o200kModels = []string{"gpt-4o", "chatgpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4"}
+
+// EncodingForModel returns the name of the encoding that tokenizes model,
+// for picking the file to load with LoadEncoding.
+func EncodingForModel(model string) string {
+	for _, family := range o200kModels {
+		if strings.HasPrefix(model, family) {
+			return EncodingO200K
+		}
+	}
+	return EncodingCL100K
+}
+
+// Encoding is a byte pair encoding loaded from a tiktoken BPE file. It is
+// safe for concurrent use.
+type Encoding struct {
+	name  string
+	ranks map[string]int
+	split func(string) []string
+}
+
+// LoadEncoding reads a tiktoken BPE file, such as the cl100k_base.tiktoken
+// or o200k_base.tiktoken files tiktoken downloads, for the named encoding.
+// Each line holds a base64 token and its merge rank. The name selects the
+// encoding's pre-tokenization rules and must be EncodingCL100K or
+// EncodingO200K.
+func LoadEncoding(name string, r io.Reader) (*Encoding, error) {
+	encoding := &Encoding{name: name, ranks: make(map[string]int)}
+	switch name {
+	case EncodingCL100K:
+		encoding.split = splitCL100K
+	case EncodingO200K:
+		encoding.split = splitO200K
+	default:
+		return nil, fmt.Errorf("unknown encoding %q (expected %s or %s)", name, EncodingCL100K, EncodingO200K)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		token, rank, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("%s line %d: expected a token and a rank", name, line)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(token)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid token: %w", name, line, err)
+		}
+		value, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid rank: %w", name, line, err)
+		}
+		encoding.ranks[string(decoded)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(encoding.ranks) == 0 {
+		return nil, fmt.Errorf("%s: no tokens", name)
+	}
+	return encoding, nil
+}
+
+// Name returns the encoding's name.
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// Count returns the number of tokens text encodes to. Special tokens such as
+// <|endoftext|> are counted as ordinary text.
+func (e *Encoding) Count(text string) int {
+	total := 0
+	for _, piece := range e.split(text) {
+		total += e.countPiece([]byte(piece))
+	}
+	return total
+}
+
+// countPiece applies the encoding's merges to a pre-tokenized piece, merging
+// the adjacent pair with the lowest rank until none remain, and returns the
+// number of parts left.
+func (e *Encoding) countPiece(piece []byte) int {
+	if _, ok := e.ranks[string(piece)]; ok {
+		return 1
+	}
+
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+	for {
+		best, at := -1, -1
+		for i := 0; i+2 < len(bounds); i++ {
+			rank, ok := e.ranks[string(piece[bounds[i]:bounds[i+2]])]
+			if ok && (best < 0 || rank < best) {
+				best, at = rank, i
+			}
+		}
+		if at < 0 {
+			return len(bounds) - 1
+		}
+		bounds = append(bounds[:at+1], bounds[at+2:]...)
+	}
+}
+
+// EstimateMessages implements zyn.TokenEstimator. With Config.Encoding it
+// counts messages as the chat format frames them: each message's role and
+// content plus three framing tokens, and three tokens priming the reply.
+// Images are not counted. Without an encoding it falls back to
+// zyn.HeuristicEstimator.
+func (p *Provider) EstimateMessages(messages []zyn.Message) int {
+	if p.encoding == nil {
+		return zyn.HeuristicEstimator{}.EstimateMessages(messages)
+	}
+
+	total := tokensPerReply
+	for _, msg := range messages {
+		total += tokensPerMessage + p.encoding.Count(msg.Role) + p.encoding.Count(msg.Content)
+		for _, call := range msg.ToolCalls {
+			total += p.encoding.Count(call.Name) + p.encoding.Count(string(call.Arguments))
+		}
+	}
+	return total
+}
+
+// Pre-tokenization splits text into pieces that are encoded independently,
+// following the regular expressions tiktoken uses for each encoding.
+
+// splitCL100K splits text as cl100k_base does:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+func splitCL100K(text string) []string {
+	return splitWith([]rune(text), func(r []rune, i int) int {
+		if end := matchContraction(r, i); end > i {
+			return end
+		}
+		start := i
+		if isPrefix(r, i) && i+1 < len(r) && unicode.IsLetter(r[i+1]) {
+			start++
+		}
+		if unicode.IsLetter(r[start]) {
+			return scan(r, start, unicode.IsLetter)
+		}
+		return matchCommon(r, i, isNewline)
+	})
+}
+
+// splitO200K splits text as o200k_base does, which splits words at case
+// changes and keeps contractions with their word:
+//
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?|
+//	[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?|
+//	\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+
+func splitO200K(text string) []string {
+	return splitWith([]rune(text), func(r []rune, i int) int {
+		start := i
+		if isPrefix(r, i) && i+1 < len(r) && isWordRune(r[i+1]) {
+			start++
+		}
+		if isWordRune(r[start]) {
+			return matchContraction(r, matchCasedWord(r, start))
+		}
+		return matchCommon(r, i, func(c rune) bool { return isNewline(c) || c == '/' })
+	})
+}
+
+// splitWith splits r into pieces, each ending where match says the piece
+// starting at its index ends.
+func splitWith(r []rune, match func(r []rune, i int) int) []string {
+	var pieces []string
+	for i := 0; i < len(r); {
+		end := match(r, i)
+		if end <= i {
+			end = i + 1
+		}
+		pieces = append(pieces, string(r[i:end]))
+		i = end
+	}
+	return pieces
+}
+
+// matchCommon matches the alternatives both encodings share after their
+// word rules: numbers, punctuation runs followed by trailing runes, and
+// whitespace.
+func matchCommon(r []rune, i int, trailing func(rune) bool) int {
+	// \p{N}{1,3}
+	if unicode.IsNumber(r[i]) {
+		end := i
+		for end < len(r) && end < i+3 && unicode.IsNumber(r[end]) {
+			end++
+		}
+		return end
+	}
+
+	// ' ?[^\s\p{L}\p{N}]+' followed by trailing runes
+	start := i
+	if r[i] == ' ' && i+1 < len(r) && isSymbol(r[i+1]) {
+		start++
+	}
+	if isSymbol(r[start]) {
+		return scan(r, scan(r, start, isSymbol), trailing)
+	}
+
+	// \s*[\r\n]+ matches through the run's last newline
+	end := scan(r, i, unicode.IsSpace)
+	for last := end - 1; last >= i; last-- {
+		if isNewline(r[last]) {
+			return last + 1
+		}
+	}
+
+	// \s+(?!\S) leaves the run's last space for the word that follows
+	if end < len(r) && end-i > 1 {
+		return end - 1
+	}
+	return end
+}
+
+// matchCasedWord matches o200k_base's word rules from i, returning the end
+// of the word: uppercase runes followed by lowercase ones, or failing that
+// uppercase runes optionally followed by lowercase ones. Modifier letters,
+// other letters, and marks count as both.
+func matchCasedWord(r []rune, i int) int {
+	upper := scan(r, i, isUpperRune)
+	if lower := scan(r, upper, isLowerRune); lower > upper {
+		return lower
+	}
+	// Backtrack: the last rune of the uppercase run may be the lowercase one
+	if upper > i && isLowerRune(r[upper-1]) {
+		return upper
+	}
+	return scan(r, upper, isLowerRune)
+}
+
+// contractions are the English contraction suffixes split after an
+// apostrophe, matched case-insensitively.
+var contractions = []string{"s", "t", "re", "ve", "m", "ll", "d"}
+
+// matchContraction returns the end of a contraction starting at i, or i
+// when there is none.
+func matchContraction(r []rune, i int) int {
+	if i >= len(r) || r[i] != '\'' {
+		return i
+	}
+	for _, suffix := range contractions {
+		end := i + 1 + len(suffix)
+		if end <= len(r) && strings.EqualFold(string(r[i+1:end]), suffix) {
+			return end
+		}
+	}
+	return i
+}
+
+// scan returns the end of the run of runes from i matching class.
+func scan(r []rune, i int, class func(rune) bool) int {
+	for i < len(r) && class(r[i]) {
+		i++
+	}
+	return i
+}
+
+// isPrefix reports whether r[i] may lead a word: [^\r\n\p{L}\p{N}].
+func isPrefix(r []rune, i int) bool {
+	return !isNewline(r[i]) && !unicode.IsLetter(r[i]) && !unicode.IsNumber(r[i])
+}
+
+// isSymbol reports whether c is in [^\s\p{L}\p{N}].
+func isSymbol(c rune) bool {
+	return !unicode.IsSpace(c) && !unicode.IsLetter(c) && !unicode.IsNumber(c)
+}
+
+func isNewline(c rune) bool {
+	return c == '\r' || c == '\n'
+}
+
+// isWordRune reports whether c may start an o200k_base word.
+func isWordRune(c rune) bool {
+	return isUpperRune(c) || isLowerRune(c)
+}
+
+// isUpperRune reports whether c is in [\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}].
+func isUpperRune(c rune) bool {
+	return unicode.In(c, unicode.Lu, unicode.Lt, unicode.Lm, unicode.Lo, unicode.M)
+}
+
+// isLowerRune reports whether c is in [\p{Ll}\p{Lm}\p{Lo}\p{M}].
+func isLowerRune(c rune) bool {
+	return unicode.In(c, unicode.Ll, unicode.Lm, unicode.Lo, unicode.M)
+}
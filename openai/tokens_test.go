@@ -0,0 +1,200 @@
+package openai
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/zyn"
+)
+
+// testEncoding returns a tiktoken file holding every byte followed by merges
+// in rank order.
+func testEncoding(merges ...string) string {
+	var file strings.Builder
+	for b := 0; b < 256; b++ {
+		fmt.Fprintf(&file, "%s %d\n", base64.StdEncoding.EncodeToString([]byte{byte(b)}), b)
+	}
+	for i, merge := range merges {
+		fmt.Fprintf(&file, "%s %d\n", base64.StdEncoding.EncodeToString([]byte(merge)), 256+i)
+	}
+	return file.String()
+}
+
+func mustLoadEncoding(t *testing.T, name string, merges ...string) *Encoding {
+	t.Helper()
+	encoding, err := LoadEncoding(name, strings.NewReader(testEncoding(merges...)))
+	if err != nil {
+		t.Fatalf("LoadEncoding failed: %v", err)
+	}
+	return encoding
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o":            EncodingO200K,
+		"gpt-4o-mini":       EncodingO200K,
+		"gpt-4.1-nano":      EncodingO200K,
+		"o3-mini":           EncodingO200K,
+		"gpt-4":             EncodingCL100K,
+		"gpt-4-turbo":       EncodingCL100K,
+		"gpt-3.5-turbo":     EncodingCL100K,
+		"local-llama-model": EncodingCL100K,
+	}
+	for model, want := range tests {
+		if got := EncodingForModel(model); got != want {
+			t.Errorf("EncodingForModel(%q) = %s, want %s", model, got, want)
+		}
+	}
+}
+
+func TestLoadEncoding(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		encoding := mustLoadEncoding(t, EncodingCL100K, "he")
+		if encoding.Name() != EncodingCL100K || len(encoding.ranks) != 257 {
+			t.Errorf("Unexpected encoding %s with %d tokens", encoding.Name(), len(encoding.ranks))
+		}
+	})
+
+	tests := map[string]struct {
+		name string
+		file string
+	}{
+		"unknown encoding": {name: "p50k_base", file: testEncoding()},
+		"missing rank":     {name: EncodingCL100K, file: "aGU=\n"},
+		"invalid token":    {name: EncodingCL100K, file: "!!! 1\n"},
+		"invalid rank":     {name: EncodingCL100K, file: "aGU= one\n"},
+		"empty":            {name: EncodingCL100K, file: "\n"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadEncoding(tt.name, strings.NewReader(tt.file)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestSplitCL100K(t *testing.T) {
+	tests := map[string][]string{
+		"Hello world":    {"Hello", " world"},
+		"it's":           {"it", "'s"},
+		"1234567":        {"123", "456", "7"},
+		"  indented":     {" ", " indented"},
+		"end.\n\nNext":   {"end", ".\n\n", "Next"},
+		"a  \n b":        {"a", "  \n", " b"},
+		"x = 1":          {"x", " =", " ", "1"},
+		"hi  ":           {"hi", "  "},
+		"camelCase":      {"camelCase"},
+		"naïve café":     {"naïve", " café"},
+		"path/to/file\n": {"path", "/to", "/file", "\n"},
+	}
+	for text, want := range tests {
+		if got := splitCL100K(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitCL100K(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestSplitO200K(t *testing.T) {
+	tests := map[string][]string{
+		"Hello world":  {"Hello", " world"},
+		"camelCase":    {"camel", "Case"},
+		"HTTPServer":   {"HTTPServer"},
+		"ALLCAPS word": {"ALLCAPS", " word"},
+		"don't stop":   {"don't", " stop"},
+		"I'M HERE":     {"I'M", " HERE"},
+		"a/b/c\n":      {"a", "/b", "/c", "\n"},
+		"path//\n":     {"path", "//\n"},
+		"1234567":      {"123", "456", "7"},
+		"x = 1":        {"x", " =", " ", "1"},
+	}
+	for text, want := range tests {
+		if got := splitO200K(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitO200K(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestEncodingCount(t *testing.T) {
+	encoding := mustLoadEncoding(t, EncodingCL100K, "he", "ll", "hell", " world")
+
+	tests := map[string]int{
+		"":            0,
+		"hello":       2, // he+l+l+o -> he+ll+o -> hell+o
+		" world":      1, // whole piece is a token
+		"hello world": 3,
+		"xyz":         3, // no merges
+		"héllo":       5, // é is two bytes
+	}
+	for text, want := range tests {
+		if got := encoding.Count(text); got != want {
+			t.Errorf("Count(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestProviderEstimateMessages(t *testing.T) {
+	messages := []zyn.Message{
+		{Role: zyn.RoleSystem, Content: "hello"},
+		{Role: zyn.RoleUser, Content: "hello world"},
+	}
+
+	t.Run("with encoding", func(t *testing.T) {
+		encoding := mustLoadEncoding(t, EncodingCL100K, "he", "ll", "hell", " world", "system", "user")
+		provider := New(Config{Encoding: encoding})
+		// 3 reply + (3 + 1 + 2) + (3 + 1 + 3)
+		if got := provider.EstimateMessages(messages); got != 16 {
+			t.Errorf("Expected 16 tokens, got %d", got)
+		}
+	})
+
+	t.Run("tool calls", func(t *testing.T) {
+		encoding := mustLoadEncoding(t, EncodingCL100K, "assistant", "lookup", "{}")
+		provider := New(Config{Encoding: encoding})
+		calls := []zyn.Message{{Role: zyn.RoleAssistant, ToolCalls: []zyn.ToolCall{{Name: "lookup", Arguments: []byte("{}")}}}}
+		// 3 reply + 3 + 1 role + 1 name + 1 arguments
+		if got := provider.EstimateMessages(calls); got != 9 {
+			t.Errorf("Expected 9 tokens, got %d", got)
+		}
+	})
+
+	t.Run("without encoding", func(t *testing.T) {
+		provider := New(Config{})
+		if got, want := provider.EstimateMessages(messages), (zyn.HeuristicEstimator{}).EstimateMessages(messages); got != want {
+			t.Errorf("Expected heuristic estimate %d, got %d", want, got)
+		}
+	})
+}
+
+func TestProviderPromptTooLarge(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	encoding := mustLoadEncoding(t, EncodingO200K)
+	provider := New(Config{APIKey: "test-key", BaseURL: server.URL, Encoding: encoding})
+	synapse, _ := zyn.Binary("question", provider, zyn.WithMaxPromptTokens(100))
+
+	_, err := synapse.Fire(context.Background(), zyn.NewSession(), "input")
+	var tooLarge *zyn.PromptTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Expected PromptTooLargeError, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no API call, got %d", calls)
+	}
+	// Without merges every byte is a token, so the estimate exceeds the
+	// rendered prompt's length
+	if tooLarge.Estimated <= 100 || tooLarge.Limit != 100 {
+		t.Errorf("Unexpected estimate %+v", tooLarge)
+	}
+}
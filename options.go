@@ -1,6 +1,9 @@
 package zyn
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/zoobzio/pipz"
@@ -11,29 +14,93 @@ var (
 	retryID          = pipz.NewIdentity("zyn:retry", "Retries failed LLM calls")
 	backoffID        = pipz.NewIdentity("zyn:backoff", "Retries with exponential backoff")
 	timeoutID        = pipz.NewIdentity("zyn:timeout", "Enforces operation timeout")
+	minDeadlineID    = pipz.NewIdentity("zyn:min-remaining-deadline", "Skips calls the deadline leaves too little time for")
 	circuitBreakerID = pipz.NewIdentity("zyn:circuit-breaker", "Circuit breaker protection")
 	rateLimitID      = pipz.NewIdentity("zyn:rate-limit", "Rate limiting")
+	concurrencyID    = pipz.NewIdentity("zyn:concurrency-limit", "Limits concurrent requests")
 	errorHandlerID   = pipz.NewIdentity("zyn:error-handler", "Error handling")
 	fallbackID       = pipz.NewIdentity("zyn:fallback", "Fallback alternatives")
+	notRetryableID   = pipz.NewIdentity("zyn:not-retryable", "Stops retries on errors a retry cannot fix")
 )
 
 // Option modifies a pipeline for reliability features.
 type Option func(pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest]
 
 // WithRetry adds retry logic to the pipeline.
-// Failed requests are retried up to maxAttempts times.
+// Failed requests are retried up to maxAttempts times. An attempt failing
+// with ErrNotRetryable ends the retries and its error is returned. After an
+// attempt fails with a *ProviderUnavailableError carrying a RetryAfter hint,
+// the next attempt waits that long.
 func WithRetry(maxAttempts int) Option {
 	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
-		return pipz.NewRetry(retryID, pipeline, maxAttempts)
+		return stopOnNotRetryable(pipeline, maxAttempts, func(attempt pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+			return pipz.NewRetry(retryID, attempt, maxAttempts)
+		})
 	}
 }
 
 // WithBackoff adds retry logic with exponential backoff to the pipeline.
 // Failed requests are retried with increasing delays between attempts.
-// The delay starts at baseDelay and doubles after each failure.
+// The delay starts at baseDelay and doubles after each failure. An attempt
+// failing with ErrNotRetryable ends the retries and its error is returned.
+// A RetryAfter hint from a *ProviderUnavailableError, such as a model cold
+// start, is waited out before the backoff delay.
 func WithBackoff(maxAttempts int, baseDelay time.Duration) Option {
 	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
-		return pipz.NewBackoff(backoffID, pipeline, maxAttempts, baseDelay)
+		return stopOnNotRetryable(pipeline, maxAttempts, func(attempt pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+			return pipz.NewBackoff(backoffID, attempt, maxAttempts, baseDelay)
+		})
+	}
+}
+
+// stopOnNotRetryable runs pipeline through the retrying connector built by
+// retry, canceling the connector's context when an attempt fails with
+// ErrNotRetryable so no further attempt is made, and returns that error.
+// Failed attempts before the last of maxAttempts wait out any RetryAfter
+// hint before the connector retries.
+func stopOnNotRetryable(pipeline pipz.Chainable[*SynapseRequest], maxAttempts int,
+	retry func(pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+	retrying := retry(pipz.Apply(notRetryableID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+		var attempt int64
+		if attempts, ok := ctx.Value(retryAttemptsContextKey).(*atomic.Int64); ok {
+			attempt = attempts.Add(1)
+		}
+
+		processed, err := pipeline.Process(ctx, req)
+		if errors.Is(err, ErrNotRetryable) {
+			if stop, ok := ctx.Value(retryStopContextKey).(context.CancelCauseFunc); ok {
+				stop(err)
+			}
+		} else if err != nil && attempt < int64(maxAttempts) {
+			waitRetryAfter(ctx, err)
+		}
+		return processed, err
+	}))
+
+	return pipz.Apply(notRetryableID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+		retryCtx, stop := context.WithCancelCause(ctx)
+		defer stop(nil)
+		retryCtx = context.WithValue(retryCtx, retryAttemptsContextKey, new(atomic.Int64))
+		processed, err := retrying.Process(context.WithValue(retryCtx, retryStopContextKey, stop), req)
+		if cause := context.Cause(retryCtx); ctx.Err() == nil && errors.Is(cause, ErrNotRetryable) {
+			return req, cause
+		}
+		return processed, err
+	})
+}
+
+// waitRetryAfter waits for the RetryAfter hint of a *ProviderUnavailableError,
+// or until ctx is done. Other errors return at once.
+func waitRetryAfter(ctx context.Context, err error) {
+	var unavailable *ProviderUnavailableError
+	if !errors.As(err, &unavailable) || unavailable.RetryAfter <= 0 {
+		return
+	}
+	timer := time.NewTimer(unavailable.RetryAfter)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
 	}
 }
 
@@ -45,6 +112,37 @@ func WithTimeout(duration time.Duration) Option {
 	}
 }
 
+// WithMinRemainingDeadline skips provider calls when the context's deadline
+// leaves less than d, the latency a call is expected to need. The request
+// fails with an *InsufficientDeadlineError (matching ErrInsufficientDeadline)
+// instead of paying for an answer that would arrive too late. The check runs
+// before every provider call, so a retry or fallback that the remaining time,
+// less any backoff already waited, cannot fit is skipped too. The error
+// matches ErrNotRetryable, so WithRetry and WithBackoff stop at once rather
+// than waiting out their delays. Contexts without a deadline are not
+// affected.
+func WithMinRemainingDeadline(d time.Duration) Option {
+	return withRequest(minDeadlineID, func(req *SynapseRequest) {
+		req.MinRemainingDeadline = d
+	})
+}
+
+// checkDeadline returns an *InsufficientDeadlineError when ctx's deadline
+// leaves less time than the request's minimum.
+func checkDeadline(ctx context.Context, req *SynapseRequest) error {
+	if req.MinRemainingDeadline <= 0 {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+	if remaining := time.Until(deadline); remaining < req.MinRemainingDeadline {
+		return &InsufficientDeadlineError{Remaining: max(remaining, 0), Required: req.MinRemainingDeadline}
+	}
+	return nil
+}
+
 // WithCircuitBreaker adds circuit breaker protection to the pipeline.
 // After 'failures' consecutive failures, the circuit opens for 'recovery' duration.
 func WithCircuitBreaker(failures int, recovery time.Duration) Option {
@@ -61,6 +159,27 @@ func WithRateLimit(rps float64, burst int) Option {
 	}
 }
 
+// WithConcurrencyLimit limits the requests in flight through the pipeline.
+// Requests beyond the limit wait for a slot or for their context to end.
+// The limit is shared by every call made with the synapse, including batches.
+func WithConcurrencyLimit(limit int) Option {
+	if limit < 1 {
+		limit = 1
+	}
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		slots := make(chan struct{}, limit)
+		return pipz.Apply(concurrencyID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return req, ctx.Err()
+			}
+			defer func() { <-slots }()
+			return pipeline.Process(ctx, req)
+		})
+	}
+}
+
 // WithErrorHandler adds error handling to the pipeline.
 // The error handler receives error context and can process/log/alert as needed.
 func WithErrorHandler(handler pipz.Chainable[*pipz.Error[*SynapseRequest]]) Option {
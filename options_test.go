@@ -3,6 +3,9 @@ package zyn
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -61,6 +64,39 @@ func TestWithRetry(t *testing.T) {
 		}
 	})
 
+	t.Run("stops on not retryable", func(t *testing.T) {
+		attempts := 0
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			attempts++
+			return req, fmt.Errorf("%w: invalid schema", ErrNotRetryable)
+		})
+
+		wrapped := WithRetry(3)(pipeline)
+		_, err := wrapped.Process(context.Background(), &SynapseRequest{})
+		if !errors.Is(err, ErrNotRetryable) {
+			t.Errorf("Expected the not retryable error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("nested retries stop", func(t *testing.T) {
+		attempts := 0
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			attempts++
+			return req, fmt.Errorf("%w: invalid schema", ErrNotRetryable)
+		})
+
+		wrapped := WithRetry(3)(WithRetry(3)(pipeline))
+		if _, err := wrapped.Process(context.Background(), &SynapseRequest{}); !errors.Is(err, ErrNotRetryable) {
+			t.Errorf("Expected the not retryable error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
 	t.Run("chaining", func(t *testing.T) {
 		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
 			return req, nil
@@ -115,6 +151,62 @@ func TestWithBackoff(t *testing.T) {
 		}
 	})
 
+	t.Run("stops on not retryable", func(t *testing.T) {
+		attempts := 0
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			attempts++
+			return req, fmt.Errorf("%w: invalid schema", ErrNotRetryable)
+		})
+
+		wrapped := WithBackoff(3, time.Second)(pipeline)
+		start := time.Now()
+		if _, err := wrapped.Process(context.Background(), &SynapseRequest{}); !errors.Is(err, ErrNotRetryable) {
+			t.Errorf("Expected the not retryable error, got: %v", err)
+		}
+		if attempts != 1 || time.Since(start) > 500*time.Millisecond {
+			t.Errorf("Expected 1 attempt without waiting, got %d in %v", attempts, time.Since(start))
+		}
+	})
+
+	t.Run("waits for retry after", func(t *testing.T) {
+		attempts := 0
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			attempts++
+			if attempts < 2 {
+				return req, &ProviderUnavailableError{Provider: "huggingface", RetryAfter: 50 * time.Millisecond, Err: errors.New("model loading")}
+			}
+			return req, nil
+		})
+
+		wrapped := WithBackoff(3, time.Millisecond)(pipeline)
+		start := time.Now()
+		if _, err := wrapped.Process(context.Background(), &SynapseRequest{}); err != nil {
+			t.Fatalf("Expected success after the model loaded, got: %v", err)
+		}
+		if elapsed := time.Since(start); attempts != 2 || elapsed < 50*time.Millisecond {
+			t.Errorf("Expected 2 attempts at least 50ms apart, got %d in %v", attempts, elapsed)
+		}
+	})
+
+	t.Run("retry after bounded by attempts and context", func(t *testing.T) {
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			return req, &ProviderUnavailableError{Provider: "huggingface", RetryAfter: time.Hour, Err: errors.New("model loading")}
+		})
+
+		start := time.Now()
+		if _, err := WithBackoff(1, time.Millisecond)(pipeline).Process(context.Background(), &SynapseRequest{}); !errors.Is(err, ErrProviderUnavailable) {
+			t.Errorf("Expected ErrProviderUnavailable, got: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := WithBackoff(3, time.Millisecond)(pipeline).Process(ctx, &SynapseRequest{}); err == nil {
+			t.Error("Expected an error")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected no wait after the last attempt or past the deadline, took %v", elapsed)
+		}
+	})
+
 	t.Run("chaining", func(t *testing.T) {
 		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
 			return req, nil
@@ -180,6 +272,94 @@ func TestWithTimeout(t *testing.T) {
 	})
 }
 
+func TestWithMinRemainingDeadline(t *testing.T) {
+	t.Run("skips call without enough time", func(t *testing.T) {
+		provider := &limitedProvider{}
+		synapse, _ := Binary("question", provider, WithMinRemainingDeadline(500*time.Millisecond), WithRetry(3))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		result, err := synapse.FireResult(ctx, NewSession(), "input")
+
+		var insufficient *InsufficientDeadlineError
+		if !errors.As(err, &insufficient) || !errors.Is(err, ErrInsufficientDeadline) {
+			t.Fatalf("expected insufficient deadline error, got %v", err)
+		}
+		if insufficient.Required != 500*time.Millisecond || insufficient.Remaining > 50*time.Millisecond {
+			t.Errorf("unexpected error details: %+v", insufficient)
+		}
+		if provider.calls.Load() != 0 || result.Attempts != 0 {
+			t.Errorf("expected no provider calls, got %d", provider.calls.Load())
+		}
+	})
+
+	t.Run("calls with enough time", func(t *testing.T) {
+		provider := &limitedProvider{}
+		synapse, _ := Binary("question", provider, WithMinRemainingDeadline(10*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if _, err := synapse.Fire(ctx, NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if provider.calls.Load() != 1 {
+			t.Errorf("expected one provider call, got %d", provider.calls.Load())
+		}
+	})
+
+	t.Run("ignores contexts without deadline", func(t *testing.T) {
+		provider := &limitedProvider{}
+		synapse, _ := Binary("question", provider, WithMinRemainingDeadline(time.Hour))
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("skips retry after backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls.Add(1)
+			return "", errors.New("unavailable")
+		})
+		synapse, _ := Binary("question", provider,
+			WithMinRemainingDeadline(900*time.Millisecond), WithBackoff(3, 200*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err := synapse.Fire(ctx, NewSession(), "input")
+		if calls.Load() != 1 {
+			t.Errorf("expected only the first attempt to reach the provider, got %d", calls.Load())
+		}
+		if !errors.Is(err, ErrInsufficientDeadline) {
+			t.Errorf("expected ErrInsufficientDeadline, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 600*time.Millisecond {
+			t.Errorf("expected the retries to stop after one backoff, took %v", elapsed)
+		}
+	})
+
+	t.Run("fails at once under backoff", func(t *testing.T) {
+		provider := &limitedProvider{}
+		synapse, _ := Binary("question", provider,
+			WithBackoff(3, 100*time.Millisecond), WithMinRemainingDeadline(time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := synapse.Fire(ctx, NewSession(), "input")
+		if !errors.Is(err, ErrInsufficientDeadline) || !errors.Is(err, ErrNotRetryable) {
+			t.Errorf("expected a not retryable ErrInsufficientDeadline, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("expected an immediate failure, took %v", elapsed)
+		}
+		if provider.calls.Load() != 0 {
+			t.Errorf("expected no provider call, got %d", provider.calls.Load())
+		}
+	})
+}
+
 func TestWithCircuitBreaker(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
@@ -288,6 +468,65 @@ func TestWithRateLimit(t *testing.T) {
 	})
 }
 
+func TestWithConcurrencyLimit(t *testing.T) {
+	t.Run("limits in-flight requests", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				seen := peak.Load()
+				if current <= seen || peak.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return req, nil
+		})
+		wrapped := WithConcurrencyLimit(2)(pipeline)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := wrapped.Process(context.Background(), &SynapseRequest{}); err != nil {
+					t.Errorf("request failed: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if peak.Load() != 2 {
+			t.Errorf("expected at most 2 requests in flight, got %d", peak.Load())
+		}
+	})
+
+	t.Run("waiting honors context", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{})
+		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			close(started)
+			<-release
+			return req, nil
+		})
+		wrapped := WithConcurrencyLimit(0)(pipeline)
+
+		go func() {
+			_, _ = wrapped.Process(context.Background(), &SynapseRequest{})
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := wrapped.Process(ctx, &SynapseRequest{})
+		close(release)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline while waiting for a slot, got %v", err)
+		}
+	})
+}
+
 func TestWithErrorHandler(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		pipeline := pipz.Apply(testID, func(_ context.Context, req *SynapseRequest) (*SynapseRequest, error) {
@@ -0,0 +1,123 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/pipz"
+)
+
+// progressID identifies the progress reporting stage.
+var progressID = pipz.NewIdentity("zyn:progress", "Reports progress of long-running operations")
+
+// Progress reports how far a long-running operation has got.
+type Progress struct {
+	Completed int           // Steps finished so far, increasing by one per report
+	Total     int           // Steps in the operation; 0 when unknown
+	Phase     string        // Step just finished, e.g. "item 412/10000" or "round 2/3, group 3/4"
+	Elapsed   time.Duration // Time since the operation started
+	Usage     TokenUsage    // Usage summed across the finished steps
+}
+
+// WithProgress reports the progress of long-running operations to fn at step
+// boundaries: after each item of FireSlice, FireMany and FireBatch, after
+// each chunk of a chunked extraction, and after each group call of a
+// tournament ranking. Single calls are not reported.
+//
+// fn is called synchronously, one report at a time and in order, outside the
+// operation's locks. Reports are never dropped, so a slow fn slows the
+// operation reporting to it; hand reports off to a goroutine if they need
+// heavy work.
+//
+// Example:
+//
+//	converter, _ := zyn.Convert[Row, Record]("normalize", provider,
+//	    zyn.WithProgress(func(p zyn.Progress) {
+//	        log.Printf("%s (%d/%d) after %v", p.Phase, p.Completed, p.Total, p.Elapsed)
+//	    }))
+func WithProgress(fn func(Progress)) Option {
+	subscriber := &progressSubscriber{fn: fn}
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		return pipz.Apply(progressID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			if scope, ok := ctx.Value(progressContextKey).(*progressScope); ok {
+				scope.subscribe(subscriber)
+			} else {
+				// Operations started further down the pipeline report here
+				ctx = context.WithValue(ctx, progressContextKey, &progressScope{subscribers: []*progressSubscriber{subscriber}})
+			}
+			return pipeline.Process(ctx, req)
+		})
+	}
+}
+
+// progressSubscriber is a callback registered by one WithProgress option.
+type progressSubscriber struct {
+	fn func(Progress)
+}
+
+// progressScope tracks the progress of one operation. WithProgress stages
+// reached by the operation's calls subscribe to it, so every report after a
+// synapse's first call reaches its callback.
+type progressScope struct {
+	notify sync.Mutex // Serializes reports so they arrive in order
+	mu     sync.Mutex // Guards the fields below
+
+	subscribers []*progressSubscriber
+	total       int
+	unit        string
+	completed   int
+	start       time.Time
+	usage       TokenUsage
+}
+
+// startProgress starts tracking an operation of total steps, described as
+// "<unit> i/total" unless advance is given a phase. The operation reports to
+// the callbacks already subscribed in ctx and to any its own calls subscribe.
+func startProgress(ctx context.Context, total int, unit string) (context.Context, *progressScope) {
+	scope := &progressScope{total: total, unit: unit, start: time.Now()}
+	if parent, ok := ctx.Value(progressContextKey).(*progressScope); ok {
+		parent.mu.Lock()
+		scope.subscribers = slices.Clone(parent.subscribers)
+		parent.mu.Unlock()
+	}
+	return context.WithValue(ctx, progressContextKey, scope), scope
+}
+
+// subscribe adds subscriber to the scope once.
+func (s *progressScope) subscribe(subscriber *progressSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !slices.Contains(s.subscribers, subscriber) {
+		s.subscribers = append(s.subscribers, subscriber)
+	}
+}
+
+// advance records a finished step with its usage and reports it to the
+// subscribers. An empty phase is described by the scope's unit.
+func (s *progressScope) advance(phase string, usage *TokenUsage) {
+	s.notify.Lock()
+	defer s.notify.Unlock()
+
+	s.mu.Lock()
+	s.completed++
+	addUsage(&s.usage, usage)
+	if phase == "" {
+		phase = fmt.Sprintf("%s %d/%d", s.unit, s.completed, s.total)
+	}
+	progress := Progress{
+		Completed: s.completed,
+		Total:     s.total,
+		Phase:     phase,
+		Elapsed:   time.Since(s.start),
+		Usage:     s.usage,
+	}
+	subscribers := slices.Clone(s.subscribers)
+	s.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.fn(progress)
+	}
+}
@@ -0,0 +1,131 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// progressLog records the progress reports it receives.
+type progressLog struct {
+	mu      sync.Mutex
+	reports []Progress
+}
+
+func (l *progressLog) record(p Progress) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reports = append(l.reports, p)
+}
+
+func (l *progressLog) all() []Progress {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Progress(nil), l.reports...)
+}
+
+// assertMonotonic checks that reports count up from 1 to total in order.
+func assertMonotonic(t *testing.T, reports []Progress, total int) {
+	t.Helper()
+	if len(reports) != total {
+		t.Fatalf("expected %d reports, got %d: %+v", total, len(reports), reports)
+	}
+	for i, report := range reports {
+		if report.Completed != i+1 || report.Total != total {
+			t.Errorf("report %d: expected %d/%d, got %d/%d", i, i+1, total, report.Completed, report.Total)
+		}
+		if i > 0 && report.Elapsed < reports[i-1].Elapsed {
+			t.Errorf("report %d: elapsed went backwards", i)
+		}
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		if strings.Contains(prompt, `"broken"`) {
+			return "not json", nil
+		}
+		return `{"count": 1, "label": "ok", "active": true}`, nil
+	})
+	items := []SimpleInput{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+
+	t.Run("reports each batch item", func(t *testing.T) {
+		log := &progressLog{}
+		synapse, _ := Convert[SimpleInput, SimpleOutput]("convert records", provider, WithProgress(log.record))
+
+		_, _, err := synapse.FireSlice(context.Background(), NewSession(), items, SliceOptions{Concurrency: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reports := log.all()
+		assertMonotonic(t, reports, 5)
+		for i, report := range reports {
+			if report.Phase != fmt.Sprintf("item %d/5", i+1) {
+				t.Errorf("report %d: unexpected phase %q", i, report.Phase)
+			}
+			if report.Usage.Total != (i+1)*150 {
+				t.Errorf("report %d: expected usage of %d items, got %+v", i, i+1, report.Usage)
+			}
+		}
+	})
+
+	t.Run("reports failed items", func(t *testing.T) {
+		log := &progressLog{}
+		synapse, _ := Convert[SimpleInput, SimpleOutput]("convert records", provider, WithProgress(log.record))
+
+		failing := []SimpleInput{{Name: "a"}, {Name: "broken"}, {Name: "c"}, {Name: "broken"}, {Name: "e"}}
+		_, itemErrs, err := synapse.FireSlice(context.Background(), NewSession(), failing, SliceOptions{ContinueOnError: true})
+		if err != nil || len(itemErrs) != 2 {
+			t.Fatalf("expected two item errors, got %v %v", err, itemErrs)
+		}
+		assertMonotonic(t, log.all(), 5)
+	})
+
+	t.Run("outer option sees the batch", func(t *testing.T) {
+		inner, outer := &progressLog{}, &progressLog{}
+		synapse, _ := Sentiment("customer feedback", NewMockProvider(),
+			WithProgress(inner.record), WithRetry(2), WithProgress(outer.record))
+
+		_, err := synapse.FireBatch(context.Background(), []string{"a", "b", "c", "d", "e"}, BatchOptions{Concurrency: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertMonotonic(t, inner.all(), 5)
+		assertMonotonic(t, outer.all(), 5)
+	})
+
+	t.Run("single calls are not reported", func(t *testing.T) {
+		log := &progressLog{}
+		synapse, _ := Binary("question", NewMockProvider(), WithProgress(log.record))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reports := log.all(); len(reports) != 0 {
+			t.Errorf("expected no reports, got %+v", reports)
+		}
+	})
+
+	t.Run("reports tournament groups", func(t *testing.T) {
+		log := &progressLog{}
+		script := &scriptedRanker{}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithProgress(log.record), WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 3}))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), shuffledItems(20)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reports := log.all()
+		assertMonotonic(t, reports, 13)
+		if reports[0].Phase != "round 1/3, group 1/4" || reports[12].Phase != "round 3/3, group 5/5" {
+			t.Errorf("unexpected phases %q and %q", reports[0].Phase, reports[12].Phase)
+		}
+		if reports[12].Usage.Total != 13*150 {
+			t.Errorf("expected usage summed over 13 calls, got %+v", reports[12].Usage)
+		}
+	})
+}
@@ -12,11 +12,20 @@ type Prompt struct {
 	Input       string              // Required: the main content to process
 	Context     string              // Optional: additional context
 	Categories  []string            // For classification synapses
+	Taxonomy    []TaxonomyBranch    // For taxonomy synapses, subcategories indented under their category
 	Items       []string            // For ranking synapses
+	Documents   []Document          // For answer synapses, rendered with their IDs
+	Left        []string            // For match synapses, rendered as L1, L2, ...
+	Right       []string            // For match synapses, rendered as R1, R2, ...
 	Aspects     []string            // For sentiment analysis
+	Mappings    []FieldMapping      // For convert synapses, declared with zyn struct tags
+	Pairs       []ExamplePair       // For convert synapses, worked examples rendered input then output
 	Examples    map[string][]string // Category->examples for classification
 	Schema      string              // Required: JSON schema for response
 	Constraints []string            // Required: rules and constraints
+	Format      OutputFormat        // Response format (JSON by default)
+	Strict      bool                // Reject responses with fields outside the schema
+	Images      []ImageInput        // Images attached to the user message, not rendered
 }
 
 // Render converts the structured prompt to a string for the LLM.
@@ -48,6 +57,18 @@ func (p *Prompt) Render() string {
 		sections = append(sections, strings.TrimSpace(cat))
 	}
 
+	// Taxonomy (for two-level classification), indented by level
+	if len(p.Taxonomy) > 0 {
+		tree := "Taxonomy:\n"
+		for _, branch := range p.Taxonomy {
+			tree += "  " + branch.Name + "\n"
+			for _, child := range branch.Children {
+				tree += "    - " + child + "\n"
+			}
+		}
+		sections = append(sections, strings.TrimSpace(tree))
+	}
+
 	// Items (for ranking)
 	if len(p.Items) > 0 {
 		items := "Items:\n"
@@ -57,6 +78,34 @@ func (p *Prompt) Render() string {
 		sections = append(sections, strings.TrimSpace(items))
 	}
 
+	// Documents (for answers), indented under their IDs
+	if len(p.Documents) > 0 {
+		docs := "Documents:\n"
+		for _, doc := range p.Documents {
+			docs += fmt.Sprintf("  [%s]\n", doc.ID)
+			for _, line := range strings.Split(strings.TrimSpace(doc.Text), "\n") {
+				docs += "    " + line + "\n"
+			}
+		}
+		sections = append(sections, strings.TrimSpace(docs))
+	}
+
+	// Left and right lists (for matching), indexed by side
+	if len(p.Left) > 0 {
+		left := "Left:\n"
+		for i, item := range p.Left {
+			left += fmt.Sprintf("  L%d. %s\n", i+1, item)
+		}
+		sections = append(sections, strings.TrimSpace(left))
+	}
+	if len(p.Right) > 0 {
+		right := "Right:\n"
+		for i, item := range p.Right {
+			right += fmt.Sprintf("  R%d. %s\n", i+1, item)
+		}
+		sections = append(sections, strings.TrimSpace(right))
+	}
+
 	// Aspects (for sentiment)
 	if len(p.Aspects) > 0 {
 		aspects := "Aspects:\n"
@@ -66,6 +115,31 @@ func (p *Prompt) Render() string {
 		sections = append(sections, strings.TrimSpace(aspects))
 	}
 
+	// Field mappings (for conversion), one target field per line
+	if len(p.Mappings) > 0 {
+		mappings := "Field mappings:\n"
+		for _, mapping := range p.Mappings {
+			line := "  " + mapping.Target
+			if mapping.From != "" {
+				line += " <- " + mapping.From
+			}
+			if mapping.Desc != "" {
+				line += ": " + mapping.Desc
+			}
+			mappings += line + "\n"
+		}
+		sections = append(sections, strings.TrimSpace(mappings))
+	}
+
+	// Worked examples (for conversion), each input followed by its output
+	if len(p.Pairs) > 0 {
+		pairs := make([]string, len(p.Pairs))
+		for i, pair := range p.Pairs {
+			pairs[i] = fmt.Sprintf("Example %d input:\n%s\nExample %d output:\n%s", i+1, pair.Input, i+1, pair.Output)
+		}
+		sections = append(sections, "Examples:\n"+strings.Join(pairs, "\n\n"))
+	}
+
 	// Examples (if provided)
 	if len(p.Examples) > 0 {
 		examples := "Examples:\n"
@@ -85,6 +159,11 @@ func (p *Prompt) Render() string {
 		sections = append(sections, "Response JSON Schema:\n"+p.Schema)
 	}
 
+	// Format instructions for non-JSON output formats
+	if instructions := p.Format.instructions(); instructions != "" {
+		sections = append(sections, "Response Format:\n"+instructions)
+	}
+
 	// Constraints - always last
 	if len(p.Constraints) > 0 {
 		con := "Constraints:\n"
@@ -102,11 +181,16 @@ func (p *Prompt) Validate() error {
 	if p.Task == "" {
 		return fmt.Errorf("prompt missing required Task field")
 	}
-	if p.Input == "" && len(p.Items) == 0 {
+	if p.Input == "" && len(p.Items) == 0 && len(p.Left) == 0 && len(p.Images) == 0 {
 		return fmt.Errorf("prompt missing required Input or Items field")
 	}
 	if p.Schema == "" {
 		return fmt.Errorf("prompt missing required Schema field")
 	}
+	for i, image := range p.Images {
+		if err := image.Validate(); err != nil {
+			return fmt.Errorf("image %d: %w", i, err)
+		}
+	}
 	return nil
 }
@@ -55,6 +55,84 @@ func TestPrompt_Render(t *testing.T) {
 			t.Error("Rendered prompt missing items")
 		}
 	})
+
+	t.Run("documents", func(t *testing.T) {
+		prompt := &Prompt{
+			Task:      "test task",
+			Input:     "test question",
+			Documents: []Document{{ID: "faq", Text: "line one\nline two"}, {ID: "doc-2", Text: "other"}},
+			Schema:    `{"field": "value"}`,
+		}
+
+		rendered := prompt.Render()
+		if !strings.Contains(rendered, "Documents:\n  [faq]\n    line one\n    line two\n  [doc-2]\n    other") {
+			t.Errorf("Rendered prompt should list documents under their IDs, got %s", rendered)
+		}
+	})
+
+	t.Run("left and right", func(t *testing.T) {
+		prompt := &Prompt{
+			Task:   "test task",
+			Left:   []string{"a", "b"},
+			Right:  []string{"c"},
+			Schema: `{"field": "value"}`,
+		}
+		if err := prompt.Validate(); err != nil {
+			t.Errorf("Prompt with lists failed validation: %v", err)
+		}
+
+		rendered := prompt.Render()
+		if !strings.Contains(rendered, "Left:\n  L1. a\n  L2. b\n\nRight:\n  R1. c") {
+			t.Errorf("Rendered prompt should index both lists by side, got %s", rendered)
+		}
+	})
+
+	t.Run("taxonomy", func(t *testing.T) {
+		prompt := &Prompt{
+			Task:     "test task",
+			Input:    "test input",
+			Taxonomy: []TaxonomyBranch{{Name: "billing", Children: []string{"refunds", "invoices"}}, {Name: "other"}},
+			Schema:   `{"field": "value"}`,
+		}
+
+		rendered := prompt.Render()
+		if !strings.Contains(rendered, "Taxonomy:\n  billing\n    - refunds\n    - invoices\n  other\n\n") {
+			t.Errorf("Rendered prompt should indent subcategories under their category, got %s", rendered)
+		}
+	})
+
+	t.Run("mappings", func(t *testing.T) {
+		prompt := &Prompt{
+			Task:  "test task",
+			Input: "test input",
+			Mappings: []FieldMapping{
+				{Target: "active", From: "is_active", Desc: "1 means true"},
+				{Target: "user_id", From: "id"},
+				{Target: "tier", Desc: "gold, silver, or bronze"},
+			},
+			Schema: `{"field": "value"}`,
+		}
+
+		rendered := prompt.Render()
+		want := "Field mappings:\n  active <- is_active: 1 means true\n  user_id <- id\n  tier: gold, silver, or bronze\n\n"
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Rendered prompt should list one mapping per line, got %s", rendered)
+		}
+	})
+	t.Run("pairs", func(t *testing.T) {
+		prompt := &Prompt{
+			Task:   "test task",
+			Input:  "test input",
+			Pairs:  []ExamplePair{{Input: `{"a": 1}`, Output: `{"b": 1}`}, {Input: `{"a": 2}`, Output: `{"b": 2}`}},
+			Schema: `{"field": "value"}`,
+		}
+
+		rendered := prompt.Render()
+		want := "Examples:\nExample 1 input:\n{\"a\": 1}\nExample 1 output:\n{\"b\": 1}\n\nExample 2 input:\n{\"a\": 2}\nExample 2 output:\n{\"b\": 2}\n\nResponse JSON Schema:"
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Rendered prompt should pair each input with its output, got %s", rendered)
+		}
+	})
 }
 
 func TestPrompt_Validate(t *testing.T) {
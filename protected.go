@@ -0,0 +1,77 @@
+package zyn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// protectedDelimiter separates the opening and closing delimiters of a
+// delimited protected pattern, as in "{{...}}".
+const protectedDelimiter = "..."
+
+// compileProtected compiles a protected pattern. A pattern containing "..."
+// matches text between the delimiters on either side of it, within one line,
+// so "{{...}}" matches "{{first_name}}". Any other pattern is a regular
+// expression.
+func compileProtected(pattern string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("protected pattern required but empty")
+	}
+	if open, closing, ok := strings.Cut(pattern, protectedDelimiter); ok {
+		if open == "" || closing == "" {
+			return nil, fmt.Errorf("protected pattern %q needs delimiters on both sides of %q", pattern, protectedDelimiter)
+		}
+		return regexp.Compile(regexp.QuoteMeta(open) + `[^\n]*?` + regexp.QuoteMeta(closing))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("protected pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// protectedTokens returns the distinct tokens of text matched by patterns, in
+// the order found, with the number of times each appears. A token matched by
+// several patterns is counted once per appearance.
+func protectedTokens(text string, patterns []string) ([]string, map[string]int, error) {
+	var tokens []string
+	counts := make(map[string]int)
+	for _, pattern := range patterns {
+		re, err := compileProtected(pattern)
+		if err != nil {
+			return nil, nil, err
+		}
+		found := make(map[string]int)
+		for _, token := range re.FindAllString(text, -1) {
+			if token != "" {
+				found[token]++
+			}
+		}
+		for _, token := range re.FindAllString(text, -1) {
+			if token == "" || found[token] <= counts[token] {
+				continue
+			}
+			if counts[token] == 0 {
+				tokens = append(tokens, token)
+			}
+			counts[token] = found[token]
+		}
+	}
+	return tokens, counts, nil
+}
+
+// checkProtected returns a ProtectedTokensError listing the tokens that
+// output repeats fewer times than counts records for the input.
+func checkProtected(tokens []string, counts map[string]int, output string) error {
+	var missing []string
+	for _, token := range tokens {
+		if strings.Count(output, token) < counts[token] {
+			missing = append(missing, token)
+		}
+	}
+	if len(missing) > 0 {
+		return &ProtectedTokensError{Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package zyn
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCompileProtected(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		text    string
+		want    []string
+	}{
+		{"{{...}}", "Hello {{first_name}}, order {{order_id}}", []string{"{{first_name}}", "{{order_id}}"}},
+		{"${...}", "Run ${HOME}/bin as ${USER}", []string{"${HOME}", "${USER}"}},
+		{"%[sd]", "%s has %d items", []string{"%s", "%d"}},
+		{"{{...}}", "{{open\nclose}}", nil},
+	} {
+		re, err := compileProtected(tt.pattern)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.pattern, err)
+		}
+		if got := re.FindAllString(tt.text, -1); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.pattern, tt.want, got)
+		}
+	}
+
+	for _, pattern := range []string{"", " ", "...}}", "{{...", "(unclosed"} {
+		if _, err := compileProtected(pattern); err == nil {
+			t.Errorf("expected %q rejected", pattern)
+		}
+	}
+}
+
+func TestProtectedTokens(t *testing.T) {
+	tokens, counts, err := protectedTokens("%s and %s, not {{name}}", []string{"%s", `\{\{\w+\}\}`, "{{...}}"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(tokens, []string{"%s", "{{name}}"}) {
+		t.Errorf("unexpected tokens: %q", tokens)
+	}
+	if counts["%s"] != 2 || counts["{{name}}"] != 1 {
+		t.Errorf("expected each appearance counted once, got %v", counts)
+	}
+
+	tokens, _, err = protectedTokens("plain text", []string{"{{...}}"})
+	if err != nil || tokens != nil {
+		t.Errorf("expected no tokens, got %q, %v", tokens, err)
+	}
+}
+
+func TestCheckProtected(t *testing.T) {
+	tokens, counts, _ := protectedTokens("%s sent %s to {{name}}", []string{"%s", "{{...}}"})
+
+	if err := checkProtected(tokens, counts, "{{name}} recibió %s de %s"); err != nil {
+		t.Errorf("expected reordered tokens accepted, got %v", err)
+	}
+
+	err := checkProtected(tokens, counts, "%s envió a {{nombre}}")
+	var changed *ProtectedTokensError
+	if !errors.As(err, &changed) || !slices.Equal(changed.Missing, []string{"%s", "{{name}}"}) {
+		t.Errorf("expected dropped and translated tokens reported, got %v", err)
+	}
+}
@@ -1,8 +1,12 @@
 package zyn
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 
 	"github.com/zoobzio/pipz"
 )
@@ -13,17 +17,45 @@ type RankingInput struct {
 	Context     string   // Additional context for ranking
 	Examples    []string // Example rankings to guide
 	TopN        int      // If set, only return top N items
+	ExplainEach bool     // Request a short justification per ranked item
 	Temperature float32  // LLM temperature setting
 }
 
+// scoreTolerance is how much a lower-ranked item's score may exceed the score
+// of the item ranked above it, absorbing rounding in provider output.
+const scoreTolerance = 0.01
+
+// justificationsProperty is the schema property holding per-item
+// justifications, left out of the schema unless ExplainEach is set.
+const justificationsProperty = "justifications"
+
+// justificationsConstraint requests per-item justifications.
+const justificationsConstraint = "justifications: one short sentence per ranked item explaining its position, keyed by exact item text"
+
+// scoreOrderNote is added to the reasoning of a response re-sorted by score.
+const scoreOrderNote = "ranked: re-sorted by score, as the returned order contradicted the scores"
+
+// WeightedCriterion is one criterion of a weighted ranking.
+type WeightedCriterion struct {
+	Name   string  // Criterion the items are scored on, e.g. "performance"
+	Weight float64 // Relative importance; must be positive
+}
+
 // RankingResponse contains the response from a ranking synapse.
 type RankingResponse struct {
-	Ranked     []string `json:"ranked"`     // Items in ranked order
-	Confidence float64  `json:"confidence"` // Overall confidence
-	Reasoning  []string `json:"reasoning"`  // Explanation of ranking
+	Ranked         []string                      `json:"ranked"`                   // Items in ranked order
+	Scores         map[string]float64            `json:"scores,omitempty"`         // Score per ranked item, when returned
+	PerCriterion   map[string]map[string]float64 `json:"per_criterion,omitempty"`  // Score per criterion and item, for weighted rankings
+	Justifications map[string]string             `json:"justifications,omitempty"` // Why each item is ranked where it is, when requested with ExplainEach
+	Confidence     float64                       `json:"confidence"`               // Overall confidence
+	Reasoning      []string                      `json:"reasoning"`                // Explanation of ranking
 }
 
 // Validate checks if the response is valid.
+// Scores are optional; when present they must cover exactly the ranked items
+// and lie in [0,1]. An order that contradicts them is re-sorted by the
+// synapse rather than rejected. Justifications may only be keyed by ranked
+// items.
 func (r RankingResponse) Validate() error {
 	if len(r.Ranked) == 0 {
 		return fmt.Errorf("ranked list required but empty")
@@ -34,22 +66,87 @@ func (r RankingResponse) Validate() error {
 	if len(r.Reasoning) == 0 {
 		return fmt.Errorf("reasoning required but empty")
 	}
+	if len(r.Scores) > 0 {
+		if err := r.validateScores(); err != nil {
+			return fmt.Errorf("invalid scores: %w", err)
+		}
+	}
+	for item := range r.Justifications {
+		if !slices.Contains(r.Ranked, item) {
+			return fmt.Errorf("justification for unranked item %q", item)
+		}
+	}
+	return nil
+}
+
+// validateScores checks the scores against the ranked items.
+func (r RankingResponse) validateScores() error {
+	ranked := make(map[string]bool, len(r.Ranked))
+	for _, item := range r.Ranked {
+		score, ok := r.Scores[item]
+		if !ok {
+			return fmt.Errorf("missing score for %q", item)
+		}
+		if score < 0 || score > 1 {
+			return fmt.Errorf("score for %q must be 0-1, got %f", item, score)
+		}
+		ranked[item] = true
+	}
+	for item := range r.Scores {
+		if !ranked[item] {
+			return fmt.Errorf("score for unranked item %q", item)
+		}
+	}
 	return nil
 }
 
+// sortedByScore returns the response with its items ordered by score,
+// highest first, if any item scores more than scoreTolerance above the item
+// ranked before it, noting the change in the reasoning. Items with equal
+// scores keep their order. Responses without scores are returned as is.
+func (r RankingResponse) sortedByScore() RankingResponse {
+	if len(r.Scores) == 0 {
+		return r
+	}
+	consistent := true
+	for i := 1; i < len(r.Ranked); i++ {
+		if r.Scores[r.Ranked[i]] > r.Scores[r.Ranked[i-1]]+scoreTolerance {
+			consistent = false
+			break
+		}
+	}
+	if consistent {
+		return r
+	}
+
+	r.Ranked = slices.Clone(r.Ranked)
+	slices.SortStableFunc(r.Ranked, func(a, b string) int {
+		return cmp.Compare(r.Scores[b], r.Scores[a])
+	})
+	r.Reasoning = append(slices.Clone(r.Reasoning), scoreOrderNote)
+	return r
+}
+
 // RankingSynapse represents a ranking/sorting synapse.
 type RankingSynapse struct {
-	criteria string
-	schema   string // Pre-computed JSON schema
-	defaults RankingInput
-	service  *Service[RankingResponse]
+	criteria      string
+	schema        string // Pre-computed JSON schema, without justifications
+	explainSchema string // Pre-computed JSON schema with justifications, for ExplainEach
+	defaults      RankingInput
+	requireScores bool                // Reject responses without scores
+	weights       []WeightedCriterion // Normalized criteria of a weighted ranking
+	service       *Service[RankingResponse]
 }
 
 // NewRanking creates a new ranking synapse bound to a provider.
 // Returns an error if the JSON schema cannot be generated.
 func NewRanking(criteria string, provider Provider, opts ...Option) (*RankingSynapse, error) {
-	// Generate schema once at construction
-	schema, err := generateJSONSchema[RankingResponse]()
+	// Generate schemas once at construction
+	explainSchema, err := generateJSONSchema[RankingResponse]()
+	var schema string
+	if err == nil {
+		schema, err = omitProperty(explainSchema, justificationsProperty)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("ranking synapse: %w", err)
 	}
@@ -63,11 +160,83 @@ func NewRanking(criteria string, provider Provider, opts ...Option) (*RankingSyn
 	// Create service with final pipeline and default temperature
 	svc := NewService[RankingResponse](pipeline, "ranking", provider, DefaultTemperatureAnalytical)
 
-	return &RankingSynapse{
-		criteria: criteria,
-		schema:   schema,
-		service:  svc,
-	}, nil
+	synapse := &RankingSynapse{
+		criteria:      criteria,
+		schema:        schema,
+		explainSchema: explainSchema,
+		service:       svc,
+	}
+	svc.validate = synapse.validateResponse
+	return synapse, nil
+}
+
+// NewWeightedRanking creates a ranking synapse that scores every item on each
+// criterion and orders them by the weighted sum of those scores. Weights must
+// be positive and are normalized to sum to 1.
+//
+// Responses must include PerCriterion scores from 0.0 to 1.0 for every
+// criterion and ranked item. The weighted totals are recomputed from them, and
+// a response whose order contradicts its totals by more than a rounding
+// tolerance is rejected as invalid. Fire returns the order; FireWithDetails
+// also returns the per-criterion scores. Weighted rankings are always made in
+// one call, so WithTournamentRanking has no effect on them.
+//
+// Returns an error if a criterion is unnamed, repeated, or not positively
+// weighted, or if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	synapse, err := NewWeightedRanking([]WeightedCriterion{
+//	    {Name: "performance", Weight: 3},
+//	    {Name: "cost", Weight: 2},
+//	    {Name: "maturity", Weight: 1},
+//	}, provider)
+//	response, err := synapse.FireWithDetails(ctx, session, candidates)
+//	// response.PerCriterion["cost"]["candidate"]
+func NewWeightedRanking(criteria []WeightedCriterion, provider Provider, opts ...Option) (*RankingSynapse, error) {
+	weights, err := normalizeWeights(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("ranking synapse: %w", err)
+	}
+
+	names := make([]string, len(weights))
+	for i, criterion := range weights {
+		names[i] = fmt.Sprintf("%s (weight %.2f)", criterion.Name, criterion.Weight)
+	}
+	synapse, err := NewRanking("weighted criteria: "+strings.Join(names, ", "), provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	synapse.weights = weights
+	return synapse, nil
+}
+
+// normalizeWeights checks the criteria and scales their weights to sum to 1.
+func normalizeWeights(criteria []WeightedCriterion) ([]WeightedCriterion, error) {
+	if len(criteria) == 0 {
+		return nil, fmt.Errorf("at least one criterion required")
+	}
+	seen := make(map[string]bool, len(criteria))
+	var sum float64
+	for _, criterion := range criteria {
+		if strings.TrimSpace(criterion.Name) == "" {
+			return nil, fmt.Errorf("criterion name required but empty")
+		}
+		if seen[criterion.Name] {
+			return nil, fmt.Errorf("duplicate criterion %q", criterion.Name)
+		}
+		if !(criterion.Weight > 0) || math.IsInf(criterion.Weight, 0) {
+			return nil, fmt.Errorf("weight for %q must be positive, got %v", criterion.Name, criterion.Weight)
+		}
+		seen[criterion.Name] = true
+		sum += criterion.Weight
+	}
+
+	normalized := make([]WeightedCriterion, len(criteria))
+	for i, criterion := range criteria {
+		normalized[i] = WeightedCriterion{Name: criterion.Name, Weight: criterion.Weight / sum}
+	}
+	return normalized, nil
 }
 
 // GetPipeline returns the internal pipeline for composition.
@@ -81,6 +250,77 @@ func (r *RankingSynapse) WithDefaults(defaults RankingInput) *RankingSynapse {
 	return r
 }
 
+// RequireScores controls whether responses must include per-item scores.
+// By default scores are optional, so providers that ignore the field still
+// work; when required, a response without scores is rejected as invalid.
+func (r *RankingSynapse) RequireScores(required bool) *RankingSynapse {
+	r.requireScores = required
+	return r
+}
+
+// validateResponse rejects responses without scores when they are required,
+// and weighted rankings whose order contradicts their criterion scores.
+func (r *RankingSynapse) validateResponse(_ *Prompt, response RankingResponse) error {
+	if r.requireScores && len(response.Scores) == 0 {
+		return fmt.Errorf("scores required but empty")
+	}
+	if len(r.weights) > 0 {
+		if err := r.validateWeighted(response); err != nil {
+			return fmt.Errorf("invalid per-criterion scores: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateWeighted checks that every criterion scores every ranked item and
+// that the weighted totals do not increase down the ranking.
+func (r *RankingSynapse) validateWeighted(response RankingResponse) error {
+	for name := range response.PerCriterion {
+		if !r.hasCriterion(name) {
+			return fmt.Errorf("unknown criterion %q", name)
+		}
+	}
+
+	totals := make([]float64, len(response.Ranked))
+	for _, criterion := range r.weights {
+		scores, ok := response.PerCriterion[criterion.Name]
+		if !ok {
+			return fmt.Errorf("missing scores for criterion %q", criterion.Name)
+		}
+		if len(scores) != len(response.Ranked) {
+			return fmt.Errorf("criterion %q scores %d items, ranked %d", criterion.Name, len(scores), len(response.Ranked))
+		}
+		for i, item := range response.Ranked {
+			score, ok := scores[item]
+			if !ok {
+				return fmt.Errorf("criterion %q missing score for %q", criterion.Name, item)
+			}
+			if score < 0 || score > 1 {
+				return fmt.Errorf("criterion %q score for %q must be 0-1, got %f", criterion.Name, item, score)
+			}
+			totals[i] += criterion.Weight * score
+		}
+	}
+
+	for i := 1; i < len(totals); i++ {
+		if totals[i] > totals[i-1]+scoreTolerance {
+			return fmt.Errorf("weighted total for %q (%.3f) exceeds total of higher-ranked %q (%.3f)",
+				response.Ranked[i], totals[i], response.Ranked[i-1], totals[i-1])
+		}
+	}
+	return nil
+}
+
+// hasCriterion reports whether name is one of the synapse's criteria.
+func (r *RankingSynapse) hasCriterion(name string) bool {
+	for _, criterion := range r.weights {
+		if criterion.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Fire executes the ranking against a list of items.
 // Returns the items in ranked order.
 func (r *RankingSynapse) Fire(ctx context.Context, session *Session, items []string) ([]string, error) {
@@ -91,7 +331,18 @@ func (r *RankingSynapse) Fire(ctx context.Context, session *Session, items []str
 	return response.Ranked, nil
 }
 
-// FireWithDetails executes the ranking and returns the full response.
+// FireResult executes the ranking and returns the ranked items in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (r *RankingSynapse) FireResult(ctx context.Context, session *Session, items []string) (Result[[]string], error) {
+	result, err := r.execute(ctx, session, RankingInput{Items: items})
+	if err != nil {
+		return withValue[RankingResponse, []string](result, nil), err
+	}
+	return withValue(result, result.Value.Ranked), nil
+}
+
+// FireWithDetails executes the ranking and returns the full response,
+// including per-item scores when the provider returns them.
 func (r *RankingSynapse) FireWithDetails(ctx context.Context, session *Session, items []string) (RankingResponse, error) {
 	rankInput := RankingInput{Items: items}
 	return r.FireWithInput(ctx, session, rankInput)
@@ -99,14 +350,37 @@ func (r *RankingSynapse) FireWithDetails(ctx context.Context, session *Session,
 
 // FireWithInput executes the ranking with rich input structure.
 func (r *RankingSynapse) FireWithInput(ctx context.Context, session *Session, input RankingInput) (RankingResponse, error) {
-	// Merge defaults with user input
-	merged := r.mergeInputs(input)
+	result, err := r.execute(ctx, session, input)
+	return result.Value, err
+}
 
-	// Build prompt
-	prompt := r.buildPrompt(merged)
+// Invoke executes the synapse through the Synapse interface.
+// Items default to the non-empty lines of the input text.
+// The returned Validator is a RankingResponse.
+func (r *RankingSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := r.FireWithInput(ctx, session, RankingInput{
+		Items:       input.items(),
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
 
-	// Execute through service with session (service handles temperature fallback)
-	return r.service.Execute(ctx, session, prompt, merged.Temperature)
+// execute merges input with the defaults and ranks the items. A response
+// whose order contradicts its scores is re-sorted by score; weighted rankings
+// are ordered by their validated weighted totals instead.
+func (r *RankingSynapse) execute(ctx context.Context, session *Session, input RankingInput) (Result[RankingResponse], error) {
+	merged := r.mergeInputs(input)
+	settings := &rankingSettings{topN: merged.TopN, weighted: len(r.weights) > 0}
+	result, err := r.service.executeConfigured(ctx, session, r.buildPrompt(merged), settings, merged.Temperature, nil)
+	if err != nil || len(r.weights) > 0 {
+		return result, err
+	}
+	result.Value = result.Value.sortedByScore()
+	return result, nil
 }
 
 // mergeInputs combines defaults with user input.
@@ -125,6 +399,9 @@ func (r *RankingSynapse) mergeInputs(input RankingInput) RankingInput {
 	if input.TopN > 0 {
 		merged.TopN = input.TopN
 	}
+	if input.ExplainEach {
+		merged.ExplainEach = true
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -154,6 +431,7 @@ func (r *RankingSynapse) buildPrompt(input RankingInput) *Prompt {
 			fmt.Sprintf("ranked: select top %d items only", input.TopN),
 			"ranked: ordered highest to lowest",
 			"ranked: preserve exact item text",
+			"scores: 0.0 to 1.0 for each ranked item, keyed by exact item text, non-increasing in rank order",
 			"confidence: 0.0 to 1.0",
 		}
 	} else {
@@ -161,10 +439,27 @@ func (r *RankingSynapse) buildPrompt(input RankingInput) *Prompt {
 			"ranked: all items, ordered highest to lowest",
 			"ranked: include every item exactly once",
 			"ranked: preserve exact item text",
+			"scores: 0.0 to 1.0 for each ranked item, keyed by exact item text, non-increasing in rank order",
 			"confidence: 0.0 to 1.0",
 		}
 	}
 
+	if len(r.weights) > 0 {
+		names := make([]string, len(r.weights))
+		for i, criterion := range r.weights {
+			names[i] = criterion.Name
+		}
+		prompt.Constraints = append(prompt.Constraints,
+			fmt.Sprintf("per_criterion: for each criterion (%s), a 0.0 to 1.0 score for every ranked item, keyed by criterion name then exact item text", strings.Join(names, ", ")),
+			"ranked: ordered by the weighted sum of per_criterion scores, highest first",
+		)
+	}
+
+	if input.ExplainEach {
+		prompt.Schema = r.explainSchema
+		prompt.Constraints = append(prompt.Constraints, justificationsConstraint)
+	}
+
 	return prompt
 }
 
@@ -178,7 +473,7 @@ func (r *RankingSynapse) buildPrompt(input RankingInput) *Prompt {
 //	    provider,
 //	    WithTimeout(10*time.Second),
 //	)
-//	ordered, err := synapse.Fire(ctx, []string{"Fix typo", "Security patch", "Add feature"})
+//	ordered, err := synapse.Fire(ctx, session, []string{"Fix typo", "Security patch", "Add feature"})
 func Ranking(criteria string, provider Provider, opts ...Option) (*RankingSynapse, error) {
 	return NewRanking(criteria, provider, opts...)
 }
@@ -2,6 +2,9 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -617,3 +620,346 @@ func TestRankingResponse_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestRankingResponse_Scores(t *testing.T) {
+	base := func(scores map[string]float64) RankingResponse {
+		return RankingResponse{
+			Ranked:     []string{"a", "b", "c"},
+			Scores:     scores,
+			Confidence: 0.9,
+			Reasoning:  []string{"reason"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		response RankingResponse
+		wantErr  bool
+	}{
+		{"absent", base(nil), false},
+		{"decreasing", base(map[string]float64{"a": 0.9, "b": 0.88, "c": 0.2}), false},
+		{"ties", base(map[string]float64{"a": 0.5, "b": 0.5, "c": 0.5}), false},
+		{"within tolerance", base(map[string]float64{"a": 0.9, "b": 0.905, "c": 0.2}), false},
+		{"increasing", base(map[string]float64{"a": 0.5, "b": 0.8, "c": 0.2}), false},
+		{"missing item", base(map[string]float64{"a": 0.9, "b": 0.5}), true},
+		{"unranked item", base(map[string]float64{"a": 0.9, "b": 0.5, "c": 0.2, "d": 0.1}), true},
+		{"out of range", base(map[string]float64{"a": 1.5, "b": 0.5, "c": 0.2}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.response.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRankingResponse_SortedByScore(t *testing.T) {
+	response := RankingResponse{
+		Ranked:     []string{"a", "b", "c", "d"},
+		Scores:     map[string]float64{"a": 0.5, "b": 0.9, "c": 0.5, "d": 0.1},
+		Confidence: 0.9,
+		Reasoning:  []string{"reason"},
+	}
+	sorted := response.sortedByScore()
+	if strings.Join(sorted.Ranked, ",") != "b,a,c,d" {
+		t.Errorf("expected a stable sort by score, got %v", sorted.Ranked)
+	}
+	if len(sorted.Reasoning) != 2 || sorted.Reasoning[1] != scoreOrderNote {
+		t.Errorf("expected a reasoning note, got %v", sorted.Reasoning)
+	}
+	if strings.Join(response.Ranked, ",") != "a,b,c,d" || len(response.Reasoning) != 1 {
+		t.Errorf("expected the original response unchanged, got %+v", response)
+	}
+
+	within := RankingResponse{
+		Ranked:    []string{"a", "b"},
+		Scores:    map[string]float64{"a": 0.9, "b": 0.905},
+		Reasoning: []string{"reason"},
+	}
+	if kept := within.sortedByScore(); kept.Ranked[0] != "a" || len(kept.Reasoning) != 1 {
+		t.Errorf("expected an order within tolerance kept, got %+v", kept)
+	}
+	unscored := RankingResponse{Ranked: []string{"a", "b"}, Reasoning: []string{"reason"}}
+	if kept := unscored.sortedByScore(); kept.Ranked[0] != "a" || len(kept.Reasoning) != 1 {
+		t.Errorf("expected a response without scores kept, got %+v", kept)
+	}
+}
+
+func TestRankingSynapse_Scores(t *testing.T) {
+	items := []string{"security patch", "new feature", "typo"}
+	scored := `{"ranked": ["security patch", "new feature", "typo"], "scores": {"security patch": 0.95, "new feature": 0.93, "typo": 0.1}, "confidence": 0.9, "reasoning": ["impact"]}`
+	unscored := `{"ranked": ["security patch", "new feature", "typo"], "confidence": 0.9, "reasoning": ["impact"]}`
+
+	t.Run("surfaced in details", func(t *testing.T) {
+		synapse, err := Ranking("urgency", NewMockProviderWithResponse(scored))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.Scores["security patch"] != 0.95 || response.Scores["typo"] != 0.1 {
+			t.Errorf("unexpected scores: %v", response.Scores)
+		}
+
+		ranked, err := synapse.Fire(context.Background(), NewSession(), items)
+		if err != nil || len(ranked) != 3 || ranked[0] != "security patch" {
+			t.Errorf("expected Fire to return the ordered slice, got %v (%v)", ranked, err)
+		}
+	})
+
+	t.Run("missing scores lenient by default", func(t *testing.T) {
+		synapse, err := Ranking("urgency", NewMockProviderWithResponse(unscored))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if err != nil {
+			t.Fatalf("expected response without scores to be accepted, got %v", err)
+		}
+		if response.Scores != nil {
+			t.Errorf("expected no scores, got %v", response.Scores)
+		}
+	})
+
+	t.Run("missing scores rejected when required", func(t *testing.T) {
+		synapse, err := Ranking("urgency", NewMockProviderWithResponse(unscored))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse.RequireScores(true)
+
+		_, err = synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected invalid response, got %v", err)
+		}
+	})
+
+	t.Run("non-monotonic scores re-sorted", func(t *testing.T) {
+		body := `{"ranked": ["new feature", "security patch", "typo"], "scores": {"security patch": 0.95, "new feature": 0.5, "typo": 0.1}, "confidence": 0.9, "reasoning": ["impact"]}`
+		synapse, err := Ranking("urgency", NewMockProviderWithResponse(body))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(response.Ranked, ",") != "security patch,new feature,typo" {
+			t.Errorf("expected items re-sorted by score, got %v", response.Ranked)
+		}
+		if len(response.Reasoning) != 2 || response.Reasoning[1] != scoreOrderNote {
+			t.Errorf("expected a reasoning note on the re-sort, got %v", response.Reasoning)
+		}
+
+		ranked, err := synapse.Fire(context.Background(), NewSession(), items)
+		if err != nil || ranked[0] != "security patch" {
+			t.Errorf("expected Fire to return the re-sorted order, got %v (%v)", ranked, err)
+		}
+	})
+}
+
+func TestRankingSynapse_ExplainEach(t *testing.T) {
+	items := []string{"security patch", "new feature", "typo"}
+	explained := `{"ranked": ["security patch", "new feature", "typo"], "justifications": {"security patch": "exploitable today", "typo": "cosmetic"}, "confidence": 0.9, "reasoning": ["impact"]}`
+
+	t.Run("off by default", func(t *testing.T) {
+		var prompt string
+		synapse, _ := Ranking("urgency", NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"ranked": ["security patch", "new feature", "typo"], "confidence": 0.9, "reasoning": ["impact"]}`, nil
+		}))
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompt, justificationsProperty) {
+			t.Errorf("expected justifications left out of the prompt and schema, got %s", prompt)
+		}
+		if response.Justifications != nil {
+			t.Errorf("expected no justifications, got %v", response.Justifications)
+		}
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		var prompt string
+		synapse, _ := Ranking("urgency", NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return explained, nil
+		}))
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), RankingInput{Items: items, ExplainEach: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompt, justificationsConstraint) || !strings.Contains(prompt, `"justifications"`) {
+			t.Errorf("expected justifications in the constraints and schema, got %s", prompt)
+		}
+		if response.Justifications["security patch"] != "exploitable today" || len(response.Justifications) != 2 {
+			t.Errorf("unexpected justifications: %v", response.Justifications)
+		}
+	})
+
+	t.Run("from defaults", func(t *testing.T) {
+		var prompt string
+		synapse, _ := Ranking("urgency", NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return explained, nil
+		}))
+		synapse.WithDefaults(RankingInput{ExplainEach: true})
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), items); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompt, justificationsConstraint) {
+			t.Errorf("expected justifications requested by default, got %s", prompt)
+		}
+	})
+
+	t.Run("unranked item rejected", func(t *testing.T) {
+		body := `{"ranked": ["security patch"], "justifications": {"security patch": "exploitable today", "typo": "cosmetic"}, "confidence": 0.9, "reasoning": ["impact"]}`
+		synapse, _ := Ranking("urgency", NewMockProviderWithResponse(body))
+
+		_, err := synapse.FireWithInput(context.Background(), NewSession(), RankingInput{Items: items, TopN: 1, ExplainEach: true})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `justification for unranked item "typo"`) {
+			t.Errorf("expected a justification of an unranked item rejected, got %v", err)
+		}
+	})
+}
+
+func TestNewWeightedRanking(t *testing.T) {
+	t.Run("normalizes weights", func(t *testing.T) {
+		synapse, err := NewWeightedRanking([]WeightedCriterion{
+			{Name: "performance", Weight: 3},
+			{Name: "cost", Weight: 1},
+		}, NewMockProvider())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if synapse.weights[0].Weight != 0.75 || synapse.weights[1].Weight != 0.25 {
+			t.Errorf("expected normalized weights, got %+v", synapse.weights)
+		}
+		if synapse.criteria != "weighted criteria: performance (weight 0.75), cost (weight 0.25)" {
+			t.Errorf("unexpected criteria: %q", synapse.criteria)
+		}
+	})
+
+	invalid := map[string][]WeightedCriterion{
+		"empty":     nil,
+		"zero":      {{Name: "performance", Weight: 0}},
+		"negative":  {{Name: "performance", Weight: 1}, {Name: "cost", Weight: -1}},
+		"unnamed":   {{Name: " ", Weight: 1}},
+		"duplicate": {{Name: "cost", Weight: 1}, {Name: "cost", Weight: 2}},
+	}
+	for name, criteria := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewWeightedRanking(criteria, NewMockProvider()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRankingSynapse_Weighted(t *testing.T) {
+	criteria := []WeightedCriterion{{Name: "performance", Weight: 2}, {Name: "cost", Weight: 1}}
+	items := []string{"postgres", "sqlite", "mongo"}
+
+	// Weighted totals: postgres 0.8, sqlite 0.6, mongo 0.5
+	consistent := `{"ranked": ["postgres", "sqlite", "mongo"],
+		"per_criterion": {
+			"performance": {"postgres": 0.9, "sqlite": 0.5, "mongo": 0.6},
+			"cost": {"postgres": 0.6, "sqlite": 0.8, "mongo": 0.3}
+		},
+		"confidence": 0.8, "reasoning": ["weighted"]}`
+	// Same scores, but sqlite (0.6) is ranked below mongo (0.5)
+	inconsistent := `{"ranked": ["postgres", "mongo", "sqlite"],
+		"per_criterion": {
+			"performance": {"postgres": 0.9, "sqlite": 0.5, "mongo": 0.6},
+			"cost": {"postgres": 0.6, "sqlite": 0.8, "mongo": 0.3}
+		},
+		"confidence": 0.8, "reasoning": ["weighted"]}`
+
+	t.Run("consistent order", func(t *testing.T) {
+		synapse, _ := NewWeightedRanking(criteria, NewMockProviderWithResponse(consistent))
+
+		ranked, err := synapse.Fire(context.Background(), NewSession(), items)
+		if err != nil || !slices.Equal(ranked, items) {
+			t.Fatalf("expected the ranked order, got %v (%v)", ranked, err)
+		}
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response.PerCriterion["cost"]["sqlite"] != 0.8 || len(response.PerCriterion) != 2 {
+			t.Errorf("expected the per-criterion matrix, got %v", response.PerCriterion)
+		}
+	})
+
+	t.Run("inconsistent order rejected", func(t *testing.T) {
+		synapse, _ := NewWeightedRanking(criteria, NewMockProviderWithResponse(inconsistent))
+		session := NewSession()
+
+		_, err := synapse.Fire(context.Background(), session, items)
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `weighted total for "sqlite"`) {
+			t.Errorf("expected the contradicting order rejected, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("within tolerance", func(t *testing.T) {
+		// Totals 0.600 and 0.605 differ by less than the tolerance
+		response := RankingResponse{
+			Ranked: []string{"a", "b"},
+			PerCriterion: map[string]map[string]float64{
+				"performance": {"a": 0.6, "b": 0.6},
+				"cost":        {"a": 0.6, "b": 0.615},
+			},
+		}
+		synapse, _ := NewWeightedRanking(criteria, NewMockProvider())
+		if err := synapse.validateWeighted(response); err != nil {
+			t.Errorf("expected rounding tolerated, got %v", err)
+		}
+	})
+
+	incomplete := map[string]map[string]map[string]float64{
+		"missing criterion": {"performance": {"a": 0.9, "b": 0.1}},
+		"missing item":      {"performance": {"a": 0.9}, "cost": {"a": 0.5, "b": 0.5}},
+		"unknown criterion": {"performance": {"a": 0.9, "b": 0.1}, "cost": {"a": 0.5, "b": 0.5}, "vibes": {"a": 1, "b": 0}},
+		"out of range":      {"performance": {"a": 1.5, "b": 0.1}, "cost": {"a": 0.5, "b": 0.5}},
+	}
+	for name, perCriterion := range incomplete {
+		t.Run(name, func(t *testing.T) {
+			synapse, _ := NewWeightedRanking(criteria, NewMockProvider())
+			if err := synapse.validateWeighted(RankingResponse{Ranked: []string{"a", "b"}, PerCriterion: perCriterion}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	t.Run("prompt", func(t *testing.T) {
+		synapse, _ := NewWeightedRanking(criteria, NewMockProvider())
+		prompt := synapse.buildPrompt(RankingInput{Items: items})
+		if !slices.ContainsFunc(prompt.Constraints, func(c string) bool { return strings.HasPrefix(c, "per_criterion:") }) ||
+			!strings.Contains(prompt.Task, "performance (weight 0.67)") {
+			t.Errorf("expected weighted prompt, got %q %v", prompt.Task, prompt.Constraints)
+		}
+	})
+
+	t.Run("ranked in one call", func(t *testing.T) {
+		synapse, _ := NewWeightedRanking(criteria, NewMockProviderWithResponse(consistent),
+			WithTournamentRanking(TournamentConfig{GroupSize: 2}))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), items)
+		if err != nil || result.Attempts != 1 {
+			t.Errorf("expected a single call, got %d attempts (%v)", result.Attempts, err)
+		}
+	})
+}
@@ -0,0 +1,267 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultPlaceholderFormat is the placeholder redacted entities are replaced
+// with unless RedactInput.PlaceholderFormat says otherwise. {TYPE} is the
+// entity type in upper case and {N} numbers the distinct values of a type.
+const DefaultPlaceholderFormat = "[{TYPE}_{N}]"
+
+// RedactInput contains rich input structure for redaction.
+type RedactInput struct {
+	Text              string   // The text to redact
+	Types             []string // Entity types to redact, e.g. email, phone, name; empty redacts whatever the policy covers
+	PlaceholderFormat string   // Placeholder for redacted values; empty uses DefaultPlaceholderFormat
+	Context           string   // Optional context, such as who may see the redacted text
+	Temperature       float32  // LLM temperature setting for this specific request
+}
+
+// RedactedEntity is a value removed from the text.
+type RedactedEntity struct {
+	Type        string `json:"type"`        // The entity type, e.g. email
+	Original    string `json:"original"`    // The value as it appeared in the text
+	Placeholder string `json:"placeholder"` // What replaced it in the redacted text
+}
+
+// RedactResponse contains the response from a redact synapse.
+type RedactResponse struct {
+	Redacted   string           `json:"redacted"`   // The text with every entity replaced by its placeholder
+	Entities   []RedactedEntity `json:"entities"`   // The values that were replaced
+	Confidence float64          `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string         `json:"reasoning"`  // Explanation of what was redacted
+}
+
+// Validate checks if the response is valid. The redacted text is checked
+// against the entities by the synapse.
+func (r RedactResponse) Validate() error {
+	if strings.TrimSpace(r.Redacted) == "" {
+		return fmt.Errorf("redacted required but empty")
+	}
+	for i, entity := range r.Entities {
+		if strings.TrimSpace(entity.Type) == "" {
+			return fmt.Errorf("entity %d: type required but empty", i)
+		}
+		if strings.TrimSpace(entity.Original) == "" {
+			return fmt.Errorf("entity %d: original required but empty", i)
+		}
+		if strings.TrimSpace(entity.Placeholder) == "" {
+			return fmt.Errorf("entity %d: placeholder required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkRedaction rejects redacted text that still contains an entity's
+// original value or lacks its placeholder, and entities that are not in the
+// input or not of a requested type. The leak check comes first: it is the
+// failure that matters most.
+func checkRedaction(input RedactInput, response RedactResponse) error {
+	for i, entity := range response.Entities {
+		if strings.Contains(response.Redacted, entity.Original) {
+			return fmt.Errorf("entity %d: %s value is still in the redacted text", i, entity.Type)
+		}
+	}
+	for i, entity := range response.Entities {
+		if !quoteAppears(input.Text, entity.Original) {
+			return fmt.Errorf("entity %d: original value does not appear in the input", i)
+		}
+		if len(input.Types) > 0 && entityType(input.Types, entity.Type) == "" {
+			return fmt.Errorf("entity %d: unknown type %q", i, entity.Type)
+		}
+		if !strings.Contains(response.Redacted, entity.Placeholder) {
+			return fmt.Errorf("entity %d: placeholder %q does not appear in the redacted text", i, entity.Placeholder)
+		}
+	}
+	return nil
+}
+
+// entityType returns the type of types matching name regardless of case and
+// surrounding space, or "" if there is none.
+func entityType(types []string, name string) string {
+	name = strings.TrimSpace(name)
+	for _, t := range types {
+		if strings.EqualFold(t, name) {
+			return t
+		}
+	}
+	return ""
+}
+
+// RedactSynapse removes personal or sensitive data from text, replacing it
+// with placeholders.
+type RedactSynapse struct {
+	policy   string
+	defaults RedactInput
+	base     *Base[RedactInput, RedactResponse]
+}
+
+// NewRedact creates a new redact synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewRedact(policy string, provider Provider, opts ...Option) (*RedactSynapse, error) {
+	synapse := &RedactSynapse{policy: policy}
+
+	base, err := NewSynapse(SynapseConfig[RedactInput, RedactResponse]{
+		Type:        "redact",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (r *RedactSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return r.base.GetPipeline()
+}
+
+// WithDefaults creates a new Redact with default input values, such as the
+// entity types to redact. These are merged with user input at execution
+// time.
+func (r *RedactSynapse) WithDefaults(defaults RedactInput) *RedactSynapse {
+	r.defaults = defaults
+	return r
+}
+
+// Fire redacts text and returns the redacted text.
+func (r *RedactSynapse) Fire(ctx context.Context, session *Session, text string) (string, error) {
+	response, err := r.FireWithInput(ctx, session, RedactInput{Text: text})
+	if err != nil {
+		return "", err
+	}
+	return response.Redacted, nil
+}
+
+// FireResult redacts text and returns the redacted text in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (r *RedactSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[string], error) {
+	result, err := r.execute(ctx, session, RedactInput{Text: text})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Redacted), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (r *RedactSynapse) FireWithInput(ctx context.Context, session *Session, input RedactInput) (RedactResponse, error) {
+	result, err := r.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a RedactResponse.
+func (r *RedactSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := r.FireWithInput(ctx, session, RedactInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and redacts the text, rejecting
+// responses that leak a redacted value.
+func (r *RedactSynapse) execute(ctx context.Context, session *Session, input RedactInput) (Result[RedactResponse], error) {
+	merged := r.mergeInputs(input)
+
+	prompt := r.buildPrompt(merged)
+	prompt.Schema = r.base.Schema()
+	result, err := r.base.service.executeChecked(ctx, session, prompt, merged.Temperature, func(response RedactResponse) error {
+		return checkRedaction(merged, response)
+	})
+	if err != nil {
+		return result, err
+	}
+	if len(merged.Types) > 0 {
+		for i, entity := range result.Value.Entities {
+			result.Value.Entities[i].Type = entityType(merged.Types, entity.Type)
+		}
+	}
+	return result, nil
+}
+
+// mergeInputs combines defaults with user input.
+func (r *RedactSynapse) mergeInputs(input RedactInput) RedactInput {
+	merged := r.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if len(input.Types) > 0 {
+		merged.Types = input.Types
+	}
+	if input.PlaceholderFormat != "" {
+		merged.PlaceholderFormat = input.PlaceholderFormat
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (r *RedactSynapse) buildPrompt(input RedactInput) *Prompt {
+	format := input.PlaceholderFormat
+	if format == "" {
+		format = DefaultPlaceholderFormat
+	}
+
+	constraints := []string{
+		"redacted: the full text with every sensitive value replaced by its placeholder and nothing else changed",
+		fmt.Sprintf("placeholder: %q, where {TYPE} is the entity type in upper case and {N} numbers the distinct values of that type from 1; "+
+			"the same value always gets the same placeholder", format),
+		"entities: one per distinct value, with original exactly as it appears in the text",
+		"redact every occurrence of a value, including partial forms such as a first name used alone",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining what was redacted",
+	}
+	if len(input.Types) > 0 {
+		constraints = append(constraints, fmt.Sprintf("types: redact only these entity types: %s", strings.Join(input.Types, ", ")))
+	}
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Redact the text according to this policy: %s", r.policy),
+		Input:       input.Text,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Redact creates a new redact synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	scrubber, err := Redact("remove customer contact details", provider, WithValidationRetry(2))
+//	response, err := scrubber.FireWithInput(ctx, session, RedactInput{
+//	    Text:  ticket,
+//	    Types: []string{"email", "phone", "name"},
+//	})
+//	// response.Redacted: "[NAME_1] asked us to call [PHONE_1]"
+func Redact(policy string, provider Provider, opts ...Option) (*RedactSynapse, error) {
+	return NewRedact(policy, provider, opts...)
+}
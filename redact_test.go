@@ -0,0 +1,252 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ticketText is the input of the redact tests.
+const ticketText = "Jane Doe (jane@example.com) asked us to call 555-0100. Jane prefers mornings."
+
+// ticketEntities are the entities of ticketText.
+var ticketEntities = []RedactedEntity{
+	{Type: "NAME", Original: "Jane Doe", Placeholder: "[NAME_1]"},
+	{Type: "email", Original: "jane@example.com", Placeholder: "[EMAIL_1]"},
+	{Type: "phone", Original: "555-0100", Placeholder: "[PHONE_1]"},
+}
+
+// ticketRedacted is ticketText with ticketEntities replaced.
+const ticketRedacted = "[NAME_1] ([EMAIL_1]) asked us to call [PHONE_1]. [NAME_1] prefers mornings."
+
+// ticketResponse is a valid redact response for ticketText.
+const ticketResponse = `{
+	"redacted": "[NAME_1] ([EMAIL_1]) asked us to call [PHONE_1]. [NAME_1] prefers mornings.",
+	"entities": [
+		{"type": "NAME", "original": "Jane Doe", "placeholder": "[NAME_1]"},
+		{"type": "email", "original": "jane@example.com", "placeholder": "[EMAIL_1]"},
+		{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"}
+	],
+	"confidence": 0.9,
+	"reasoning": ["contact details"]
+}`
+
+func TestRedactResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   ticketEntities[:1],
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_redacted_text", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   " ",
+			Entities:   ticketEntities[:1],
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for blank redacted text")
+		}
+	})
+
+	t.Run("entity_without_type", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   []RedactedEntity{{Original: "x", Placeholder: "y"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an entity without a type")
+		}
+	})
+
+	t.Run("entity_without_original", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   []RedactedEntity{{Type: "x", Placeholder: "y"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an entity without its original text")
+		}
+	})
+
+	t.Run("entity_without_placeholder", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   []RedactedEntity{{Type: "x", Original: "y"}},
+			Confidence: 0.9,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an entity without a placeholder")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   ticketEntities[:1],
+			Confidence: 2,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := RedactResponse{
+			Redacted:   "[NAME_1] called",
+			Entities:   ticketEntities[:1],
+			Confidence: 0.9,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestRedactSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return ticketResponse, nil
+	})
+	scrubber, err := Redact("remove customer contact details", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := scrubber.FireWithInput(context.Background(), NewSession(), RedactInput{
+		Text:  ticketText,
+		Types: []string{"name", "email", "phone"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Redacted != ticketRedacted || len(response.Entities) != 3 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	if response.Entities[0].Type != "name" {
+		t.Errorf("expected entity types as requested, got %q", response.Entities[0].Type)
+	}
+	for _, want := range []string{"remove customer contact details", `"[{TYPE}_{N}]"`, "only these entity types: name, email, phone"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestRedactSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"redacted": "<email> wrote in", "entities": [{"type": "email", "original": "jane@example.com", "placeholder": "<email>"}], "confidence": 0.9, "reasoning": ["an email"]}`, nil
+	})
+	scrubber, _ := Redact("remove emails", provider)
+	scrubber.WithDefaults(RedactInput{PlaceholderFormat: "<{TYPE}>"})
+
+	redacted, err := scrubber.Fire(context.Background(), NewSession(), "jane@example.com wrote in")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if redacted != "<email> wrote in" {
+		t.Errorf("unexpected redacted text: %q", redacted)
+	}
+	if !strings.Contains(prompt, `"<{TYPE}>"`) || strings.Contains(prompt, "only these entity types") {
+		t.Errorf("expected the default placeholder format and no type list, got %s", prompt)
+	}
+}
+
+func TestRedactSynapse_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{
+			"value left in the text",
+			`{"redacted": "[NAME_1] ([EMAIL_1]) asked us to call 555-0100. [NAME_1] prefers mornings.", "entities": [
+				{"type": "name", "original": "Jane Doe", "placeholder": "[NAME_1]"},
+				{"type": "email", "original": "jane@example.com", "placeholder": "[EMAIL_1]"},
+				{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"}
+			], "confidence": 0.9, "reasoning": ["x"]}`,
+			"entity 2: phone value is still in the redacted text",
+		},
+		{
+			"invented value",
+			`{"redacted": "[NAME_1] ([EMAIL_1]) asked us to call [PHONE_1]. [NAME_1] prefers mornings.", "entities": [
+				{"type": "name", "original": "Jane Doe", "placeholder": "[NAME_1]"},
+				{"type": "email", "original": "jane@example.com", "placeholder": "[EMAIL_1]"},
+				{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"},
+				{"type": "name", "original": "John", "placeholder": "[NAME_2]"}
+			], "confidence": 0.9, "reasoning": ["x"]}`,
+			"entity 3: original value does not appear in the input",
+		},
+		{
+			"unrequested type",
+			`{"redacted": "[NAME_1] ([EMAIL_1]) asked us to call [PHONE_1]. [NAME_1] prefers mornings.", "entities": [
+				{"type": "person", "original": "Jane Doe", "placeholder": "[NAME_1]"},
+				{"type": "email", "original": "jane@example.com", "placeholder": "[EMAIL_1]"},
+				{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"}
+			], "confidence": 0.9, "reasoning": ["x"]}`,
+			`entity 0: unknown type "person"`,
+		},
+		{
+			"missing placeholder",
+			`{"redacted": "[NAME_1] ([EMAIL_1]) asked us to call [PHONE]. [NAME_1] prefers mornings.", "entities": [
+				{"type": "name", "original": "Jane Doe", "placeholder": "[NAME_1]"},
+				{"type": "email", "original": "jane@example.com", "placeholder": "[EMAIL_1]"},
+				{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"}
+			], "confidence": 0.9, "reasoning": ["x"]}`,
+			`entity 2: placeholder "[PHONE_1]" does not appear in the redacted text`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scrubber, _ := Redact("contact details", NewMockProviderWithResponse(tt.response))
+
+			_, err := scrubber.FireWithInput(context.Background(), NewSession(), RedactInput{Text: ticketText, Types: []string{"name", "email", "phone"}})
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRedactSynapse_LeakNotInError(t *testing.T) {
+	leaked := `{"redacted": "call 555-0100", "entities": [{"type": "phone", "original": "555-0100", "placeholder": "[PHONE_1]"}], "confidence": 0.9, "reasoning": ["a phone number"]}`
+	scrubber, _ := Redact("contact details", NewMockProviderWithResponse(leaked))
+
+	_, err := scrubber.Fire(context.Background(), NewSession(), "call 555-0100")
+	if err == nil {
+		t.Fatal("expected the leak to be rejected")
+	}
+	if strings.Contains(err.Error(), "555-0100") {
+		t.Errorf("expected the leaked value kept out of the check's message, got %v", err)
+	}
+}
+
+func TestRedactSynapse_Invoke(t *testing.T) {
+	scrubber, _ := Redact("contact details", NewMockProviderWithResponse(ticketResponse))
+
+	validator, err := scrubber.Invoke(context.Background(), NewSession(), SynapseInput{Input: ticketText})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(RedactResponse); !ok || response.Redacted != ticketRedacted {
+		t.Errorf("expected RedactResponse, got %#v", validator)
+	}
+}
@@ -0,0 +1,90 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Result is a uniform envelope around a synapse's value and the metadata of
+// the call that produced it, so cost and latency accounting does not depend
+// on hooks. Synapses return it from FireResult.
+//
+// When a call fails, the metadata known at the point of failure is still
+// populated (for example the request ID, attempts, and the usage of a
+// response that failed validation) and Value is the zero value.
+type Result[T any] struct {
+	Value     T               // The synapse's value, as returned by Fire
+	Raw       json.RawMessage // Response body converted to JSON, before validation
+	Usage     *TokenUsage     // Token usage reported by the provider
+	Duration  time.Duration   // Time from request start to parsed response, including retries
+	RequestID string          // Request ID used for hook events
+	Provider  string          // Name of the provider the synapse is bound to
+	Attempts  int             // Provider calls made, including retries and fallbacks
+	ToolCalls []ToolCall      // Tools the model called instead of answering, with a *ToolCallsError
+	Logprobs  []TokenLogprob  // Log probabilities of the response tokens, for providers that return them
+
+	response string // Raw provider response text, kept for batch error reports
+}
+
+// withValue returns a copy of r's metadata carrying value instead.
+func withValue[T, U any](r Result[T], value U) Result[U] {
+	return Result[U]{
+		Value:     value,
+		Raw:       r.Raw,
+		Usage:     r.Usage,
+		Duration:  r.Duration,
+		RequestID: r.RequestID,
+		Provider:  r.Provider,
+		Attempts:  r.Attempts,
+		ToolCalls: r.ToolCalls,
+		Logprobs:  r.Logprobs,
+		response:  r.response,
+	}
+}
+
+// callRecorder collects the envelopes of synapse calls made with a context,
+// letting composites such as Chain report call metadata for each step.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []Result[any]
+}
+
+// withCallRecorder returns a context whose synapse calls are recorded.
+func withCallRecorder(ctx context.Context) (context.Context, *callRecorder) {
+	recorder := &callRecorder{}
+	return context.WithValue(ctx, callRecorderContextKey, recorder), recorder
+}
+
+// recordCall adds a call's envelope to the context's recorder, if any.
+func recordCall[T any](ctx context.Context, result Result[T]) {
+	recorder, ok := ctx.Value(callRecorderContextKey).(*callRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.calls = append(recorder.calls, withValue(result, any(result.Value)))
+}
+
+// summary returns the metadata of the recorded calls: the request ID, provider,
+// and raw body of the last call, and the attempts and usage of all calls,
+// including failed ones.
+func (r *callRecorder) summary() Result[any] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summary := Result[any]{Usage: &TokenUsage{}}
+	for _, call := range r.calls {
+		summary.Attempts += call.Attempts
+		addUsage(summary.Usage, call.Usage)
+	}
+	if len(r.calls) > 0 {
+		last := r.calls[len(r.calls)-1]
+		summary.RequestID = last.RequestID
+		summary.Provider = last.Provider
+		summary.Raw = last.Raw
+	}
+	return summary
+}
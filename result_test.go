@@ -0,0 +1,215 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// usageProvider responds with fixed content and usage after an optional delay,
+// failing the first failures calls.
+type usageProvider struct {
+	content  string
+	usage    TokenUsage
+	delay    time.Duration
+	failures int
+	calls    int
+	logprobs []TokenLogprob
+}
+
+func (p *usageProvider) Call(_ context.Context, _ []Message, _ float32) (*ProviderResponse, error) {
+	p.calls++
+	time.Sleep(p.delay)
+	if p.calls <= p.failures {
+		return nil, fmt.Errorf("transient failure %d", p.calls)
+	}
+	return &ProviderResponse{Content: p.content, Usage: p.usage, Logprobs: p.logprobs}, nil
+}
+
+func (*usageProvider) Name() string {
+	return "usage-provider"
+}
+
+func TestBinary_FireResult(t *testing.T) {
+	provider := &usageProvider{
+		content:  "```json\n{\"decision\": true, \"confidence\": 0.9, \"reasoning\": [\"ok\"]}\n```",
+		usage:    TokenUsage{Prompt: 120, Completion: 30, Total: 150},
+		delay:    2 * time.Millisecond,
+		failures: 1,
+	}
+	synapse, err := Binary("valid", provider, WithRetry(3))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-result")
+	result, err := synapse.FireResult(ctx, NewSession(), "input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Value {
+		t.Error("expected true value")
+	}
+	if string(result.Raw) != `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}` {
+		t.Errorf("expected raw JSON without fences, got %s", result.Raw)
+	}
+	if result.Usage == nil || *result.Usage != provider.usage {
+		t.Errorf("expected provider usage, got %+v", result.Usage)
+	}
+	if result.Duration < 2*provider.delay {
+		t.Errorf("expected duration to include both attempts, got %v", result.Duration)
+	}
+	if result.RequestID == "" || result.RequestID == "req-result" {
+		t.Errorf("expected the call's own request ID, got %q", result.RequestID)
+	}
+	if result.Provider != "usage-provider" {
+		t.Errorf("expected provider name, got %q", result.Provider)
+	}
+	if result.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", result.Attempts)
+	}
+}
+
+func TestFireResult_Logprobs(t *testing.T) {
+	provider := &usageProvider{
+		content:  `{"decision": false, "confidence": 0.8, "reasoning": ["no"]}`,
+		logprobs: []TokenLogprob{{Token: "false", Logprob: -0.22, TopLogprobs: []TokenLogprob{{Token: "false", Logprob: -0.22}, {Token: "true", Logprob: -1.6}}}},
+	}
+	synapse, _ := Binary("valid", provider)
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Logprobs) != 1 || result.Logprobs[0].Token != "false" || len(result.Logprobs[0].TopLogprobs) != 2 {
+		t.Errorf("expected provider logprobs, got %+v", result.Logprobs)
+	}
+
+	provider.logprobs = nil
+	result, _ = synapse.FireResult(context.Background(), NewSession(), "input")
+	if result.Logprobs != nil {
+		t.Errorf("expected no logprobs, got %+v", result.Logprobs)
+	}
+}
+
+func TestFireResult_FailureKeepsMetadata(t *testing.T) {
+	provider := &usageProvider{
+		content: `{"decision": true, "confidence": 7, "reasoning": ["ok"]}`,
+		usage:   TokenUsage{Prompt: 100, Completion: 20, Total: 120},
+	}
+	synapse, err := Binary("valid", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "input")
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("expected ErrInvalidResponse, got %v", err)
+	}
+	if result.Value {
+		t.Error("expected zero value on failure")
+	}
+	if result.Usage == nil || result.Usage.Total != 120 {
+		t.Errorf("expected usage of the failed call, got %+v", result.Usage)
+	}
+	if result.RequestID == "" || result.Attempts != 1 || len(result.Raw) == 0 {
+		t.Errorf("expected request metadata on failure, got %+v", result)
+	}
+
+	// Provider failures carry attempts but no usage
+	failing := &usageProvider{failures: 5}
+	synapse, err = Binary("valid", failing, WithRetry(2))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	result, err = synapse.FireResult(context.Background(), NewSession(), "input")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if result.Attempts != 2 || result.Usage != nil {
+		t.Errorf("expected 2 attempts and no usage, got %d and %+v", result.Attempts, result.Usage)
+	}
+}
+
+func TestFireResult_Values(t *testing.T) {
+	ctx := context.Background()
+	provider := NewMockProvider()
+
+	classification, err := Classification("what kind", []string{"bug", "feature"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classification: %v", err)
+	}
+	primary, err := classification.FireResult(ctx, NewSession(), "it crashes")
+	if err != nil || primary.Value == "" || primary.Attempts != 1 {
+		t.Errorf("unexpected classification result: %+v (err %v)", primary, err)
+	}
+
+	ranking, err := Ranking("priority", provider)
+	if err != nil {
+		t.Fatalf("failed to create ranking: %v", err)
+	}
+	ranked, err := ranking.FireResult(ctx, NewSession(), []string{"a", "b", "c"})
+	if err != nil || len(ranked.Value) == 0 {
+		t.Errorf("unexpected ranking result: %+v (err %v)", ranked, err)
+	}
+
+	sentiment, err := Sentiment("overall", provider)
+	if err != nil {
+		t.Fatalf("failed to create sentiment: %v", err)
+	}
+	overall, err := sentiment.FireResult(ctx, NewSession(), "I love it")
+	if err != nil || overall.Value != normalizeSentiment(overall.Value) {
+		t.Errorf("expected normalized sentiment, got %+v (err %v)", overall, err)
+	}
+
+	transform, err := Transform("summarize", provider)
+	if err != nil {
+		t.Fatalf("failed to create transform: %v", err)
+	}
+	output, err := transform.FireResult(ctx, NewSession(), "long text")
+	if err != nil || output.Value == "" || output.Usage == nil {
+		t.Errorf("unexpected transform result: %+v (err %v)", output, err)
+	}
+
+	failing, err := Transform("summarize", NewMockProviderWithError("down"))
+	if err != nil {
+		t.Fatalf("failed to create transform: %v", err)
+	}
+	if _, err := failing.FireResult(ctx, NewSession(), "text"); err == nil || !strings.HasPrefix(err.Error(), "transform failed") {
+		t.Errorf("expected wrapped transform error, got %v", err)
+	}
+}
+
+func TestChain_StepEnvelopes(t *testing.T) {
+	provider := &usageProvider{
+		content:  `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`,
+		usage:    TokenUsage{Prompt: 10, Completion: 5, Total: 15},
+		failures: 1,
+	}
+	synapse, err := Binary("valid", provider, WithRetry(2))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	chain, err := Chain(Step("check", synapse.Fire))
+	if err != nil {
+		t.Fatalf("failed to build chain: %v", err)
+	}
+	ctx := ContextWithRequestID(context.Background(), "req-chain")
+	result, err := chain.Run(ctx, NewSession(), "input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	step := result.Outputs[0]
+	if step.Value != true || step.RequestID == "" || step.RequestID == "req-chain" || step.Provider != "usage-provider" {
+		t.Errorf("unexpected step envelope: %+v", step.Result)
+	}
+	if step.Attempts != 2 || step.Usage == nil || step.Usage.Total != 15 || len(step.Raw) == 0 {
+		t.Errorf("expected attempts, usage, and raw in step envelope, got %+v", step.Result)
+	}
+}
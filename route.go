@@ -0,0 +1,136 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RouteResponse contains the result of a routed call.
+type RouteResponse struct {
+	Classification ClassificationResponse // Classification used to pick the route
+	Route          string                 // Category whose synapse handled the input; empty when the fallback did
+	Fallback       bool                   // Whether the fallback synapse handled the input
+	Result         Validator              // Full response of the synapse that handled the input
+}
+
+// Validate checks if the response is valid.
+func (r RouteResponse) Validate() error {
+	if err := r.Classification.Validate(); err != nil {
+		return fmt.Errorf("classification: %w", err)
+	}
+	if r.Result == nil {
+		return fmt.Errorf("result required but nil")
+	}
+	return r.Result.Validate()
+}
+
+// RouterSynapse classifies input and dispatches it to the synapse registered
+// for the resulting category.
+type RouterSynapse struct {
+	classifier    *ClassificationSynapse
+	routes        map[string]Synapse
+	fallback      Synapse
+	minConfidence float64
+}
+
+// Route creates a router that classifies input with classifier and fires the
+// synapse registered in routes for the primary category, passing it the
+// original input. Route keys must be categories of the classifier and are
+// matched case-insensitively.
+//
+// The fallback handles categories without a route and, when a minimum
+// confidence is set, low-confidence classifications. It may be nil, in which
+// case those calls fail with ErrNoRoute.
+//
+// Example:
+//
+//	router, err := zyn.Route(classifier, map[string]zyn.Synapse{
+//	    "billing":   billingExtractor,
+//	    "technical": triageClassifier,
+//	}, generalReply)
+//	response, err := router.WithMinConfidence(0.7).Fire(ctx, session, ticket)
+func Route(classifier *ClassificationSynapse, routes map[string]Synapse, fallback Synapse) (*RouterSynapse, error) {
+	if classifier == nil {
+		return nil, fmt.Errorf("router: classifier is required")
+	}
+
+	normalized := make(map[string]Synapse, len(routes))
+	for category, synapse := range routes {
+		if synapse == nil {
+			return nil, fmt.Errorf("router: route %q has no synapse", category)
+		}
+		if !containsFold(classifier.categories, category) {
+			return nil, fmt.Errorf("router: route %q is not a classifier category (expected one of %s)",
+				category, strings.Join(classifier.categories, ", "))
+		}
+		normalized[strings.ToLower(category)] = synapse
+	}
+
+	return &RouterSynapse{
+		classifier: classifier,
+		routes:     normalized,
+		fallback:   fallback,
+	}, nil
+}
+
+// WithMinConfidence sends classifications with confidence below threshold to
+// the fallback instead of the matched route.
+func (r *RouterSynapse) WithMinConfidence(threshold float64) *RouterSynapse {
+	r.minConfidence = threshold
+	return r
+}
+
+// Fire classifies input and dispatches it to the matching route.
+func (r *RouterSynapse) Fire(ctx context.Context, session *Session, input string) (RouteResponse, error) {
+	return r.FireWithInput(ctx, session, SynapseInput{Input: input})
+}
+
+// FireWithInput classifies input and dispatches it to the matching route.
+// Both the classification and the routed call are recorded to the session.
+// Temperature applies to the routed call only; the classifier uses its own default.
+func (r *RouterSynapse) FireWithInput(ctx context.Context, session *Session, input SynapseInput) (RouteResponse, error) {
+	classification, err := r.classifier.FireWithInput(ctx, session, ClassificationInput{
+		Subject: input.Input,
+		Context: input.Context,
+	})
+	if err != nil {
+		return RouteResponse{}, fmt.Errorf("router: classification failed: %w", err)
+	}
+
+	response := RouteResponse{Classification: classification}
+
+	target, matched := r.routes[strings.ToLower(classification.Primary)]
+	switch {
+	case matched && classification.Confidence >= r.minConfidence:
+		response.Route = classification.Primary
+	case r.fallback != nil:
+		target = r.fallback
+		response.Fallback = true
+	case matched:
+		return response, fmt.Errorf("%w: confidence %.2f for category %q is below %.2f",
+			ErrNoRoute, classification.Confidence, classification.Primary, r.minConfidence)
+	default:
+		return response, fmt.Errorf("%w: category %q", ErrNoRoute, classification.Primary)
+	}
+
+	result, err := target.Invoke(ctx, session, input)
+	if err != nil {
+		if response.Fallback {
+			return response, fmt.Errorf("router: fallback failed: %w", err)
+		}
+		return response, fmt.Errorf("router: route %q failed: %w", response.Route, err)
+	}
+	response.Result = result
+	return response, nil
+}
+
+// containsFold reports whether values contains target, ignoring case.
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// routeProvider answers the router's classifier with the given category and
+// confidence, and any routed synapse with a fixed binary or transform response.
+func routeProvider(category string, confidence string) Provider {
+	return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		switch {
+		case strings.Contains(prompt, "Which team handles this"):
+			return `{"primary": "` + category + `", "secondary": "", "confidence": ` + confidence + `, "reasoning": ["routed"]}`, nil
+		case strings.Contains(prompt, "is urgent"):
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["outage"]}`, nil
+		default:
+			return `{"output": "Thanks, we will get back to you.", "confidence": 0.8, "changes": ["reply"], "reasoning": ["general"]}`, nil
+		}
+	})
+}
+
+func newTestRouter(t *testing.T, provider Provider, withFallback bool) *RouterSynapse {
+	t.Helper()
+	classifier, err := Classification("Which team handles this?", []string{"technical", "billing", "other"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classifier: %v", err)
+	}
+	urgent, err := Binary("this ticket is urgent", provider)
+	if err != nil {
+		t.Fatalf("failed to create binary: %v", err)
+	}
+	var fallback Synapse
+	if withFallback {
+		reply, replyErr := Transform("write a general reply", provider)
+		if replyErr != nil {
+			t.Fatalf("failed to create transform: %v", replyErr)
+		}
+		fallback = reply
+	}
+
+	router, err := Route(classifier, map[string]Synapse{"Technical": urgent}, fallback)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	return router
+}
+
+func TestRoute_Matched(t *testing.T) {
+	router := newTestRouter(t, routeProvider("technical", "0.9"), true)
+	session := NewSession()
+
+	response, err := router.Fire(context.Background(), session, "The API is down")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Route != "technical" || response.Fallback {
+		t.Errorf("expected technical route, got %q (fallback %v)", response.Route, response.Fallback)
+	}
+	if response.Classification.Primary != "technical" {
+		t.Errorf("expected classification details, got %+v", response.Classification)
+	}
+	binary, ok := response.Result.(BinaryResponse)
+	if !ok || !binary.Decision {
+		t.Errorf("expected routed BinaryResponse, got %#v", response.Result)
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("expected valid response, got %v", err)
+	}
+
+	// Classification and routed call are both recorded
+	if session.Len() != 4 {
+		t.Errorf("expected 4 session messages, got %d", session.Len())
+	}
+	first, _ := session.At(0)
+	routed, _ := session.At(2)
+	if !strings.Contains(first.Content, "The API is down") || !strings.Contains(routed.Content, "The API is down") {
+		t.Error("expected original input passed to both classifier and route")
+	}
+}
+
+func TestRoute_Unmatched(t *testing.T) {
+	router := newTestRouter(t, routeProvider("billing", "0.9"), true)
+
+	response, err := router.Fire(context.Background(), NewSession(), "Refund my invoice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.Fallback || response.Route != "" {
+		t.Errorf("expected fallback, got route %q (fallback %v)", response.Route, response.Fallback)
+	}
+	if _, ok := response.Result.(TransformResponse); !ok {
+		t.Errorf("expected fallback TransformResponse, got %T", response.Result)
+	}
+}
+
+func TestRoute_LowConfidence(t *testing.T) {
+	router := newTestRouter(t, routeProvider("technical", "0.4"), true).WithMinConfidence(0.7)
+
+	response, err := router.Fire(context.Background(), NewSession(), "Something seems off")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !response.Fallback {
+		t.Errorf("expected low-confidence classification to use fallback, got route %q", response.Route)
+	}
+	if response.Classification.Primary != "technical" {
+		t.Errorf("expected classification details preserved, got %+v", response.Classification)
+	}
+
+	// Confidence at the threshold takes the route
+	router = newTestRouter(t, routeProvider("technical", "0.7"), true).WithMinConfidence(0.7)
+	response, err = router.Fire(context.Background(), NewSession(), "The API is down")
+	if err != nil || response.Fallback {
+		t.Errorf("expected route at threshold, got fallback %v (err %v)", response.Fallback, err)
+	}
+}
+
+func TestRoute_NoFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		category   string
+		confidence string
+		want       string
+	}{
+		{name: "unmatched", category: "billing", confidence: "0.9", want: `no route: category "billing"`},
+		{name: "low confidence", category: "technical", confidence: "0.4", want: "below 0.70"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(t, routeProvider(tt.category, tt.confidence), false).WithMinConfidence(0.7)
+
+			response, err := router.Fire(context.Background(), NewSession(), "input")
+			if !errors.Is(err, ErrNoRoute) {
+				t.Fatalf("expected ErrNoRoute, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, err.Error())
+			}
+			if response.Classification.Primary != tt.category {
+				t.Errorf("expected classification returned with error, got %+v", response.Classification)
+			}
+		})
+	}
+}
+
+func TestRoute_Errors(t *testing.T) {
+	provider := NewMockProvider()
+	classifier, err := Classification("Which team?", []string{"technical", "billing"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classifier: %v", err)
+	}
+	binary, err := Binary("urgent", provider)
+	if err != nil {
+		t.Fatalf("failed to create binary: %v", err)
+	}
+
+	if _, err := Route(nil, nil, nil); err == nil {
+		t.Error("expected error for nil classifier")
+	}
+	if _, err := Route(classifier, map[string]Synapse{"shipping": binary}, nil); err == nil ||
+		!strings.Contains(err.Error(), `route "shipping" is not a classifier category`) {
+		t.Errorf("expected unknown category error, got %v", err)
+	}
+	if _, err := Route(classifier, map[string]Synapse{"billing": nil}, nil); err == nil {
+		t.Error("expected error for nil route synapse")
+	}
+
+	failing, err := Classification("Which team?", []string{"technical"}, NewMockProviderWithError("down"))
+	if err != nil {
+		t.Fatalf("failed to create classifier: %v", err)
+	}
+	router, err := Route(failing, nil, binary)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	if _, err := router.Fire(context.Background(), NewSession(), "input"); err == nil ||
+		!strings.Contains(err.Error(), "router: classification failed") {
+		t.Errorf("expected classification error, got %v", err)
+	}
+}
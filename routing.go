@@ -0,0 +1,132 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/zoobzio/capitan"
+)
+
+// RouteFunc picks the route for a call from the messages and temperature
+// the call is made with.
+type RouteFunc func(messages []Message, temperature float32) string
+
+// RoutingProvider is a Provider that sends each call to one of several
+// providers, chosen by a RouteFunc. It lets one synapse send cheap calls to a
+// small model and long or difficult ones to a larger model.
+// It is safe for concurrent use when the route function is.
+type RoutingProvider struct {
+	route    RouteFunc
+	routes   map[string]Provider
+	fallback Provider
+}
+
+// NewRoutingProvider creates a provider that calls the provider registered
+// in routes under the name route returns. Calls routed to a name without a
+// provider go to fallback. Route names need not match provider names, so
+// several providers of the same kind can be routed between.
+//
+// Each call emits ProviderRouted with the route name, or the fallback's
+// name, under RoutedProviderKey and, under RouteFallbackKey, whether the
+// call fell through to the fallback. The chosen provider's own hook events
+// also carry RoutedProviderKey.
+//
+// Example:
+//
+//	router, err := zyn.NewRoutingProvider(func(messages []zyn.Message, _ float32) string {
+//	    if len(messages[len(messages)-1].Content) > 8000 {
+//	        return "large"
+//	    }
+//	    return "small"
+//	}, map[string]zyn.Provider{"small": mini, "large": gpt4o}, mini)
+func NewRoutingProvider(route RouteFunc, routes map[string]Provider, fallback Provider) (*RoutingProvider, error) {
+	if route == nil {
+		return nil, fmt.Errorf("routing provider: route function is required")
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("routing provider: fallback provider is required")
+	}
+	for name, provider := range routes {
+		if provider == nil {
+			return nil, fmt.Errorf("routing provider: route %q has no provider", name)
+		}
+	}
+
+	return &RoutingProvider{route: route, routes: maps.Clone(routes), fallback: fallback}, nil
+}
+
+// Name returns the provider identifier.
+func (r *RoutingProvider) Name() string {
+	return "routing"
+}
+
+// Call sends messages to the provider the route function picks.
+func (r *RoutingProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	ctx, provider := r.pick(ctx, messages, temperature)
+	return provider.Call(ctx, messages, temperature)
+}
+
+// CallWithTools sends messages and tools to the provider the route function
+// picks. Calls routed to a provider that does not implement ToolProvider
+// fail with a *ToolsUnsupportedError.
+func (r *RoutingProvider) CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error) {
+	ctx, provider := r.pick(ctx, messages, temperature)
+	if len(tools) == 0 {
+		return provider.Call(ctx, messages, temperature)
+	}
+	toolProvider, ok := provider.(ToolProvider)
+	if !ok {
+		return nil, &ToolsUnsupportedError{Provider: provider.Name(), Tools: len(tools)}
+	}
+	return toolProvider.CallWithTools(ctx, messages, temperature, tools)
+}
+
+// Capabilities returns what every call can rely on whichever provider it is
+// routed to: vision when all providers support it, and the smallest context
+// window among those that advertise one, so prompt size checks hold on every
+// route. The model is left unset since it varies by call.
+func (r *RoutingProvider) Capabilities() Capabilities {
+	capabilities := Capabilities{Vision: true}
+	for _, provider := range append(slices.Collect(maps.Values(r.routes)), r.fallback) {
+		capable, ok := provider.(CapabilitiesProvider)
+		if !ok {
+			capabilities.Vision = false
+			continue
+		}
+		backend := capable.Capabilities()
+		capabilities.Vision = capabilities.Vision && backend.Vision
+		if backend.MaxContextTokens > 0 && (capabilities.MaxContextTokens == 0 || backend.MaxContextTokens < capabilities.MaxContextTokens) {
+			capabilities.MaxContextTokens = backend.MaxContextTokens
+		}
+	}
+	return capabilities
+}
+
+// Close closes every route's provider and the fallback that implement
+// io.Closer, returning their errors joined.
+func (r *RoutingProvider) Close() error {
+	providers := make([]Provider, 0, len(r.routes)+1)
+	for _, name := range slices.Sorted(maps.Keys(r.routes)) {
+		providers = append(providers, r.routes[name])
+	}
+	return CloseProviders(append(providers, r.fallback)...)
+}
+
+// pick routes a call, emitting ProviderRouted and returning a context whose
+// hook events name the chosen route.
+func (r *RoutingProvider) pick(ctx context.Context, messages []Message, temperature float32) (context.Context, Provider) {
+	name := r.route(messages, temperature)
+	provider, ok := r.routes[name]
+	if !ok {
+		name, provider = r.fallback.Name(), r.fallback
+	}
+
+	ctx = ContextWithMeta(ctx, RoutedProviderKey.Name(), name)
+	capitan.Info(ctx, ProviderRouted, HookFields(ctx,
+		ProviderKey.Field(r.Name()),
+		RouteFallbackKey.Field(!ok),
+	)...)
+	return ctx, provider
+}
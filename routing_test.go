@@ -0,0 +1,284 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zoobzio/capitan"
+)
+
+// routeByLength sends prompts longer than 2000 characters to "large" and the
+// rest to "small".
+func routeByLength(messages []Message, _ float32) string {
+	if len(messages[len(messages)-1].Content) > 2000 {
+		return "large"
+	}
+	return "small"
+}
+
+// routeRecorder collects ProviderRouted events.
+type routeRecorder struct {
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+	providers []string
+	fallbacks []bool
+}
+
+func recordRoutes(t *testing.T, events int) *routeRecorder {
+	t.Helper()
+	recorder := &routeRecorder{}
+	recorder.wg.Add(events)
+	listener := capitan.Hook(ProviderRouted, func(_ context.Context, e *capitan.Event) {
+		defer recorder.wg.Done()
+		provider, _ := RoutedProviderKey.From(e)
+		fallback, _ := RouteFallbackKey.From(e)
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.providers = append(recorder.providers, provider)
+		recorder.fallbacks = append(recorder.fallbacks, fallback)
+	})
+	t.Cleanup(listener.Close)
+	return recorder
+}
+
+func TestNewRoutingProvider(t *testing.T) {
+	small := NewMockProviderWithName("small")
+
+	tests := map[string]struct {
+		route    RouteFunc
+		routes   map[string]Provider
+		fallback Provider
+	}{
+		"no route":     {fallback: small},
+		"no fallback":  {route: routeByLength},
+		"nil provider": {route: routeByLength, routes: map[string]Provider{"small": small, "large": nil}, fallback: small},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRoutingProvider(tt.route, tt.routes, tt.fallback); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRoutingProvider_RoutesByMessageLength(t *testing.T) {
+	small := NewMockProviderWithName("small")
+	large := NewMockProviderWithName("large")
+	router, err := NewRoutingProvider(routeByLength, map[string]Provider{"small": small, "large": large}, small)
+	if err != nil {
+		t.Fatalf("failed to create router: %v", err)
+	}
+	if router.Name() != "routing" {
+		t.Errorf("expected name routing, got %s", router.Name())
+	}
+
+	recorder := recordRoutes(t, 2)
+	synapse, _ := Binary("Is this text positive?", router)
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "short input"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), strings.Repeat("long input ", 300)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recorder.wg.Wait()
+	if len(recorder.providers) != 2 || recorder.providers[0] != "small" || recorder.providers[1] != "large" {
+		t.Errorf("expected small then large, got %v", recorder.providers)
+	}
+	if recorder.fallbacks[0] || recorder.fallbacks[1] {
+		t.Errorf("expected no fallbacks, got %v", recorder.fallbacks)
+	}
+
+	// A failing backend shows the call reached it
+	large.SetAvailable(false)
+	if _, err := synapse.Fire(context.Background(), NewSession(), strings.Repeat("long input ", 300)); err == nil {
+		t.Error("expected long input to reach the unavailable large provider")
+	}
+	if _, err := synapse.Fire(context.Background(), NewSession(), "short input"); err != nil {
+		t.Errorf("expected short input to reach small provider, got %v", err)
+	}
+}
+
+func TestRoutingProvider_UnknownNameFallsThrough(t *testing.T) {
+	small := NewMockProviderWithName("small")
+	large := NewMockProviderWithName("large")
+	fallback := NewMockProviderWithName("default")
+	router, _ := NewRoutingProvider(func([]Message, float32) string { return "unknown" }, map[string]Provider{"small": small, "large": large}, fallback)
+
+	recorder := recordRoutes(t, 1)
+	small.SetAvailable(false)
+	large.SetAvailable(false)
+
+	if _, err := router.Call(context.Background(), []Message{{Role: RoleUser, Content: "input"}}, 0.1); err != nil {
+		t.Fatalf("expected fallback to answer, got %v", err)
+	}
+
+	recorder.wg.Wait()
+	if len(recorder.providers) != 1 || recorder.providers[0] != "default" || !recorder.fallbacks[0] {
+		t.Errorf("expected fallback route, got %v %v", recorder.providers, recorder.fallbacks)
+	}
+}
+
+func TestRoutingProvider_BackendEventsCarryRoute(t *testing.T) {
+	backend := &hookingProvider{Provider: NewMockProviderWithName("large")}
+	router, _ := NewRoutingProvider(func([]Message, float32) string { return "large" }, map[string]Provider{"large": backend}, NewMockProviderWithName("small"))
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		route string
+	)
+	wg.Add(1)
+	listener := capitan.Hook(ProviderCallStarted, func(_ context.Context, e *capitan.Event) {
+		defer wg.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		route, _ = RoutedProviderKey.From(e)
+	})
+	defer listener.Close()
+
+	if _, err := router.Call(context.Background(), []Message{{Role: RoleUser, Content: "input"}}, 0.1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if route != "large" {
+		t.Errorf("expected backend events to carry the route, got %q", route)
+	}
+}
+
+// hookingProvider emits ProviderCallStarted before calling its provider, as
+// the HTTP providers do.
+type hookingProvider struct {
+	Provider
+}
+
+func (p *hookingProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	capitan.Info(ctx, ProviderCallStarted, HookFields(ctx, ProviderKey.Field(p.Name()))...)
+	return p.Provider.Call(ctx, messages, temperature)
+}
+
+func TestRoutingProvider_RouteNames(t *testing.T) {
+	mini := NewMockProviderWithName("openai")
+	large := NewMockProviderWithName("openai")
+	router, _ := NewRoutingProvider(routeByLength, map[string]Provider{"small": mini, "large": large}, mini)
+
+	recorder := recordRoutes(t, 1)
+	large.SetAvailable(false)
+	if _, err := router.Call(context.Background(), []Message{{Role: RoleUser, Content: strings.Repeat("long input ", 300)}}, 0.1); err == nil {
+		t.Error("expected the call to reach the large provider")
+	}
+
+	recorder.wg.Wait()
+	if recorder.providers[0] != "large" {
+		t.Errorf("expected route name in event, got %v", recorder.providers)
+	}
+}
+
+func TestRoutingProvider_Tools(t *testing.T) {
+	tools := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+	plain := NewMockProviderWithName("plain")
+	target := "tools"
+	router, _ := NewRoutingProvider(func([]Message, float32) string { return target }, map[string]Provider{"tools": tools, "plain": plain}, plain)
+	synapse, _ := Binary("Has order A-1 shipped?", router, WithTools(lookupOrder))
+
+	_, err := synapse.Fire(context.Background(), NewSession(), "order A-1")
+	if !errors.Is(err, ErrToolCalls) || len(tools.tools) != 1 {
+		t.Fatalf("expected tool calls from the tool provider, got %v", err)
+	}
+
+	target = "plain"
+	_, err = synapse.Fire(context.Background(), NewSession(), "order A-1")
+	var unsupported *ToolsUnsupportedError
+	if !errors.As(err, &unsupported) || unsupported.Provider != "plain" {
+		t.Errorf("expected ToolsUnsupportedError from plain, got %v", err)
+	}
+}
+
+func TestRoutingProvider_Capabilities(t *testing.T) {
+	small := &capableProvider{MockProvider: NewMockProviderWithName("small"), capabilities: Capabilities{Vision: true, MaxContextTokens: 16000, Model: "gpt-4o-mini"}}
+	large := &capableProvider{MockProvider: NewMockProviderWithName("large"), capabilities: Capabilities{Vision: true, MaxContextTokens: 128000, Model: "gpt-4o"}}
+
+	routes := map[string]Provider{"small": small, "large": large}
+	router, _ := NewRoutingProvider(routeByLength, routes, small)
+	if got := router.Capabilities(); got != (Capabilities{Vision: true, MaxContextTokens: 16000}) {
+		t.Errorf("unexpected capabilities: %+v", got)
+	}
+
+	router, _ = NewRoutingProvider(routeByLength, routes, NewMockProviderWithName("plain"))
+	if got := router.Capabilities(); got.Vision || got.MaxContextTokens != 16000 {
+		t.Errorf("expected no vision with a provider lacking capabilities, got %+v", got)
+	}
+
+	unlimited := &capableProvider{MockProvider: NewMockProviderWithName("unlimited"), capabilities: Capabilities{Vision: true}}
+	router, _ = NewRoutingProvider(routeByLength, map[string]Provider{"large": large}, unlimited)
+	if got := router.Capabilities(); got.MaxContextTokens != 128000 {
+		t.Errorf("expected a provider without a limit to be ignored, got %+v", got)
+	}
+}
+
+// capableProvider is a MockProvider advertising fixed capabilities.
+type capableProvider struct {
+	*MockProvider
+	capabilities Capabilities
+}
+
+func (p *capableProvider) Capabilities() Capabilities { return p.capabilities }
+
+func TestRoutingProvider_Concurrent(t *testing.T) {
+	small := NewMockProviderWithName("small")
+	large := NewMockProviderWithName("large")
+	router, _ := NewRoutingProvider(routeByLength, map[string]Provider{"small": small, "large": large}, small)
+	synapse, _ := Binary("Is this text positive?", router)
+
+	const calls = 20
+	recorder := recordRoutes(t, calls)
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			input := "short input"
+			if i%2 == 1 {
+				input = strings.Repeat("long input ", 300)
+			}
+			if _, err := synapse.Fire(context.Background(), NewSession(), input); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	recorder.wg.Wait()
+	counts := map[string]int{}
+	for _, provider := range recorder.providers {
+		counts[provider]++
+	}
+	if counts["small"] != calls/2 || counts["large"] != calls/2 {
+		t.Errorf("expected even split, got %v", counts)
+	}
+}
+
+func TestRoutingProvider_Close(t *testing.T) {
+	small := NewMockProviderWithName("small")
+	large := NewMockProviderWithName("large")
+	fallback := NewMockProviderWithName("fallback")
+	router, _ := NewRoutingProvider(routeByLength, map[string]Provider{"small": small, "large": large}, fallback)
+
+	if err := CloseProviders(router); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !small.Closed() || !large.Closed() || !fallback.Closed() {
+		t.Error("expected every route and the fallback closed")
+	}
+	if _, err := router.Call(context.Background(), userMessage("input"), 0.1); !errors.Is(err, ErrProviderClosed) {
+		t.Errorf("expected ErrProviderClosed, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package zyn
+
+import "github.com/zoobzio/pipz"
+
+// Identities for sampling options.
+var (
+	seedID          = pipz.NewIdentity("zyn:seed", "Sets the sampling seed")
+	maxTokensID     = pipz.NewIdentity("zyn:max-tokens", "Limits completion tokens")
+	topPID          = pipz.NewIdentity("zyn:top-p", "Sets nucleus sampling")
+	stopSequencesID = pipz.NewIdentity("zyn:stop-sequences", "Sets sequences that end the completion")
+)
+
+// WithSeed asks the provider to sample deterministically with seed, so
+// repeated calls with the same prompt and parameters return the same
+// response as far as the provider allows. Use it to compare outputs across
+// prompt changes in regression tests.
+//
+// Providers read the seed from CallParams; those without seed support ignore
+// it. OpenAI reports the backend configuration that served each call as
+// SystemFingerprintKey on ProviderCallCompleted: outputs are only expected to
+// repeat while the fingerprint stays the same.
+func WithSeed(seed int) Option {
+	return withRequest(seedID, func(req *SynapseRequest) {
+		req.Seed = &seed
+	})
+}
+
+// WithMaxTokens limits the completion to maxTokens tokens, capping the cost
+// of each call. A response cut off at the limit fails with a
+// *ResponseTruncatedError (matching ErrResponseTruncated) instead of a parse
+// error; it is not retried, since the retry would hit the same limit.
+func WithMaxTokens(maxTokens int) Option {
+	return withRequest(maxTokensID, func(req *SynapseRequest) {
+		req.MaxTokens = maxTokens
+	})
+}
+
+// WithTopP sets nucleus sampling: the model samples only from the most likely
+// tokens whose probabilities add up to topP. Adjust this or the temperature,
+// not both.
+func WithTopP(topP float32) Option {
+	return withRequest(topPID, func(req *SynapseRequest) {
+		req.TopP = topP
+	})
+}
+
+// WithStopSequences ends the completion at the first of the sequences the
+// model generates. The sequence itself is not returned, so avoid sequences
+// that can appear inside the structured response.
+func WithStopSequences(sequences ...string) Option {
+	return withRequest(stopSequencesID, func(req *SynapseRequest) {
+		req.Stop = sequences
+	})
+}
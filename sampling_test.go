@@ -0,0 +1,127 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithSeed(t *testing.T) {
+	ctx := context.Background()
+	fire := map[string]func(Provider, ...Option){
+		"binary": func(p Provider, opts ...Option) {
+			s, _ := Binary("question", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), "input")
+		},
+		"classification": func(p Provider, opts ...Option) {
+			s, _ := Classification("question", []string{"a", "b"}, p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), "input")
+		},
+		"sentiment": func(p Provider, opts ...Option) {
+			s, _ := Sentiment("tone", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), "input")
+		},
+		"ranking": func(p Provider, opts ...Option) {
+			s, _ := Ranking("relevance", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), []string{"a", "b"})
+		},
+		"transform": func(p Provider, opts ...Option) {
+			s, _ := Transform("summarize", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), "input")
+		},
+		"extraction": func(p Provider, opts ...Option) {
+			s, _ := Extract[ExtractData]("data", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), "input")
+		},
+		"analyze": func(p Provider, opts ...Option) {
+			s, _ := Analyze[TestData]("data", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), TestData{})
+		},
+		"convert": func(p Provider, opts ...Option) {
+			s, _ := Convert[SimpleInput, SimpleOutput]("convert", p, opts...)
+			_, _ = s.Fire(ctx, NewSession(), SimpleInput{})
+		},
+	}
+
+	for name, fire := range fire {
+		t.Run(name, func(t *testing.T) {
+			seeded := &paramsProvider{MockProvider: NewMockProviderWithName("seeded")}
+			fire(seeded, WithSeed(42))
+			if len(seeded.params) == 0 || seeded.params[0].Seed == nil || *seeded.params[0].Seed != 42 {
+				t.Errorf("expected seed 42, got %+v", seeded.params)
+			}
+
+			unseeded := &paramsProvider{MockProvider: NewMockProviderWithName("unseeded")}
+			fire(unseeded)
+			if len(unseeded.params) == 0 || unseeded.params[0].Seed != nil {
+				t.Errorf("expected no seed, got %+v", unseeded.params)
+			}
+		})
+	}
+
+	t.Run("zero seed", func(t *testing.T) {
+		provider := &paramsProvider{MockProvider: NewMockProviderWithName("seeded")}
+		synapse, _ := Binary("question", provider, WithSeed(0))
+		_, _ = synapse.Fire(ctx, NewSession(), "input")
+		if len(provider.params) != 1 || provider.params[0].Seed == nil || *provider.params[0].Seed != 0 {
+			t.Errorf("expected seed 0, got %+v", provider.params)
+		}
+	})
+}
+
+func TestSamplingOptions(t *testing.T) {
+	provider := &paramsProvider{MockProvider: NewMockProviderWithName("sampling")}
+	synapse, _ := Transform("summarize", provider, WithMaxTokens(256), WithTopP(0.9), WithStopSequences("END", "STOP"))
+	_, _ = synapse.Fire(context.Background(), NewSession(), "input")
+
+	if len(provider.params) != 1 {
+		t.Fatalf("expected one call, got %d", len(provider.params))
+	}
+	params := provider.params[0]
+	if params.MaxTokens != 256 || params.TopP != 0.9 || len(params.Stop) != 2 || params.Stop[1] != "STOP" {
+		t.Errorf("unexpected params: %+v", params)
+	}
+
+	unset := &paramsProvider{MockProvider: NewMockProviderWithName("sampling")}
+	plain, _ := Transform("summarize", unset)
+	_, _ = plain.Fire(context.Background(), NewSession(), "input")
+	if params := unset.params[0]; params.MaxTokens != 0 || params.TopP != 0 || params.Stop != nil {
+		t.Errorf("expected no sampling params, got %+v", params)
+	}
+}
+
+// truncatingProvider answers with a response cut off at the token limit.
+type truncatingProvider struct {
+	calls int
+}
+
+func (p *truncatingProvider) Call(context.Context, []Message, float32) (*ProviderResponse, error) {
+	p.calls++
+	return &ProviderResponse{
+		Content:   `{"decision": true, "confidence": 0.9, "reaso`,
+		Usage:     TokenUsage{Prompt: 100, Completion: 16, Total: 116},
+		Truncated: true,
+	}, nil
+}
+
+func (*truncatingProvider) Name() string { return "truncating" }
+
+func TestResponseTruncated(t *testing.T) {
+	provider := &truncatingProvider{}
+	synapse, _ := Binary("question", provider, WithMaxTokens(16), WithRetry(3))
+
+	_, err := synapse.Fire(context.Background(), NewSession(), "input")
+	var truncated *ResponseTruncatedError
+	if !errors.As(err, &truncated) || !errors.Is(err, ErrResponseTruncated) {
+		t.Fatalf("expected ResponseTruncatedError, got %v", err)
+	}
+	if errors.Is(err, ErrParseFailed) {
+		t.Errorf("expected no parse failure, got %v", err)
+	}
+	if truncated.Provider != "truncating" || truncated.MaxTokens != 16 || truncated.CompletionTokens != 16 {
+		t.Errorf("unexpected error: %+v", truncated)
+	}
+	if provider.calls != 1 {
+		t.Errorf("expected truncation not to be retried, got %d calls", provider.calls)
+	}
+}
@@ -1,19 +1,34 @@
 package zyn
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/zoobzio/pipz"
 	"github.com/zoobzio/sentinel"
 )
 
+// Identity for the strict schema option.
+var strictSchemaID = pipz.NewIdentity("zyn:strict-schema", "Enforces strict response schemas")
+
+// strictSchemas caches strict conversions keyed by the original schema JSON.
+var strictSchemas sync.Map
+
 // mapTypeRegex matches map[K]V patterns and captures the value type.
 var mapTypeRegex = regexp.MustCompile(`^map\[[^\]]+\](.+)$`)
 
 // JSON Schema type constants.
 const (
+	jsonTypeNull    = "null"
 	jsonTypeObject  = "object"
 	jsonTypeString  = "string"
 	jsonTypeInteger = "integer"
@@ -36,8 +51,13 @@ type JSONSchema struct {
 	Items                   *JSONSchema            `json:"-"` // for arrays
 	Required                []string               `json:"-"` // handled in MarshalJSON
 	Description             string                 `json:"-"` // optional field description
+	Format                  string                 `json:"-"` // optional format hint, e.g. "date-time"
 	AdditionalProperties    *JSONSchema            `json:"-"` // for map value types
 	DisallowAdditionalProps bool                   `json:"-"` // when true, additionalProperties: false
+	Nullable                bool                   `json:"-"` // when true, type also admits null
+	Enum                    []any                  `json:"-"` // allowed values, when restricted
+	Ref                     string                 `json:"-"` // $ref to a shared definition or "#" for the root
+	Defs                    map[string]*JSONSchema `json:"-"` // $defs for shared and recursive types
 }
 
 // MarshalJSON implements custom JSON marshaling to handle the additionalProperties
@@ -45,8 +65,23 @@ type JSONSchema struct {
 func (s *JSONSchema) MarshalJSON() ([]byte, error) {
 	m := make(map[string]any)
 
+	if s.Ref != "" {
+		// A reference carries no type, so nullability needs anyOf
+		if s.Nullable {
+			m["anyOf"] = []map[string]string{{"$ref": s.Ref}, {"type": jsonTypeNull}}
+		} else {
+			m["$ref"] = s.Ref
+		}
+	}
+	if len(s.Defs) > 0 {
+		m["$defs"] = s.Defs
+	}
 	if s.Type != "" {
-		m["type"] = s.Type
+		if s.Nullable {
+			m["type"] = []string{s.Type, jsonTypeNull}
+		} else {
+			m["type"] = s.Type
+		}
 	}
 	if len(s.Properties) > 0 {
 		m["properties"] = s.Properties
@@ -60,6 +95,12 @@ func (s *JSONSchema) MarshalJSON() ([]byte, error) {
 	if s.Description != "" {
 		m["description"] = s.Description
 	}
+	if s.Format != "" {
+		m["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		m["enum"] = s.Enum
+	}
 
 	// Handle additionalProperties: either false or a schema
 	if s.DisallowAdditionalProps {
@@ -71,15 +112,522 @@ func (s *JSONSchema) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m)
 }
 
+// UnmarshalJSON implements custom JSON unmarshaling, accepting additionalProperties
+// as either a boolean or a schema and type as either a string or a [type, "null"] pair.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type                 json.RawMessage        `json:"type"`
+		Properties           map[string]*JSONSchema `json:"properties"`
+		Items                *JSONSchema            `json:"items"`
+		Required             []string               `json:"required"`
+		Description          string                 `json:"description"`
+		Format               string                 `json:"format"`
+		Enum                 []any                  `json:"enum"`
+		AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+		Ref                  string                 `json:"$ref"`
+		Defs                 map[string]*JSONSchema `json:"$defs"`
+		AnyOf                []*JSONSchema          `json:"anyOf"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = JSONSchema{
+		Properties:  raw.Properties,
+		Items:       raw.Items,
+		Required:    raw.Required,
+		Description: raw.Description,
+		Format:      raw.Format,
+		Enum:        raw.Enum,
+		Ref:         raw.Ref,
+		Defs:        raw.Defs,
+	}
+
+	// anyOf is only produced for nullable references
+	for _, option := range raw.AnyOf {
+		switch {
+		case option.Ref != "":
+			s.Ref = option.Ref
+		case option.Type == jsonTypeNull:
+			s.Nullable = true
+		}
+	}
+
+	if len(raw.Type) > 0 {
+		var single string
+		var multiple []string
+		switch {
+		case json.Unmarshal(raw.Type, &single) == nil:
+			s.Type = single
+		case json.Unmarshal(raw.Type, &multiple) == nil:
+			for _, t := range multiple {
+				if t == jsonTypeNull {
+					s.Nullable = true
+				} else if s.Type == "" {
+					s.Type = t
+				}
+			}
+		default:
+			return fmt.Errorf("invalid schema type: %s", raw.Type)
+		}
+	}
+
+	if len(raw.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(raw.AdditionalProperties, &allowed); err == nil {
+			s.DisallowAdditionalProps = !allowed
+		} else {
+			s.AdditionalProperties = &JSONSchema{}
+			if err := json.Unmarshal(raw.AdditionalProperties, s.AdditionalProperties); err != nil {
+				return fmt.Errorf("invalid additionalProperties: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Strict returns a copy of the schema in strict mode, as required by provider
+// structured-output features. Every object with declared properties, including
+// shared definitions, disallows additional properties and lists all of its
+// properties as required; properties that were optional become nullable instead.
+func (s *JSONSchema) Strict() *JSONSchema {
+	if s == nil {
+		return nil
+	}
+
+	strict := *s
+	strict.Items = s.Items.Strict()
+	strict.AdditionalProperties = s.AdditionalProperties.Strict()
+
+	if len(s.Defs) > 0 {
+		strict.Defs = make(map[string]*JSONSchema, len(s.Defs))
+		for name, def := range s.Defs {
+			strict.Defs[name] = def.Strict()
+		}
+	}
+
+	if len(s.Properties) > 0 {
+		strict.Properties = make(map[string]*JSONSchema, len(s.Properties))
+		strict.Required = make([]string, 0, len(s.Properties))
+		for name, prop := range s.Properties {
+			propStrict := prop.Strict()
+			if !slices.Contains(s.Required, name) {
+				propStrict.Nullable = true
+				if len(propStrict.Enum) > 0 && !slices.Contains(propStrict.Enum, nil) {
+					propStrict.Enum = append(slices.Clip(propStrict.Enum), nil)
+				}
+			}
+			strict.Properties[name] = propStrict
+			strict.Required = append(strict.Required, name)
+		}
+		sort.Strings(strict.Required)
+		strict.DisallowAdditionalProps = true
+		strict.AdditionalProperties = nil
+	}
+
+	return &strict
+}
+
+// WithStrictSchema enables strict schema mode.
+// The schema sent to the LLM disallows additional properties on every object
+// and marks every property as required (optional ones become nullable), and
+// responses containing top-level fields outside the schema are rejected.
+// A prompt whose schema cannot be parsed is left as it is, without strict
+// checks, since responses could not be checked against it.
+func WithStrictSchema() Option {
+	return withRequest(strictSchemaID, func(req *SynapseRequest) {
+		strict, err := strictSchemaJSON(req.Prompt.Schema)
+		if err != nil {
+			return
+		}
+		req.Prompt.Schema = strict
+		req.Prompt.Strict = true
+	})
+}
+
+// strictSchemaJSON converts a JSON schema string to its strict form.
+// Conversions are cached since the same schema is converted on every call.
+func strictSchemaJSON(schema string) (string, error) {
+	if cached, ok := strictSchemas.Load(schema); ok {
+		if strict, isString := cached.(string); isString {
+			return strict, nil
+		}
+	}
+
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+
+	jsonBytes, err := json.MarshalIndent(parsed.Strict(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate strict schema: %w", err)
+	}
+
+	strictSchemas.Store(schema, string(jsonBytes))
+	return string(jsonBytes), nil
+}
+
+// StrictSchema converts a JSON schema to the strict form required by provider
+// structured-output features, such as OpenAI's json_schema response format.
+// It reports false for schemas that strict mode cannot express: maps and
+// other objects without declared properties, and values of unknown type.
+func StrictSchema(schema string) (string, bool) {
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil || !parsed.Strict().strictCompatible() {
+		return "", false
+	}
+	strict, err := strictSchemaJSON(schema)
+	return strict, err == nil
+}
+
+// strictCompatible reports whether every value in the schema has a known
+// type and every object declares its properties.
+func (s *JSONSchema) strictCompatible() bool {
+	for _, def := range s.Defs {
+		if !def.strictCompatible() {
+			return false
+		}
+	}
+	if s.Ref != "" {
+		return true
+	}
+	if s.Nullable && len(s.Enum) > 0 && !slices.Contains(s.Enum, nil) {
+		return false
+	}
+
+	switch s.Type {
+	case jsonTypeObject:
+		if len(s.Properties) == 0 || s.AdditionalProperties != nil {
+			return false
+		}
+		for _, prop := range s.Properties {
+			if !prop.strictCompatible() {
+				return false
+			}
+		}
+		return true
+	case jsonTypeArray:
+		return s.Items != nil && s.Items.strictCompatible()
+	case "":
+		return false
+	default:
+		return true
+	}
+}
+
+// unexpectedFields returns the sorted top-level keys of a JSON object response
+// that are not declared as properties in the schema.
+func unexpectedFields(body []byte, schema string) ([]string, error) {
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	var unexpected []string
+	for name := range fields {
+		if _, declared := parsed.Properties[name]; !declared {
+			unexpected = append(unexpected, name)
+		}
+	}
+	sort.Strings(unexpected)
+	return unexpected, nil
+}
+
+// parseSchema parses a JSON Schema document describing a JSON object, for
+// validating responses with validateSchemaValue. Only the keywords that
+// validateSchemaValue enforces are checked: type, properties, required,
+// additionalProperties, items, enum, anyOf, and $ref into $defs. Other
+// keywords, such as description or minimum, are allowed and ignored.
+func parseSchema(schema string) (map[string]any, error) {
+	var root map[string]any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("invalid schema: must be a JSON object")
+	}
+	if err := checkSchemaNode(root, root, "schema"); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if types := schemaTypes(root); !slices.Equal(types, []string{jsonTypeObject}) {
+		return nil, fmt.Errorf("invalid schema: root type must be %q, got %v", jsonTypeObject, types)
+	}
+	return root, nil
+}
+
+// checkSchemaNode checks that the supported keywords of a schema node, and
+// of the nodes nested in it, are well formed and that references resolve.
+func checkSchemaNode(root, node map[string]any, path string) error {
+	if raw, ok := node["type"]; ok {
+		types := schemaTypes(node)
+		if len(types) == 0 {
+			return fmt.Errorf("%s: type must be a string or an array of strings, got %v", path, raw)
+		}
+		for _, t := range types {
+			switch t {
+			case jsonTypeNull, jsonTypeObject, jsonTypeString, jsonTypeInteger, jsonTypeNumber, jsonTypeBoolean, jsonTypeArray:
+			default:
+				return fmt.Errorf("%s: unknown type %q", path, t)
+			}
+		}
+	}
+
+	if raw, ok := node["$ref"]; ok {
+		ref, isString := raw.(string)
+		if !isString || resolveRef(root, ref) == nil {
+			return fmt.Errorf("%s: unresolvable $ref %v", path, raw)
+		}
+	}
+
+	if raw, ok := node["required"]; ok {
+		required, isArray := raw.([]any)
+		if !isArray {
+			return fmt.Errorf("%s: required must be an array", path)
+		}
+		for _, name := range required {
+			if _, isString := name.(string); !isString {
+				return fmt.Errorf("%s: required entries must be strings, got %v", path, name)
+			}
+		}
+	}
+
+	if raw, ok := node["enum"]; ok {
+		if values, isArray := raw.([]any); !isArray || len(values) == 0 {
+			return fmt.Errorf("%s: enum must be a non-empty array", path)
+		}
+	}
+
+	for _, keyword := range []string{"properties", "$defs"} {
+		raw, ok := node[keyword]
+		if !ok {
+			continue
+		}
+		children, isObject := raw.(map[string]any)
+		if !isObject {
+			return fmt.Errorf("%s: %s must be an object", path, keyword)
+		}
+		for name, child := range children {
+			if err := checkSchemaChild(root, child, path+"."+keyword+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if raw, ok := node["items"]; ok {
+		if err := checkSchemaChild(root, raw, path+".items"); err != nil {
+			return err
+		}
+	}
+
+	if raw, ok := node["additionalProperties"]; ok {
+		if _, isBool := raw.(bool); !isBool {
+			if err := checkSchemaChild(root, raw, path+".additionalProperties"); err != nil {
+				return err
+			}
+		}
+	}
+
+	if raw, ok := node["anyOf"]; ok {
+		options, isArray := raw.([]any)
+		if !isArray || len(options) == 0 {
+			return fmt.Errorf("%s: anyOf must be a non-empty array", path)
+		}
+		for i, option := range options {
+			if err := checkSchemaChild(root, option, fmt.Sprintf("%s.anyOf[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSchemaChild checks a nested value that must be a schema node.
+func checkSchemaChild(root map[string]any, raw any, path string) error {
+	child, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: must be a schema object", path)
+	}
+	return checkSchemaNode(root, child, path)
+}
+
+// validateSchemaValue checks a decoded JSON value against a schema node
+// parsed by parseSchema. Optional properties given as null are treated as
+// absent, since strict mode asks for them to be sent that way.
+func validateSchemaValue(root, node map[string]any, value any, path string) error {
+	if ref, ok := node["$ref"].(string); ok {
+		node = resolveRef(root, ref)
+	}
+
+	if raw, ok := node["anyOf"].([]any); ok {
+		matched := false
+		for _, option := range raw {
+			if child, isNode := option.(map[string]any); isNode && validateSchemaValue(root, child, value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: %s does not match any allowed schema", path, jsonValueType(value))
+		}
+	}
+
+	if types := schemaTypes(node); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return matchesJSONType(value, t) }) {
+		return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonValueType(value))
+	}
+
+	if raw, ok := node["enum"].([]any); ok {
+		if !slices.ContainsFunc(raw, func(allowed any) bool { return reflect.DeepEqual(allowed, value) }) {
+			return fmt.Errorf("%s: value %v is not one of %v", path, value, raw)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateSchemaObject(root, node, v, path)
+	case []any:
+		items := schemaChild(node, "items")
+		if items == nil {
+			return nil
+		}
+		for i, item := range v {
+			if err := validateSchemaValue(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaObject checks the required, properties, and
+// additionalProperties keywords against an object value.
+func validateSchemaObject(root, node map[string]any, object map[string]any, path string) error {
+	var required []string
+	if raw, ok := node["required"].([]any); ok {
+		for _, name := range raw {
+			if s, isString := name.(string); isString {
+				required = append(required, s)
+			}
+		}
+	}
+	for _, name := range required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, name)
+		}
+	}
+
+	properties := schemaChild(node, "properties")
+	additional, additionalAllowed := node["additionalProperties"].(bool)
+
+	// Sorted so the first reported error is deterministic
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := object[name]
+		childPath := path + "." + name
+		if property := schemaChild(properties, name); property != nil {
+			if value == nil && !slices.Contains(required, name) {
+				continue
+			}
+			if err := validateSchemaValue(root, property, value, childPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if additionalAllowed && !additional {
+			return fmt.Errorf("%s: unexpected property", childPath)
+		}
+		if schema := schemaChild(node, "additionalProperties"); schema != nil {
+			if err := validateSchemaValue(root, schema, value, childPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes returns the types a schema node admits, from a "type" given as
+// a string or an array of strings. Returns nil when the type is unrestricted
+// or malformed.
+func schemaTypes(node map[string]any) []string {
+	switch t := node["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, entry := range t {
+			s, ok := entry.(string)
+			if !ok {
+				return nil
+			}
+			types = append(types, s)
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+// matchesJSONType reports whether a decoded JSON value has the given type.
+// Integers are numbers without a fractional part.
+func matchesJSONType(value any, t string) bool {
+	if n, ok := value.(float64); ok && t == jsonTypeInteger {
+		return n == math.Trunc(n)
+	}
+	actual := jsonValueType(value)
+	return actual == t || (actual == jsonTypeInteger && t == jsonTypeNumber)
+}
+
+// jsonValueType returns the JSON type of a decoded JSON value, reporting
+// whole numbers as integers.
+func jsonValueType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return jsonTypeNull
+	case bool:
+		return jsonTypeBoolean
+	case float64:
+		if v == math.Trunc(v) {
+			return jsonTypeInteger
+		}
+		return jsonTypeNumber
+	case string:
+		return jsonTypeString
+	case []any:
+		return jsonTypeArray
+	case map[string]any:
+		return jsonTypeObject
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
 // generateJSONSchema creates a proper JSON Schema from a Go type using sentinel.
 // Uses Scan to recursively register nested types, then builds a complete schema.
+// Struct types used more than once, or recursively, are emitted once under
+// "$defs" and referenced with "$ref"; self-references to the root type use "#".
 // Returns an error if the schema cannot be marshaled to JSON.
 func generateJSONSchema[T any]() (string, error) {
 	// Use Scan to recursively register all nested types in the same module
 	metadata := sentinel.Scan[T]()
 
 	// Build the schema recursively
-	schema := buildSchemaFromMetadata(metadata, true)
+	builder := newSchemaBuilder(metadata)
+	schema := builder.build(metadata, true)
+	if len(builder.defs) > 0 {
+		schema.Defs = builder.defs
+	}
 
 	// Marshal to JSON
 	jsonBytes, err := json.MarshalIndent(schema, "", "  ")
@@ -90,9 +638,84 @@ func generateJSONSchema[T any]() (string, error) {
 	return string(jsonBytes), nil
 }
 
-// buildSchemaFromMetadata constructs a JSONSchema from sentinel metadata.
+// schemaBuilder tracks nested struct types while a schema is built so that
+// shared and recursive types are defined once instead of inlined repeatedly.
+type schemaBuilder struct {
+	root   string                 // FQDN of the root type
+	shared map[string]bool        // FQDNs emitted under $defs
+	names  map[string]string      // FQDN -> definition name
+	defs   map[string]*JSONSchema // definition name -> schema
+}
+
+// newSchemaBuilder counts struct type usages reachable from the root type and
+// decides which of them become shared definitions.
+func newSchemaBuilder(root sentinel.Metadata) *schemaBuilder {
+	b := &schemaBuilder{
+		root:   root.FQDN,
+		shared: make(map[string]bool),
+		names:  make(map[string]string),
+		defs:   make(map[string]*JSONSchema),
+	}
+
+	counts := make(map[string]int)
+	recursive := make(map[string]bool)
+	countTypeUses(root, map[string]bool{root.FQDN: true}, counts, recursive)
+
+	// Sorted for deterministic definition names
+	fqdns := make([]string, 0, len(counts))
+	for fqdn := range counts {
+		fqdns = append(fqdns, fqdn)
+	}
+	sort.Strings(fqdns)
+
+	taken := make(map[string]bool)
+	for _, fqdn := range fqdns {
+		if fqdn == b.root || (counts[fqdn] < 2 && !recursive[fqdn]) {
+			continue
+		}
+		nested, found := sentinel.Lookup(fqdn)
+		if !found {
+			continue
+		}
+		name := nested.TypeName
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%s%d", nested.TypeName, i)
+		}
+		taken[name] = true
+		b.shared[fqdn] = true
+		b.names[fqdn] = name
+	}
+
+	return b
+}
+
+// countTypeUses counts how many times each struct type is referenced in the
+// schema of metadata. Each type's own fields are only walked on first use,
+// matching how often they would appear once shared types are defined once.
+// Types referenced from within themselves are recorded as recursive.
+func countTypeUses(metadata sentinel.Metadata, path map[string]bool, counts map[string]int, recursive map[string]bool) {
+	for _, rel := range metadata.Relationships {
+		counts[rel.To]++
+		if path[rel.To] {
+			recursive[rel.To] = true
+			continue
+		}
+		if counts[rel.To] > 1 {
+			continue
+		}
+		nested, found := sentinel.Lookup(rel.To)
+		if !found {
+			continue
+		}
+		path[rel.To] = true
+		countTypeUses(nested, path, counts, recursive)
+		delete(path, rel.To)
+	}
+}
+
+// build constructs a JSONSchema from sentinel metadata.
 // isRoot indicates if this is the top-level schema (affects additionalProperties handling).
-func buildSchemaFromMetadata(metadata sentinel.Metadata, isRoot bool) *JSONSchema {
+func (b *schemaBuilder) build(metadata sentinel.Metadata, isRoot bool) *JSONSchema {
 	schema := &JSONSchema{
 		Type:                    jsonTypeObject,
 		Properties:              make(map[string]*JSONSchema),
@@ -112,12 +735,15 @@ func buildSchemaFromMetadata(metadata sentinel.Metadata, isRoot bool) *JSONSchem
 		}
 
 		// Build schema for this field
-		fieldSchema := buildFieldSchema(field, relMap)
+		fieldSchema := b.buildField(field, relMap)
 
 		// Add description if available
 		if desc, ok := field.Tags["desc"]; ok {
 			fieldSchema.Description = desc
 		}
+		if format := structTag(metadata, field, "format"); format != "" {
+			fieldSchema.Format = format
+		}
 
 		schema.Properties[jsonName] = fieldSchema
 
@@ -130,55 +756,158 @@ func buildSchemaFromMetadata(metadata sentinel.Metadata, isRoot bool) *JSONSchem
 	return schema
 }
 
-// buildFieldSchema creates a JSONSchema for a single field.
-func buildFieldSchema(field sentinel.FieldMetadata, relMap map[string]sentinel.TypeRelationship) *JSONSchema {
-	typeStr := field.Type
+// buildField creates a JSONSchema for a single field. Pointer fields are
+// nullable.
+func (b *schemaBuilder) buildField(field sentinel.FieldMetadata, relMap map[string]sentinel.TypeRelationship) *JSONSchema {
+	// Types that marshal themselves are described by their JSON form
+	if field.ReflectType != nil {
+		if schema, ok := marshaledSchema(field.ReflectType); ok {
+			return schema
+		}
+	}
 
-	// Check if this field has a relationship (nested struct)
+	var schema *JSONSchema
 	if rel, hasRel := relMap[field.Name]; hasRel {
-		return buildRelationshipSchema(rel)
+		// Nested struct
+		schema = b.buildRelationship(rel)
+	} else {
+		// Primitive types and containers
+		schema = buildPrimitiveSchema(field.Type)
+	}
+	if field.ReflectType != nil && field.ReflectType.Kind() == reflect.Pointer {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+// Types whose JSON form is not their Go structure.
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// marshaledSchema returns the schema of t when its values, or the values it
+// points to or contains, marshal themselves: time.Time is an RFC 3339
+// date-time string, json.RawMessage is any JSON value, and other types are
+// strings when they implement encoding.TextMarshaler or marshal to a JSON
+// string with json.Marshaler. Pointers are nullable. Reports false for any
+// other type, including json.Marshaler implementations producing objects,
+// which are described by their fields.
+func marshaledSchema(t reflect.Type) (*JSONSchema, bool) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema, ok := marshaledSchema(t.Elem())
+		if ok {
+			schema.Nullable = true
+		}
+		return schema, ok
+	case reflect.Slice, reflect.Array:
+		if t == rawMessageType {
+			return &JSONSchema{}, true
+		}
+		if items, ok := marshaledSchema(t.Elem()); ok {
+			return &JSONSchema{Type: jsonTypeArray, Items: items}, true
+		}
+	case reflect.Map:
+		if values, ok := marshaledSchema(t.Elem()); ok {
+			return &JSONSchema{Type: jsonTypeObject, AdditionalProperties: values}, true
+		}
 	}
 
-	// Handle primitive types and containers
-	return buildPrimitiveSchema(typeStr)
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: jsonTypeString, Format: "date-time"}, true
+	case implements(t, jsonMarshalerType):
+		if marshalsToString(t) {
+			return &JSONSchema{Type: jsonTypeString}, true
+		}
+		return nil, false
+	case implements(t, textMarshalerType):
+		return &JSONSchema{Type: jsonTypeString}, true
+	default:
+		return nil, false
+	}
+}
+
+// implements reports whether t or a pointer to t implements iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// marshalsToString reports whether the zero value of t marshals to a JSON
+// string. A MarshalJSON method that fails or panics on the zero value
+// reports true, since a type marshaling itself is most often a string.
+func marshalsToString(t reflect.Type) (isString bool) {
+	defer func() {
+		if recover() != nil {
+			isString = true
+		}
+	}()
+	data, err := json.Marshal(reflect.New(t).Interface())
+	return err != nil || (len(data) > 0 && data[0] == '"')
+}
+
+// structTag returns the value of the named struct tag on field of the type
+// described by metadata, or "" if it has none.
+func structTag(metadata sentinel.Metadata, field sentinel.FieldMetadata, name string) string {
+	t := metadata.ReflectType
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || len(field.Index) == 0 {
+		return ""
+	}
+	return t.FieldByIndex(field.Index).Tag.Get(name)
 }
 
-// buildRelationshipSchema handles fields that reference other structs.
-func buildRelationshipSchema(rel sentinel.TypeRelationship) *JSONSchema {
+// buildRelationship handles fields that reference other structs.
+func (b *schemaBuilder) buildRelationship(rel sentinel.TypeRelationship) *JSONSchema {
 	switch rel.Kind {
 	case sentinel.RelationshipReference, sentinel.RelationshipEmbedding:
-		// Direct struct reference - look up and recurse
-		if nested, found := sentinel.Lookup(rel.To); found {
-			return buildSchemaFromMetadata(nested, false)
-		}
-		// Fallback if not found
-		return &JSONSchema{Type: jsonTypeObject}
+		// Direct struct reference
+		return b.buildStruct(rel.To)
 
 	case sentinel.RelationshipCollection:
 		// Array of structs
-		schema := &JSONSchema{Type: jsonTypeArray}
-		if nested, found := sentinel.Lookup(rel.To); found {
-			schema.Items = buildSchemaFromMetadata(nested, false)
-		} else {
-			schema.Items = &JSONSchema{Type: jsonTypeObject}
-		}
-		return schema
+		return &JSONSchema{Type: jsonTypeArray, Items: b.buildStruct(rel.To)}
 
 	case sentinel.RelationshipMap:
 		// Map with struct values
-		schema := &JSONSchema{Type: jsonTypeObject}
-		if nested, found := sentinel.Lookup(rel.To); found {
-			schema.AdditionalProperties = buildSchemaFromMetadata(nested, false)
-		} else {
-			schema.AdditionalProperties = &JSONSchema{Type: jsonTypeObject}
-		}
-		return schema
+		return &JSONSchema{Type: jsonTypeObject, AdditionalProperties: b.buildStruct(rel.To)}
 
 	default:
 		return &JSONSchema{Type: jsonTypeObject}
 	}
 }
 
+// buildStruct returns the schema for a nested struct type: a reference for
+// the root or shared types, or the inlined object schema otherwise.
+func (b *schemaBuilder) buildStruct(fqdn string) *JSONSchema {
+	if fqdn == b.root {
+		return &JSONSchema{Ref: "#"}
+	}
+
+	nested, found := sentinel.Lookup(fqdn)
+	if !found {
+		// Fallback if not found
+		return &JSONSchema{Type: jsonTypeObject}
+	}
+
+	if !b.shared[fqdn] {
+		return b.build(nested, false)
+	}
+
+	name := b.names[fqdn]
+	if _, defined := b.defs[name]; !defined {
+		// Reserve the name before building so recursive references terminate
+		b.defs[name] = nil
+		b.defs[name] = b.build(nested, false)
+	}
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
+
 // buildPrimitiveSchema handles primitive types and primitive containers.
 func buildPrimitiveSchema(typeStr string) *JSONSchema {
 	// Check for array types: []T
@@ -2,7 +2,11 @@ package zyn
 
 import (
 	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/zoobzio/sentinel"
 )
@@ -380,3 +384,738 @@ func (f FieldMetadataInput) toSentinelField() sentinel.FieldMetadata {
 		Tags: tags,
 	}
 }
+
+// assertJSONEqual compares two JSON documents structurally.
+func assertJSONEqual(t *testing.T, expected, actual string) {
+	t.Helper()
+	var want, got any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(actual), &got); err != nil {
+		t.Fatalf("invalid actual JSON: %v", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("JSON mismatch:\nexpected: %s\nactual:   %s", expected, actual)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	t.Run("optional fields become required and nullable", func(t *testing.T) {
+		schema, err := generateJSONSchema[ComplexStruct]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		strict, err := strictSchemaJSON(schema)
+		if err != nil {
+			t.Fatalf("failed to convert schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"list": {"type": "array", "items": {"type": "string"}},
+				"optional": {"type": ["string", "null"]},
+				"required": {"type": "string"}
+			},
+			"required": ["list", "optional", "required"]
+		}`, strict)
+	})
+
+	t.Run("nested objects disallow additional properties", func(t *testing.T) {
+		schema, err := generateJSONSchema[NestedOuter]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		strict, err := strictSchemaJSON(schema)
+		if err != nil {
+			t.Fatalf("failed to convert schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"inner": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {
+						"label": {"type": "string"},
+						"value": {"type": "number"}
+					},
+					"required": ["label", "value"]
+				},
+				"name": {"type": "string"}
+			},
+			"required": ["inner", "name"]
+		}`, strict)
+	})
+
+	t.Run("default schema is unchanged", func(t *testing.T) {
+		schema, err := generateJSONSchema[NestedOuter]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"inner": {
+					"type": "object",
+					"properties": {
+						"label": {"type": "string"},
+						"value": {"type": "number"}
+					},
+					"required": ["value", "label"]
+				},
+				"name": {"type": "string"}
+			},
+			"required": ["name", "inner"]
+		}`, schema)
+	})
+
+	t.Run("map values keep their schema", func(t *testing.T) {
+		schema, err := generateJSONSchema[WithMapOfStructs]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		strict, err := strictSchemaJSON(schema)
+		if err != nil {
+			t.Fatalf("failed to convert schema: %v", err)
+		}
+
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(strict), &parsed); err != nil {
+			t.Fatalf("strict schema does not round-trip: %v", err)
+		}
+		records := parsed.Properties["records"]
+		if records.DisallowAdditionalProps {
+			t.Error("map schema should not disallow additional properties")
+		}
+		if records.AdditionalProperties == nil || !records.AdditionalProperties.DisallowAdditionalProps {
+			t.Error("map value objects should be strict")
+		}
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		if _, err := strictSchemaJSON("not json"); err == nil {
+			t.Error("expected error for invalid schema")
+		}
+	})
+}
+
+func TestJSONSchema_UnmarshalJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		schema, err := generateJSONSchema[SentimentResponse]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+			t.Fatalf("failed to unmarshal schema: %v", err)
+		}
+		remarshaled, err := json.Marshal(&parsed)
+		if err != nil {
+			t.Fatalf("failed to marshal schema: %v", err)
+		}
+		assertJSONEqual(t, schema, string(remarshaled))
+	})
+
+	t.Run("nullable type", func(t *testing.T) {
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(`{"type": ["null", "integer"]}`), &parsed); err != nil {
+			t.Fatalf("failed to unmarshal schema: %v", err)
+		}
+		if parsed.Type != "integer" || !parsed.Nullable {
+			t.Errorf("expected nullable integer, got %+v", parsed)
+		}
+	})
+
+	t.Run("additionalProperties true", func(t *testing.T) {
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(`{"type": "object", "additionalProperties": true}`), &parsed); err != nil {
+			t.Fatalf("failed to unmarshal schema: %v", err)
+		}
+		if parsed.DisallowAdditionalProps || parsed.AdditionalProperties != nil {
+			t.Errorf("expected additional properties to be allowed, got %+v", parsed)
+		}
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		schema := `{"type": "object", "properties": {"tier": {"type": "string", "enum": ["gold", "silver"]}}}`
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+			t.Fatalf("failed to unmarshal schema: %v", err)
+		}
+		strict, err := json.Marshal(parsed.Strict())
+		if err != nil {
+			t.Fatalf("failed to marshal schema: %v", err)
+		}
+		if !strings.Contains(string(strict), `"enum":["gold","silver",null]`) {
+			t.Errorf("expected enum to survive strict conversion, got %s", strict)
+		}
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		var parsed JSONSchema
+		if err := json.Unmarshal([]byte(`{"type": 5}`), &parsed); err == nil {
+			t.Error("expected error for invalid type")
+		}
+	})
+}
+
+// Test structs for shared and recursive nesting.
+type SchemaLineItem struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+type SchemaInvoice struct {
+	Ordered []SchemaLineItem `json:"ordered"`
+	Shipped []SchemaLineItem `json:"shipped"`
+	Primary SchemaLineItem   `json:"primary"`
+}
+
+type SchemaOutlineSection struct {
+	Title    string                 `json:"title"`
+	Children []SchemaOutlineSection `json:"children,omitempty"`
+}
+
+type SchemaOutline struct {
+	Title    string                 `json:"title"`
+	Sections []SchemaOutlineSection `json:"sections"`
+}
+
+type SchemaTreeNode struct {
+	Label    string           `json:"label"`
+	Children []SchemaTreeNode `json:"children"`
+}
+
+func TestGenerateJSONSchema_Defs(t *testing.T) {
+	t.Run("shared type is defined once", func(t *testing.T) {
+		schema, err := generateJSONSchema[SchemaInvoice]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"ordered": {"type": "array", "items": {"$ref": "#/$defs/SchemaLineItem"}},
+				"shipped": {"type": "array", "items": {"$ref": "#/$defs/SchemaLineItem"}},
+				"primary": {"$ref": "#/$defs/SchemaLineItem"}
+			},
+			"required": ["ordered", "shipped", "primary"],
+			"additionalProperties": false,
+			"$defs": {
+				"SchemaLineItem": {
+					"type": "object",
+					"properties": {
+						"sku": {"type": "string"},
+						"quantity": {"type": "integer"}
+					},
+					"required": ["sku", "quantity"]
+				}
+			}
+		}`, schema)
+	})
+
+	t.Run("recursive type references its definition", func(t *testing.T) {
+		schema, err := generateJSONSchema[SchemaOutline]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"title": {"type": "string"},
+				"sections": {"type": "array", "items": {"$ref": "#/$defs/SchemaOutlineSection"}}
+			},
+			"required": ["title", "sections"],
+			"additionalProperties": false,
+			"$defs": {
+				"SchemaOutlineSection": {
+					"type": "object",
+					"properties": {
+						"title": {"type": "string"},
+						"children": {"type": "array", "items": {"$ref": "#/$defs/SchemaOutlineSection"}}
+					},
+					"required": ["title"]
+				}
+			}
+		}`, schema)
+	})
+
+	t.Run("recursive root references itself", func(t *testing.T) {
+		schema, err := generateJSONSchema[SchemaTreeNode]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"label": {"type": "string"},
+				"children": {"type": "array", "items": {"$ref": "#"}}
+			},
+			"required": ["label", "children"],
+			"additionalProperties": false
+		}`, schema)
+	})
+
+	t.Run("single use stays inline", func(t *testing.T) {
+		schema, err := generateJSONSchema[NestedOuter]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		if strings.Contains(schema, "$defs") || strings.Contains(schema, "$ref") {
+			t.Errorf("expected no definitions for single-use type, got %s", schema)
+		}
+	})
+
+	t.Run("strict round-trip", func(t *testing.T) {
+		schema, err := generateJSONSchema[SchemaOutline]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+		strict, err := strictSchemaJSON(schema)
+		if err != nil {
+			t.Fatalf("failed to convert schema: %v", err)
+		}
+
+		assertJSONEqual(t, `{
+			"type": "object",
+			"properties": {
+				"title": {"type": "string"},
+				"sections": {"type": "array", "items": {"$ref": "#/$defs/SchemaOutlineSection"}}
+			},
+			"required": ["sections", "title"],
+			"additionalProperties": false,
+			"$defs": {
+				"SchemaOutlineSection": {
+					"type": "object",
+					"properties": {
+						"title": {"type": "string"},
+						"children": {"type": ["array", "null"], "items": {"$ref": "#/$defs/SchemaOutlineSection"}}
+					},
+					"required": ["children", "title"],
+					"additionalProperties": false
+				}
+			}
+		}`, strict)
+	})
+
+	t.Run("nullable reference uses anyOf", func(t *testing.T) {
+		schema := &JSONSchema{Ref: "#/$defs/Item", Nullable: true}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		assertJSONEqual(t, `{"anyOf": [{"$ref": "#/$defs/Item"}, {"type": "null"}]}`, string(data))
+
+		var parsed JSONSchema
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Fatalf("failed to unmarshal: %v", err)
+		}
+		if parsed.Ref != "#/$defs/Item" || !parsed.Nullable {
+			t.Errorf("expected nullable ref, got %+v", parsed)
+		}
+	})
+
+	t.Run("prompt size regression", func(t *testing.T) {
+		schema, err := generateJSONSchema[SchemaInvoice]()
+		if err != nil {
+			t.Fatalf("failed to generate schema: %v", err)
+		}
+
+		// The line item object must not be repeated per field
+		if count := strings.Count(schema, `"quantity": {`); count != 1 {
+			t.Errorf("expected line item properties once, found %d times", count)
+		}
+
+		prompt := &Prompt{Task: "Extract invoice", Input: "text", Schema: schema}
+		if size := len(prompt.Render()); size > 800 {
+			t.Errorf("rendered prompt grew to %d bytes, expected at most 800", size)
+		}
+	})
+}
+
+// dynamicOrderSchema is a runtime schema with nested objects, arrays, enums,
+// and a shared definition.
+const dynamicOrderSchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "integer"},
+		"status": {"type": "string", "enum": ["open", "shipped", "cancelled"]},
+		"customer": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"email": {"type": ["string", "null"]}
+			},
+			"required": ["name"],
+			"additionalProperties": false
+		},
+		"lines": {"type": "array", "items": {"$ref": "#/$defs/line"}},
+		"note": {"type": "string", "description": "free text"}
+	},
+	"required": ["id", "status", "customer", "lines"],
+	"$defs": {
+		"line": {
+			"type": "object",
+			"properties": {
+				"sku": {"type": "string"},
+				"quantity": {"type": "integer"},
+				"price": {"type": "number"}
+			},
+			"required": ["sku", "quantity"]
+		}
+	}
+}`
+
+func TestParseSchema(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		root, err := parseSchema(dynamicOrderSchema)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if schemaType(root) != jsonTypeObject {
+			t.Errorf("expected object root, got %v", root["type"])
+		}
+	})
+
+	invalid := map[string]string{
+		"not json":            `{"type": "object"`,
+		"null":                `null`,
+		"array root":          `{"type": "array", "items": {"type": "string"}}`,
+		"untyped root":        `{"properties": {"a": {"type": "string"}}}`,
+		"unknown type":        `{"type": "object", "properties": {"a": {"type": "text"}}}`,
+		"malformed type":      `{"type": "object", "properties": {"a": {"type": 5}}}`,
+		"unresolvable ref":    `{"type": "object", "properties": {"a": {"$ref": "#/$defs/missing"}}}`,
+		"external ref":        `{"type": "object", "properties": {"a": {"$ref": "other.json"}}}`,
+		"required not array":  `{"type": "object", "required": "a"}`,
+		"required not string": `{"type": "object", "required": [1]}`,
+		"empty enum":          `{"type": "object", "properties": {"a": {"enum": []}}}`,
+		"tuple items":         `{"type": "object", "properties": {"a": {"type": "array", "items": [{"type": "string"}]}}}`,
+		"properties not map":  `{"type": "object", "properties": []}`,
+		"bad anyOf":           `{"type": "object", "properties": {"a": {"anyOf": {}}}}`,
+	}
+	for name, schema := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseSchema(schema); err == nil {
+				t.Errorf("expected error for %s", schema)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaValue(t *testing.T) {
+	root, err := parseSchema(dynamicOrderSchema)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			value: `{"id": 7, "status": "open", "customer": {"name": "Ada", "email": "ada@example.com"}, "lines": [{"sku": "A1", "quantity": 2, "price": 9.5}]}`,
+		},
+		{
+			name:  "null optional property",
+			value: `{"id": 7, "status": "open", "customer": {"name": "Ada"}, "lines": [], "note": null}`,
+		},
+		{
+			name:  "nullable type",
+			value: `{"id": 7, "status": "open", "customer": {"name": "Ada", "email": null}, "lines": []}`,
+		},
+		{
+			name:  "extra property where allowed",
+			value: `{"id": 7, "status": "open", "customer": {"name": "Ada"}, "lines": [{"sku": "A1", "quantity": 1, "gift": true}]}`,
+		},
+		{
+			name:    "missing required",
+			value:   `{"id": 7, "status": "open", "lines": []}`,
+			wantErr: `output: missing required property "customer"`,
+		},
+		{
+			name:    "missing nested required",
+			value:   `{"id": 7, "status": "open", "customer": {"email": null}, "lines": []}`,
+			wantErr: `output.customer: missing required property "name"`,
+		},
+		{
+			name:    "enum violation",
+			value:   `{"id": 7, "status": "lost", "customer": {"name": "Ada"}, "lines": []}`,
+			wantErr: "output.status: value lost is not one of [open shipped cancelled]",
+		},
+		{
+			name:    "fractional integer",
+			value:   `{"id": 7.5, "status": "open", "customer": {"name": "Ada"}, "lines": []}`,
+			wantErr: "output.id: expected integer, got number",
+		},
+		{
+			name:    "wrong type in array item",
+			value:   `{"id": 7, "status": "open", "customer": {"name": "Ada"}, "lines": [{"sku": "A1", "quantity": 1}, {"sku": 2, "quantity": 1}]}`,
+			wantErr: "output.lines[1].sku: expected string, got integer",
+		},
+		{
+			name:    "array expected",
+			value:   `{"id": 7, "status": "open", "customer": {"name": "Ada"}, "lines": {"sku": "A1"}}`,
+			wantErr: "output.lines: expected array, got object",
+		},
+		{
+			name:    "additional property disallowed",
+			value:   `{"id": 7, "status": "open", "customer": {"name": "Ada", "phone": "555"}, "lines": []}`,
+			wantErr: "output.customer.phone: unexpected property",
+		},
+		{
+			name:    "null required property",
+			value:   `{"id": null, "status": "open", "customer": {"name": "Ada"}, "lines": []}`,
+			wantErr: "output.id: expected integer, got null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("invalid test value: %v", err)
+			}
+			err := validateSchemaValue(root, root, value, "output")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaValue_AnyOfAndAdditionalSchema(t *testing.T) {
+	root, err := parseSchema(`{
+		"type": "object",
+		"properties": {
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"additionalProperties": {"type": "number"}
+	}`)
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	valid := []string{`{"id": "a", "x": 1.5}`, `{"id": 3}`}
+	for _, v := range valid {
+		var value any
+		_ = json.Unmarshal([]byte(v), &value)
+		if err := validateSchemaValue(root, root, value, "output"); err != nil {
+			t.Errorf("expected %s to be valid, got %v", v, err)
+		}
+	}
+
+	invalid := []string{`{"id": true}`, `{"id": 3, "x": "text"}`}
+	for _, v := range invalid {
+		var value any
+		_ = json.Unmarshal([]byte(v), &value)
+		if err := validateSchemaValue(root, root, value, "output"); err == nil {
+			t.Errorf("expected %s to be invalid", v)
+		}
+	}
+}
+
+func TestStrictSchemaCompatibility(t *testing.T) {
+	t.Run("structs convert", func(t *testing.T) {
+		schema, _ := generateJSONSchema[NestedOuter]()
+		strict, ok := StrictSchema(schema)
+		want, _ := strictSchemaJSON(schema)
+		if !ok || strict != want {
+			t.Errorf("expected strict schema, got %q (%t)", strict, ok)
+		}
+	})
+
+	t.Run("optional enums admit null", func(t *testing.T) {
+		strict, ok := StrictSchema(`{"type": "object", "properties": {"tone": {"type": "string", "enum": ["calm", "angry"]}}}`)
+		if !ok {
+			t.Fatal("expected optional enum to convert")
+		}
+		assertJSONEqual(t, `{
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {"tone": {"type": ["string", "null"], "enum": ["calm", "angry", null]}},
+			"required": ["tone"]
+		}`, strict)
+	})
+
+	t.Run("recursive types convert", func(t *testing.T) {
+		schema, _ := generateJSONSchema[SchemaTreeNode]()
+		if _, ok := StrictSchema(schema); !ok {
+			t.Error("expected recursive schema to convert")
+		}
+	})
+
+	incompatible := map[string]string{
+		"map":                 mustSchema[WithMap](t),
+		"map of structs":      mustSchema[WithMapOfStructs](t),
+		"untyped value":       `{"type": "object", "properties": {"value": {}}, "required": ["value"]}`,
+		"untyped object":      `{"type": "object", "properties": {"value": {"type": "object"}}, "required": ["value"]}`,
+		"array without items": `{"type": "object", "properties": {"values": {"type": "array"}}, "required": ["values"]}`,
+		"enum without null":   `{"type": "object", "properties": {"tone": {"type": ["string", "null"], "enum": ["calm", "angry"]}}, "required": ["tone"]}`,
+		"invalid":             `{"type": `,
+	}
+	for name, schema := range incompatible {
+		t.Run(name, func(t *testing.T) {
+			if strict, ok := StrictSchema(schema); ok {
+				t.Errorf("expected no strict form, got %s", strict)
+			}
+		})
+	}
+}
+
+// mustSchema generates the JSON schema of T.
+func mustSchema[T any](t *testing.T) string {
+	t.Helper()
+	schema, err := generateJSONSchema[T]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+	return schema
+}
+
+// metricWindow is a duration marshaled as a string such as "1m30s".
+type metricWindow time.Duration
+
+func (w metricWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(w).String())
+}
+
+func (w *metricWindow) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(text)
+	*w = metricWindow(parsed)
+	return err
+}
+
+// metricLevel is a level marshaled as text.
+type metricLevel int
+
+func (l metricLevel) MarshalText() ([]byte, error) { return []byte(strconv.Itoa(int(l))), nil }
+
+func (l *metricLevel) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(string(text))
+	*l = metricLevel(n)
+	return err
+}
+
+// metricPoint marshals itself as an object of its own fields.
+type metricPoint struct {
+	X int `json:"x"`
+}
+
+func (p metricPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"x": p.X})
+}
+
+// processedMetric has fields whose JSON form differs from their Go structure.
+type processedMetric struct {
+	Name     string          `json:"name"`
+	At       time.Time       `json:"at"`
+	Seen     *time.Time      `json:"seen"`
+	Day      time.Time       `json:"day" format:"date"`
+	Count    *int            `json:"count"`
+	Raw      json.RawMessage `json:"raw"`
+	Window   metricWindow    `json:"window" format:"duration"`
+	Windows  []metricWindow  `json:"windows"`
+	Level    metricLevel     `json:"level"`
+	Point    metricPoint     `json:"point"`
+	Previous *NestedInner    `json:"previous"`
+}
+
+func (processedMetric) Validate() error { return nil }
+
+func TestGenerateJSONSchema_MarshaledTypes(t *testing.T) {
+	schema, err := generateJSONSchema[processedMetric]()
+	if err != nil {
+		t.Fatalf("failed to generate schema: %v", err)
+	}
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	props := parsed["properties"].(map[string]any)
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"name", `{"type":"string"}`},
+		{"at", `{"format":"date-time","type":"string"}`},
+		{"seen", `{"format":"date-time","type":["string","null"]}`},
+		{"day", `{"format":"date","type":"string"}`},
+		{"count", `{"type":["integer","null"]}`},
+		{"raw", `{}`},
+		{"window", `{"format":"duration","type":"string"}`},
+		{"windows", `{"items":{"type":"string"},"type":"array"}`},
+		{"level", `{"type":"string"}`},
+		{"point", `{"properties":{"x":{"type":"integer"}},"required":["x"],"type":"object"}`},
+		{"previous", `{"properties":{"label":{"type":"string"},"value":{"type":"number"}},"required":["value","label"],"type":["object","null"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, _ := json.Marshal(props[tt.field])
+			if string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	// The format survives a round trip, as in strict mode
+	var roundTrip JSONSchema
+	if err := json.Unmarshal([]byte(schema), &roundTrip); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roundTrip.Properties["at"].Format != "date-time" || !roundTrip.Properties["seen"].Nullable {
+		t.Errorf("expected format and nullability kept, got %+v", roundTrip.Properties["seen"])
+	}
+}
+
+func TestMarshaledSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want string // "" when the type is described by its structure
+	}{
+		{"time", reflect.TypeFor[time.Time](), `{"format":"date-time","type":"string"}`},
+		{"time map", reflect.TypeFor[map[string]time.Time](), `{"additionalProperties":{"format":"date-time","type":"string"},"type":"object"}`},
+		{"raw message", reflect.TypeFor[json.RawMessage](), `{}`},
+		{"text marshaler", reflect.TypeFor[metricLevel](), `{"type":"string"}`},
+		{"string marshaler", reflect.TypeFor[*metricWindow](), `{"type":["string","null"]}`},
+		{"object marshaler", reflect.TypeFor[metricPoint](), ""},
+		{"int", reflect.TypeFor[int](), ""},
+		{"struct", reflect.TypeFor[NestedInner](), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, ok := marshaledSchema(tt.typ)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("expected no schema, got %+v", schema)
+				}
+				return
+			}
+			got, _ := json.Marshal(schema)
+			if !ok || string(got) != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultSegmentTolerance is the fraction of the input's words segments may
+// leave out, or add, unless SegmentInput.Tolerance says otherwise.
+const DefaultSegmentTolerance = 0.05
+
+// SegmentInput contains rich input structure for segmentation.
+type SegmentInput struct {
+	Text         string  // The text to segment
+	MaxSegments  int     // Optional hint for the most segments wanted
+	TargetLength int     // Optional hint for the length of each segment, in words
+	Tolerance    float64 // Fraction of words segments may drop or add; 0 uses DefaultSegmentTolerance
+	Context      string  // Optional context, such as what the segments are for
+	Temperature  float32 // LLM temperature setting for this specific request
+}
+
+// TextSegment is a contiguous, self-contained part of a text. It is not
+// named Segment, which is the synapse constructor.
+type TextSegment struct {
+	Title     string `json:"title"`      // Short title of the segment
+	Text      string `json:"text"`       // The segment's text, copied from the input
+	StartHint string `json:"start_hint"` // The segment's opening words, for locating it in the input
+}
+
+// SegmentResponse contains the response from a segment synapse.
+type SegmentResponse struct {
+	Segments   []TextSegment `json:"segments"`   // The segments, in input order
+	Confidence float64       `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string      `json:"reasoning"`  // Explanation of the boundaries
+}
+
+// Validate checks if the response is valid. Coverage of the input is
+// checked by the synapse.
+func (r SegmentResponse) Validate() error {
+	if len(r.Segments) == 0 {
+		return fmt.Errorf("segments required but empty")
+	}
+	for i, segment := range r.Segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			return fmt.Errorf("segment %d: text required but empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkCoverage compares the words of the segments with the words of the
+// input, ignoring case and whitespace, and rejects segments that drop or add
+// more than tolerance of the input's words.
+func checkCoverage(text string, tolerance float64, response SegmentResponse) error {
+	input := wordCounts(text)
+	segments := make(map[string]int)
+	for _, segment := range response.Segments {
+		for word, count := range wordCounts(segment.Text) {
+			segments[word] += count
+		}
+	}
+
+	var total, covered, added int
+	for word, count := range input {
+		total += count
+		covered += min(count, segments[word])
+	}
+	for word, count := range segments {
+		added += max(count-input[word], 0)
+	}
+
+	allowed := tolerance * float64(total)
+	if missing := total - covered; float64(missing) > allowed {
+		return fmt.Errorf("segments leave out %d of %d input words, at most %.0f allowed", missing, total, allowed)
+	}
+	if float64(added) > allowed {
+		return fmt.Errorf("segments add %d words not in the input, at most %.0f allowed", added, allowed)
+	}
+	return nil
+}
+
+// wordCounts counts the lowercased words of text.
+func wordCounts(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		counts[word]++
+	}
+	return counts
+}
+
+// SegmentSynapse splits long text into semantically coherent segments.
+type SegmentSynapse struct {
+	instruction string
+	defaults    SegmentInput
+	base        *Base[SegmentInput, SegmentResponse]
+}
+
+// NewSegment creates a new segment synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewSegment(instruction string, provider Provider, opts ...Option) (*SegmentSynapse, error) {
+	synapse := &SegmentSynapse{instruction: instruction}
+
+	base, err := NewSynapse(SynapseConfig[SegmentInput, SegmentResponse]{
+		Type:        "segment",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (s *SegmentSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return s.base.GetPipeline()
+}
+
+// WithDefaults creates a new Segment with default input values.
+// These are merged with user input at execution time.
+func (s *SegmentSynapse) WithDefaults(defaults SegmentInput) *SegmentSynapse {
+	s.defaults = defaults
+	return s
+}
+
+// Fire segments text and returns the segments.
+func (s *SegmentSynapse) Fire(ctx context.Context, session *Session, text string) ([]TextSegment, error) {
+	response, err := s.FireWithInput(ctx, session, SegmentInput{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return response.Segments, nil
+}
+
+// FireResult segments text and returns the segments in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (s *SegmentSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[[]TextSegment], error) {
+	result, err := s.execute(ctx, session, SegmentInput{Text: text})
+	if err != nil {
+		return withValue[SegmentResponse, []TextSegment](result, nil), err
+	}
+	return withValue(result, result.Value.Segments), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (s *SegmentSynapse) FireWithInput(ctx context.Context, session *Session, input SegmentInput) (SegmentResponse, error) {
+	result, err := s.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a SegmentResponse.
+func (s *SegmentSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := s.FireWithInput(ctx, session, SegmentInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and segments the text, rejecting
+// segments that do not cover it.
+func (s *SegmentSynapse) execute(ctx context.Context, session *Session, input SegmentInput) (Result[SegmentResponse], error) {
+	merged := s.mergeInputs(input)
+	if strings.TrimSpace(merged.Text) == "" {
+		return Result[SegmentResponse]{Provider: s.base.service.providerName},
+			fmt.Errorf("%w: segment synapse needs text", ErrInvalidPrompt)
+	}
+	if merged.Tolerance < 0 || merged.Tolerance >= 1 {
+		return Result[SegmentResponse]{Provider: s.base.service.providerName},
+			fmt.Errorf("%w: tolerance must be at least 0 and below 1, got %f", ErrInvalidPrompt, merged.Tolerance)
+	}
+	tolerance := merged.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultSegmentTolerance
+	}
+
+	prompt := s.buildPrompt(merged)
+	prompt.Schema = s.base.Schema()
+	return s.base.service.executeChecked(ctx, session, prompt, merged.Temperature, func(response SegmentResponse) error {
+		return checkCoverage(merged.Text, tolerance, response)
+	})
+}
+
+// mergeInputs combines defaults with user input.
+func (s *SegmentSynapse) mergeInputs(input SegmentInput) SegmentInput {
+	merged := s.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if input.MaxSegments != 0 {
+		merged.MaxSegments = input.MaxSegments
+	}
+	if input.TargetLength != 0 {
+		merged.TargetLength = input.TargetLength
+	}
+	if input.Tolerance != 0 {
+		merged.Tolerance = input.Tolerance
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (s *SegmentSynapse) buildPrompt(input SegmentInput) *Prompt {
+	constraints := []string{
+		"segments: contiguous, in input order, together covering the whole text with no overlap; include the final paragraph",
+		"text: copied exactly from the input, never summarized or rewritten",
+		"title: a few words naming the segment's subject",
+		"start_hint: the segment's first few words, exactly as in the input",
+	}
+	if input.MaxSegments > 0 {
+		constraints = append(constraints, fmt.Sprintf("segments: at most %d", input.MaxSegments))
+	}
+	if input.TargetLength > 0 {
+		constraints = append(constraints, fmt.Sprintf("segments: about %d words each, without splitting a topic to get there", input.TargetLength))
+	}
+	constraints = append(constraints,
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining where the boundaries fall",
+	)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Split the text into segments: %s", s.instruction),
+		Input:       input.Text,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Segment creates a new segment synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	chunker, err := Segment("one segment per topic", provider, WithValidationRetry(2))
+//	chunker.WithDefaults(SegmentInput{TargetLength: 300})
+//	segments, err := chunker.Fire(ctx, session, document)
+func Segment(instruction string, provider Provider, opts ...Option) (*SegmentSynapse, error) {
+	return NewSegment(instruction, provider, opts...)
+}
@@ -0,0 +1,239 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// segmentText is the input of the segment tests: three paragraphs of 10, 10
+// and 11 words.
+const segmentText = "Go was designed at Google to improve programming productivity there.\n\n" +
+	"Its concurrency model uses goroutines and channels instead of threads.\n\n" +
+	"The toolchain includes formatting, testing, and profiling out of the box."
+
+// topicSegments is a segment response covering segmentText, one segment
+// per paragraph.
+const topicSegments = `{
+	"segments": [
+		{"title": "Origins", "text": "Go was designed at Google to improve programming productivity there.", "start_hint": "Go was"},
+		{"title": "Concurrency", "text": "Its concurrency model uses goroutines and channels instead of threads.", "start_hint": "Its concurrency"},
+		{"title": "Tooling", "text": "The toolchain includes formatting, testing, and profiling out of the box.", "start_hint": "The toolchain"}
+	],
+	"confidence": 0.8,
+	"reasoning": ["one per topic"]
+}`
+
+// partialSegments is a segment response that leaves out the last paragraph
+// of segmentText.
+const partialSegments = `{
+	"segments": [
+		{"title": "Origins", "text": "Go was designed at Google to improve programming productivity there.", "start_hint": "Go was"},
+		{"title": "Concurrency", "text": "Its concurrency model uses goroutines and channels instead of threads.", "start_hint": "Its concurrency"}
+	],
+	"confidence": 0.8,
+	"reasoning": ["one per topic"]
+}`
+
+func TestSegmentResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := SegmentResponse{
+			Segments:   []TextSegment{{Title: "a", Text: "text"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_segments", func(t *testing.T) {
+		r := SegmentResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no segments")
+		}
+	})
+
+	t.Run("empty_segment", func(t *testing.T) {
+		r := SegmentResponse{
+			Segments:   []TextSegment{{Title: "a", Text: "text"}, {Title: "b", Text: " \n"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a segment without text")
+		}
+	})
+
+	t.Run("confidence_too_low", func(t *testing.T) {
+		r := SegmentResponse{
+			Segments:   []TextSegment{{Title: "a", Text: "text"}},
+			Confidence: -1,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for negative confidence")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := SegmentResponse{
+			Segments:   []TextSegment{{Title: "a", Text: "text"}},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestSegmentSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return topicSegments, nil
+	})
+	chunker, err := Segment("one segment per topic", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	segments, err := chunker.Fire(context.Background(), NewSession(), segmentText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segments) != 3 || segments[2].StartHint != "The toolchain" {
+		t.Errorf("unexpected segments: %+v", segments)
+	}
+	for _, want := range []string{"one segment per topic", "include the final paragraph"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "at most") || strings.Contains(prompt, "words each") {
+		t.Errorf("expected no size hints without MaxSegments or TargetLength, got %s", prompt)
+	}
+}
+
+func TestSegmentSynapse_Coverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		tolerance float64
+		expected  string
+	}{
+		{"dropped last paragraph", partialSegments, 0, "segments leave out 11 of 31 input words"},
+		{"repeated paragraph", `{
+			"segments": [
+				{"title": "Origins", "text": "Go was designed at Google to improve programming productivity there.", "start_hint": "Go was"},
+				{"title": "Concurrency", "text": "Its concurrency model uses goroutines and channels instead of threads.", "start_hint": "Its concurrency"},
+				{"title": "Tooling", "text": "The toolchain includes formatting, testing, and profiling out of the box.", "start_hint": "The toolchain"},
+				{"title": "Origins", "text": "Go was designed at Google to improve programming productivity there.", "start_hint": "Go was"}
+			],
+			"confidence": 0.8,
+			"reasoning": ["one per topic"]
+		}`, 0, "segments add 10 words not in the input"},
+		{"within a wide tolerance", partialSegments, 0.5, ""},
+		{"rewrapped and recased", `{
+			"segments": [
+				{"title": "Origins", "text": "GO WAS DESIGNED AT GOOGLE TO IMPROVE PROGRAMMING PRODUCTIVITY THERE.", "start_hint": "GO WAS"},
+				{"title": "The rest", "text": "Its\nconcurrency\nmodel\nuses\ngoroutines\nand\nchannels\ninstead\nof\nthreads.\n\nThe\ntoolchain\nincludes\nformatting,\ntesting,\nand\nprofiling\nout\nof\nthe\nbox.", "start_hint": "Its concurrency"}
+			],
+			"confidence": 0.8,
+			"reasoning": ["one per topic"]
+		}`, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunker, _ := Segment("by topic", NewMockProviderWithResponse(tt.response))
+
+			_, err := chunker.FireWithInput(context.Background(), NewSession(), SegmentInput{Text: segmentText, Tolerance: tt.tolerance})
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSegmentSynapse_Hints(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return topicSegments, nil
+	})
+	chunker, _ := Segment("by topic", provider)
+	chunker.WithDefaults(SegmentInput{MaxSegments: 4, TargetLength: 200})
+
+	if _, err := chunker.Fire(context.Background(), NewSession(), segmentText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"segments: at most 4", "about 200 words each"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestSegmentSynapse_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input SegmentInput
+	}{
+		{"no text", SegmentInput{Text: "  "}},
+		{"negative tolerance", SegmentInput{Text: segmentText, Tolerance: -0.1}},
+		{"tolerance of one", SegmentInput{Text: segmentText, Tolerance: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunker, _ := Segment("by topic", NewMockProviderWithName("mock"))
+
+			_, err := chunker.FireWithInput(context.Background(), NewSession(), tt.input)
+			if !errors.Is(err, ErrInvalidPrompt) {
+				t.Errorf("expected ErrInvalidPrompt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSegmentSynapse_ValidationRetry(t *testing.T) {
+	var prompts []string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompts = append(prompts, p)
+		if len(prompts) == 1 {
+			return partialSegments, nil
+		}
+		return topicSegments, nil
+	})
+	chunker, _ := Segment("by topic", provider, WithValidationRetry(2))
+
+	segments, err := chunker.Fire(context.Background(), NewSession(), segmentText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prompts) != 2 || len(segments) != 3 || !strings.Contains(prompts[1], "leave out 11 of 31") {
+		t.Errorf("expected a retry naming the dropped words, got %d calls and %d segments", len(prompts), len(segments))
+	}
+}
+
+func TestSegmentSynapse_Invoke(t *testing.T) {
+	chunker, _ := Segment("by topic", NewMockProviderWithResponse(topicSegments))
+
+	validator, err := chunker.Invoke(context.Background(), NewSession(), SynapseInput{Input: segmentText})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(SegmentResponse); !ok || len(response.Segments) != 3 {
+		t.Errorf("expected SegmentResponse, got %#v", validator)
+	}
+}
@@ -2,7 +2,10 @@ package zyn
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/zoobzio/pipz"
@@ -27,6 +30,7 @@ type SentimentInput struct {
 // SentimentResponse contains the sentiment analysis results.
 type SentimentResponse struct {
 	Overall    string            `json:"overall"`    // Primary sentiment: positive, negative, neutral, mixed
+	Intensity  float64           `json:"intensity"`  // Signed strength from -1.0 (most negative) to 1.0 (most positive)
 	Confidence float64           `json:"confidence"` // Confidence in overall sentiment
 	Scores     SentimentScores   `json:"scores"`     // Detailed sentiment scores
 	Aspects    map[string]string `json:"aspects"`    // Sentiment per aspect if requested
@@ -39,6 +43,9 @@ func (r SentimentResponse) Validate() error {
 	if r.Overall == "" {
 		return fmt.Errorf("overall sentiment required but empty")
 	}
+	if r.Intensity < -1 || r.Intensity > 1 {
+		return fmt.Errorf("intensity must be -1 to 1, got %f", r.Intensity)
+	}
 	if r.Confidence < 0 || r.Confidence > 1 {
 		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
 	}
@@ -78,6 +85,269 @@ func (s SentimentScores) Validate() error {
 	return nil
 }
 
+// maxTopEmotions is the number of emotions reported by AggregateSentiment.
+const maxTopEmotions = 5
+
+// AggregateSentiment summarizes a set of sentiment responses.
+type AggregateSentiment struct {
+	Count         int                // Number of responses aggregated
+	Distribution  map[string]float64 // Share of responses per overall sentiment
+	MeanScores    SentimentScores    // Mean of the detailed sentiment scores
+	MeanIntensity float64            // Mean of the signed intensities
+	TopEmotions   []string           // Most frequent emotions, most frequent first
+	TopAspects    map[string]string  // Most frequent sentiment per aspect
+}
+
+// AggregateSentiments computes the aggregate of responses client-side.
+// Ties between emotions and between aspect sentiments are broken alphabetically.
+func AggregateSentiments(responses []SentimentResponse) AggregateSentiment {
+	aggregate := AggregateSentiment{
+		Count:        len(responses),
+		Distribution: make(map[string]float64),
+		TopAspects:   make(map[string]string),
+	}
+	if len(responses) == 0 {
+		return aggregate
+	}
+
+	emotions := make(map[string]int)
+	aspects := make(map[string]map[string]int)
+	for _, response := range responses {
+		aggregate.Distribution[normalizeSentiment(response.Overall)]++
+		aggregate.MeanScores.Positive += response.Scores.Positive
+		aggregate.MeanScores.Negative += response.Scores.Negative
+		aggregate.MeanScores.Neutral += response.Scores.Neutral
+		aggregate.MeanIntensity += response.Intensity
+
+		for _, emotion := range response.Emotions {
+			emotions[strings.ToLower(strings.TrimSpace(emotion))]++
+		}
+		for aspect, sentiment := range response.Aspects {
+			if aspects[aspect] == nil {
+				aspects[aspect] = make(map[string]int)
+			}
+			aspects[aspect][normalizeSentiment(sentiment)]++
+		}
+	}
+
+	count := float64(len(responses))
+	for sentiment := range aggregate.Distribution {
+		aggregate.Distribution[sentiment] /= count
+	}
+	aggregate.MeanScores.Positive /= count
+	aggregate.MeanScores.Negative /= count
+	aggregate.MeanScores.Neutral /= count
+	aggregate.MeanIntensity /= count
+
+	aggregate.TopEmotions = mostFrequent(emotions)
+	if len(aggregate.TopEmotions) > maxTopEmotions {
+		aggregate.TopEmotions = aggregate.TopEmotions[:maxTopEmotions]
+	}
+	for aspect, sentiments := range aspects {
+		aggregate.TopAspects[aspect] = mostFrequent(sentiments)[0]
+	}
+
+	return aggregate
+}
+
+// mostFrequent returns the keys of counts ordered by count, then alphabetically.
+func mostFrequent(counts map[string]int) []string {
+	keys := slices.Sorted(maps.Keys(counts))
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return counts[b] - counts[a]
+	})
+	return keys
+}
+
+// emotionsProperty is the schema property holding the detected emotions.
+const emotionsProperty = "emotions"
+
+// defaultEmotionsConstraint describes emotions when no taxonomy is set.
+const defaultEmotionsConstraint = "emotions: standard emotion categories"
+
+// emotionTaxonomyPrefix starts the constraint carrying a taxonomy, followed
+// by the allowed emotions as a JSON array.
+const emotionTaxonomyPrefix = "emotions: only from "
+
+// sentimentSettings holds the settings of one sentiment call.
+type sentimentSettings struct {
+	emotions []string // Taxonomy requested by WithEmotionTaxonomy, or nil
+}
+
+// Identity for the emotion taxonomy option.
+var emotionTaxonomyID = pipz.NewIdentity("zyn:emotion-taxonomy", "Restricts emotions to a taxonomy")
+
+// WithEmotionTaxonomy restricts the emotions a sentiment synapse reports to
+// the given list, so responses aggregate cleanly. The emotions are listed in
+// the prompt and as an enum in the response schema, and compared
+// case-insensitively; responses return them as written in the list.
+//
+// By default, emotions outside the list are corrected to the nearest listed
+// emotion when they are a near miss, such as "Angry" for "anger", and dropped
+// otherwise. With WithStrictSchema, any emotion outside the list, other than
+// a change of case, rejects the response with an *UnknownEmotionError
+// (matching ErrUnknownEmotion and ErrInvalidResponse).
+// The option has no effect on other synapse types.
+//
+// Example:
+//
+//	synapse, _ := Sentiment("customer feedback", provider,
+//	    WithEmotionTaxonomy([]string{"joy", "anger", "fear", "sadness", "surprise"}))
+func WithEmotionTaxonomy(emotions []string) Option {
+	emotions = slices.Clone(emotions)
+	return withSettings(emotionTaxonomyID, func(req *SynapseRequest, settings *sentimentSettings) {
+		if len(emotions) == 0 || settings.emotions != nil {
+			return
+		}
+		settings.emotions = emotions
+		requestEmotionTaxonomy(req.Prompt, emotions)
+	})
+}
+
+// requestEmotionTaxonomy restricts a sentiment prompt's emotions to the
+// taxonomy in its constraints and schema.
+func requestEmotionTaxonomy(prompt *Prompt, emotions []string) {
+	encoded, err := json.Marshal(emotions)
+	if err != nil {
+		return
+	}
+	constraints := slices.Clone(prompt.Constraints)
+	constraint := emotionTaxonomyPrefix + string(encoded)
+	if i := slices.Index(constraints, defaultEmotionsConstraint); i >= 0 {
+		constraints[i] = constraint
+	} else {
+		constraints = append(constraints, constraint)
+	}
+	prompt.Constraints = constraints
+
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(prompt.Schema), &parsed); err != nil {
+		return
+	}
+	property, ok := parsed.Properties[emotionsProperty]
+	if !ok || property.Items == nil {
+		return
+	}
+	items := *property.Items
+	items.Enum = make([]any, len(emotions))
+	for i, emotion := range emotions {
+		items.Enum[i] = emotion
+	}
+	property.Items = &items
+	if schema, err := json.MarshalIndent(&parsed, "", "  "); err == nil {
+		prompt.Schema = string(schema)
+	}
+}
+
+// validateEmotions rejects emotions outside the call's taxonomy in strict
+// mode. Other prompts are corrected by normalizeEmotions instead.
+func validateEmotions(prompt *Prompt, settings *sentimentSettings, response SentimentResponse) error {
+	taxonomy := settings.emotions
+	if taxonomy == nil || !prompt.Strict {
+		return nil
+	}
+	for _, emotion := range response.Emotions {
+		if _, ok := matchEmotion(taxonomy, emotion, false); !ok {
+			return &UnknownEmotionError{Emotion: emotion, Allowed: taxonomy}
+		}
+	}
+	return nil
+}
+
+// normalizeEmotions maps the response's emotions onto the call's taxonomy,
+// correcting near misses and dropping the rest. Duplicates are removed.
+func normalizeEmotions(prompt *Prompt, settings *sentimentSettings, response SentimentResponse) SentimentResponse {
+	taxonomy := settings.emotions
+	if taxonomy == nil {
+		return response
+	}
+	normalized := make([]string, 0, len(response.Emotions))
+	for _, emotion := range response.Emotions {
+		if match, ok := matchEmotion(taxonomy, emotion, !prompt.Strict); ok && !slices.Contains(normalized, match) {
+			normalized = append(normalized, match)
+		}
+	}
+	response.Emotions = normalized
+	return response
+}
+
+// matchEmotion returns the taxonomy entry matching emotion case-insensitively
+// or, when nearest is set, the closest entry within a small edit distance.
+func matchEmotion(taxonomy []string, emotion string, nearest bool) (string, bool) {
+	emotion = strings.ToLower(strings.TrimSpace(emotion))
+	for _, allowed := range taxonomy {
+		if strings.ToLower(allowed) == emotion {
+			return allowed, true
+		}
+	}
+	if !nearest {
+		return "", false
+	}
+
+	// Allow one edit in short words and two in longer ones
+	best, bestDistance := "", 2
+	if len([]rune(emotion)) < 5 {
+		bestDistance = 1
+	}
+	found := false
+	for _, allowed := range taxonomy {
+		if distance := editDistance(strings.ToLower(allowed), emotion); distance <= bestDistance && (!found || distance < bestDistance) {
+			best, bestDistance, found = allowed, distance, true
+		}
+	}
+	return best, found
+}
+
+// editDistance returns the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+// normalizeIntensity re-derives the intensity from the scores when its sign
+// contradicts a positive or negative overall sentiment, such as a positive
+// label with an intensity of -0.8. The overall sentiment must be normalized.
+func normalizeIntensity(response SentimentResponse) SentimentResponse {
+	contradicts := (response.Overall == sentimentPositive && response.Intensity < 0) ||
+		(response.Overall == sentimentNegative && response.Intensity > 0)
+	if contradicts {
+		response.Intensity = response.Scores.Positive - response.Scores.Negative
+	}
+	return response
+}
+
+// finishSentiment normalizes the overall sentiment, the intensity, and the
+// emotions.
+func finishSentiment(prompt *Prompt, settings *sentimentSettings, response SentimentResponse) SentimentResponse {
+	response.Overall = normalizeSentiment(response.Overall)
+	response = normalizeIntensity(response)
+	return normalizeEmotions(prompt, settings, response)
+}
+
+// SentimentBatch contains the results of FireBatch.
+type SentimentBatch struct {
+	Responses []SentimentResponse // Per-text responses in input order; zero for failed texts
+	Errors    []ItemError         // Failed texts in index order
+	Aggregate AggregateSentiment  // Aggregate of the successful responses
+	Usage     TokenUsage          // Usage summed across all provider calls
+}
+
 // SentimentSynapse represents a sentiment analysis synapse.
 type SentimentSynapse struct {
 	analysisType string // What kind of sentiment to analyze
@@ -132,6 +402,16 @@ func (s *SentimentSynapse) Fire(ctx context.Context, session *Session, text stri
 	return response.Overall, nil
 }
 
+// FireResult executes sentiment analysis and returns the overall sentiment in
+// a Result envelope carrying the call's usage, timing, and request metadata.
+func (s *SentimentSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[string], error) {
+	result, err := s.execute(ctx, session, SentimentInput{Text: text})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Overall), nil
+}
+
 // FireWithDetails executes sentiment analysis and returns full details.
 func (s *SentimentSynapse) FireWithDetails(ctx context.Context, session *Session, text string) (SentimentResponse, error) {
 	input := SentimentInput{Text: text}
@@ -140,21 +420,70 @@ func (s *SentimentSynapse) FireWithDetails(ctx context.Context, session *Session
 
 // FireWithInput executes sentiment analysis with rich input structure.
 func (s *SentimentSynapse) FireWithInput(ctx context.Context, session *Session, input SentimentInput) (SentimentResponse, error) {
-	// Merge defaults with user input
-	merged := s.mergeInputs(input)
+	result, err := s.execute(ctx, session, input)
+	return result.Value, err
+}
 
-	// Build prompt
+// execute runs one sentiment call and normalizes the overall sentiment and
+// emotions to standard values.
+func (s *SentimentSynapse) execute(ctx context.Context, session *Session, input SentimentInput) (Result[SentimentResponse], error) {
+	merged := s.mergeInputs(input)
 	prompt := s.buildPrompt(merged)
-
-	// Execute through service with session (service handles temperature fallback)
-	response, err := s.service.Execute(ctx, session, prompt, merged.Temperature)
+	settings := &sentimentSettings{}
+	result, err := s.service.executeConfigured(ctx, session, prompt, settings, merged.Temperature, func(response SentimentResponse) error {
+		return validateEmotions(prompt, settings, response)
+	})
 	if err != nil {
-		return response, err
+		return result, err
 	}
+	return withValue(result, finishSentiment(prompt, settings, result.Value)), nil
+}
 
-	// Normalize the overall sentiment to standard values
-	response.Overall = normalizeSentiment(response.Overall)
+// FireBatch analyzes each text independently, with up to opts.Concurrency
+// calls in flight, and aggregates the successful responses without further
+// provider calls. Each text is analyzed in its own session.
+//
+// Failed texts are reported in the batch's Errors and do not stop the batch
+// unless opts.StopOnError is set, in which case the first failure is also
+// returned as the error alongside the results collected so far.
+func (s *SentimentSynapse) FireBatch(ctx context.Context, texts []string, opts BatchOptions) (*SentimentBatch, error) {
+	outcome := runBatch(ctx, texts, opts.Concurrency, opts.StopOnError, "item",
+		func(ctx context.Context, text string) (Result[SentimentResponse], error) {
+			result, err := s.execute(ctx, NewSession(), SentimentInput{Text: text})
+			if err != nil {
+				return withValue(result, SentimentResponse{}), err
+			}
+			return result, nil
+		})
+
+	batch := &SentimentBatch{
+		Responses: make([]SentimentResponse, len(texts)),
+		Errors:    outcome.errors,
+		Usage:     outcome.usage,
+	}
+	succeeded := make([]SentimentResponse, 0, len(texts))
+	for i, result := range outcome.results {
+		batch.Responses[i] = result.Value
+		if outcome.ok(i) {
+			succeeded = append(succeeded, result.Value)
+		}
+	}
+	batch.Aggregate = AggregateSentiments(succeeded)
+
+	return batch, outcome.err
+}
 
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a SentimentResponse.
+func (s *SentimentSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := s.FireWithInput(ctx, session, SentimentInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return response, nil
 }
 
@@ -191,8 +520,9 @@ func (s *SentimentSynapse) buildPrompt(input SentimentInput) *Prompt {
 	// Build constraints
 	prompt.Constraints = []string{
 		"overall: positive, negative, neutral, or mixed only",
+		"intensity: -1.0 (most negative) to 1.0 (most positive), near 0 for neutral, with the sign of overall",
 		"scores: sum to 1.0",
-		"emotions: standard emotion categories",
+		defaultEmotionsConstraint,
 		"confidence: 0.0 to 1.0",
 	}
 
@@ -228,10 +558,10 @@ func normalizeSentiment(sentiment string) string {
 // Example:
 //
 //	synapse, err := Sentiment("customer feedback", provider)
-//	sentiment, err := synapse.Fire(ctx, "This product exceeded my expectations!")
+//	sentiment, err := synapse.Fire(ctx, session, "This product exceeded my expectations!")
 //	// Returns: "positive"
 //
-//	details, err := synapse.FireWithDetails(ctx, text)
+//	details, err := synapse.FireWithDetails(ctx, session, text)
 //	// Returns full analysis with scores and emotions
 func Sentiment(analysisType string, provider Provider, opts ...Option) (*SentimentSynapse, error) {
 	return NewSentiment(analysisType, provider, opts...)
@@ -2,6 +2,11 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -225,7 +230,7 @@ func TestSentimentSynapse_Fire(t *testing.T) {
 
 func TestSentimentSynapse_FireWithDetails(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
-		provider := NewMockProviderWithResponse(`{"overall": "positive", "confidence": 0.95, "scores": {"positive": 0.9, "negative": 0.05, "neutral": 0.05}, "aspects": {"quality": "positive"}, "emotions": ["joy"], "reasoning": ["enthusiastic"]}`)
+		provider := NewMockProviderWithResponse(`{"overall": "positive", "intensity": 0.85, "confidence": 0.95, "scores": {"positive": 0.9, "negative": 0.05, "neutral": 0.05}, "aspects": {"quality": "positive"}, "emotions": ["joy"], "reasoning": ["enthusiastic"]}`)
 		synapse, err := NewSentiment("detailed sentiment", provider)
 		if err != nil {
 			t.Fatalf("failed to create synapse: %v", err)
@@ -242,6 +247,9 @@ func TestSentimentSynapse_FireWithDetails(t *testing.T) {
 		if response.Confidence != 0.95 {
 			t.Errorf("Expected confidence=0.95, got %f", response.Confidence)
 		}
+		if response.Intensity != 0.85 {
+			t.Errorf("Expected intensity=0.85, got %f", response.Intensity)
+		}
 		if response.Scores.Positive != 0.9 {
 			t.Errorf("Expected positive score=0.9, got %f", response.Scores.Positive)
 		}
@@ -269,6 +277,22 @@ func TestSentimentSynapse_FireWithDetails(t *testing.T) {
 		}
 	})
 
+	t.Run("contradictory intensity", func(t *testing.T) {
+		provider := NewMockProviderWithResponse(`{"overall": "Positive", "intensity": -0.8, "confidence": 0.9, "scores": {"positive": 0.7, "negative": 0.1, "neutral": 0.2}, "aspects": {}, "emotions": [], "reasoning": ["test"]}`)
+		synapse, err := NewSentiment("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "test")
+		if err != nil {
+			t.Fatalf("FireWithDetails failed: %v", err)
+		}
+		if !approxEqual(response.Intensity, 0.6) {
+			t.Errorf("Expected intensity re-derived from scores as 0.6, got %f", response.Intensity)
+		}
+	})
+
 	t.Run("chaining", func(t *testing.T) {
 		provider := NewMockProviderWithResponse(`{"overall": "neutral", "confidence": 0.9, "scores": {"positive": 0.3, "negative": 0.3, "neutral": 0.4}, "aspects": {}, "emotions": [], "reasoning": ["test"]}`)
 		synapse, err := NewSentiment("test", provider)
@@ -457,6 +481,9 @@ func TestSentimentSynapse_buildPrompt(t *testing.T) {
 		if prompt.Schema == "" {
 			t.Error("Expected schema to be set")
 		}
+		if !slices.ContainsFunc(prompt.Constraints, func(c string) bool { return strings.HasPrefix(c, "intensity: -1.0") }) {
+			t.Errorf("Expected intensity scale constraint, got %v", prompt.Constraints)
+		}
 	})
 
 	t.Run("reliability", func(t *testing.T) {
@@ -534,6 +561,29 @@ func TestNormalizeSentiment(t *testing.T) {
 			t.Error("Case normalization should work")
 		}
 	})
+
+	t.Run("intensity", func(t *testing.T) {
+		scores := SentimentScores{Positive: 0.2, Negative: 0.7, Neutral: 0.1}
+		tests := []struct {
+			overall   string
+			intensity float64
+			expected  float64
+		}{
+			{"POSITIVE", -0.8, -0.5},
+			{"negative", 0.4, -0.5},
+			{"Negative", -0.6, -0.6},
+			{"positive", 0, 0},
+			{"mixed", 0.3, 0.3},
+			{"neutral", -0.1, -0.1},
+		}
+
+		for _, tt := range tests {
+			response := finishSentiment(&Prompt{}, &sentimentSettings{}, SentimentResponse{Overall: tt.overall, Intensity: tt.intensity, Scores: scores})
+			if !approxEqual(response.Intensity, tt.expected) {
+				t.Errorf("intensity for %q at %f = %f, want %f", tt.overall, tt.intensity, response.Intensity, tt.expected)
+			}
+		}
+	})
 }
 
 func TestSentiment(t *testing.T) {
@@ -646,6 +696,21 @@ func TestSentimentResponse_Validate(t *testing.T) {
 		}
 	})
 
+	t.Run("intensity_out_of_range", func(t *testing.T) {
+		for _, intensity := range []float64{-1.2, 1.01} {
+			r := SentimentResponse{
+				Overall:    "positive",
+				Intensity:  intensity,
+				Confidence: 0.9,
+				Scores:     validScores,
+				Reasoning:  []string{"reason"},
+			}
+			if err := r.Validate(); err == nil {
+				t.Errorf("expected error for intensity %f", intensity)
+			}
+		}
+	})
+
 	t.Run("empty_reasoning", func(t *testing.T) {
 		r := SentimentResponse{
 			Overall:    "positive",
@@ -785,3 +850,225 @@ func TestSentimentScores_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestSentimentSynapse_FireBatch(t *testing.T) {
+	responses := map[string]string{
+		"love it":  `{"overall": "positive", "confidence": 0.9, "scores": {"positive": 0.8, "negative": 0.1, "neutral": 0.1}, "aspects": {"price": "positive", "support": "negative"}, "emotions": ["joy", "trust"], "reasoning": ["praise"]}`,
+		"great":    `{"overall": "POS", "confidence": 0.8, "scores": {"positive": 0.6, "negative": 0.1, "neutral": 0.3}, "aspects": {"price": "positive"}, "emotions": ["joy"], "reasoning": ["praise"]}`,
+		"terrible": `{"overall": "negative", "confidence": 0.9, "scores": {"positive": 0.1, "negative": 0.7, "neutral": 0.2}, "aspects": {"support": "negative"}, "emotions": ["anger", "joy"], "reasoning": ["complaint"]}`,
+		"broken":   `{"overall": "positive"}`,
+	}
+	provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		for text, response := range responses {
+			if strings.Contains(prompt, "Input: "+text+"\n") {
+				return response, nil
+			}
+		}
+		return "", fmt.Errorf("unexpected prompt")
+	})
+	synapse, err := Sentiment("reviews", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	t.Run("aggregate", func(t *testing.T) {
+		texts := []string{"love it", "broken", "great", "terrible"}
+		batch, err := synapse.FireBatch(context.Background(), texts, BatchOptions{Concurrency: 2})
+		if err != nil {
+			t.Fatalf("unexpected batch error: %v", err)
+		}
+
+		if len(batch.Errors) != 1 || batch.Errors[0].Index != 1 {
+			t.Fatalf("expected item error for index 1, got %+v", batch.Errors)
+		}
+		if batch.Responses[0].Overall != "positive" || batch.Responses[2].Overall != "positive" || batch.Responses[3].Overall != "negative" {
+			t.Errorf("expected normalized responses in input order, got %+v", batch.Responses)
+		}
+		if batch.Usage.Total != 600 {
+			t.Errorf("expected usage summed over 4 calls, got %+v", batch.Usage)
+		}
+
+		aggregate := batch.Aggregate
+		if aggregate.Count != 3 {
+			t.Errorf("expected 3 aggregated responses, got %d", aggregate.Count)
+		}
+		if !approxEqual(aggregate.Distribution["positive"], 2.0/3) || !approxEqual(aggregate.Distribution["negative"], 1.0/3) {
+			t.Errorf("unexpected distribution: %v", aggregate.Distribution)
+		}
+		if !approxEqual(aggregate.MeanScores.Positive, 0.5) || !approxEqual(aggregate.MeanScores.Negative, 0.3) || !approxEqual(aggregate.MeanScores.Neutral, 0.2) {
+			t.Errorf("unexpected mean scores: %+v", aggregate.MeanScores)
+		}
+		expectedEmotions := []string{"joy", "anger", "trust"}
+		if strings.Join(aggregate.TopEmotions, ",") != strings.Join(expectedEmotions, ",") {
+			t.Errorf("expected emotions %v, got %v", expectedEmotions, aggregate.TopEmotions)
+		}
+		if aggregate.TopAspects["price"] != "positive" || aggregate.TopAspects["support"] != "negative" {
+			t.Errorf("unexpected aspects: %v", aggregate.TopAspects)
+		}
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		texts := []string{"great", "broken", "terrible"}
+		batch, err := synapse.FireBatch(context.Background(), texts, BatchOptions{StopOnError: true})
+		if err == nil {
+			t.Fatal("expected batch error")
+		}
+		if batch.Aggregate.Count != 1 {
+			t.Errorf("expected only the first response aggregated, got %d", batch.Aggregate.Count)
+		}
+	})
+}
+
+func TestAggregateSentiments(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		aggregate := AggregateSentiments(nil)
+		if aggregate.Count != 0 || len(aggregate.Distribution) != 0 || aggregate.TopEmotions != nil {
+			t.Errorf("expected empty aggregate, got %+v", aggregate)
+		}
+	})
+
+	t.Run("limits emotions and breaks ties", func(t *testing.T) {
+		aggregate := AggregateSentiments([]SentimentResponse{
+			{Overall: "mixed", Emotions: []string{"g", "f", "e", "d", "c", "b", "a"}, Aspects: map[string]string{"x": "positive"}},
+			{Overall: "mixed", Emotions: []string{"Z "}, Aspects: map[string]string{"x": "negative"}},
+		})
+		expected := []string{"a", "b", "c", "d", "e"}
+		if strings.Join(aggregate.TopEmotions, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected emotions %v, got %v", expected, aggregate.TopEmotions)
+		}
+		if aggregate.TopAspects["x"] != "negative" {
+			t.Errorf("expected alphabetical tie-break, got %q", aggregate.TopAspects["x"])
+		}
+		if aggregate.Distribution["mixed"] != 1 {
+			t.Errorf("unexpected distribution: %v", aggregate.Distribution)
+		}
+	})
+
+	t.Run("mean intensity", func(t *testing.T) {
+		aggregate := AggregateSentiments([]SentimentResponse{
+			{Overall: "positive", Intensity: 0.9},
+			{Overall: "negative", Intensity: -0.3},
+		})
+		if !approxEqual(aggregate.MeanIntensity, 0.3) {
+			t.Errorf("expected mean intensity 0.3, got %f", aggregate.MeanIntensity)
+		}
+	})
+}
+
+// approxEqual compares floats with a tolerance for accumulated rounding.
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestWithEmotionTaxonomy(t *testing.T) {
+	taxonomy := []string{"joy", "anger", "fear", "sadness"}
+	respond := func(emotions string) Provider {
+		return NewMockProviderWithResponse(`{"overall": "negative", "confidence": 0.8,
+			"scores": {"positive": 0.1, "negative": 0.8, "neutral": 0.1}, "aspects": {},
+			"emotions": ` + emotions + `, "reasoning": ["tone"]}`)
+	}
+
+	t.Run("valid set", func(t *testing.T) {
+		synapse, _ := Sentiment("feedback", respond(`["anger", "fear"]`), WithEmotionTaxonomy(taxonomy))
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "text")
+		if err != nil || strings.Join(response.Emotions, ",") != "anger,fear" {
+			t.Errorf("expected emotions kept, got %v (%v)", response.Emotions, err)
+		}
+	})
+
+	t.Run("cased variants normalized", func(t *testing.T) {
+		for _, strict := range []bool{false, true} {
+			opts := []Option{WithEmotionTaxonomy(taxonomy)}
+			if strict {
+				opts = append(opts, WithStrictSchema())
+			}
+			synapse, _ := Sentiment("feedback", respond(`["ANGER", " Fear ", "anger"]`), opts...)
+			response, err := synapse.FireWithDetails(context.Background(), NewSession(), "text")
+			if err != nil || strings.Join(response.Emotions, ",") != "anger,fear" {
+				t.Errorf("strict=%v: expected normalized emotions, got %v (%v)", strict, response.Emotions, err)
+			}
+		}
+	})
+
+	t.Run("lenient corrects near misses", func(t *testing.T) {
+		synapse, _ := Sentiment("feedback", respond(`["angry", "sadnes", "happiness"]`), WithEmotionTaxonomy(taxonomy))
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "text")
+		if err != nil || strings.Join(response.Emotions, ",") != "anger,sadness" {
+			t.Errorf("expected near misses corrected and synonyms dropped, got %v (%v)", response.Emotions, err)
+		}
+	})
+
+	t.Run("strict rejects synonyms", func(t *testing.T) {
+		synapse, _ := Sentiment("feedback", respond(`["anger", "happiness"]`),
+			WithEmotionTaxonomy(taxonomy), WithStrictSchema())
+		session := NewSession()
+
+		_, err := synapse.FireWithDetails(context.Background(), session, "text")
+		var unknown *UnknownEmotionError
+		if !errors.As(err, &unknown) || unknown.Emotion != "happiness" {
+			t.Fatalf("expected unknown emotion error, got %v", err)
+		}
+		if !errors.Is(err, ErrUnknownEmotion) || !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected error to match ErrUnknownEmotion and ErrInvalidResponse, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("prompt and schema", func(t *testing.T) {
+		var received string
+		provider := NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			received = prompt
+			return `{"overall": "positive", "confidence": 0.9, "scores": {"positive": 0.9, "negative": 0.05, "neutral": 0.05},
+				"aspects": {}, "emotions": ["joy"], "reasoning": ["tone"]}`, nil
+		})
+		synapse, _ := Sentiment("feedback", provider, WithEmotionTaxonomy(taxonomy))
+		if _, err := synapse.Fire(context.Background(), NewSession(), "text"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(received, `emotions: only from ["joy","anger","fear","sadness"]`) {
+			t.Errorf("expected taxonomy constraint in prompt:\n%s", received)
+		}
+		if strings.Contains(received, defaultEmotionsConstraint) {
+			t.Error("expected default emotions constraint replaced")
+		}
+		if !strings.Contains(received, `"enum": [`) || !strings.Contains(received, `"sadness"`) {
+			t.Errorf("expected emotions enum in schema:\n%s", received)
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		synapse, _ := Sentiment("feedback", respond(`["Joy", "joyy"]`), WithEmotionTaxonomy(taxonomy))
+		batch, err := synapse.FireBatch(context.Background(), []string{"a", "b"}, BatchOptions{})
+		if err != nil || strings.Join(batch.Aggregate.TopEmotions, ",") != "joy" {
+			t.Errorf("expected normalized emotions aggregated, got %v (%v)", batch.Aggregate.TopEmotions, err)
+		}
+	})
+
+	t.Run("default unchanged", func(t *testing.T) {
+		synapse, _ := Sentiment("feedback", respond(`["Happiness", "delight"]`), WithStrictSchema())
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), "text")
+		if err != nil || strings.Join(response.Emotions, ",") != "Happiness,delight" {
+			t.Errorf("expected free-form emotions, got %v (%v)", response.Emotions, err)
+		}
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"anger", "anger", 0},
+		{"anger", "angry", 2},
+		{"sadness", "sadnes", 1},
+		{"", "joy", 3},
+		{"joy", "happiness", 9},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/zoobzio/capitan"
@@ -21,6 +23,7 @@ type Service[T Validator] struct {
 	synapseType        string
 	providerName       string
 	defaultTemperature float32
+	validate           func(*Prompt, T) error // Optional post-validation against the prompt, set by synapses
 }
 
 // NewService creates a new Service with the given pipeline, synapse type, provider, and default temperature.
@@ -36,6 +39,8 @@ func NewService[T Validator](pipeline pipz.Chainable[*SynapseRequest], synapseTy
 
 // NewTerminal creates a terminal processor that calls the provider with session messages.
 // This is the common terminal processor used by all synapse types.
+// Before calling the provider, the estimated prompt size is checked against
+// the request's token limits.
 func NewTerminal(provider Provider) pipz.Chainable[*SynapseRequest] {
 	return pipz.Apply(terminalID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
 		// Build messages array from session + new prompt
@@ -47,19 +52,102 @@ func NewTerminal(provider Provider) pipz.Chainable[*SynapseRequest] {
 		messages[len(messages)-1] = Message{
 			Role:    RoleUser,
 			Content: promptStr,
+			Images:  req.Prompt.Images,
 		}
 
+		// Fail fast on images the provider cannot see
+		if err := checkVision(provider, req); err != nil {
+			return req, err
+		}
+
+		// Fail fast on tools the provider cannot offer
+		if err := checkTools(provider, req); err != nil {
+			return req, err
+		}
+
+		// Fail fast on prompts that cannot fit
+		if err := checkPromptSize(ctx, provider, req, messages); err != nil {
+			return req, err
+		}
+
+		// Skip calls that cannot finish before the deadline
+		if err := checkDeadline(ctx, req); err != nil {
+			return req, err
+		}
+
+		// Previews record the call in place of making it
+		if recordPreview(ctx, provider, req, messages) {
+			return req, nil
+		}
+
+		// Count every provider call, including retries and fallbacks
+		req.Attempts++
+		countAttempt(ctx)
+
 		// Call provider with full message history
-		resp, err := provider.Call(ctx, messages, req.Temperature)
+		resp, err := callProvider(contextWithCallParams(ctx, callParams(req)), provider, req, messages)
 		if err != nil {
 			return req, err
 		}
+		if resp.Truncated {
+			req.Usage = &resp.Usage
+			return req, &ResponseTruncatedError{Provider: provider.Name(), MaxTokens: req.MaxTokens, CompletionTokens: resp.Usage.Completion}
+		}
 		req.Response = resp.Content
 		req.Usage = &resp.Usage
+		req.ToolCalls = resp.ToolCalls
+		req.Logprobs = resp.Logprobs
 		return req, nil
 	})
 }
 
+// callParams returns the parameters providers may apply to the request.
+func callParams(req *SynapseRequest) CallParams {
+	params := CallParams{Seed: req.Seed, MaxTokens: req.MaxTokens, TopP: req.TopP, Stop: req.Stop}
+	if req.Prompt.Format == OutputFormatJSON {
+		params.ResponseSchema = req.Prompt.Schema
+	}
+	return params
+}
+
+// withAttemptCounter returns a context counting the provider calls made for
+// a request. Stages abandoned by a timeout may still be calling the provider
+// after the pipeline returns, so a failed request's count is read from here
+// rather than from its SynapseRequest.
+func withAttemptCounter(ctx context.Context) (context.Context, *atomic.Int64) {
+	attempts := &atomic.Int64{}
+	return context.WithValue(ctx, attemptsContextKey, attempts), attempts
+}
+
+// countAttempt records a provider call for the request served with ctx.
+func countAttempt(ctx context.Context) {
+	if attempts, ok := ctx.Value(attemptsContextKey).(*atomic.Int64); ok {
+		attempts.Add(1)
+	}
+}
+
+// validateResponse parses response and applies the checks execute does,
+// returning the error execute would.
+func (s *Service[T]) validateResponse(prompt *Prompt, response string, check func(T) error) error {
+	value, _, err := parseResponse[T](response, prompt)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParseFailed, err)
+	}
+	if err := value.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	if s.validate != nil {
+		err = s.validate(prompt, value)
+	}
+	if err == nil && check != nil {
+		err = check(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	return nil
+}
+
 // GetPipeline returns the internal pipeline for composition.
 // This is used by WithFallback to combine pipelines.
 func (s *Service[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
@@ -76,7 +164,38 @@ func (s *Service[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
 // The session is only updated after a successful response, ensuring that
 // retries from pipz don't corrupt the session state.
 func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prompt, temperature float32) (T, error) {
-	var result T
+	result, err := s.ExecuteResult(ctx, session, prompt, temperature)
+	return result.Value, err
+}
+
+// ExecuteResult is like Execute but returns the response in a Result envelope
+// carrying the call's metadata. On failure the envelope holds the metadata
+// gathered so far, and Value holds the decoded response if it failed validation.
+func (s *Service[T]) ExecuteResult(ctx context.Context, session *Session, prompt *Prompt, temperature float32) (Result[T], error) {
+	return s.executeChecked(ctx, session, prompt, temperature, nil)
+}
+
+// executeChecked is like ExecuteResult with an additional validation for this
+// call only, run after the service's own. A failed check is reported like any
+// other invalid response and leaves the session untouched.
+func (s *Service[T]) executeChecked(ctx context.Context, session *Session, prompt *Prompt, temperature float32, check func(T) error) (Result[T], error) {
+	return s.executeConfigured(ctx, session, prompt, nil, temperature, check)
+}
+
+// executeConfigured is like executeChecked for synapses whose options
+// configure each call. settings points to the synapse's settings for this
+// call; it travels with the request so those options can set it, and the
+// synapse reads it back once the call returns.
+func (s *Service[T]) executeConfigured(ctx context.Context, session *Session, prompt *Prompt, settings any, temperature float32, check func(T) error) (Result[T], error) {
+	result, err := s.execute(ctx, session, prompt, settings, temperature, check)
+	recordCall(ctx, result)
+	return result, err
+}
+
+// execute runs the request and builds its Result envelope.
+func (s *Service[T]) execute(ctx context.Context, session *Session, prompt *Prompt, settings any, temperature float32, check func(T) error) (result Result[T], err error) {
+	result = Result[T]{Provider: s.providerName}
+	start := time.Now()
 
 	// Resolve temperature: use default if unset or zero
 	if temperature == TemperatureUnset || temperature == 0 {
@@ -85,58 +204,104 @@ func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prom
 
 	// Validate prompt
 	if err := prompt.Validate(); err != nil {
-		return result, fmt.Errorf("invalid prompt: %w", err)
+		return result, fmt.Errorf("%w: %w", ErrInvalidPrompt, err)
 	}
 
-	// Generate unique request ID
+	// Every call gets its own request ID; a caller's ID becomes its parent
 	requestID := uuid.New().String()
+	parentRequestID, _ := RequestIDFromContext(ctx)
+
+	result.RequestID = requestID
+
+	// Expose the request to stages and providers further down the pipeline
+	ctx = contextWithSession(contextWithRequest(ctx, requestID), session)
 
 	// Get current messages from session
 	sessionMessages := session.Messages()
 
 	// Create request with session context
 	request := &SynapseRequest{
-		Prompt:       prompt,
-		Temperature:  temperature,
-		Messages:     sessionMessages,
-		SessionID:    session.ID(),
-		RequestID:    requestID,
-		SynapseType:  s.synapseType,
-		ProviderName: s.providerName,
+		Prompt:          prompt,
+		Temperature:     temperature,
+		Messages:        sessionMessages,
+		SessionID:       session.ID(),
+		RequestID:       requestID,
+		ParentRequestID: parentRequestID,
+		SynapseType:     s.synapseType,
+		ProviderName:    s.providerName,
+		settings:        settings,
 	}
+	request.validate = func(response string) error {
+		return s.validateResponse(prompt, response, check)
+	}
+
+	// Write audit records once the outcome, including parsing and validation, is known
+	ctx, audit := withRequestAudit(ctx)
+	audited := request
+	defer func() { audit.write(ctx, audited, err) }()
 
 	// Emit request.started hook
-	capitan.Info(ctx, RequestStarted,
+	capitan.Info(ctx, RequestStarted, HookFields(ctx,
 		RequestIDKey.Field(requestID),
 		SynapseTypeKey.Field(s.synapseType),
 		ProviderKey.Field(s.providerName),
 		PromptTaskKey.Field(prompt.Task),
 		InputKey.Field(prompt.Input),
 		TemperatureKey.Field(float64(temperature)),
-	)
+	)...)
 
 	// Process through pipeline
+	ctx, attempts := withAttemptCounter(ctx)
+	unprocessed := *request
 	processed, err := s.pipeline.Process(ctx, request)
+	result.Attempts = int(attempts.Load())
+	result.Duration = time.Since(start)
 	if err != nil {
+		// Stages abandoned by a timeout may still be writing to request
+		unprocessed.Attempts = result.Attempts
+		audited = &unprocessed
+
 		// Emit request.failed hook
-		capitan.Error(ctx, RequestFailed,
+		capitan.Error(ctx, RequestFailed, HookFields(ctx,
 			RequestIDKey.Field(requestID),
 			SynapseTypeKey.Field(s.synapseType),
 			ProviderKey.Field(s.providerName),
 			PromptTaskKey.Field(prompt.Task),
 			ErrorKey.Field(err.Error()),
-		)
+		)...)
 		return result, err
 	}
 
+	// Tool calls stand in for the answer: record them for the next call
+	if len(processed.ToolCalls) > 0 {
+		result.Usage = processed.Usage
+		result.response = processed.Response
+		result.ToolCalls = processed.ToolCalls
+		session.Append(RoleUser, prompt.Render())
+		session.appendMessage(Message{Role: RoleAssistant, Content: processed.Response, ToolCalls: processed.ToolCalls})
+		session.SetUsage(processed.Usage)
+		return result, &ToolCallsError{Calls: processed.ToolCalls}
+	}
+
 	// Parse response to type T
 	if processed.Response == "" {
+		if previewing(ctx) {
+			return result, errPreview
+		}
 		return result, fmt.Errorf("no response from provider")
 	}
 
-	if parseErr := json.Unmarshal([]byte(processed.Response), &result); parseErr != nil {
+	result.Usage = processed.Usage
+	result.Logprobs = processed.Logprobs
+	result.response = processed.Response
+
+	value, raw, parseErr := parseResponse[T](processed.Response, prompt)
+	result.Value = value
+	result.Raw = raw
+	result.Duration = time.Since(start)
+	if parseErr != nil {
 		// Emit response.failed hook
-		capitan.Error(ctx, ResponseParseFailed,
+		capitan.Error(ctx, ResponseParseFailed, HookFields(ctx,
 			RequestIDKey.Field(requestID),
 			SynapseTypeKey.Field(s.synapseType),
 			ProviderKey.Field(s.providerName),
@@ -144,14 +309,35 @@ func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prom
 			ResponseKey.Field(processed.Response),
 			ErrorKey.Field(parseErr.Error()),
 			ErrorTypeKey.Field("parse_error"),
-		)
-		return result, fmt.Errorf("failed to parse response: %w", parseErr)
+		)...)
+		return result, fmt.Errorf("%w: %w", ErrParseFailed, parseErr)
 	}
 
 	// Validate response (T is constrained to Validator)
-	if validationErr := result.Validate(); validationErr != nil {
+	if validationErr := value.Validate(); validationErr != nil {
 		// Emit response.failed hook
-		capitan.Error(ctx, ResponseParseFailed,
+		capitan.Error(ctx, ResponseParseFailed, HookFields(ctx,
+			RequestIDKey.Field(requestID),
+			SynapseTypeKey.Field(s.synapseType),
+			ProviderKey.Field(s.providerName),
+			PromptTaskKey.Field(prompt.Task),
+			ResponseKey.Field(processed.Response),
+			ErrorKey.Field(validationErr.Error()),
+			ErrorTypeKey.Field("validation_error"),
+		)...)
+		return result, &ValidationError[T]{Value: value, Err: validationErr}
+	}
+
+	// Apply synapse-specific and call-specific post-validation
+	var validationErr error
+	if s.validate != nil {
+		validationErr = s.validate(prompt, value)
+	}
+	if validationErr == nil && check != nil {
+		validationErr = check(value)
+	}
+	if validationErr != nil {
+		capitan.Error(ctx, ResponseParseFailed, HookFields(ctx,
 			RequestIDKey.Field(requestID),
 			SynapseTypeKey.Field(s.synapseType),
 			ProviderKey.Field(s.providerName),
@@ -159,8 +345,8 @@ func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prom
 			ResponseKey.Field(processed.Response),
 			ErrorKey.Field(validationErr.Error()),
 			ErrorTypeKey.Field("validation_error"),
-		)
-		return result, fmt.Errorf("invalid response: %w", validationErr)
+		)...)
+		return result, &ValidationError[T]{Value: value, Err: validationErr}
 	}
 
 	// Success - update session with conversation and usage
@@ -171,14 +357,14 @@ func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prom
 	session.SetUsage(processed.Usage)
 
 	// Marshal result to JSON for output field
-	outputJSON, marshalErr := json.Marshal(result)
+	outputJSON, marshalErr := json.Marshal(value)
 	if marshalErr != nil {
 		// This should never fail since we already unmarshaled successfully
 		outputJSON = []byte("{}")
 	}
 
 	// Emit request.completed hook
-	capitan.Info(ctx, RequestCompleted,
+	capitan.Info(ctx, RequestCompleted, HookFields(ctx,
 		RequestIDKey.Field(requestID),
 		SynapseTypeKey.Field(s.synapseType),
 		ProviderKey.Field(s.providerName),
@@ -186,7 +372,7 @@ func (s *Service[T]) Execute(ctx context.Context, session *Session, prompt *Prom
 		InputKey.Field(prompt.Input),
 		OutputKey.Field(string(outputJSON)),
 		ResponseKey.Field(processed.Response),
-	)
+	)...)
 
 	return result, nil
 }
@@ -3,6 +3,7 @@ package zyn
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/zoobzio/pipz"
@@ -191,3 +192,37 @@ func TestService_Execute(t *testing.T) {
 		}
 	})
 }
+
+// paramsProvider is a MockProvider that records the call parameters of each call.
+type paramsProvider struct {
+	*MockProvider
+	params []CallParams
+}
+
+func (p *paramsProvider) Call(ctx context.Context, messages []Message, temperature float32) (*ProviderResponse, error) {
+	params, _ := CallParamsFromContext(ctx)
+	p.params = append(p.params, params)
+	return p.MockProvider.Call(ctx, messages, temperature)
+}
+
+func TestTerminalCallParams(t *testing.T) {
+	t.Run("json prompts carry the schema", func(t *testing.T) {
+		provider := &paramsProvider{MockProvider: NewMockProviderWithName("params")}
+		synapse, _ := Binary("question", provider)
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(provider.params) != 1 || !strings.Contains(provider.params[0].ResponseSchema, `"decision"`) {
+			t.Errorf("expected the prompt schema, got %+v", provider.params)
+		}
+	})
+
+	t.Run("other formats omit the schema", func(t *testing.T) {
+		provider := &paramsProvider{MockProvider: NewMockProviderWithName("params")}
+		synapse, _ := Binary("question", provider, WithOutputFormat(OutputFormatYAML))
+		_, _ = synapse.Fire(context.Background(), NewSession(), "input")
+		if len(provider.params) != 1 || provider.params[0].ResponseSchema != "" {
+			t.Errorf("expected no schema, got %+v", provider.params)
+		}
+	})
+}
@@ -14,10 +14,11 @@ import (
 //
 // Sessions are safe for concurrent use by multiple goroutines.
 type Session struct {
-	id        string
-	messages  []Message
-	lastUsage *TokenUsage
-	mu        sync.RWMutex
+	id         string
+	messages   []Message
+	lastUsage  *TokenUsage
+	totalUsage TokenUsage
+	mu         sync.RWMutex
 }
 
 // NewSession creates a new conversation session with a unique ID.
@@ -35,6 +36,16 @@ func NewSession() *Session {
 	}
 }
 
+// NewSessionWithID creates a new, empty session with the given ID.
+// Use this when session IDs are assigned externally, such as by a client
+// header or a SessionStore.
+func NewSessionWithID(id string) *Session {
+	return &Session{
+		id:       id,
+		messages: make([]Message, 0),
+	}
+}
+
 // ID returns the unique identifier for this session.
 func (s *Session) ID() string {
 	s.mu.RLock()
@@ -70,6 +81,20 @@ func (s *Session) Append(role, content string) {
 	})
 }
 
+// AppendToolResult adds the result of a tool call to the session, answering
+// the call with the given ID from a *ToolCallsError.
+func (s *Session) AppendToolResult(callID, content string) {
+	s.appendMessage(Message{Role: RoleTool, Content: content, ToolCallID: callID})
+}
+
+// appendMessage adds msg to the session.
+func (s *Session) appendMessage(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, msg)
+}
+
 // Clear removes all messages from the session.
 // Use this when you want to start a fresh conversation in the same session.
 //
@@ -127,6 +152,16 @@ func (s *Session) Len() int {
 	return len(s.messages)
 }
 
+// EstimateTokens estimates the tokens the session's messages add to a
+// prompt, using HeuristicEstimator when estimator is nil. Pass the provider,
+// when it implements TokenEstimator, for its tokenizer's count.
+func (s *Session) EstimateTokens(estimator TokenEstimator) int {
+	if estimator == nil {
+		estimator = HeuristicEstimator{}
+	}
+	return estimator.EstimateMessages(s.Messages())
+}
+
 // LastUsage returns the token usage from the most recent provider call.
 // Returns nil if no calls have been made yet.
 func (s *Session) LastUsage() *TokenUsage {
@@ -140,7 +175,16 @@ func (s *Session) LastUsage() *TokenUsage {
 	return &usage
 }
 
-// SetUsage updates the session's last usage statistics.
+// TotalUsage returns the token usage accumulated across all provider calls
+// recorded in this session. Clearing or pruning messages does not reset it.
+func (s *Session) TotalUsage() TokenUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.totalUsage
+}
+
+// SetUsage updates the session's last usage statistics and adds them to
+// the session's total usage.
 // This is called internally by the service after successful provider calls.
 func (s *Session) SetUsage(usage *TokenUsage) {
 	s.mu.Lock()
@@ -148,6 +192,7 @@ func (s *Session) SetUsage(usage *TokenUsage) {
 	if usage != nil {
 		u := *usage
 		s.lastUsage = &u
+		addUsage(&s.totalUsage, &u)
 	}
 }
 
@@ -1,6 +1,7 @@
 package zyn
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -18,6 +19,17 @@ func TestNewSession(t *testing.T) {
 	}
 }
 
+func TestNewSessionWithID(t *testing.T) {
+	session := NewSessionWithID("custom-id")
+
+	if session.ID() != "custom-id" {
+		t.Errorf("Expected ID 'custom-id', got %q", session.ID())
+	}
+	if session.Len() != 0 {
+		t.Errorf("New session should have 0 messages, got %d", session.Len())
+	}
+}
+
 func TestSession_ID(t *testing.T) {
 	session1 := NewSession()
 	session2 := NewSession()
@@ -437,6 +449,25 @@ func TestSession_SetMessages(t *testing.T) {
 	})
 }
 
+func TestSession_EstimateTokens(t *testing.T) {
+	session := NewSession()
+	session.Append(RoleUser, strings.Repeat("a", 400))
+	session.Append(RoleAssistant, "abc")
+
+	t.Run("heuristic by default", func(t *testing.T) {
+		// 100 + 4 overhead, 1 + 4 overhead
+		if got := session.EstimateTokens(nil); got != 109 {
+			t.Errorf("expected 109 tokens, got %d", got)
+		}
+	})
+
+	t.Run("given estimator", func(t *testing.T) {
+		if got := session.EstimateTokens(&countingEstimator{tokens: 42}); got != 42 {
+			t.Errorf("expected estimator count, got %d", got)
+		}
+	})
+}
+
 func TestSession_LastUsage(t *testing.T) {
 	t.Run("initially nil", func(t *testing.T) {
 		session := NewSession()
@@ -509,6 +540,24 @@ func TestSession_LastUsage(t *testing.T) {
 	})
 }
 
+func TestSession_TotalUsage(t *testing.T) {
+	session := NewSession()
+
+	if total := session.TotalUsage(); total != (TokenUsage{}) {
+		t.Errorf("Expected zero usage for new session, got %+v", total)
+	}
+
+	session.SetUsage(&TokenUsage{Prompt: 100, Completion: 50, Total: 150})
+	session.SetUsage(nil)
+	session.SetUsage(&TokenUsage{Prompt: 200, Completion: 100, Total: 300, Reasoning: 64})
+	session.Clear()
+
+	expected := TokenUsage{Prompt: 300, Completion: 150, Total: 450, Reasoning: 64}
+	if total := session.TotalUsage(); total != expected {
+		t.Errorf("Expected %+v, got %+v", expected, total)
+	}
+}
+
 func TestSession_Prune(t *testing.T) {
 	t.Run("prune pairs", func(t *testing.T) {
 		session := NewSession()
@@ -0,0 +1,263 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Span is an extracted value with the model's confidence and its location in
+// the input text. Start and End are rune offsets, End exclusive. Both are -1
+// when the location is unknown.
+type Span[T Validator] struct {
+	Value      T       `json:"value"`      // The extracted value
+	Text       string  `json:"text"`       // The occurrence exactly as written in the input
+	Confidence float64 `json:"confidence"` // Confidence in this value, 0.0-1.0
+	Start      int     `json:"start"`      // Rune offset of the first character in the input
+	End        int     `json:"end"`        // Rune offset just past the last character in the input
+}
+
+// HasLocation reports whether the span's offsets are known.
+func (s Span[T]) HasLocation() bool {
+	return s.Start >= 0 && s.End >= 0
+}
+
+// ByteOffsets converts the span's rune offsets to byte offsets into text,
+// the input the span was extracted from, for slicing it as a Go string.
+// Returns -1, -1 when the location is unknown or not within text.
+func (s Span[T]) ByteOffsets(text string) (start, end int) {
+	if !s.HasLocation() {
+		return -1, -1
+	}
+	start, end = -1, -1
+	runes := 0
+	for offset := range text {
+		if runes == s.Start {
+			start = offset
+		}
+		if runes == s.End {
+			end = offset
+		}
+		runes++
+	}
+	if runes == s.End {
+		end = len(text)
+	}
+	if start < 0 || end < 0 {
+		return -1, -1
+	}
+	return start, end
+}
+
+// SpanResponse contains the response from a span extraction synapse.
+type SpanResponse[T Validator] struct {
+	Items []Span[T] `json:"items"` // Extracted values in order of appearance
+}
+
+// Validate checks each extracted value and its confidence.
+// Offsets depend on the input text and are checked by the synapse.
+func (r SpanResponse[T]) Validate() error {
+	for i, item := range r.Items {
+		if item.Confidence < 0 || item.Confidence > 1 {
+			return fmt.Errorf("item %d: confidence must be 0-1, got %f", i, item.Confidence)
+		}
+		if err := item.Value.Validate(); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// SpanExtractionSynapse extracts every occurrence of T from text, reporting
+// a confidence and source offsets for each one so results can be spot-checked.
+type SpanExtractionSynapse[T Validator] struct {
+	extraction *ExtractionSynapse[SpanResponse[T]]
+	lenient    bool // Clear invalid offsets instead of rejecting the response
+}
+
+// ExtractWithSpans creates a new span extraction synapse bound to a provider.
+// The type parameter T defines the structure of each extracted item.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Offsets are validated against the input text. Each item quotes its
+// occurrence, and offsets that do not cover the quote are repaired to the
+// nearest place the quote appears, matched exactly or else regardless of
+// case. By default a response with an item that cannot be located this way
+// is rejected as invalid. Use LenientOffsets for models that cannot provide
+// offsets.
+//
+// Example:
+//
+//	extractor, err := ExtractWithSpans[Person]("people mentioned", provider)
+//	spans, err := extractor.Fire(ctx, session, "Ada met Grace in 1944.")
+//	// spans[0].Value.Name == "Ada", spans[0].Start == 0, spans[0].End == 3
+func ExtractWithSpans[T Validator](what string, provider Provider, opts ...Option) (*SpanExtractionSynapse[T], error) {
+	extraction, err := NewExtraction[SpanResponse[T]](what, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	extraction.constraints = []string{
+		fmt.Sprintf("items: one entry per occurrence of %s, in order of appearance", what),
+		"confidence: 0.0 to 1.0 per item",
+		"text: the occurrence exactly as written in the input",
+		"start, end: character offsets of the text in the input, counted in Unicode characters from 0, end exclusive",
+	}
+
+	synapse := &SpanExtractionSynapse[T]{extraction: extraction}
+	extraction.service.validate = synapse.validateOffsets
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+func (s *SpanExtractionSynapse[T]) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return s.extraction.GetPipeline()
+}
+
+// WithDefaults creates a new span extraction with default input values.
+func (s *SpanExtractionSynapse[T]) WithDefaults(defaults ExtractionInput) *SpanExtractionSynapse[T] {
+	s.extraction.WithDefaults(defaults)
+	return s
+}
+
+// LenientOffsets controls how invalid offsets are handled. When lenient, an
+// item that cannot be located in the input is kept with Start and End set
+// to -1 instead of the whole response being rejected.
+func (s *SpanExtractionSynapse[T]) LenientOffsets(lenient bool) *SpanExtractionSynapse[T] {
+	s.lenient = lenient
+	return s
+}
+
+// Fire executes the extraction against text and returns the extracted spans.
+func (s *SpanExtractionSynapse[T]) Fire(ctx context.Context, session *Session, text string) ([]Span[T], error) {
+	return s.FireWithInput(ctx, session, ExtractionInput{Text: text})
+}
+
+// FireResult executes the extraction and returns the spans in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (s *SpanExtractionSynapse[T]) FireResult(ctx context.Context, session *Session, text string) (Result[[]Span[T]], error) {
+	merged := s.extraction.mergeInputs(ExtractionInput{Text: text})
+	result, err := s.extraction.service.ExecuteResult(ctx, session, s.extraction.buildPrompt(merged), merged.Temperature)
+	if err != nil {
+		return withValue[SpanResponse[T], []Span[T]](result, nil), err
+	}
+	return withValue(result, s.locate(result.Value, merged.Text)), nil
+}
+
+// FireWithInput executes the extraction with rich input structure.
+func (s *SpanExtractionSynapse[T]) FireWithInput(ctx context.Context, session *Session, input ExtractionInput) ([]Span[T], error) {
+	merged := s.extraction.mergeInputs(input)
+	response, err := s.extraction.FireWithInput(ctx, session, merged)
+	if err != nil {
+		return nil, err
+	}
+	return s.locate(response, merged.Text), nil
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a SpanResponse[T].
+func (s *SpanExtractionSynapse[T]) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	spans, err := s.FireWithInput(ctx, session, ExtractionInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SpanResponse[T]{Items: spans}, nil
+}
+
+// validateOffsets rejects items that cannot be located in the prompt's input
+// text, unless offsets are lenient.
+func (s *SpanExtractionSynapse[T]) validateOffsets(prompt *Prompt, response SpanResponse[T]) error {
+	if s.lenient {
+		return nil
+	}
+	text := []rune(prompt.Input)
+	for i, item := range response.Items {
+		if _, _, ok := anchorSpan(text, item.Start, item.End, item.Text); ok {
+			continue
+		}
+		if item.Text != "" {
+			return fmt.Errorf("item %d: text %q not found in input", i, item.Text)
+		}
+		return fmt.Errorf("item %d: offsets [%d, %d) outside input of %d characters", i, item.Start, item.End, len(text))
+	}
+	return nil
+}
+
+// locate returns the response's spans located in text, with offsets
+// repaired where they do not cover the quoted text and cleared where the
+// span cannot be located. Only lenient synapses can reach this with spans
+// that cannot be located.
+func (s *SpanExtractionSynapse[T]) locate(response SpanResponse[T], text string) []Span[T] {
+	runes := []rune(text)
+	spans := make([]Span[T], len(response.Items))
+	for i, item := range response.Items {
+		item.Start, item.End, _ = anchorSpan(runes, item.Start, item.End, item.Text)
+		spans[i] = item
+	}
+	return spans
+}
+
+// anchorSpan returns the rune offsets of a span quoting quote in text.
+// Offsets within text are kept when quote is empty, or when the text they
+// cover is quote or contains it regardless of case. Otherwise the offsets
+// are those of the occurrence of quote nearest start, matched exactly if
+// possible and regardless of case if not. Returns -1, -1 and false if the
+// span cannot be located.
+func anchorSpan(text []rune, start, end int, quote string) (int, int, bool) {
+	if validOffsets(start, end, len(text)) {
+		covered := string(text[start:end])
+		if quote == "" || covered == quote || strings.Contains(strings.ToLower(covered), strings.ToLower(quote)) {
+			return start, end, true
+		}
+	}
+	if quote == "" {
+		return -1, -1, false
+	}
+
+	needle := []rune(quote)
+	for _, fold := range []bool{false, true} {
+		nearest := -1
+		for i := 0; i+len(needle) <= len(text); i++ {
+			if !runesEqual(text[i:i+len(needle)], needle, fold) {
+				continue
+			}
+			if nearest < 0 || distance(i, start) < distance(nearest, start) {
+				nearest = i
+			}
+		}
+		if nearest >= 0 {
+			return nearest, nearest + len(needle), true
+		}
+	}
+	return -1, -1, false
+}
+
+// runesEqual reports whether a and b, of equal length, hold the same runes,
+// regardless of case when fold is set.
+func runesEqual(a, b []rune, fold bool) bool {
+	for i := range a {
+		if a[i] != b[i] && (!fold || unicode.ToLower(a[i]) != unicode.ToLower(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// distance returns how far apart offsets a and b are.
+func distance(a, b int) int {
+	if a < b {
+		return b - a
+	}
+	return a - b
+}
+
+// validOffsets reports whether [start, end) is a non-empty range within length runes.
+func validOffsets(start, end, length int) bool {
+	return start >= 0 && start < end && end <= length
+}
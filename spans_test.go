@@ -0,0 +1,276 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type spanPerson struct {
+	Name string `json:"name"`
+}
+
+func (p spanPerson) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name required")
+	}
+	return nil
+}
+
+func TestExtractWithSpans_Schema(t *testing.T) {
+	synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProvider())
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	var schema JSONSchema
+	if err := json.Unmarshal([]byte(synapse.extraction.schema), &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	items := schema.Properties["items"]
+	if items == nil || items.Items == nil {
+		t.Fatalf("expected items array in schema, got %s", synapse.extraction.schema)
+	}
+	for _, field := range []string{"value", "text", "confidence", "start", "end"} {
+		if items.Items.Properties[field] == nil {
+			t.Errorf("expected span field %q in schema", field)
+		}
+	}
+	if items.Items.Properties["value"].Properties["name"] == nil {
+		t.Error("expected wrapped type's fields in schema")
+	}
+}
+
+func TestExtractWithSpans_Fire(t *testing.T) {
+	// "Zoë" is three runes but four bytes, so byte offsets would be rejected
+	text := "Zoë met Ada."
+	valid := `{"items": [
+		{"value": {"name": "Zoë"}, "confidence": 0.9, "start": 0, "end": 3},
+		{"value": {"name": "Ada"}, "confidence": 0.8, "start": 8, "end": 11}
+	]}`
+
+	t.Run("valid offsets", func(t *testing.T) {
+		synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(valid))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		spans, err := synapse.Fire(context.Background(), NewSession(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(spans) != 2 {
+			t.Fatalf("expected 2 spans, got %d", len(spans))
+		}
+		runes := []rune(text)
+		for _, span := range spans {
+			if got := string(runes[span.Start:span.End]); got != span.Value.Name {
+				t.Errorf("expected offsets to locate %q, got %q", span.Value.Name, got)
+			}
+		}
+	})
+
+	t.Run("offsets out of range rejected", func(t *testing.T) {
+		tests := map[string]string{
+			"past end":  `{"items": [{"value": {"name": "Ada"}, "confidence": 0.8, "start": 9, "end": 14}]}`,
+			"negative":  `{"items": [{"value": {"name": "Ada"}, "confidence": 0.8, "start": -1, "end": 3}]}`,
+			"empty":     `{"items": [{"value": {"name": "Ada"}, "confidence": 0.8, "start": 3, "end": 3}]}`,
+			"byte unit": `{"items": [{"value": {"name": "Ada"}, "confidence": 0.8, "start": 9, "end": 13}]}`,
+		}
+		for name, body := range tests {
+			synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(body))
+			if err != nil {
+				t.Fatalf("failed to create synapse: %v", err)
+			}
+			session := NewSession()
+			_, err = synapse.Fire(context.Background(), session, text)
+			if !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("%s: expected invalid response, got %v", name, err)
+			}
+			if session.Len() != 0 {
+				t.Errorf("%s: expected session untouched", name)
+			}
+		}
+	})
+
+	t.Run("lenient offsets", func(t *testing.T) {
+		body := `{"items": [
+			{"value": {"name": "Zoë"}, "confidence": 0.9, "start": 0, "end": 0},
+			{"value": {"name": "Ada"}, "confidence": 0.8, "start": 8, "end": 11}
+		]}`
+		synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(body))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse.LenientOffsets(true)
+
+		spans, err := synapse.Fire(context.Background(), NewSession(), text)
+		if err != nil {
+			t.Fatalf("expected lenient synapse to accept missing offsets, got %v", err)
+		}
+		if spans[0].HasLocation() || spans[0].Start != -1 || spans[0].End != -1 {
+			t.Errorf("expected unknown location for first span, got [%d, %d)", spans[0].Start, spans[0].End)
+		}
+		if !spans[1].HasLocation() || spans[1].Start != 8 {
+			t.Errorf("expected valid offsets kept, got [%d, %d)", spans[1].Start, spans[1].End)
+		}
+	})
+
+	t.Run("quoted text repairs offsets", func(t *testing.T) {
+		// Byte offsets for Ada, a repeated name, and a quote in another case
+		body := `{"items": [
+			{"value": {"name": "Ada"}, "text": "Ada", "confidence": 0.8, "start": 9, "end": 12},
+			{"value": {"name": "Zoë"}, "text": "zoë", "confidence": 0.9, "start": 4, "end": 7}
+		]}`
+		synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(body))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		spans, err := synapse.Fire(context.Background(), NewSession(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if spans[0].Start != 8 || spans[0].End != 11 {
+			t.Errorf("expected Ada repaired to [8, 11), got [%d, %d)", spans[0].Start, spans[0].End)
+		}
+		if spans[1].Start != 0 || spans[1].End != 3 {
+			t.Errorf("expected Zoë repaired to [0, 3) regardless of case, got [%d, %d)", spans[1].Start, spans[1].End)
+		}
+	})
+
+	t.Run("quoted text not in input rejected", func(t *testing.T) {
+		body := `{"items": [{"value": {"name": "Grace"}, "text": "Grace", "confidence": 0.8, "start": 8, "end": 11}]}`
+		synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(body))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		_, err = synapse.Fire(context.Background(), NewSession(), text)
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `text "Grace" not found`) {
+			t.Errorf("expected an invented quote rejected, got %v", err)
+		}
+
+		synapse.LenientOffsets(true)
+		spans, err := synapse.Fire(context.Background(), NewSession(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if spans[0].HasLocation() {
+			t.Errorf("expected unknown location when lenient, got [%d, %d)", spans[0].Start, spans[0].End)
+		}
+	})
+
+	t.Run("result envelope", func(t *testing.T) {
+		synapse, err := ExtractWithSpans[spanPerson]("people", NewMockProviderWithResponse(valid))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		result, err := synapse.FireResult(context.Background(), NewSession(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Value) != 2 || result.Usage == nil {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("prompt constraints", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return valid, nil
+		})
+		synapse, err := ExtractWithSpans[spanPerson]("people", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), NewSession(), text); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompt, "Unicode characters") {
+			t.Error("expected offset constraint in prompt")
+		}
+	})
+}
+
+func TestSpanResponse_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response SpanResponse[spanPerson]
+		wantErr  bool
+	}{
+		{"empty", SpanResponse[spanPerson]{}, false},
+		{"valid", SpanResponse[spanPerson]{Items: []Span[spanPerson]{{Value: spanPerson{Name: "Ada"}, Confidence: 1}}}, false},
+		{"confidence too high", SpanResponse[spanPerson]{Items: []Span[spanPerson]{{Value: spanPerson{Name: "Ada"}, Confidence: 1.5}}}, true},
+		{"confidence negative", SpanResponse[spanPerson]{Items: []Span[spanPerson]{{Value: spanPerson{Name: "Ada"}, Confidence: -0.1}}}, true},
+		{"invalid value", SpanResponse[spanPerson]{Items: []Span[spanPerson]{{Confidence: 0.5}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.response.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAnchorSpan(t *testing.T) {
+	text := []rune("Ada met ada. ÉMILE and Émile left.")
+	tests := []struct {
+		name        string
+		start, end  int
+		quote       string
+		wantStart   int
+		wantEnd     int
+		wantLocated bool
+	}{
+		{"no quote, valid offsets", 0, 3, "", 0, 3, true},
+		{"no quote, invalid offsets", 0, 99, "", -1, -1, false},
+		{"offsets cover the quote", 8, 11, "ada", 8, 11, true},
+		{"offsets contain the quote regardless of case", 0, 11, "MET", 0, 11, true},
+		{"repaired to the nearest exact match", 10, 13, "Ada", 0, 3, true},
+		{"exact match preferred over nearer folded match", 9, 12, "Ada", 0, 3, true},
+		{"repaired regardless of case", 0, 3, "émile", 13, 18, true},
+		{"nearest of several folded matches", 24, 29, "émile", 23, 28, true},
+		{"quote not in text", 0, 3, "Grace", -1, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := anchorSpan(text, tt.start, tt.end, tt.quote)
+			if start != tt.wantStart || end != tt.wantEnd || ok != tt.wantLocated {
+				t.Errorf("anchorSpan() = %d, %d, %v; want %d, %d, %v", start, end, ok, tt.wantStart, tt.wantEnd, tt.wantLocated)
+			}
+		})
+	}
+}
+
+func TestSpan_ByteOffsets(t *testing.T) {
+	text := "Zoë met Ada."
+	tests := []struct {
+		name      string
+		span      Span[spanPerson]
+		wantStart int
+		wantEnd   int
+	}{
+		{"multi-byte rune", Span[spanPerson]{Start: 0, End: 3}, 0, 4},
+		{"after a multi-byte rune", Span[spanPerson]{Start: 8, End: 11}, 9, 12},
+		{"to the end", Span[spanPerson]{Start: 8, End: 12}, 9, 13},
+		{"unknown location", Span[spanPerson]{Start: -1, End: -1}, -1, -1},
+		{"past the end", Span[spanPerson]{Start: 8, End: 13}, -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.span.ByteOffsets(text)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ByteOffsets() = %d, %d; want %d, %d", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+	if start, end := (Span[spanPerson]{Start: 0, End: 3}).ByteOffsets(text); text[start:end] != "Zoë" {
+		t.Errorf("expected byte offsets to slice Zoë, got %q", text[start:end])
+	}
+}
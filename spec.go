@@ -0,0 +1,316 @@
+package zyn
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Synapse types supported by BuildFromSpec.
+const (
+	SpecTypeBinary         = "binary"
+	SpecTypeClassification = "classification"
+	SpecTypeRanking        = "ranking"
+	SpecTypeSentiment      = "sentiment"
+	SpecTypeTransform      = "transform"
+)
+
+// Maximum temperature accepted in a spec.
+const maxSpecTemperature = 2.0
+
+// SynapseSpec declaratively describes a synapse so it can be defined in
+// configuration (YAML or JSON) rather than code. Callers unmarshal the spec
+// themselves and pass it to BuildFromSpec.
+//
+// Extraction, Analyze, and Convert synapses need Go types and remain code-only.
+//
+// Example YAML:
+//
+//	name: spam-check
+//	type: binary
+//	provider: openai
+//	question: this email is spam
+//	temperature: 0.1
+//	retry: 3
+//	timeout: 10s
+//	defaults:
+//	  context: Corporate mailbox
+type SynapseSpec struct {
+	Name        string       `json:"name" yaml:"name"`                                   // Identifier used in error messages
+	Type        string       `json:"type" yaml:"type"`                                   // One of the SpecType constants
+	Provider    string       `json:"provider,omitempty" yaml:"provider,omitempty"`       // Key into the providers map; optional when only one provider is given
+	Question    string       `json:"question,omitempty" yaml:"question,omitempty"`       // Binary and classification question, or sentiment analysis type
+	Criteria    string       `json:"criteria,omitempty" yaml:"criteria,omitempty"`       // Ranking criteria
+	Instruction string       `json:"instruction,omitempty" yaml:"instruction,omitempty"` // Transform instruction
+	Categories  []string     `json:"categories,omitempty" yaml:"categories,omitempty"`   // Classification categories
+	Defaults    SpecDefaults `json:"defaults,omitempty" yaml:"defaults,omitempty"`       // Default input values merged into every call
+	Temperature float32      `json:"temperature,omitempty" yaml:"temperature,omitempty"` // Default temperature (0 keeps the synapse default)
+	Retry       int          `json:"retry,omitempty" yaml:"retry,omitempty"`             // Maximum attempts; mutually exclusive with Backoff
+	Backoff     *BackoffSpec `json:"backoff,omitempty" yaml:"backoff,omitempty"`         // Retry with exponential backoff
+	Timeout     string       `json:"timeout,omitempty" yaml:"timeout,omitempty"`         // Overall timeout as a Go duration (e.g. "10s")
+}
+
+// SpecDefaults holds default input values for a spec-built synapse.
+// Fields that do not apply to the spec's type are rejected.
+type SpecDefaults struct {
+	Context     string   `json:"context,omitempty" yaml:"context,omitempty"`         // All types
+	Criteria    []string `json:"criteria,omitempty" yaml:"criteria,omitempty"`       // Binary
+	Examples    []string `json:"examples,omitempty" yaml:"examples,omitempty"`       // Binary, ranking
+	Constraints []string `json:"constraints,omitempty" yaml:"constraints,omitempty"` // Binary
+	Aspects     []string `json:"aspects,omitempty" yaml:"aspects,omitempty"`         // Sentiment
+	Style       string   `json:"style,omitempty" yaml:"style,omitempty"`             // Transform
+	MaxLength   int      `json:"max_length,omitempty" yaml:"max_length,omitempty"`   // Transform
+	TopN        int      `json:"top_n,omitempty" yaml:"top_n,omitempty"`             // Ranking
+}
+
+// BackoffSpec configures retry with exponential backoff.
+type BackoffSpec struct {
+	Attempts int    `json:"attempts" yaml:"attempts"` // Maximum attempts
+	Delay    string `json:"delay" yaml:"delay"`       // Initial delay as a Go duration (e.g. "100ms")
+}
+
+// BuildFromSpec constructs a synapse from a declarative spec.
+// The provider is looked up by spec.Provider in providers; when spec.Provider
+// is empty and exactly one provider is given, that provider is used.
+// Unknown types, missing or inapplicable fields, and invalid option
+// combinations return descriptive errors.
+func BuildFromSpec(spec SynapseSpec, providers map[string]Provider) (Synapse, error) {
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("synapse spec %q: %w", spec.Name, err)
+	}
+
+	provider, err := spec.resolveProvider(providers)
+	if err != nil {
+		return nil, fmt.Errorf("synapse spec %q: %w", spec.Name, err)
+	}
+
+	opts, err := spec.options()
+	if err != nil {
+		return nil, fmt.Errorf("synapse spec %q: %w", spec.Name, err)
+	}
+
+	synapse, err := spec.build(provider, opts)
+	if err != nil {
+		return nil, fmt.Errorf("synapse spec %q: %w", spec.Name, err)
+	}
+	return synapse, nil
+}
+
+// validate checks that the spec's fields are present and applicable to its type.
+func (spec SynapseSpec) validate() error {
+	var required map[string]bool
+	switch spec.Type {
+	case SpecTypeBinary:
+		required = map[string]bool{"question": true}
+	case SpecTypeClassification:
+		required = map[string]bool{"question": true, "categories": true}
+	case SpecTypeRanking:
+		required = map[string]bool{"criteria": true}
+	case SpecTypeSentiment:
+		required = map[string]bool{"question": true}
+	case SpecTypeTransform:
+		required = map[string]bool{"instruction": true}
+	case "extraction", "extract", "analyze", "convert":
+		return fmt.Errorf("%s synapses require Go types and cannot be built from a spec", spec.Type)
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unknown synapse type %q (expected one of %s)", spec.Type, strings.Join(specTypes(), ", "))
+	}
+
+	set := map[string]bool{
+		"question":    spec.Question != "",
+		"criteria":    spec.Criteria != "",
+		"instruction": spec.Instruction != "",
+		"categories":  len(spec.Categories) > 0,
+	}
+	for _, field := range []string{"question", "criteria", "instruction", "categories"} {
+		if required[field] && !set[field] {
+			return fmt.Errorf("%s is required for %s synapses", field, spec.Type)
+		}
+		if !required[field] && set[field] {
+			return fmt.Errorf("%s does not apply to %s synapses", field, spec.Type)
+		}
+	}
+
+	return spec.Defaults.validate(spec.Type)
+}
+
+// validate rejects defaults that do not apply to the synapse type.
+func (d SpecDefaults) validate(synapseType string) error {
+	applies := map[string][]string{
+		"criteria":    {SpecTypeBinary},
+		"examples":    {SpecTypeBinary, SpecTypeRanking},
+		"constraints": {SpecTypeBinary},
+		"aspects":     {SpecTypeSentiment},
+		"style":       {SpecTypeTransform},
+		"max_length":  {SpecTypeTransform},
+		"top_n":       {SpecTypeRanking},
+	}
+	set := map[string]bool{
+		"criteria":    len(d.Criteria) > 0,
+		"examples":    len(d.Examples) > 0,
+		"constraints": len(d.Constraints) > 0,
+		"aspects":     len(d.Aspects) > 0,
+		"style":       d.Style != "",
+		"max_length":  d.MaxLength != 0,
+		"top_n":       d.TopN != 0,
+	}
+
+	fields := make([]string, 0, len(applies))
+	for field := range applies {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if set[field] && !slices.Contains(applies[field], synapseType) {
+			return fmt.Errorf("default %s does not apply to %s synapses", field, synapseType)
+		}
+	}
+
+	if d.MaxLength < 0 {
+		return fmt.Errorf("default max_length must not be negative, got %d", d.MaxLength)
+	}
+	if d.TopN < 0 {
+		return fmt.Errorf("default top_n must not be negative, got %d", d.TopN)
+	}
+	return nil
+}
+
+// resolveProvider selects the spec's provider from the available providers.
+func (spec SynapseSpec) resolveProvider(providers map[string]Provider) (Provider, error) {
+	if spec.Provider == "" {
+		if len(providers) == 1 {
+			for _, provider := range providers {
+				return provider, nil
+			}
+		}
+		return nil, fmt.Errorf("provider is required when %d providers are available", len(providers))
+	}
+
+	provider, ok := providers[spec.Provider]
+	if !ok || provider == nil {
+		return nil, fmt.Errorf("unknown provider %q", spec.Provider)
+	}
+	return provider, nil
+}
+
+// options converts the spec's reliability settings to options.
+// Retries are applied before the timeout so the timeout bounds the total duration.
+func (spec SynapseSpec) options() ([]Option, error) {
+	if spec.Temperature < 0 || spec.Temperature > maxSpecTemperature {
+		return nil, fmt.Errorf("temperature must be between 0 and %.0f, got %g", maxSpecTemperature, spec.Temperature)
+	}
+
+	var opts []Option
+
+	switch {
+	case spec.Retry != 0 && spec.Backoff != nil:
+		return nil, fmt.Errorf("retry and backoff are mutually exclusive")
+	case spec.Retry < 0:
+		return nil, fmt.Errorf("retry must be positive, got %d", spec.Retry)
+	case spec.Retry > 0:
+		opts = append(opts, WithRetry(spec.Retry))
+	case spec.Backoff != nil:
+		if spec.Backoff.Attempts < 1 {
+			return nil, fmt.Errorf("backoff attempts must be positive, got %d", spec.Backoff.Attempts)
+		}
+		delay, err := time.ParseDuration(spec.Backoff.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backoff delay %q: %w", spec.Backoff.Delay, err)
+		}
+		if delay <= 0 {
+			return nil, fmt.Errorf("backoff delay must be positive, got %s", delay)
+		}
+		opts = append(opts, WithBackoff(spec.Backoff.Attempts, delay))
+	}
+
+	if spec.Timeout != "" {
+		timeout, err := time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", spec.Timeout, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+
+	return opts, nil
+}
+
+// build constructs the synapse for the spec's type.
+func (spec SynapseSpec) build(provider Provider, opts []Option) (Synapse, error) {
+	d := spec.Defaults
+
+	switch spec.Type {
+	case SpecTypeBinary:
+		synapse, err := Binary(spec.Question, provider, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return synapse.WithDefaults(BinaryInput{
+			Context:     d.Context,
+			Criteria:    d.Criteria,
+			Examples:    d.Examples,
+			Constraints: d.Constraints,
+			Temperature: spec.Temperature,
+		}), nil
+
+	case SpecTypeClassification:
+		synapse, err := Classification(spec.Question, spec.Categories, provider, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return synapse.WithDefaults(ClassificationInput{
+			Context:     d.Context,
+			Temperature: spec.Temperature,
+		}), nil
+
+	case SpecTypeRanking:
+		synapse, err := Ranking(spec.Criteria, provider, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return synapse.WithDefaults(RankingInput{
+			Context:     d.Context,
+			Examples:    d.Examples,
+			TopN:        d.TopN,
+			Temperature: spec.Temperature,
+		}), nil
+
+	case SpecTypeSentiment:
+		synapse, err := Sentiment(spec.Question, provider, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return synapse.WithDefaults(SentimentInput{
+			Context:     d.Context,
+			Aspects:     d.Aspects,
+			Temperature: spec.Temperature,
+		}), nil
+
+	case SpecTypeTransform:
+		synapse, err := Transform(spec.Instruction, provider, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return synapse.WithDefaults(TransformInput{
+			Context:     d.Context,
+			Style:       d.Style,
+			MaxLength:   d.MaxLength,
+			Temperature: spec.Temperature,
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("unknown synapse type %q", spec.Type)
+	}
+}
+
+// specTypes returns the synapse types supported by BuildFromSpec.
+func specTypes() []string {
+	return []string{SpecTypeBinary, SpecTypeClassification, SpecTypeRanking, SpecTypeSentiment, SpecTypeTransform}
+}
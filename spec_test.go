@@ -0,0 +1,315 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildFromSpec_RoundTrip(t *testing.T) {
+	providers := map[string]Provider{"mock": NewMockProvider()}
+
+	tests := []struct {
+		name     string
+		yaml     string
+		input    SynapseInput
+		validate func(t *testing.T, response Validator)
+	}{
+		{
+			name: "binary",
+			yaml: `
+name: email-check
+type: binary
+provider: mock
+question: this is a valid email address
+temperature: 0.1
+retry: 3
+timeout: 10s
+defaults:
+  context: Signup form
+  criteria: [has an @ symbol]
+`,
+			input: SynapseInput{Input: "user@example.com"},
+			validate: func(t *testing.T, response Validator) {
+				r, ok := response.(BinaryResponse)
+				if !ok {
+					t.Fatalf("expected BinaryResponse, got %T", response)
+				}
+				if !r.Decision {
+					t.Error("expected positive decision for valid email")
+				}
+			},
+		},
+		{
+			name: "classification",
+			yaml: `
+name: ticket-type
+type: classification
+question: What type of ticket is this?
+categories: [bug, feature, question]
+backoff:
+  attempts: 3
+  delay: 100ms
+`,
+			input: SynapseInput{Input: "The app crashes on login"},
+			validate: func(t *testing.T, response Validator) {
+				r, ok := response.(ClassificationResponse)
+				if !ok {
+					t.Fatalf("expected ClassificationResponse, got %T", response)
+				}
+				if r.Primary != "bug" {
+					t.Errorf("expected primary 'bug', got %q", r.Primary)
+				}
+			},
+		},
+		{
+			name: "ranking",
+			yaml: `
+name: priority
+type: ranking
+criteria: urgency
+defaults:
+  top_n: 2
+`,
+			input: SynapseInput{Input: "outage\ntypo\nslow page"},
+			validate: func(t *testing.T, response Validator) {
+				if _, ok := response.(RankingResponse); !ok {
+					t.Fatalf("expected RankingResponse, got %T", response)
+				}
+			},
+		},
+		{
+			name: "sentiment",
+			yaml: `
+name: feedback
+type: sentiment
+question: customer feedback
+defaults:
+  aspects: [price, support]
+`,
+			input: SynapseInput{Input: "Great support, too pricey"},
+			validate: func(t *testing.T, response Validator) {
+				r, ok := response.(SentimentResponse)
+				if !ok {
+					t.Fatalf("expected SentimentResponse, got %T", response)
+				}
+				if r.Overall != "positive" {
+					t.Errorf("expected positive sentiment, got %q", r.Overall)
+				}
+			},
+		},
+		{
+			name: "transform",
+			yaml: `
+name: summarize
+type: transform
+instruction: Summarize in one sentence
+defaults:
+  style: formal
+  max_length: 100
+`,
+			input: SynapseInput{Input: "A long article"},
+			validate: func(t *testing.T, response Validator) {
+				r, ok := response.(TransformResponse)
+				if !ok {
+					t.Fatalf("expected TransformResponse, got %T", response)
+				}
+				if r.Output == "" {
+					t.Error("expected transformed output")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spec SynapseSpec
+			if err := yaml.Unmarshal([]byte(tt.yaml), &spec); err != nil {
+				t.Fatalf("failed to unmarshal spec: %v", err)
+			}
+
+			synapse, err := BuildFromSpec(spec, providers)
+			if err != nil {
+				t.Fatalf("failed to build synapse: %v", err)
+			}
+
+			response, err := synapse.Invoke(context.Background(), NewSession(), tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, response)
+		})
+	}
+}
+
+func TestBuildFromSpec_JSON(t *testing.T) {
+	raw := `{"name": "spam", "type": "binary", "question": "this is spam", "defaults": {"constraints": ["be strict"]}}`
+
+	var spec SynapseSpec
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		t.Fatalf("failed to unmarshal spec: %v", err)
+	}
+
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"decision": false, "confidence": 0.9, "reasoning": ["not spam"]}`, nil
+	})
+
+	synapse, err := BuildFromSpec(spec, map[string]Provider{"only": provider})
+	if err != nil {
+		t.Fatalf("failed to build synapse: %v", err)
+	}
+	if _, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "hello"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Determine if this is spam") {
+		t.Errorf("expected question in prompt, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "be strict") {
+		t.Errorf("expected default constraint in prompt, got:\n%s", prompt)
+	}
+}
+
+func TestBuildFromSpec_Temperature(t *testing.T) {
+	var temperature float32
+	provider := NewMockProviderWithCallback(func(_ string, temp float32) (string, error) {
+		temperature = temp
+		return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+	})
+
+	spec := SynapseSpec{Name: "temp", Type: SpecTypeBinary, Question: "ok", Temperature: 0.7}
+	synapse, err := BuildFromSpec(spec, map[string]Provider{"p": provider})
+	if err != nil {
+		t.Fatalf("failed to build synapse: %v", err)
+	}
+	if _, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if temperature != 0.7 {
+		t.Errorf("expected temperature 0.7, got %v", temperature)
+	}
+}
+
+func TestBuildFromSpec_Retry(t *testing.T) {
+	var calls atomic.Int32
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		if calls.Add(1) < 3 {
+			return "", context.DeadlineExceeded
+		}
+		return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+	})
+
+	spec := SynapseSpec{Name: "retry", Type: SpecTypeBinary, Question: "ok", Retry: 3}
+	synapse, err := BuildFromSpec(spec, map[string]Provider{"p": provider})
+	if err != nil {
+		t.Fatalf("failed to build synapse: %v", err)
+	}
+	if _, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "x"}); err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestBuildFromSpec_Errors(t *testing.T) {
+	providers := map[string]Provider{"a": NewMockProvider(), "b": NewMockProvider()}
+
+	tests := []struct {
+		name     string
+		spec     SynapseSpec
+		expected string
+	}{
+		{
+			name:     "missing type",
+			spec:     SynapseSpec{Name: "x", Provider: "a"},
+			expected: `synapse spec "x": type is required`,
+		},
+		{
+			name:     "unknown type",
+			spec:     SynapseSpec{Name: "x", Type: "summarize", Provider: "a"},
+			expected: `unknown synapse type "summarize" (expected one of binary, classification, ranking, sentiment, transform)`,
+		},
+		{
+			name:     "code-only type",
+			spec:     SynapseSpec{Name: "x", Type: "extraction", Provider: "a"},
+			expected: "extraction synapses require Go types",
+		},
+		{
+			name:     "missing question",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Provider: "a"},
+			expected: "question is required for binary synapses",
+		},
+		{
+			name:     "missing categories",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeClassification, Question: "q", Provider: "a"},
+			expected: "categories is required for classification synapses",
+		},
+		{
+			name:     "inapplicable field",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Categories: []string{"a"}, Provider: "a"},
+			expected: "categories does not apply to binary synapses",
+		},
+		{
+			name:     "inapplicable default",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Defaults: SpecDefaults{Style: "formal"}, Provider: "a"},
+			expected: "default style does not apply to binary synapses",
+		},
+		{
+			name:     "retry with backoff",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Retry: 2, Backoff: &BackoffSpec{Attempts: 2, Delay: "1s"}, Provider: "a"},
+			expected: "retry and backoff are mutually exclusive",
+		},
+		{
+			name:     "negative retry",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Retry: -1, Provider: "a"},
+			expected: "retry must be positive",
+		},
+		{
+			name:     "invalid backoff delay",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Backoff: &BackoffSpec{Attempts: 2, Delay: "soon"}, Provider: "a"},
+			expected: `invalid backoff delay "soon"`,
+		},
+		{
+			name:     "invalid timeout",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Timeout: "-5s", Provider: "a"},
+			expected: "timeout must be positive",
+		},
+		{
+			name:     "temperature out of range",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Temperature: 3, Provider: "a"},
+			expected: "temperature must be between 0 and 2",
+		},
+		{
+			name:     "unknown provider",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q", Provider: "c"},
+			expected: `unknown provider "c"`,
+		},
+		{
+			name:     "ambiguous provider",
+			spec:     SynapseSpec{Name: "x", Type: SpecTypeBinary, Question: "q"},
+			expected: "provider is required when 2 providers are available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, err := BuildFromSpec(tt.spec, providers)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if synapse != nil {
+				t.Error("expected nil synapse on error")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
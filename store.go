@@ -0,0 +1,73 @@
+package zyn
+
+import (
+	"context"
+	"sync"
+)
+
+// SessionStore persists sessions by ID so conversations can span requests,
+// such as in an HTTP server. Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Load returns the session with the given ID.
+	// Returns ErrSessionNotFound if no such session exists.
+	Load(ctx context.Context, id string) (*Session, error)
+
+	// Save stores the session under its ID, replacing any previous version.
+	Save(ctx context.Context, session *Session) error
+
+	// Delete removes the session with the given ID.
+	// Deleting a missing session is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemorySessionStore is an in-process SessionStore backed by a map.
+// Sessions are stored by reference, so changes made after Save are visible
+// to later Loads.
+type MemorySessionStore struct {
+	sessions map[string]*Session
+	mu       sync.RWMutex
+}
+
+// NewMemorySessionStore creates an empty in-memory session store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Load implements SessionStore.
+func (m *MemorySessionStore) Load(_ context.Context, id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Save implements SessionStore.
+func (m *MemorySessionStore) Save(_ context.Context, session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.ID()] = session
+	return nil
+}
+
+// Delete implements SessionStore.
+func (m *MemorySessionStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
+
+// Len returns the number of stored sessions.
+func (m *MemorySessionStore) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sessions)
+}
@@ -0,0 +1,80 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+var _ SessionStore = (*MemorySessionStore)(nil)
+
+func TestMemorySessionStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("load missing", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		session, err := store.Load(ctx, "missing")
+		if !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("expected ErrSessionNotFound, got %v", err)
+		}
+		if session != nil {
+			t.Error("expected nil session")
+		}
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		session := NewSessionWithID("abc")
+		session.Append(RoleUser, "hello")
+
+		if err := store.Save(ctx, session); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+
+		loaded, err := store.Load(ctx, "abc")
+		if err != nil {
+			t.Fatalf("load failed: %v", err)
+		}
+		if loaded.ID() != "abc" || loaded.Len() != 1 {
+			t.Errorf("unexpected session: id=%s len=%d", loaded.ID(), loaded.Len())
+		}
+		if store.Len() != 1 {
+			t.Errorf("expected 1 stored session, got %d", store.Len())
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		if err := store.Save(ctx, NewSessionWithID("abc")); err != nil {
+			t.Fatalf("save failed: %v", err)
+		}
+		if err := store.Delete(ctx, "abc"); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+		if _, err := store.Load(ctx, "abc"); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("expected ErrSessionNotFound after delete, got %v", err)
+		}
+		if err := store.Delete(ctx, "abc"); err != nil {
+			t.Errorf("deleting missing session should not fail: %v", err)
+		}
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		store := NewMemorySessionStore()
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				session := NewSession()
+				_ = store.Save(ctx, session)
+				_, _ = store.Load(ctx, session.ID())
+			}()
+		}
+		wg.Wait()
+		if store.Len() != 50 {
+			t.Errorf("expected 50 sessions, got %d", store.Len())
+		}
+	})
+}
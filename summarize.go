@@ -0,0 +1,256 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zoobzio/pipz"
+)
+
+// SummaryFormat is the shape of a summary.
+type SummaryFormat string
+
+// Summary formats.
+const (
+	SummaryParagraph SummaryFormat = "paragraph" // Prose (the default)
+	SummaryBullets   SummaryFormat = "bullets"   // One "- " bullet per line
+)
+
+// summaryLengthTolerance is how far past MaxLength, as a fraction of it, a
+// summary may run before it is rejected, since models count characters
+// loosely.
+const summaryLengthTolerance = 0.1
+
+// keyPointsThreshold is the input length, in characters, above which a
+// summary must list at least one key point.
+const keyPointsThreshold = 500
+
+// SummarizeInput contains rich input structure for summaries.
+type SummarizeInput struct {
+	Text        string        // The text to summarize
+	MaxLength   int           // Optional maximum summary length in characters, enforced with a small tolerance
+	Audience    string        // Optional audience the summary is written for, e.g. "executives"
+	Format      SummaryFormat // SummaryParagraph or SummaryBullets; defaults to SummaryParagraph
+	Context     string        // Optional context, such as what the summary is for
+	Temperature float32       // LLM temperature setting for this specific request
+}
+
+// SummarizeResponse contains the response from a summarize synapse.
+type SummarizeResponse struct {
+	Summary       string   `json:"summary"`        // The summary, in the requested format
+	KeyPoints     []string `json:"key_points"`     // The main points, one sentence each, most important first
+	OmittedTopics []string `json:"omitted_topics"` // Topics of the text the summary leaves out; empty if none
+	Confidence    float64  `json:"confidence"`     // 0.0 to 1.0 confidence score
+	Reasoning     []string `json:"reasoning"`      // Explanation of what was kept and left out
+}
+
+// Validate checks if the response is valid. The summary's length and the
+// key points required for long inputs are checked by the synapse.
+func (r SummarizeResponse) Validate() error {
+	if strings.TrimSpace(r.Summary) == "" {
+		return fmt.Errorf("summary required but empty")
+	}
+	for i, point := range r.KeyPoints {
+		if strings.TrimSpace(point) == "" {
+			return fmt.Errorf("key point %d is empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// checkSummary rejects a summary longer than the input's MaxLength allows and
+// a summary of a long input without key points.
+func checkSummary(input SummarizeInput, response SummarizeResponse) error {
+	if input.MaxLength > 0 {
+		length := utf8.RuneCountInString(response.Summary)
+		if float64(length) > float64(input.MaxLength)*(1+summaryLengthTolerance) {
+			return &OutputTooLongError{Length: length, Limit: input.MaxLength, Unit: LengthCharacters}
+		}
+	}
+	if len(response.KeyPoints) == 0 && utf8.RuneCountInString(input.Text) > keyPointsThreshold {
+		return fmt.Errorf("key points required but empty")
+	}
+	return nil
+}
+
+// SummarizeSynapse summarizes text into a summary and structured key points.
+type SummarizeSynapse struct {
+	defaults SummarizeInput
+	base     *Base[SummarizeInput, SummarizeResponse]
+}
+
+// NewSummarize creates a new summarize synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewSummarize(provider Provider, opts ...Option) (*SummarizeSynapse, error) {
+	synapse := &SummarizeSynapse{}
+
+	base, err := NewSynapse(SynapseConfig[SummarizeInput, SummarizeResponse]{
+		Type:        "summarize",
+		Temperature: DefaultTemperatureAnalytical,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (s *SummarizeSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return s.base.GetPipeline()
+}
+
+// WithDefaults creates a new Summarize with default input values, such as a
+// fixed audience or length. These are merged with user input at execution
+// time.
+func (s *SummarizeSynapse) WithDefaults(defaults SummarizeInput) *SummarizeSynapse {
+	s.defaults = defaults
+	return s
+}
+
+// Fire summarizes text and returns the summary.
+func (s *SummarizeSynapse) Fire(ctx context.Context, session *Session, text string) (string, error) {
+	response, err := s.FireWithInput(ctx, session, SummarizeInput{Text: text})
+	if err != nil {
+		return "", err
+	}
+	return response.Summary, nil
+}
+
+// FireResult summarizes text and returns the summary in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (s *SummarizeSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[string], error) {
+	result, err := s.execute(ctx, session, SummarizeInput{Text: text})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Summary), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (s *SummarizeSynapse) FireWithInput(ctx context.Context, session *Session, input SummarizeInput) (SummarizeResponse, error) {
+	result, err := s.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a SummarizeResponse.
+func (s *SummarizeSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := s.FireWithInput(ctx, session, SummarizeInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and summarizes, rejecting summaries
+// over the length limit and summaries of long inputs without key points.
+// An over-length summary is reported as an *OutputTooLongError.
+func (s *SummarizeSynapse) execute(ctx context.Context, session *Session, input SummarizeInput) (Result[SummarizeResponse], error) {
+	merged := s.mergeInputs(input)
+	if merged.MaxLength < 0 {
+		return Result[SummarizeResponse]{Provider: s.base.service.providerName},
+			fmt.Errorf("%w: max length must not be negative, got %d", ErrInvalidPrompt, merged.MaxLength)
+	}
+	if merged.Format != SummaryParagraph && merged.Format != SummaryBullets {
+		return Result[SummarizeResponse]{Provider: s.base.service.providerName},
+			fmt.Errorf("%w: unknown summary format %q", ErrInvalidPrompt, merged.Format)
+	}
+
+	prompt := s.buildPrompt(merged)
+	prompt.Schema = s.base.Schema()
+	return s.base.service.executeChecked(ctx, session, prompt, merged.Temperature, func(response SummarizeResponse) error {
+		return checkSummary(merged, response)
+	})
+}
+
+// mergeInputs combines defaults with user input.
+func (s *SummarizeSynapse) mergeInputs(input SummarizeInput) SummarizeInput {
+	merged := s.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if input.MaxLength != 0 {
+		merged.MaxLength = input.MaxLength
+	}
+	if input.Audience != "" {
+		merged.Audience = input.Audience
+	}
+	if input.Format != "" {
+		merged.Format = input.Format
+	}
+	if merged.Format == "" {
+		merged.Format = SummaryParagraph
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (s *SummarizeSynapse) buildPrompt(input SummarizeInput) *Prompt {
+	constraints := []string{"summary: only what the text says, with nothing added"}
+	if input.Format == SummaryBullets {
+		constraints = append(constraints, `summary: one point per line, each line starting with "- "`)
+	} else {
+		constraints = append(constraints, "summary: prose paragraphs, no bullets or headings")
+	}
+	if input.MaxLength > 0 {
+		constraints = append(constraints, fmt.Sprintf("summary: at most %d characters", input.MaxLength))
+	}
+	if input.Audience != "" {
+		constraints = append(constraints, fmt.Sprintf("write for %s", input.Audience))
+	}
+	constraints = append(constraints,
+		"key_points: the main points, one sentence each, most important first",
+		"omitted_topics: topics of the text the summary leaves out; empty if none",
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining what was kept and left out",
+	)
+
+	return &Prompt{
+		Task:        "Summarize the text",
+		Input:       input.Text,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Summarize creates a new summarize synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	summarizer, err := Summarize(provider, WithValidationRetry(2))
+//	response, err := summarizer.FireWithInput(ctx, session, SummarizeInput{
+//	    Text:      report,
+//	    MaxLength: 400,
+//	    Audience:  "executives",
+//	    Format:    SummaryBullets,
+//	})
+//	// response.KeyPoints[0]: "Revenue grew 12% on enterprise renewals."
+func Summarize(provider Provider, opts ...Option) (*SummarizeSynapse, error) {
+	return NewSummarize(provider, opts...)
+}
@@ -0,0 +1,186 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeResponse_Validate(t *testing.T) {
+	valid := SummarizeResponse{Summary: "Sales grew.", KeyPoints: []string{"Sales grew 12%."}, Confidence: 0.9, Reasoning: []string{"r"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	empty := valid
+	empty.Summary = " "
+	if err := empty.Validate(); err == nil {
+		t.Error("expected empty summary rejected")
+	}
+	blank := valid
+	blank.KeyPoints = []string{"Sales grew 12%.", ""}
+	if err := blank.Validate(); err == nil || err.Error() != "key point 1 is empty" {
+		t.Errorf("expected empty key point rejected, got %v", err)
+	}
+	unsure := valid
+	unsure.Confidence = 1.5
+	if err := unsure.Validate(); err == nil {
+		t.Error("expected confidence out of range rejected")
+	}
+}
+
+func TestCheckSummary(t *testing.T) {
+	response := SummarizeResponse{Summary: strings.Repeat("a", 105), KeyPoints: []string{"point"}}
+	if err := checkSummary(SummarizeInput{Text: "short", MaxLength: 100}, response); err != nil {
+		t.Errorf("expected a summary within the tolerance accepted, got %v", err)
+	}
+	response.Summary = strings.Repeat("é", 111)
+	err := checkSummary(SummarizeInput{Text: "short", MaxLength: 100}, response)
+	var tooLong *OutputTooLongError
+	if !errors.As(err, &tooLong) || tooLong.Length != 111 || tooLong.Limit != 100 {
+		t.Errorf("expected an over-length summary rejected in characters, got %v", err)
+	}
+
+	response = SummarizeResponse{Summary: "Short."}
+	if err := checkSummary(SummarizeInput{Text: "A short note."}, response); err != nil {
+		t.Errorf("expected short inputs to need no key points, got %v", err)
+	}
+	if err := checkSummary(SummarizeInput{Text: strings.Repeat("word ", 200)}, response); err == nil {
+		t.Error("expected long inputs to need key points")
+	}
+}
+
+func TestSummarizeSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"summary": "Sales grew on renewals.", "key_points": ["Sales grew 12%."], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`, nil
+	})
+	summarizer, err := Summarize(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summary, err := summarizer.Fire(context.Background(), NewSession(), "Quarterly report text.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary != "Sales grew on renewals." {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+	for _, want := range []string{"Task: Summarize the text", "Input: Quarterly report text.", "prose paragraphs", "key_points"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "at most") {
+		t.Errorf("expected no length limit by default, got %s", prompt)
+	}
+}
+
+func TestSummarizeSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"summary": "- Sales grew\n- Costs fell", "key_points": ["Sales grew 12%.", "Costs fell 3%."], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`, nil
+	})
+	summarizer, _ := Summarize(provider)
+	summarizer.WithDefaults(SummarizeInput{Audience: "executives"})
+
+	response, err := summarizer.FireWithInput(context.Background(), NewSession(), SummarizeInput{
+		Text:      "Quarterly report text.",
+		MaxLength: 200,
+		Format:    SummaryBullets,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.KeyPoints) != 2 || len(response.OmittedTopics) != 1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{`starting with "- "`, "at most 200 characters", "write for executives"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestSummarizeSynapse_TooLong(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"summary": "` + strings.Repeat("a", 50) + `", "key_points": ["point"], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`)
+	summarizer, _ := Summarize(provider)
+
+	session := NewSession()
+	_, err := summarizer.FireWithInput(context.Background(), session, SummarizeInput{Text: "Report.", MaxLength: 20})
+	if !errors.Is(err, ErrInvalidResponse) || !errors.Is(err, ErrOutputTooLong) {
+		t.Errorf("expected an over-length summary rejected, got %v", err)
+	}
+	if session.Len() != 0 {
+		t.Errorf("expected session untouched, got %d messages", session.Len())
+	}
+}
+
+func TestSummarizeSynapse_KeyPointsRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"summary": "Sales grew.", "key_points": [], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`, nil
+		}
+		return `{"summary": "Sales grew.", "key_points": ["Sales grew 12%."], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`, nil
+	})
+	summarizer, _ := Summarize(provider, WithValidationRetry(2))
+
+	response, err := summarizer.FireWithInput(context.Background(), NewSession(), SummarizeInput{Text: strings.Repeat("Sales grew. ", 60)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(response.KeyPoints) != 1 {
+		t.Errorf("expected key points after one retry, got %+v in %d calls", response, calls)
+	}
+}
+
+func TestSummarizeSynapse_InvalidInput(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"summary": "Sales grew.", "key_points": [], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`)
+	summarizer, _ := Summarize(provider)
+
+	for _, input := range []SummarizeInput{
+		{Text: "Report.", MaxLength: -1},
+		{Text: "Report.", Format: "table"},
+	} {
+		result, err := summarizer.execute(context.Background(), NewSession(), input)
+		if !errors.Is(err, ErrInvalidPrompt) || result.Provider == "" {
+			t.Errorf("expected %+v rejected before the call, got %v", input, err)
+		}
+	}
+}
+
+func TestSummarizeSynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"summary": "Sales grew.", "key_points": [], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`)
+	summarizer, _ := Summarize(provider)
+
+	result, err := summarizer.FireResult(context.Background(), NewSession(), "Report.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value != "Sales grew." || result.Usage == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestSummarizeSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"summary": "Sales grew.", "key_points": [], "omitted_topics": ["the appendix"], "confidence": 0.9, "reasoning": ["kept the results"]}`)
+	summarizer, _ := Summarize(provider)
+
+	var synapse Synapse = summarizer
+	response, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "Report."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary, ok := response.(SummarizeResponse); !ok || summary.Summary != "Sales grew." {
+		t.Errorf("expected a SummarizeResponse, got %#v", response)
+	}
+	if summarizer.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
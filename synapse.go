@@ -0,0 +1,41 @@
+package zyn
+
+import (
+	"context"
+	"strings"
+)
+
+// Synapse is the type-erased interface shared by synapses that accept text
+// input. It allows synapses to be built from configuration and composed
+// without knowing their response types. The built-in Binary, Classification,
+// Extraction, Ranking, Sentiment, and Transform synapses implement it.
+type Synapse interface {
+	ServiceProvider
+
+	// Invoke executes the synapse and returns its full response
+	// (e.g. BinaryResponse for a Binary synapse).
+	Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error)
+}
+
+// SynapseInput is the common input accepted through the Synapse interface.
+// Each synapse maps it onto its own rich input type.
+type SynapseInput struct {
+	Input       string   // The text to process
+	Context     string   // Optional background information
+	Items       []string // Items for list-based synapses; defaults to the non-empty lines of Input
+	Temperature float32  // LLM temperature setting for this specific request
+}
+
+// items returns the explicit items, or the trimmed non-empty lines of Input.
+func (in SynapseInput) items() []string {
+	if len(in.Items) > 0 {
+		return in.Items
+	}
+	var items []string
+	for _, line := range strings.Split(in.Input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
@@ -0,0 +1,98 @@
+package zyn
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Compile-time checks that the built-in text synapses implement Synapse.
+var (
+	_ Synapse = (*BinarySynapse)(nil)
+	_ Synapse = (*ClassificationSynapse)(nil)
+	_ Synapse = (*ExtractionSynapse[ExtractData])(nil)
+	_ Synapse = (*RankingSynapse)(nil)
+	_ Synapse = (*SentimentSynapse)(nil)
+	_ Synapse = (*TransformSynapse)(nil)
+)
+
+func TestSynapseInput_Items(t *testing.T) {
+	explicit := SynapseInput{Input: "ignored", Items: []string{"a", "b"}}
+	if got := explicit.items(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected explicit items, got %v", got)
+	}
+
+	lines := SynapseInput{Input: " first \n\nsecond\n  \nthird"}
+	if got := lines.items(); !reflect.DeepEqual(got, []string{"first", "second", "third"}) {
+		t.Errorf("expected items from lines, got %v", got)
+	}
+}
+
+func TestSynapse_Invoke(t *testing.T) {
+	ctx := context.Background()
+	provider := NewMockProvider()
+
+	binary, err := Binary("this is an email", provider)
+	if err != nil {
+		t.Fatalf("failed to create binary synapse: %v", err)
+	}
+	classification, err := Classification("what kind", []string{"bug", "feature"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classification synapse: %v", err)
+	}
+	ranking, err := Ranking("priority", provider)
+	if err != nil {
+		t.Fatalf("failed to create ranking synapse: %v", err)
+	}
+	sentiment, err := Sentiment("customer", provider)
+	if err != nil {
+		t.Fatalf("failed to create sentiment synapse: %v", err)
+	}
+	transform, err := Transform("summarize", provider)
+	if err != nil {
+		t.Fatalf("failed to create transform synapse: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		synapse  Synapse
+		input    SynapseInput
+		expected any
+	}{
+		{"binary", binary, SynapseInput{Input: "user@example.com"}, BinaryResponse{}},
+		{"classification", classification, SynapseInput{Input: "it crashes"}, ClassificationResponse{}},
+		{"ranking", ranking, SynapseInput{Input: "low\nhigh"}, RankingResponse{}},
+		{"sentiment", sentiment, SynapseInput{Input: "great"}, SentimentResponse{}},
+		{"transform", transform, SynapseInput{Input: "long text"}, TransformResponse{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSession()
+			response, err := tt.synapse.Invoke(ctx, session, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reflect.TypeOf(response) != reflect.TypeOf(tt.expected) {
+				t.Errorf("expected %T, got %T", tt.expected, response)
+			}
+			if session.Len() != 2 {
+				t.Errorf("expected session to record the exchange, got %d messages", session.Len())
+			}
+		})
+	}
+
+	t.Run("error returns nil response", func(t *testing.T) {
+		failing, err := Binary("question", NewMockProviderWithError("down"))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		response, err := failing.Invoke(ctx, NewSession(), SynapseInput{Input: "x"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if response != nil {
+			t.Errorf("expected nil response on error, got %v", response)
+		}
+	})
+}
@@ -0,0 +1,281 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/zoobzio/pipz"
+)
+
+// DefaultMaxTags is the most tags a tag synapse returns unless
+// TagInput.MaxTags says otherwise.
+const DefaultMaxTags = 5
+
+// kebabCaseTagsConstraint is added to tag prompts by WithKebabCaseTags.
+const kebabCaseTagsConstraint = "tags: kebab-case, lowercase words joined by hyphens with no other punctuation, e.g. machine-learning"
+
+// kebabCaseTagsID identifies the kebab-case tags option.
+var kebabCaseTagsID = pipz.NewIdentity("zyn:kebab-case-tags", "Constrains tags to kebab-case")
+
+// TagInput contains rich input structure for tagging.
+type TagInput struct {
+	Text         string   // The content to tag
+	MaxTags      int      // Most tags returned; 0 uses DefaultMaxTags
+	Context      string   // Optional context, such as the site the content is published on
+	ExistingTags []string // Tags already in use, preferred when they fit
+	Temperature  float32  // LLM temperature setting for this specific request
+}
+
+// TagResponse contains the response from a tag synapse.
+type TagResponse struct {
+	Tags       []string `json:"tags"`       // Tags for the content, most relevant first
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the tags
+}
+
+// Validate checks if the response is valid. The tag count is checked by the
+// synapse once the tags are normalized.
+func (r TagResponse) Validate() error {
+	if len(r.Tags) == 0 {
+		return fmt.Errorf("tags required but empty")
+	}
+	for i, tag := range r.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("tag %d is empty", i)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// tagSettings holds the settings of one tag call.
+type tagSettings struct {
+	kebabCase bool // Convert tags to kebab-case, with WithKebabCaseTags
+}
+
+// WithKebabCaseTags constrains tag synapses to kebab-case tags, such as
+// "machine-learning", which are safe to use in URLs. Tags the model returns
+// in another form are converted.
+// The option has no effect on other synapse types.
+func WithKebabCaseTags() Option {
+	return withSettings(kebabCaseTagsID, func(req *SynapseRequest, settings *tagSettings) {
+		if settings.kebabCase {
+			return
+		}
+		settings.kebabCase = true
+		req.Prompt.Constraints = append(slices.Clone(req.Prompt.Constraints), kebabCaseTagsConstraint)
+	})
+}
+
+// normalizeTags lowercases and trims tags, collapsing inner whitespace, or
+// converts them to kebab-case, and drops repeats and tags left empty,
+// keeping the first of each.
+func normalizeTags(tags []string, kebabCase bool) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if kebabCase {
+			tag = kebabCaseTag(tag)
+		} else {
+			tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		}
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// kebabCaseTag lowercases tag and joins its runs of letters and digits with
+// hyphens, e.g. "Machine Learning!" becomes "machine-learning".
+func kebabCaseTag(tag string) string {
+	words := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// TagSynapse proposes tags for content from an open vocabulary.
+type TagSynapse struct {
+	what     string
+	defaults TagInput
+	base     *Base[TagInput, TagResponse]
+}
+
+// NewTag creates a new tag synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewTag(what string, provider Provider, opts ...Option) (*TagSynapse, error) {
+	synapse := &TagSynapse{what: what}
+
+	base, err := NewSynapse(SynapseConfig[TagInput, TagResponse]{
+		Type:        "tag",
+		Temperature: DefaultTemperatureCreative,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (t *TagSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return t.base.GetPipeline()
+}
+
+// WithDefaults creates a new Tag with default input values, such as the
+// site's existing tags. These are merged with user input at execution time.
+func (t *TagSynapse) WithDefaults(defaults TagInput) *TagSynapse {
+	t.defaults = defaults
+	return t
+}
+
+// Fire tags text and returns the tags.
+func (t *TagSynapse) Fire(ctx context.Context, session *Session, text string) ([]string, error) {
+	response, err := t.FireWithInput(ctx, session, TagInput{Text: text})
+	if err != nil {
+		return nil, err
+	}
+	return response.Tags, nil
+}
+
+// FireResult tags text and returns the tags in a Result envelope carrying
+// the call's usage, timing, and request metadata.
+func (t *TagSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[[]string], error) {
+	result, err := t.execute(ctx, session, TagInput{Text: text})
+	if err != nil {
+		return withValue[TagResponse, []string](result, nil), err
+	}
+	return withValue(result, result.Value.Tags), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (t *TagSynapse) FireWithInput(ctx context.Context, session *Session, input TagInput) (TagResponse, error) {
+	result, err := t.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a TagResponse.
+func (t *TagSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := t.FireWithInput(ctx, session, TagInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, tags the text, and normalizes the
+// tags, rejecting responses with more distinct tags than allowed.
+func (t *TagSynapse) execute(ctx context.Context, session *Session, input TagInput) (Result[TagResponse], error) {
+	merged := t.mergeInputs(input)
+	if merged.MaxTags < 0 {
+		return Result[TagResponse]{Provider: t.base.service.providerName},
+			fmt.Errorf("%w: max tags must not be negative, got %d", ErrInvalidPrompt, merged.MaxTags)
+	}
+
+	// WithKebabCaseTags sets the settings as the request passes through it
+	prompt := t.buildPrompt(merged)
+	prompt.Schema = t.base.Schema()
+	settings := &tagSettings{}
+
+	result, err := t.base.service.executeConfigured(ctx, session, prompt, settings, merged.Temperature, func(response TagResponse) error {
+		tags := normalizeTags(response.Tags, settings.kebabCase)
+		if len(tags) == 0 {
+			return fmt.Errorf("no usable tags in %q", response.Tags)
+		}
+		if len(tags) > maxTags(merged) {
+			return fmt.Errorf("%d tags, at most %d allowed", len(tags), maxTags(merged))
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Value.Tags = normalizeTags(result.Value.Tags, settings.kebabCase)
+	return result, nil
+}
+
+// maxTags returns the tag limit of input.
+func maxTags(input TagInput) int {
+	if input.MaxTags == 0 {
+		return DefaultMaxTags
+	}
+	return input.MaxTags
+}
+
+// mergeInputs combines defaults with user input.
+func (t *TagSynapse) mergeInputs(input TagInput) TagInput {
+	merged := t.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if input.MaxTags != 0 {
+		merged.MaxTags = input.MaxTags
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if len(input.ExistingTags) > 0 {
+		merged.ExistingTags = input.ExistingTags
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (t *TagSynapse) buildPrompt(input TagInput) *Prompt {
+	constraints := []string{
+		fmt.Sprintf("tags: 1 to %d short, lowercase tags describing the content, most relevant first", maxTags(input)),
+		"tags: each names a distinct topic; no synonyms of one another",
+	}
+	if len(input.ExistingTags) > 0 {
+		constraints = append(constraints,
+			fmt.Sprintf("prefer these existing tags, exactly as written, when they fit: %s", strings.Join(input.ExistingTags, ", ")))
+	}
+	constraints = append(constraints,
+		"confidence: 0.0 to 1.0",
+		"reasoning: ordered steps explaining the tags",
+	)
+
+	return &Prompt{
+		Task:        fmt.Sprintf("Tag the %s", t.what),
+		Input:       input.Text,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Tag creates a new tag synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	tagger, err := Tag("blog post", provider, WithKebabCaseTags())
+//	tagger.WithDefaults(TagInput{ExistingTags: siteTags, MaxTags: 3})
+//	tags, err := tagger.Fire(ctx, session, post)
+//	// tags: ["machine-learning", "go", "performance"]
+func Tag(what string, provider Provider, opts ...Option) (*TagSynapse, error) {
+	return NewTag(what, provider, opts...)
+}
@@ -0,0 +1,208 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTagResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := TagResponse{
+			Tags:       []string{"go"},
+			Confidence: 0.8,
+			Reasoning:  []string{"about go"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_tags", func(t *testing.T) {
+		r := TagResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"about go"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no tags")
+		}
+	})
+
+	t.Run("empty_tag", func(t *testing.T) {
+		r := TagResponse{
+			Tags:       []string{"go", "  "},
+			Confidence: 0.8,
+			Reasoning:  []string{"about go"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an empty tag")
+		}
+	})
+
+	t.Run("confidence_too_high", func(t *testing.T) {
+		r := TagResponse{
+			Tags:       []string{"go"},
+			Confidence: 1.5,
+			Reasoning:  []string{"about go"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for confidence > 1")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := TagResponse{
+			Tags:       []string{"go"},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags := []string{" Machine  Learning ", "go", "GO", "C++", "machine learning"}
+
+	if got := normalizeTags(tags, false); !slices.Equal(got, []string{"machine learning", "go", "c++"}) {
+		t.Errorf("unexpected tags: %q", got)
+	}
+	if got := normalizeTags(tags, true); !slices.Equal(got, []string{"machine-learning", "go", "c"}) {
+		t.Errorf("unexpected kebab-case tags: %q", got)
+	}
+	if got := normalizeTags([]string{"!!!", "Café Culture"}, true); !slices.Equal(got, []string{"café-culture"}) {
+		t.Errorf("expected punctuation-only tags dropped, got %q", got)
+	}
+}
+
+func TestTagSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"tags": ["Go", " Performance", "go"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`, nil
+	})
+	tagger, err := Tag("blog post", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags, err := tagger.Fire(context.Background(), NewSession(), "Profiling Go services")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(tags, []string{"go", "performance"}) {
+		t.Errorf("expected normalized, deduplicated tags, got %q", tags)
+	}
+	for _, want := range []string{"Tag the blog post", "1 to 5 short, lowercase tags"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "kebab-case") || strings.Contains(prompt, "existing tags") {
+		t.Errorf("expected no kebab-case or existing tags constraint, got %s", prompt)
+	}
+}
+
+func TestTagSynapse_MaxTags(t *testing.T) {
+	t.Run("too many", func(t *testing.T) {
+		tagger, _ := Tag("blog post", NewMockProviderWithResponse(`{"tags": ["go", "performance", "profiling"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`))
+
+		_, err := tagger.FireWithInput(context.Background(), NewSession(), TagInput{Text: "post", MaxTags: 2})
+		if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "3 tags, at most 2 allowed") {
+			t.Errorf("expected ErrInvalidResponse for too many tags, got %v", err)
+		}
+	})
+
+	t.Run("repeats do not count", func(t *testing.T) {
+		tagger, _ := Tag("blog post", NewMockProviderWithResponse(`{"tags": ["go", "Go", "performance"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`))
+
+		response, err := tagger.FireWithInput(context.Background(), NewSession(), TagInput{Text: "post", MaxTags: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.Tags) != 2 {
+			t.Errorf("unexpected tags: %q", response.Tags)
+		}
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		tagger, _ := Tag("blog post", NewMockProviderWithName("mock"))
+
+		_, err := tagger.FireWithInput(context.Background(), NewSession(), TagInput{Text: "post", MaxTags: -1})
+		if !errors.Is(err, ErrInvalidPrompt) {
+			t.Errorf("expected ErrInvalidPrompt, got %v", err)
+		}
+	})
+}
+
+func TestTagSynapse_ExistingTags(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"tags": ["golang"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`, nil
+	})
+	tagger, _ := Tag("blog post", provider)
+	tagger.WithDefaults(TagInput{ExistingTags: []string{"golang", "databases"}, MaxTags: 3})
+
+	if _, err := tagger.Fire(context.Background(), NewSession(), "post"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"when they fit: golang, databases", "1 to 3 short"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestWithKebabCaseTags(t *testing.T) {
+	t.Run("tag", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"tags": ["Machine Learning", "machine-learning", "Go!"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`, nil
+		})
+		tagger, _ := Tag("blog post", provider, WithKebabCaseTags(), WithKebabCaseTags())
+
+		tags, err := tagger.Fire(context.Background(), NewSession(), "post")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(tags, []string{"machine-learning", "go"}) {
+			t.Errorf("expected kebab-case tags, got %q", tags)
+		}
+		if strings.Count(prompt, kebabCaseTagsConstraint) != 1 {
+			t.Errorf("expected the kebab-case constraint once, got %s", prompt)
+		}
+	})
+
+	t.Run("other synapse types", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["x"]}`, nil
+		})
+		synapse, _ := Binary("question", provider, WithKebabCaseTags())
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompt, "kebab-case") {
+			t.Errorf("expected no kebab-case constraint on a binary prompt, got %s", prompt)
+		}
+	})
+}
+
+func TestTagSynapse_Invoke(t *testing.T) {
+	tagger, _ := Tag("blog post", NewMockProviderWithResponse(`{"tags": ["go"], "confidence": 0.8, "reasoning": ["the post covers these topics"]}`))
+
+	validator, err := tagger.Invoke(context.Background(), NewSession(), SynapseInput{Input: "post"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(TagResponse); !ok || !slices.Equal(response.Tags, []string{"go"}) {
+		t.Errorf("expected TagResponse, got %#v", validator)
+	}
+}
@@ -0,0 +1,291 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// TaxonomyBranch is a top-level category of a taxonomy and its
+// subcategories.
+type TaxonomyBranch struct {
+	Name     string   // The category
+	Children []string // Its subcategories; empty if it has none
+}
+
+// child returns the subcategory matching name regardless of case and
+// surrounding space, or false if there is none.
+func (b TaxonomyBranch) child(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, child := range b.Children {
+		if strings.EqualFold(child, name) {
+			return child, true
+		}
+	}
+	return "", false
+}
+
+// TaxonomyInput contains rich input structure for two-level classification.
+type TaxonomyInput struct {
+	Subject     string  // The input to classify
+	Context     string  // Optional context
+	Temperature float32 // LLM temperature setting for this specific request
+}
+
+// TaxonomyResponse contains the response from a taxonomy synapse.
+type TaxonomyResponse struct {
+	Primary    string   `json:"primary"`    // Top-level category
+	Secondary  string   `json:"secondary"`  // Subcategory of Primary; empty if Primary has none
+	Confidence float64  `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string `json:"reasoning"`  // Explanation of the classification
+}
+
+// Validate checks if the response is valid. The categories are checked
+// against the tree by the synapse.
+func (r TaxonomyResponse) Validate() error {
+	if strings.TrimSpace(r.Primary) == "" {
+		return fmt.Errorf("primary category required but empty")
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// TaxonomySynapse classifies input into a category and one of its
+// subcategories in a single call.
+type TaxonomySynapse struct {
+	question string
+	branches []TaxonomyBranch // Sorted by name
+	defaults TaxonomyInput
+	base     *Base[TaxonomyInput, TaxonomyResponse]
+}
+
+// NewTaxonomy creates a new taxonomy synapse bound to a provider.
+// The tree maps each top-level category to its subcategories.
+// Returns an error if the tree is empty, a category name is empty or
+// declared twice, or the JSON schema cannot be generated.
+func NewTaxonomy(question string, tree map[string][]string, provider Provider, opts ...Option) (*TaxonomySynapse, error) {
+	branches, err := taxonomyBranches(tree)
+	if err != nil {
+		return nil, fmt.Errorf("taxonomy synapse: %w", err)
+	}
+	synapse := &TaxonomySynapse{question: question, branches: branches}
+
+	base, err := NewSynapse(SynapseConfig[TaxonomyInput, TaxonomyResponse]{
+		Type:        "taxonomy",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = synapse.validateTaxonomy
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// taxonomyBranches returns the branches of tree sorted by name, rejecting an
+// empty tree and empty or repeated names. Top-level categories are compared
+// with each other and subcategories with all other subcategories, regardless
+// of case, so a subcategory alone names its branch.
+func taxonomyBranches(tree map[string][]string) ([]TaxonomyBranch, error) {
+	if len(tree) == 0 {
+		return nil, fmt.Errorf("at least one category is required")
+	}
+	branches := make([]TaxonomyBranch, 0, len(tree))
+	primaries := make(map[string]string, len(tree))
+	secondaries := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(tree)) {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			return nil, fmt.Errorf("category name required but empty")
+		}
+		if other, ok := primaries[key]; ok {
+			return nil, fmt.Errorf("category %q declared twice, as %q", other, name)
+		}
+		primaries[key] = name
+
+		for _, child := range tree[name] {
+			key := strings.ToLower(strings.TrimSpace(child))
+			if key == "" {
+				return nil, fmt.Errorf("category %q: subcategory name required but empty", name)
+			}
+			if other, ok := secondaries[key]; ok && other == name {
+				return nil, fmt.Errorf("subcategory %q declared twice under %q", child, name)
+			} else if ok {
+				return nil, fmt.Errorf("subcategory %q declared under both %q and %q", child, other, name)
+			}
+			secondaries[key] = name
+		}
+		branches = append(branches, TaxonomyBranch{Name: name, Children: slices.Clone(tree[name])})
+	}
+	return branches, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (t *TaxonomySynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return t.base.GetPipeline()
+}
+
+// WithDefaults creates a new Taxonomy with default input values.
+// These are merged with user input at execution time.
+func (t *TaxonomySynapse) WithDefaults(defaults TaxonomyInput) *TaxonomySynapse {
+	t.defaults = defaults
+	return t
+}
+
+// Fire classifies input into a category and subcategory.
+func (t *TaxonomySynapse) Fire(ctx context.Context, session *Session, input string) (TaxonomyResponse, error) {
+	return t.FireWithInput(ctx, session, TaxonomyInput{Subject: input})
+}
+
+// FireResult classifies input and returns the response in a Result envelope
+// carrying the call's usage, timing, and request metadata.
+func (t *TaxonomySynapse) FireResult(ctx context.Context, session *Session, input string) (Result[TaxonomyResponse], error) {
+	return t.execute(ctx, session, TaxonomyInput{Subject: input})
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (t *TaxonomySynapse) FireWithInput(ctx context.Context, session *Session, input TaxonomyInput) (TaxonomyResponse, error) {
+	result, err := t.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a TaxonomyResponse.
+func (t *TaxonomySynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := t.FireWithInput(ctx, session, TaxonomyInput{
+		Subject:     input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, classifies, and returns the
+// categories as declared.
+func (t *TaxonomySynapse) execute(ctx context.Context, session *Session, input TaxonomyInput) (Result[TaxonomyResponse], error) {
+	merged := t.mergeInputs(input)
+	result, err := t.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+	if err != nil {
+		return result, err
+	}
+	result.Value = t.canonical(result.Value)
+	return result, nil
+}
+
+// branch returns the top-level category matching name regardless of case
+// and surrounding space, or false if there is none.
+func (t *TaxonomySynapse) branch(name string) (TaxonomyBranch, bool) {
+	name = strings.TrimSpace(name)
+	for _, branch := range t.branches {
+		if strings.EqualFold(branch.Name, name) {
+			return branch, true
+		}
+	}
+	return TaxonomyBranch{}, false
+}
+
+// validateTaxonomy checks that the response names a category of the tree
+// and one of its subcategories, or no subcategory when it has none.
+func (t *TaxonomySynapse) validateTaxonomy(_ *Prompt, response TaxonomyResponse) error {
+	branch, ok := t.branch(response.Primary)
+	if !ok {
+		return fmt.Errorf("unknown category %q", response.Primary)
+	}
+	secondary := strings.TrimSpace(response.Secondary)
+	if len(branch.Children) == 0 {
+		if secondary != "" {
+			return fmt.Errorf("category %q has no subcategories, got %q", branch.Name, response.Secondary)
+		}
+		return nil
+	}
+	if secondary == "" {
+		return fmt.Errorf("category %q requires a subcategory", branch.Name)
+	}
+	if _, ok := branch.child(secondary); ok {
+		return nil
+	}
+	for _, other := range t.branches {
+		if _, ok := other.child(secondary); ok {
+			return fmt.Errorf("subcategory %q belongs to %q, not %q", response.Secondary, other.Name, branch.Name)
+		}
+	}
+	return fmt.Errorf("unknown subcategory %q of %q", response.Secondary, branch.Name)
+}
+
+// canonical returns a validated response with the categories as declared.
+func (t *TaxonomySynapse) canonical(response TaxonomyResponse) TaxonomyResponse {
+	branch, _ := t.branch(response.Primary)
+	response.Primary = branch.Name
+	response.Secondary, _ = branch.child(response.Secondary)
+	return response
+}
+
+// mergeInputs combines defaults with user input.
+func (t *TaxonomySynapse) mergeInputs(input TaxonomyInput) TaxonomyInput {
+	merged := t.defaults
+
+	if input.Subject != "" {
+		merged.Subject = input.Subject
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (t *TaxonomySynapse) buildPrompt(input TaxonomyInput) *Prompt {
+	return &Prompt{
+		Task:     t.question,
+		Input:    input.Subject,
+		Context:  input.Context,
+		Taxonomy: t.branches,
+		Constraints: []string{
+			"primary: exactly one top-level category from the taxonomy",
+			"secondary: exactly one subcategory listed under primary; empty string only if primary has none",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the classification",
+		},
+	}
+}
+
+// Taxonomy creates a new taxonomy synapse bound to a provider.
+// The synapse classifies input into a category and one of its subcategories
+// in a single call, instead of chaining two classification synapses.
+// Returns an error if the tree is invalid or the JSON schema cannot be
+// generated.
+//
+// Example:
+//
+//	synapse, err := Taxonomy("Which team should handle this ticket?",
+//	    map[string][]string{
+//	        "billing":  {"refunds", "invoices"},
+//	        "shipping": {"delays", "damage"},
+//	        "other":    nil,
+//	    },
+//	    provider,
+//	)
+//	response, err := synapse.Fire(ctx, session, "My parcel arrived crushed")
+//	// response.Primary: "shipping", response.Secondary: "damage"
+func Taxonomy(question string, tree map[string][]string, provider Provider, opts ...Option) (*TaxonomySynapse, error) {
+	return NewTaxonomy(question, tree, provider, opts...)
+}
@@ -0,0 +1,199 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// supportTree is the taxonomy of the taxonomy tests.
+var supportTree = map[string][]string{
+	"billing":  {"refunds", "invoices"},
+	"shipping": {"delays", "damage"},
+	"other":    nil,
+}
+
+func TestTaxonomyResponse_Validate(t *testing.T) {
+	valid := TaxonomyResponse{Primary: "shipping", Secondary: "damage", Confidence: 0.9, Reasoning: []string{"r"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	empty := valid
+	empty.Primary = " "
+	if err := empty.Validate(); err == nil {
+		t.Error("expected empty primary rejected")
+	}
+	unsure := valid
+	unsure.Confidence = -0.1
+	if err := unsure.Validate(); err == nil {
+		t.Error("expected confidence out of range rejected")
+	}
+	unreasoned := valid
+	unreasoned.Reasoning = nil
+	if err := unreasoned.Validate(); err == nil {
+		t.Error("expected empty reasoning rejected")
+	}
+}
+
+func TestNewTaxonomy_InvalidTree(t *testing.T) {
+	provider := NewMockProvider()
+	for _, tt := range []struct {
+		name string
+		tree map[string][]string
+		want string
+	}{
+		{"empty", nil, "at least one category"},
+		{"empty category", map[string][]string{" ": {"a"}}, "category name required"},
+		{"empty subcategory", map[string][]string{"billing": {"refunds", ""}}, `category "billing": subcategory name required`},
+		{"repeated category", map[string][]string{"Billing": nil, "billing ": nil}, "declared twice"},
+		{"repeated subcategory", map[string][]string{"billing": {"other"}, "shipping": {"Other"}}, `subcategory "Other" declared under both "billing" and "shipping"`},
+		{"repeated within a branch", map[string][]string{"billing": {"refunds", "Refunds"}}, `subcategory "Refunds" declared twice under "billing"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTaxonomy("Which team?", tt.tree, provider)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTaxonomySynapse_validateTaxonomy(t *testing.T) {
+	synapse, err := Taxonomy("Which team?", supportTree, NewMockProvider())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tt := range []struct {
+		primary, secondary string
+		want               string
+	}{
+		{"shipping", "damage", ""},
+		{"Shipping", " DAMAGE", ""},
+		{"other", "", ""},
+		{"legal", "", `unknown category "legal"`},
+		{"other", "refunds", `category "other" has no subcategories, got "refunds"`},
+		{"billing", "", `category "billing" requires a subcategory`},
+		{"shipping", "refunds", `subcategory "refunds" belongs to "billing", not "shipping"`},
+		{"billing", "chargebacks", `unknown subcategory "chargebacks" of "billing"`},
+	} {
+		err := synapse.validateTaxonomy(nil, TaxonomyResponse{Primary: tt.primary, Secondary: tt.secondary})
+		if tt.want == "" && err != nil {
+			t.Errorf("%s > %s: unexpected error: %v", tt.primary, tt.secondary, err)
+		}
+		if tt.want != "" && (err == nil || err.Error() != tt.want) {
+			t.Errorf("%s > %s: expected %q, got %v", tt.primary, tt.secondary, tt.want, err)
+		}
+	}
+}
+
+func TestTaxonomySynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"primary": "Shipping", "secondary": "Damage", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`, nil
+	})
+	synapse, err := Taxonomy("Which team should handle this ticket?", supportTree, provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	response, err := synapse.Fire(context.Background(), NewSession(), "My parcel arrived crushed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Primary != "shipping" || response.Secondary != "damage" {
+		t.Errorf("expected categories as declared, got %+v", response)
+	}
+	want := "Taxonomy:\n  billing\n    - refunds\n    - invoices\n  other\n  shipping\n    - delays\n    - damage"
+	if !strings.Contains(prompt, want) {
+		t.Errorf("expected prompt to render the sorted tree, got %s", prompt)
+	}
+}
+
+func TestTaxonomySynapse_WrongBranch(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"primary": "billing", "secondary": "damage", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`, nil
+		}
+		return `{"primary": "shipping", "secondary": "damage", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`, nil
+	})
+
+	synapse, _ := Taxonomy("Which team?", supportTree, provider)
+	_, err := synapse.Fire(context.Background(), NewSession(), "My parcel arrived crushed")
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), `belongs to "shipping"`) {
+		t.Errorf("expected a subcategory of another branch rejected, got %v", err)
+	}
+
+	retrying, _ := Taxonomy("Which team?", supportTree, provider, WithValidationRetry(2))
+	response, err := retrying.Fire(context.Background(), NewSession(), "My parcel arrived crushed")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Primary != "shipping" {
+		t.Errorf("expected shipping after a retry, got %+v", response)
+	}
+}
+
+func TestTaxonomySynapse_LeafBranch(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"primary": "other", "secondary": "", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`)
+	synapse, _ := Taxonomy("Which team?", supportTree, provider)
+
+	response, err := synapse.Fire(context.Background(), NewSession(), "Do you sell gift cards?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Primary != "other" || response.Secondary != "" {
+		t.Errorf("unexpected response: %+v", response)
+	}
+}
+
+func TestTaxonomySynapse_FireResult(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"primary": "billing", "secondary": "refunds", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`)
+	synapse, _ := Taxonomy("Which team?", supportTree, provider)
+
+	result, err := synapse.FireResult(context.Background(), NewSession(), "I want my money back")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Value.Secondary != "refunds" || result.Usage == nil {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestTaxonomySynapse_WithDefaults(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"primary": "billing", "secondary": "invoices", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`, nil
+	})
+	synapse, _ := Taxonomy("Which team?", supportTree, provider)
+	synapse.WithDefaults(TaxonomyInput{Context: "enterprise customer"})
+
+	if _, err := synapse.Fire(context.Background(), NewSession(), "Where is my invoice?"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Context: enterprise customer") {
+		t.Errorf("expected default context in prompt, got %s", prompt)
+	}
+}
+
+func TestTaxonomySynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"primary": "shipping", "secondary": "delays", "confidence": 0.9, "reasoning": ["the parcel was damaged"]}`)
+	synapse, _ := Taxonomy("Which team?", supportTree, provider)
+
+	var s Synapse = synapse
+	response, err := s.Invoke(context.Background(), NewSession(), SynapseInput{Input: "Still waiting on my order"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if classified, ok := response.(TaxonomyResponse); !ok || classified.Secondary != "delays" {
+		t.Errorf("expected a TaxonomyResponse, got %#v", response)
+	}
+	if synapse.GetPipeline() == nil {
+		t.Error("expected a pipeline")
+	}
+}
@@ -0,0 +1,154 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/zoobzio/zyn"
+)
+
+// Latency histogram layout: bucket i holds calls up to
+// histogramBase × 2^(i/histogramSteps), so each bucket is about 9% wider than
+// the last, from 1µs up to about 2 minutes. Percentiles are reported at the
+// upper bound of their bucket, capped at the slowest call.
+const (
+	histogramBase    = time.Microsecond
+	histogramSteps   = 8
+	histogramBuckets = 27 * histogramSteps
+)
+
+// ProviderStats summarizes the calls recorded by a BenchmarkProvider.
+type ProviderStats struct {
+	Count      int           // Calls recorded
+	Errors     int           // Calls that returned an error
+	P50        time.Duration // Median call latency
+	P95        time.Duration // 95th percentile call latency
+	P99        time.Duration // 99th percentile call latency
+	Max        time.Duration // Slowest call
+	Throughput float64       // Calls per second, from the first call's start to the last call's end
+}
+
+// BenchmarkProvider wraps a provider and records the latency and outcome of
+// every call in a fixed-bucket histogram. Recording uses atomic counters only,
+// so it is safe and cheap under concurrent benchmarks.
+type BenchmarkProvider struct {
+	provider zyn.Provider
+	buckets  [histogramBuckets + 1]atomic.Int64 // The last bucket holds anything slower
+	errors   atomic.Int64
+	max      atomic.Int64 // Nanoseconds
+	first    atomic.Int64 // Unix nanoseconds of the first call's start
+	last     atomic.Int64 // Unix nanoseconds of the last call's end
+}
+
+// NewBenchmarkProvider wraps a provider with latency recording.
+func NewBenchmarkProvider(provider zyn.Provider) *BenchmarkProvider {
+	return &BenchmarkProvider{provider: provider}
+}
+
+// Call delegates to the wrapped provider and records its latency and outcome.
+func (p *BenchmarkProvider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	start := time.Now()
+	p.first.CompareAndSwap(0, start.UnixNano())
+
+	resp, err := p.provider.Call(ctx, messages, temperature)
+
+	end := time.Now()
+	elapsed := end.Sub(start)
+	p.buckets[bucketFor(elapsed)].Add(1)
+	if err != nil {
+		p.errors.Add(1)
+	}
+	storeMax(&p.max, int64(elapsed))
+	storeMax(&p.last, end.UnixNano())
+	return resp, err
+}
+
+// Name returns the wrapped provider's name.
+func (p *BenchmarkProvider) Name() string {
+	return p.provider.Name()
+}
+
+// Stats returns a summary of the calls recorded so far.
+func (p *BenchmarkProvider) Stats() ProviderStats {
+	var counts [histogramBuckets + 1]int64
+	var total int64
+	for i := range p.buckets {
+		counts[i] = p.buckets[i].Load()
+		total += counts[i]
+	}
+
+	stats := ProviderStats{
+		Count:  int(total),
+		Errors: int(p.errors.Load()),
+		Max:    time.Duration(p.max.Load()),
+	}
+	if total == 0 {
+		return stats
+	}
+	stats.P50 = percentile(counts[:], total, 0.50, stats.Max)
+	stats.P95 = percentile(counts[:], total, 0.95, stats.Max)
+	stats.P99 = percentile(counts[:], total, 0.99, stats.Max)
+	if window := time.Duration(p.last.Load() - p.first.Load()); window > 0 {
+		stats.Throughput = float64(total) / window.Seconds()
+	}
+	return stats
+}
+
+// Report writes the stats in a human-readable form.
+func (p *BenchmarkProvider) Report(w io.Writer) {
+	stats := p.Stats()
+	fmt.Fprintf(w, "%s: %d calls, %d errors, %.1f calls/s\n", p.Name(), stats.Count, stats.Errors, stats.Throughput)
+	fmt.Fprintf(w, "  p50 %v  p95 %v  p99 %v  max %v\n", stats.P50, stats.P95, stats.P99, stats.Max)
+}
+
+// Reset clears all recorded calls.
+func (p *BenchmarkProvider) Reset() {
+	for i := range p.buckets {
+		p.buckets[i].Store(0)
+	}
+	p.errors.Store(0)
+	p.max.Store(0)
+	p.first.Store(0)
+	p.last.Store(0)
+}
+
+// bucketFor returns the histogram bucket of a latency.
+func bucketFor(d time.Duration) int {
+	if d <= histogramBase {
+		return 0
+	}
+	bucket := int(math.Ceil(math.Log2(float64(d)/float64(histogramBase)) * histogramSteps))
+	return min(bucket, histogramBuckets)
+}
+
+// bucketBound returns the upper bound of a histogram bucket.
+func bucketBound(bucket int) time.Duration {
+	return time.Duration(float64(histogramBase) * math.Exp2(float64(bucket)/histogramSteps))
+}
+
+// percentile returns the latency below which fraction q of the calls fall.
+func percentile(counts []int64, total int64, q float64, slowest time.Duration) time.Duration {
+	rank := int64(math.Ceil(q * float64(total)))
+	var seen int64
+	for bucket, count := range counts {
+		seen += count
+		if seen >= rank {
+			return min(bucketBound(bucket), slowest)
+		}
+	}
+	return slowest
+}
+
+// storeMax raises v to n if n is larger.
+func storeMax(v *atomic.Int64, n int64) {
+	for {
+		current := v.Load()
+		if n <= current || v.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/zyn"
+)
+
+func TestBenchmarkProvider_RecordsCalls(t *testing.T) {
+	provider := NewBenchmarkProvider(NewLatencyProvider(NewFailingProvider(2), 2*time.Millisecond))
+	ctx := context.Background()
+	messages := []zyn.Message{{Role: zyn.RoleUser, Content: "test"}}
+
+	for i := 0; i < 10; i++ {
+		_, _ = provider.Call(ctx, messages, 0.5)
+	}
+
+	stats := provider.Stats()
+	if stats.Count != 10 || stats.Errors != 2 {
+		t.Errorf("expected 10 calls and 2 errors, got %d and %d", stats.Count, stats.Errors)
+	}
+	if stats.Max < 2*time.Millisecond {
+		t.Errorf("expected max of at least the induced latency, got %v", stats.Max)
+	}
+	if stats.P50 < 2*time.Millisecond || stats.P50 > stats.P95 || stats.P95 > stats.P99 || stats.P99 > stats.Max {
+		t.Errorf("expected ordered percentiles above the induced latency, got %+v", stats)
+	}
+	if stats.Throughput <= 0 || stats.Throughput > 500 {
+		t.Errorf("expected at most 500 calls/s with 2ms calls, got %f", stats.Throughput)
+	}
+
+	provider.Reset()
+	if stats := provider.Stats(); stats != (ProviderStats{}) {
+		t.Errorf("expected empty stats after reset, got %+v", stats)
+	}
+}
+
+func TestBenchmarkProvider_Report(t *testing.T) {
+	provider := NewBenchmarkProvider(NewSequencedProvider("ok"))
+	_, _ = provider.Call(context.Background(), nil, 0)
+
+	var out bytes.Buffer
+	provider.Report(&out)
+	if !strings.Contains(out.String(), SequencedProviderName+": 1 calls, 0 errors") || !strings.Contains(out.String(), "p99") {
+		t.Errorf("unexpected report: %q", out.String())
+	}
+}
+
+func TestBenchmarkProvider_Concurrent(t *testing.T) {
+	provider := NewBenchmarkProvider(zyn.NewMockProvider())
+	synapse, _ := zyn.Binary("question", provider)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = synapse.Fire(context.Background(), zyn.NewSession(), "input")
+			_ = provider.Stats()
+		}()
+	}
+	wg.Wait()
+
+	if stats := provider.Stats(); stats.Count != 50 || stats.Errors != 0 {
+		t.Errorf("expected 50 successful calls, got %+v", stats)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var counts [histogramBuckets + 1]int64
+	counts[bucketFor(time.Millisecond)] = 90
+	counts[bucketFor(10*time.Millisecond)] = 9
+	counts[bucketFor(100*time.Millisecond)] = 1
+	slowest := 100 * time.Millisecond
+
+	within := func(got, want time.Duration) bool {
+		return got >= want && float64(got) <= float64(want)*1.1
+	}
+	if p50 := percentile(counts[:], 100, 0.50, slowest); !within(p50, time.Millisecond) {
+		t.Errorf("expected p50 near 1ms, got %v", p50)
+	}
+	if p95 := percentile(counts[:], 100, 0.95, slowest); !within(p95, 10*time.Millisecond) {
+		t.Errorf("expected p95 near 10ms, got %v", p95)
+	}
+	if p99 := percentile(counts[:], 100, 0.99, slowest); !within(p99, 10*time.Millisecond) {
+		t.Errorf("expected p99 near 10ms, got %v", p99)
+	}
+	if p100 := percentile(counts[:], 100, 1, slowest); p100 != slowest {
+		t.Errorf("expected the slowest call capped at %v, got %v", slowest, p100)
+	}
+}
+
+func TestBucketFor(t *testing.T) {
+	if bucketFor(0) != 0 || bucketFor(time.Microsecond) != 0 {
+		t.Error("expected sub-microsecond calls in the first bucket")
+	}
+	if bucketFor(time.Hour) != histogramBuckets {
+		t.Error("expected very slow calls in the overflow bucket")
+	}
+	for _, d := range []time.Duration{3 * time.Microsecond, 750 * time.Microsecond, 42 * time.Millisecond, 3 * time.Second} {
+		bucket := bucketFor(d)
+		if d > bucketBound(bucket) || d <= bucketBound(bucket-1) {
+			t.Errorf("expected %v within bucket %d (%v, %v]", d, bucket, bucketBound(bucket-1), bucketBound(bucket))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package benchmarks
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/zyn"
+	zynt "github.com/zoobzio/zyn/testing"
+)
+
+// tailLatencyProvider answers most calls quickly and every slowEvery-th call
+// slowly, simulating a provider with a long latency tail.
+type tailLatencyProvider struct {
+	provider  zyn.Provider
+	fast      time.Duration
+	slow      time.Duration
+	slowEvery int64
+	calls     atomic.Int64
+}
+
+func (p *tailLatencyProvider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+	delay := p.fast
+	if p.calls.Add(1)%p.slowEvery == 0 {
+		delay = p.slow
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return p.provider.Call(ctx, messages, temperature)
+}
+
+func (p *tailLatencyProvider) Name() string {
+	return p.provider.Name()
+}
+
+// BenchmarkLatency_RetryStacks compares option stacks against a provider with
+// a latency tail. Retrying alone waits out every slow call; retrying around a
+// timeout abandons slow calls and tries again. ns/op is the mean latency seen
+// by callers; the reported metrics describe the provider calls behind it.
+func BenchmarkLatency_RetryStacks(b *testing.B) {
+	stacks := []struct {
+		name string
+		opts []zyn.Option
+	}{
+		{"WithRetry", []zyn.Option{zyn.WithRetry(3)}},
+		{"WithTimeoutAndRetry", []zyn.Option{zyn.WithTimeout(5 * time.Millisecond), zyn.WithRetry(3)}},
+	}
+
+	for _, stack := range stacks {
+		b.Run(stack.name, func(b *testing.B) {
+			provider := zynt.NewBenchmarkProvider(&tailLatencyProvider{
+				provider:  zyn.NewMockProvider(),
+				fast:      time.Millisecond,
+				slow:      20 * time.Millisecond,
+				slowEvery: 10,
+			})
+			synapse, _ := zyn.Binary("question", provider, stack.opts...)
+			ctx := context.Background()
+
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				session := zyn.NewSession()
+				for pb.Next() {
+					session.Clear()
+					result, err := synapse.Fire(ctx, session, "input")
+					sinkBool = result
+					sinkError = err
+				}
+			})
+			b.StopTimer()
+
+			stats := provider.Stats()
+			b.ReportMetric(float64(stats.P50.Microseconds()), "p50-µs")
+			b.ReportMetric(float64(stats.P99.Microseconds()), "p99-µs")
+			b.ReportMetric(float64(stats.Count)/float64(b.N), "calls/op")
+			b.ReportMetric(stats.Throughput, "calls/s")
+		})
+	}
+}
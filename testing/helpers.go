@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -36,6 +37,14 @@ func (b *ResponseBuilder) WithDecision(decision bool) *ResponseBuilder {
 	return b
 }
 
+// WithUnknown sets a null decision and the unknown field (for binary
+// synapses built with zyn.WithAbstain).
+func (b *ResponseBuilder) WithUnknown() *ResponseBuilder {
+	b.data["decision"] = nil
+	b.data["unknown"] = true
+	return b
+}
+
 // WithConfidence sets the confidence field.
 func (b *ResponseBuilder) WithConfidence(confidence float64) *ResponseBuilder {
 	b.data["confidence"] = confidence
@@ -60,12 +69,33 @@ func (b *ResponseBuilder) WithSecondary(secondary string) *ResponseBuilder {
 	return b
 }
 
+// WithDistribution sets the distribution field (for classification synapses).
+func (b *ResponseBuilder) WithDistribution(distribution map[string]float64) *ResponseBuilder {
+	b.data["distribution"] = distribution
+	return b
+}
+
 // WithRanked sets the ranked field (for ranking synapses).
 func (b *ResponseBuilder) WithRanked(items ...string) *ResponseBuilder {
 	b.data["ranked"] = items
 	return b
 }
 
+// WithRankedScores sets the scores field (for ranking synapses).
+// Scores are keyed by item; if they increase down the order set by WithRanked,
+// the synapse re-sorts the items by score.
+func (b *ResponseBuilder) WithRankedScores(scores map[string]float64) *ResponseBuilder {
+	b.data["scores"] = scores
+	return b
+}
+
+// WithJustifications sets the justifications field (for ranking synapses
+// with ExplainEach), keyed by ranked item.
+func (b *ResponseBuilder) WithJustifications(justifications map[string]string) *ResponseBuilder {
+	b.data["justifications"] = justifications
+	return b
+}
+
 // WithOutput sets the output field (for transform synapses).
 func (b *ResponseBuilder) WithOutput(output string) *ResponseBuilder {
 	b.data["output"] = output
@@ -78,12 +108,25 @@ func (b *ResponseBuilder) WithChanges(changes ...string) *ResponseBuilder {
 	return b
 }
 
+// WithFindings sets the findings field (for analyze synapses).
+func (b *ResponseBuilder) WithFindings(findings ...zyn.Finding) *ResponseBuilder {
+	b.data["findings"] = findings
+	return b
+}
+
 // WithOverall sets the overall field (for sentiment synapses).
 func (b *ResponseBuilder) WithOverall(overall string) *ResponseBuilder {
 	b.data["overall"] = overall
 	return b
 }
 
+// WithIntensity sets the intensity field (for sentiment synapses), from -1.0
+// (most negative) to 1.0 (most positive).
+func (b *ResponseBuilder) WithIntensity(intensity float64) *ResponseBuilder {
+	b.data["intensity"] = intensity
+	return b
+}
+
 // WithScores sets the scores field (for sentiment synapses).
 func (b *ResponseBuilder) WithScores(positive, negative, neutral float64) *ResponseBuilder {
 	b.data["scores"] = map[string]float64{
@@ -249,6 +292,8 @@ func (p *FailingProvider) Reset() {
 type RecordedCall struct {
 	Messages    []zyn.Message
 	Temperature float32
+	RequestID   string         // Request ID carried by the call's context
+	Params      zyn.CallParams // Call parameters carried by the call's context
 }
 
 // CallRecorder wraps a provider and records all calls made to it.
@@ -260,26 +305,43 @@ type CallRecorder struct {
 
 // NewCallRecorder wraps a provider with call recording.
 func NewCallRecorder(provider zyn.Provider) *CallRecorder {
-	return &CallRecorder{
-		provider: provider,
-		calls:    make([]RecordedCall, 0),
-	}
+	r := &CallRecorder{calls: make([]RecordedCall, 0)}
+	r.provider = zyn.WrapProvider(provider, r.Middleware())
+	return r
+}
+
+// Middleware returns a middleware recording calls on r, for adding
+// recording to a provider built with zyn.WrapProvider.
+func (r *CallRecorder) Middleware() zyn.ProviderMiddleware {
+	return zyn.CallMiddleware(func(next zyn.CallFunc) zyn.CallFunc {
+		return func(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+			r.record(ctx, messages, temperature)
+			return next(ctx, messages, temperature)
+		}
+	})
 }
 
 // Call delegates to the wrapped provider and records the call.
 func (r *CallRecorder) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
-	// Record the call (copy messages to avoid aliasing)
+	return r.provider.Call(ctx, messages, temperature)
+}
+
+// record adds a call, copying messages to avoid aliasing.
+func (r *CallRecorder) record(ctx context.Context, messages []zyn.Message, temperature float32) {
 	msgCopy := make([]zyn.Message, len(messages))
 	copy(msgCopy, messages)
 
+	requestID, _ := zyn.RequestIDFromContext(ctx)
+	params, _ := zyn.CallParamsFromContext(ctx)
+
 	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.calls = append(r.calls, RecordedCall{
 		Messages:    msgCopy,
 		Temperature: temperature,
+		RequestID:   requestID,
+		Params:      params,
 	})
-	r.mu.Unlock()
-
-	return r.provider.Call(ctx, messages, temperature)
 }
 
 // Name returns the wrapped provider's name.
@@ -287,6 +349,11 @@ func (r *CallRecorder) Name() string {
 	return r.provider.Name()
 }
 
+// Close closes the wrapped provider, if it implements io.Closer.
+func (r *CallRecorder) Close() error {
+	return closeProvider(r.provider)
+}
+
 // Calls returns a copy of all recorded calls.
 func (r *CallRecorder) Calls() []RecordedCall {
 	r.mu.Lock()
@@ -326,29 +393,36 @@ func (r *CallRecorder) Reset() {
 // LatencyProvider wraps a provider and adds artificial latency.
 type LatencyProvider struct {
 	provider zyn.Provider
-	delay    time.Duration
 }
 
 // NewLatencyProvider wraps a provider with artificial delay.
 // The delay is applied before each provider call and respects context cancellation.
 func NewLatencyProvider(provider zyn.Provider, delay time.Duration) *LatencyProvider {
-	return &LatencyProvider{
-		provider: provider,
-		delay:    delay,
-	}
+	return &LatencyProvider{provider: zyn.WrapProvider(provider, Latency(delay))}
+}
+
+// Latency returns a middleware delaying each call by delay before it
+// reaches the provider. A context canceled during the delay ends the call
+// with the context's error.
+func Latency(delay time.Duration) zyn.ProviderMiddleware {
+	return zyn.CallMiddleware(func(next zyn.CallFunc) zyn.CallFunc {
+		return func(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+					// Delay completed
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			return next(ctx, messages, temperature)
+		}
+	})
 }
 
 // Call adds latency then delegates to the wrapped provider.
 // Respects context cancellation during the delay period.
 func (p *LatencyProvider) Call(ctx context.Context, messages []zyn.Message, temperature float32) (*zyn.ProviderResponse, error) {
-	if p.delay > 0 {
-		select {
-		case <-time.After(p.delay):
-			// Delay completed
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
-	}
 	return p.provider.Call(ctx, messages, temperature)
 }
 
@@ -357,12 +431,30 @@ func (p *LatencyProvider) Name() string {
 	return p.provider.Name()
 }
 
-// UsageAccumulator tracks total token usage across multiple calls.
+// Close closes the wrapped provider, if it implements io.Closer.
+func (p *LatencyProvider) Close() error {
+	return closeProvider(p.provider)
+}
+
+// closeProvider closes provider if it implements io.Closer.
+func closeProvider(provider zyn.Provider) error {
+	if closer, ok := provider.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// UsageAccumulator tracks total token usage across multiple calls, and the
+// usage of each model for calls added with AddUsageFor.
 type UsageAccumulator struct {
 	promptTokens     atomic.Int64
 	completionTokens atomic.Int64
 	totalTokens      atomic.Int64
+	reasoningTokens  atomic.Int64
 	callCount        atomic.Int64
+
+	mu      sync.Mutex
+	byModel map[string]zyn.TokenUsage
 }
 
 // NewUsageAccumulator creates a new usage accumulator.
@@ -376,6 +468,7 @@ func (a *UsageAccumulator) Add(session *zyn.Session) {
 		a.promptTokens.Add(int64(usage.Prompt))
 		a.completionTokens.Add(int64(usage.Completion))
 		a.totalTokens.Add(int64(usage.Total))
+		a.reasoningTokens.Add(int64(usage.Reasoning))
 		a.callCount.Add(1)
 	}
 }
@@ -386,10 +479,58 @@ func (a *UsageAccumulator) AddUsage(usage *zyn.TokenUsage) {
 		a.promptTokens.Add(int64(usage.Prompt))
 		a.completionTokens.Add(int64(usage.Completion))
 		a.totalTokens.Add(int64(usage.Total))
+		a.reasoningTokens.Add(int64(usage.Reasoning))
 		a.callCount.Add(1)
 	}
 }
 
+// AddUsageFor accumulates usage consumed by model, counting it in the totals
+// and in the model's breakdown.
+func (a *UsageAccumulator) AddUsageFor(model string, usage *zyn.TokenUsage) {
+	if usage == nil {
+		return
+	}
+	a.AddUsage(usage)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.byModel == nil {
+		a.byModel = make(map[string]zyn.TokenUsage)
+	}
+	total := a.byModel[model]
+	total.Prompt += usage.Prompt
+	total.Completion += usage.Completion
+	total.Total += usage.Total
+	total.CachedPrompt += usage.CachedPrompt
+	total.Reasoning += usage.Reasoning
+	a.byModel[model] = total
+}
+
+// ByModel returns the usage of each model added with AddUsageFor.
+// The returned map is a copy.
+func (a *UsageAccumulator) ByModel() map[string]zyn.TokenUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	byModel := make(map[string]zyn.TokenUsage, len(a.byModel))
+	for model, usage := range a.byModel {
+		byModel[model] = usage
+	}
+	return byModel
+}
+
+// CostUSD returns the cost of the usage added with AddUsageFor at the given
+// prices. Usage added without a model, or for a model the table does not
+// price, is not included.
+func (a *UsageAccumulator) CostUSD(prices zyn.PriceTable) float64 {
+	var total float64
+	for model, usage := range a.ByModel() {
+		if cost, ok := prices.Cost(model, usage); ok {
+			total += cost
+		}
+	}
+	return total
+}
+
 // PromptTokens returns total prompt tokens.
 func (a *UsageAccumulator) PromptTokens() int {
 	return int(a.promptTokens.Load())
@@ -405,6 +546,12 @@ func (a *UsageAccumulator) TotalTokens() int {
 	return int(a.totalTokens.Load())
 }
 
+// ReasoningTokens returns total reasoning tokens, which are included in
+// CompletionTokens.
+func (a *UsageAccumulator) ReasoningTokens() int {
+	return int(a.reasoningTokens.Load())
+}
+
 // CallCount returns number of calls accumulated.
 func (a *UsageAccumulator) CallCount() int {
 	return int(a.callCount.Load())
@@ -415,5 +562,10 @@ func (a *UsageAccumulator) Reset() {
 	a.promptTokens.Store(0)
 	a.completionTokens.Store(0)
 	a.totalTokens.Store(0)
+	a.reasoningTokens.Store(0)
 	a.callCount.Store(0)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.byModel = nil
 }
@@ -3,6 +3,7 @@ package testing
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -34,6 +35,26 @@ func TestResponseBuilder_BinaryResponse(t *testing.T) {
 	}
 }
 
+func TestResponseBuilder_Unknown(t *testing.T) {
+	response := NewResponseBuilder().
+		WithUnknown().
+		WithConfidence(0.8).
+		WithReasoning("no information").
+		Build()
+
+	synapse, err := zyn.Binary("Is this spam?", zyn.NewMockProviderWithResponse(response), zyn.WithAbstain())
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	details, err := synapse.FireWithDetails(context.Background(), zyn.NewSession(), "see attached")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !details.Unknown || details.Decision {
+		t.Errorf("expected an unknown response, got %+v", details)
+	}
+}
+
 func TestResponseBuilder_ClassificationResponse(t *testing.T) {
 	response := NewResponseBuilder().
 		WithPrimary("spam").
@@ -55,6 +76,83 @@ func TestResponseBuilder_ClassificationResponse(t *testing.T) {
 	}
 }
 
+func TestResponseBuilder_Distribution(t *testing.T) {
+	response := NewResponseBuilder().
+		WithPrimary("spam").
+		WithDistribution(map[string]float64{"spam": 0.7, "ham": 0.3}).
+		Build()
+
+	var data struct {
+		Distribution map[string]float64 `json:"distribution"`
+	}
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if data.Distribution["spam"] != 0.7 || data.Distribution["ham"] != 0.3 {
+		t.Errorf("unexpected distribution: %v", data.Distribution)
+	}
+}
+
+func TestResponseBuilder_RankedScores(t *testing.T) {
+	response := NewResponseBuilder().
+		WithRanked("first", "second").
+		WithRankedScores(map[string]float64{"first": 0.9, "second": 0.4}).
+		Build()
+
+	var data struct {
+		Ranked []string           `json:"ranked"`
+		Scores map[string]float64 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(data.Ranked) != 2 || data.Scores["first"] != 0.9 || data.Scores["second"] != 0.4 {
+		t.Errorf("unexpected ranking: %v %v", data.Ranked, data.Scores)
+	}
+}
+
+func TestResponseBuilder_Justifications(t *testing.T) {
+	response := NewResponseBuilder().
+		WithRanked("first", "second").
+		WithJustifications(map[string]string{"first": "most urgent"}).
+		WithConfidence(0.9).
+		WithReasoning("by urgency").
+		Build()
+
+	var data zyn.RankingResponse
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if data.Justifications["first"] != "most urgent" {
+		t.Errorf("unexpected justifications: %v", data.Justifications)
+	}
+	if err := data.Validate(); err != nil {
+		t.Errorf("expected a valid ranking response, got %v", err)
+	}
+}
+
+func TestResponseBuilder_Findings(t *testing.T) {
+	response := NewResponseBuilder().
+		WithField("analysis", "two issues").
+		WithConfidence(0.8).
+		WithFindings(
+			zyn.Finding{Title: "plaintext passwords", Severity: zyn.SeverityHigh, Area: "auth", Field: "password", Recommendation: "hash them"},
+			zyn.Finding{Title: "verbose logging", Severity: zyn.SeverityLow},
+		).
+		Build()
+
+	var data zyn.AnalyzeResponse
+	if err := json.Unmarshal([]byte(response), &data); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if err := data.Validate(); err != nil {
+		t.Fatalf("built response failed validation: %v", err)
+	}
+	if len(data.Findings) != 2 || data.Findings[0].Area != "auth" || data.Findings[1].Severity != zyn.SeverityLow {
+		t.Errorf("unexpected findings: %+v", data.Findings)
+	}
+}
+
 func TestResponseBuilder_RankingResponse(t *testing.T) {
 	response := NewResponseBuilder().
 		WithRanked("first", "second", "third").
@@ -79,6 +177,7 @@ func TestResponseBuilder_RankingResponse(t *testing.T) {
 func TestResponseBuilder_SentimentResponse(t *testing.T) {
 	response := NewResponseBuilder().
 		WithOverall("positive").
+		WithIntensity(0.6).
 		WithConfidence(0.85).
 		WithScores(0.7, 0.1, 0.2).
 		WithEmotions("joy", "satisfaction").
@@ -94,6 +193,9 @@ func TestResponseBuilder_SentimentResponse(t *testing.T) {
 	if data["overall"] != "positive" {
 		t.Errorf("expected overall=positive, got %v", data["overall"])
 	}
+	if data["intensity"] != 0.6 {
+		t.Errorf("expected intensity=0.6, got %v", data["intensity"])
+	}
 
 	scores, ok := data["scores"].(map[string]any)
 	if !ok {
@@ -304,6 +406,52 @@ func TestCallRecorder_RecordsCalls(t *testing.T) {
 	}
 }
 
+func TestCallRecorder_RecordsRequestID(t *testing.T) {
+	recorder := NewCallRecorder(NewSequencedProvider(`{"ok": true}`))
+
+	ctx := zyn.ContextWithRequestID(context.Background(), "req-7")
+	if _, err := recorder.Call(ctx, nil, 0.5); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	if got := recorder.LastCall().RequestID; got != "req-7" {
+		t.Errorf("expected request ID req-7, got %q", got)
+	}
+}
+
+func TestCallRecorder_RecordsSeed(t *testing.T) {
+	recorder := NewCallRecorder(NewSequencedProvider(`{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`))
+	synapse, err := zyn.Binary("question", recorder, zyn.WithSeed(42))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("fire failed: %v", err)
+	}
+
+	if seed := recorder.LastCall().Params.Seed; seed == nil || *seed != 42 {
+		t.Errorf("expected seed 42, got %v", seed)
+	}
+}
+
+func TestCallRecorder_RecordsSamplingParams(t *testing.T) {
+	recorder := NewCallRecorder(NewSequencedProvider(`{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`))
+	synapse, err := zyn.Binary("question", recorder, zyn.WithMaxTokens(128), zyn.WithTopP(0.8), zyn.WithStopSequences("END"))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	if _, err := synapse.Fire(context.Background(), zyn.NewSession(), "input"); err != nil {
+		t.Fatalf("fire failed: %v", err)
+	}
+
+	params := recorder.LastCall().Params
+	if params.MaxTokens != 128 || params.TopP != 0.8 || len(params.Stop) != 1 || params.Stop[0] != "END" {
+		t.Errorf("unexpected sampling params: %+v", params)
+	}
+}
+
 func TestCallRecorder_LastCall(t *testing.T) {
 	inner := NewSequencedProvider(`{"ok": true}`)
 	recorder := NewCallRecorder(inner)
@@ -433,6 +581,28 @@ func TestUsageAccumulator_AccumulatesUsage(t *testing.T) {
 	}
 }
 
+func TestUsageAccumulator_ReasoningTokens(t *testing.T) {
+	acc := NewUsageAccumulator()
+
+	session := zyn.NewSession()
+	session.SetUsage(&zyn.TokenUsage{Prompt: 120, Completion: 900, Total: 1020, Reasoning: 832})
+	acc.Add(session)
+	acc.AddUsageFor("o4-mini", &zyn.TokenUsage{Prompt: 80, Completion: 300, Total: 380, Reasoning: 256})
+	acc.AddUsage(&zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15})
+
+	if acc.ReasoningTokens() != 1088 || acc.CompletionTokens() != 1205 {
+		t.Errorf("expected 1088 of 1205 completion tokens spent reasoning, got %d of %d", acc.ReasoningTokens(), acc.CompletionTokens())
+	}
+	if got := acc.ByModel()["o4-mini"].Reasoning; got != 256 {
+		t.Errorf("expected 256 reasoning tokens for o4-mini, got %d", got)
+	}
+
+	acc.Reset()
+	if acc.ReasoningTokens() != 0 {
+		t.Errorf("expected 0 reasoning tokens after reset, got %d", acc.ReasoningTokens())
+	}
+}
+
 func TestUsageAccumulator_AddFromSession(t *testing.T) {
 	acc := NewUsageAccumulator()
 
@@ -483,6 +653,78 @@ func TestUsageAccumulator_ConcurrentSafety(t *testing.T) {
 	}
 }
 
+func TestUsageAccumulator_ByModel(t *testing.T) {
+	acc := NewUsageAccumulator()
+	acc.AddUsageFor("gpt-4o", &zyn.TokenUsage{Prompt: 100, Completion: 50, Total: 150})
+	acc.AddUsageFor("gpt-4o", &zyn.TokenUsage{Prompt: 200, Completion: 100, Total: 300, CachedPrompt: 50})
+	acc.AddUsageFor("claude-3-5-haiku", &zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15})
+	acc.AddUsage(&zyn.TokenUsage{Prompt: 1, Completion: 1, Total: 2})
+
+	byModel := acc.ByModel()
+	if len(byModel) != 2 {
+		t.Fatalf("expected 2 models, got %v", byModel)
+	}
+	if got := byModel["gpt-4o"]; got != (zyn.TokenUsage{Prompt: 300, Completion: 150, Total: 450, CachedPrompt: 50}) {
+		t.Errorf("unexpected gpt-4o usage: %+v", got)
+	}
+	if acc.TotalTokens() != 467 || acc.CallCount() != 4 {
+		t.Errorf("expected totals across all calls, got %d tokens over %d calls", acc.TotalTokens(), acc.CallCount())
+	}
+
+	byModel["gpt-4o"] = zyn.TokenUsage{}
+	if acc.ByModel()["gpt-4o"].Total != 450 {
+		t.Error("expected ByModel to return a copy")
+	}
+
+	acc.Reset()
+	if len(acc.ByModel()) != 0 {
+		t.Error("expected no models after reset")
+	}
+}
+
+func TestUsageAccumulator_CostUSD(t *testing.T) {
+	prices := zyn.PriceTable{
+		"model-a": {Prompt: 2, Completion: 10, CachedPrompt: 1},
+		"model-b": {Prompt: 1, Completion: 4},
+	}
+
+	acc := NewUsageAccumulator()
+	acc.AddUsageFor("model-a-2025-01-01", &zyn.TokenUsage{Prompt: 1_000_000, Completion: 100_000, Total: 1_100_000, CachedPrompt: 500_000})
+	acc.AddUsageFor("model-b", &zyn.TokenUsage{Prompt: 500_000, Completion: 250_000, Total: 750_000})
+	acc.AddUsageFor("unpriced", &zyn.TokenUsage{Prompt: 1_000_000, Completion: 1_000_000, Total: 2_000_000})
+
+	// model-a: 500k at $2/M + 500k cached at $1/M + 100k at $10/M = $2.50
+	// model-b: 500k at $1/M + 250k at $4/M = $1.50
+	if cost := acc.CostUSD(prices); cost < 3.999999 || cost > 4.000001 {
+		t.Errorf("expected $4.00, got $%f", cost)
+	}
+}
+
+func TestUsageAccumulator_ConcurrentByModel(t *testing.T) {
+	acc := NewUsageAccumulator()
+	models := []string{"model-a", "model-b", "model-c"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(1)
+		go func(model string) {
+			defer wg.Done()
+			acc.AddUsageFor(model, &zyn.TokenUsage{Prompt: 10, Completion: 5, Total: 15})
+			_ = acc.ByModel()
+		}(models[i%len(models)])
+	}
+	wg.Wait()
+
+	for _, model := range models {
+		if got := acc.ByModel()[model].Total; got != 1500 {
+			t.Errorf("expected 1500 tokens for %s, got %d", model, got)
+		}
+	}
+	if acc.CallCount() != 300 || acc.TotalTokens() != 4500 {
+		t.Errorf("expected 300 calls and 4500 tokens, got %d and %d", acc.CallCount(), acc.TotalTokens())
+	}
+}
+
 func TestResponseBuilder_BuildBytes(t *testing.T) {
 	t.Run("valid_response", func(t *testing.T) {
 		bytes := NewResponseBuilder().
@@ -560,6 +802,43 @@ func TestLatencyProvider_Name(t *testing.T) {
 	}
 }
 
+func TestWrappers_Close(t *testing.T) {
+	inner := zyn.NewMockProviderWithName("inner")
+	recorder := NewCallRecorder(NewLatencyProvider(inner, time.Millisecond))
+
+	if err := zyn.CloseProviders(recorder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !inner.Closed() {
+		t.Error("expected Close forwarded through both wrappers")
+	}
+	if _, err := recorder.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "hello"}}, 0.3); !errors.Is(err, zyn.ErrProviderClosed) {
+		t.Errorf("expected ErrProviderClosed, got %v", err)
+	}
+	if err := NewCallRecorder(NewSequencedProvider(`{"ok": true}`)).Close(); err != nil {
+		t.Errorf("expected no error closing a provider without Close, got %v", err)
+	}
+}
+
+func TestMiddleware_Composes(t *testing.T) {
+	recorder := NewCallRecorder(zyn.NewMockProvider())
+	provider := zyn.WrapProvider(NewSequencedProvider(`{"ok": true}`), recorder.Middleware(), Latency(20*time.Millisecond))
+
+	start := time.Now()
+	if _, err := provider.Call(context.Background(), []zyn.Message{{Role: zyn.RoleUser, Content: "hello"}}, 0.3); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms latency, got %v", elapsed)
+	}
+	if recorder.CallCount() != 1 || recorder.LastCall().Messages[0].Content != "hello" {
+		t.Errorf("expected the call recorded, got %+v", recorder.Calls())
+	}
+	if provider.Name() != SequencedProviderName {
+		t.Errorf("expected name='%s', got '%s'", SequencedProviderName, provider.Name())
+	}
+}
+
 func TestSequencedProvider_EmptyResponses(t *testing.T) {
 	// Test with no responses - should use default error response
 	provider := NewSequencedProvider()
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zoobzio/zyn"
+	zynt "github.com/zoobzio/zyn/testing"
+)
+
+type ticket struct {
+	Customer string `json:"customer"`
+	Issue    string `json:"issue"`
+}
+
+func (t ticket) Validate() error {
+	if t.Issue == "" {
+		return fmt.Errorf("issue required")
+	}
+	return nil
+}
+
+func buildSupportChain(t *testing.T, provider zyn.Provider) *zyn.SynapseChain {
+	t.Helper()
+
+	classifier, err := zyn.Classification("What kind of message is this?", []string{"support", "sales"}, provider)
+	if err != nil {
+		t.Fatalf("failed to create classifier: %v", err)
+	}
+	extractor, err := zyn.Extract[ticket]("support ticket", provider)
+	if err != nil {
+		t.Fatalf("failed to create extractor: %v", err)
+	}
+	summarizer, err := zyn.Transform("summarize the ticket", provider)
+	if err != nil {
+		t.Fatalf("failed to create transformer: %v", err)
+	}
+
+	const email = "Hi, I cannot log in. - Ada"
+	chain, err := zyn.Chain(
+		zyn.Step("classify", classifier.FireWithDetails),
+		zyn.StepWith("extract",
+			func(zyn.ClassificationResponse) (string, error) { return email, nil },
+			extractor.Fire,
+		),
+		zyn.StepWith("summarize",
+			func(tk ticket) (string, error) { return tk.Customer + ": " + tk.Issue, nil },
+			summarizer.Fire,
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to build chain: %v", err)
+	}
+	return chain
+}
+
+func TestChain_ThreeSteps(t *testing.T) {
+	provider := zynt.NewSequencedProvider(
+		zynt.NewResponseBuilder().
+			WithPrimary("support").
+			WithSecondary("").
+			WithConfidence(0.9).
+			WithReasoning("asks for help").
+			Build(),
+		zynt.NewResponseBuilder().
+			WithField("customer", "Ada").
+			WithField("issue", "cannot log in").
+			Build(),
+		zynt.NewResponseBuilder().
+			WithOutput("Ada cannot log in").
+			WithConfidence(0.8).
+			WithChanges("condensed").
+			WithReasoning("summary").
+			Build(),
+	)
+	chain := buildSupportChain(t, provider)
+	session := zyn.NewSession()
+
+	result, err := chain.Run(context.Background(), session, "Hi, I cannot log in. - Ada")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if provider.CallCount() != 3 {
+		t.Errorf("expected 3 provider calls, got %d", provider.CallCount())
+	}
+	if result.Final() != "Ada cannot log in" {
+		t.Errorf("unexpected final output: %v", result.Final())
+	}
+	if tk, ok := zyn.ChainOutput[ticket](result, "extract"); !ok || tk.Customer != "Ada" {
+		t.Errorf("unexpected extract output: %+v", tk)
+	}
+	if result.Usage.Total != 450 {
+		t.Errorf("expected 450 aggregated tokens, got %d", result.Usage.Total)
+	}
+	if session.Len() != 6 {
+		t.Errorf("expected 6 session messages, got %d", session.Len())
+	}
+}
+
+func TestChain_FailurePreservesEarlierResults(t *testing.T) {
+	// The extraction response is missing its required issue field
+	provider := zynt.NewSequencedProvider(
+		zynt.NewResponseBuilder().
+			WithPrimary("support").
+			WithSecondary("").
+			WithConfidence(0.9).
+			WithReasoning("asks for help").
+			Build(),
+		zynt.NewResponseBuilder().
+			WithField("customer", "Ada").
+			Build(),
+	)
+	chain := buildSupportChain(t, provider)
+
+	result, err := chain.Run(context.Background(), zyn.NewSession(), "Hi, I cannot log in. - Ada")
+	var stepErr *zyn.ChainStepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("expected ChainStepError, got %v", err)
+	}
+	if stepErr.Step != "extract" || stepErr.Index != 1 {
+		t.Errorf("expected extract step failure, got %d (%s)", stepErr.Index, stepErr.Step)
+	}
+	if len(result.Outputs) != 1 || result.Outputs[0].Name != "classify" {
+		t.Errorf("expected classify output preserved, got %+v", result.Outputs)
+	}
+}
@@ -350,3 +350,37 @@ func TestConcurrency_UsageAccumulation(t *testing.T) {
 		t.Errorf("expected %d total tokens, got %d", expectedTokens, acc.TotalTokens())
 	}
 }
+
+func TestConcurrency_CachingProvider(t *testing.T) {
+	// Identical calls from many goroutines reach the provider once
+	recorder := zynt.NewCallRecorder(zyn.NewMockProvider())
+	cached := zyn.NewCachingProvider(recorder, zyn.CacheConfig{MaxEntries: 10})
+	synapse, err := zyn.Binary("Is this a duplicate ticket?", cached)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := synapse.Fire(ctx, zyn.NewSession(), "printer offline"); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if recorder.CallCount() != 1 {
+		t.Fatalf("expected 1 provider call, got %d", recorder.CallCount())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := synapse.Fire(ctx, zyn.NewSession(), "printer offline"); err != nil {
+				t.Errorf("call failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if recorder.CallCount() != 1 {
+		t.Errorf("expected cached calls to skip the provider, got %d calls", recorder.CallCount())
+	}
+}
@@ -177,7 +177,7 @@ func TestEdgeCase_PartiallyValidResponse(t *testing.T) {
 }
 
 func TestEdgeCase_ExtraFieldsInResponse(t *testing.T) {
-	// Response has extra fields not in schema - should be ignored
+	// Response has extra fields not in schema - ignored by default, rejected in strict mode
 	response := `{
 		"decision": true,
 		"confidence": 0.9,
@@ -186,19 +186,39 @@ func TestEdgeCase_ExtraFieldsInResponse(t *testing.T) {
 		"another_extra": 123
 	}`
 
-	provider := zynt.NewSequencedProvider(response)
-	synapse, _ := zyn.Binary("question", provider)
-	session := zyn.NewSession()
-	ctx := context.Background()
+	t.Run("lenient", func(t *testing.T) {
+		provider := zynt.NewSequencedProvider(response)
+		synapse, _ := zyn.Binary("question", provider)
+		session := zyn.NewSession()
+		ctx := context.Background()
 
-	result, err := synapse.Fire(ctx, session, "input")
-	if err != nil {
-		t.Fatalf("extra fields should not cause error: %v", err)
-	}
+		result, err := synapse.Fire(ctx, session, "input")
+		if err != nil {
+			t.Fatalf("extra fields should not cause error: %v", err)
+		}
 
-	if !result {
-		t.Error("expected true result")
-	}
+		if !result {
+			t.Error("expected true result")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		provider := zynt.NewSequencedProvider(response)
+		synapse, _ := zyn.Binary("question", provider, zyn.WithStrictSchema())
+		session := zyn.NewSession()
+		ctx := context.Background()
+
+		_, err := synapse.Fire(ctx, session, "input")
+		if err == nil {
+			t.Fatal("extra fields should cause error in strict mode")
+		}
+		if !strings.Contains(err.Error(), "another_extra, extra_field") {
+			t.Errorf("error should list extra fields, got: %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("session should be untouched on failure, got %d messages", session.Len())
+		}
+	})
 }
 
 func TestEdgeCase_NullFieldsInResponse(t *testing.T) {
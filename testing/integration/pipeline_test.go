@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -105,6 +106,40 @@ func TestPipeline_Timeout(t *testing.T) {
 	}
 }
 
+func TestPipeline_MinRemainingDeadline(t *testing.T) {
+	// Provider that takes 800ms per call, behind a recorder counting calls
+	recorder := zynt.NewCallRecorder(zynt.NewLatencyProvider(
+		zynt.NewSequencedProvider(
+			zynt.NewResponseBuilder().WithDecision(true).WithConfidence(0.9).WithReasoning("slow").Build(),
+		),
+		800*time.Millisecond,
+	))
+
+	synapse, err := zyn.Binary("question", recorder,
+		zyn.WithMinRemainingDeadline(800*time.Millisecond), zyn.WithRetry(3))
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	// Only 50ms left: the call could never finish in time
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = synapse.Fire(ctx, zyn.NewSession(), "input")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, zyn.ErrInsufficientDeadline) {
+		t.Fatalf("expected ErrInsufficientDeadline, got %v", err)
+	}
+	if recorder.CallCount() != 0 {
+		t.Errorf("expected no provider calls, got %d", recorder.CallCount())
+	}
+	if elapsed > 25*time.Millisecond {
+		t.Errorf("expected to fail immediately, took %v", elapsed)
+	}
+}
+
 func TestPipeline_TimeoutSuccess(t *testing.T) {
 	// Provider that responds quickly
 	fastProvider := zynt.NewLatencyProvider(
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zoobzio/zyn"
+	zynt "github.com/zoobzio/zyn/testing"
+)
+
+// dbConfig is the analyzed and converted type of the prompt tests.
+type dbConfig struct {
+	MaxConnections int `json:"max_connections"`
+}
+
+func (dbConfig) Validate() error { return nil }
+
+func TestPrompt_AnalyzeConstraints(t *testing.T) {
+	recorder := zynt.NewCallRecorder(zynt.NewSequencedProvider(
+		`{"analysis": "pool is small", "confidence": 0.8, "findings": [], "reasoning": ["checked the pool"]}`,
+	))
+	synapse, err := zyn.Analyze[dbConfig]("database config", recorder)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	synapse.WithDefaults(zyn.AnalyzeInput[dbConfig]{Constraints: []string{"cite each setting by name"}})
+
+	_, err = synapse.FireWithInput(context.Background(), zyn.NewSession(), zyn.AnalyzeInput[dbConfig]{
+		Data:        dbConfig{MaxConnections: 200},
+		Constraints: []string{"never recommend increasing max_connections above 500"},
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	message := recorder.LastCall().Messages[0].Content
+	want := "- cite each setting by name\n- never recommend increasing max_connections above 500"
+	if !strings.Contains(message, want) {
+		t.Errorf("expected the constraints in the provider call, got %s", message)
+	}
+}
+
+func TestPrompt_ConvertConstraints(t *testing.T) {
+	recorder := zynt.NewCallRecorder(zynt.NewSequencedProvider(`{"max_connections": 200}`))
+	synapse, err := zyn.Convert[dbConfig, dbConfig]("normalize the config", recorder)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	_, err = synapse.FireWithInput(context.Background(), zyn.NewSession(), zyn.ConvertInput[dbConfig, dbConfig]{
+		Data:        dbConfig{MaxConnections: 200},
+		Constraints: []string{"keep max_connections at or below 500"},
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+
+	message := recorder.LastCall().Messages[0].Content
+	if !strings.Contains(message, "- keep max_connections at or below 500") {
+		t.Errorf("expected the constraint in the provider call, got %s", message)
+	}
+}
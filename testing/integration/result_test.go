@@ -0,0 +1,55 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zoobzio/zyn"
+	zynt "github.com/zoobzio/zyn/testing"
+)
+
+func TestResult_MatchesRecordedCalls(t *testing.T) {
+	response := zynt.NewResponseBuilder().
+		WithDecision(true).
+		WithConfidence(0.9).
+		WithReasoning("valid").
+		Build()
+	recorder := zynt.NewCallRecorder(zynt.NewSequencedProvider(response))
+
+	synapse, err := zyn.Binary("the input is valid", recorder)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	session := zyn.NewSession()
+	accumulator := zynt.NewUsageAccumulator()
+	for i := 0; i < 3; i++ {
+		result, err := synapse.FireResult(context.Background(), session, "input")
+		if err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+
+		call := recorder.LastCall()
+		if result.RequestID == "" || result.RequestID != call.RequestID {
+			t.Errorf("call %d: expected envelope request ID %q to match provider call, got %q", i, call.RequestID, result.RequestID)
+		}
+		if result.Attempts != 1 {
+			t.Errorf("call %d: expected 1 attempt, got %d", i, result.Attempts)
+		}
+		if string(result.Raw) != response {
+			t.Errorf("call %d: expected raw response, got %s", i, result.Raw)
+		}
+		accumulator.AddUsage(result.Usage)
+	}
+
+	if recorder.CallCount() != 3 {
+		t.Errorf("expected 3 provider calls, got %d", recorder.CallCount())
+	}
+	// SequencedProvider reports 100 prompt and 50 completion tokens per call
+	if accumulator.PromptTokens() != 300 || accumulator.CompletionTokens() != 150 {
+		t.Errorf("expected envelope usage to sum to 300/150, got %d/%d", accumulator.PromptTokens(), accumulator.CompletionTokens())
+	}
+	if total := session.TotalUsage(); total.Prompt != accumulator.PromptTokens() {
+		t.Errorf("expected envelope usage to match session total %d, got %d", total.Prompt, accumulator.PromptTokens())
+	}
+}
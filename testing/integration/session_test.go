@@ -287,3 +287,42 @@ func TestSession_HeterogeneousSynapses(t *testing.T) {
 		t.Errorf("expected 6 messages from 3 calls, got %d", session.Len())
 	}
 }
+
+func TestSession_AnalyzeAndConvert(t *testing.T) {
+	analysis := `{"analysis": "the pool is oversized", "confidence": 0.9, "findings": [], "reasoning": ["checked the pool"]}`
+	recorder := zynt.NewCallRecorder(zynt.NewSequencedProvider(analysis, analysis, `{"max_connections": 500}`, `{"max_connections": "invalid"}`))
+	session := zyn.NewSession()
+	ctx := context.Background()
+
+	analyzer, _ := zyn.Analyze[dbConfig]("server config", recorder)
+	if _, err := analyzer.Fire(ctx, session, dbConfig{MaxConnections: 900}); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if session.Len() != 2 || session.LastUsage() == nil || session.LastUsage().Total != 150 {
+		t.Fatalf("expected the analysis enrolled with its usage, got %d messages and %+v", session.Len(), session.LastUsage())
+	}
+
+	// A follow-up sees the earlier exchange
+	if _, err := analyzer.FireWithInput(ctx, session, zyn.AnalyzeInput[dbConfig]{Data: dbConfig{MaxConnections: 900}, Focus: "now focus on security"}); err != nil {
+		t.Fatalf("follow-up failed: %v", err)
+	}
+	if messages := recorder.LastCall().Messages; len(messages) != 3 || messages[1].Role != zyn.RoleAssistant {
+		t.Errorf("expected the follow-up to carry the first exchange, got %d messages", len(messages))
+	}
+
+	converter, _ := zyn.Convert[dbConfig, dbConfig]("harden the config", recorder)
+	if _, err := converter.Fire(ctx, session, dbConfig{MaxConnections: 900}); err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
+	if session.Len() != 6 || session.TotalUsage().Total != 450 {
+		t.Errorf("expected three exchanges and their usage, got %d messages and %+v", session.Len(), session.TotalUsage())
+	}
+
+	// A failed call leaves the session as it was
+	if _, err := converter.FireWithInput(ctx, session, zyn.ConvertInput[dbConfig, dbConfig]{Data: dbConfig{MaxConnections: 900}}); err == nil {
+		t.Fatal("expected the invalid response to fail")
+	}
+	if session.Len() != 6 {
+		t.Errorf("expected the failed call not enrolled, got %d messages", session.Len())
+	}
+}
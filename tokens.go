@@ -0,0 +1,122 @@
+package zyn
+
+import (
+	"context"
+
+	"github.com/zoobzio/capitan"
+	"github.com/zoobzio/pipz"
+)
+
+// Identities for prompt size options.
+var (
+	maxPromptTokensID    = pipz.NewIdentity("zyn:max-prompt-tokens", "Rejects prompts above a token limit")
+	promptTokenWarningID = pipz.NewIdentity("zyn:prompt-token-warning", "Warns on prompts above a token threshold")
+)
+
+// Heuristic estimation constants.
+const (
+	// charsPerToken approximates the characters per token of English text
+	// for common BPE tokenizers.
+	charsPerToken = 4
+
+	// messageOverheadTokens approximates the per-message framing tokens
+	// (role markers and separators) added by chat formats.
+	messageOverheadTokens = 4
+
+	// defaultWarningRatio is the fraction of the token limit above which a
+	// PromptSizeWarning is emitted when no explicit threshold is configured.
+	defaultWarningRatio = 0.8
+)
+
+// TokenEstimator estimates the token count of a set of messages.
+// Providers may implement it to supply tokenizer-accurate counts;
+// HeuristicEstimator is used otherwise.
+type TokenEstimator interface {
+	EstimateMessages(messages []Message) int
+}
+
+// HeuristicEstimator estimates tokens as one token per four characters plus
+// a small per-message overhead. It is deliberately approximate and errs
+// towards overestimating for non-English text.
+type HeuristicEstimator struct{}
+
+// EstimateMessages implements TokenEstimator.
+func (HeuristicEstimator) EstimateMessages(messages []Message) int {
+	total := 0
+	for _, msg := range messages {
+		total += (len(msg.Content)+charsPerToken-1)/charsPerToken + messageOverheadTokens
+	}
+	return total
+}
+
+// estimatorFor returns the provider's estimator when it implements
+// TokenEstimator, or the heuristic estimator otherwise.
+func estimatorFor(provider Provider) TokenEstimator {
+	if estimator, ok := provider.(TokenEstimator); ok {
+		return estimator
+	}
+	return HeuristicEstimator{}
+}
+
+// WithMaxPromptTokens sets a hard limit on the estimated prompt size,
+// including the rendered prompt, schema, and session history. Requests above
+// the limit fail with a *PromptTooLargeError (matching ErrPromptTooLarge)
+// before the provider is called. Without this option the provider's
+// advertised MaxContextTokens is used, if any.
+func WithMaxPromptTokens(limit int) Option {
+	return withRequest(maxPromptTokensID, func(req *SynapseRequest) {
+		req.MaxPromptTokens = limit
+	})
+}
+
+// WithPromptTokenWarning sets the estimated prompt size above which a
+// PromptSizeWarning hook event is emitted. The default threshold is 80% of
+// the effective token limit.
+func WithPromptTokenWarning(threshold int) Option {
+	return withRequest(promptTokenWarningID, func(req *SynapseRequest) {
+		req.PromptTokenWarning = threshold
+	})
+}
+
+// checkPromptSize estimates the size of messages and enforces the request's
+// token limits. It records the estimate on the request, emits
+// PromptSizeWarning above the soft threshold, and returns a
+// *PromptTooLargeError above the hard limit.
+func checkPromptSize(ctx context.Context, provider Provider, req *SynapseRequest, messages []Message) error {
+	limit := req.MaxPromptTokens
+	if limit <= 0 {
+		if capable, ok := provider.(CapabilitiesProvider); ok {
+			limit = capable.Capabilities().MaxContextTokens
+		}
+	}
+
+	threshold := req.PromptTokenWarning
+	if threshold <= 0 && limit > 0 {
+		threshold = int(float64(limit) * defaultWarningRatio)
+	}
+
+	// Nothing to enforce
+	if limit <= 0 && threshold <= 0 {
+		return nil
+	}
+
+	estimated := estimatorFor(provider).EstimateMessages(messages)
+	req.EstimatedPromptTokens = estimated
+
+	if limit > 0 && estimated > limit {
+		return &PromptTooLargeError{Estimated: estimated, Limit: limit}
+	}
+
+	if threshold > 0 && estimated > threshold {
+		capitan.Warn(ctx, PromptSizeWarning, HookFields(ctx,
+			RequestIDKey.Field(req.RequestID),
+			SynapseTypeKey.Field(req.SynapseType),
+			ProviderKey.Field(req.ProviderName),
+			EstimatedTokensKey.Field(estimated),
+			TokenLimitKey.Field(limit),
+			TokenThresholdKey.Field(threshold),
+		)...)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/capitan"
+)
+
+const tokensTestResponse = `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`
+
+// limitedProvider advertises a context window and counts calls.
+type limitedProvider struct {
+	maxContext int
+	calls      atomic.Int32
+}
+
+func (p *limitedProvider) Call(_ context.Context, _ []Message, _ float32) (*ProviderResponse, error) {
+	p.calls.Add(1)
+	return &ProviderResponse{Content: tokensTestResponse}, nil
+}
+
+func (*limitedProvider) Name() string { return "limited" }
+
+func (p *limitedProvider) Capabilities() Capabilities {
+	return Capabilities{MaxContextTokens: p.maxContext}
+}
+
+// countingEstimator is a provider-supplied estimator returning a fixed count.
+type countingEstimator struct {
+	limitedProvider
+	tokens int
+}
+
+func (e *countingEstimator) EstimateMessages(_ []Message) int { return e.tokens }
+
+// longSession returns a session with a few large exchanges.
+func longSession() *Session {
+	session := NewSession()
+	for i := 0; i < 5; i++ {
+		session.Append(RoleUser, strings.Repeat("question ", 200))
+		session.Append(RoleAssistant, strings.Repeat("answer ", 200))
+	}
+	return session
+}
+
+func TestHeuristicEstimator(t *testing.T) {
+	estimator := HeuristicEstimator{}
+
+	if got := estimator.EstimateMessages(nil); got != 0 {
+		t.Errorf("expected 0 tokens for no messages, got %d", got)
+	}
+
+	messages := []Message{
+		{Role: RoleUser, Content: strings.Repeat("a", 400)},
+		{Role: RoleAssistant, Content: "abc"},
+	}
+	// 100 + 4 overhead, 1 + 4 overhead
+	if got := estimator.EstimateMessages(messages); got != 109 {
+		t.Errorf("expected 109 tokens, got %d", got)
+	}
+}
+
+func TestEstimatorFor(t *testing.T) {
+	if _, ok := estimatorFor(NewMockProvider()).(HeuristicEstimator); !ok {
+		t.Error("expected heuristic estimator for provider without one")
+	}
+
+	provider := &countingEstimator{tokens: 42}
+	if got := estimatorFor(provider).EstimateMessages(nil); got != 42 {
+		t.Errorf("expected provider estimator, got %d", got)
+	}
+}
+
+func TestWithMaxPromptTokens(t *testing.T) {
+	t.Run("rejects before provider call", func(t *testing.T) {
+		var calls atomic.Int32
+		provider := NewMockProviderWithCallback(func(_ string, _ float32) (string, error) {
+			calls.Add(1)
+			return tokensTestResponse, nil
+		})
+
+		synapse, err := Binary("question", provider, WithMaxPromptTokens(500))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := longSession()
+		_, err = synapse.Fire(context.Background(), session, "input")
+		if !errors.Is(err, ErrPromptTooLarge) {
+			t.Fatalf("expected ErrPromptTooLarge, got %v", err)
+		}
+
+		var tooLarge *PromptTooLargeError
+		if !errors.As(err, &tooLarge) {
+			t.Fatalf("expected *PromptTooLargeError, got %T", err)
+		}
+		if tooLarge.Limit != 500 {
+			t.Errorf("expected limit 500, got %d", tooLarge.Limit)
+		}
+		if tooLarge.Estimated <= 500 {
+			t.Errorf("expected estimate above limit, got %d", tooLarge.Estimated)
+		}
+		if calls.Load() != 0 {
+			t.Errorf("expected no provider calls, got %d", calls.Load())
+		}
+		if session.Len() != 10 {
+			t.Errorf("expected session unchanged, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("allows prompts within limit", func(t *testing.T) {
+		synapse, err := Binary("question", NewMockProviderWithResponse(tokensTestResponse), WithMaxPromptTokens(100000))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), longSession(), "input"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("uses provider capabilities", func(t *testing.T) {
+		provider := &limitedProvider{maxContext: 500}
+		synapse, err := Binary("question", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		_, err = synapse.Fire(context.Background(), longSession(), "input")
+		if !errors.Is(err, ErrPromptTooLarge) {
+			t.Fatalf("expected ErrPromptTooLarge, got %v", err)
+		}
+		if provider.calls.Load() != 0 {
+			t.Errorf("expected no provider calls, got %d", provider.calls.Load())
+		}
+	})
+
+	t.Run("option overrides capabilities", func(t *testing.T) {
+		provider := &limitedProvider{maxContext: 500}
+		synapse, err := Binary("question", provider, WithMaxPromptTokens(100000))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), longSession(), "input"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("uses provider estimator", func(t *testing.T) {
+		provider := &countingEstimator{tokens: 600}
+		synapse, err := Binary("question", provider, WithMaxPromptTokens(500))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		_, err = synapse.Fire(context.Background(), NewSession(), "input")
+		var tooLarge *PromptTooLargeError
+		if !errors.As(err, &tooLarge) || tooLarge.Estimated != 600 {
+			t.Errorf("expected rejection with provider estimate 600, got %v", err)
+		}
+	})
+}
+
+func TestPromptSizeWarningHook(t *testing.T) {
+	received := make(chan *capitan.Event, 1)
+	listener := capitan.Hook(PromptSizeWarning, func(_ context.Context, e *capitan.Event) {
+		select {
+		case received <- e:
+		default:
+		}
+	})
+	defer listener.Close()
+
+	synapse, err := Binary("question", NewMockProviderWithResponse(tokensTestResponse),
+		WithMaxPromptTokens(100000),
+		WithPromptTokenWarning(500),
+	)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	if _, err := synapse.Fire(context.Background(), longSession(), "input"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case e := <-received:
+		estimated, _ := EstimatedTokensKey.From(e)
+		limit, _ := TokenLimitKey.From(e)
+		threshold, _ := TokenThresholdKey.From(e)
+		if estimated <= 500 {
+			t.Errorf("expected estimate above threshold, got %d", estimated)
+		}
+		if limit != 100000 {
+			t.Errorf("expected limit 100000, got %d", limit)
+		}
+		if threshold != 500 {
+			t.Errorf("expected threshold 500, got %d", threshold)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for prompt size warning")
+	}
+}
+
+func TestCheckPromptSize(t *testing.T) {
+	messages := []Message{{Role: RoleUser, Content: strings.Repeat("a", 400)}}
+
+	t.Run("no limits skips estimation", func(t *testing.T) {
+		req := &SynapseRequest{}
+		if err := checkPromptSize(context.Background(), NewMockProvider(), req, messages); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if req.EstimatedPromptTokens != 0 {
+			t.Errorf("expected no estimate, got %d", req.EstimatedPromptTokens)
+		}
+	})
+
+	t.Run("records estimate", func(t *testing.T) {
+		req := &SynapseRequest{MaxPromptTokens: 1000}
+		if err := checkPromptSize(context.Background(), NewMockProvider(), req, messages); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if req.EstimatedPromptTokens != 104 {
+			t.Errorf("expected estimate 104, got %d", req.EstimatedPromptTokens)
+		}
+	})
+
+	t.Run("limit is inclusive", func(t *testing.T) {
+		req := &SynapseRequest{MaxPromptTokens: 104}
+		if err := checkPromptSize(context.Background(), NewMockProvider(), req, messages); err != nil {
+			t.Errorf("unexpected error at exact limit: %v", err)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/zoobzio/pipz"
+)
+
+// toolsID identifies the tools option.
+var toolsID = pipz.NewIdentity("zyn:tools", "Offers tools the model may call")
+
+// Tool describes a function the model may call instead of answering.
+type Tool struct {
+	Name        string          // Function name, e.g. "lookup_order"
+	Description string          // What the tool does and when to use it
+	Parameters  json.RawMessage // JSON schema of the arguments object
+}
+
+// ToolCall is a call to a Tool requested by the model.
+type ToolCall struct {
+	ID        string          // Provider-assigned ID, echoed by the tool result
+	Name      string          // Name of the tool to call
+	Arguments json.RawMessage // Arguments as a JSON object
+}
+
+// ToolProvider is an optional interface for providers that support tool
+// calling. Requests with tools are sent through CallWithTools, and the
+// provider returns requested calls in ProviderResponse.ToolCalls. Requests
+// with tools sent to other providers fail with a *ToolsUnsupportedError.
+type ToolProvider interface {
+	CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error)
+}
+
+// WithTools offers tools to the model on every call. When the model calls
+// tools instead of answering, Fire fails with a *ToolCallsError holding the
+// calls (FireResult also sets Result.ToolCalls), and the session records the
+// prompt and the assistant's calls. Run the tools, add each result with
+// Session.AppendToolResult, and fire again to get the answer.
+//
+// Tools stack across WithTools options; a name is offered once. Providers
+// that do not implement ToolProvider fail the call with a
+// *ToolsUnsupportedError before it is sent.
+//
+// Example:
+//
+//	synapse, _ := zyn.Binary("Has the order shipped?", provider, zyn.WithTools(lookupOrder))
+//	for {
+//	    shipped, err := synapse.Fire(ctx, session, question)
+//	    var calls *zyn.ToolCallsError
+//	    if !errors.As(err, &calls) {
+//	        return shipped, err
+//	    }
+//	    for _, call := range calls.Calls {
+//	        session.AppendToolResult(call.ID, runTool(call))
+//	    }
+//	}
+func WithTools(tools ...Tool) Option {
+	return withRequest(toolsID, func(req *SynapseRequest) {
+		for _, tool := range tools {
+			if !slices.ContainsFunc(req.Tools, func(t Tool) bool { return t.Name == tool.Name }) {
+				req.Tools = append(req.Tools, tool)
+			}
+		}
+	})
+}
+
+// checkTools returns a *ToolsUnsupportedError when the request offers tools
+// the provider cannot pass on.
+func checkTools(provider Provider, req *SynapseRequest) error {
+	if len(req.Tools) == 0 {
+		return nil
+	}
+	if _, ok := provider.(ToolProvider); ok {
+		return nil
+	}
+	return &ToolsUnsupportedError{Provider: provider.Name(), Tools: len(req.Tools)}
+}
+
+// callProvider calls the provider, through CallWithTools when the request
+// offers tools.
+func callProvider(ctx context.Context, provider Provider, req *SynapseRequest, messages []Message) (*ProviderResponse, error) {
+	if len(req.Tools) > 0 {
+		return provider.(ToolProvider).CallWithTools(ctx, messages, req.Temperature, req.Tools)
+	}
+	return provider.Call(ctx, messages, req.Temperature)
+}
@@ -0,0 +1,113 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// toolProvider is a MockProvider that calls a tool until a tool result is in
+// the conversation, then answers.
+type toolProvider struct {
+	*MockProvider
+	tools [][]Tool // Tools offered on each call
+}
+
+func (p *toolProvider) CallWithTools(ctx context.Context, messages []Message, temperature float32, tools []Tool) (*ProviderResponse, error) {
+	p.tools = append(p.tools, tools)
+	for _, msg := range messages {
+		if msg.Role == RoleTool {
+			return p.MockProvider.Call(ctx, messages, temperature)
+		}
+	}
+	return &ProviderResponse{
+		ToolCalls: []ToolCall{{ID: "call-1", Name: "lookup_order", Arguments: json.RawMessage(`{"id":"A-1"}`)}},
+		Usage:     TokenUsage{Prompt: 40, Completion: 10, Total: 50},
+	}, nil
+}
+
+var lookupOrder = Tool{
+	Name:        "lookup_order",
+	Description: "Look up an order by ID",
+	Parameters:  json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
+}
+
+func TestWithTools(t *testing.T) {
+	t.Run("tool call loop", func(t *testing.T) {
+		provider := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+		synapse, _ := Binary("Has order A-1 shipped?", provider, WithTools(lookupOrder))
+		session := NewSession()
+
+		result, err := synapse.FireResult(context.Background(), session, "order A-1")
+		var calls *ToolCallsError
+		if !errors.As(err, &calls) || !errors.Is(err, ErrToolCalls) {
+			t.Fatalf("expected tool calls, got %v", err)
+		}
+		if len(calls.Calls) != 1 || calls.Calls[0].Name != "lookup_order" || string(calls.Calls[0].Arguments) != `{"id":"A-1"}` {
+			t.Errorf("unexpected calls: %+v", calls.Calls)
+		}
+		if len(result.ToolCalls) != 1 || result.Usage == nil || result.Usage.Total != 50 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+
+		messages := session.Messages()
+		if len(messages) != 2 || messages[0].Role != RoleUser || len(messages[1].ToolCalls) != 1 {
+			t.Fatalf("expected prompt and tool calls in session, got %+v", messages)
+		}
+
+		session.AppendToolResult(calls.Calls[0].ID, `{"status":"shipped"}`)
+		if _, err := synapse.Fire(context.Background(), session, "order A-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		messages = session.Messages()
+		if len(messages) != 5 || messages[2].Role != RoleTool || messages[2].ToolCallID != "call-1" || messages[4].Role != RoleAssistant {
+			t.Errorf("unexpected session: %+v", messages)
+		}
+		if len(provider.tools) != 2 || len(provider.tools[1]) != 1 || provider.tools[1][0].Name != "lookup_order" {
+			t.Errorf("expected tools offered on every call, got %+v", provider.tools)
+		}
+	})
+
+	t.Run("tools stack by name", func(t *testing.T) {
+		provider := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+		other := Tool{Name: "cancel_order", Parameters: json.RawMessage(`{"type":"object"}`)}
+		synapse, _ := Binary("question", provider, WithTools(lookupOrder), WithTools(other, lookupOrder))
+
+		_, _ = synapse.Fire(context.Background(), NewSession(), "input")
+		if len(provider.tools) != 1 || len(provider.tools[0]) != 2 {
+			t.Errorf("expected two distinct tools, got %+v", provider.tools)
+		}
+	})
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		calls := 0
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["ok"]}`, nil
+		})
+		synapse, _ := Binary("question", provider, WithTools(lookupOrder), WithRetry(3))
+
+		_, err := synapse.Fire(context.Background(), NewSession(), "input")
+		var unsupported *ToolsUnsupportedError
+		if !errors.As(err, &unsupported) || !errors.Is(err, ErrToolsUnsupported) {
+			t.Fatalf("expected ToolsUnsupportedError, got %v", err)
+		}
+		if unsupported.Provider != provider.Name() || unsupported.Tools != 1 || calls != 0 {
+			t.Errorf("unexpected error %+v after %d calls", unsupported, calls)
+		}
+	})
+
+	t.Run("no tools uses Call", func(t *testing.T) {
+		provider := &toolProvider{MockProvider: NewMockProviderWithName("tools")}
+		synapse, _ := Binary("question", provider)
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(provider.tools) != 0 {
+			t.Errorf("expected CallWithTools unused, got %+v", provider.tools)
+		}
+	})
+}
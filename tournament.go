@@ -0,0 +1,355 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// tournamentID identifies the tournament ranking stage.
+var tournamentID = pipz.NewIdentity("zyn:tournament-ranking", "Ranks long lists in groups across rounds")
+
+// TournamentConfig configures tournament ranking.
+type TournamentConfig struct {
+	GroupSize     int // Items ranked per provider call; values below 2 use 10
+	Rounds        int // Ranking rounds; values below 1 use 3
+	GroupAttempts int // Calls per group before it keeps its standing for the round; values below 1 use 2
+}
+
+// rankingSettings describes a ranking call to the options that reshape it.
+type rankingSettings struct {
+	topN     int  // Items the ranking is cut to; 0 ranks every item
+	weighted bool // Whether items are ordered by weighted criterion scores
+}
+
+// withDefaults fills unset fields with their defaults.
+func (c TournamentConfig) withDefaults() TournamentConfig {
+	if c.GroupSize < 2 {
+		c.GroupSize = 10
+	}
+	if c.Rounds < 1 {
+		c.Rounds = 3
+	}
+	if c.GroupAttempts < 1 {
+		c.GroupAttempts = 2
+	}
+	return c
+}
+
+// WithTournamentRanking ranks lists longer than the group size in rounds of
+// group calls instead of a single provider call, each group ranked through
+// the rest of the pipeline.
+//
+// The first round splits the items in input order into groups of about
+// GroupSize and merges the results Swiss-style: every group's winner first,
+// then every runner-up, and so on. Later rounds regroup neighbours in the
+// standing and re-rank them, alternating the group boundaries by half a group
+// so items can move past them. Scores hold each item's final standing, from
+// 1.0 for first down to 0.0 for last.
+//
+// A run makes about ceil(items/GroupSize) × Rounds calls; Result.Attempts
+// reports the exact count, including retried groups, and usage is summed
+// across calls. A group that still fails after GroupAttempts calls keeps its
+// standing for that round; the call fails only when every group fails.
+// WithProgress callbacks are told as each group finishes.
+// Shorter lists, lists with duplicate items, and weighted rankings are ranked
+// in one call.
+// The option has no effect on other synapse types.
+func WithTournamentRanking(cfg TournamentConfig) Option {
+	cfg = cfg.withDefaults()
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		return pipz.Apply(tournamentID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			settings, ok := req.settings.(*rankingSettings)
+			items := req.Prompt.Items
+			if !ok || settings.weighted || len(items) <= cfg.GroupSize || hasDuplicates(items) {
+				return pipeline.Process(ctx, req)
+			}
+			return runTournament(ctx, pipeline, req, settings.topN, cfg)
+		})
+	}
+}
+
+// tournament tracks the standing of a tournament ranking.
+type tournament struct {
+	items       []string
+	standing    []int     // Item indices, best first
+	calls       int       // Group calls that succeeded
+	degraded    int       // Groups that kept their standing after failing
+	confidences []float64 // Confidence of each successful group call
+	// Latest justification per item index, from groups asked to explain each
+	justifications map[int]string
+}
+
+// runTournament ranks req's items over cfg.Rounds rounds of group calls and
+// sets the merged ranking as the request's response.
+func runTournament(ctx context.Context, pipeline pipz.Chainable[*SynapseRequest], req *SynapseRequest, topN int, cfg TournamentConfig) (*SynapseRequest, error) {
+	t := &tournament{items: req.Prompt.Items, standing: make([]int, len(req.Prompt.Items))}
+	for i := range t.standing {
+		t.standing[i] = i
+	}
+	var usage TokenUsage
+	var lastErr error
+	ctx, progress := startProgress(ctx, t.plannedGroups(cfg), "group")
+
+	for round := range cfg.Rounds {
+		groups := t.groups(cfg.GroupSize, round)
+		for i, group := range groups {
+			if len(group) < 2 {
+				continue
+			}
+			var groupUsage TokenUsage
+			order, err := t.rankGroup(ctx, pipeline, req, group, cfg.GroupAttempts, &groupUsage)
+			addUsage(&usage, &groupUsage)
+			if ctx.Err() != nil {
+				return req, ctx.Err()
+			}
+			progress.advance(fmt.Sprintf("round %d/%d, group %d/%d", round+1, cfg.Rounds, i+1, len(groups)), &groupUsage)
+			if err != nil {
+				lastErr = err
+				t.degraded++
+				continue
+			}
+			groups[i] = order
+		}
+		if round == 0 {
+			t.standing = swissMerge(groups)
+		} else {
+			t.standing = slices.Concat(groups...)
+		}
+	}
+	if t.calls == 0 {
+		return req, fmt.Errorf("tournament ranking: every group failed: %w", lastErr)
+	}
+
+	response, err := json.Marshal(t.response(topN, cfg.Rounds))
+	if err != nil {
+		return req, err
+	}
+	req.Response = string(response)
+	req.Usage = &usage
+	return req, nil
+}
+
+// plannedGroups returns the number of groups ranked across every round.
+func (t *tournament) plannedGroups(cfg TournamentConfig) int {
+	planned := 0
+	for round := range cfg.Rounds {
+		for _, group := range t.groups(cfg.GroupSize, round) {
+			if len(group) >= 2 {
+				planned++
+			}
+		}
+	}
+	return planned
+}
+
+// groups splits the standing into consecutive groups of at most size items,
+// spread evenly. From the third round on, every other round moves the group
+// boundaries to the middle of the even groups.
+func (t *tournament) groups(size, round int) [][]int {
+	n := len(t.standing)
+	count := (n + size - 1) / size
+	bounds := make([]int, count+1)
+	for i := range bounds {
+		bounds[i] = i * n / count
+	}
+	if round >= 2 && round%2 == 0 {
+		shifted := []int{0}
+		for i := 1; i < len(bounds); i++ {
+			shifted = append(shifted, (bounds[i-1]+bounds[i])/2)
+		}
+		bounds = append(shifted, n)
+	}
+
+	groups := make([][]int, 0, len(bounds)-1)
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] > bounds[i-1] {
+			groups = append(groups, slices.Clone(t.standing[bounds[i-1]:bounds[i]]))
+		}
+	}
+	return groups
+}
+
+// swissMerge orders ranked groups by place: every group's first item in group
+// order, then every second item, and so on, with places compared relative to
+// group size.
+func swissMerge(groups [][]int) []int {
+	type entry struct {
+		index int
+		place float64
+	}
+	var entries []entry
+	for _, group := range groups {
+		for position, index := range group {
+			place := 0.0
+			if len(group) > 1 {
+				place = float64(position) / float64(len(group)-1)
+			}
+			entries = append(entries, entry{index, place})
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		switch {
+		case a.place < b.place:
+			return -1
+		case a.place > b.place:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	merged := make([]int, len(entries))
+	for i, e := range entries {
+		merged[i] = e.index
+	}
+	return merged
+}
+
+// rankGroup ranks the items at the given indices with up to attempts calls
+// through the pipeline, adding each call's usage and attempts to the totals,
+// and returns the indices in ranked order.
+func (t *tournament) rankGroup(ctx context.Context, pipeline pipz.Chainable[*SynapseRequest], req *SynapseRequest,
+	group []int, attempts int, usage *TokenUsage) ([]int, error) {
+	prompt := *req.Prompt
+	prompt.Items = make([]string, len(group))
+	for i, index := range group {
+		prompt.Items[i] = t.items[index]
+	}
+	prompt.Constraints = groupConstraints(req.Prompt.Constraints)
+
+	var err error
+	for range attempts {
+		call := *req
+		call.Prompt = &prompt
+		call.Response, call.Usage, call.Error = "", nil, nil
+		call.Attempts, call.EstimatedPromptTokens = 0, 0
+
+		_, err = pipeline.Process(ctx, &call)
+		req.Attempts += call.Attempts
+		addUsage(usage, call.Usage)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var response RankingResponse
+		response, err = parseGroupRanking(call.Response, &prompt)
+		if err != nil {
+			continue
+		}
+		t.calls++
+		t.confidences = append(t.confidences, response.Confidence)
+		order := make([]int, len(response.Ranked))
+		for i, item := range response.Ranked {
+			order[i] = group[slices.Index(prompt.Items, item)]
+			if justification, ok := response.Justifications[item]; ok {
+				if t.justifications == nil {
+					t.justifications = make(map[int]string)
+				}
+				t.justifications[order[i]] = justification
+			}
+		}
+		return order, nil
+	}
+	return nil, err
+}
+
+// parseGroupRanking decodes a group's response, re-sorted by score if its
+// order contradicts its scores, and checks that it ranks every item of the
+// group exactly once.
+func parseGroupRanking(raw string, prompt *Prompt) (RankingResponse, error) {
+	response, _, err := parseResponse[RankingResponse](raw, prompt)
+	if err != nil {
+		return response, fmt.Errorf("%w: %w", ErrParseFailed, err)
+	}
+	if err := response.Validate(); err != nil {
+		return response, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+	response = response.sortedByScore()
+	if len(response.Ranked) != len(prompt.Items) || hasDuplicates(response.Ranked) {
+		return response, fmt.Errorf("%w: ranked %d items of a group of %d", ErrInvalidResponse, len(response.Ranked), len(prompt.Items))
+	}
+	for _, item := range response.Ranked {
+		if !slices.Contains(prompt.Items, item) {
+			return response, fmt.Errorf("%w: ranked unknown item %q", ErrInvalidResponse, item)
+		}
+	}
+	return response, nil
+}
+
+// response builds the merged ranking, cut to the top N items when topN is
+// set, with each item's final standing as its score and its latest
+// justification, if any.
+func (t *tournament) response(topN, rounds int) RankingResponse {
+	order := t.standing
+	if topN > 0 && topN < len(order) {
+		order = order[:topN]
+	}
+
+	last := float64(len(t.standing) - 1)
+	response := RankingResponse{
+		Ranked: make([]string, len(order)),
+		Scores: make(map[string]float64, len(order)),
+	}
+	for position, index := range order {
+		response.Ranked[position] = t.items[index]
+		response.Scores[t.items[index]] = (last - float64(position)) / last
+		if justification, ok := t.justifications[index]; ok {
+			if response.Justifications == nil {
+				response.Justifications = make(map[string]string, len(order))
+			}
+			response.Justifications[t.items[index]] = justification
+		}
+	}
+
+	var confidence float64
+	for _, c := range t.confidences {
+		confidence += c
+	}
+	response.Confidence = confidence / float64(len(t.confidences))
+
+	response.Reasoning = []string{fmt.Sprintf("Tournament ranking of %d items over %d rounds: %d group calls",
+		len(t.items), rounds, t.calls)}
+	if t.degraded > 0 {
+		response.Reasoning = append(response.Reasoning,
+			fmt.Sprintf("%d groups failed and kept their standing", t.degraded))
+	}
+	return response
+}
+
+// groupConstraints returns constraints for ranking a whole group: a top N
+// selection in the original constraints is replaced by ranking every item.
+func groupConstraints(constraints []string) []string {
+	group := []string{
+		"ranked: all items, ordered highest to lowest",
+		"ranked: include every item exactly once",
+	}
+	for _, constraint := range constraints {
+		if strings.HasPrefix(constraint, "ranked: select top") ||
+			constraint == "ranked: ordered highest to lowest" ||
+			slices.Contains(group, constraint) {
+			continue
+		}
+		group = append(group, constraint)
+	}
+	return group
+}
+
+// hasDuplicates reports whether items contains the same item twice.
+func hasDuplicates(items []string) bool {
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item] {
+			return true
+		}
+		seen[item] = true
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package zyn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// scriptedRanker ranks the items of a rendered ranking prompt by their
+// numeric suffix, highest first, and records every group it is asked to rank.
+type scriptedRanker struct {
+	mu      sync.Mutex
+	groups  [][]string
+	fail    func(call int, items []string) bool // Fails the call when it returns true
+	explain bool                                // Justifies each item with the call that ranked it
+}
+
+func (s *scriptedRanker) provider() Provider {
+	return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		items := promptItems(prompt)
+
+		s.mu.Lock()
+		call := len(s.groups)
+		s.groups = append(s.groups, items)
+		s.mu.Unlock()
+
+		if s.fail != nil && s.fail(call, items) {
+			return "", fmt.Errorf("group call %d failed", call)
+		}
+
+		ranked := slices.Clone(items)
+		slices.SortFunc(ranked, func(a, b string) int { return itemNumber(b) - itemNumber(a) })
+		response := RankingResponse{Ranked: ranked, Confidence: 0.8, Reasoning: []string{"by number"}}
+		if s.explain {
+			response.Justifications = make(map[string]string, len(ranked))
+			for _, item := range ranked {
+				response.Justifications[item] = fmt.Sprintf("call %d", call)
+			}
+		}
+		body, _ := json.Marshal(response)
+		return string(body), nil
+	})
+}
+
+// promptItems returns the items listed in a rendered prompt.
+func promptItems(prompt string) []string {
+	var items []string
+	_, section, _ := strings.Cut(prompt, "Items:\n")
+	for _, line := range strings.Split(section, "\n") {
+		_, item, ok := strings.Cut(strings.TrimSpace(line), ". ")
+		if !ok || !strings.HasPrefix(item, "item-") {
+			break
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func itemNumber(item string) int {
+	var n int
+	fmt.Sscanf(item, "item-%d", &n)
+	return n
+}
+
+// shuffledItems returns item-01 to item-n in a fixed scrambled order.
+func shuffledItems(n int) []string {
+	items := make([]string, n)
+	for i := range n {
+		items[i] = fmt.Sprintf("item-%02d", (i*7)%n+1)
+	}
+	return items
+}
+
+func TestWithTournamentRanking(t *testing.T) {
+	t.Run("ranks long list in groups", func(t *testing.T) {
+		script := &scriptedRanker{}
+		synapse, err := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 3}))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		items := shuffledItems(20)
+		session := NewSession()
+		result, err := synapse.FireResult(context.Background(), session, items)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// 4 groups in each of the first two rounds, then 5 with shifted boundaries
+		if len(script.groups) != 13 || result.Attempts != 13 {
+			t.Errorf("expected 13 calls, got %d calls and %d attempts", len(script.groups), result.Attempts)
+		}
+		for _, group := range script.groups {
+			if len(group) < 2 || len(group) > 5 {
+				t.Errorf("expected groups of 2 to 5 items, got %v", group)
+			}
+		}
+		if result.Usage == nil || result.Usage.Total != 13*150 {
+			t.Errorf("expected usage summed over 13 calls, got %+v", result.Usage)
+		}
+
+		ranked := result.Value
+		if len(ranked) != 20 || !slices.Equal(slices.Sorted(slices.Values(ranked)), slices.Sorted(slices.Values(items))) {
+			t.Fatalf("expected a permutation of the items, got %v", ranked)
+		}
+		if !slices.Equal(ranked[:5], []string{"item-20", "item-19", "item-18", "item-17", "item-16"}) {
+			t.Errorf("expected exact top five, got %v", ranked[:5])
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected one exchange in session, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("scores and reasoning", func(t *testing.T) {
+		script := &scriptedRanker{}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 2}))
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), shuffledItems(20))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.Scores) != 20 || response.Scores[response.Ranked[0]] != 1 {
+			t.Errorf("expected a score per item with a perfect leader, got %v", response.Scores)
+		}
+		if response.Confidence < 0.799 || response.Confidence > 0.801 {
+			t.Errorf("expected mean group confidence, got %f", response.Confidence)
+		}
+		if !strings.Contains(response.Reasoning[0], "20 items over 2 rounds: 8 group calls") {
+			t.Errorf("unexpected reasoning: %v", response.Reasoning)
+		}
+		if response.Justifications != nil {
+			t.Errorf("expected no justifications unless requested, got %v", response.Justifications)
+		}
+	})
+
+	t.Run("justifications from the latest group", func(t *testing.T) {
+		script := &scriptedRanker{explain: true}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 2}))
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), RankingInput{Items: shuffledItems(20), ExplainEach: true, TopN: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.Justifications) != 3 {
+			t.Fatalf("expected a justification per ranked item, got %v", response.Justifications)
+		}
+		// Calls 0-3 make the first round and calls 4-7 the second
+		for _, item := range response.Ranked {
+			var call int
+			if _, err := fmt.Sscanf(response.Justifications[item], "call %d", &call); err != nil || call < 4 {
+				t.Errorf("expected %s justified by a second-round call, got %q", item, response.Justifications[item])
+			}
+		}
+	})
+
+	t.Run("top n", func(t *testing.T) {
+		script := &scriptedRanker{}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 3}))
+
+		response, err := synapse.FireWithInput(context.Background(), NewSession(), RankingInput{
+			Items: shuffledItems(20),
+			TopN:  3,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(response.Ranked, []string{"item-20", "item-19", "item-18"}) || len(response.Scores) != 3 {
+			t.Errorf("expected top three with scores, got %v %v", response.Ranked, response.Scores)
+		}
+	})
+
+	t.Run("retries failed group", func(t *testing.T) {
+		script := &scriptedRanker{fail: func(call int, _ []string) bool { return call == 1 }}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 3}))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), shuffledItems(20))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Attempts != 14 {
+			t.Errorf("expected 13 calls plus one retry, got %d", result.Attempts)
+		}
+		if !slices.Equal(script.groups[1], script.groups[2]) {
+			t.Errorf("expected the failed group to be retried, got %v then %v", script.groups[1], script.groups[2])
+		}
+	})
+
+	t.Run("degrades after retries", func(t *testing.T) {
+		script := &scriptedRanker{fail: func(_ int, items []string) bool { return slices.Contains(items, "item-03") }}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 1, GroupAttempts: 3}))
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), shuffledItems(20))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(script.groups) != 6 {
+			t.Errorf("expected 3 healthy calls and 3 attempts on the failing group, got %d", len(script.groups))
+		}
+		if len(response.Ranked) != 20 {
+			t.Errorf("expected every item ranked, got %v", response.Ranked)
+		}
+		if len(response.Reasoning) != 2 || !strings.Contains(response.Reasoning[1], "1 groups failed") {
+			t.Errorf("expected degraded group reported, got %v", response.Reasoning)
+		}
+	})
+
+	t.Run("fails when every group fails", func(t *testing.T) {
+		script := &scriptedRanker{fail: func(int, []string) bool { return true }}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5, Rounds: 2}))
+
+		session := NewSession()
+		_, err := synapse.Fire(context.Background(), session, shuffledItems(20))
+		if err == nil || !strings.Contains(err.Error(), "every group failed") {
+			t.Errorf("expected every group to fail, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("short list uses one call", func(t *testing.T) {
+		script := &scriptedRanker{}
+		synapse, _ := Ranking("priority", script.provider(),
+			WithTournamentRanking(TournamentConfig{GroupSize: 5}))
+
+		ranked, err := synapse.Fire(context.Background(), NewSession(), shuffledItems(5))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(script.groups) != 1 || ranked[0] != "item-05" {
+			t.Errorf("expected a single call, got %d calls ranking %v", len(script.groups), ranked)
+		}
+	})
+
+	t.Run("ignores other synapses", func(t *testing.T) {
+		synapse, _ := Binary("valid?", NewMockProvider(), WithTournamentRanking(TournamentConfig{GroupSize: 2}))
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestTournamentConfig_Defaults(t *testing.T) {
+	cfg := TournamentConfig{}.withDefaults()
+	if cfg.GroupSize != 10 || cfg.Rounds != 3 || cfg.GroupAttempts != 2 {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestGroupConstraints(t *testing.T) {
+	constraints := groupConstraints([]string{
+		"ranked: select top 3 items only",
+		"ranked: ordered highest to lowest",
+		"ranked: preserve exact item text",
+		"confidence: 0.0 to 1.0",
+	})
+	expected := []string{
+		"ranked: all items, ordered highest to lowest",
+		"ranked: include every item exactly once",
+		"ranked: preserve exact item text",
+		"confidence: 0.0 to 1.0",
+	}
+	if !slices.Equal(constraints, expected) {
+		t.Errorf("expected %v, got %v", expected, constraints)
+	}
+}
+
+func TestParseGroupRanking(t *testing.T) {
+	prompt := &Prompt{Items: []string{"a", "b"}}
+	if _, err := parseGroupRanking(`{"ranked": ["b", "a"], "confidence": 0.9, "reasoning": ["r"]}`, prompt); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	response, err := parseGroupRanking(`{"ranked": ["b", "a"], "scores": {"a": 0.9, "b": 0.2}, "confidence": 0.9, "reasoning": ["r"]}`, prompt)
+	if err != nil || response.Ranked[0] != "a" {
+		t.Errorf("expected the group re-sorted by score, got %v (%v)", response.Ranked, err)
+	}
+	for _, raw := range []string{
+		`{"ranked": ["b"], "confidence": 0.9, "reasoning": ["r"]}`,
+		`{"ranked": ["b", "c"], "confidence": 0.9, "reasoning": ["r"]}`,
+		`{"ranked": ["b", "b"], "confidence": 0.9, "reasoning": ["r"]}`,
+	} {
+		if _, err := parseGroupRanking(raw, prompt); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected invalid response for %s, got %v", raw, err)
+		}
+	}
+}
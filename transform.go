@@ -2,27 +2,99 @@ package zyn
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/zoobzio/pipz"
 )
 
+// LengthUnit is the unit in which a transform's output length is measured.
+type LengthUnit string
+
+// Length units.
+const (
+	LengthCharacters LengthUnit = "characters" // Unicode characters (the default)
+	LengthWords      LengthUnit = "words"      // Whitespace-separated words
+)
+
+// maxLengthCorrections is how many times an over-length output is sent back
+// to the model to be shortened before the transform fails.
+const maxLengthCorrections = 1
+
 // TransformInput contains rich input structure for transformation.
 type TransformInput struct {
-	Text        string            // The text to transform
-	Context     string            // Optional context
-	Style       string            // Optional style guidance
-	Examples    map[string]string // Optional input->output examples
-	MaxLength   int               // Optional maximum length
-	Temperature float32           // Temperature for creativity
+	Text              string            // The text to transform
+	Context           string            // Optional context
+	Style             string            // Optional style guidance
+	Examples          map[string]string // Optional input->output examples
+	MaxLength         int               // Optional maximum output length, enforced
+	LengthUnit        LengthUnit        // Unit for MaxLength; defaults to LengthCharacters
+	Truncate          bool              // Truncate over-length output at a word boundary instead of asking for a shorter one
+	TrackChanges      bool              // Request structured change spans for this call, as WithChangeSpans does for every call
+	ProtectedPatterns []string          // Tokens to keep verbatim: regular expressions, or delimiters around "..." such as "{{...}}"
+	Temperature       float32           // Temperature for creativity
+}
+
+// Change span kinds.
+const (
+	ChangeAdded     = "added"     // After was inserted; Before is empty
+	ChangeRemoved   = "removed"   // Before was deleted; After is empty
+	ChangeReworded  = "reworded"  // Before was rewritten as After
+	ChangeReordered = "reordered" // Before was moved, appearing as After
+)
+
+// changeSpansProperty is the schema property holding change spans.
+const changeSpansProperty = "change_spans"
+
+// Identity for the change spans option.
+var changeSpansID = pipz.NewIdentity("zyn:change-spans", "Requests structured change spans")
+
+// fullTransformSchema is the transform schema including change spans,
+// generated on first use by WithChangeSpans.
+var fullTransformSchema = sync.OnceValues(generateJSONSchema[TransformResponse])
+
+// ChangeSpan describes one change made by a transform, in a form that can
+// drive a diff view.
+type ChangeSpan struct {
+	Before string `json:"before"` // Text from the input; empty for added text
+	After  string `json:"after"`  // Text in the output; empty for removed text
+	Kind   string `json:"kind"`   // added, removed, reworded, or reordered
+}
+
+// Validate checks the span's kind and that it carries the text its kind needs.
+func (c ChangeSpan) Validate() error {
+	switch c.Kind {
+	case ChangeAdded:
+		if c.After == "" {
+			return fmt.Errorf("added span requires after text")
+		}
+	case ChangeRemoved:
+		if c.Before == "" {
+			return fmt.Errorf("removed span requires before text")
+		}
+	case ChangeReworded, ChangeReordered:
+		if c.Before == "" || c.After == "" {
+			return fmt.Errorf("%s span requires before and after text", c.Kind)
+		}
+	default:
+		return fmt.Errorf("unknown change kind %q", c.Kind)
+	}
+	return nil
 }
 
 // TransformResponse contains the transformed output with metadata.
 type TransformResponse struct {
-	Output     string   `json:"output"`     // The transformed text
-	Confidence float64  `json:"confidence"` // Confidence in transformation
-	Changes    []string `json:"changes"`    // Key changes made
-	Reasoning  []string `json:"reasoning"`  // Explanation of approach
+	Output      string       `json:"output"`                 // The transformed text
+	Confidence  float64      `json:"confidence"`             // Confidence in transformation
+	Changes     []string     `json:"changes"`                // Key changes made
+	ChangeSpans []ChangeSpan `json:"change_spans,omitempty"` // Structured changes, requested with WithChangeSpans or TrackChanges
+	Reasoning   []string     `json:"reasoning"`              // Explanation of approach
 }
 
 // Validate checks if the response is valid.
@@ -33,9 +105,102 @@ func (r TransformResponse) Validate() error {
 	if r.Confidence < 0 || r.Confidence > 1 {
 		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
 	}
+	for i, span := range r.ChangeSpans {
+		if err := span.Validate(); err != nil {
+			return fmt.Errorf("change span %d: %w", i, err)
+		}
+	}
 	return nil
 }
 
+// changeSpansConstraint describes the change spans requested by
+// WithChangeSpans and TransformInput.TrackChanges.
+const changeSpansConstraint = "change_spans: one entry per change; before is exact text from the input (empty when added), " +
+	"after is exact text from the output (empty when removed), kind is added, removed, reworded, or reordered"
+
+// WithChangeSpans asks a transform synapse for structured change spans
+// alongside its prose changes on every call; TransformInput.TrackChanges asks
+// for them on a single call. Without either the response schema does not
+// mention change spans. Responses that omit spans are still accepted. Spans
+// whose Before text does not appear in the input, or whose After text does
+// not appear in the output, are dropped with a note in the reasoning.
+// The option has no effect on other synapse types.
+func WithChangeSpans() Option {
+	return withRequest(changeSpansID, func(req *SynapseRequest) {
+		if req.SynapseType != "transform" {
+			return
+		}
+		requestChangeSpans(req.Prompt)
+	})
+}
+
+// requestChangeSpans adds change spans to a transform prompt's schema and
+// constraints, keeping the schema strict if it is.
+func requestChangeSpans(prompt *Prompt) {
+	schema, err := fullTransformSchema()
+	if err != nil {
+		return
+	}
+	if prompt.Strict {
+		if schema, err = strictSchemaJSON(schema); err != nil {
+			return
+		}
+	}
+	prompt.Schema = schema
+	if !slices.Contains(prompt.Constraints, changeSpansConstraint) {
+		prompt.Constraints = append(slices.Clone(prompt.Constraints), changeSpansConstraint)
+	}
+}
+
+// groundChangeSpans drops change spans whose Before text does not appear in
+// the input or whose After text does not appear in the output, noting each
+// in the reasoning, so invented changes never reach a diff view.
+func groundChangeSpans(prompt *Prompt, response TransformResponse) TransformResponse {
+	if len(response.ChangeSpans) == 0 {
+		return response
+	}
+	var grounded []ChangeSpan
+	for _, span := range response.ChangeSpans {
+		switch {
+		case span.Before != "" && !quoteAppears(prompt.Input, span.Before):
+			response.Reasoning = append(response.Reasoning,
+				fmt.Sprintf("change span dropped: before text %q does not appear in the input", span.Before))
+		case span.After != "" && !quoteAppears(response.Output, span.After):
+			response.Reasoning = append(response.Reasoning,
+				fmt.Sprintf("change span dropped: after text %q does not appear in the output", span.After))
+		default:
+			grounded = append(grounded, span)
+		}
+	}
+	response.ChangeSpans = grounded
+	return response
+}
+
+// quoteAppears reports whether quote appears in text, ignoring differences
+// in whitespace.
+func quoteAppears(text, quote string) bool {
+	normalize := func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	return strings.Contains(normalize(text), normalize(quote))
+}
+
+// omitProperty returns schema without the named top-level property.
+func omitProperty(schema, property string) (string, error) {
+	var parsed JSONSchema
+	if err := json.Unmarshal([]byte(schema), &parsed); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	delete(parsed.Properties, property)
+	parsed.Required = slices.DeleteFunc(parsed.Required, func(name string) bool { return name == property })
+
+	jsonBytes, err := json.MarshalIndent(&parsed, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
 // TransformSynapse transforms text according to specified instructions.
 type TransformSynapse struct {
 	instruction string // What transformation to perform
@@ -47,8 +212,12 @@ type TransformSynapse struct {
 // Transform creates a new text transformation synapse.
 // Returns an error if the JSON schema cannot be generated.
 func Transform(instruction string, provider Provider, opts ...Option) (*TransformSynapse, error) {
-	// Generate schema once at construction
+	// Generate schema once at construction; change spans are only part of
+	// the schema when requested with WithChangeSpans
 	schema, err := generateJSONSchema[TransformResponse]()
+	if err == nil {
+		schema, err = omitProperty(schema, changeSpansProperty)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("transform synapse: %w", err)
 	}
@@ -74,12 +243,30 @@ func (t *TransformSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
 	return t.service.GetPipeline()
 }
 
+// WithDefaults sets default input values for the transformation.
+// These are merged with user input at execution time.
+func (t *TransformSynapse) WithDefaults(defaults TransformInput) *TransformSynapse {
+	t.defaults = defaults
+	return t
+}
+
 // Fire performs the transformation with a simple string input.
 func (t *TransformSynapse) Fire(ctx context.Context, session *Session, text string) (string, error) {
 	input := TransformInput{Text: text}
 	return t.FireWithInput(ctx, session, input)
 }
 
+// FireResult performs the transformation and returns the output in a Result
+// envelope carrying the call's usage, timing, and request metadata.
+func (t *TransformSynapse) FireResult(ctx context.Context, session *Session, text string) (Result[string], error) {
+	merged := t.mergeInputs(TransformInput{Text: text})
+	result, err := t.execute(ctx, session, t.buildPrompt(merged), merged)
+	if err != nil {
+		return withValue(result, ""), fmt.Errorf("transform failed: %w", err)
+	}
+	return withValue(result, result.Value.Output), nil
+}
+
 // FireWithDetails performs the transformation and returns detailed response.
 func (t *TransformSynapse) FireWithDetails(ctx context.Context, session *Session, text string) (*TransformResponse, error) {
 	input := TransformInput{Text: text}
@@ -104,12 +291,158 @@ func (t *TransformSynapse) FireWithInputDetails(ctx context.Context, session *Se
 	prompt := t.buildPrompt(merged)
 
 	// Execute through service with session (service handles temperature fallback)
-	response, err := t.service.Execute(ctx, session, prompt, merged.Temperature)
+	result, err := t.execute(ctx, session, prompt, merged)
 	if err != nil {
 		return nil, fmt.Errorf("transform failed: %w", err)
 	}
 
-	return &response, nil
+	return &result.Value, nil
+}
+
+// execute runs the prompt, enforces the input's MaxLength on the output,
+// rejects output that does not keep the input's protected tokens verbatim,
+// and drops change spans not grounded in the final input and output.
+func (t *TransformSynapse) execute(ctx context.Context, session *Session, prompt *Prompt, input TransformInput) (Result[TransformResponse], error) {
+	tokens, counts, err := protectedTokens(input.Text, input.ProtectedPatterns)
+	if err != nil {
+		return Result[TransformResponse]{Provider: t.service.providerName}, fmt.Errorf("%w: %w", ErrInvalidPrompt, err)
+	}
+	var check func(TransformResponse) error
+	if len(tokens) > 0 {
+		check = func(response TransformResponse) error {
+			return checkProtected(tokens, counts, response.Output)
+		}
+	}
+
+	result, err := t.executeLimited(ctx, session, prompt, input, check)
+	if err == nil {
+		result.Value = groundChangeSpans(prompt, result.Value)
+	}
+	return result, err
+}
+
+// executeLimited runs the prompt, checking each response with check if it is
+// not nil, and enforces the input's MaxLength on the output.
+//
+// An over-length output is truncated when input.Truncate is set. Otherwise it
+// is rejected, leaving the session untouched, and the model is asked up to
+// maxLengthCorrections times to shorten it. Only the exchange that produced
+// the final output is added to the session. The returned envelope covers all
+// calls made.
+func (t *TransformSynapse) executeLimited(ctx context.Context, session *Session, prompt *Prompt, input TransformInput, check func(TransformResponse) error) (Result[TransformResponse], error) {
+	if input.MaxLength <= 0 {
+		return t.service.executeChecked(ctx, session, prompt, input.Temperature, check)
+	}
+	unit := input.LengthUnit
+	if unit == "" {
+		unit = LengthCharacters
+	}
+
+	if input.Truncate {
+		result, err := t.service.executeChecked(ctx, session, prompt, input.Temperature, check)
+		if err == nil {
+			result.Value.Output = truncateOutput(result.Value.Output, input.MaxLength, unit)
+		}
+		return result, err
+	}
+
+	limit := func(response TransformResponse) error {
+		if check != nil {
+			if err := check(response); err != nil {
+				return err
+			}
+		}
+		if length := outputLength(response.Output, unit); length > input.MaxLength {
+			return &OutputTooLongError{Length: length, Limit: input.MaxLength, Unit: unit}
+		}
+		return nil
+	}
+
+	result, err := t.service.executeChecked(ctx, session, prompt, input.Temperature, limit)
+	var tooLong *OutputTooLongError
+	for corrections := 0; corrections < maxLengthCorrections && errors.As(err, &tooLong); corrections++ {
+		correction := *prompt
+		correction.Constraints = append(slices.Clone(prompt.Constraints), fmt.Sprintf(
+			"your previous answer was %d %s over the limit of %d %s; shorten it: %s",
+			tooLong.Length-tooLong.Limit, unit, tooLong.Limit, unit, result.Value.Output))
+
+		previous := result
+		result, err = t.service.executeChecked(ctx, session, &correction, input.Temperature, limit)
+		result = combineResults(previous, result)
+	}
+	return result, err
+}
+
+// combineResults returns next with the attempts, duration, and usage of the
+// earlier call added in.
+func combineResults[T any](earlier, next Result[T]) Result[T] {
+	next.Attempts += earlier.Attempts
+	next.Duration += earlier.Duration
+	if earlier.Usage != nil {
+		usage := *earlier.Usage
+		addUsage(&usage, next.Usage)
+		next.Usage = &usage
+	}
+	return next
+}
+
+// outputLength measures text in unit.
+func outputLength(text string, unit LengthUnit) int {
+	if unit == LengthWords {
+		return len(strings.Fields(text))
+	}
+	return utf8.RuneCountInString(text)
+}
+
+// truncateOutput shortens text to at most limit units, cutting at a word
+// boundary. A single word longer than a character limit is cut mid-word.
+func truncateOutput(text string, limit int, unit LengthUnit) string {
+	if outputLength(text, unit) <= limit {
+		return text
+	}
+
+	if unit == LengthWords {
+		words := 0
+		inWord := false
+		for i, r := range text {
+			if unicode.IsSpace(r) {
+				inWord = false
+				continue
+			}
+			if !inWord {
+				if words == limit {
+					return strings.TrimRightFunc(text[:i], unicode.IsSpace)
+				}
+				words++
+				inWord = true
+			}
+		}
+		return text
+	}
+
+	runes := []rune(text)
+	cut := runes[:limit]
+	if !unicode.IsSpace(runes[limit]) {
+		// Back up to the end of the last whole word, if there is one
+		if i := strings.LastIndexFunc(string(cut), unicode.IsSpace); i > 0 {
+			return strings.TrimRightFunc(string(cut)[:i], unicode.IsSpace)
+		}
+	}
+	return strings.TrimRightFunc(string(cut), unicode.IsSpace)
+}
+
+// Invoke executes the synapse through the Synapse interface.
+// The returned Validator is a TransformResponse.
+func (t *TransformSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := t.FireWithInputDetails(ctx, session, TransformInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *response, nil
 }
 
 // mergeInputs combines defaults with user input.
@@ -136,6 +469,18 @@ func (t *TransformSynapse) mergeInputs(input TransformInput) TransformInput {
 	if input.MaxLength > 0 {
 		merged.MaxLength = input.MaxLength
 	}
+	if input.LengthUnit != "" {
+		merged.LengthUnit = input.LengthUnit
+	}
+	if input.Truncate {
+		merged.Truncate = true
+	}
+	if input.TrackChanges {
+		merged.TrackChanges = true
+	}
+	if len(input.ProtectedPatterns) > 0 {
+		merged.ProtectedPatterns = input.ProtectedPatterns
+	}
 	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
 		merged.Temperature = input.Temperature
 	}
@@ -176,10 +521,24 @@ func (t *TransformSynapse) buildPrompt(input TransformInput) *Prompt {
 	}
 
 	if input.MaxLength > 0 {
-		constraints = append(constraints, fmt.Sprintf("maximum length: %d characters", input.MaxLength))
+		unit := input.LengthUnit
+		if unit == "" {
+			unit = LengthCharacters
+		}
+		constraints = append(constraints, fmt.Sprintf("maximum length: %d %s", input.MaxLength, unit))
+	}
+
+	// Invalid patterns are rejected by execute before the call
+	if tokens, _, err := protectedTokens(input.Text, input.ProtectedPatterns); err == nil && len(tokens) > 0 {
+		constraints = append(constraints, fmt.Sprintf(
+			"output: keep these tokens exactly as written, never translated or altered: %s", strings.Join(tokens, ", ")))
 	}
 
 	prompt.Constraints = constraints
 
+	if input.TrackChanges {
+		requestChangeSpans(prompt)
+	}
+
 	return prompt
 }
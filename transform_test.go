@@ -2,8 +2,13 @@ package zyn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestTransform(t *testing.T) {
@@ -106,6 +111,48 @@ func TestTransformSynapse_GetPipeline(t *testing.T) {
 	})
 }
 
+func TestTransformSynapse_WithDefaults(t *testing.T) {
+	t.Run("sets_defaults", func(t *testing.T) {
+		provider := NewMockProvider()
+		synapse, err := Transform("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		synapseWithDefaults := synapse.WithDefaults(TransformInput{
+			Style:     "formal",
+			MaxLength: 50,
+		})
+
+		if synapseWithDefaults == nil {
+			t.Fatal("WithDefaults returned nil")
+		}
+		if synapseWithDefaults.defaults.Style != "formal" {
+			t.Error("Defaults not set correctly")
+		}
+	})
+
+	t.Run("applied_to_prompt", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"output": "done", "confidence": 0.9, "changes": ["x"], "reasoning": ["y"]}`, nil
+		})
+		synapse, err := Transform("test", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		synapse = synapse.WithDefaults(TransformInput{Style: "pirate"})
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "hello"); err != nil {
+			t.Fatalf("Fire failed with defaults: %v", err)
+		}
+		if !strings.Contains(prompt, "pirate") {
+			t.Errorf("expected default style in prompt, got:\n%s", prompt)
+		}
+	})
+}
+
 func TestTransformSynapse_Fire(t *testing.T) {
 	t.Run("simple", func(t *testing.T) {
 		provider := NewMockProviderWithResponse(`{"output": "summary text", "confidence": 0.9, "changes": [], "reasoning": ["test"]}`)
@@ -651,3 +698,434 @@ func TestTransformResponse_Validate(t *testing.T) {
 		}
 	})
 }
+
+func TestTransformSynapse_MaxLength(t *testing.T) {
+	// sequencedResponses returns a provider answering with outputs in order,
+	// repeating the last one, and records the prompts it receives.
+	sequencedResponses := func(prompts *[]string, outputs ...string) Provider {
+		return NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+			*prompts = append(*prompts, prompt)
+			output := outputs[min(len(*prompts), len(outputs))-1]
+			return fmt.Sprintf(`{"output": %q, "confidence": 0.9, "changes": [], "reasoning": ["shortened"]}`, output), nil
+		})
+	}
+	long := "Your order has shipped and will arrive on Tuesday between nine and five."
+	short := "Order shipped, arrives Tuesday."
+
+	t.Run("corrective retry", func(t *testing.T) {
+		var prompts []string
+		synapse, err := Transform("summarize for SMS", sequencedResponses(&prompts, long, short))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		result, err := synapse.FireWithInput(context.Background(), session, TransformInput{Text: "order details", MaxLength: 40})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != short {
+			t.Errorf("expected corrected output, got %q", result)
+		}
+		if len(prompts) != 2 {
+			t.Fatalf("expected one corrective call, got %d calls", len(prompts))
+		}
+		over := utf8.RuneCountInString(long) - 40
+		if !strings.Contains(prompts[1], fmt.Sprintf("your previous answer was %d characters over the limit", over)) {
+			t.Errorf("expected corrective instruction in second prompt, got %q", prompts[1])
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected a single exchange in session, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("fails after corrections", func(t *testing.T) {
+		var prompts []string
+		synapse, err := Transform("summarize for SMS", sequencedResponses(&prompts, long))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		session := NewSession()
+		result, err := synapse.FireResult(context.Background(), session, "order details")
+		if err != nil {
+			t.Fatalf("expected no limit without MaxLength, got %v", err)
+		}
+		if result.Value != long {
+			t.Errorf("expected output unchanged without MaxLength, got %q", result.Value)
+		}
+
+		synapse.WithDefaults(TransformInput{MaxLength: 5, LengthUnit: LengthWords})
+		prompts = nil
+		result, err = synapse.FireResult(context.Background(), session, "order details")
+		if !errors.Is(err, ErrOutputTooLong) {
+			t.Fatalf("expected ErrOutputTooLong, got %v", err)
+		}
+		var tooLong *OutputTooLongError
+		if !errors.As(err, &tooLong) || tooLong.Unit != LengthWords || tooLong.Limit != 5 {
+			t.Errorf("expected word-limit details, got %+v", tooLong)
+		}
+		if len(prompts) != 1+maxLengthCorrections {
+			t.Errorf("expected %d calls, got %d", 1+maxLengthCorrections, len(prompts))
+		}
+		if result.Attempts != len(prompts) || result.Usage == nil || result.Usage.Total != 150*len(prompts) {
+			t.Errorf("expected envelope to cover all calls, got attempts %d usage %+v", result.Attempts, result.Usage)
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected session untouched by the failed transform, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		var prompts []string
+		synapse, err := Transform("summarize for SMS", sequencedResponses(&prompts, long))
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		result, err := synapse.FireWithInput(context.Background(), NewSession(), TransformInput{Text: "order details", MaxLength: 30, Truncate: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "Your order has shipped and" {
+			t.Errorf("expected truncation at a word boundary, got %q", result)
+		}
+		if len(prompts) != 1 {
+			t.Errorf("expected no corrective call when truncating, got %d calls", len(prompts))
+		}
+	})
+}
+
+func TestTruncateOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		unit     LengthUnit
+		expected string
+	}{
+		{"within limit", "short text", 20, LengthCharacters, "short text"},
+		{"word boundary", "hello brave new world", 13, LengthCharacters, "hello brave"},
+		{"boundary at limit", "hello brave new world", 11, LengthCharacters, "hello brave"},
+		{"single long word", "supercalifragilistic", 5, LengthCharacters, "super"},
+		{"multibyte", "héllo wörld again", 12, LengthCharacters, "héllo wörld"},
+		{"words", "one two  three four", 3, LengthWords, "one two  three"},
+		{"words within limit", "one two", 3, LengthWords, "one two"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateOutput(tt.text, tt.limit, tt.unit)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if outputLength(got, tt.unit) > tt.limit {
+				t.Errorf("truncated output %q exceeds limit %d", got, tt.limit)
+			}
+		})
+	}
+}
+
+func TestChangeSpan_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		span    ChangeSpan
+		wantErr bool
+	}{
+		{"added", ChangeSpan{After: "please", Kind: ChangeAdded}, false},
+		{"added without after", ChangeSpan{Kind: ChangeAdded}, true},
+		{"removed", ChangeSpan{Before: "very", Kind: ChangeRemoved}, false},
+		{"removed without before", ChangeSpan{After: "x", Kind: ChangeRemoved}, true},
+		{"reworded", ChangeSpan{Before: "gonna", After: "going to", Kind: ChangeReworded}, false},
+		{"reworded without after", ChangeSpan{Before: "gonna", Kind: ChangeReworded}, true},
+		{"reordered", ChangeSpan{Before: "first, second", After: "second, first", Kind: ChangeReordered}, false},
+		{"reordered without before", ChangeSpan{After: "second, first", Kind: ChangeReordered}, true},
+		{"unknown kind", ChangeSpan{Before: "a", After: "b", Kind: "replaced"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.span.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTransformSynapse_ChangeSpans(t *testing.T) {
+	input := "We are gonna ship the   very big update on Friday."
+	respond := func(spans string) Provider {
+		return NewMockProviderWithResponse(`{"output": "Please note we will ship the big update on Friday.", "confidence": 0.9, "changes": ["formal tone"], "change_spans": ` + spans + `, "reasoning": ["r"]}`)
+	}
+
+	t.Run("schema unchanged without option", func(t *testing.T) {
+		synapse, err := Transform("formalize", NewMockProvider())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if strings.Contains(synapse.schema, changeSpansProperty) {
+			t.Error("expected default schema without change spans")
+		}
+	})
+
+	t.Run("schema requested by option", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"output": "done", "confidence": 0.9, "changes": [], "reasoning": ["r"]}`, nil
+		})
+		synapse, err := Transform("formalize", provider, WithChangeSpans(), WithStrictSchema())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), input)
+		if err != nil {
+			t.Fatalf("expected response without spans to be accepted, got %v", err)
+		}
+		if response.ChangeSpans != nil {
+			t.Errorf("expected no spans, got %+v", response.ChangeSpans)
+		}
+		if !strings.Contains(prompt, `"change_spans"`) || !strings.Contains(prompt, "change_spans: one entry per change") {
+			t.Error("expected change spans schema and constraint in prompt")
+		}
+		if !strings.Contains(prompt, `"additionalProperties": false`) {
+			t.Error("expected schema to stay strict")
+		}
+	})
+
+	t.Run("grounded spans accepted", func(t *testing.T) {
+		synapse, err := Transform("formalize", respond(`[
+			{"before": "gonna", "after": "will", "kind": "reworded"},
+			{"before": "very big", "after": "", "kind": "removed"},
+			{"before": "", "after": "Please note", "kind": "added"},
+			{"before": "on Friday", "after": "Friday", "kind": "reordered"}
+		]`), WithChangeSpans())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithDetails(context.Background(), NewSession(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.ChangeSpans) != 4 || response.ChangeSpans[1].Kind != ChangeRemoved {
+			t.Errorf("unexpected spans: %+v", response.ChangeSpans)
+		}
+	})
+
+	t.Run("ungrounded spans dropped with a note", func(t *testing.T) {
+		synapse, err := Transform("formalize", respond(`[
+			{"before": "gonna", "after": "will", "kind": "reworded"},
+			{"before": "on Monday", "after": "", "kind": "removed"},
+			{"before": "very big", "after": "huge", "kind": "reworded"}
+		]`), WithChangeSpans())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		session := NewSession()
+		response, err := synapse.FireWithDetails(context.Background(), session, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(response.ChangeSpans) != 1 || response.ChangeSpans[0].Before != "gonna" {
+			t.Errorf("expected only the grounded span kept, got %+v", response.ChangeSpans)
+		}
+		want := []string{
+			"r",
+			`change span dropped: before text "on Monday" does not appear in the input`,
+			`change span dropped: after text "huge" does not appear in the output`,
+		}
+		if !slices.Equal(response.Reasoning, want) {
+			t.Errorf("expected dropped spans noted, got %q", response.Reasoning)
+		}
+		if session.Len() != 2 {
+			t.Errorf("expected the exchange recorded, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("requested per call", func(t *testing.T) {
+		var prompts []string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompts = append(prompts, p)
+			return `{"output": "We will ship the update on Friday. Kind regards.", "confidence": 0.9, "changes": ["formal tone"], "change_spans": [
+				{"before": "gonna", "after": "will", "kind": "reworded"},
+				{"before": "", "after": "Kind regards.", "kind": "added"}
+			], "reasoning": ["r"]}`, nil
+		})
+		synapse, err := Transform("formalize", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		response, err := synapse.FireWithInputDetails(context.Background(), NewSession(), TransformInput{
+			Text:         "We are gonna ship the update on Friday.",
+			TrackChanges: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []ChangeSpan{
+			{Before: "gonna", After: "will", Kind: ChangeReworded},
+			{After: "Kind regards.", Kind: ChangeAdded},
+		}
+		if !slices.Equal(response.ChangeSpans, want) {
+			t.Errorf("expected both edits tracked, got %+v", response.ChangeSpans)
+		}
+		if strings.Count(prompts[0], "change_spans: one entry per change") != 1 || !strings.Contains(prompts[0], `"change_spans"`) {
+			t.Error("expected change spans schema and constraint in prompt")
+		}
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "We are gonna ship."); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompts[1], "change_spans") {
+			t.Error("expected change spans requested only for the tracked call")
+		}
+	})
+
+	t.Run("unknown kind rejected", func(t *testing.T) {
+		synapse, err := Transform("formalize", respond(`[{"before": "gonna", "after": "will", "kind": "swapped"}]`), WithChangeSpans())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.FireWithDetails(context.Background(), NewSession(), input); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected invalid response, got %v", err)
+		}
+	})
+
+	t.Run("other synapses unaffected", func(t *testing.T) {
+		var prompt string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompt = p
+			return `{"decision": true, "confidence": 0.9, "reasoning": ["r"]}`, nil
+		})
+		synapse, err := Binary("is it formal?", provider, WithChangeSpans())
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+		if _, err := synapse.Fire(context.Background(), NewSession(), input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(prompt, "change_spans") {
+			t.Error("expected binary prompt without change spans")
+		}
+	})
+}
+
+func TestQuoteAppears(t *testing.T) {
+	text := "The quick  brown\nfox"
+	if !quoteAppears(text, "quick brown fox") {
+		t.Error("expected whitespace differences to be ignored")
+	}
+	if quoteAppears(text, "quick red fox") {
+		t.Error("expected absent quote to be rejected")
+	}
+}
+
+func TestTransformSynapse_ProtectedPatterns(t *testing.T) {
+	respond := func(outputs ...string) (Provider, *[]string) {
+		var prompts []string
+		return NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompts = append(prompts, p)
+			output := outputs[min(len(prompts), len(outputs))-1]
+			return fmt.Sprintf(`{"output": %q, "confidence": 0.9, "changes": ["translated"], "reasoning": ["r"]}`, output), nil
+		}), &prompts
+	}
+	input := TransformInput{
+		Text:              "Hello {{first_name}}, your order {{order_id}} shipped",
+		ProtectedPatterns: []string{"{{...}}"},
+	}
+
+	t.Run("tokens kept", func(t *testing.T) {
+		provider, prompts := respond("Hola {{first_name}}, tu pedido {{order_id}} fue enviado")
+		synapse, err := Transform("Translate to Spanish", provider)
+		if err != nil {
+			t.Fatalf("failed to create synapse: %v", err)
+		}
+
+		output, err := synapse.FireWithInput(context.Background(), NewSession(), input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "Hola {{first_name}}, tu pedido {{order_id}} fue enviado" {
+			t.Errorf("unexpected output: %q", output)
+		}
+		if !strings.Contains((*prompts)[0], "keep these tokens exactly as written, never translated or altered: {{first_name}}, {{order_id}}") {
+			t.Errorf("expected tokens listed in prompt, got %s", (*prompts)[0])
+		}
+	})
+
+	t.Run("translated token rejected", func(t *testing.T) {
+		provider, _ := respond("Hola {{nombre}}, tu pedido {{order_id}} fue enviado")
+		synapse, _ := Transform("Translate to Spanish", provider)
+
+		session := NewSession()
+		_, err := synapse.FireWithInput(context.Background(), session, input)
+		var changed *ProtectedTokensError
+		if !errors.Is(err, ErrInvalidResponse) || !errors.As(err, &changed) || !slices.Equal(changed.Missing, []string{"{{first_name}}"}) {
+			t.Errorf("expected the translated token reported, got %v", err)
+		}
+		if session.Len() != 0 {
+			t.Errorf("expected session untouched, got %d messages", session.Len())
+		}
+	})
+
+	t.Run("retried with WithValidationRetry", func(t *testing.T) {
+		provider, prompts := respond("%s tiene artículos", "%s tiene %d artículos")
+		synapse, _ := Transform("Translate to Spanish", provider, WithValidationRetry(2))
+
+		output, err := synapse.FireWithInput(context.Background(), NewSession(), TransformInput{
+			Text:              "%s has %d items",
+			ProtectedPatterns: []string{"%[sd]"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "%s tiene %d artículos" || len(*prompts) != 2 {
+			t.Errorf("expected tokens kept after a retry, got %q in %d calls", output, len(*prompts))
+		}
+	})
+
+	t.Run("shell variables with a length limit", func(t *testing.T) {
+		provider, _ := respond("Ejecuta ${HOME}/bin como ${USER} ahora mismo, por favor")
+		synapse, _ := Transform("Translate to Spanish", provider)
+
+		_, err := synapse.FireWithInput(context.Background(), NewSession(), TransformInput{
+			Text:              "Run ${HOME}/bin as ${USER} and ${USER}",
+			ProtectedPatterns: []string{"${...}"},
+			MaxLength:         100,
+		})
+		var changed *ProtectedTokensError
+		if !errors.As(err, &changed) || !slices.Equal(changed.Missing, []string{"${USER}"}) {
+			t.Errorf("expected a dropped repeat of ${USER} reported, got %v", err)
+		}
+	})
+
+	t.Run("no tokens in input", func(t *testing.T) {
+		provider, prompts := respond("Hola")
+		synapse, _ := Transform("Translate to Spanish", provider)
+
+		if _, err := synapse.FireWithInput(context.Background(), NewSession(), TransformInput{
+			Text:              "Hello",
+			ProtectedPatterns: []string{"{{...}}"},
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains((*prompts)[0], "keep these tokens") {
+			t.Error("expected no token constraint without tokens")
+		}
+	})
+
+	t.Run("invalid pattern rejected before the call", func(t *testing.T) {
+		provider, prompts := respond("Hola")
+		synapse, _ := Transform("Translate to Spanish", provider)
+
+		_, err := synapse.FireWithInput(context.Background(), NewSession(), TransformInput{
+			Text:              "Hello",
+			ProtectedPatterns: []string{"(unclosed"},
+		})
+		if !errors.Is(err, ErrInvalidPrompt) || len(*prompts) != 0 {
+			t.Errorf("expected invalid prompt without a call, got %v after %d calls", err, len(*prompts))
+		}
+	})
+}
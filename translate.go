@@ -0,0 +1,250 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// TranslateInput contains rich input structure for translation.
+type TranslateInput struct {
+	Text               string            // The text to translate
+	SourceLang         string            // Optional source language; detected when empty
+	TargetLang         string            // The language to translate into
+	PreserveFormatting bool              // Keep markdown and HTML structure intact
+	Glossary           map[string]string // Source terms and their required rendering; map a term to itself to keep it untranslated
+	Context            string            // Optional context, such as the text's audience
+	Temperature        float32           // LLM temperature setting for this specific request
+}
+
+// TranslateResponse contains the response from a translate synapse.
+type TranslateResponse struct {
+	Output             string   `json:"output"`               // The translated text
+	DetectedSourceLang string   `json:"detected_source_lang"` // The language the text was in
+	Confidence         float64  `json:"confidence"`           // 0.0 to 1.0 confidence score
+	Notes              []string `json:"notes"`                // Translation choices worth reviewing, such as idioms
+}
+
+// Validate checks if the response is valid. The output is checked against
+// the input by the synapse.
+func (r TranslateResponse) Validate() error {
+	if strings.TrimSpace(r.Output) == "" {
+		return fmt.Errorf("output required but empty")
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	return nil
+}
+
+// TranslateSynapse translates text into a target language.
+type TranslateSynapse struct {
+	defaults TranslateInput
+	base     *Base[TranslateInput, TranslateResponse]
+}
+
+// NewTranslate creates a new translate synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewTranslate(provider Provider, opts ...Option) (*TranslateSynapse, error) {
+	synapse := &TranslateSynapse{}
+
+	base, err := NewSynapse(SynapseConfig[TranslateInput, TranslateResponse]{
+		Type:        "translate",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (t *TranslateSynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return t.base.GetPipeline()
+}
+
+// WithDefaults creates a new Translate with default input values, such as a
+// fixed target language or a shared glossary. These are merged with user
+// input at execution time; glossary entries from both apply, the input's
+// taking precedence.
+func (t *TranslateSynapse) WithDefaults(defaults TranslateInput) *TranslateSynapse {
+	t.defaults = defaults
+	return t
+}
+
+// Fire translates text into targetLang and returns the translation.
+func (t *TranslateSynapse) Fire(ctx context.Context, session *Session, text, targetLang string) (string, error) {
+	response, err := t.FireWithInput(ctx, session, TranslateInput{Text: text, TargetLang: targetLang})
+	if err != nil {
+		return "", err
+	}
+	return response.Output, nil
+}
+
+// FireResult translates text into targetLang and returns the translation in
+// a Result envelope carrying the call's usage, timing, and request metadata.
+func (t *TranslateSynapse) FireResult(ctx context.Context, session *Session, text, targetLang string) (Result[string], error) {
+	result, err := t.execute(ctx, session, TranslateInput{Text: text, TargetLang: targetLang})
+	if err != nil {
+		return withValue(result, ""), err
+	}
+	return withValue(result, result.Value.Output), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (t *TranslateSynapse) FireWithInput(ctx context.Context, session *Session, input TranslateInput) (TranslateResponse, error) {
+	result, err := t.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, translating into
+// the default target language. The returned Validator is a TranslateResponse.
+func (t *TranslateSynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := t.FireWithInput(ctx, session, TranslateInput{
+		Text:        input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults and translates, rejecting
+// responses that leave the text untranslated or break the glossary.
+func (t *TranslateSynapse) execute(ctx context.Context, session *Session, input TranslateInput) (Result[TranslateResponse], error) {
+	merged := t.mergeInputs(input)
+	if merged.TargetLang == "" {
+		return Result[TranslateResponse]{Provider: t.base.service.providerName},
+			fmt.Errorf("%w: translate synapse needs a target language", ErrInvalidPrompt)
+	}
+
+	prompt := t.buildPrompt(merged)
+	prompt.Schema = t.base.Schema()
+	return t.base.service.executeChecked(ctx, session, prompt, merged.Temperature, func(response TranslateResponse) error {
+		return checkTranslation(merged, response)
+	})
+}
+
+// checkTranslation rejects output identical to the input when the languages
+// differ, and output missing the required rendering of a glossary term that
+// appears in the input.
+func checkTranslation(input TranslateInput, response TranslateResponse) error {
+	source := input.SourceLang
+	if source == "" {
+		source = response.DetectedSourceLang
+	}
+	if !sameLanguage(source, input.TargetLang) &&
+		strings.Join(strings.Fields(response.Output), " ") == strings.Join(strings.Fields(input.Text), " ") {
+		return fmt.Errorf("output is identical to the input, not translated into %s", input.TargetLang)
+	}
+
+	for _, term := range slices.Sorted(maps.Keys(input.Glossary)) {
+		rendering := input.Glossary[term]
+		if strings.Contains(input.Text, term) && !strings.Contains(response.Output, rendering) {
+			return fmt.Errorf("glossary term %q must appear as %q", term, rendering)
+		}
+	}
+	return nil
+}
+
+// sameLanguage reports whether two language names are the same, ignoring
+// case and surrounding space. An unknown language matches nothing.
+func sameLanguage(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
+
+// mergeInputs combines defaults with user input.
+func (t *TranslateSynapse) mergeInputs(input TranslateInput) TranslateInput {
+	merged := t.defaults
+
+	if input.Text != "" {
+		merged.Text = input.Text
+	}
+	if input.SourceLang != "" {
+		merged.SourceLang = input.SourceLang
+	}
+	if input.TargetLang != "" {
+		merged.TargetLang = input.TargetLang
+	}
+	if input.PreserveFormatting {
+		merged.PreserveFormatting = true
+	}
+	if len(input.Glossary) > 0 {
+		merged.Glossary = maps.Clone(t.defaults.Glossary)
+		if merged.Glossary == nil {
+			merged.Glossary = make(map[string]string, len(input.Glossary))
+		}
+		maps.Copy(merged.Glossary, input.Glossary)
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input.
+func (t *TranslateSynapse) buildPrompt(input TranslateInput) *Prompt {
+	task := fmt.Sprintf("Translate the text into %s", input.TargetLang)
+	if input.SourceLang != "" {
+		task = fmt.Sprintf("Translate the text from %s into %s", input.SourceLang, input.TargetLang)
+	}
+
+	constraints := []string{
+		fmt.Sprintf("output: the full text in %s, with nothing left in the source language", input.TargetLang),
+		"translate meaning and tone, not word by word",
+		"detected_source_lang: the language the text is written in, named the way the target language is",
+		"confidence: 0.0 to 1.0",
+		"notes: translation choices worth a reviewer's attention, such as idioms or ambiguous terms; empty if none",
+	}
+	if input.PreserveFormatting {
+		constraints = append(constraints,
+			"keep markdown and HTML structure exactly: tags, attributes, links, code spans and blocks, "+
+				"list markers, headings, and line breaks stay as they are; translate only the text between them")
+	}
+	for _, term := range slices.Sorted(maps.Keys(input.Glossary)) {
+		if rendering := input.Glossary[term]; rendering == term {
+			constraints = append(constraints, fmt.Sprintf("never translate %q; keep it exactly as written", term))
+		} else {
+			constraints = append(constraints, fmt.Sprintf("always render %q as %q", term, rendering))
+		}
+	}
+
+	return &Prompt{
+		Task:        task,
+		Input:       input.Text,
+		Context:     input.Context,
+		Constraints: constraints,
+	}
+}
+
+// Translate creates a new translate synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	translator, err := Translate(provider)
+//	response, err := translator.FireWithInput(ctx, session, TranslateInput{
+//	    Text:               readme,
+//	    TargetLang:         "French",
+//	    PreserveFormatting: true,
+//	    Glossary:           map[string]string{"zyn": "zyn"},
+//	})
+func Translate(provider Provider, opts ...Option) (*TranslateSynapse, error) {
+	return NewTranslate(provider, opts...)
+}
@@ -0,0 +1,176 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTranslateResponse_Validate(t *testing.T) {
+	if err := (TranslateResponse{Output: "Bonjour", Confidence: 0.9}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (TranslateResponse{Output: "  ", Confidence: 0.9}).Validate(); err == nil {
+		t.Error("expected empty output rejected")
+	}
+	if err := (TranslateResponse{Output: "Bonjour", Confidence: 1.1}).Validate(); err == nil {
+		t.Error("expected confidence out of range rejected")
+	}
+}
+
+func TestTranslateSynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"output": "Bonjour le monde", "detected_source_lang": "English", "confidence": 0.95, "notes": []}`, nil
+	})
+	synapse, err := Translate(provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output, err := synapse.Fire(context.Background(), NewSession(), "Hello world", "French")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "Bonjour le monde" {
+		t.Errorf("unexpected output: %q", output)
+	}
+	if !strings.Contains(prompt, "Task: Translate the text into French") || !strings.Contains(prompt, "Input: Hello world") {
+		t.Errorf("unexpected prompt: %s", prompt)
+	}
+	if strings.Contains(prompt, "markdown") {
+		t.Errorf("expected no formatting constraint by default, got %s", prompt)
+	}
+}
+
+func TestTranslateSynapse_FireWithInput(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return `{"output": "## Installer zyn\n\nLancez ` + "`go get`" + `.", "detected_source_lang": "English", "confidence": 0.9, "notes": ["kept the heading level"]}`, nil
+	})
+	synapse, _ := Translate(provider)
+	synapse.WithDefaults(TranslateInput{TargetLang: "French", Glossary: map[string]string{"zyn": "zyn"}})
+
+	response, err := synapse.FireWithInput(context.Background(), NewSession(), TranslateInput{
+		Text:               "## Install zyn\n\nRun `go get`.",
+		SourceLang:         "English",
+		PreserveFormatting: true,
+		Glossary:           map[string]string{"pull request": "demande de fusion"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.DetectedSourceLang != "English" || len(response.Notes) != 1 {
+		t.Errorf("unexpected response: %+v", response)
+	}
+	for _, want := range []string{
+		"Translate the text from English into French",
+		"keep markdown and HTML structure exactly",
+		`always render "pull request" as "demande de fusion"`,
+		`never translate "zyn"`,
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestTranslateSynapse_RejectsUntranslated(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    TranslateInput
+		response string
+		valid    bool
+	}{
+		{
+			name:     "identical",
+			input:    TranslateInput{Text: "Hello world", TargetLang: "French"},
+			response: `{"output": "Hello  world", "detected_source_lang": "English", "confidence": 0.9}`,
+		},
+		{
+			name:     "identical, source unknown",
+			input:    TranslateInput{Text: "Hello world", TargetLang: "French"},
+			response: `{"output": "Hello world", "detected_source_lang": "", "confidence": 0.9}`,
+		},
+		{
+			name:     "already in the target language",
+			input:    TranslateInput{Text: "Bonjour", TargetLang: "french"},
+			response: `{"output": "Bonjour", "detected_source_lang": "French", "confidence": 0.9}`,
+			valid:    true,
+		},
+		{
+			name:     "same languages given",
+			input:    TranslateInput{Text: "Bonjour", SourceLang: "fr", TargetLang: "FR"},
+			response: `{"output": "Bonjour", "detected_source_lang": "", "confidence": 0.9}`,
+			valid:    true,
+		},
+		{
+			name:     "glossary broken",
+			input:    TranslateInput{Text: "Open a pull request", TargetLang: "French", Glossary: map[string]string{"pull request": "pull request"}},
+			response: `{"output": "Ouvrez une demande de tirage", "detected_source_lang": "English", "confidence": 0.9}`,
+		},
+		{
+			name:     "glossary term absent",
+			input:    TranslateInput{Text: "Hello", TargetLang: "French", Glossary: map[string]string{"pull request": "pull request"}},
+			response: `{"output": "Bonjour", "detected_source_lang": "English", "confidence": 0.9}`,
+			valid:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synapse, _ := Translate(NewMockProviderWithResponse(tt.response))
+			session := NewSession()
+			_, err := synapse.FireWithInput(context.Background(), session, tt.input)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && (!errors.Is(err, ErrInvalidResponse) || session.Len() != 0) {
+				t.Errorf("expected ErrInvalidResponse with the session untouched, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslateSynapse_ValidationRetry(t *testing.T) {
+	var prompts []string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompts = append(prompts, p)
+		if len(prompts) == 1 {
+			return `{"output": "Hello world", "detected_source_lang": "English", "confidence": 0.9}`, nil
+		}
+		return `{"output": "Hallo Welt", "detected_source_lang": "English", "confidence": 0.9}`, nil
+	})
+	synapse, _ := Translate(provider, WithValidationRetry(2))
+
+	output, err := synapse.Fire(context.Background(), NewSession(), "Hello world", "German")
+	if err != nil || output != "Hallo Welt" {
+		t.Fatalf("expected the retried translation, got %q, %v", output, err)
+	}
+	if !strings.Contains(prompts[1], "not translated into German") {
+		t.Errorf("expected the rejection fed back, got %s", prompts[1])
+	}
+}
+
+func TestTranslateSynapse_RequiresTarget(t *testing.T) {
+	synapse, _ := Translate(NewMockProvider())
+	if _, err := synapse.Fire(context.Background(), NewSession(), "Hello", ""); !errors.Is(err, ErrInvalidPrompt) {
+		t.Errorf("expected ErrInvalidPrompt, got %v", err)
+	}
+}
+
+func TestTranslateSynapse_Invoke(t *testing.T) {
+	provider := NewMockProviderWithResponse(`{"output": "Hola", "detected_source_lang": "English", "confidence": 0.9}`)
+	synapse, _ := Translate(provider)
+	synapse.WithDefaults(TranslateInput{TargetLang: "Spanish"})
+
+	result, err := synapse.Invoke(context.Background(), NewSession(), SynapseInput{Input: "Hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := result.(TranslateResponse); !ok || response.Output != "Hola" {
+		t.Errorf("expected a TranslateResponse, got %+v", result)
+	}
+}
@@ -0,0 +1,72 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoobzio/pipz"
+)
+
+// validationRetryID identifies the validation retry stage.
+var validationRetryID = pipz.NewIdentity("zyn:validation-retry", "Retries responses that fail parsing or validation")
+
+// WithValidationRetry retries responses the synapse would reject: a response
+// that cannot be parsed, or whose value fails its Validate method or the
+// synapse's own checks, is asked for again, up to maxAttempts calls in all.
+// Each retry adds the rejection to the prompt's constraints so the model can
+// correct itself.
+//
+// Every call counts toward Result.Attempts and their usage is summed. When
+// the last response is still rejected, the synapse reports it as usual, with
+// ErrParseFailed or ErrInvalidResponse. Failed calls are not retried here;
+// combine with WithRetry for those.
+func WithValidationRetry(maxAttempts int) Option {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return func(pipeline pipz.Chainable[*SynapseRequest]) pipz.Chainable[*SynapseRequest] {
+		return pipz.Apply(validationRetryID, func(ctx context.Context, req *SynapseRequest) (*SynapseRequest, error) {
+			if req.validate == nil || previewing(ctx) {
+				return pipeline.Process(ctx, req)
+			}
+			return retryInvalid(ctx, pipeline, req, maxAttempts)
+		})
+	}
+}
+
+// retryInvalid calls the pipeline until req.validate accepts the response or
+// maxAttempts calls are made, and sets the last response as req's.
+func retryInvalid(ctx context.Context, pipeline pipz.Chainable[*SynapseRequest], req *SynapseRequest, maxAttempts int) (*SynapseRequest, error) {
+	var usage TokenUsage
+	prompt := req.Prompt
+	for attempt := 1; ; attempt++ {
+		call := *req
+		call.Prompt = prompt
+		call.Response, call.Usage, call.Error = "", nil, nil
+		call.Attempts, call.EstimatedPromptTokens = 0, 0
+
+		_, err := pipeline.Process(ctx, &call)
+		req.Attempts += call.Attempts
+		addUsage(&usage, call.Usage)
+		if err != nil {
+			req.Usage = &usage
+			return req, err
+		}
+
+		req.Response, req.ToolCalls, req.Logprobs = call.Response, call.ToolCalls, call.Logprobs
+		req.EstimatedPromptTokens = call.EstimatedPromptTokens
+		req.Usage = &usage
+		if len(call.ToolCalls) > 0 || attempt >= maxAttempts {
+			return req, nil
+		}
+
+		invalid := req.validate(call.Response)
+		if invalid == nil {
+			return req, nil
+		}
+		retry := *req.Prompt
+		retry.Constraints = append(append([]string(nil), req.Prompt.Constraints...),
+			fmt.Sprintf("A previous response was rejected (%v); respond again, fixing the problem", invalid))
+		prompt = &retry
+	}
+}
@@ -0,0 +1,118 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithValidationRetry(t *testing.T) {
+	t.Run("recovers", func(t *testing.T) {
+		var prompts []string
+		provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+			prompts = append(prompts, p)
+			switch len(prompts) {
+			case 1:
+				return `not json`, nil
+			case 2:
+				return `{"decision": true, "confidence": 1.5, "reasoning": ["sure"]}`, nil
+			default:
+				return `{"decision": true, "confidence": 0.9, "reasoning": ["sure"]}`, nil
+			}
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithValidationRetry(3))
+
+		session := NewSession()
+		result, err := synapse.FireResult(context.Background(), session, "input")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Value || result.Attempts != 3 {
+			t.Errorf("expected true after 3 attempts, got %v after %d", result.Value, result.Attempts)
+		}
+		if result.Usage == nil || result.Usage.Total != 450 {
+			t.Errorf("expected the usage of every call, got %+v", result.Usage)
+		}
+		if !strings.Contains(prompts[1], "parse") || !strings.Contains(prompts[2], "confidence") {
+			t.Errorf("expected each rejection fed back, got %v", prompts[1:])
+		}
+		if session.Len() != 2 || strings.Contains(session.Messages()[0].Content, "rejected") {
+			t.Errorf("expected the original prompt recorded once, got %v", session.Messages())
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		var calls int
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			return `{"decision": true, "confidence": 2, "reasoning": ["sure"]}`, nil
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithValidationRetry(2))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); !errors.Is(err, ErrInvalidResponse) {
+			t.Errorf("expected ErrInvalidResponse, got %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("synapse checks", func(t *testing.T) {
+		var calls int
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			if calls == 1 {
+				return `{"decision": true, "score": 0.2, "confidence": 0.9, "reasoning": ["x"]}`, nil
+			}
+			return `{"decision": true, "score": 0.8, "confidence": 0.9, "reasoning": ["x"]}`, nil
+		})
+		synapse, _ := Binary("Is this spam?", provider, WithBinaryScore(), WithValidationRetry(2))
+
+		score, err := synapse.FireScore(context.Background(), NewSession(), "WIN A PRIZE")
+		if err != nil || score != 0.8 || calls != 2 {
+			t.Errorf("expected the contradicting score retried, got %v after %d calls, %v", score, calls, err)
+		}
+	})
+
+	t.Run("provider errors", func(t *testing.T) {
+		provider := NewMockProviderWithName("mock")
+		provider.SetAvailable(false)
+		synapse, _ := Binary("Is this valid?", provider, WithValidationRetry(3))
+
+		result, err := synapse.FireResult(context.Background(), NewSession(), "input")
+		if err == nil || result.Attempts != 1 {
+			t.Errorf("expected one failed call, got %d attempts and %v", result.Attempts, err)
+		}
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		var calls int
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", errors.New("connection reset")
+			}
+			return `{"decision": false, "confidence": 0.9, "reasoning": ["no"]}`, nil
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithValidationRetry(2), WithRetry(2))
+
+		if _, err := synapse.Fire(context.Background(), NewSession(), "input"); err != nil || calls != 2 {
+			t.Errorf("expected the failed call retried, got %d calls and %v", calls, err)
+		}
+	})
+
+	t.Run("preview", func(t *testing.T) {
+		var calls int
+		provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+			calls++
+			return `invalid`, nil
+		})
+		synapse, _ := Binary("Is this valid?", provider, WithValidationRetry(3))
+
+		previews, err := previewSynapse(synapse, SynapseInput{Input: "input"})
+		if err != nil || len(previews) != 1 || calls != 0 {
+			t.Errorf("expected one preview and no calls, got %d previews, %d calls, %v", len(previews), calls, err)
+		}
+	})
+}
@@ -0,0 +1,348 @@
+package zyn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zoobzio/pipz"
+)
+
+// Verdicts a verify synapse gives a claim.
+const (
+	VerdictSupported    = "supported"    // The evidence states or directly implies the claim
+	VerdictContradicted = "contradicted" // The evidence states the opposite of the claim
+	VerdictUnverifiable = "unverifiable" // The evidence neither supports nor contradicts the claim
+)
+
+// VerifyInput contains rich input structure for claim verification.
+// The evidence is Evidence, Documents, or both.
+type VerifyInput struct {
+	Claims      []string   // The claims to check
+	Evidence    string     // Source text the claims are checked against
+	Documents   []Document // Source documents the claims are checked against
+	Context     string     // Optional context, such as how the claims were produced
+	Temperature float32    // LLM temperature setting for this specific request
+}
+
+// Verdict is the verdict on one claim.
+type Verdict struct {
+	Claim   string `json:"claim"`   // The claim, as given
+	Verdict string `json:"verdict"` // One of VerdictSupported, VerdictContradicted, VerdictUnverifiable
+	Quote   string `json:"quote"`   // Evidence text the verdict rests on; empty for unverifiable claims
+}
+
+// VerifyResponse contains the response from a verify synapse.
+type VerifyResponse struct {
+	Verdicts   []Verdict `json:"verdicts"`   // One verdict per claim, in claim order
+	Confidence float64   `json:"confidence"` // 0.0 to 1.0 confidence score
+	Reasoning  []string  `json:"reasoning"`  // Explanation of the verdicts
+}
+
+// Validate checks if the response is valid. Claims and quotes are checked
+// against the input by the synapse.
+func (r VerifyResponse) Validate() error {
+	if len(r.Verdicts) == 0 {
+		return fmt.Errorf("verdicts required but empty")
+	}
+	for i, verdict := range r.Verdicts {
+		if strings.TrimSpace(verdict.Claim) == "" {
+			return fmt.Errorf("verdict %d: claim required but empty", i)
+		}
+		switch normalizeVerdict(verdict.Verdict) {
+		case VerdictSupported, VerdictContradicted:
+			if strings.TrimSpace(verdict.Quote) == "" {
+				return fmt.Errorf("verdict %d: %s verdict requires a quote", i, normalizeVerdict(verdict.Verdict))
+			}
+		case VerdictUnverifiable:
+		default:
+			return fmt.Errorf("verdict %d: verdict must be supported, contradicted, or unverifiable, got %q", i, verdict.Verdict)
+		}
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		return fmt.Errorf("confidence must be 0-1, got %f", r.Confidence)
+	}
+	if len(r.Reasoning) == 0 {
+		return fmt.Errorf("reasoning required but empty")
+	}
+	return nil
+}
+
+// Supported reports whether every verdict is VerdictSupported.
+func (r VerifyResponse) Supported() bool {
+	for _, verdict := range r.Verdicts {
+		if verdict.Verdict != VerdictSupported {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeVerdict lowercases and trims a verdict.
+func normalizeVerdict(verdict string) string {
+	return strings.ToLower(strings.TrimSpace(verdict))
+}
+
+// claimKey returns the form claims are compared in, ignoring case and
+// differences in whitespace.
+func claimKey(claim string) string {
+	return strings.ToLower(strings.Join(strings.Fields(claim), " "))
+}
+
+// validateVerdicts checks that the response gives exactly one verdict per
+// claim of the prompt, and that every quote appears in the evidence.
+func validateVerdicts(prompt *Prompt, response VerifyResponse) error {
+	claims := make(map[string]bool, len(prompt.Items))
+	for _, claim := range prompt.Items {
+		claims[claimKey(claim)] = false
+	}
+	for i, verdict := range response.Verdicts {
+		key := claimKey(verdict.Claim)
+		judged, ok := claims[key]
+		if !ok {
+			return fmt.Errorf("verdict %d: unknown claim %q", i, verdict.Claim)
+		}
+		if judged {
+			return fmt.Errorf("verdict %d: claim %q judged twice", i, verdict.Claim)
+		}
+		claims[key] = true
+		if verdict.Quote != "" && !quoteInEvidence(prompt, verdict.Quote) {
+			return fmt.Errorf("verdict %d: quote does not appear in the evidence: %q", i, verdict.Quote)
+		}
+	}
+	var missing []string
+	for _, claim := range prompt.Items {
+		if !claims[claimKey(claim)] {
+			missing = append(missing, fmt.Sprintf("%q", claim))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("claims without a verdict: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// quoteInEvidence reports whether quote appears in the prompt's evidence
+// text or in one of its documents.
+func quoteInEvidence(prompt *Prompt, quote string) bool {
+	if quoteAppears(prompt.Input, quote) {
+		return true
+	}
+	for _, doc := range prompt.Documents {
+		if quoteAppears(doc.Text, quote) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifySynapse fact-checks claims against source evidence.
+type VerifySynapse struct {
+	what     string
+	defaults VerifyInput
+	base     *Base[VerifyInput, VerifyResponse]
+}
+
+// NewVerify creates a new verify synapse bound to a provider.
+// Returns an error if the JSON schema cannot be generated.
+func NewVerify(what string, provider Provider, opts ...Option) (*VerifySynapse, error) {
+	synapse := &VerifySynapse{what: what}
+
+	base, err := NewSynapse(SynapseConfig[VerifyInput, VerifyResponse]{
+		Type:        "verify",
+		Temperature: DefaultTemperatureDeterministic,
+		BuildPrompt: synapse.buildPrompt,
+	}, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	base.service.validate = validateVerdicts
+
+	synapse.base = base
+	return synapse, nil
+}
+
+// GetPipeline returns the internal pipeline for composition.
+// Implements ServiceProvider interface.
+func (v *VerifySynapse) GetPipeline() pipz.Chainable[*SynapseRequest] {
+	return v.base.GetPipeline()
+}
+
+// WithDefaults creates a new Verify with default input values.
+// These are merged with user input at execution time.
+func (v *VerifySynapse) WithDefaults(defaults VerifyInput) *VerifySynapse {
+	v.defaults = defaults
+	return v
+}
+
+// Fire checks claims against evidence and returns a verdict per claim.
+func (v *VerifySynapse) Fire(ctx context.Context, session *Session, claims []string, evidence string) ([]Verdict, error) {
+	response, err := v.FireWithInput(ctx, session, VerifyInput{Claims: claims, Evidence: evidence})
+	if err != nil {
+		return nil, err
+	}
+	return response.Verdicts, nil
+}
+
+// FireResult checks claims against evidence and returns the verdicts in a
+// Result envelope carrying the call's usage, timing, and request metadata.
+func (v *VerifySynapse) FireResult(ctx context.Context, session *Session, claims []string, evidence string) (Result[[]Verdict], error) {
+	result, err := v.execute(ctx, session, VerifyInput{Claims: claims, Evidence: evidence})
+	if err != nil {
+		return withValue[VerifyResponse, []Verdict](result, nil), err
+	}
+	return withValue(result, result.Value.Verdicts), nil
+}
+
+// FireWithInput executes the synapse with rich input structure.
+func (v *VerifySynapse) FireWithInput(ctx context.Context, session *Session, input VerifyInput) (VerifyResponse, error) {
+	result, err := v.execute(ctx, session, input)
+	return result.Value, err
+}
+
+// Invoke executes the synapse through the Synapse interface, checking the
+// items as claims against the input as evidence. The returned Validator is a
+// VerifyResponse.
+func (v *VerifySynapse) Invoke(ctx context.Context, session *Session, input SynapseInput) (Validator, error) {
+	response, err := v.FireWithInput(ctx, session, VerifyInput{
+		Claims:      input.Items,
+		Evidence:    input.Input,
+		Context:     input.Context,
+		Temperature: input.Temperature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// execute merges input with the defaults, checks the claims, and returns
+// the verdicts in claim order with claims as given and verdicts lowercased.
+func (v *VerifySynapse) execute(ctx context.Context, session *Session, input VerifyInput) (Result[VerifyResponse], error) {
+	merged := v.mergeInputs(input)
+	if err := checkEvidence(merged); err != nil {
+		return Result[VerifyResponse]{Provider: v.base.service.providerName}, err
+	}
+	result, err := v.base.ExecuteResult(ctx, session, merged, merged.Temperature)
+	if err != nil {
+		return result, err
+	}
+	result.Value.Verdicts = orderVerdicts(merged.Claims, result.Value.Verdicts)
+	return result, nil
+}
+
+// checkEvidence rejects input without claims or evidence, or whose documents
+// share an ID.
+func checkEvidence(input VerifyInput) error {
+	if len(input.Claims) == 0 {
+		return fmt.Errorf("%w: verify synapse needs claims", ErrInvalidPrompt)
+	}
+	if strings.TrimSpace(input.Evidence) == "" && len(input.Documents) == 0 {
+		return fmt.Errorf("%w: verify synapse needs evidence or documents", ErrInvalidPrompt)
+	}
+	seen := make(map[string]bool, len(input.Documents))
+	for _, doc := range input.Documents {
+		if seen[doc.ID] {
+			return fmt.Errorf("%w: duplicate document ID %q", ErrInvalidPrompt, doc.ID)
+		}
+		seen[doc.ID] = true
+	}
+	return nil
+}
+
+// orderVerdicts returns validated verdicts in the order of claims, with
+// each claim as given and each verdict lowercased.
+func orderVerdicts(claims []string, verdicts []Verdict) []Verdict {
+	byClaim := make(map[string]Verdict, len(verdicts))
+	for _, verdict := range verdicts {
+		byClaim[claimKey(verdict.Claim)] = verdict
+	}
+	ordered := make([]Verdict, 0, len(claims))
+	for _, claim := range claims {
+		verdict := byClaim[claimKey(claim)]
+		verdict.Claim = claim
+		verdict.Verdict = normalizeVerdict(verdict.Verdict)
+		ordered = append(ordered, verdict)
+	}
+	return ordered
+}
+
+// mergeInputs combines defaults with user input. Repeated claims are dropped,
+// keeping the first of each, and documents without an ID are given their
+// position.
+func (v *VerifySynapse) mergeInputs(input VerifyInput) VerifyInput {
+	merged := v.defaults
+
+	if len(input.Claims) > 0 {
+		merged.Claims = input.Claims
+	}
+	if input.Evidence != "" {
+		merged.Evidence = input.Evidence
+	}
+	if len(input.Documents) > 0 {
+		merged.Documents = input.Documents
+	}
+	if input.Context != "" {
+		merged.Context = input.Context
+	}
+	if input.Temperature != 0 && input.Temperature != TemperatureUnset {
+		merged.Temperature = input.Temperature
+	}
+
+	claims := make([]string, 0, len(merged.Claims))
+	seen := make(map[string]bool, len(merged.Claims))
+	for _, claim := range merged.Claims {
+		if key := claimKey(claim); key != "" && !seen[key] {
+			seen[key] = true
+			claims = append(claims, claim)
+		}
+	}
+	merged.Claims = claims
+
+	documents := make([]Document, len(merged.Documents))
+	for i, doc := range merged.Documents {
+		if doc.ID == "" {
+			doc.ID = fmt.Sprintf("doc-%d", i+1)
+		}
+		documents[i] = doc
+	}
+	merged.Documents = documents
+
+	return merged
+}
+
+// buildPrompt constructs the prompt from the merged input. The claims are
+// the items and the evidence text is the input.
+func (v *VerifySynapse) buildPrompt(input VerifyInput) *Prompt {
+	return &Prompt{
+		Task:      fmt.Sprintf("Check each claim about %s against the evidence only", v.what),
+		Input:     input.Evidence,
+		Context:   input.Context,
+		Items:     input.Claims,
+		Documents: input.Documents,
+		Constraints: []string{
+			"verdicts: exactly one per item, with claim copied exactly from the items",
+			"verdict: supported if the evidence states or directly implies the claim, contradicted if it states otherwise, unverifiable if it does neither",
+			"judge from the evidence only, not from prior knowledge; a claim that is true but not in the evidence is unverifiable",
+			"quote: for supported and contradicted claims, the evidence text the verdict rests on, copied exactly; empty for unverifiable claims",
+			"confidence: 0.0 to 1.0",
+			"reasoning: ordered steps explaining the verdicts",
+		},
+	}
+}
+
+// Verify creates a new verify synapse bound to a provider.
+// The synapse is immediately usable and can be enhanced with options.
+// Returns an error if the JSON schema cannot be generated.
+//
+// Example:
+//
+//	checker, err := Verify("a quarterly report", provider, WithValidationRetry(2))
+//	verdicts, err := checker.Fire(ctx, session, []string{
+//	    "Revenue grew 12%",
+//	    "The company hired a new CFO",
+//	}, report)
+//	// verdicts[0]: {Verdict: "supported", Quote: "revenue rose 12% year over year"}
+func Verify(what string, provider Provider, opts ...Option) (*VerifySynapse, error) {
+	return NewVerify(what, provider, opts...)
+}
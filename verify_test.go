@@ -0,0 +1,284 @@
+package zyn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// reportEvidence is the evidence of the verify tests.
+const reportEvidence = "In the third quarter revenue rose 12% year over year.\nOperating costs fell slightly."
+
+// reportClaims are the claims of the verify tests.
+var reportClaims = []string{"Revenue grew 12%", "Costs went up", "The company hired a new CFO"}
+
+// validReportVerdicts is a valid verify response for reportClaims, out of
+// claim order.
+const validReportVerdicts = `{
+	"verdicts": [
+		{"claim": "the company hired a new CFO", "verdict": "unverifiable", "quote": ""},
+		{"claim": "Revenue grew 12%", "verdict": "Supported", "quote": "revenue rose 12%   year over year"},
+		{"claim": "Costs went up", "verdict": "contradicted", "quote": "Operating costs fell slightly."}
+	],
+	"confidence": 0.9,
+	"reasoning": ["checked each claim"]
+}`
+
+func TestVerifyResponse_Validate(t *testing.T) {
+	t.Run("valid_response", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "supported", Quote: "a"}, {Claim: "b", Verdict: "unverifiable"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected valid response, got error: %v", err)
+		}
+	})
+
+	t.Run("no_verdicts", func(t *testing.T) {
+		r := VerifyResponse{
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for no verdicts")
+		}
+	})
+
+	t.Run("empty_claim", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: " ", Verdict: "unverifiable"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a blank claim")
+		}
+	})
+
+	t.Run("unknown_verdict", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "true", Quote: "a"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for an unknown verdict")
+		}
+	})
+
+	t.Run("supported_without_quote", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "supported"}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a supported claim without a quote")
+		}
+	})
+
+	t.Run("contradicted_without_quote", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "contradicted", Quote: " "}},
+			Confidence: 0.8,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for a contradicted claim with a blank quote")
+		}
+	})
+
+	t.Run("confidence_too_low", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "supported", Quote: "a"}, {Claim: "b", Verdict: "unverifiable"}},
+			Confidence: -0.1,
+			Reasoning:  []string{"x"},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for negative confidence")
+		}
+	})
+
+	t.Run("empty_reasoning", func(t *testing.T) {
+		r := VerifyResponse{
+			Verdicts:   []Verdict{{Claim: "a", Verdict: "supported", Quote: "a"}, {Claim: "b", Verdict: "unverifiable"}},
+			Confidence: 0.8,
+			Reasoning:  []string{},
+		}
+		if err := r.Validate(); err == nil {
+			t.Error("expected error for empty reasoning")
+		}
+	})
+}
+
+func TestVerifyResponse_Supported(t *testing.T) {
+	response := VerifyResponse{Verdicts: []Verdict{{Verdict: VerdictSupported}, {Verdict: VerdictSupported}}}
+	if !response.Supported() {
+		t.Error("expected all claims supported")
+	}
+	response.Verdicts = append(response.Verdicts, Verdict{Verdict: VerdictUnverifiable})
+	if response.Supported() {
+		t.Error("expected an unverifiable claim to count against support")
+	}
+}
+
+func TestVerifySynapse_Fire(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return validReportVerdicts, nil
+	})
+	checker, err := Verify("a quarterly report", provider)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	verdicts, err := checker.Fire(context.Background(), NewSession(), reportClaims, reportEvidence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(verdicts) != 3 {
+		t.Fatalf("expected a verdict per claim, got %+v", verdicts)
+	}
+	for i, want := range []string{VerdictSupported, VerdictContradicted, VerdictUnverifiable} {
+		if verdicts[i].Claim != reportClaims[i] || verdicts[i].Verdict != want {
+			t.Errorf("verdict %d: expected %q for %q, got %+v", i, want, reportClaims[i], verdicts[i])
+		}
+	}
+	for _, want := range []string{"a quarterly report", "Input: In the third quarter", "1. Revenue grew 12%", "3. The company hired a new CFO"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("expected prompt to contain %q, got %s", want, prompt)
+		}
+	}
+}
+
+func TestVerifySynapse_Documents(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return validReportVerdicts, nil
+	})
+	checker, _ := Verify("a quarterly report", provider)
+
+	documents := []Document{{Text: "In the third quarter revenue rose 12% year over year."}, {ID: "costs", Text: "Operating costs fell slightly."}}
+	response, err := checker.FireWithInput(context.Background(), NewSession(), VerifyInput{Claims: reportClaims, Documents: documents})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Supported() {
+		t.Errorf("expected unsupported claims, got %+v", response.Verdicts)
+	}
+	if !strings.Contains(prompt, "[doc-1]") || !strings.Contains(prompt, "[costs]") {
+		t.Errorf("expected the documents with IDs in the prompt, got %s", prompt)
+	}
+}
+
+func TestVerifySynapse_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		verdicts string
+		expected string
+	}{
+		{"missing claim", `{"claim": "Revenue grew 12%", "verdict": "supported", "quote": "revenue rose 12%"},
+			{"claim": "Costs went up", "verdict": "contradicted", "quote": "costs fell"}`, `claims without a verdict: "The company hired a new CFO"`},
+		{"repeated claim", `{"claim": "Revenue grew 12%", "verdict": "supported", "quote": "revenue rose 12%"},
+			{"claim": "revenue grew 12%", "verdict": "unverifiable", "quote": ""}`, `claim "revenue grew 12%" judged twice`},
+		{"unknown claim", `{"claim": "Profit doubled", "verdict": "unverifiable", "quote": ""}`, `unknown claim "Profit doubled"`},
+		{"invented quote", `{"claim": "Revenue grew 12%", "verdict": "supported", "quote": "revenue grew by twelve percent"}`, "quote does not appear in the evidence"},
+		{"supported without quote", `{"claim": "Revenue grew 12%", "verdict": "supported", "quote": ""}`, "supported verdict requires a quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, _ := Verify("a quarterly report", NewMockProviderWithResponse(`{"verdicts": [`+tt.verdicts+`], "confidence": 0.9, "reasoning": ["checked each claim"]}`))
+
+			_, err := checker.Fire(context.Background(), NewSession(), reportClaims, reportEvidence)
+			if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected ErrInvalidResponse naming %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestVerifySynapse_InvalidInput(t *testing.T) {
+	checker, _ := Verify("a quarterly report", NewMockProviderWithResponse(validReportVerdicts))
+
+	tests := []struct {
+		name  string
+		input VerifyInput
+	}{
+		{"no claims", VerifyInput{Evidence: reportEvidence}},
+		{"no evidence", VerifyInput{Claims: reportClaims, Evidence: " "}},
+		{"repeated document ID", VerifyInput{Claims: reportClaims, Documents: []Document{{ID: "a", Text: "x"}, {ID: "a", Text: "y"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := checker.FireWithInput(context.Background(), NewSession(), tt.input); !errors.Is(err, ErrInvalidPrompt) {
+				t.Errorf("expected ErrInvalidPrompt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySynapse_RepeatedClaims(t *testing.T) {
+	var prompt string
+	provider := NewMockProviderWithCallback(func(p string, _ float32) (string, error) {
+		prompt = p
+		return validReportVerdicts, nil
+	})
+	checker, _ := Verify("a quarterly report", provider)
+
+	verdicts, err := checker.Fire(context.Background(), NewSession(), append(reportClaims, "revenue grew  12%"), reportEvidence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(verdicts) != 3 || strings.Contains(prompt, "4.") {
+		t.Errorf("expected repeated claims sent and judged once, got %+v", verdicts)
+	}
+}
+
+func TestVerifySynapse_FireResult(t *testing.T) {
+	checker, _ := Verify("a quarterly report", NewMockProviderWithResponse(validReportVerdicts))
+
+	result, err := checker.FireResult(context.Background(), NewSession(), reportClaims, reportEvidence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Value) != 3 || result.Value[0].Verdict != VerdictSupported {
+		t.Errorf("unexpected result: %+v", result.Value)
+	}
+}
+
+func TestVerifySynapse_Invoke(t *testing.T) {
+	checker, _ := Verify("a quarterly report", NewMockProviderWithResponse(validReportVerdicts))
+
+	validator, err := checker.Invoke(context.Background(), NewSession(), SynapseInput{Input: reportEvidence, Items: reportClaims})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response, ok := validator.(VerifyResponse); !ok || len(response.Verdicts) != 3 {
+		t.Errorf("expected VerifyResponse, got %#v", validator)
+	}
+}
+
+func TestVerifySynapse_ValidationRetry(t *testing.T) {
+	calls := 0
+	provider := NewMockProviderWithCallback(func(string, float32) (string, error) {
+		calls++
+		if calls == 1 {
+			return `{"verdicts": [{"claim": "Revenue grew 12%", "verdict": "supported", "quote": "revenue doubled"}], "confidence": 0.9, "reasoning": ["checked each claim"]}`, nil
+		}
+		return validReportVerdicts, nil
+	})
+	checker, _ := Verify("a quarterly report", provider, WithValidationRetry(2))
+
+	verdicts, err := checker.Fire(context.Background(), NewSession(), reportClaims, reportEvidence)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(verdicts) != 3 {
+		t.Errorf("expected a retry after the invented quote, got %d calls and %+v", calls, verdicts)
+	}
+}
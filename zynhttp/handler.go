@@ -0,0 +1,224 @@
+// Package zynhttp adapts zyn synapses to net/http handlers.
+//
+// A handler decodes a JSON request body, resolves the conversation session
+// from the X-Session-ID header, invokes the synapse, and encodes the full
+// response. Errors are mapped to HTTP status codes using zyn's typed errors.
+//
+// Example:
+//
+//	synapse, _ := zyn.Binary("this email is spam", provider)
+//	store := zyn.NewMemorySessionStore()
+//	http.Handle("/spam", zynhttp.Handler(synapse, store))
+package zynhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/zoobzio/zyn"
+)
+
+// Header names used by the handler.
+const (
+	// SessionHeader selects the conversation session. Requests without it
+	// start a new session; unknown IDs create a session with that ID.
+	// The resolved session ID is always echoed in the response.
+	SessionHeader = "X-Session-ID"
+
+	// RequestIDHeader carries the request ID. An incoming value, such as one
+	// set by a proxy or upstream middleware, is reused; otherwise one is generated.
+	// The ID is also passed to the synapse via zyn.ContextWithRequestID, so it
+	// appears as the parent request ID of the synapse's calls in hook events
+	// and audit records.
+	RequestIDHeader = "X-Request-ID"
+)
+
+// defaultMaxBodyBytes limits request bodies when no limit is configured.
+const defaultMaxBodyBytes = 1 << 20
+
+// Request is the JSON body accepted by the handler.
+type Request struct {
+	Input       string   `json:"input"`                 // The text to process
+	Context     string   `json:"context,omitempty"`     // Optional background information
+	Items       []string `json:"items,omitempty"`       // Items for list-based synapses such as Ranking
+	Temperature float32  `json:"temperature,omitempty"` // Optional temperature override
+}
+
+// Response is the JSON body returned on success.
+type Response struct {
+	RequestID string `json:"request_id"`
+	SessionID string `json:"session_id"`
+	Result    any    `json:"result"` // The synapse's full response (e.g. zyn.BinaryResponse)
+}
+
+// ErrorResponse is the JSON body returned on failure.
+type ErrorResponse struct {
+	RequestID string `json:"request_id"`
+	Error     string `json:"error"`
+}
+
+// Option configures a handler.
+type Option func(*handler)
+
+// WithMaxBodyBytes limits the size of request bodies. The default is 1 MiB.
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *handler) {
+		h.maxBodyBytes = n
+	}
+}
+
+// WithStatusMapper overrides how errors are mapped to HTTP status codes.
+// The mapper may delegate to StatusCode for errors it does not handle.
+func WithStatusMapper(mapper func(error) int) Option {
+	return func(h *handler) {
+		h.statusCode = mapper
+	}
+}
+
+// handler serves a single synapse.
+type handler struct {
+	synapse      zyn.Synapse
+	store        zyn.SessionStore
+	maxBodyBytes int64
+	statusCode   func(error) int
+}
+
+// Handler returns an http.Handler that serves synapse over POST requests.
+// Sessions are loaded from and saved to store; a session is only saved after
+// a successful call. The request's context is passed to the synapse, so
+// cancellation and values set by middleware reach the pipeline and provider.
+func Handler(synapse zyn.Synapse, store zyn.SessionStore, opts ...Option) http.Handler {
+	h := &handler{
+		synapse:      synapse,
+		store:        store,
+		maxBodyBytes: defaultMaxBodyBytes,
+		statusCode:   StatusCode,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	// The synapse's calls record this ID as their parent request ID
+	ctx := zyn.ContextWithRequestID(r.Context(), requestID)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, requestID, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, requestID, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	session, err := h.session(ctx, r.Header.Get(SessionHeader))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, requestID, err)
+		return
+	}
+	w.Header().Set(SessionHeader, session.ID())
+
+	result, err := h.synapse.Invoke(ctx, session, zyn.SynapseInput{
+		Input:       req.Input,
+		Context:     req.Context,
+		Items:       req.Items,
+		Temperature: req.Temperature,
+	})
+	if err != nil {
+		writeError(w, h.statusCode(err), requestID, err)
+		return
+	}
+
+	if err := h.store.Save(ctx, session); err != nil {
+		writeError(w, http.StatusInternalServerError, requestID, fmt.Errorf("failed to save session: %w", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{
+		RequestID: requestID,
+		SessionID: session.ID(),
+		Result:    result,
+	})
+}
+
+// session loads the session with the given ID, creating one if the ID is
+// empty or unknown.
+func (h *handler) session(ctx context.Context, id string) (*zyn.Session, error) {
+	if id == "" {
+		return zyn.NewSession(), nil
+	}
+
+	session, err := h.store.Load(ctx, id)
+	if errors.Is(err, zyn.ErrSessionNotFound) {
+		return zyn.NewSessionWithID(id), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	return session, nil
+}
+
+// StatusCode maps a synapse error to an HTTP status code:
+//
+//   - 422 for invalid input and responses that fail parsing or validation
+//   - 413 for prompts exceeding the token limit
+//   - 429 for provider rate limits
+//   - 504 for timeouts
+//   - 502 for any other failure, which originates with the provider
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, zyn.ErrInvalidPrompt),
+		errors.Is(err, zyn.ErrParseFailed),
+		errors.Is(err, zyn.ErrInvalidResponse):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, zyn.ErrPromptTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, zyn.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, zyn.ErrInsufficientDeadline):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// writeError writes an ErrorResponse with the given status.
+func writeError(w http.ResponseWriter, status int, requestID string, err error) {
+	writeJSON(w, status, ErrorResponse{
+		RequestID: requestID,
+		Error:     err.Error(),
+	})
+}
+
+// writeJSON encodes body as the JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		// The client has gone away; nothing further can be reported
+		return
+	}
+}
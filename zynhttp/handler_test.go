@@ -0,0 +1,285 @@
+package zynhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zoobzio/pipz"
+	"github.com/zoobzio/zyn"
+)
+
+const validResponse = `{"decision": true, "confidence": 0.9, "reasoning": ["looks right"]}`
+
+// newHandler builds a handler over a Binary synapse whose provider responds via callback.
+func newHandler(t *testing.T, callback func(prompt string) (string, error), opts ...zyn.Option) (http.Handler, *zyn.MemorySessionStore) {
+	t.Helper()
+	provider := zyn.NewMockProviderWithCallback(func(prompt string, _ float32) (string, error) {
+		return callback(prompt)
+	})
+	synapse, err := zyn.Binary("the input is valid", provider, opts...)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+	store := zyn.NewMemorySessionStore()
+	return Handler(synapse, store), store
+}
+
+// post sends a JSON body to the handler and returns the recorded response.
+func post(handler http.Handler, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Success(t *testing.T) {
+	handler, store := newHandler(t, func(string) (string, error) { return validResponse, nil })
+
+	rec := post(handler, `{"input": "hello", "context": "greeting"}`, map[string]string{RequestIDHeader: "req-1"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+
+	var resp struct {
+		RequestID string             `json:"request_id"`
+		SessionID string             `json:"session_id"`
+		Result    zyn.BinaryResponse `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response body: %v", err)
+	}
+	if resp.RequestID != "req-1" {
+		t.Errorf("expected request ID to be propagated, got %q", resp.RequestID)
+	}
+	if resp.SessionID == "" || rec.Header().Get(SessionHeader) != resp.SessionID {
+		t.Errorf("expected session ID in body and header, got %q / %q", resp.SessionID, rec.Header().Get(SessionHeader))
+	}
+	if !resp.Result.Decision || resp.Result.Confidence != 0.9 {
+		t.Errorf("unexpected result: %+v", resp.Result)
+	}
+	if store.Len() != 1 {
+		t.Errorf("expected session to be saved, got %d sessions", store.Len())
+	}
+}
+
+func TestHandler_GeneratesRequestID(t *testing.T) {
+	handler, _ := newHandler(t, func(string) (string, error) { return validResponse, nil })
+
+	rec := post(handler, `{"input": "hello"}`, nil)
+	if rec.Header().Get(RequestIDHeader) == "" {
+		t.Error("expected generated request ID header")
+	}
+}
+
+func TestHandler_RequestIDInContext(t *testing.T) {
+	var seen string
+	stage := func(pipeline pipz.Chainable[*zyn.SynapseRequest]) pipz.Chainable[*zyn.SynapseRequest] {
+		record := pipz.Effect(pipz.NewIdentity("test:record", "Records request ID"), func(ctx context.Context, _ *zyn.SynapseRequest) error {
+			seen, _ = zyn.ParentRequestIDFromContext(ctx)
+			return nil
+		})
+		return pipz.NewSequence(pipz.NewIdentity("test:stage", "Test stage"), record, pipeline)
+	}
+	handler, _ := newHandler(t, func(string) (string, error) { return validResponse, nil }, stage)
+
+	post(handler, `{"input": "hello"}`, map[string]string{RequestIDHeader: "req-ctx"})
+	if seen != "req-ctx" {
+		t.Errorf("expected synapse to see header request ID as parent, got %q", seen)
+	}
+}
+
+func TestHandler_SessionReuse(t *testing.T) {
+	var lastPrompt string
+	handler, store := newHandler(t, func(prompt string) (string, error) {
+		lastPrompt = prompt
+		return validResponse, nil
+	})
+	headers := map[string]string{SessionHeader: "conversation-1"}
+
+	if rec := post(handler, `{"input": "first"}`, headers); rec.Code != http.StatusOK {
+		t.Fatalf("first request failed: %d", rec.Code)
+	}
+	if rec := post(handler, `{"input": "second"}`, headers); rec.Code != http.StatusOK {
+		t.Fatalf("second request failed: %d", rec.Code)
+	}
+
+	session, err := store.Load(context.Background(), "conversation-1")
+	if err != nil {
+		t.Fatalf("expected stored session: %v", err)
+	}
+	if session.Len() != 4 {
+		t.Errorf("expected 4 messages across both requests, got %d", session.Len())
+	}
+	if !strings.Contains(lastPrompt, "second") {
+		t.Errorf("expected latest prompt to contain second input, got %q", lastPrompt)
+	}
+}
+
+func TestHandler_StatusMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		callback func(string) (string, error)
+		opts     []zyn.Option
+		expected int
+	}{
+		{
+			name:     "invalid body",
+			body:     `{"input": `,
+			callback: func(string) (string, error) { return validResponse, nil },
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "missing input",
+			body:     `{"input": ""}`,
+			callback: func(string) (string, error) { return validResponse, nil },
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "unparseable response",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return "not json", nil },
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "invalid response",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return `{"decision": true, "confidence": 5, "reasoning": ["x"]}`, nil },
+			expected: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "rate limited",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return "", fmt.Errorf("%w: slow down", zyn.ErrRateLimited) },
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:     "prompt too large",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return validResponse, nil },
+			opts:     []zyn.Option{zyn.WithMaxPromptTokens(1)},
+			expected: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "timeout",
+			body: `{"input": "x"}`,
+			callback: func(string) (string, error) {
+				time.Sleep(200 * time.Millisecond)
+				return validResponse, nil
+			},
+			opts:     []zyn.Option{zyn.WithTimeout(10 * time.Millisecond)},
+			expected: http.StatusGatewayTimeout,
+		},
+		{
+			name:     "insufficient deadline",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return validResponse, nil },
+			opts:     []zyn.Option{zyn.WithMinRemainingDeadline(time.Second), zyn.WithTimeout(10 * time.Millisecond)},
+			expected: http.StatusGatewayTimeout,
+		},
+		{
+			name:     "provider failure",
+			body:     `{"input": "x"}`,
+			callback: func(string) (string, error) { return "", errors.New("connection refused") },
+			expected: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, store := newHandler(t, tt.callback, tt.opts...)
+
+			rec := post(handler, tt.body, map[string]string{RequestIDHeader: "req-err"})
+			if rec.Code != tt.expected {
+				t.Fatalf("expected %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid error body: %v", err)
+			}
+			if resp.Error == "" || resp.RequestID != "req-err" {
+				t.Errorf("unexpected error body: %+v", resp)
+			}
+			if store.Len() != 0 {
+				t.Errorf("expected no session saved on failure, got %d", store.Len())
+			}
+		})
+	}
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	handler, _ := newHandler(t, func(string) (string, error) { return validResponse, nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+	if rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("expected Allow header, got %q", rec.Header().Get("Allow"))
+	}
+}
+
+func TestHandler_Options(t *testing.T) {
+	provider := zyn.NewMockProviderWithResponse(validResponse)
+	synapse, err := zyn.Binary("the input is valid", provider)
+	if err != nil {
+		t.Fatalf("failed to create synapse: %v", err)
+	}
+
+	t.Run("max body bytes", func(t *testing.T) {
+		handler := Handler(synapse, zyn.NewMemorySessionStore(), WithMaxBodyBytes(8))
+		rec := post(handler, `{"input": "this body is too long"}`, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("status mapper", func(t *testing.T) {
+		mapper := func(err error) int {
+			if errors.Is(err, zyn.ErrInvalidPrompt) {
+				return http.StatusBadRequest
+			}
+			return StatusCode(err)
+		}
+		handler := Handler(synapse, zyn.NewMemorySessionStore(), WithStatusMapper(mapper))
+		rec := post(handler, `{"input": ""}`, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestHandler_ContextPropagation(t *testing.T) {
+	handler, _ := newHandler(t, func(string) (string, error) {
+		time.Sleep(200 * time.Millisecond)
+		return validResponse, nil
+	}, zyn.WithTimeout(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"input": "x"}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("expected request deadline to reach the pipeline, got %d", rec.Code)
+	}
+}
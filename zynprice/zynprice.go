@@ -0,0 +1,111 @@
+// Package zynprice provides token prices for common OpenAI, Anthropic, and
+// Gemini models, for use with zyn's cost features.
+//
+// The prices are embedded from prices.json, in US dollars per million tokens,
+// and reflect published list prices on the date reported by AsOf. Prices
+// change; use Merge to override or extend them without waiting for a release.
+//
+// Example:
+//
+//	prices := zynprice.Merge(zyn.PriceTable{
+//	    "my-fine-tune": {Prompt: 3.00, Completion: 12.00},
+//	})
+//	estimate, _ := zyn.EstimateCost(synapse, samples, prices, zyn.CostAssumptions{})
+//
+//	cost, ok := zynprice.Cost("gpt-4o-2024-08-06", session.TotalUsage())
+package zynprice
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zoobzio/zyn"
+)
+
+//go:embed prices.json
+var pricesJSON []byte
+
+// priceData is the format of prices.json.
+type priceData struct {
+	AsOf   string         `json:"as_of"` // Date the prices were checked, as YYYY-MM-DD
+	Models zyn.PriceTable `json:"models"`
+}
+
+// prices parses the embedded price data once.
+var prices = sync.OnceValue(func() priceData {
+	var data priceData
+	if err := json.Unmarshal(pricesJSON, &data); err != nil {
+		panic(fmt.Sprintf("zynprice: invalid embedded prices: %v", err))
+	}
+	return data
+})
+
+// Default returns the embedded prices. The table is a copy the caller may
+// modify.
+func Default() zyn.PriceTable {
+	return maps.Clone(prices().Models)
+}
+
+// AsOf returns the date the embedded prices were last checked.
+func AsOf() time.Time {
+	date, err := time.Parse(time.DateOnly, prices().AsOf)
+	if err != nil {
+		panic(fmt.Sprintf("zynprice: invalid embedded date: %v", err))
+	}
+	return date
+}
+
+// Merge returns the embedded prices with overrides applied. Overrides replace
+// the entry of the same model and add models the defaults do not cover; their
+// names are cleaned the same way as model names passed to Cost.
+func Merge(overrides zyn.PriceTable) zyn.PriceTable {
+	table := Default()
+	for model, price := range overrides {
+		table[clean(model)] = price
+	}
+	return table
+}
+
+// Cost returns the cost in US dollars of usage for model at the embedded
+// prices, and false when model is not covered. Cached prompt tokens reported
+// in usage are billed at the model's cached rate.
+func Cost(model string, usage zyn.TokenUsage) (float64, bool) {
+	return prices().Models.Cost(clean(model), usage)
+}
+
+// Normalize returns the name of the embedded entry that prices model, such as
+// "gpt-4o" for "gpt-4o-2024-08-06" or "claude-3-5-sonnet" for
+// "anthropic/claude-3.5-sonnet-latest". Models without an entry are returned
+// cleaned but otherwise unchanged.
+func Normalize(model string) string {
+	model = clean(model)
+	family := ""
+	for name := range prices().Models {
+		if (model == name || strings.HasPrefix(model, name+"-")) && len(name) > len(family) {
+			family = name
+		}
+	}
+	if family == "" {
+		return model
+	}
+	return family
+}
+
+// clean lowercases model and removes the routing prefixes and punctuation
+// variants that gateways and SDKs add, such as "models/" or "openai/", and
+// "claude-3.5" for "claude-3-5".
+func clean(model string) string {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+	if strings.HasPrefix(model, "claude-") {
+		model = strings.ReplaceAll(model, ".", "-")
+	}
+	return model
+}
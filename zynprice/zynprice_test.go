@@ -0,0 +1,109 @@
+package zynprice
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zoobzio/zyn"
+)
+
+func TestDefault(t *testing.T) {
+	table := Default()
+	for _, model := range []string{"gpt-4o", "gpt-4o-mini", "claude-sonnet-4", "claude-3-5-haiku", "gemini-1.5-flash", "gemini-2.5-pro"} {
+		price, ok := table[model]
+		if !ok {
+			t.Errorf("expected a price for %q", model)
+			continue
+		}
+		if price.Prompt <= 0 || price.Completion <= 0 || price.CachedPrompt > price.Prompt {
+			t.Errorf("implausible price for %q: %+v", model, price)
+		}
+	}
+
+	table["gpt-4o"] = zyn.ModelPrice{}
+	if Default()["gpt-4o"].Prompt == 0 {
+		t.Error("expected Default to return a copy")
+	}
+
+	if AsOf().IsZero() || AsOf().Year() < 2025 {
+		t.Errorf("unexpected price date %v", AsOf())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o":                             "gpt-4o",
+		"gpt-4o-2024-08-06":                  "gpt-4o",
+		"gpt-4o-mini-2024-07-18":             "gpt-4o-mini",
+		"GPT-4o":                             "gpt-4o",
+		"openai/gpt-4.1-mini":                "gpt-4.1-mini",
+		"gpt-4-turbo-2024-04-09":             "gpt-4-turbo",
+		"gpt-4-0613":                         "gpt-4",
+		"o3-mini-2025-01-31":                 "o3-mini",
+		"claude-sonnet-4-20250514":           "claude-sonnet-4",
+		"claude-3-5-sonnet-latest":           "claude-3-5-sonnet",
+		"anthropic/claude-3.5-sonnet-latest": "claude-3-5-sonnet",
+		"models/gemini-1.5-pro":              "gemini-1.5-pro",
+		"gemini-2.0-flash-lite-001":          "gemini-2.0-flash-lite",
+		"gemini-2.0-flash-001":               "gemini-2.0-flash",
+		"my-fine-tune":                       "my-fine-tune",
+	}
+	for model, want := range tests {
+		if got := Normalize(model); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", model, got, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	table := Merge(zyn.PriceTable{
+		"gpt-4o":       {Prompt: 1, Completion: 2},
+		"My-Fine-Tune": {Prompt: 3, Completion: 12},
+	})
+	if table["gpt-4o"].Prompt != 1 {
+		t.Errorf("expected override to replace default, got %+v", table["gpt-4o"])
+	}
+	if table["my-fine-tune"].Completion != 12 {
+		t.Errorf("expected override to add model, got %+v", table["my-fine-tune"])
+	}
+	if table["gpt-4o-mini"] != Default()["gpt-4o-mini"] {
+		t.Error("expected other defaults kept")
+	}
+	if Default()["gpt-4o"].Prompt == 1 {
+		t.Error("expected defaults unchanged")
+	}
+}
+
+func TestCost(t *testing.T) {
+	price := Default()["gpt-4o"]
+	usage := zyn.TokenUsage{Prompt: 10_000, Completion: 2_000, Total: 12_000}
+
+	cost, ok := Cost("gpt-4o-2024-08-06", usage)
+	want := (10_000*price.Prompt + 2_000*price.Completion) / 1e6
+	if !ok || math.Abs(cost-want) > 1e-12 {
+		t.Errorf("expected $%f, got $%f (%v)", want, cost, ok)
+	}
+
+	// 6,000 of the prompt tokens were read from cache
+	usage.CachedPrompt = 6_000
+	cached, _ := Cost("gpt-4o", usage)
+	want = (4_000*price.Prompt + 6_000*price.CachedPrompt + 2_000*price.Completion) / 1e6
+	if math.Abs(cached-want) > 1e-12 {
+		t.Errorf("expected cached discount to $%f, got $%f", want, cached)
+	}
+	if cached >= cost {
+		t.Errorf("expected cached usage to cost less: $%f >= $%f", cached, cost)
+	}
+
+	// Models without a cached rate bill cached tokens as prompt tokens
+	gpt4, _ := Cost("gpt-4", usage)
+	usage.CachedPrompt = 0
+	uncached, _ := Cost("gpt-4", usage)
+	if math.Abs(gpt4-uncached) > 1e-12 {
+		t.Errorf("expected no discount without a cached rate: $%f != $%f", gpt4, uncached)
+	}
+
+	if _, ok := Cost("unknown-model", usage); ok {
+		t.Error("expected unknown model to be unpriced")
+	}
+}